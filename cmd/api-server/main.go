@@ -3,6 +3,8 @@ package main
 import (
 	"context"
 	"os"
+	"strconv"
+	"time"
 
 	"cloud.google.com/go/firestore"
 	"github.com/golang-jwt/jwt/v5"
@@ -14,6 +16,7 @@ import (
 	"github.com/tsongpon/athena/internal/repository"
 	"github.com/tsongpon/athena/internal/service"
 	"go.uber.org/zap"
+	"google.golang.org/api/option"
 )
 
 func main() {
@@ -33,6 +36,17 @@ func main() {
 
 	var bookmarkRepo service.BookmarkRepository
 	var userRepo service.UserRepository
+	var usageRepo service.UsageRepository
+	var billingEventRepo service.BillingEventRepository
+	var collectionRepo service.CollectionRepository
+	var invitationRepo service.InvitationRepository
+	var activityRepo service.ActivityRepository
+	var shareLinkRepo service.ShareLinkRepository
+	var feedTokenRepo service.FeedTokenRepository
+	var highlightRepo service.HighlightRepository
+	var webhookRepo service.WebhookRepository
+	var bookmarkChangeRepo service.BookmarkChangeRepository
+	var eventBroker service.EventBroker // nil delivers events within this instance only
 
 	switch storageType {
 	case "firestore":
@@ -50,29 +64,179 @@ func main() {
 		}
 		defer client.Close()
 
-		bookmarkRepo = repository.NewBookmarkFirestoreRepository(ctx, client)
+		bookmarks := repository.NewBookmarkFirestoreRepository(ctx, client)
+		bookmarkRepo, bookmarkChangeRepo = bookmarks, bookmarks
 		userRepo = repository.NewUserFirestoreRepository(ctx, client)
+		usageRepo = repository.NewUsageFirestoreRepository(ctx, client)
+		billingEventRepo = repository.NewBillingEventFirestoreRepository(ctx, client)
+		collectionRepo = repository.NewCollectionFirestoreRepository(ctx, client)
+		invitationRepo = repository.NewInvitationFirestoreRepository(ctx, client)
+		activityRepo = repository.NewActivityFirestoreRepository(ctx, client)
+		shareLinkRepo = repository.NewShareLinkFirestoreRepository(ctx, client)
+		feedTokenRepo = repository.NewFeedTokenFirestoreRepository(ctx, client)
+		highlightRepo = repository.NewHighlightFirestoreRepository(ctx, client)
+		webhookRepo = repository.NewWebhookFirestoreRepository(ctx, client)
+		// Instances behind a load balancer share bookmark change events through Firestore
+		if os.Getenv("EVENT_BROKER") == "firestore" {
+			eventBroker = repository.NewEventFirestoreBroker(ctx, client)
+		}
 		logger.Info("Using Firestore storage for bookmarks and users", zap.String("project_id", projectID))
 
 	default:
-		bookmarkRepo = repository.NewBookmarkInMemRepository()
+		bookmarks := repository.NewBookmarkInMemRepository()
+		bookmarkRepo, bookmarkChangeRepo = bookmarks, bookmarks
 		userRepo = repository.NewUserInMemRepository()
+		usageRepo = repository.NewUsageInMemRepository()
+		billingEventRepo = repository.NewBillingEventInMemRepository()
+		collectionRepo = repository.NewCollectionInMemRepository()
+		invitationRepo = repository.NewInvitationInMemRepository()
+		activityRepo = repository.NewActivityInMemRepository()
+		shareLinkRepo = repository.NewShareLinkInMemRepository()
+		feedTokenRepo = repository.NewFeedTokenInMemRepository()
+		highlightRepo = repository.NewHighlightInMemRepository()
+		webhookRepo = repository.NewWebhookInMemRepository()
 		logger.Info("Using in-memory storage for bookmarks and users")
 	}
 
-	webRepo := repository.NewWebRepository()
-	bookmarkService := service.NewBookmarkService(bookmarkRepo, userRepo, webRepo)
+	// All outgoing page fetches share one fetcher so per-host limits hold across features
+	hostRate, err := strconv.ParseFloat(getEnv("FETCH_HOST_RATE", "1"), 64)
+	if err != nil {
+		logger.Fatal("Invalid FETCH_HOST_RATE", zap.Error(err))
+	}
+	hostBurst, err := strconv.Atoi(getEnv("FETCH_HOST_BURST", "3"))
+	if err != nil {
+		logger.Fatal("Invalid FETCH_HOST_BURST", zap.Error(err))
+	}
+	hostConcurrency, err := strconv.Atoi(getEnv("FETCH_HOST_CONCURRENCY", "2"))
+	if err != nil {
+		logger.Fatal("Invalid FETCH_HOST_CONCURRENCY", zap.Error(err))
+	}
+	fetcher := repository.NewFetcher(repository.FetcherConfig{
+		UserAgent:            os.Getenv("FETCH_USER_AGENT"),
+		RequestsPerSecond:    hostRate,
+		Burst:                hostBurst,
+		MaxConcurrentPerHost: hostConcurrency,
+		RespectRobots:        os.Getenv("FETCH_RESPECT_ROBOTS") == "true",
+		// Fetched URLs come from users, so internal services are off limits unless opted in
+		AllowPrivateAddresses: os.Getenv("FETCH_ALLOW_PRIVATE") == "true",
+	})
+
+	webRepo := repository.NewWebRepository(fetcher)
+	policy := service.NewPolicy(collectionRepo)
+	eventHub := service.NewEventHub(eventBroker)
+	go eventHub.Run(context.Background())
+
+	// Offline snapshots of bookmarked pages and thumbnails of their images are kept in a blob
+	// store and disabled without one
+	var blobStore service.BlobStore
+	switch os.Getenv("BLOB_STORE") {
+	case "local":
+		store, err := repository.NewLocalBlobStore(getEnv("BLOB_DIR", "data/blobs"))
+		if err != nil {
+			logger.Fatal("Failed to create local blob store", zap.Error(err))
+		}
+		blobStore = store
+	case "gcs":
+		bucket := os.Getenv("BLOB_BUCKET")
+		if bucket == "" {
+			logger.Fatal("BLOB_BUCKET environment variable is required for the GCS blob store")
+		}
+		var opts []option.ClientOption
+		if endpoint := os.Getenv("BLOB_ENDPOINT"); endpoint != "" {
+			// GCS compatible servers and emulators such as fake-gcs-server
+			opts = append(opts, option.WithEndpoint(endpoint), option.WithoutAuthentication())
+		}
+		store, err := repository.NewGCSBlobStore(context.Background(), bucket, opts...)
+		if err != nil {
+			logger.Fatal("Failed to create GCS blob store", zap.Error(err))
+		}
+		blobStore = store
+	}
+	var snapshotService *service.SnapshotService
+	var thumbnailService *service.ThumbnailService
+	if blobStore != nil {
+		snapshotService = service.NewSnapshotService(bookmarkRepo, repository.NewWebArchiver(fetcher), blobStore, policy, eventHub)
+		thumbnailService = service.NewThumbnailService(bookmarkRepo, repository.NewWebThumbnailer(fetcher), blobStore, policy, eventHub)
+		logger.Info("Offline page snapshots and image thumbnails enabled", zap.String("blob_store", os.Getenv("BLOB_STORE")))
+	}
+
+	entitlementService := service.NewEntitlementService(userRepo, bookmarkRepo, usageRepo)
+	// List cursors get a key of their own, derived from JWT_SECRET unless CURSOR_SECRET is set
+	cursorKey := []byte(os.Getenv("CURSOR_SECRET"))
+	if jwtSecret := os.Getenv("JWT_SECRET"); len(cursorKey) == 0 && jwtSecret != "" {
+		cursorKey = service.DeriveCursorKey(jwtSecret)
+	}
+	bookmarkService := service.NewBookmarkService(bookmarkRepo, userRepo, webRepo, entitlementService, policy, snapshotService, thumbnailService, eventHub, cursorKey)
 	userService := service.NewUserService(userRepo)
+	adminService := service.NewAdminService(userRepo, bookmarkRepo)
+	collectionService := service.NewCollectionService(collectionRepo, bookmarkRepo, activityRepo, policy, eventHub)
+	batchService := service.NewBatchService(bookmarkRepo, collectionRepo, activityRepo, policy, eventHub)
+	sharingService := service.NewSharingService(collectionRepo, invitationRepo, activityRepo, userRepo, policy)
+	shareService := service.NewShareService(shareLinkRepo, bookmarkRepo, collectionRepo, policy)
+	feedService := service.NewFeedService(feedTokenRepo, bookmarkRepo, collectionRepo, policy)
+	highlightService := service.NewHighlightService(highlightRepo, bookmarkRepo, policy)
+
+	// Webhooks: events are queued when published and sent by a background worker with retries
+	webhookService := service.NewWebhookService(webhookRepo, repository.NewHTTPWebhookSender(os.Getenv("WEBHOOK_ALLOW_PRIVATE") == "true"), policy)
+	eventHub.Listen(webhookService.HandleEvent)
+	go webhookService.Run(context.Background(), 5*time.Second)
+
+	// Sync: clients catch up from the per-user change log the bookmark repository writes
+	syncService := service.NewSyncService(bookmarkRepo, bookmarkChangeRepo, policy, eventHub)
+
+	// Dead-link checker: every bookmark is revalidated once per LINK_CHECK_INTERVAL ("0" disables)
+	linkCheckInterval, err := time.ParseDuration(getEnv("LINK_CHECK_INTERVAL", "24h"))
+	if err != nil {
+		logger.Fatal("Invalid LINK_CHECK_INTERVAL", zap.Error(err))
+	}
+	if linkCheckInterval > 0 {
+		linkHealthService := service.NewLinkHealthService(bookmarkRepo, repository.NewHTTPLinkChecker(fetcher), linkCheckInterval)
+		// Due bookmarks are checked in batches, at least hourly
+		go linkHealthService.Run(context.Background(), min(linkCheckInterval, time.Hour))
+		logger.Info("Link health checks enabled", zap.Duration("interval", linkCheckInterval))
+	}
+
+	// Trash purger: deleted bookmarks are permanently removed after TRASH_RETENTION ("0" keeps them)
+	trashRetention, err := time.ParseDuration(getEnv("TRASH_RETENTION", "720h"))
+	if err != nil {
+		logger.Fatal("Invalid TRASH_RETENTION", zap.Error(err))
+	}
+	trashService := service.NewTrashService(bookmarkRepo, highlightRepo, userRepo, entitlementService, policy, snapshotService, thumbnailService, trashRetention, eventHub)
+	if trashRetention > 0 {
+		// Expired bookmarks are purged in batches, at least hourly
+		go trashService.Run(context.Background(), min(trashRetention, time.Hour))
+		logger.Info("Trash purging enabled", zap.Duration("retention", trashRetention))
+	}
+
+	// Promote registered users listed in ADMIN_EMAILS that have not been promoted before
+	if err := userService.BootstrapAdmins(); err != nil {
+		logger.Fatal("Failed to bootstrap admin users", zap.Error(err))
+	}
 
 	bookmarkHandler := handler.NewBookmarkHandler(bookmarkService)
 	authHandler := handler.NewAuthHandler(userService)
+	adminHandler := handler.NewAdminHandler(adminService)
+	usageHandler := handler.NewUsageHandler(entitlementService)
+	collectionHandler := handler.NewCollectionHandler(collectionService)
+	batchHandler := handler.NewBatchHandler(batchService)
+	trashHandler := handler.NewTrashHandler(trashService)
+	highlightHandler := handler.NewHighlightHandler(highlightService)
+	eventHandler := handler.NewEventHandler(eventHub)
+	webhookHandler := handler.NewWebhookHandler(webhookService)
+	syncHandler := handler.NewSyncHandler(syncService)
+	sharingHandler := handler.NewSharingHandler(sharingService)
+	publicBaseURL := os.Getenv("PUBLIC_BASE_URL")
+	shareHandler := handler.NewShareHandler(shareService, publicBaseURL)
+	feedHandler := handler.NewFeedHandler(feedService, publicBaseURL)
+	authMiddleware := handler.NewAuthMiddleware(userService)
 
 	e := echo.New()
 
 	// Middleware
 	// e.Use(middleware.Logger())
 	e.Use(middleware.Recover())
-	e.Use(middleware.CORS())
+	// Browser clients need to read the ETag of a bookmark to send it back in If-Match
+	e.Use(middleware.CORSWithConfig(middleware.CORSConfig{ExposeHeaders: []string{"ETag"}}))
 
 	// JWT secret
 	jwtSecret := os.Getenv("JWT_SECRET")
@@ -98,12 +262,122 @@ func main() {
 	e.POST("/users", authHandler.CreateUser)
 	e.POST("/login", authHandler.Login)
 
+	// Public share links (no authentication; POST submits the password form of protected links)
+	e.GET("/s/:slug", shareHandler.ViewShare)
+	e.POST("/s/:slug", shareHandler.ViewShare)
+
+	// Bookmark feeds (authenticated by the feed token in the URL: /feeds/:token.atom or /feeds/:token.rss)
+	e.GET("/feeds/:token", feedHandler.GetFeed)
+
+	// Protected routes validate the JWT and then reject disabled or logged-out accounts
+	protected := []echo.MiddlewareFunc{echojwt.WithConfig(jwtConfig), authMiddleware.RequireActiveUser}
+
 	// Bookmark routes (all protected with JWT)
-	e.POST("/bookmarks", bookmarkHandler.CreateBookmark, echojwt.WithConfig(jwtConfig))
-	e.GET("/bookmarks/:id", bookmarkHandler.GetBookmark, echojwt.WithConfig(jwtConfig))
-	e.GET("/bookmarks", bookmarkHandler.GetBookmarks, echojwt.WithConfig(jwtConfig))
-	e.POST("/bookmarks/:id/archive", bookmarkHandler.ArchiveBookmark, echojwt.WithConfig(jwtConfig))
-	e.DELETE("/bookmarks/:id", bookmarkHandler.DeleteBookmark, echojwt.WithConfig(jwtConfig))
+	e.POST("/bookmarks", bookmarkHandler.CreateBookmark, protected...)
+	e.GET("/bookmarks/:id", bookmarkHandler.GetBookmark, protected...)
+	e.GET("/bookmarks", bookmarkHandler.GetBookmarks, protected...)
+	e.POST("/bookmarks/:id/archive", bookmarkHandler.ArchiveBookmark, protected...)
+	e.POST("/bookmarks/:id/open", bookmarkHandler.OpenBookmark, protected...)
+	e.PATCH("/bookmarks/:id/state", bookmarkHandler.UpdateReadingState, protected...)
+	e.PUT("/bookmarks/:id/notes", bookmarkHandler.UpdateNotes, protected...)
+	e.GET("/bookmarks/:id/highlights", highlightHandler.ListHighlights, protected...)
+	e.POST("/bookmarks/:id/highlights", highlightHandler.CreateHighlight, protected...)
+	e.PATCH("/bookmarks/:id/highlights/:highlight_id", highlightHandler.UpdateHighlight, protected...)
+	e.DELETE("/bookmarks/:id/highlights/:highlight_id", highlightHandler.DeleteHighlight, protected...)
+	e.POST("/bookmarks/:id/refresh", bookmarkHandler.RefreshBookmark, protected...)
+	e.POST("/bookmarks/refresh", bookmarkHandler.RefreshBookmarks, protected...)
+	e.POST("/bookmarks/batch", batchHandler.ApplyBatch, protected...)
+	e.GET("/bookmarks/:id/enrichments", bookmarkHandler.ListEnrichments, protected...)
+	e.DELETE("/bookmarks/:id", bookmarkHandler.DeleteBookmark, protected...)
+	if snapshotService != nil {
+		e.GET("/bookmarks/:id/archive.html", handler.NewSnapshotHandler(snapshotService).GetSnapshot, protected...)
+	}
+	if thumbnailService != nil {
+		e.GET("/images/:id/:size", handler.NewImageHandler(thumbnailService).GetImage, protected...)
+	}
+
+	// Trash routes (deleted bookmarks until they are purged)
+	e.GET("/trash", trashHandler.ListTrash, protected...)
+	e.DELETE("/trash", trashHandler.EmptyTrash, protected...)
+	e.POST("/trash/:id/restore", trashHandler.RestoreBookmark, protected...)
+
+	// Collection routes
+	e.POST("/collections", collectionHandler.CreateCollection, protected...)
+	e.GET("/collections", collectionHandler.ListCollections, protected...)
+	e.GET("/collections/shared", collectionHandler.ListSharedCollections, protected...)
+	e.GET("/collections/:id", collectionHandler.GetCollection, protected...)
+	e.PUT("/collections/:id", collectionHandler.UpdateCollection, protected...)
+	e.DELETE("/collections/:id", collectionHandler.DeleteCollection, protected...)
+	e.GET("/collections/:id/bookmarks", collectionHandler.ListCollectionBookmarks, protected...)
+	e.POST("/collections/:id/bookmarks", collectionHandler.AddBookmark, protected...)
+	e.PUT("/collections/:id/bookmarks/order", collectionHandler.ReorderBookmarks, protected...)
+	e.DELETE("/collections/:id/bookmarks/:bookmark_id", collectionHandler.RemoveBookmark, protected...)
+	e.GET("/collections/:id/activity", collectionHandler.ListActivity, protected...)
+
+	// Sharing routes
+	e.POST("/collections/:id/invitations", sharingHandler.InviteCollaborator, protected...)
+	e.GET("/collections/:id/invitations", sharingHandler.ListCollectionInvitations, protected...)
+	e.DELETE("/collections/:id/invitations/:invitation_id", sharingHandler.RevokeInvitation, protected...)
+	e.PUT("/collections/:id/collaborators/:user_id", sharingHandler.UpdateCollaborator, protected...)
+	e.DELETE("/collections/:id/collaborators/:user_id", sharingHandler.RemoveCollaborator, protected...)
+	e.GET("/invitations", sharingHandler.ListPendingInvitations, protected...)
+	e.POST("/invitations/:id/accept", sharingHandler.AcceptInvitation, protected...)
+	e.POST("/invitations/:id/decline", sharingHandler.DeclineInvitation, protected...)
+
+	// Share link routes
+	e.POST("/bookmarks/:id/share", shareHandler.ShareBookmark, protected...)
+	e.POST("/collections/:id/share", shareHandler.ShareCollection, protected...)
+	e.GET("/shares", shareHandler.ListShareLinks, protected...)
+	e.DELETE("/shares/:slug", shareHandler.RevokeShareLink, protected...)
+
+	// Feed token routes
+	e.POST("/feeds", feedHandler.CreateFeedToken, protected...)
+	e.GET("/feeds", feedHandler.ListFeedTokens, protected...)
+	e.DELETE("/feeds/:token", feedHandler.RevokeFeedToken, protected...)
+
+	// Bookmark change events (Server-Sent Events)
+	e.GET("/events", eventHandler.Stream, protected...)
+
+	// Sync routes (delta sync for offline-first clients)
+	e.GET("/sync", syncHandler.GetChanges, protected...)
+	e.POST("/sync", syncHandler.PushChanges, protected...)
+
+	// Webhook routes
+	e.POST("/webhooks", webhookHandler.CreateWebhook, protected...)
+	e.GET("/webhooks", webhookHandler.ListWebhooks, protected...)
+	e.PATCH("/webhooks/:id", webhookHandler.UpdateWebhook, protected...)
+	e.DELETE("/webhooks/:id", webhookHandler.DeleteWebhook, protected...)
+	e.GET("/webhooks/:id/deliveries", webhookHandler.ListDeliveries, protected...)
+
+	// Account routes
+	e.GET("/me/usage", usageHandler.GetUsage, protected...)
+
+	// Billing webhook (public, authenticated by the provider's signature)
+	var billingProvider service.BillingProvider
+	switch os.Getenv("BILLING_PROVIDER") {
+	case "stripe":
+		billingProvider = repository.NewStripeBillingProvider(os.Getenv("BILLING_WEBHOOK_SECRET"))
+	case "fake":
+		billingProvider = repository.NewFakeBillingProvider(os.Getenv("BILLING_WEBHOOK_SECRET"))
+	}
+	if billingProvider != nil {
+		if os.Getenv("BILLING_WEBHOOK_SECRET") == "" {
+			logger.Fatal("BILLING_WEBHOOK_SECRET environment variable is required for billing webhooks")
+		}
+		billingHandler := handler.NewBillingHandler(service.NewBillingService(billingProvider, userRepo, billingEventRepo))
+		e.POST("/billing/webhook", billingHandler.Webhook)
+		logger.Info("Billing webhook enabled", zap.String("provider", billingProvider.Name()))
+	}
+
+	// Admin routes (JWT + admin role)
+	admin := e.Group("/admin", append(protected, authMiddleware.RequireAdmin)...)
+	admin.GET("/users", adminHandler.ListUsers)
+	admin.GET("/users/:id", adminHandler.GetUser)
+	admin.PUT("/users/:id/tier", adminHandler.UpdateTier)
+	admin.PUT("/users/:id/role", adminHandler.UpdateRole)
+	admin.POST("/users/:id/disable", adminHandler.DisableUser)
+	admin.POST("/users/:id/enable", adminHandler.EnableUser)
+	admin.POST("/users/:id/logout", adminHandler.ForceLogout)
 
 	// Start server
 	port := os.Getenv("PORT")
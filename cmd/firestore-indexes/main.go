@@ -0,0 +1,26 @@
+// Command firestore-indexes writes firestore.indexes.json, the composite indexes the
+// Firestore repositories need, for deploying with `firebase deploy --only firestore:indexes`
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/tsongpon/athena/internal/repository"
+)
+
+func main() {
+	output := flag.String("o", "firestore.indexes.json", "file to write the index definitions to")
+	flag.Parse()
+
+	data, err := repository.FirestoreIndexesJSON()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "failed to build index definitions:", err)
+		os.Exit(1)
+	}
+	if err := os.WriteFile(*output, data, 0o644); err != nil {
+		fmt.Fprintln(os.Stderr, "failed to write index definitions:", err)
+		os.Exit(1)
+	}
+}
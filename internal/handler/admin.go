@@ -0,0 +1,165 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/tsongpon/athena/internal/logger"
+	"github.com/tsongpon/athena/internal/model"
+	"github.com/tsongpon/athena/internal/transport"
+	"go.uber.org/zap"
+)
+
+type AdminHandler struct {
+	adminService AdminService
+}
+
+func NewAdminHandler(adminService AdminService) *AdminHandler {
+	return &AdminHandler{
+		adminService: adminService,
+	}
+}
+
+// ListUsers lists users, optionally filtered by an email prefix passed as ?q=
+func (h *AdminHandler) ListUsers(c echo.Context) error {
+	page, err := strconv.Atoi(c.QueryParam("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	pageSize, err := strconv.Atoi(c.QueryParam("page_size"))
+	if err != nil || pageSize < 1 {
+		pageSize = 20
+	}
+
+	response, err := h.adminService.ListUsers(c.QueryParam("q"), page, pageSize)
+	if err != nil {
+		logger.Error("Failed to list users", zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	users := make([]transport.AdminUserResponse, len(response.Users))
+	for i, u := range response.Users {
+		users[i] = toAdminUserResponse(u)
+	}
+
+	return c.JSON(http.StatusOK, transport.AdminUserListResponse{
+		Users:      users,
+		TotalCount: response.TotalCount,
+		Page:       response.Page,
+		PageSize:   response.PageSize,
+		TotalPages: response.TotalPages,
+	})
+}
+
+// GetUser returns a user together with their bookmark counts
+func (h *AdminHandler) GetUser(c echo.Context) error {
+	stats, err := h.adminService.GetUserStats(c.Param("id"))
+	if err != nil {
+		return adminError(err)
+	}
+
+	return c.JSON(http.StatusOK, transport.AdminUserStatsResponse{
+		AdminUserResponse: toAdminUserResponse(stats.User),
+		BookmarkCount:     stats.BookmarkCount,
+		ArchivedCount:     stats.ArchivedCount,
+	})
+}
+
+// UpdateTier changes a user's tier
+func (h *AdminHandler) UpdateTier(c echo.Context) error {
+	req := &transport.UpdateTierRequest{}
+	if err := c.Bind(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	user, err := h.adminService.SetTier(c.Param("id"), req.Tier)
+	if err != nil {
+		return adminError(err)
+	}
+	return c.JSON(http.StatusOK, toAdminUserResponse(user))
+}
+
+// UpdateRole changes a user's role
+func (h *AdminHandler) UpdateRole(c echo.Context) error {
+	req := &transport.UpdateRoleRequest{}
+	if err := c.Bind(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	admin, err := getAuthenticatedUser(c)
+	if err != nil {
+		return err
+	}
+
+	user, err := h.adminService.SetRole(admin.UserID, c.Param("id"), req.Role)
+	if err != nil {
+		return adminError(err)
+	}
+	return c.JSON(http.StatusOK, toAdminUserResponse(user))
+}
+
+// DisableUser disables a user account and revokes its tokens
+func (h *AdminHandler) DisableUser(c echo.Context) error {
+	return h.setDisabled(c, true)
+}
+
+// EnableUser re-enables a disabled user account
+func (h *AdminHandler) EnableUser(c echo.Context) error {
+	return h.setDisabled(c, false)
+}
+
+func (h *AdminHandler) setDisabled(c echo.Context, disabled bool) error {
+	admin, err := getAuthenticatedUser(c)
+	if err != nil {
+		return err
+	}
+
+	user, err := h.adminService.SetDisabled(admin.UserID, c.Param("id"), disabled)
+	if err != nil {
+		return adminError(err)
+	}
+	return c.JSON(http.StatusOK, toAdminUserResponse(user))
+}
+
+// ForceLogout revokes every token issued to a user
+func (h *AdminHandler) ForceLogout(c echo.Context) error {
+	if _, err := h.adminService.ForceLogout(c.Param("id")); err != nil {
+		return adminError(err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// adminError maps service errors to HTTP errors
+func adminError(err error) error {
+	switch {
+	case errors.Is(err, model.ErrInvalidInput):
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	case errors.Is(err, model.ErrForbidden):
+		return echo.NewHTTPError(http.StatusForbidden, err.Error())
+	case errors.Is(err, model.ErrNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, "User not found")
+	default:
+		logger.Error("Admin operation failed", zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+}
+
+func toAdminUserResponse(user model.User) transport.AdminUserResponse {
+	resp := transport.AdminUserResponse{
+		ID:        user.ID,
+		Name:      user.Name,
+		Email:     user.Email,
+		Tier:      user.Tier,
+		Role:      user.Role,
+		Disabled:  user.Disabled,
+		CreatedAt: user.CreatedAt,
+		UpdatedAt: user.UpdatedAt,
+	}
+	if !user.TokensRevokedAt.IsZero() {
+		revokedAt := user.TokensRevokedAt
+		resp.TokensRevokedAt = &revokedAt
+	}
+	return resp
+}
@@ -0,0 +1,191 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/tsongpon/athena/internal/model"
+	"github.com/tsongpon/athena/internal/transport"
+)
+
+// MockAdminService is a mock implementation of AdminService
+type MockAdminService struct {
+	mock.Mock
+}
+
+func (m *MockAdminService) ListUsers(search string, page, pageSize int) (model.UserListResponse, error) {
+	args := m.Called(search, page, pageSize)
+	return args.Get(0).(model.UserListResponse), args.Error(1)
+}
+
+func (m *MockAdminService) GetUserStats(id string) (model.UserStats, error) {
+	args := m.Called(id)
+	return args.Get(0).(model.UserStats), args.Error(1)
+}
+
+func (m *MockAdminService) SetTier(id, tier string) (model.User, error) {
+	args := m.Called(id, tier)
+	return args.Get(0).(model.User), args.Error(1)
+}
+
+func (m *MockAdminService) SetRole(actorID, id, role string) (model.User, error) {
+	args := m.Called(actorID, id, role)
+	return args.Get(0).(model.User), args.Error(1)
+}
+
+func (m *MockAdminService) SetDisabled(actorID, id string, disabled bool) (model.User, error) {
+	args := m.Called(actorID, id, disabled)
+	return args.Get(0).(model.User), args.Error(1)
+}
+
+func (m *MockAdminService) ForceLogout(id string) (model.User, error) {
+	args := m.Called(id)
+	return args.Get(0).(model.User), args.Error(1)
+}
+
+func TestAdminHandler_ListUsers(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/admin/users?q=al&page=2&page_size=10", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	mockService := new(MockAdminService)
+	handler := NewAdminHandler(mockService)
+
+	mockService.On("ListUsers", "al", 2, 10).Return(model.UserListResponse{
+		Users:      []model.User{{ID: "user-1", Email: "alice@example.com", Tier: model.TierFree}},
+		TotalCount: 11,
+		Page:       2,
+		PageSize:   10,
+		TotalPages: 2,
+	}, nil)
+
+	err := handler.ListUsers(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var response transport.AdminUserListResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Len(t, response.Users, 1)
+	assert.Equal(t, "alice@example.com", response.Users[0].Email)
+	assert.Equal(t, 11, response.TotalCount)
+	mockService.AssertExpectations(t)
+}
+
+func TestAdminHandler_GetUser(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/admin/users/user-1", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("user-1")
+
+	mockService := new(MockAdminService)
+	handler := NewAdminHandler(mockService)
+
+	mockService.On("GetUserStats", "user-1").Return(model.UserStats{
+		User:          model.User{ID: "user-1", Email: "alice@example.com"},
+		BookmarkCount: 7,
+		ArchivedCount: 2,
+	}, nil)
+
+	err := handler.GetUser(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var response transport.AdminUserStatsResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, "user-1", response.ID)
+	assert.Equal(t, 7, response.BookmarkCount)
+	assert.Equal(t, 2, response.ArchivedCount)
+}
+
+func TestAdminHandler_GetUser_NotFound(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/admin/users/missing", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("missing")
+
+	mockService := new(MockAdminService)
+	handler := NewAdminHandler(mockService)
+
+	mockService.On("GetUserStats", "missing").Return(model.UserStats{}, fmt.Errorf("lookup: %w", model.ErrNotFound))
+
+	err := handler.GetUser(c)
+
+	httpErr, ok := err.(*echo.HTTPError)
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusNotFound, httpErr.Code)
+}
+
+func TestAdminHandler_UpdateTier_Invalid(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPut, "/admin/users/user-1/tier", strings.NewReader(`{"tier":"gold"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("user-1")
+
+	mockService := new(MockAdminService)
+	handler := NewAdminHandler(mockService)
+
+	mockService.On("SetTier", "user-1", "gold").Return(model.User{}, fmt.Errorf("unknown tier: %w", model.ErrInvalidInput))
+
+	err := handler.UpdateTier(c)
+
+	httpErr, ok := err.(*echo.HTTPError)
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusBadRequest, httpErr.Code)
+}
+
+func TestAdminHandler_DisableUser(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/admin/users/user-1/disable", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("user-1")
+	c.Set("user", &JWTClaims{UserID: "admin-1", Role: model.RoleAdmin})
+
+	mockService := new(MockAdminService)
+	handler := NewAdminHandler(mockService)
+
+	mockService.On("SetDisabled", "admin-1", "user-1", true).Return(model.User{ID: "user-1", Disabled: true}, nil)
+
+	err := handler.DisableUser(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestAdminHandler_ForceLogout(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/admin/users/user-1/logout", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("user-1")
+
+	mockService := new(MockAdminService)
+	handler := NewAdminHandler(mockService)
+
+	mockService.On("ForceLogout", "user-1").Return(model.User{ID: "user-1"}, nil)
+
+	err := handler.ForceLogout(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+}
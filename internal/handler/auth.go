@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 	"time"
 
@@ -34,6 +35,7 @@ type JWTClaims struct {
 	UserID string `json:"user_id"`
 	Email  string `json:"email"`
 	Name   string `json:"name"`
+	Role   string `json:"role"`
 	jwt.RegisteredClaims
 }
 
@@ -54,6 +56,10 @@ func (h *AuthHandler) Login(c echo.Context) error {
 
 	// Authenticate user via service
 	user, err := h.userService.AuthenticateUser(req.Email, req.Password)
+	if errors.Is(err, model.ErrForbidden) {
+		logger.Warn("Login attempt on disabled account", zap.String("email", req.Email))
+		return echo.NewHTTPError(http.StatusForbidden, "Account is disabled")
+	}
 	if err != nil {
 		// Return generic error to prevent user enumeration
 		logger.Warn("Failed login attempt", zap.String("email", req.Email), zap.Error(err))
@@ -61,7 +67,7 @@ func (h *AuthHandler) Login(c echo.Context) error {
 	}
 
 	// Generate JWT token
-	token, expiresAt, err := generateJWT(user.ID, user.Email, user.Name)
+	token, expiresAt, err := generateJWT(user.ID, user.Email, user.Name, user.Role)
 	if err != nil {
 		logger.Error("Failed to generate JWT token", zap.String("user_id", user.ID), zap.Error(err))
 		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to generate token")
@@ -77,13 +83,7 @@ func (h *AuthHandler) Login(c echo.Context) error {
 		Token:     token,
 		TokenType: "Bearer",
 		ExpiresIn: int64(expiresIn),
-		User: transport.UserResponse{
-			ID:        user.ID,
-			Name:      user.Name,
-			Email:     user.Email,
-			CreatedAt: user.CreatedAt,
-			UpdatedAt: user.UpdatedAt,
-		},
+		User:      toUserResponse(user),
 	}
 
 	return c.JSON(http.StatusOK, resp)
@@ -141,17 +141,24 @@ func (h *AuthHandler) CreateUser(c echo.Context) error {
 	logger.Info("User created successfully", zap.String("user_id", createdUser.ID), zap.String("email", createdUser.Email))
 
 	// Build response (excluding password)
-	resp := transport.UserResponse{
-		ID:        createdUser.ID,
-		Name:      createdUser.Name,
-		Email:     createdUser.Email,
-		CreatedAt: createdUser.CreatedAt,
-		UpdatedAt: createdUser.UpdatedAt,
-	}
+	resp := toUserResponse(createdUser)
 
 	return c.JSON(http.StatusCreated, resp)
 }
 
+// toUserResponse converts model.User to its API representation (excluding password)
+func toUserResponse(user model.User) transport.UserResponse {
+	return transport.UserResponse{
+		ID:        user.ID,
+		Name:      user.Name,
+		Email:     user.Email,
+		Tier:      user.Tier,
+		Role:      user.Role,
+		CreatedAt: user.CreatedAt,
+		UpdatedAt: user.UpdatedAt,
+	}
+}
+
 // containsString checks if a string contains a substring
 func containsString(s, substr string) bool {
 	return len(s) >= len(substr) &&
@@ -33,6 +33,11 @@ func (m *MockUserService) CreateUser(user model.User) (model.User, error) {
 	return args.Get(0).(model.User), args.Error(1)
 }
 
+func (m *MockUserService) GetUser(id string) (model.User, error) {
+	args := m.Called(id)
+	return args.Get(0).(model.User), args.Error(1)
+}
+
 // Test NewAuthHandler
 func TestNewAuthHandler(t *testing.T) {
 	mockService := new(MockUserService)
@@ -393,7 +398,7 @@ func TestAuthHandler_CreateUser_GenericError(t *testing.T) {
 
 // Test generateJWT
 func TestGenerateJWT_Success(t *testing.T) {
-	token, expiresAt, err := generateJWT("user123", "test@example.com", "Test User")
+	token, expiresAt, err := generateJWT("user123", "test@example.com", "Test User", model.RoleUser)
 
 	assert.NoError(t, err)
 	assert.NotEmpty(t, token)
@@ -406,6 +411,7 @@ func TestGenerateJWT_Success(t *testing.T) {
 	assert.Equal(t, "user123", claims.UserID)
 	assert.Equal(t, "test@example.com", claims.Email)
 	assert.Equal(t, "Test User", claims.Name)
+	assert.Equal(t, model.RoleUser, claims.Role)
 	assert.Equal(t, "athena", claims.Issuer)
 }
 
@@ -414,7 +420,7 @@ func TestGenerateJWT_WithCustomSecret(t *testing.T) {
 	os.Setenv("JWT_SECRET", "custom-test-secret")
 	defer os.Unsetenv("JWT_SECRET")
 
-	token, expiresAt, err := generateJWT("user123", "test@example.com", "Test User")
+	token, expiresAt, err := generateJWT("user123", "test@example.com", "Test User", model.RoleUser)
 
 	assert.NoError(t, err)
 	assert.NotEmpty(t, token)
@@ -428,7 +434,7 @@ func TestGenerateJWT_WithCustomSecret(t *testing.T) {
 
 // Test ValidateJWT - Success
 func TestValidateJWT_Success(t *testing.T) {
-	token, _, err := generateJWT("user123", "test@example.com", "Test User")
+	token, _, err := generateJWT("user123", "test@example.com", "Test User", model.RoleUser)
 	assert.NoError(t, err)
 
 	claims, err := validateJWT(token)
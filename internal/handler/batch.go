@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/tsongpon/athena/internal/logger"
+	"github.com/tsongpon/athena/internal/model"
+	"github.com/tsongpon/athena/internal/transport"
+	"go.uber.org/zap"
+)
+
+type BatchHandler struct {
+	batchService BatchService
+}
+
+func NewBatchHandler(batchService BatchService) *BatchHandler {
+	return &BatchHandler{
+		batchService: batchService,
+	}
+}
+
+// ApplyBatch applies several bookmark operations at once. It answers 200 even when some of
+// them fail, the status of each is in its result.
+func (h *BatchHandler) ApplyBatch(c echo.Context) error {
+	authenticatedUser, err := getAuthenticatedUser(c)
+	if err != nil {
+		return err
+	}
+
+	var req transport.BatchRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	ops := make([]model.BatchOperation, len(req.Operations))
+	for i, op := range req.Operations {
+		ops[i] = model.BatchOperation{
+			Op:           op.Op,
+			BookmarkID:   op.BookmarkID,
+			Tags:         op.Tags,
+			CollectionID: op.CollectionID,
+		}
+	}
+
+	results, err := h.batchService.ApplyBatch(authenticatedUser.UserID, ops)
+	if errors.Is(err, model.ErrInvalidInput) {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	if err != nil {
+		logger.Error("Failed to apply bookmark batch", zap.String("user_id", authenticatedUser.UserID), zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to apply operations")
+	}
+
+	resp := transport.BatchResponse{Results: make([]transport.BatchResultTransport, len(results))}
+	for i, result := range results {
+		resp.Results[i] = transport.BatchResultTransport{
+			Index:      i,
+			Op:         result.Op,
+			BookmarkID: result.BookmarkID,
+			Status:     result.Status,
+			Error:      result.Error,
+		}
+		if result.Status == model.BatchStatusOK {
+			resp.Succeeded++
+		} else {
+			resp.Failed++
+		}
+	}
+	return c.JSON(http.StatusOK, resp)
+}
@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/tsongpon/athena/internal/model"
+)
+
+// MockBatchService is a mock implementation of BatchService
+type MockBatchService struct {
+	mock.Mock
+}
+
+func (m *MockBatchService) ApplyBatch(userID string, ops []model.BatchOperation) ([]model.BatchResult, error) {
+	args := m.Called(userID, ops)
+	results, _ := args.Get(0).([]model.BatchResult)
+	return results, args.Error(1)
+}
+
+func TestBatchHandler_ApplyBatch(t *testing.T) {
+	c, rec := newCollectionContext(http.MethodPost, "/bookmarks/batch",
+		`{"operations":[{"op":"tag","bookmark_id":"b1","tags":["go"]},{"op":"move","bookmark_id":"b2","collection_id":"c1"}]}`)
+
+	mockService := new(MockBatchService)
+	handler := NewBatchHandler(mockService)
+	mockService.On("ApplyBatch", "user123", []model.BatchOperation{
+		{Op: model.BatchOpTag, BookmarkID: "b1", Tags: []string{"go"}},
+		{Op: model.BatchOpMove, BookmarkID: "b2", CollectionID: "c1"},
+	}).Return([]model.BatchResult{
+		{Op: model.BatchOpTag, BookmarkID: "b1", Status: model.BatchStatusOK},
+		{Op: model.BatchOpMove, BookmarkID: "b2", Status: model.BatchStatusForbidden, Error: "forbidden"},
+	}, nil)
+
+	err := handler.ApplyBatch(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{
+		"results": [
+			{"index":0,"op":"tag","bookmark_id":"b1","status":"ok"},
+			{"index":1,"op":"move","bookmark_id":"b2","status":"forbidden","error":"forbidden"}
+		],
+		"succeeded": 1,
+		"failed": 1
+	}`, rec.Body.String())
+	mockService.AssertExpectations(t)
+}
+
+func TestBatchHandler_ApplyBatch_InvalidInput(t *testing.T) {
+	c, rec := newCollectionContext(http.MethodPost, "/bookmarks/batch", `{"operations":[]}`)
+
+	mockService := new(MockBatchService)
+	handler := NewBatchHandler(mockService)
+	mockService.On("ApplyBatch", "user123", []model.BatchOperation{}).
+		Return(nil, fmt.Errorf("between 1 and 500 operations are required: %w", model.ErrInvalidInput))
+
+	err := handler.ApplyBatch(c)
+
+	httpErr, ok := err.(*echo.HTTPError)
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusBadRequest, httpErr.Code)
+	assert.Equal(t, http.StatusOK, rec.Code) // Nothing was written
+}
@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/tsongpon/athena/internal/logger"
+	"github.com/tsongpon/athena/internal/model"
+	"go.uber.org/zap"
+)
+
+// maxWebhookBodyBytes caps the size of webhook payloads read into memory
+const maxWebhookBodyBytes = 1 << 20
+
+type BillingHandler struct {
+	billingService BillingService
+}
+
+func NewBillingHandler(billingService BillingService) *BillingHandler {
+	return &BillingHandler{
+		billingService: billingService,
+	}
+}
+
+// Webhook receives subscription events from the billing provider
+func (h *BillingHandler) Webhook(c echo.Context) error {
+	payload, err := io.ReadAll(io.LimitReader(c.Request().Body, maxWebhookBodyBytes))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Failed to read request body")
+	}
+
+	signature := c.Request().Header.Get(h.billingService.SignatureHeader())
+	if err := h.billingService.HandleWebhook(payload, signature); err != nil {
+		if errors.Is(err, model.ErrInvalidInput) {
+			logger.Warn("Rejected billing webhook", zap.Error(err))
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		logger.Error("Failed to handle billing webhook", zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to handle webhook")
+	}
+
+	return c.JSON(http.StatusOK, map[string]bool{"received": true})
+}
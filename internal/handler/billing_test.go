@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/tsongpon/athena/internal/model"
+)
+
+// MockBillingService is a mock implementation of BillingService
+type MockBillingService struct {
+	mock.Mock
+}
+
+func (m *MockBillingService) SignatureHeader() string {
+	return "Stripe-Signature"
+}
+
+func (m *MockBillingService) HandleWebhook(payload []byte, signature string) error {
+	args := m.Called(string(payload), signature)
+	return args.Error(0)
+}
+
+func newWebhookContext(body string) (echo.Context, *httptest.ResponseRecorder) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/billing/webhook", strings.NewReader(body))
+	req.Header.Set("Stripe-Signature", "t=1,v1=abc")
+	rec := httptest.NewRecorder()
+	return e.NewContext(req, rec), rec
+}
+
+func TestBillingHandler_Webhook_Success(t *testing.T) {
+	c, rec := newWebhookContext(`{"id":"evt_1"}`)
+
+	mockService := new(MockBillingService)
+	mockService.On("HandleWebhook", `{"id":"evt_1"}`, "t=1,v1=abc").Return(nil)
+
+	err := NewBillingHandler(mockService).Webhook(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestBillingHandler_Webhook_InvalidSignature(t *testing.T) {
+	c, _ := newWebhookContext(`{"id":"evt_1"}`)
+
+	mockService := new(MockBillingService)
+	mockService.On("HandleWebhook", mock.Anything, mock.Anything).Return(fmt.Errorf("invalid webhook signature: %w", model.ErrInvalidInput))
+
+	err := NewBillingHandler(mockService).Webhook(c)
+
+	httpErr, ok := err.(*echo.HTTPError)
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusBadRequest, httpErr.Code)
+}
+
+func TestBillingHandler_Webhook_ServiceError(t *testing.T) {
+	c, _ := newWebhookContext(`{"id":"evt_1"}`)
+
+	mockService := new(MockBillingService)
+	mockService.On("HandleWebhook", mock.Anything, mock.Anything).Return(errors.New("datastore unavailable"))
+
+	err := NewBillingHandler(mockService).Webhook(c)
+
+	httpErr, ok := err.(*echo.HTTPError)
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusInternalServerError, httpErr.Code)
+}
@@ -1,8 +1,11 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/tsongpon/athena/internal/logger"
@@ -46,8 +49,19 @@ func (h *BookmarkHandler) CreateBookmark(c echo.Context) error {
 	b := model.Bookmark{
 		URL:    bt.URL,
 		UserID: authenticatedUser.UserID, // Use authenticated user's ID from JWT
+		Tags:   bt.Tags,
+		Notes:  bt.Notes,
 	}
 	createdBookmark, err := h.bookmarkService.CreateBookmark(b)
+	if errors.Is(err, model.ErrInvalidInput) {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	if httpErr := entitlementError(err); httpErr != nil {
+		logger.Info("Bookmark creation rejected by tier limits",
+			zap.String("user_id", authenticatedUser.UserID),
+			zap.Error(err))
+		return httpErr
+	}
 	if err != nil {
 		logger.Error("Failed to create bookmark",
 			zap.String("user_id", authenticatedUser.UserID),
@@ -59,16 +73,7 @@ func (h *BookmarkHandler) CreateBookmark(c echo.Context) error {
 		zap.String("bookmark_id", createdBookmark.ID),
 		zap.String("user_id", createdBookmark.UserID),
 		zap.String("url", createdBookmark.URL))
-	responseTransport := transport.BookmarkTransport{
-		ID:             createdBookmark.ID,
-		URL:            createdBookmark.URL,
-		Title:          createdBookmark.Title,
-		UserID:         createdBookmark.UserID,
-		MainImageURL:   createdBookmark.MainImageURL,
-		ContentSummary: createdBookmark.ContentSummary,
-		IsArchived:     createdBookmark.IsArchived,
-	}
-	return c.JSON(http.StatusCreated, responseTransport)
+	return c.JSON(http.StatusCreated, toBookmarkTransport(createdBookmark))
 }
 
 func (h *BookmarkHandler) GetBookmark(c echo.Context) error {
@@ -83,26 +88,14 @@ func (h *BookmarkHandler) GetBookmark(c echo.Context) error {
 		return err
 	}
 
-	bookmark, err := h.bookmarkService.GetBookmark(id)
+	// The service allows the owner and collaborators of a collection holding the bookmark
+	bookmark, err := h.bookmarkService.GetBookmark(authenticatedUser.UserID, id)
 	if err != nil {
-		return err
+		return bookmarkError(err)
 	}
 
-	// Authorization check: ensure user can only access their own bookmarks
-	if bookmark.UserID != authenticatedUser.UserID {
-		return echo.NewHTTPError(http.StatusForbidden, "Access denied")
-	}
-
-	t := transport.BookmarkTransport{
-		ID:             bookmark.ID,
-		URL:            bookmark.URL,
-		Title:          bookmark.Title,
-		UserID:         bookmark.UserID,
-		MainImageURL:   bookmark.MainImageURL,
-		ContentSummary: bookmark.ContentSummary,
-		IsArchived:     bookmark.IsArchived,
-	}
-	return c.JSON(http.StatusOK, t)
+	setBookmarkETag(c, bookmark)
+	return c.JSON(http.StatusOK, toBookmarkTransport(bookmark))
 }
 
 func (h *BookmarkHandler) GetBookmarks(c echo.Context) error {
@@ -120,10 +113,52 @@ func (h *BookmarkHandler) GetBookmarks(c echo.Context) error {
 		archived = false
 	}
 
+	// Optional filters and sort order
+	query, err := bookmarkQueryParams(c)
+	if err != nil {
+		return err
+	}
+	query.UserID = userID
+	query.Archived = archived
+	filtered := query != model.BookmarkQuery{UserID: userID, Archived: archived}
+
 	// Check for pagination parameters
+	cursor := c.QueryParam("cursor")
 	pageParam := c.QueryParam("page")
 	pageSizeParam := c.QueryParam("page_size")
 
+	// A cursor from an earlier response continues the listing from where it left off
+	if cursor != "" {
+		pageSize, err := strconv.Atoi(pageSizeParam)
+		if err != nil || pageSize < 1 {
+			pageSize = 20 // Default page size
+		}
+		query.PageSize = pageSize
+		query.Cursor = cursor
+		response, err := h.bookmarkService.ListBookmarks(query)
+		if errors.Is(err, model.ErrInvalidInput) {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		if err != nil {
+			logger.Error("Failed to get bookmarks from cursor",
+				zap.String("user_id", userID),
+				zap.Int("page_size", pageSize),
+				zap.Error(err))
+			return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		}
+
+		ts := make([]transport.BookmarkTransport, len(response.Bookmarks))
+		for i, b := range response.Bookmarks {
+			ts[i] = toBookmarkTransport(b)
+		}
+		return c.JSON(http.StatusOK, map[string]interface{}{
+			"bookmarks":   ts,
+			"page_size":   response.PageSize,
+			"next_cursor": response.NextCursor,
+			"prev_cursor": response.PrevCursor,
+		})
+	}
+
 	// If pagination parameters are provided, use paginated endpoint
 	if pageParam != "" || pageSizeParam != "" {
 		page, err := strconv.Atoi(pageParam)
@@ -136,7 +171,17 @@ func (h *BookmarkHandler) GetBookmarks(c echo.Context) error {
 			pageSize = 20 // Default page size
 		}
 
-		response, err := h.bookmarkService.GetBookmarksWithPagination(userID, archived, page, pageSize)
+		var response model.BookmarkListResponse
+		if filtered {
+			query.Page = page
+			query.PageSize = pageSize
+			response, err = h.bookmarkService.ListBookmarks(query)
+		} else {
+			response, err = h.bookmarkService.GetBookmarksWithPagination(userID, archived, page, pageSize)
+		}
+		if errors.Is(err, model.ErrInvalidInput) {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
 		if err != nil {
 			logger.Error("Failed to get paginated bookmarks",
 				zap.String("user_id", userID),
@@ -149,16 +194,7 @@ func (h *BookmarkHandler) GetBookmarks(c echo.Context) error {
 		// Convert bookmarks to transport format
 		ts := make([]transport.BookmarkTransport, len(response.Bookmarks))
 		for i, b := range response.Bookmarks {
-			ts[i] = transport.BookmarkTransport{
-				ID:             b.ID,
-				URL:            b.URL,
-				Title:          b.Title,
-				UserID:         b.UserID,
-				MainImageURL:   b.MainImageURL,
-				ContentSummary: b.ContentSummary,
-				CreatedAt:      b.CreatedAt,
-				IsArchived:     b.IsArchived,
-			}
+			ts[i] = toBookmarkTransport(b)
 		}
 
 		// Return paginated response
@@ -169,31 +205,107 @@ func (h *BookmarkHandler) GetBookmarks(c echo.Context) error {
 			"page_size":   response.PageSize,
 			"total_pages": response.TotalPages,
 		}
+		if response.NextCursor != "" {
+			paginatedResponse["next_cursor"] = response.NextCursor
+		}
+		if response.PrevCursor != "" {
+			paginatedResponse["prev_cursor"] = response.PrevCursor
+		}
 
 		return c.JSON(http.StatusOK, paginatedResponse)
 	}
 
 	// No pagination - return all bookmarks
-	bookmarks, err := h.bookmarkService.GetAllBookmarks(userID, archived)
-	if err != nil {
-		return err
+	var bookmarks []model.Bookmark
+	if filtered {
+		response, err := h.bookmarkService.ListBookmarks(query)
+		if errors.Is(err, model.ErrInvalidInput) {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		if err != nil {
+			return err
+		}
+		bookmarks = response.Bookmarks
+	} else {
+		bookmarks, err = h.bookmarkService.GetAllBookmarks(userID, archived)
+		if err != nil {
+			return err
+		}
 	}
 	ts := make([]transport.BookmarkTransport, len(bookmarks))
 	for i, b := range bookmarks {
-		ts[i] = transport.BookmarkTransport{
-			ID:             b.ID,
-			URL:            b.URL,
-			Title:          b.Title,
-			UserID:         b.UserID,
-			MainImageURL:   b.MainImageURL,
-			ContentSummary: b.ContentSummary,
-			CreatedAt:      b.CreatedAt,
-			IsArchived:     b.IsArchived,
-		}
+		ts[i] = toBookmarkTransport(b)
 	}
 	return c.JSON(http.StatusOK, ts)
 }
 
+// bookmarkQueryParams reads the optional filters and sort order of the bookmark listing.
+// Combinations are checked by the service.
+func bookmarkQueryParams(c echo.Context) (model.BookmarkQuery, error) {
+	query := model.BookmarkQuery{
+		CollectionID:     c.QueryParam("collection_id"),
+		Health:           c.QueryParam("health"),
+		Domain:           c.QueryParam("domain"),
+		Tag:              c.QueryParam("tag"),
+		EnrichmentStatus: c.QueryParam("enrichment_status"),
+		Sort:             c.QueryParam("sort"),
+		Order:            c.QueryParam("order"),
+	}
+	if query.Health != "" && !model.IsValidLinkHealthStatus(query.Health) {
+		return model.BookmarkQuery{}, echo.NewHTTPError(http.StatusBadRequest, "health must be one of ok, broken, error")
+	}
+	if query.EnrichmentStatus != "" && !model.IsValidEnrichmentStatus(query.EnrichmentStatus) {
+		return model.BookmarkQuery{}, echo.NewHTTPError(http.StatusBadRequest, "enrichment_status must be one of none, ok, failed")
+	}
+	if query.Sort != "" && !model.IsValidBookmarkSort(query.Sort) {
+		return model.BookmarkQuery{}, echo.NewHTTPError(http.StatusBadRequest, "sort must be one of created, updated, title, domain, last_opened")
+	}
+	if query.Order != "" && query.Order != model.SortAscending && query.Order != model.SortDescending {
+		return model.BookmarkQuery{}, echo.NewHTTPError(http.StatusBadRequest, "order must be asc or desc")
+	}
+	if param := c.QueryParam("has_summary"); param != "" {
+		hasSummary, err := strconv.ParseBool(param)
+		if err != nil {
+			return model.BookmarkQuery{}, echo.NewHTTPError(http.StatusBadRequest, "has_summary must be true or false")
+		}
+		query.HasSummary = &hasSummary
+	}
+	if param := c.QueryParam("read"); param != "" {
+		read, err := strconv.ParseBool(param)
+		if err != nil {
+			return model.BookmarkQuery{}, echo.NewHTTPError(http.StatusBadRequest, "read must be true or false")
+		}
+		query.Read = &read
+	}
+	if param := c.QueryParam("favorite"); param != "" {
+		favorite, err := strconv.ParseBool(param)
+		if err != nil {
+			return model.BookmarkQuery{}, echo.NewHTTPError(http.StatusBadRequest, "favorite must be true or false")
+		}
+		query.Favorite = &favorite
+	}
+	var err error
+	if query.CreatedFrom, err = parseDateParam(c.QueryParam("created_from")); err != nil {
+		return model.BookmarkQuery{}, echo.NewHTTPError(http.StatusBadRequest, "created_from must be a date (2006-01-02) or an RFC 3339 time")
+	}
+	if query.CreatedTo, err = parseDateParam(c.QueryParam("created_to")); err != nil {
+		return model.BookmarkQuery{}, echo.NewHTTPError(http.StatusBadRequest, "created_to must be a date (2006-01-02) or an RFC 3339 time")
+	}
+	return query, nil
+}
+
+// parseDateParam parses an RFC 3339 time, or a date meaning its midnight UTC. An empty value
+// is the zero time.
+func parseDateParam(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.DateOnly, value)
+}
+
 func (h *BookmarkHandler) ArchiveBookmark(c echo.Context) error {
 	id := c.Param("id")
 	if id == "" {
@@ -206,24 +318,42 @@ func (h *BookmarkHandler) ArchiveBookmark(c echo.Context) error {
 		return err
 	}
 
-	// Get the bookmark first to verify ownership
-	bookmark, err := h.bookmarkService.GetBookmark(id)
+	version, err := ifMatchVersion(c)
 	if err != nil {
 		return err
 	}
 
-	// Authorization check: ensure user can only archive their own bookmarks
-	if bookmark.UserID != authenticatedUser.UserID {
-		return echo.NewHTTPError(http.StatusForbidden, "Access denied")
+	// Only the owner can archive a bookmark
+	updated, err := h.bookmarkService.ArchiveBookmark(authenticatedUser.UserID, id, version)
+	if err != nil {
+		return bookmarkError(err)
 	}
+	setBookmarkETag(c, updated)
+	return c.NoContent(http.StatusNoContent)
+}
 
-	if _, err := h.bookmarkService.ArchiveBookmark(id); err != nil {
+// OpenBookmark records that the owner opened the link of a bookmark
+func (h *BookmarkHandler) OpenBookmark(c echo.Context) error {
+	id := c.Param("id")
+	if id == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "ID is required")
+	}
+
+	// Get authenticated user ID from JWT token
+	authenticatedUser, err := getAuthenticatedUser(c)
+	if err != nil {
 		return err
 	}
+
+	// Only the owner's opens are recorded, collaborators have their own bookmarks
+	if _, err := h.bookmarkService.OpenBookmark(authenticatedUser.UserID, id); err != nil {
+		return bookmarkError(err)
+	}
 	return c.NoContent(http.StatusNoContent)
 }
 
-func (h *BookmarkHandler) DeleteBookmark(c echo.Context) error {
+// UpdateReadingState marks a bookmark read or favorite and records the reading progress
+func (h *BookmarkHandler) UpdateReadingState(c echo.Context) error {
 	id := c.Param("id")
 	if id == "" {
 		return echo.NewHTTPError(http.StatusBadRequest, "ID is required")
@@ -235,19 +365,282 @@ func (h *BookmarkHandler) DeleteBookmark(c echo.Context) error {
 		return err
 	}
 
-	// Get the bookmark first to verify ownership
-	bookmark, err := h.bookmarkService.GetBookmark(id)
+	var req transport.ReadingStateRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+	version, err := ifMatchVersion(c)
 	if err != nil {
 		return err
 	}
 
-	// Authorization check: ensure user can only delete their own bookmarks
-	if bookmark.UserID != authenticatedUser.UserID {
-		return echo.NewHTTPError(http.StatusForbidden, "Access denied")
+	updated, err := h.bookmarkService.UpdateReadingState(authenticatedUser.UserID, id, model.ReadingStateUpdate{
+		IsRead:          req.IsRead,
+		IsFavorite:      req.IsFavorite,
+		ReadingProgress: req.ReadingProgress,
+	}, version)
+	if err != nil {
+		if errors.Is(err, model.ErrInvalidInput) {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		return bookmarkError(err)
+	}
+	setBookmarkETag(c, updated)
+	return c.JSON(http.StatusOK, toBookmarkTransport(updated))
+}
+
+// UpdateNotes replaces the Markdown notes of a bookmark
+func (h *BookmarkHandler) UpdateNotes(c echo.Context) error {
+	id := c.Param("id")
+	if id == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "ID is required")
+	}
+
+	// Get authenticated user ID from JWT token
+	authenticatedUser, err := getAuthenticatedUser(c)
+	if err != nil {
+		return err
 	}
 
-	if err := h.bookmarkService.DeleteBookmark(id); err != nil {
+	var req transport.NotesRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+	version, err := ifMatchVersion(c)
+	if err != nil {
+		return err
+	}
+
+	updated, err := h.bookmarkService.UpdateNotes(authenticatedUser.UserID, id, req.Notes, version)
+	if err != nil {
+		if errors.Is(err, model.ErrInvalidInput) {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		return bookmarkError(err)
+	}
+	setBookmarkETag(c, updated)
+	return c.JSON(http.StatusOK, toBookmarkTransport(updated))
+}
+
+// RefreshBookmark re-runs title, image, summary and metadata enrichment of a bookmark
+func (h *BookmarkHandler) RefreshBookmark(c echo.Context) error {
+	id := c.Param("id")
+	if id == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "ID is required")
+	}
+
+	authenticatedUser, err := getAuthenticatedUser(c)
+	if err != nil {
+		return err
+	}
+
+	// Only the owner can refresh a bookmark
+	bookmark, err := h.bookmarkService.RefreshBookmark(authenticatedUser.UserID, id)
+	if err != nil {
+		return bookmarkError(err)
+	}
+	return c.JSON(http.StatusOK, toBookmarkTransport(bookmark))
+}
+
+// RefreshBookmarks queues several bookmarks for enrichment and answers before they are done
+func (h *BookmarkHandler) RefreshBookmarks(c echo.Context) error {
+	authenticatedUser, err := getAuthenticatedUser(c)
+	if err != nil {
 		return err
 	}
+
+	var req transport.BulkRefreshRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	result, err := h.bookmarkService.RefreshBookmarks(authenticatedUser.UserID, req.BookmarkIDs)
+	if errors.Is(err, model.ErrInvalidInput) {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	if err != nil {
+		logger.Error("Failed to queue bookmark refresh", zap.String("user_id", authenticatedUser.UserID), zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to refresh bookmarks")
+	}
+
+	resp := transport.BulkRefreshResponse{
+		Queued:   []string{},
+		NotFound: []string{},
+	}
+	resp.Queued = append(resp.Queued, result.Queued...)
+	resp.NotFound = append(resp.NotFound, result.NotFound...)
+	return c.JSON(http.StatusAccepted, resp)
+}
+
+// ListEnrichments returns the enrichment history of a bookmark, oldest first
+func (h *BookmarkHandler) ListEnrichments(c echo.Context) error {
+	id := c.Param("id")
+	if id == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "ID is required")
+	}
+
+	authenticatedUser, err := getAuthenticatedUser(c)
+	if err != nil {
+		return err
+	}
+
+	bookmark, err := h.bookmarkService.GetBookmark(authenticatedUser.UserID, id)
+	if err != nil {
+		return bookmarkError(err)
+	}
+
+	ts := make([]transport.EnrichmentTransport, len(bookmark.Enrichments))
+	for i, e := range bookmark.Enrichments {
+		ts[i] = transport.EnrichmentTransport{
+			At:              e.At,
+			Trigger:         e.Trigger,
+			Summary:         e.Summary,
+			SummaryProvider: e.SummaryProvider,
+			Updated:         append([]string{}, e.Updated...),
+			Failed:          e.Failed,
+		}
+	}
+	return c.JSON(http.StatusOK, ts)
+}
+
+func (h *BookmarkHandler) DeleteBookmark(c echo.Context) error {
+	id := c.Param("id")
+	if id == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "ID is required")
+	}
+
+	// Get authenticated user ID from JWT token
+	authenticatedUser, err := getAuthenticatedUser(c)
+	if err != nil {
+		return err
+	}
+
+	version, err := ifMatchVersion(c)
+	if err != nil {
+		return err
+	}
+
+	// Only the owner can delete a bookmark; it goes to the trash
+	if err := h.bookmarkService.DeleteBookmark(authenticatedUser.UserID, id, version); err != nil {
+		return bookmarkError(err)
+	}
 	return c.NoContent(http.StatusNoContent)
 }
+
+// bookmarkError maps service errors to HTTP errors
+func bookmarkError(err error) error {
+	switch {
+	case errors.Is(err, model.ErrForbidden):
+		return echo.NewHTTPError(http.StatusForbidden, "Access denied")
+	case errors.Is(err, model.ErrNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, "Bookmark not found")
+	case errors.Is(err, model.ErrPreconditionFailed):
+		return echo.NewHTTPError(http.StatusPreconditionFailed, "Bookmark was changed, get it again before changing it")
+	case errors.Is(err, model.ErrConflict):
+		return echo.NewHTTPError(http.StatusConflict, "Bookmark is being changed, try again")
+	default:
+		return err
+	}
+}
+
+// setBookmarkETag sets the ETag header to the version of b. Bookmarks stored before versions
+// existed have none until their next change.
+func setBookmarkETag(c echo.Context, b model.Bookmark) {
+	if b.Version > 0 {
+		c.Response().Header().Set("ETag", strconv.Quote(strconv.FormatInt(b.Version, 10)))
+	}
+}
+
+// ifMatchVersion returns the bookmark version of the If-Match header, zero when it is absent
+// or "*". Only a single strong ETag is supported; any other value can never match.
+func ifMatchVersion(c echo.Context) (int64, error) {
+	ifMatch := strings.TrimSpace(c.Request().Header.Get("If-Match"))
+	if ifMatch == "" || ifMatch == "*" {
+		return 0, nil
+	}
+	unquoted, err := strconv.Unquote(ifMatch)
+	if err != nil || !strings.HasPrefix(ifMatch, `"`) {
+		return 0, echo.NewHTTPError(http.StatusPreconditionFailed, "If-Match does not match the bookmark")
+	}
+	version, err := strconv.ParseInt(unquoted, 10, 64)
+	if err != nil || version <= 0 {
+		return 0, echo.NewHTTPError(http.StatusPreconditionFailed, "If-Match does not match the bookmark")
+	}
+	return version, nil
+}
+
+func toBookmarkTransport(b model.Bookmark) transport.BookmarkTransport {
+	t := transport.BookmarkTransport{
+		ID:              b.ID,
+		URL:             b.URL,
+		Title:           b.Title,
+		UserID:          b.UserID,
+		MainImageURL:    b.MainImageURL,
+		ContentSummary:  b.ContentSummary,
+		CreatedAt:       b.CreatedAt,
+		UpdatedAt:       b.UpdatedAt,
+		Version:         b.Version,
+		IsArchived:      b.IsArchived,
+		IsRead:          b.IsRead,
+		IsFavorite:      b.IsFavorite,
+		ReadingProgress: b.ReadingProgress,
+		CollectionIDs:   b.CollectionIDs,
+		Tags:            b.Tags,
+		Notes:           b.Notes,
+
+		Description: b.Metadata.Description,
+		SiteName:    b.Metadata.SiteName,
+		PageType:    b.Metadata.Type,
+		Author:      b.Metadata.Author,
+		FaviconURL:  b.Metadata.FaviconURL,
+		PageCount:   b.Metadata.PageCount,
+		WordCount:   b.Metadata.WordCount,
+		ReadingTime: int(b.Metadata.ReadingTime() / time.Minute),
+	}
+	if b.Health.Status != "" {
+		t.LinkHealth = &transport.LinkHealthTransport{
+			Status:     b.Health.Status,
+			StatusCode: b.Health.StatusCode,
+			FinalURL:   b.Health.FinalURL,
+			Error:      b.Health.Error,
+			CheckedAt:  b.Health.CheckedAt,
+		}
+	}
+	if !b.LastOpenedAt.IsZero() {
+		lastOpenedAt := b.LastOpenedAt
+		t.LastOpenedAt = &lastOpenedAt
+	}
+	if !b.ReadAt.IsZero() {
+		readAt := b.ReadAt
+		t.ReadAt = &readAt
+	}
+	if !b.DeletedAt.IsZero() {
+		deletedAt := b.DeletedAt
+		t.DeletedAt = &deletedAt
+	}
+	if !b.SnapshotAt.IsZero() {
+		snapshotAt := b.SnapshotAt
+		t.SnapshotAt = &snapshotAt
+	}
+	if len(b.Thumbnails) > 0 {
+		// The capture time busts caches that keep images as immutable
+		version := strconv.FormatInt(b.ThumbnailsAt.Unix(), 10)
+		t.ThumbnailURLs = make(map[string]string, len(b.Thumbnails))
+		for size := range b.Thumbnails {
+			t.ThumbnailURLs[size] = "/images/" + b.ID + "/" + size + "?v=" + version
+		}
+	}
+	if !b.Metadata.PublishedAt.IsZero() {
+		publishedAt := b.Metadata.PublishedAt
+		t.PublishedAt = &publishedAt
+	}
+	if b.Metadata.Embed.URL != "" {
+		t.Embed = &transport.EmbedTransport{
+			Provider: b.Metadata.Embed.Provider,
+			URL:      b.Metadata.Embed.URL,
+			Width:    b.Metadata.Embed.Width,
+			Height:   b.Metadata.Embed.Height,
+		}
+	}
+	return t
+}
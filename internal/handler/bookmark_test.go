@@ -3,6 +3,7 @@ package handler
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -26,13 +27,13 @@ func (m *MockBookmarkService) CreateBookmark(b model.Bookmark) (model.Bookmark,
 	return args.Get(0).(model.Bookmark), args.Error(1)
 }
 
-func (m *MockBookmarkService) GetBookmark(id string) (model.Bookmark, error) {
-	args := m.Called(id)
+func (m *MockBookmarkService) GetBookmark(userID, id string) (model.Bookmark, error) {
+	args := m.Called(userID, id)
 	return args.Get(0).(model.Bookmark), args.Error(1)
 }
 
-func (m *MockBookmarkService) DeleteBookmark(id string) error {
-	args := m.Called(id)
+func (m *MockBookmarkService) DeleteBookmark(userID, id string, version int64) error {
+	args := m.Called(userID, id, version)
 	return args.Error(0)
 }
 
@@ -46,11 +47,41 @@ func (m *MockBookmarkService) GetBookmarksWithPagination(userID string, archived
 	return args.Get(0).(model.BookmarkListResponse), args.Error(1)
 }
 
-func (m *MockBookmarkService) ArchiveBookmark(id string) (model.Bookmark, error) {
-	args := m.Called(id)
+func (m *MockBookmarkService) ListBookmarks(query model.BookmarkQuery) (model.BookmarkListResponse, error) {
+	args := m.Called(query)
+	return args.Get(0).(model.BookmarkListResponse), args.Error(1)
+}
+
+func (m *MockBookmarkService) ArchiveBookmark(userID, id string, version int64) (model.Bookmark, error) {
+	args := m.Called(userID, id, version)
+	return args.Get(0).(model.Bookmark), args.Error(1)
+}
+
+func (m *MockBookmarkService) OpenBookmark(userID, id string) (model.Bookmark, error) {
+	args := m.Called(userID, id)
 	return args.Get(0).(model.Bookmark), args.Error(1)
 }
 
+func (m *MockBookmarkService) UpdateReadingState(userID, id string, update model.ReadingStateUpdate, version int64) (model.Bookmark, error) {
+	args := m.Called(userID, id, update, version)
+	return args.Get(0).(model.Bookmark), args.Error(1)
+}
+
+func (m *MockBookmarkService) UpdateNotes(userID, id, notes string, version int64) (model.Bookmark, error) {
+	args := m.Called(userID, id, notes, version)
+	return args.Get(0).(model.Bookmark), args.Error(1)
+}
+
+func (m *MockBookmarkService) RefreshBookmark(userID, id string) (model.Bookmark, error) {
+	args := m.Called(userID, id)
+	return args.Get(0).(model.Bookmark), args.Error(1)
+}
+
+func (m *MockBookmarkService) RefreshBookmarks(userID string, ids []string) (model.BulkRefresh, error) {
+	args := m.Called(userID, ids)
+	return args.Get(0).(model.BulkRefresh), args.Error(1)
+}
+
 func TestNewBookmarkHandler(t *testing.T) {
 	mockService := new(MockBookmarkService)
 	handler := NewBookmarkHandler(mockService)
@@ -194,6 +225,30 @@ func TestBookmarkHandler_CreateBookmark_ServiceError(t *testing.T) {
 	mockService.AssertExpectations(t)
 }
 
+func TestBookmarkHandler_CreateBookmark_QuotaExceeded(t *testing.T) {
+	e := echo.New()
+	bookmarkJSON := `{"url":"https://example.com"}`
+	req := httptest.NewRequest(http.MethodPost, "/bookmarks", strings.NewReader(bookmarkJSON))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	c.Set("user", &JWTClaims{UserID: "user123", Email: "test@example.com", Name: "Test User"})
+
+	mockService := new(MockBookmarkService)
+	handler := NewBookmarkHandler(mockService)
+
+	mockService.On("CreateBookmark", mock.Anything).Return(model.Bookmark{}, fmt.Errorf("free tier allows 200 bookmarks: %w", model.ErrQuotaExceeded))
+
+	err := handler.CreateBookmark(c)
+
+	httpErr, ok := err.(*echo.HTTPError)
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusPaymentRequired, httpErr.Code)
+
+	mockService.AssertExpectations(t)
+}
+
 func TestBookmarkHandler_CreateBookmark_InvalidJSON(t *testing.T) {
 	e := echo.New()
 	bookmarkJSON := `{"url":"https://example.com","invalid":}`
@@ -238,7 +293,7 @@ func TestBookmarkHandler_GetBookmark_Success(t *testing.T) {
 		CreatedAt:      time.Now(),
 	}
 
-	mockService.On("GetBookmark", "bookmark123").Return(expectedBookmark, nil)
+	mockService.On("GetBookmark", "user123", "bookmark123").Return(expectedBookmark, nil)
 
 	err := handler.GetBookmark(c)
 
@@ -258,6 +313,60 @@ func TestBookmarkHandler_GetBookmark_Success(t *testing.T) {
 	mockService.AssertExpectations(t)
 }
 
+func TestBookmarkHandler_GetBookmark_Metadata(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/bookmarks/bookmark123", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("id")
+	c.SetParamValues("bookmark123")
+	c.Set("user", &JWTClaims{UserID: "user123", Email: "test@example.com", Name: "Test User"})
+
+	mockService := new(MockBookmarkService)
+	handler := NewBookmarkHandler(mockService)
+
+	publishedAt := time.Date(2024, 3, 1, 8, 30, 0, 0, time.UTC)
+	mockService.On("GetBookmark", "user123", "bookmark123").Return(model.Bookmark{
+		ID:     "bookmark123",
+		URL:    "https://video.example.com/watch?v=1",
+		UserID: "user123",
+		Thumbnails: map[string]string{
+			"small": "thumbnails/bookmark123/small.jpg",
+			"large": "thumbnails/bookmark123/large.jpg",
+		},
+		ThumbnailsAt: time.Unix(1731666645, 0),
+		Metadata: model.PageMetadata{
+			SiteName:    "VideoSite",
+			Type:        "video.other",
+			Author:      "Cat Lover",
+			PublishedAt: publishedAt,
+			Embed:       model.Embed{Provider: "VideoSite", URL: "https://player.example.com/1", Width: 560, Height: 315},
+		},
+	}, nil)
+
+	err := handler.GetBookmark(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var responseTransport transport.BookmarkTransport
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &responseTransport))
+	assert.Equal(t, "VideoSite", responseTransport.SiteName)
+	assert.Equal(t, "video.other", responseTransport.PageType)
+	assert.Equal(t, "Cat Lover", responseTransport.Author)
+	if assert.NotNil(t, responseTransport.PublishedAt) {
+		assert.True(t, publishedAt.Equal(*responseTransport.PublishedAt))
+	}
+	assert.Equal(t, &transport.EmbedTransport{Provider: "VideoSite", URL: "https://player.example.com/1", Width: 560, Height: 315}, responseTransport.Embed)
+	assert.NotContains(t, rec.Body.String(), `"page_count"`)
+	assert.Equal(t, map[string]string{
+		"small": "/images/bookmark123/small?v=1731666645",
+		"large": "/images/bookmark123/large?v=1731666645",
+	}, responseTransport.ThumbnailURLs)
+
+	mockService.AssertExpectations(t)
+}
+
 func TestBookmarkHandler_GetBookmark_MissingID(t *testing.T) {
 	e := echo.New()
 	req := httptest.NewRequest(http.MethodGet, "/bookmarks/", nil)
@@ -290,7 +399,7 @@ func TestBookmarkHandler_GetBookmark_ServiceError(t *testing.T) {
 	mockService := new(MockBookmarkService)
 	handler := NewBookmarkHandler(mockService)
 
-	mockService.On("GetBookmark", "bookmark123").Return(model.Bookmark{}, errors.New("not found"))
+	mockService.On("GetBookmark", "user123", "bookmark123").Return(model.Bookmark{}, errors.New("not found"))
 
 	err := handler.GetBookmark(c)
 
@@ -517,14 +626,6 @@ func TestBookmarkHandler_ArchiveBookmark_Success(t *testing.T) {
 	mockService := new(MockBookmarkService)
 	handler := NewBookmarkHandler(mockService)
 
-	existingBookmark := model.Bookmark{
-		ID:         "bookmark123",
-		URL:        "https://example.com",
-		Title:      "Example Site",
-		UserID:     "user123",
-		IsArchived: false,
-	}
-
 	archivedBookmark := model.Bookmark{
 		ID:         "bookmark123",
 		URL:        "https://example.com",
@@ -533,8 +634,7 @@ func TestBookmarkHandler_ArchiveBookmark_Success(t *testing.T) {
 		IsArchived: true,
 	}
 
-	mockService.On("GetBookmark", "bookmark123").Return(existingBookmark, nil)
-	mockService.On("ArchiveBookmark", "bookmark123").Return(archivedBookmark, nil)
+	mockService.On("ArchiveBookmark", "user123", "bookmark123", int64(0)).Return(archivedBookmark, nil)
 
 	err := handler.ArchiveBookmark(c)
 
@@ -577,7 +677,7 @@ func TestBookmarkHandler_ArchiveBookmark_ServiceError(t *testing.T) {
 	mockService := new(MockBookmarkService)
 	handler := NewBookmarkHandler(mockService)
 
-	mockService.On("GetBookmark", "bookmark123").Return(model.Bookmark{}, errors.New("bookmark not found"))
+	mockService.On("ArchiveBookmark", "user123", "bookmark123", int64(0)).Return(model.Bookmark{}, errors.New("bookmark not found"))
 
 	err := handler.ArchiveBookmark(c)
 
@@ -753,7 +853,7 @@ func TestBookmarkHandler_GetBookmark_NotFoundError(t *testing.T) {
 	mockService := new(MockBookmarkService)
 	handler := NewBookmarkHandler(mockService)
 
-	mockService.On("GetBookmark", "nonexistent").Return(model.Bookmark{}, errors.New("bookmark not found"))
+	mockService.On("GetBookmark", "user123", "nonexistent").Return(model.Bookmark{}, errors.New("bookmark not found"))
 
 	err := handler.GetBookmark(c)
 
@@ -893,8 +993,7 @@ func TestBookmarkHandler_ArchiveBookmark_AlreadyArchived(t *testing.T) {
 		IsArchived: true,
 	}
 
-	mockService.On("GetBookmark", "bookmark123").Return(existingArchivedBookmark, nil)
-	mockService.On("ArchiveBookmark", "bookmark123").Return(existingArchivedBookmark, nil)
+	mockService.On("ArchiveBookmark", "user123", "bookmark123", int64(0)).Return(existingArchivedBookmark, nil)
 
 	err := handler.ArchiveBookmark(c)
 
@@ -917,7 +1016,7 @@ func TestBookmarkHandler_ArchiveBookmark_NotFound(t *testing.T) {
 	mockService := new(MockBookmarkService)
 	handler := NewBookmarkHandler(mockService)
 
-	mockService.On("GetBookmark", "nonexistent").Return(model.Bookmark{}, errors.New("bookmark not found"))
+	mockService.On("ArchiveBookmark", "user123", "nonexistent", int64(0)).Return(model.Bookmark{}, errors.New("bookmark not found"))
 
 	err := handler.ArchiveBookmark(c)
 
@@ -999,15 +1098,7 @@ func TestBookmarkHandler_GetBookmark_Forbidden_DifferentUser(t *testing.T) {
 	mockService := new(MockBookmarkService)
 	handler := NewBookmarkHandler(mockService)
 
-	bookmarkOwnedByDifferentUser := model.Bookmark{
-		ID:         "bookmark123",
-		URL:        "https://example.com",
-		Title:      "Example Site",
-		UserID:     "user123", // Owned by different user
-		IsArchived: false,
-	}
-
-	mockService.On("GetBookmark", "bookmark123").Return(bookmarkOwnedByDifferentUser, nil)
+	mockService.On("GetBookmark", "user456", "bookmark123").Return(model.Bookmark{}, fmt.Errorf("cannot view bookmark: %w", model.ErrForbidden))
 
 	err := handler.GetBookmark(c)
 
@@ -1056,15 +1147,7 @@ func TestBookmarkHandler_ArchiveBookmark_Forbidden_DifferentUser(t *testing.T) {
 	mockService := new(MockBookmarkService)
 	handler := NewBookmarkHandler(mockService)
 
-	bookmarkOwnedByDifferentUser := model.Bookmark{
-		ID:         "bookmark123",
-		URL:        "https://example.com",
-		Title:      "Example Site",
-		UserID:     "user123", // Owned by different user
-		IsArchived: false,
-	}
-
-	mockService.On("GetBookmark", "bookmark123").Return(bookmarkOwnedByDifferentUser, nil)
+	mockService.On("ArchiveBookmark", "user456", "bookmark123", int64(0)).Return(model.Bookmark{}, fmt.Errorf("cannot modify bookmark: %w", model.ErrForbidden))
 
 	err := handler.ArchiveBookmark(c)
 
@@ -1141,7 +1224,7 @@ func BenchmarkBookmarkHandler_GetBookmark(b *testing.B) {
 		IsArchived: false,
 	}
 
-	mockService.On("GetBookmark", "bookmark123").Return(expectedBookmark, nil)
+	mockService.On("GetBookmark", "user123", "bookmark123").Return(expectedBookmark, nil)
 
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
@@ -1170,16 +1253,7 @@ func TestBookmarkHandler_DeleteBookmark_Success(t *testing.T) {
 	mockService := new(MockBookmarkService)
 	handler := NewBookmarkHandler(mockService)
 
-	existingBookmark := model.Bookmark{
-		ID:         "bookmark123",
-		URL:        "https://example.com",
-		Title:      "Example Site",
-		UserID:     "user123",
-		IsArchived: false,
-	}
-
-	mockService.On("GetBookmark", "bookmark123").Return(existingBookmark, nil)
-	mockService.On("DeleteBookmark", "bookmark123").Return(nil)
+	mockService.On("DeleteBookmark", "user123", "bookmark123", int64(0)).Return(nil)
 
 	err := handler.DeleteBookmark(c)
 
@@ -1264,15 +1338,7 @@ func TestBookmarkHandler_DeleteBookmark_Forbidden_DifferentUser(t *testing.T) {
 	mockService := new(MockBookmarkService)
 	handler := NewBookmarkHandler(mockService)
 
-	bookmarkOwnedByDifferentUser := model.Bookmark{
-		ID:         "bookmark123",
-		URL:        "https://example.com",
-		Title:      "Example Site",
-		UserID:     "user123", // Owned by different user
-		IsArchived: false,
-	}
-
-	mockService.On("GetBookmark", "bookmark123").Return(bookmarkOwnedByDifferentUser, nil)
+	mockService.On("DeleteBookmark", "user456", "bookmark123", int64(0)).Return(fmt.Errorf("cannot modify bookmark: %w", model.ErrForbidden))
 
 	err := handler.DeleteBookmark(c)
 
@@ -1299,7 +1365,7 @@ func TestBookmarkHandler_DeleteBookmark_BookmarkNotFound(t *testing.T) {
 	mockService := new(MockBookmarkService)
 	handler := NewBookmarkHandler(mockService)
 
-	mockService.On("GetBookmark", "nonexistent").Return(model.Bookmark{}, errors.New("bookmark not found"))
+	mockService.On("DeleteBookmark", "user123", "nonexistent", int64(0)).Return(errors.New("bookmark not found"))
 
 	err := handler.DeleteBookmark(c)
 
@@ -1321,16 +1387,7 @@ func TestBookmarkHandler_DeleteBookmark_ServiceError(t *testing.T) {
 	mockService := new(MockBookmarkService)
 	handler := NewBookmarkHandler(mockService)
 
-	existingBookmark := model.Bookmark{
-		ID:         "bookmark123",
-		URL:        "https://example.com",
-		Title:      "Example Site",
-		UserID:     "user123",
-		IsArchived: false,
-	}
-
-	mockService.On("GetBookmark", "bookmark123").Return(existingBookmark, nil)
-	mockService.On("DeleteBookmark", "bookmark123").Return(errors.New("database error"))
+	mockService.On("DeleteBookmark", "user123", "bookmark123", int64(0)).Return(errors.New("database error"))
 
 	err := handler.DeleteBookmark(c)
 
@@ -1352,16 +1409,7 @@ func TestBookmarkHandler_DeleteBookmark_ArchivedBookmark(t *testing.T) {
 	mockService := new(MockBookmarkService)
 	handler := NewBookmarkHandler(mockService)
 
-	archivedBookmark := model.Bookmark{
-		ID:         "bookmark123",
-		URL:        "https://example.com",
-		Title:      "Example Site",
-		UserID:     "user123",
-		IsArchived: true, // Archived bookmark
-	}
-
-	mockService.On("GetBookmark", "bookmark123").Return(archivedBookmark, nil)
-	mockService.On("DeleteBookmark", "bookmark123").Return(nil)
+	mockService.On("DeleteBookmark", "user123", "bookmark123", int64(0)).Return(nil)
 
 	err := handler.DeleteBookmark(c)
 
@@ -1393,3 +1441,518 @@ func TestBookmarkHandler_DeleteBookmark_NullUserContext(t *testing.T) {
 	assert.Equal(t, http.StatusUnauthorized, httpErr.Code)
 	assert.Equal(t, "User not authenticated", httpErr.Message)
 }
+
+func TestBookmarkHandler_GetBookmarks_HealthFilter(t *testing.T) {
+	c, rec := newCollectionContext(http.MethodGet, "/bookmarks?health=broken", "")
+
+	mockService := new(MockBookmarkService)
+	handler := NewBookmarkHandler(mockService)
+
+	checkedAt := time.Date(2025, 11, 15, 10, 30, 45, 0, time.UTC)
+	mockService.On("ListBookmarks", model.BookmarkQuery{UserID: "user123", Health: model.LinkHealthBroken}).
+		Return(model.BookmarkListResponse{Bookmarks: []model.Bookmark{{
+			ID:     "bookmark1",
+			URL:    "https://example.com/old",
+			UserID: "user123",
+			Health: model.LinkHealth{Status: model.LinkHealthBroken, StatusCode: http.StatusNotFound, FinalURL: "https://example.com/old", CheckedAt: checkedAt},
+		}}}, nil)
+
+	err := handler.GetBookmarks(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var responseTransports []transport.BookmarkTransport
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &responseTransports))
+	assert.Equal(t, 1, len(responseTransports))
+	assert.Equal(t, &transport.LinkHealthTransport{
+		Status:     model.LinkHealthBroken,
+		StatusCode: http.StatusNotFound,
+		FinalURL:   "https://example.com/old",
+		CheckedAt:  checkedAt,
+	}, responseTransports[0].LinkHealth)
+	mockService.AssertExpectations(t)
+}
+
+func TestBookmarkHandler_GetBookmarks_InvalidHealthFilter(t *testing.T) {
+	c, _ := newCollectionContext(http.MethodGet, "/bookmarks?health=dead", "")
+
+	mockService := new(MockBookmarkService)
+	handler := NewBookmarkHandler(mockService)
+
+	err := handler.GetBookmarks(c)
+
+	httpErr, ok := err.(*echo.HTTPError)
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusBadRequest, httpErr.Code)
+	mockService.AssertNotCalled(t, "ListBookmarks", mock.Anything)
+}
+
+func TestBookmarkHandler_GetBookmarks_Cursor(t *testing.T) {
+	c, rec := newCollectionContext(http.MethodGet, "/bookmarks?cursor=abc.def&page_size=2", "")
+
+	mockService := new(MockBookmarkService)
+	handler := NewBookmarkHandler(mockService)
+	mockService.On("ListBookmarks", model.BookmarkQuery{UserID: "user123", PageSize: 2, Cursor: "abc.def"}).
+		Return(model.BookmarkListResponse{
+			Bookmarks:  []model.Bookmark{{ID: "bookmark3", UserID: "user123"}, {ID: "bookmark2", UserID: "user123"}},
+			PageSize:   2,
+			NextCursor: "next.sig",
+			PrevCursor: "prev.sig",
+		}, nil)
+
+	err := handler.GetBookmarks(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var response struct {
+		Bookmarks  []transport.BookmarkTransport `json:"bookmarks"`
+		PageSize   int                           `json:"page_size"`
+		NextCursor string                        `json:"next_cursor"`
+		PrevCursor string                        `json:"prev_cursor"`
+		TotalCount *int                          `json:"total_count"`
+	}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, 2, len(response.Bookmarks))
+	assert.Equal(t, 2, response.PageSize)
+	assert.Equal(t, "next.sig", response.NextCursor)
+	assert.Equal(t, "prev.sig", response.PrevCursor)
+	assert.Nil(t, response.TotalCount)
+	mockService.AssertExpectations(t)
+}
+
+func TestBookmarkHandler_GetBookmarks_InvalidCursor(t *testing.T) {
+	c, _ := newCollectionContext(http.MethodGet, "/bookmarks?cursor=forged", "")
+
+	mockService := new(MockBookmarkService)
+	handler := NewBookmarkHandler(mockService)
+	mockService.On("ListBookmarks", model.BookmarkQuery{UserID: "user123", PageSize: 20, Cursor: "forged"}).
+		Return(model.BookmarkListResponse{}, fmt.Errorf("invalid cursor signature: %w", model.ErrInvalidInput))
+
+	err := handler.GetBookmarks(c)
+
+	httpErr, ok := err.(*echo.HTTPError)
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusBadRequest, httpErr.Code)
+	assert.Equal(t, "invalid cursor signature: invalid input", httpErr.Message)
+}
+
+func TestBookmarkHandler_GetBookmarks_SortAndFilters(t *testing.T) {
+	c, rec := newCollectionContext(http.MethodGet, "/bookmarks?sort=title&order=desc&domain=example.com&tag=go&has_summary=false&read=false&favorite=true&enrichment_status=failed&created_from=2025-01-01&created_to=2025-02-01T12:00:00Z", "")
+
+	mockService := new(MockBookmarkService)
+	handler := NewBookmarkHandler(mockService)
+	hasSummary, read, favorite := false, false, true
+	mockService.On("ListBookmarks", model.BookmarkQuery{
+		UserID:           "user123",
+		Domain:           "example.com",
+		Tag:              "go",
+		HasSummary:       &hasSummary,
+		Read:             &read,
+		Favorite:         &favorite,
+		EnrichmentStatus: model.EnrichmentStatusFailed,
+		CreatedFrom:      time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		CreatedTo:        time.Date(2025, 2, 1, 12, 0, 0, 0, time.UTC),
+		Sort:             model.BookmarkSortTitle,
+		Order:            model.SortDescending,
+	}).Return(model.BookmarkListResponse{Bookmarks: []model.Bookmark{{ID: "bookmark1", UserID: "user123", Tags: []string{"go"}}}}, nil)
+
+	err := handler.GetBookmarks(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var responseTransports []transport.BookmarkTransport
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &responseTransports))
+	assert.Equal(t, 1, len(responseTransports))
+	assert.Equal(t, []string{"go"}, responseTransports[0].Tags)
+	mockService.AssertExpectations(t)
+}
+
+func TestBookmarkHandler_GetBookmarks_InvalidSortAndFilters(t *testing.T) {
+	for _, target := range []string{
+		"/bookmarks?sort=popularity",
+		"/bookmarks?order=sideways",
+		"/bookmarks?has_summary=maybe",
+		"/bookmarks?read=sometimes",
+		"/bookmarks?favorite=2",
+		"/bookmarks?enrichment_status=pending",
+		"/bookmarks?created_from=yesterday",
+	} {
+		t.Run(target, func(t *testing.T) {
+			c, _ := newCollectionContext(http.MethodGet, target, "")
+			mockService := new(MockBookmarkService)
+			handler := NewBookmarkHandler(mockService)
+
+			err := handler.GetBookmarks(c)
+
+			httpErr, ok := err.(*echo.HTTPError)
+			assert.True(t, ok)
+			assert.Equal(t, http.StatusBadRequest, httpErr.Code)
+			mockService.AssertNotCalled(t, "ListBookmarks", mock.Anything)
+		})
+	}
+}
+
+func TestBookmarkHandler_GetBookmarks_RejectedCombination(t *testing.T) {
+	c, _ := newCollectionContext(http.MethodGet, "/bookmarks?tag=go&collection_id=c1&page=1", "")
+
+	mockService := new(MockBookmarkService)
+	handler := NewBookmarkHandler(mockService)
+	mockService.On("ListBookmarks", model.BookmarkQuery{UserID: "user123", CollectionID: "c1", Tag: "go", Page: 1, PageSize: 20}).
+		Return(model.BookmarkListResponse{}, fmt.Errorf("tag and collection filters cannot be combined: %w", model.ErrInvalidInput))
+
+	err := handler.GetBookmarks(c)
+
+	httpErr, ok := err.(*echo.HTTPError)
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusBadRequest, httpErr.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestBookmarkHandler_OpenBookmark(t *testing.T) {
+	c, rec := newCollectionContext(http.MethodPost, "/bookmarks/bookmark123/open", "")
+	c.SetParamNames("id")
+	c.SetParamValues("bookmark123")
+
+	mockService := new(MockBookmarkService)
+	handler := NewBookmarkHandler(mockService)
+	mockService.On("OpenBookmark", "user123", "bookmark123").Return(model.Bookmark{ID: "bookmark123", UserID: "user123", LastOpenedAt: time.Now()}, nil)
+
+	err := handler.OpenBookmark(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestBookmarkHandler_OpenBookmark_Forbidden(t *testing.T) {
+	c, _ := newCollectionContext(http.MethodPost, "/bookmarks/bookmark123/open", "")
+	c.SetParamNames("id")
+	c.SetParamValues("bookmark123")
+
+	mockService := new(MockBookmarkService)
+	handler := NewBookmarkHandler(mockService)
+	mockService.On("OpenBookmark", "user123", "bookmark123").Return(model.Bookmark{}, model.ErrForbidden)
+
+	err := handler.OpenBookmark(c)
+
+	httpErr, ok := err.(*echo.HTTPError)
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusForbidden, httpErr.Code)
+}
+
+func TestBookmarkHandler_UpdateReadingState(t *testing.T) {
+	c, rec := newCollectionContext(http.MethodPatch, "/bookmarks/bookmark123/state", `{"is_favorite": true, "reading_progress": 40}`)
+	c.SetParamNames("id")
+	c.SetParamValues("bookmark123")
+
+	mockService := new(MockBookmarkService)
+	handler := NewBookmarkHandler(mockService)
+	favorite, progress := true, 40
+	mockService.On("UpdateReadingState", "user123", "bookmark123", model.ReadingStateUpdate{IsFavorite: &favorite, ReadingProgress: &progress}, int64(0)).
+		Return(model.Bookmark{ID: "bookmark123", UserID: "user123", IsFavorite: true, ReadingProgress: 40, Metadata: model.PageMetadata{WordCount: 1000}}, nil)
+
+	err := handler.UpdateReadingState(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var response transport.BookmarkTransport
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.True(t, response.IsFavorite)
+	assert.False(t, response.IsRead)
+	assert.Nil(t, response.ReadAt)
+	assert.Equal(t, 40, response.ReadingProgress)
+	assert.Equal(t, 5, response.ReadingTime)
+	mockService.AssertExpectations(t)
+}
+
+func TestBookmarkHandler_UpdateReadingState_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		code int
+	}{
+		{"invalid", fmt.Errorf("reading_progress must be between 0 and 100: %w", model.ErrInvalidInput), http.StatusBadRequest},
+		{"forbidden", model.ErrForbidden, http.StatusForbidden},
+		{"not found", model.ErrNotFound, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := newCollectionContext(http.MethodPatch, "/bookmarks/bookmark123/state", `{"reading_progress": 120}`)
+			c.SetParamNames("id")
+			c.SetParamValues("bookmark123")
+
+			mockService := new(MockBookmarkService)
+			handler := NewBookmarkHandler(mockService)
+			mockService.On("UpdateReadingState", "user123", "bookmark123", mock.Anything, int64(0)).Return(model.Bookmark{}, tt.err)
+
+			err := handler.UpdateReadingState(c)
+
+			httpErr, ok := err.(*echo.HTTPError)
+			assert.True(t, ok)
+			assert.Equal(t, tt.code, httpErr.Code)
+		})
+	}
+}
+
+func TestBookmarkHandler_UpdateNotes(t *testing.T) {
+	c, rec := newCollectionContext(http.MethodPut, "/bookmarks/bookmark123/notes", `{"notes": "# Why I saved this"}`)
+	c.SetParamNames("id")
+	c.SetParamValues("bookmark123")
+
+	mockService := new(MockBookmarkService)
+	handler := NewBookmarkHandler(mockService)
+	mockService.On("UpdateNotes", "user123", "bookmark123", "# Why I saved this", int64(0)).
+		Return(model.Bookmark{ID: "bookmark123", UserID: "user123", Notes: "# Why I saved this"}, nil)
+
+	err := handler.UpdateNotes(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var response transport.BookmarkTransport
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, "# Why I saved this", response.Notes)
+	mockService.AssertExpectations(t)
+}
+
+func TestBookmarkHandler_UpdateNotes_TooLong(t *testing.T) {
+	c, _ := newCollectionContext(http.MethodPut, "/bookmarks/bookmark123/notes", `{"notes": "long"}`)
+	c.SetParamNames("id")
+	c.SetParamValues("bookmark123")
+
+	mockService := new(MockBookmarkService)
+	handler := NewBookmarkHandler(mockService)
+	mockService.On("UpdateNotes", "user123", "bookmark123", "long", int64(0)).
+		Return(model.Bookmark{}, fmt.Errorf("notes can be at most 20000 characters: %w", model.ErrInvalidInput))
+
+	err := handler.UpdateNotes(c)
+
+	httpErr, ok := err.(*echo.HTTPError)
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusBadRequest, httpErr.Code)
+}
+
+func TestBookmarkHandler_GetBookmark_ETag(t *testing.T) {
+	c, rec := newCollectionContext(http.MethodGet, "/bookmarks/bookmark123", "")
+	c.SetParamNames("id")
+	c.SetParamValues("bookmark123")
+
+	mockService := new(MockBookmarkService)
+	handler := NewBookmarkHandler(mockService)
+	mockService.On("GetBookmark", "user123", "bookmark123").Return(model.Bookmark{ID: "bookmark123", Version: 7}, nil)
+
+	err := handler.GetBookmark(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, `"7"`, rec.Header().Get("ETag"))
+	var response transport.BookmarkTransport
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, int64(7), response.Version)
+}
+
+func TestBookmarkHandler_UpdateNotes_IfMatch(t *testing.T) {
+	c, rec := newCollectionContext(http.MethodPut, "/bookmarks/bookmark123/notes", `{"notes": "Updated"}`)
+	c.Request().Header.Set("If-Match", `"7"`)
+	c.SetParamNames("id")
+	c.SetParamValues("bookmark123")
+
+	mockService := new(MockBookmarkService)
+	handler := NewBookmarkHandler(mockService)
+	mockService.On("UpdateNotes", "user123", "bookmark123", "Updated", int64(7)).
+		Return(model.Bookmark{ID: "bookmark123", Notes: "Updated", Version: 8}, nil)
+
+	err := handler.UpdateNotes(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, `"8"`, rec.Header().Get("ETag"))
+	mockService.AssertExpectations(t)
+}
+
+func TestBookmarkHandler_ArchiveBookmark_IfMatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		ifMatch string
+		err     error
+		code    int
+	}{
+		{"stale version", `"6"`, fmt.Errorf("bookmark is at version 7: %w", model.ErrPreconditionFailed), http.StatusPreconditionFailed},
+		{"concurrent writes", "*", fmt.Errorf("failed to update bookmark: %w", model.ErrConflict), http.StatusConflict},
+		{"weak etag", `W/"7"`, nil, http.StatusPreconditionFailed},
+		{"not a version", `"abc"`, nil, http.StatusPreconditionFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := newCollectionContext(http.MethodPost, "/bookmarks/bookmark123/archive", "")
+			c.Request().Header.Set("If-Match", tt.ifMatch)
+			c.SetParamNames("id")
+			c.SetParamValues("bookmark123")
+
+			mockService := new(MockBookmarkService)
+			handler := NewBookmarkHandler(mockService)
+			mockService.On("ArchiveBookmark", "user123", "bookmark123", mock.Anything).Return(model.Bookmark{}, tt.err)
+
+			err := handler.ArchiveBookmark(c)
+
+			httpErr, ok := err.(*echo.HTTPError)
+			assert.True(t, ok)
+			assert.Equal(t, tt.code, httpErr.Code)
+			if tt.err == nil {
+				mockService.AssertNotCalled(t, "ArchiveBookmark", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestBookmarkHandler_DeleteBookmark_IfMatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		ifMatch string
+		err     error
+		code    int
+	}{
+		{"matching version", `"7"`, nil, http.StatusNoContent},
+		{"stale version", `"6"`, fmt.Errorf("bookmark is at version 7: %w", model.ErrPreconditionFailed), http.StatusPreconditionFailed},
+		{"not a version", `"abc"`, nil, http.StatusPreconditionFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, rec := newCollectionContext(http.MethodDelete, "/bookmarks/bookmark123", "")
+			c.Request().Header.Set("If-Match", tt.ifMatch)
+			c.SetParamNames("id")
+			c.SetParamValues("bookmark123")
+
+			mockService := new(MockBookmarkService)
+			handler := NewBookmarkHandler(mockService)
+			mockService.On("DeleteBookmark", "user123", "bookmark123", mock.Anything).Return(tt.err)
+
+			err := handler.DeleteBookmark(c)
+
+			if tt.code == http.StatusNoContent {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.code, rec.Code)
+				mockService.AssertCalled(t, "DeleteBookmark", "user123", "bookmark123", int64(7))
+				return
+			}
+			httpErr, ok := err.(*echo.HTTPError)
+			assert.True(t, ok)
+			assert.Equal(t, tt.code, httpErr.Code)
+			if tt.err == nil {
+				mockService.AssertNotCalled(t, "DeleteBookmark", mock.Anything, mock.Anything, mock.Anything)
+			}
+		})
+	}
+}
+
+func TestBookmarkHandler_RefreshBookmark(t *testing.T) {
+	c, rec := newCollectionContext(http.MethodPost, "/bookmarks/bookmark123/refresh", "")
+	c.SetParamNames("id")
+	c.SetParamValues("bookmark123")
+
+	mockService := new(MockBookmarkService)
+	handler := NewBookmarkHandler(mockService)
+	mockService.On("RefreshBookmark", "user123", "bookmark123").Return(model.Bookmark{
+		ID:             "bookmark123",
+		URL:            "https://example.com",
+		Title:          "Example Site",
+		ContentSummary: "A summary written after the upgrade",
+		UserID:         "user123",
+	}, nil)
+
+	err := handler.RefreshBookmark(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var responseTransport transport.BookmarkTransport
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &responseTransport))
+	assert.Equal(t, "Example Site", responseTransport.Title)
+	assert.Equal(t, "A summary written after the upgrade", responseTransport.ContentSummary)
+	mockService.AssertExpectations(t)
+}
+
+func TestBookmarkHandler_RefreshBookmark_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+	}{
+		{"not found", fmt.Errorf("failed to get bookmark: %w", model.ErrNotFound), http.StatusNotFound},
+		{"not owner", fmt.Errorf("cannot modify: %w", model.ErrForbidden), http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := newCollectionContext(http.MethodPost, "/bookmarks/bookmark123/refresh", "")
+			c.SetParamNames("id")
+			c.SetParamValues("bookmark123")
+
+			mockService := new(MockBookmarkService)
+			handler := NewBookmarkHandler(mockService)
+			mockService.On("RefreshBookmark", "user123", "bookmark123").Return(model.Bookmark{}, tt.err)
+
+			err := handler.RefreshBookmark(c)
+
+			httpErr, ok := err.(*echo.HTTPError)
+			assert.True(t, ok)
+			assert.Equal(t, tt.wantStatus, httpErr.Code)
+		})
+	}
+}
+
+func TestBookmarkHandler_RefreshBookmarks(t *testing.T) {
+	c, rec := newCollectionContext(http.MethodPost, "/bookmarks/refresh", `{"bookmark_ids":["b1","b2","b3"]}`)
+
+	mockService := new(MockBookmarkService)
+	handler := NewBookmarkHandler(mockService)
+	mockService.On("RefreshBookmarks", "user123", []string{"b1", "b2", "b3"}).
+		Return(model.BulkRefresh{Queued: []string{"b1", "b3"}, NotFound: []string{"b2"}}, nil)
+
+	err := handler.RefreshBookmarks(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+	assert.JSONEq(t, `{"queued":["b1","b3"],"not_found":["b2"]}`, rec.Body.String())
+	mockService.AssertExpectations(t)
+}
+
+func TestBookmarkHandler_RefreshBookmarks_InvalidInput(t *testing.T) {
+	c, rec := newCollectionContext(http.MethodPost, "/bookmarks/refresh", `{"bookmark_ids":[]}`)
+
+	mockService := new(MockBookmarkService)
+	handler := NewBookmarkHandler(mockService)
+	mockService.On("RefreshBookmarks", "user123", []string{}).
+		Return(model.BulkRefresh{}, fmt.Errorf("between 1 and 100 bookmark IDs are required: %w", model.ErrInvalidInput))
+
+	err := handler.RefreshBookmarks(c)
+
+	httpErr, ok := err.(*echo.HTTPError)
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusBadRequest, httpErr.Code)
+	assert.Equal(t, http.StatusOK, rec.Code) // Nothing was written
+}
+
+func TestBookmarkHandler_ListEnrichments(t *testing.T) {
+	c, rec := newCollectionContext(http.MethodGet, "/bookmarks/bookmark123/enrichments", "")
+	c.SetParamNames("id")
+	c.SetParamValues("bookmark123")
+
+	mockService := new(MockBookmarkService)
+	handler := NewBookmarkHandler(mockService)
+	at := time.Date(2025, 11, 15, 10, 30, 45, 0, time.UTC)
+	mockService.On("GetBookmark", "user123", "bookmark123").Return(model.Bookmark{
+		ID:     "bookmark123",
+		UserID: "user123",
+		Enrichments: []model.Enrichment{
+			{At: at, Trigger: model.EnrichmentTriggerCreate, Summary: model.EnrichmentSummaryNotAvailable, Failed: []string{"title"}},
+			{At: at.Add(time.Hour), Trigger: model.EnrichmentTriggerRefresh, Summary: model.EnrichmentSummaryGenerated, SummaryProvider: "anthropic", Updated: []string{"title", "summary"}},
+		},
+	}, nil)
+
+	err := handler.ListEnrichments(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `[
+		{"at":"2025-11-15T10:30:45Z","trigger":"create","summary":"not_available","updated":[],"failed":["title"]},
+		{"at":"2025-11-15T11:30:45Z","trigger":"refresh","summary":"generated","summary_provider":"anthropic","updated":["title","summary"]}
+	]`, rec.Body.String())
+	mockService.AssertExpectations(t)
+}
@@ -0,0 +1,290 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/tsongpon/athena/internal/logger"
+	"github.com/tsongpon/athena/internal/model"
+	"github.com/tsongpon/athena/internal/transport"
+	"go.uber.org/zap"
+)
+
+type CollectionHandler struct {
+	collectionService CollectionService
+}
+
+func NewCollectionHandler(collectionService CollectionService) *CollectionHandler {
+	return &CollectionHandler{
+		collectionService: collectionService,
+	}
+}
+
+// CreateCollection creates a collection for the authenticated user
+func (h *CollectionHandler) CreateCollection(c echo.Context) error {
+	req := &transport.CollectionRequest{}
+	if err := c.Bind(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	authenticatedUser, err := getAuthenticatedUser(c)
+	if err != nil {
+		return err
+	}
+
+	created, err := h.collectionService.CreateCollection(model.Collection{
+		UserID:      authenticatedUser.UserID,
+		ParentID:    req.ParentID,
+		Name:        req.Name,
+		Description: req.Description,
+		Position:    req.Position,
+	})
+	if err != nil {
+		return collectionError(err)
+	}
+	return c.JSON(http.StatusCreated, toCollectionResponse(created, authenticatedUser.UserID))
+}
+
+// ListCollections lists every collection of the authenticated user
+func (h *CollectionHandler) ListCollections(c echo.Context) error {
+	authenticatedUser, err := getAuthenticatedUser(c)
+	if err != nil {
+		return err
+	}
+
+	collections, err := h.collectionService.ListCollections(authenticatedUser.UserID)
+	if err != nil {
+		return collectionError(err)
+	}
+
+	resp := make([]transport.CollectionResponse, len(collections))
+	for i, collection := range collections {
+		resp[i] = toCollectionResponse(collection, authenticatedUser.UserID)
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// ListSharedCollections lists collections other users have shared with the authenticated user
+func (h *CollectionHandler) ListSharedCollections(c echo.Context) error {
+	authenticatedUser, err := getAuthenticatedUser(c)
+	if err != nil {
+		return err
+	}
+
+	collections, err := h.collectionService.ListSharedCollections(authenticatedUser.UserID)
+	if err != nil {
+		return collectionError(err)
+	}
+
+	resp := make([]transport.CollectionResponse, len(collections))
+	for i, collection := range collections {
+		resp[i] = toCollectionResponse(collection, authenticatedUser.UserID)
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// ListActivity returns who changed what in a collection, newest first. Accepts ?limit= (default 50, max 100).
+func (h *CollectionHandler) ListActivity(c echo.Context) error {
+	authenticatedUser, err := getAuthenticatedUser(c)
+	if err != nil {
+		return err
+	}
+	limit, err := strconv.Atoi(c.QueryParam("limit"))
+	if err != nil {
+		limit = 0
+	}
+
+	activities, err := h.collectionService.ListActivity(authenticatedUser.UserID, c.Param("id"), limit)
+	if err != nil {
+		return collectionError(err)
+	}
+
+	resp := make([]transport.ActivityResponse, len(activities))
+	for i, a := range activities {
+		resp[i] = transport.ActivityResponse{
+			ID:           a.ID,
+			ActorID:      a.ActorID,
+			Action:       a.Action,
+			BookmarkID:   a.BookmarkID,
+			TargetUserID: a.TargetUserID,
+			CreatedAt:    a.CreatedAt,
+		}
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// GetCollection returns a single collection
+func (h *CollectionHandler) GetCollection(c echo.Context) error {
+	authenticatedUser, err := getAuthenticatedUser(c)
+	if err != nil {
+		return err
+	}
+
+	collection, err := h.collectionService.GetCollection(authenticatedUser.UserID, c.Param("id"))
+	if err != nil {
+		return collectionError(err)
+	}
+	return c.JSON(http.StatusOK, toCollectionResponse(collection, authenticatedUser.UserID))
+}
+
+// UpdateCollection renames, describes, moves or repositions a collection
+func (h *CollectionHandler) UpdateCollection(c echo.Context) error {
+	req := &transport.CollectionRequest{}
+	if err := c.Bind(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	authenticatedUser, err := getAuthenticatedUser(c)
+	if err != nil {
+		return err
+	}
+
+	updated, err := h.collectionService.UpdateCollection(authenticatedUser.UserID, model.Collection{
+		ID:          c.Param("id"),
+		ParentID:    req.ParentID,
+		Name:        req.Name,
+		Description: req.Description,
+		Position:    req.Position,
+	})
+	if err != nil {
+		return collectionError(err)
+	}
+	return c.JSON(http.StatusOK, toCollectionResponse(updated, authenticatedUser.UserID))
+}
+
+// DeleteCollection deletes a collection and its nested collections. Bookmarks are kept.
+func (h *CollectionHandler) DeleteCollection(c echo.Context) error {
+	authenticatedUser, err := getAuthenticatedUser(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.collectionService.DeleteCollection(authenticatedUser.UserID, c.Param("id")); err != nil {
+		return collectionError(err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// ListCollectionBookmarks returns the bookmarks of a collection in their manual order with who added them
+func (h *CollectionHandler) ListCollectionBookmarks(c echo.Context) error {
+	authenticatedUser, err := getAuthenticatedUser(c)
+	if err != nil {
+		return err
+	}
+
+	bookmarks, err := h.collectionService.ListCollectionBookmarks(authenticatedUser.UserID, c.Param("id"))
+	if err != nil {
+		return collectionError(err)
+	}
+
+	ts := make([]transport.CollectionBookmarkResponse, len(bookmarks))
+	for i, b := range bookmarks {
+		ts[i] = transport.CollectionBookmarkResponse{
+			BookmarkTransport: toBookmarkTransport(b.Bookmark),
+			AddedBy:           b.AddedBy,
+		}
+	}
+	return c.JSON(http.StatusOK, ts)
+}
+
+// AddBookmark adds a bookmark to a collection, appending it unless a position is given
+func (h *CollectionHandler) AddBookmark(c echo.Context) error {
+	req := &transport.AddCollectionBookmarkRequest{}
+	if err := c.Bind(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+	if req.BookmarkID == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "bookmark_id is required")
+	}
+	position := -1
+	if req.Position != nil {
+		position = *req.Position
+	}
+
+	authenticatedUser, err := getAuthenticatedUser(c)
+	if err != nil {
+		return err
+	}
+
+	updated, err := h.collectionService.AddBookmark(authenticatedUser.UserID, c.Param("id"), req.BookmarkID, position)
+	if err != nil {
+		return collectionError(err)
+	}
+	return c.JSON(http.StatusOK, toCollectionResponse(updated, authenticatedUser.UserID))
+}
+
+// RemoveBookmark removes a bookmark from a collection without deleting it
+func (h *CollectionHandler) RemoveBookmark(c echo.Context) error {
+	authenticatedUser, err := getAuthenticatedUser(c)
+	if err != nil {
+		return err
+	}
+
+	if _, err := h.collectionService.RemoveBookmark(authenticatedUser.UserID, c.Param("id"), c.Param("bookmark_id")); err != nil {
+		return collectionError(err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// ReorderBookmarks sets the manual order of the bookmarks in a collection
+func (h *CollectionHandler) ReorderBookmarks(c echo.Context) error {
+	req := &transport.ReorderCollectionRequest{}
+	if err := c.Bind(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	authenticatedUser, err := getAuthenticatedUser(c)
+	if err != nil {
+		return err
+	}
+
+	updated, err := h.collectionService.ReorderBookmarks(authenticatedUser.UserID, c.Param("id"), req.BookmarkIDs)
+	if err != nil {
+		return collectionError(err)
+	}
+	return c.JSON(http.StatusOK, toCollectionResponse(updated, authenticatedUser.UserID))
+}
+
+// collectionError maps service errors to HTTP errors
+func collectionError(err error) error {
+	switch {
+	case errors.Is(err, model.ErrInvalidInput):
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	case errors.Is(err, model.ErrForbidden):
+		return echo.NewHTTPError(http.StatusForbidden, "Access denied")
+	case errors.Is(err, model.ErrNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, err.Error())
+	case errors.Is(err, model.ErrConflict):
+		return echo.NewHTTPError(http.StatusConflict, err.Error())
+	default:
+		logger.Error("Collection operation failed", zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+}
+
+// toCollectionResponse converts a collection, reporting the role userID holds on it
+func toCollectionResponse(collection model.Collection, userID string) transport.CollectionResponse {
+	bookmarkIDs := collection.BookmarkIDs
+	if bookmarkIDs == nil {
+		bookmarkIDs = []string{}
+	}
+	collaborators := make([]transport.CollaboratorResponse, len(collection.Collaborators))
+	for i, c := range collection.Collaborators {
+		collaborators[i] = transport.CollaboratorResponse{UserID: c.UserID, Role: c.Role, AddedAt: c.AddedAt}
+	}
+	return transport.CollectionResponse{
+		ID:            collection.ID,
+		UserID:        collection.UserID,
+		ParentID:      collection.ParentID,
+		Name:          collection.Name,
+		Description:   collection.Description,
+		Position:      collection.Position,
+		Role:          collection.RoleOf(userID),
+		BookmarkIDs:   bookmarkIDs,
+		Collaborators: collaborators,
+		CreatedAt:     collection.CreatedAt,
+		UpdatedAt:     collection.UpdatedAt,
+	}
+}
@@ -0,0 +1,299 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/tsongpon/athena/internal/model"
+	"github.com/tsongpon/athena/internal/transport"
+)
+
+// MockCollectionService is a mock implementation of CollectionService
+type MockCollectionService struct {
+	mock.Mock
+}
+
+func (m *MockCollectionService) CreateCollection(c model.Collection) (model.Collection, error) {
+	args := m.Called(c)
+	return args.Get(0).(model.Collection), args.Error(1)
+}
+
+func (m *MockCollectionService) GetCollection(userID, id string) (model.Collection, error) {
+	args := m.Called(userID, id)
+	return args.Get(0).(model.Collection), args.Error(1)
+}
+
+func (m *MockCollectionService) ListCollections(userID string) ([]model.Collection, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]model.Collection), args.Error(1)
+}
+
+func (m *MockCollectionService) UpdateCollection(userID string, update model.Collection) (model.Collection, error) {
+	args := m.Called(userID, update)
+	return args.Get(0).(model.Collection), args.Error(1)
+}
+
+func (m *MockCollectionService) DeleteCollection(userID, id string) error {
+	args := m.Called(userID, id)
+	return args.Error(0)
+}
+
+func (m *MockCollectionService) AddBookmark(userID, collectionID, bookmarkID string, position int) (model.Collection, error) {
+	args := m.Called(userID, collectionID, bookmarkID, position)
+	return args.Get(0).(model.Collection), args.Error(1)
+}
+
+func (m *MockCollectionService) RemoveBookmark(userID, collectionID, bookmarkID string) (model.Collection, error) {
+	args := m.Called(userID, collectionID, bookmarkID)
+	return args.Get(0).(model.Collection), args.Error(1)
+}
+
+func (m *MockCollectionService) ReorderBookmarks(userID, collectionID string, bookmarkIDs []string) (model.Collection, error) {
+	args := m.Called(userID, collectionID, bookmarkIDs)
+	return args.Get(0).(model.Collection), args.Error(1)
+}
+
+func (m *MockCollectionService) ListCollectionBookmarks(userID, collectionID string) ([]model.CollectionBookmark, error) {
+	args := m.Called(userID, collectionID)
+	return args.Get(0).([]model.CollectionBookmark), args.Error(1)
+}
+
+func (m *MockCollectionService) ListSharedCollections(userID string) ([]model.Collection, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]model.Collection), args.Error(1)
+}
+
+func (m *MockCollectionService) ListActivity(userID, collectionID string, limit int) ([]model.Activity, error) {
+	args := m.Called(userID, collectionID, limit)
+	return args.Get(0).([]model.Activity), args.Error(1)
+}
+
+func newCollectionContext(method, target, body string) (echo.Context, *httptest.ResponseRecorder) {
+	e := echo.New()
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.Set("user", &JWTClaims{UserID: "user123", Email: "test@example.com", Name: "Test User"})
+	return c, rec
+}
+
+func TestCollectionHandler_CreateCollection(t *testing.T) {
+	c, rec := newCollectionContext(http.MethodPost, "/collections", `{"name":"Reading","parent_id":"parent-1"}`)
+
+	mockService := new(MockCollectionService)
+	handler := NewCollectionHandler(mockService)
+
+	mockService.On("CreateCollection", model.Collection{UserID: "user123", Name: "Reading", ParentID: "parent-1"}).
+		Return(model.Collection{ID: "c1", UserID: "user123", Name: "Reading", ParentID: "parent-1"}, nil)
+
+	err := handler.CreateCollection(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+
+	var response transport.CollectionResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, "c1", response.ID)
+	assert.Equal(t, "parent-1", response.ParentID)
+	assert.Equal(t, []string{}, response.BookmarkIDs)
+	mockService.AssertExpectations(t)
+}
+
+func TestCollectionHandler_GetCollection_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		code int
+	}{
+		{name: "forbidden", err: fmt.Errorf("other user: %w", model.ErrForbidden), code: http.StatusForbidden},
+		{name: "not found", err: fmt.Errorf("collection c1 %w", model.ErrNotFound), code: http.StatusNotFound},
+		{name: "invalid", err: fmt.Errorf("id is required: %w", model.ErrInvalidInput), code: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := newCollectionContext(http.MethodGet, "/collections/c1", "")
+			c.SetParamNames("id")
+			c.SetParamValues("c1")
+
+			mockService := new(MockCollectionService)
+			handler := NewCollectionHandler(mockService)
+			mockService.On("GetCollection", "user123", "c1").Return(model.Collection{}, tt.err)
+
+			err := handler.GetCollection(c)
+
+			httpErr, ok := err.(*echo.HTTPError)
+			assert.True(t, ok)
+			assert.Equal(t, tt.code, httpErr.Code)
+		})
+	}
+}
+
+func TestCollectionHandler_AddBookmark(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		position int
+	}{
+		{name: "append when position is missing", body: `{"bookmark_id":"b1"}`, position: -1},
+		{name: "explicit position", body: `{"bookmark_id":"b1","position":0}`, position: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, rec := newCollectionContext(http.MethodPost, "/collections/c1/bookmarks", tt.body)
+			c.SetParamNames("id")
+			c.SetParamValues("c1")
+
+			mockService := new(MockCollectionService)
+			handler := NewCollectionHandler(mockService)
+			mockService.On("AddBookmark", "user123", "c1", "b1", tt.position).
+				Return(model.Collection{ID: "c1", BookmarkIDs: []string{"b1"}}, nil)
+
+			err := handler.AddBookmark(c)
+
+			assert.NoError(t, err)
+			assert.Equal(t, http.StatusOK, rec.Code)
+			mockService.AssertExpectations(t)
+		})
+	}
+}
+
+func TestCollectionHandler_AddBookmark_MissingBookmarkID(t *testing.T) {
+	c, _ := newCollectionContext(http.MethodPost, "/collections/c1/bookmarks", `{}`)
+	c.SetParamNames("id")
+	c.SetParamValues("c1")
+
+	handler := NewCollectionHandler(new(MockCollectionService))
+	err := handler.AddBookmark(c)
+
+	httpErr, ok := err.(*echo.HTTPError)
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusBadRequest, httpErr.Code)
+}
+
+func TestCollectionHandler_ReorderBookmarks(t *testing.T) {
+	c, rec := newCollectionContext(http.MethodPut, "/collections/c1/bookmarks/order", `{"bookmark_ids":["b2","b1"]}`)
+	c.SetParamNames("id")
+	c.SetParamValues("c1")
+
+	mockService := new(MockCollectionService)
+	handler := NewCollectionHandler(mockService)
+	mockService.On("ReorderBookmarks", "user123", "c1", []string{"b2", "b1"}).
+		Return(model.Collection{ID: "c1", BookmarkIDs: []string{"b2", "b1"}}, nil)
+
+	err := handler.ReorderBookmarks(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var response transport.CollectionResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, []string{"b2", "b1"}, response.BookmarkIDs)
+}
+
+func TestCollectionHandler_ListCollectionBookmarks(t *testing.T) {
+	c, rec := newCollectionContext(http.MethodGet, "/collections/c1/bookmarks", "")
+	c.SetParamNames("id")
+	c.SetParamValues("c1")
+
+	mockService := new(MockCollectionService)
+	handler := NewCollectionHandler(mockService)
+	mockService.On("ListCollectionBookmarks", "user123", "c1").Return([]model.CollectionBookmark{
+		{Bookmark: model.Bookmark{ID: "b2", UserID: "user123", CollectionIDs: []string{"c1"}}, AddedBy: "user456"},
+		{Bookmark: model.Bookmark{ID: "b1", UserID: "user123", CollectionIDs: []string{"c1"}}, AddedBy: "user123"},
+	}, nil)
+
+	err := handler.ListCollectionBookmarks(c)
+
+	assert.NoError(t, err)
+	var response []transport.CollectionBookmarkResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Len(t, response, 2)
+	assert.Equal(t, "b2", response[0].ID)
+	assert.Equal(t, []string{"c1"}, response[0].CollectionIDs)
+	assert.Equal(t, "user456", response[0].AddedBy)
+}
+
+func TestCollectionHandler_GetCollection_ReportsCallerRole(t *testing.T) {
+	c, rec := newCollectionContext(http.MethodGet, "/collections/c1", "")
+	c.SetParamNames("id")
+	c.SetParamValues("c1")
+
+	mockService := new(MockCollectionService)
+	handler := NewCollectionHandler(mockService)
+	mockService.On("GetCollection", "user123", "c1").Return(model.Collection{
+		ID:            "c1",
+		UserID:        "owner-1",
+		Name:          "Team reading",
+		Collaborators: []model.Collaborator{{UserID: "user123", Role: model.CollectionRoleEditor}},
+	}, nil)
+
+	err := handler.GetCollection(c)
+
+	assert.NoError(t, err)
+	var response transport.CollectionResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, "owner-1", response.UserID)
+	assert.Equal(t, model.CollectionRoleEditor, response.Role)
+	assert.Len(t, response.Collaborators, 1)
+}
+
+func TestCollectionHandler_ListActivity(t *testing.T) {
+	c, rec := newCollectionContext(http.MethodGet, "/collections/c1/activity?limit=10", "")
+	c.SetParamNames("id")
+	c.SetParamValues("c1")
+
+	mockService := new(MockCollectionService)
+	handler := NewCollectionHandler(mockService)
+	mockService.On("ListActivity", "user123", "c1", 10).Return([]model.Activity{
+		{ID: "a1", CollectionID: "c1", ActorID: "user456", Action: model.ActivityBookmarkAdded, BookmarkID: "b1"},
+	}, nil)
+
+	err := handler.ListActivity(c)
+
+	assert.NoError(t, err)
+	var response []transport.ActivityResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Len(t, response, 1)
+	assert.Equal(t, "user456", response[0].ActorID)
+	assert.Equal(t, model.ActivityBookmarkAdded, response[0].Action)
+}
+
+func TestCollectionHandler_DeleteCollection(t *testing.T) {
+	c, rec := newCollectionContext(http.MethodDelete, "/collections/c1", "")
+	c.SetParamNames("id")
+	c.SetParamValues("c1")
+
+	mockService := new(MockCollectionService)
+	handler := NewCollectionHandler(mockService)
+	mockService.On("DeleteCollection", "user123", "c1").Return(nil)
+
+	err := handler.DeleteCollection(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+}
+
+func TestBookmarkHandler_GetBookmarks_CollectionFilter(t *testing.T) {
+	c, rec := newCollectionContext(http.MethodGet, "/bookmarks?collection_id=c1", "")
+
+	mockService := new(MockBookmarkService)
+	handler := NewBookmarkHandler(mockService)
+	mockService.On("ListBookmarks", model.BookmarkQuery{UserID: "user123", CollectionID: "c1"}).
+		Return(model.BookmarkListResponse{Bookmarks: []model.Bookmark{{ID: "b1", CollectionIDs: []string{"c1"}}}}, nil)
+
+	err := handler.GetBookmarks(c)
+
+	assert.NoError(t, err)
+	var response []transport.BookmarkTransport
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Len(t, response, 1)
+	mockService.AssertExpectations(t)
+}
@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/tsongpon/athena/internal/logger"
+	"github.com/tsongpon/athena/internal/model"
+	"github.com/tsongpon/athena/internal/transport"
+	"go.uber.org/zap"
+)
+
+// eventHeartbeat is how often an idle stream sends a comment, so proxies do not close it
+const eventHeartbeat = 25 * time.Second
+
+// eventReset is sent instead of the missed events when they are no longer kept; the client
+// reloads its bookmarks
+const eventReset = "reset"
+
+type EventHandler struct {
+	eventService EventService
+}
+
+func NewEventHandler(eventService EventService) *EventHandler {
+	return &EventHandler{
+		eventService: eventService,
+	}
+}
+
+// Stream sends the bookmark changes of the user as Server-Sent Events until the client
+// disconnects. A reconnecting client passes the ID of the last event it got in the
+// Last-Event-ID header, or the last_event_id query parameter, to get the events it missed.
+func (h *EventHandler) Stream(c echo.Context) error {
+	authenticatedUser, err := getAuthenticatedUser(c)
+	if err != nil {
+		return err
+	}
+	lastEventID := c.Request().Header.Get("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = c.QueryParam("last_event_id")
+	}
+
+	sub, err := h.eventService.Subscribe(authenticatedUser.UserID, lastEventID)
+	if err != nil {
+		logger.Error("Failed to subscribe to events", zap.String("user_id", authenticatedUser.UserID), zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to open event stream")
+	}
+	defer sub.Close()
+
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, "text/event-stream")
+	res.Header().Set(echo.HeaderCacheControl, "no-cache")
+	res.Header().Set(echo.HeaderConnection, "keep-alive")
+	res.Header().Set("X-Accel-Buffering", "no") // Disables response buffering in nginx
+	res.WriteHeader(http.StatusOK)
+
+	if sub.Reset {
+		fmt.Fprintf(res, "event: %s\ndata: {}\n\n", eventReset)
+	}
+	for _, event := range sub.Replay {
+		if err := writeEvent(res, event); err != nil {
+			return nil
+		}
+	}
+	// An initial comment flushes the headers, so the client sees the stream open
+	fmt.Fprint(res, ": connected\n\n")
+	res.Flush()
+
+	heartbeat := time.NewTicker(eventHeartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-c.Request().Context().Done():
+			return nil
+		case event, ok := <-sub.Events:
+			if !ok {
+				// Dropped as too slow; the client reconnects with Last-Event-ID
+				return nil
+			}
+			if err := writeEvent(res, event); err != nil {
+				return nil
+			}
+			res.Flush()
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(res, ": heartbeat\n\n"); err != nil {
+				return nil
+			}
+			res.Flush()
+		}
+	}
+}
+
+// writeEvent writes event in the Server-Sent Events format
+func writeEvent(res *echo.Response, event model.Event) error {
+	data := transport.EventTransport{
+		ID:         event.ID,
+		Type:       event.Type,
+		BookmarkID: event.Bookmark.ID,
+		At:         event.At,
+	}
+	if event.Type != model.EventBookmarkDeleted {
+		bookmark := toBookmarkTransport(event.Bookmark.VisibleTo(event.UserID))
+		data.Bookmark = &bookmark
+	}
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(res, "id: %s\nevent: %s\ndata: %s\n\n", event.ID, event.Type, payload)
+	return err
+}
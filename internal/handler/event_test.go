@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/tsongpon/athena/internal/model"
+)
+
+// MockEventService is a mock implementation of EventService
+type MockEventService struct {
+	mock.Mock
+}
+
+func (m *MockEventService) Subscribe(userID, lastEventID string) (model.EventSubscription, error) {
+	args := m.Called(userID, lastEventID)
+	return args.Get(0).(model.EventSubscription), args.Error(1)
+}
+
+// closedSubscription returns a subscription that replays events and then ends
+func closedSubscription(reset bool, replay ...model.Event) model.EventSubscription {
+	events := make(chan model.Event)
+	close(events)
+	return model.EventSubscription{Replay: replay, Reset: reset, Events: events, Close: func() {}}
+}
+
+func TestEventHandler_Stream(t *testing.T) {
+	c, rec := newCollectionContext(http.MethodGet, "/events", "")
+	c.Request().Header.Set("Last-Event-ID", "e0")
+
+	mockService := new(MockEventService)
+	handler := NewEventHandler(mockService)
+	at := time.Date(2025, 11, 15, 10, 30, 0, 0, time.UTC)
+	mockService.On("Subscribe", "user123", "e0").Return(closedSubscription(false,
+		model.Event{ID: "e1", UserID: "user123", Type: model.EventBookmarkEnriched, At: at,
+			Bookmark: model.Bookmark{ID: "b1", UserID: "user123", Title: "Go", Notes: "mine"}},
+		model.Event{ID: "e2", UserID: "user123", Type: model.EventBookmarkDeleted, At: at,
+			Bookmark: model.Bookmark{ID: "b2", UserID: "user123"}},
+	), nil)
+
+	err := handler.Stream(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "text/event-stream", rec.Header().Get("Content-Type"))
+	body := rec.Body.String()
+	assert.NotContains(t, body, "event: reset")
+	assert.Contains(t, body, "id: e1\nevent: bookmark.enriched\ndata: {\"id\":\"e1\",\"type\":\"bookmark.enriched\",\"bookmark_id\":\"b1\",\"bookmark\":{")
+	assert.Contains(t, body, `"notes":"mine"`)
+	assert.Contains(t, body, "id: e2\nevent: bookmark.deleted\ndata: {\"id\":\"e2\",\"type\":\"bookmark.deleted\",\"bookmark_id\":\"b2\",\"at\":\"2025-11-15T10:30:00Z\"}\n\n")
+	assert.Less(t, strings.Index(body, "id: e1"), strings.Index(body, "id: e2"))
+	mockService.AssertExpectations(t)
+}
+
+func TestEventHandler_Stream_Reset(t *testing.T) {
+	c, rec := newCollectionContext(http.MethodGet, "/events?last_event_id=old", "")
+
+	mockService := new(MockEventService)
+	handler := NewEventHandler(mockService)
+	mockService.On("Subscribe", "user123", "old").Return(closedSubscription(true), nil)
+
+	err := handler.Stream(c)
+
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(rec.Body.String(), "event: reset\ndata: {}\n\n"))
+	mockService.AssertExpectations(t)
+}
@@ -0,0 +1,277 @@
+package handler
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/tsongpon/athena/internal/logger"
+	"github.com/tsongpon/athena/internal/model"
+	"github.com/tsongpon/athena/internal/transport"
+	"go.uber.org/zap"
+)
+
+// Feed formats, selected by the extension of the feed URL
+const (
+	feedFormatAtom = ".atom"
+	feedFormatRSS  = ".rss"
+)
+
+type FeedHandler struct {
+	feedService FeedService
+	baseURL     string
+}
+
+// NewFeedHandler creates a feed handler. baseURL prefixes the feed URLs handed to users;
+// when empty it is derived from the incoming request.
+func NewFeedHandler(feedService FeedService, baseURL string) *FeedHandler {
+	return &FeedHandler{
+		feedService: feedService,
+		baseURL:     strings.TrimSuffix(baseURL, "/"),
+	}
+}
+
+// CreateFeedToken issues a feed token for the authenticated user's bookmarks or one collection
+func (h *FeedHandler) CreateFeedToken(c echo.Context) error {
+	req := &transport.CreateFeedTokenRequest{}
+	if err := c.Bind(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	authenticatedUser, err := getAuthenticatedUser(c)
+	if err != nil {
+		return err
+	}
+
+	feedToken, err := h.feedService.CreateFeedToken(authenticatedUser.UserID, req.CollectionID)
+	if err != nil {
+		return collectionError(err)
+	}
+	return c.JSON(http.StatusCreated, h.toFeedTokenResponse(c, feedToken))
+}
+
+// ListFeedTokens lists the feed tokens of the authenticated user
+func (h *FeedHandler) ListFeedTokens(c echo.Context) error {
+	authenticatedUser, err := getAuthenticatedUser(c)
+	if err != nil {
+		return err
+	}
+
+	tokens, err := h.feedService.ListFeedTokens(authenticatedUser.UserID)
+	if err != nil {
+		return collectionError(err)
+	}
+
+	resp := make([]transport.FeedTokenResponse, len(tokens))
+	for i, feedToken := range tokens {
+		resp[i] = h.toFeedTokenResponse(c, feedToken)
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// RevokeFeedToken deletes a feed token; its feed URLs stop working immediately
+func (h *FeedHandler) RevokeFeedToken(c echo.Context) error {
+	authenticatedUser, err := getAuthenticatedUser(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.feedService.RevokeFeedToken(authenticatedUser.UserID, c.Param("token")); err != nil {
+		if errors.Is(err, model.ErrNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound, "Feed token not found")
+		}
+		return collectionError(err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// GetFeed serves /feeds/:token.atom and /feeds/:token.rss to feed readers. The token in
+// the URL is the only credential. Responses carry an ETag and Last-Modified so readers
+// can poll with If-None-Match or If-Modified-Since and get 304 Not Modified.
+func (h *FeedHandler) GetFeed(c echo.Context) error {
+	token, format := splitFeedToken(c.Param("token"))
+	if format == "" {
+		return echo.NewHTTPError(http.StatusNotFound, "Feed not found")
+	}
+
+	feed, err := h.feedService.GetFeed(token)
+	if err != nil {
+		// Revoked tokens and lost collection access look the same to the reader
+		if errors.Is(err, model.ErrNotFound) || errors.Is(err, model.ErrForbidden) {
+			return echo.NewHTTPError(http.StatusNotFound, "Feed not found")
+		}
+		logger.Error("Failed to build feed", zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to build feed")
+	}
+
+	selfURL := h.publicBaseURL(c) + c.Request().URL.Path
+	var doc any
+	contentType := "application/atom+xml; charset=utf-8"
+	if format == feedFormatAtom {
+		doc = toAtomFeed(feed, selfURL)
+	} else {
+		doc = toRSSFeed(feed, h.publicBaseURL(c))
+		contentType = "application/rss+xml; charset=utf-8"
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	if err := xml.NewEncoder(&buf).Encode(doc); err != nil {
+		logger.Error("Failed to encode feed", zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to build feed")
+	}
+
+	sum := sha256.Sum256(buf.Bytes())
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+	lastModified := feed.UpdatedAt.UTC().Truncate(time.Second)
+
+	header := c.Response().Header()
+	header.Set("ETag", etag)
+	header.Set("Last-Modified", lastModified.Format(http.TimeFormat))
+	// Readers must revalidate so that revoked tokens stop working immediately
+	header.Set("Cache-Control", "private, no-cache")
+
+	if notModified(c.Request(), etag, lastModified) {
+		return c.NoContent(http.StatusNotModified)
+	}
+	return c.Blob(http.StatusOK, contentType, buf.Bytes())
+}
+
+// notModified evaluates If-None-Match and, only when it is absent, If-Modified-Since
+func notModified(r *http.Request, etag string, lastModified time.Time) bool {
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		for _, candidate := range strings.Split(inm, ",") {
+			candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+			if candidate == etag || candidate == "*" {
+				return true
+			}
+		}
+		return false
+	}
+	if ims := r.Header.Get("If-Modified-Since"); ims != "" {
+		since, err := http.ParseTime(ims)
+		return err == nil && !lastModified.After(since)
+	}
+	return false
+}
+
+// splitFeedToken splits "token.atom" into the token and the format, returning an
+// empty format for unknown extensions
+func splitFeedToken(param string) (string, string) {
+	for _, format := range []string{feedFormatAtom, feedFormatRSS} {
+		if token, ok := strings.CutSuffix(param, format); ok && token != "" {
+			return token, format
+		}
+	}
+	return param, ""
+}
+
+func (h *FeedHandler) publicBaseURL(c echo.Context) string {
+	if h.baseURL != "" {
+		return h.baseURL
+	}
+	return c.Scheme() + "://" + c.Request().Host
+}
+
+func (h *FeedHandler) toFeedTokenResponse(c echo.Context, feedToken model.FeedToken) transport.FeedTokenResponse {
+	feedURL := h.publicBaseURL(c) + "/feeds/" + feedToken.Token
+	return transport.FeedTokenResponse{
+		Token:        feedToken.Token,
+		CollectionID: feedToken.CollectionID,
+		AtomURL:      feedURL + feedFormatAtom,
+		RSSURL:       feedURL + feedFormatRSS,
+		CreatedAt:    feedToken.CreatedAt,
+	}
+}
+
+// feedID is a stable identifier of a feed that does not change when its token is rotated
+func feedID(feed model.Feed) string {
+	id := "urn:athena:feed:" + feed.UserID
+	if feed.CollectionID != "" {
+		id += ":collection:" + feed.CollectionID
+	}
+	return id
+}
+
+func toAtomFeed(feed model.Feed, selfURL string) transport.AtomFeed {
+	atom := transport.AtomFeed{
+		ID:      feedID(feed),
+		Title:   feed.Title,
+		Updated: feed.UpdatedAt.UTC().Format(time.RFC3339),
+		Author:  transport.AtomPerson{Name: "Athena"},
+		Links:   []transport.AtomLink{{Rel: "self", Href: selfURL, Type: "application/atom+xml"}},
+		Entries: make([]transport.AtomEntry, len(feed.Bookmarks)),
+	}
+	for i, b := range feed.Bookmarks {
+		updated := b.UpdatedAt
+		if updated.Before(b.CreatedAt) {
+			updated = b.CreatedAt
+		}
+		entry := transport.AtomEntry{
+			ID:        "urn:uuid:" + b.ID,
+			Title:     feedItemTitle(b),
+			Links:     []transport.AtomLink{{Rel: "alternate", Href: b.URL}},
+			Published: b.CreatedAt.UTC().Format(time.RFC3339),
+			Updated:   updated.UTC().Format(time.RFC3339),
+			Summary:   b.ContentSummary,
+		}
+		if b.MainImageURL != "" {
+			entry.Links = append(entry.Links, transport.AtomLink{Rel: "enclosure", Href: b.MainImageURL, Type: imageMIMEType(b.MainImageURL)})
+		}
+		atom.Entries[i] = entry
+	}
+	return atom
+}
+
+func toRSSFeed(feed model.Feed, siteURL string) transport.RSSFeed {
+	rss := transport.RSSFeed{
+		Version: "2.0",
+		Channel: transport.RSSChannel{
+			Title:         feed.Title,
+			Link:          siteURL,
+			Description:   "Bookmarks saved in Athena",
+			LastBuildDate: feed.UpdatedAt.UTC().Format(time.RFC1123Z),
+			Items:         make([]transport.RSSItem, len(feed.Bookmarks)),
+		},
+	}
+	for i, b := range feed.Bookmarks {
+		item := transport.RSSItem{
+			Title:       feedItemTitle(b),
+			Link:        b.URL,
+			Description: b.ContentSummary,
+			GUID:        transport.RSSGUID{IsPermaLink: false, Value: "urn:uuid:" + b.ID},
+			PubDate:     b.CreatedAt.UTC().Format(time.RFC1123Z),
+		}
+		if b.MainImageURL != "" {
+			item.Enclosure = &transport.RSSEnclosure{URL: b.MainImageURL, Type: imageMIMEType(b.MainImageURL)}
+		}
+		rss.Channel.Items[i] = item
+	}
+	return rss
+}
+
+func feedItemTitle(b model.Bookmark) string {
+	if b.Title != "" {
+		return b.Title
+	}
+	return b.URL
+}
+
+// imageMIMEType guesses the MIME type of an image from its URL, defaulting to JPEG
+func imageMIMEType(imageURL string) string {
+	if u, err := url.Parse(imageURL); err == nil {
+		if t := mime.TypeByExtension(strings.ToLower(path.Ext(u.Path))); strings.HasPrefix(t, "image/") {
+			return t
+		}
+	}
+	return "image/jpeg"
+}
@@ -0,0 +1,186 @@
+package handler
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/tsongpon/athena/internal/model"
+	"github.com/tsongpon/athena/internal/transport"
+)
+
+// MockFeedService is a mock implementation of FeedService
+type MockFeedService struct {
+	mock.Mock
+}
+
+func (m *MockFeedService) CreateFeedToken(userID, collectionID string) (model.FeedToken, error) {
+	args := m.Called(userID, collectionID)
+	return args.Get(0).(model.FeedToken), args.Error(1)
+}
+
+func (m *MockFeedService) ListFeedTokens(userID string) ([]model.FeedToken, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]model.FeedToken), args.Error(1)
+}
+
+func (m *MockFeedService) RevokeFeedToken(userID, token string) error {
+	args := m.Called(userID, token)
+	return args.Error(0)
+}
+
+func (m *MockFeedService) GetFeed(token string) (model.Feed, error) {
+	args := m.Called(token)
+	return args.Get(0).(model.Feed), args.Error(1)
+}
+
+var testFeed = model.Feed{
+	Title:     "Bookmarks",
+	UserID:    "user123",
+	UpdatedAt: time.Date(2025, 11, 15, 10, 30, 45, 0, time.UTC),
+	Bookmarks: []model.Bookmark{{
+		ID:             "550e8400-e29b-41d4-a716-446655440000",
+		URL:            "https://example.com/post",
+		Title:          "Example & Co",
+		MainImageURL:   "https://example.com/og.png",
+		ContentSummary: "A summary",
+		CreatedAt:      time.Date(2025, 11, 15, 10, 30, 45, 0, time.UTC),
+	}},
+}
+
+func newFeedContext(target string, header map[string]string) (echo.Context, *httptest.ResponseRecorder) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("token")
+	c.SetParamValues(target[len("/feeds/"):])
+	return c, rec
+}
+
+func TestFeedHandler_GetFeed_Atom(t *testing.T) {
+	c, rec := newFeedContext("/feeds/tok.atom", nil)
+	mockService := new(MockFeedService)
+	handler := NewFeedHandler(mockService, "https://athena.example")
+	mockService.On("GetFeed", "tok").Return(testFeed, nil)
+
+	err := handler.GetFeed(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/atom+xml; charset=utf-8", rec.Header().Get(echo.HeaderContentType))
+	assert.NotEmpty(t, rec.Header().Get("ETag"))
+	assert.Equal(t, "Sat, 15 Nov 2025 10:30:45 GMT", rec.Header().Get("Last-Modified"))
+
+	var feed transport.AtomFeed
+	assert.NoError(t, xml.Unmarshal(rec.Body.Bytes(), &feed))
+	assert.Equal(t, "urn:athena:feed:user123", feed.ID)
+	assert.Equal(t, "https://athena.example/feeds/tok.atom", feed.Links[0].Href)
+	assert.Len(t, feed.Entries, 1)
+	entry := feed.Entries[0]
+	assert.Equal(t, "Example & Co", entry.Title)
+	assert.Equal(t, "A summary", entry.Summary)
+	assert.Equal(t, "2025-11-15T10:30:45Z", entry.Published)
+	assert.Equal(t, transport.AtomLink{Rel: "enclosure", Href: "https://example.com/og.png", Type: "image/png"}, entry.Links[1])
+}
+
+func TestFeedHandler_GetFeed_RSS(t *testing.T) {
+	c, rec := newFeedContext("/feeds/tok.rss", nil)
+	mockService := new(MockFeedService)
+	handler := NewFeedHandler(mockService, "https://athena.example")
+	mockService.On("GetFeed", "tok").Return(testFeed, nil)
+
+	err := handler.GetFeed(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "application/rss+xml; charset=utf-8", rec.Header().Get(echo.HeaderContentType))
+	var feed transport.RSSFeed
+	assert.NoError(t, xml.Unmarshal(rec.Body.Bytes(), &feed))
+	assert.Equal(t, "2.0", feed.Version)
+	assert.Len(t, feed.Channel.Items, 1)
+	item := feed.Channel.Items[0]
+	assert.Equal(t, "https://example.com/post", item.Link)
+	assert.Equal(t, "A summary", item.Description)
+	assert.Equal(t, "Sat, 15 Nov 2025 10:30:45 +0000", item.PubDate)
+	assert.Equal(t, &transport.RSSEnclosure{URL: "https://example.com/og.png", Type: "image/png"}, item.Enclosure)
+}
+
+func TestFeedHandler_GetFeed_ConditionalRequests(t *testing.T) {
+	c, rec := newFeedContext("/feeds/tok.atom", nil)
+	mockService := new(MockFeedService)
+	handler := NewFeedHandler(mockService, "")
+	mockService.On("GetFeed", "tok").Return(testFeed, nil)
+	assert.NoError(t, handler.GetFeed(c))
+	etag := rec.Header().Get("ETag")
+
+	tests := []struct {
+		name       string
+		header     map[string]string
+		wantStatus int
+	}{
+		{"matching etag", map[string]string{"If-None-Match": etag}, http.StatusNotModified},
+		{"weak matching etag in a list", map[string]string{"If-None-Match": `"other", W/` + etag}, http.StatusNotModified},
+		{"stale etag wins over if-modified-since", map[string]string{"If-None-Match": `"other"`, "If-Modified-Since": "Sun, 16 Nov 2025 00:00:00 GMT"}, http.StatusOK},
+		{"not modified since", map[string]string{"If-Modified-Since": "Sat, 15 Nov 2025 10:30:45 GMT"}, http.StatusNotModified},
+		{"modified since", map[string]string{"If-Modified-Since": "Sat, 15 Nov 2025 10:30:44 GMT"}, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, rec := newFeedContext("/feeds/tok.atom", tt.header)
+			assert.NoError(t, handler.GetFeed(c))
+			assert.Equal(t, tt.wantStatus, rec.Code)
+			if tt.wantStatus == http.StatusNotModified {
+				assert.Empty(t, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestFeedHandler_GetFeed_NotFound(t *testing.T) {
+	tests := []struct {
+		name   string
+		target string
+		err    error
+	}{
+		{"unknown extension", "/feeds/tok.json", nil},
+		{"revoked token", "/feeds/tok.atom", fmt.Errorf("feed token %w", model.ErrNotFound)},
+		{"lost collection access", "/feeds/tok.rss", fmt.Errorf("cannot view: %w", model.ErrForbidden)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := newFeedContext(tt.target, nil)
+			mockService := new(MockFeedService)
+			handler := NewFeedHandler(mockService, "")
+			mockService.On("GetFeed", "tok").Return(model.Feed{}, tt.err)
+
+			err := handler.GetFeed(c)
+
+			httpErr, ok := err.(*echo.HTTPError)
+			assert.True(t, ok)
+			assert.Equal(t, http.StatusNotFound, httpErr.Code)
+		})
+	}
+}
+
+func TestFeedHandler_CreateFeedToken(t *testing.T) {
+	c, rec := newCollectionContext(http.MethodPost, "/feeds", `{"collection_id":"c1"}`)
+	mockService := new(MockFeedService)
+	handler := NewFeedHandler(mockService, "https://athena.example")
+	mockService.On("CreateFeedToken", "user123", "c1").Return(model.FeedToken{Token: "tok", UserID: "user123", CollectionID: "c1"}, nil)
+
+	err := handler.CreateFeedToken(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"atom_url":"https://athena.example/feeds/tok.atom"`)
+	assert.Contains(t, rec.Body.String(), `"rss_url":"https://athena.example/feeds/tok.rss"`)
+}
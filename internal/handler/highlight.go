@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/tsongpon/athena/internal/model"
+	"github.com/tsongpon/athena/internal/transport"
+)
+
+type HighlightHandler struct {
+	highlightService HighlightService
+}
+
+func NewHighlightHandler(highlightService HighlightService) *HighlightHandler {
+	return &HighlightHandler{
+		highlightService: highlightService,
+	}
+}
+
+// CreateHighlight highlights a passage of a bookmark the user owns
+func (h *HighlightHandler) CreateHighlight(c echo.Context) error {
+	authenticatedUser, err := getAuthenticatedUser(c)
+	if err != nil {
+		return err
+	}
+
+	var req transport.CreateHighlightRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	highlight := model.Highlight{Quote: req.Quote, Note: req.Note}
+	if req.Anchor != nil {
+		highlight.Anchor = model.HighlightAnchor{Start: req.Anchor.Start, End: req.Anchor.End}
+	}
+	created, err := h.highlightService.CreateHighlight(authenticatedUser.UserID, c.Param("id"), highlight)
+	if err != nil {
+		return highlightError(err)
+	}
+	return c.JSON(http.StatusCreated, toHighlightTransport(created))
+}
+
+// ListHighlights returns the highlights of a bookmark the user owns, in the order of the page
+func (h *HighlightHandler) ListHighlights(c echo.Context) error {
+	authenticatedUser, err := getAuthenticatedUser(c)
+	if err != nil {
+		return err
+	}
+
+	highlights, err := h.highlightService.ListHighlights(authenticatedUser.UserID, c.Param("id"))
+	if err != nil {
+		return highlightError(err)
+	}
+	ts := make([]transport.HighlightTransport, len(highlights))
+	for i, highlight := range highlights {
+		ts[i] = toHighlightTransport(highlight)
+	}
+	return c.JSON(http.StatusOK, ts)
+}
+
+// UpdateHighlight changes the note or anchor of a highlight
+func (h *HighlightHandler) UpdateHighlight(c echo.Context) error {
+	authenticatedUser, err := getAuthenticatedUser(c)
+	if err != nil {
+		return err
+	}
+
+	var req transport.UpdateHighlightRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	update := model.HighlightUpdate{Note: req.Note}
+	if req.Anchor != nil {
+		update.Anchor = &model.HighlightAnchor{Start: req.Anchor.Start, End: req.Anchor.End}
+	}
+	updated, err := h.highlightService.UpdateHighlight(authenticatedUser.UserID, c.Param("id"), c.Param("highlight_id"), update)
+	if err != nil {
+		return highlightError(err)
+	}
+	return c.JSON(http.StatusOK, toHighlightTransport(updated))
+}
+
+// DeleteHighlight deletes a highlight
+func (h *HighlightHandler) DeleteHighlight(c echo.Context) error {
+	authenticatedUser, err := getAuthenticatedUser(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.highlightService.DeleteHighlight(authenticatedUser.UserID, c.Param("id"), c.Param("highlight_id")); err != nil {
+		return highlightError(err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// highlightError maps the errors of the highlight service to HTTP errors
+func highlightError(err error) error {
+	switch {
+	case errors.Is(err, model.ErrInvalidInput):
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	case errors.Is(err, model.ErrNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, "Bookmark or highlight not found")
+	default:
+		return bookmarkError(err)
+	}
+}
+
+func toHighlightTransport(highlight model.Highlight) transport.HighlightTransport {
+	t := transport.HighlightTransport{
+		ID:         highlight.ID,
+		BookmarkID: highlight.BookmarkID,
+		Quote:      highlight.Quote,
+		Note:       highlight.Note,
+		CreatedAt:  highlight.CreatedAt,
+		UpdatedAt:  highlight.UpdatedAt,
+	}
+	if highlight.Anchor != (model.HighlightAnchor{}) {
+		t.Anchor = &transport.HighlightAnchorTransport{Start: highlight.Anchor.Start, End: highlight.Anchor.End}
+	}
+	return t
+}
@@ -0,0 +1,168 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/tsongpon/athena/internal/model"
+	"github.com/tsongpon/athena/internal/transport"
+)
+
+// MockHighlightService is a mock implementation of HighlightService
+type MockHighlightService struct {
+	mock.Mock
+}
+
+func (m *MockHighlightService) CreateHighlight(userID, bookmarkID string, highlight model.Highlight) (model.Highlight, error) {
+	args := m.Called(userID, bookmarkID, highlight)
+	return args.Get(0).(model.Highlight), args.Error(1)
+}
+
+func (m *MockHighlightService) ListHighlights(userID, bookmarkID string) ([]model.Highlight, error) {
+	args := m.Called(userID, bookmarkID)
+	highlights, _ := args.Get(0).([]model.Highlight)
+	return highlights, args.Error(1)
+}
+
+func (m *MockHighlightService) UpdateHighlight(userID, bookmarkID, id string, update model.HighlightUpdate) (model.Highlight, error) {
+	args := m.Called(userID, bookmarkID, id, update)
+	return args.Get(0).(model.Highlight), args.Error(1)
+}
+
+func (m *MockHighlightService) DeleteHighlight(userID, bookmarkID, id string) error {
+	args := m.Called(userID, bookmarkID, id)
+	return args.Error(0)
+}
+
+func TestHighlightHandler_CreateHighlight(t *testing.T) {
+	c, rec := newCollectionContext(http.MethodPost, "/bookmarks/b1/highlights", `{"quote":"a passage","note":"**why** it matters","anchor":{"start":10,"end":19}}`)
+	c.SetParamNames("id")
+	c.SetParamValues("b1")
+
+	mockService := new(MockHighlightService)
+	handler := NewHighlightHandler(mockService)
+	createdAt := time.Date(2025, 11, 15, 10, 30, 0, 0, time.UTC)
+	mockService.On("CreateHighlight", "user123", "b1", model.Highlight{Quote: "a passage", Note: "**why** it matters", Anchor: model.HighlightAnchor{Start: 10, End: 19}}).
+		Return(model.Highlight{ID: "h1", BookmarkID: "b1", UserID: "user123", Quote: "a passage", Note: "**why** it matters",
+			Anchor: model.HighlightAnchor{Start: 10, End: 19}, CreatedAt: createdAt, UpdatedAt: createdAt}, nil)
+
+	err := handler.CreateHighlight(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	var response transport.HighlightTransport
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, "h1", response.ID)
+	assert.Equal(t, "**why** it matters", response.Note)
+	assert.Equal(t, &transport.HighlightAnchorTransport{Start: 10, End: 19}, response.Anchor)
+	mockService.AssertExpectations(t)
+}
+
+func TestHighlightHandler_CreateHighlight_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		code int
+	}{
+		{"invalid", fmt.Errorf("quote is required: %w", model.ErrInvalidInput), http.StatusBadRequest},
+		{"forbidden", model.ErrForbidden, http.StatusForbidden},
+		{"not found", model.ErrNotFound, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := newCollectionContext(http.MethodPost, "/bookmarks/b1/highlights", `{"quote":""}`)
+			c.SetParamNames("id")
+			c.SetParamValues("b1")
+
+			mockService := new(MockHighlightService)
+			handler := NewHighlightHandler(mockService)
+			mockService.On("CreateHighlight", "user123", "b1", mock.Anything).Return(model.Highlight{}, tt.err)
+
+			err := handler.CreateHighlight(c)
+
+			httpErr, ok := err.(*echo.HTTPError)
+			assert.True(t, ok)
+			assert.Equal(t, tt.code, httpErr.Code)
+		})
+	}
+}
+
+func TestHighlightHandler_ListHighlights(t *testing.T) {
+	c, rec := newCollectionContext(http.MethodGet, "/bookmarks/b1/highlights", "")
+	c.SetParamNames("id")
+	c.SetParamValues("b1")
+
+	mockService := new(MockHighlightService)
+	handler := NewHighlightHandler(mockService)
+	mockService.On("ListHighlights", "user123", "b1").Return([]model.Highlight{
+		{ID: "h1", BookmarkID: "b1", Quote: "anchored", Anchor: model.HighlightAnchor{Start: 1, End: 9}},
+		{ID: "h2", BookmarkID: "b1", Quote: "not anchored"},
+	}, nil)
+
+	err := handler.ListHighlights(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var response []transport.HighlightTransport
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Len(t, response, 2)
+	assert.NotNil(t, response[0].Anchor)
+	assert.Nil(t, response[1].Anchor)
+	mockService.AssertExpectations(t)
+}
+
+func TestHighlightHandler_UpdateHighlight(t *testing.T) {
+	c, rec := newCollectionContext(http.MethodPatch, "/bookmarks/b1/highlights/h1", `{"note":"rethought"}`)
+	c.SetParamNames("id", "highlight_id")
+	c.SetParamValues("b1", "h1")
+
+	mockService := new(MockHighlightService)
+	handler := NewHighlightHandler(mockService)
+	note := "rethought"
+	mockService.On("UpdateHighlight", "user123", "b1", "h1", model.HighlightUpdate{Note: &note}).
+		Return(model.Highlight{ID: "h1", BookmarkID: "b1", Quote: "a passage", Note: note}, nil)
+
+	err := handler.UpdateHighlight(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestHighlightHandler_DeleteHighlight(t *testing.T) {
+	c, rec := newCollectionContext(http.MethodDelete, "/bookmarks/b1/highlights/h1", "")
+	c.SetParamNames("id", "highlight_id")
+	c.SetParamValues("b1", "h1")
+
+	mockService := new(MockHighlightService)
+	handler := NewHighlightHandler(mockService)
+	mockService.On("DeleteHighlight", "user123", "b1", "h1").Return(nil)
+
+	err := handler.DeleteHighlight(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestHighlightHandler_DeleteHighlight_NotFound(t *testing.T) {
+	c, _ := newCollectionContext(http.MethodDelete, "/bookmarks/b1/highlights/h9", "")
+	c.SetParamNames("id", "highlight_id")
+	c.SetParamValues("b1", "h9")
+
+	mockService := new(MockHighlightService)
+	handler := NewHighlightHandler(mockService)
+	mockService.On("DeleteHighlight", "user123", "b1", "h9").Return(fmt.Errorf("highlight with ID h9 %w", model.ErrNotFound))
+
+	err := handler.DeleteHighlight(c)
+
+	httpErr, ok := err.(*echo.HTTPError)
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusNotFound, httpErr.Code)
+}
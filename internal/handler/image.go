@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/tsongpon/athena/internal/logger"
+	"github.com/tsongpon/athena/internal/model"
+	"go.uber.org/zap"
+)
+
+// imageCacheControl lets clients keep thumbnails for a year. Thumbnail URLs carry the
+// capture time, so a recaptured image gets a new URL rather than going stale.
+const imageCacheControl = "private, max-age=31536000, immutable"
+
+type ImageHandler struct {
+	thumbnailService ThumbnailService
+}
+
+func NewImageHandler(thumbnailService ThumbnailService) *ImageHandler {
+	return &ImageHandler{
+		thumbnailService: thumbnailService,
+	}
+}
+
+// GetImage serves a thumbnail of the main image of a bookmark
+func (h *ImageHandler) GetImage(c echo.Context) error {
+	authenticatedUser, err := getAuthenticatedUser(c)
+	if err != nil {
+		return err
+	}
+
+	thumbnail, err := h.thumbnailService.GetThumbnail(authenticatedUser.UserID, c.Param("id"), c.Param("size"))
+	if errors.Is(err, model.ErrNotFound) {
+		return echo.NewHTTPError(http.StatusNotFound, "Image not found")
+	}
+	if errors.Is(err, model.ErrForbidden) {
+		return echo.NewHTTPError(http.StatusForbidden, "Access denied")
+	}
+	if err != nil {
+		logger.Error("Failed to get image",
+			zap.String("bookmark_id", c.Param("id")),
+			zap.String("size", c.Param("size")),
+			zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get image")
+	}
+
+	contentType := thumbnail.ContentType
+	if !strings.HasPrefix(contentType, "image/") {
+		contentType = http.DetectContentType(thumbnail.Data)
+	}
+	sum := sha256.Sum256(thumbnail.Data)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+	lastModified := thumbnail.UpdatedAt.UTC().Truncate(time.Second)
+
+	header := c.Response().Header()
+	header.Set("X-Content-Type-Options", "nosniff")
+	header.Set("ETag", etag)
+	header.Set("Last-Modified", lastModified.Format(http.TimeFormat))
+	header.Set("Cache-Control", imageCacheControl)
+
+	if notModified(c.Request(), etag, lastModified) {
+		return c.NoContent(http.StatusNotModified)
+	}
+	return c.Blob(http.StatusOK, contentType, thumbnail.Data)
+}
@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/tsongpon/athena/internal/model"
+)
+
+// MockThumbnailService is a mock implementation of ThumbnailService
+type MockThumbnailService struct {
+	mock.Mock
+}
+
+func (m *MockThumbnailService) GetThumbnail(userID, bookmarkID, size string) (model.Blob, error) {
+	args := m.Called(userID, bookmarkID, size)
+	return args.Get(0).(model.Blob), args.Error(1)
+}
+
+func newImageContext(header map[string]string) (echo.Context, *httptest.ResponseRecorder) {
+	c, rec := newCollectionContext(http.MethodGet, "/images/b1/small", "")
+	for k, v := range header {
+		c.Request().Header.Set(k, v)
+	}
+	c.SetParamNames("id", "size")
+	c.SetParamValues("b1", "small")
+	return c, rec
+}
+
+func TestImageHandler_GetImage(t *testing.T) {
+	mockService := new(MockThumbnailService)
+	handler := NewImageHandler(mockService)
+	mockService.On("GetThumbnail", "user123", "b1", "small").Return(model.Blob{
+		Key:         "thumbnails/b1/small.jpg",
+		ContentType: "image/jpeg",
+		Data:        []byte("\xff\xd8\xff\xe0 jpeg"),
+		UpdatedAt:   time.Date(2025, 11, 15, 10, 30, 45, 0, time.UTC),
+	}, nil)
+
+	c, rec := newImageContext(nil)
+	err := handler.GetImage(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "image/jpeg", rec.Header().Get(echo.HeaderContentType))
+	assert.Equal(t, "private, max-age=31536000, immutable", rec.Header().Get("Cache-Control"))
+	assert.Equal(t, "nosniff", rec.Header().Get("X-Content-Type-Options"))
+	assert.Equal(t, "Sat, 15 Nov 2025 10:30:45 GMT", rec.Header().Get("Last-Modified"))
+	assert.Equal(t, "\xff\xd8\xff\xe0 jpeg", rec.Body.String())
+
+	c, rec = newImageContext(map[string]string{"If-None-Match": rec.Header().Get("ETag")})
+	assert.NoError(t, handler.GetImage(c))
+	assert.Equal(t, http.StatusNotModified, rec.Code)
+}
+
+func TestImageHandler_GetImage_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+	}{
+		{"no thumbnail", fmt.Errorf("bookmark b1 has no \"small\" thumbnail: %w", model.ErrNotFound), http.StatusNotFound},
+		{"not allowed", fmt.Errorf("cannot view: %w", model.ErrForbidden), http.StatusForbidden},
+		{"storage failure", fmt.Errorf("failed to download blob"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockThumbnailService)
+			handler := NewImageHandler(mockService)
+			mockService.On("GetThumbnail", "user123", "b1", "small").Return(model.Blob{}, tt.err)
+
+			c, _ := newImageContext(nil)
+			err := handler.GetImage(c)
+
+			httpErr, ok := err.(*echo.HTTPError)
+			assert.True(t, ok)
+			assert.Equal(t, tt.wantStatus, httpErr.Code)
+		})
+	}
+}
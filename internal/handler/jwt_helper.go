@@ -10,7 +10,7 @@ import (
 )
 
 // generateJWT creates a new JWT token for the authenticated user
-func generateJWT(userID, email, name string) (string, time.Time, error) {
+func generateJWT(userID, email, name, role string) (string, time.Time, error) {
 	// Get JWT secret from environment or use default
 	secret := os.Getenv("JWT_SECRET")
 	if secret == "" {
@@ -25,6 +25,7 @@ func generateJWT(userID, email, name string) (string, time.Time, error) {
 		UserID: userID,
 		Email:  email,
 		Name:   name,
+		Role:   role,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(expiresAt),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/tsongpon/athena/internal/logger"
+	"github.com/tsongpon/athena/internal/model"
+	"go.uber.org/zap"
+)
+
+// currentUserContextKey is the Echo context key holding the model.User loaded by RequireActiveUser
+const currentUserContextKey = "current_user"
+
+// AuthMiddleware enforces account state on top of the JWT middleware
+type AuthMiddleware struct {
+	userService UserService
+}
+
+func NewAuthMiddleware(userService UserService) *AuthMiddleware {
+	return &AuthMiddleware{
+		userService: userService,
+	}
+}
+
+// RequireActiveUser rejects tokens that belong to disabled accounts or that were
+// issued before the account's last forced logout. It must run after the JWT middleware.
+func (m *AuthMiddleware) RequireActiveUser(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		claims, err := getAuthenticatedUser(c)
+		if err != nil {
+			return err
+		}
+
+		user, err := m.userService.GetUser(claims.UserID)
+		if errors.Is(err, model.ErrNotFound) {
+			return echo.NewHTTPError(http.StatusUnauthorized, "User not found")
+		}
+		if err != nil {
+			logger.Error("Failed to load authenticated user", zap.String("user_id", claims.UserID), zap.Error(err))
+			return echo.NewHTTPError(http.StatusInternalServerError, "Failed to load user")
+		}
+
+		if user.Disabled {
+			return echo.NewHTTPError(http.StatusForbidden, "Account is disabled")
+		}
+		if tokenRevoked(claims, user) {
+			return echo.NewHTTPError(http.StatusUnauthorized, "Token has been revoked")
+		}
+
+		c.Set(currentUserContextKey, user)
+		return next(c)
+	}
+}
+
+// tokenRevoked reports whether the token was issued before the account's last forced logout.
+// JWT iat has second precision, so the revocation time is truncated to the second and tokens
+// issued within that second are revoked too: a token from just before the revocation cannot
+// be told apart from one just after it, and a user logging in again right away only has to
+// retry a second later.
+func tokenRevoked(claims *JWTClaims, user model.User) bool {
+	if user.TokensRevokedAt.IsZero() {
+		return false
+	}
+	if claims.IssuedAt == nil {
+		return true
+	}
+	return !claims.IssuedAt.Time.After(user.TokensRevokedAt.Truncate(time.Second))
+}
+
+// RequireAdmin rejects callers without the admin role. It must run after RequireActiveUser
+// so that the role is read from the stored account rather than trusted from the token alone.
+func (m *AuthMiddleware) RequireAdmin(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		user, ok := c.Get(currentUserContextKey).(model.User)
+		if !ok || !user.IsAdmin() {
+			return echo.NewHTTPError(http.StatusForbidden, "Admin role required")
+		}
+		return next(c)
+	}
+}
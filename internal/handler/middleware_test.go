@@ -0,0 +1,122 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/tsongpon/athena/internal/model"
+)
+
+func okHandler(c echo.Context) error {
+	return c.NoContent(http.StatusOK)
+}
+
+func newMiddlewareContext(issuedAt time.Time) (echo.Context, *httptest.ResponseRecorder) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/bookmarks", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.Set("user", &JWTClaims{
+		UserID: "user123",
+		RegisteredClaims: jwt.RegisteredClaims{
+			IssuedAt: jwt.NewNumericDate(issuedAt),
+		},
+	})
+	return c, rec
+}
+
+func TestAuthMiddleware_RequireActiveUser_Success(t *testing.T) {
+	c, rec := newMiddlewareContext(time.Now())
+
+	mockService := new(MockUserService)
+	mockService.On("GetUser", "user123").Return(model.User{ID: "user123", Role: model.RoleUser}, nil)
+
+	err := NewAuthMiddleware(mockService).RequireActiveUser(okHandler)(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "user123", c.Get(currentUserContextKey).(model.User).ID)
+}
+
+func TestAuthMiddleware_RequireActiveUser_Disabled(t *testing.T) {
+	c, _ := newMiddlewareContext(time.Now())
+
+	mockService := new(MockUserService)
+	mockService.On("GetUser", "user123").Return(model.User{ID: "user123", Disabled: true}, nil)
+
+	err := NewAuthMiddleware(mockService).RequireActiveUser(okHandler)(c)
+
+	httpErr, ok := err.(*echo.HTTPError)
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusForbidden, httpErr.Code)
+}
+
+func TestAuthMiddleware_RequireActiveUser_RevokedToken(t *testing.T) {
+	c, _ := newMiddlewareContext(time.Now().Add(-time.Hour))
+
+	mockService := new(MockUserService)
+	mockService.On("GetUser", "user123").Return(model.User{ID: "user123", TokensRevokedAt: time.Now()}, nil)
+
+	err := NewAuthMiddleware(mockService).RequireActiveUser(okHandler)(c)
+
+	httpErr, ok := err.(*echo.HTTPError)
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusUnauthorized, httpErr.Code)
+}
+
+// TestAuthMiddleware_RequireActiveUser_RevokedSameSecond tests that tokens issued in the second
+// of a forced logout are revoked, and tokens from the next second are accepted
+func TestAuthMiddleware_RequireActiveUser_RevokedSameSecond(t *testing.T) {
+	revokedAt := time.Date(2026, 3, 1, 12, 0, 0, 700_000_000, time.UTC)
+
+	tests := []struct {
+		name     string
+		issuedAt time.Time
+		wantCode int
+	}{
+		{name: "issued earlier in the same second", issuedAt: revokedAt.Add(-500 * time.Millisecond), wantCode: http.StatusUnauthorized},
+		{name: "issued later in the same second", issuedAt: revokedAt.Add(200 * time.Millisecond), wantCode: http.StatusUnauthorized},
+		{name: "issued the next second", issuedAt: revokedAt.Add(300 * time.Millisecond), wantCode: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, rec := newMiddlewareContext(tt.issuedAt)
+
+			mockService := new(MockUserService)
+			mockService.On("GetUser", "user123").Return(model.User{ID: "user123", TokensRevokedAt: revokedAt}, nil)
+
+			err := NewAuthMiddleware(mockService).RequireActiveUser(okHandler)(c)
+
+			if tt.wantCode == http.StatusOK {
+				assert.NoError(t, err)
+				assert.Equal(t, http.StatusOK, rec.Code)
+				return
+			}
+			httpErr, ok := err.(*echo.HTTPError)
+			assert.True(t, ok)
+			assert.Equal(t, tt.wantCode, httpErr.Code)
+		})
+	}
+}
+
+func TestAuthMiddleware_RequireAdmin(t *testing.T) {
+	middleware := NewAuthMiddleware(new(MockUserService))
+
+	c, rec := newMiddlewareContext(time.Now())
+	c.Set(currentUserContextKey, model.User{ID: "user123", Role: model.RoleAdmin})
+	assert.NoError(t, middleware.RequireAdmin(okHandler)(c))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	c, _ = newMiddlewareContext(time.Now())
+	c.Set(currentUserContextKey, model.User{ID: "user123", Role: model.RoleUser})
+	err := middleware.RequireAdmin(okHandler)(c)
+	httpErr, ok := err.(*echo.HTTPError)
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusForbidden, httpErr.Code)
+}
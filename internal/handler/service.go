@@ -1,17 +1,129 @@
 package handler
 
-import "github.com/tsongpon/athena/internal/model"
+import (
+	"time"
+
+	"github.com/tsongpon/athena/internal/model"
+)
 
 type UserService interface {
 	AuthenticateUser(email, password string) (model.User, error)
 	CreateUser(user model.User) (model.User, error)
+	GetUser(id string) (model.User, error)
 }
 
 type BookmarkService interface {
 	CreateBookmark(b model.Bookmark) (model.Bookmark, error)
-	GetBookmark(id string) (model.Bookmark, error)
-	DeleteBookmark(id string) error
+	GetBookmark(userID, id string) (model.Bookmark, error)
+	DeleteBookmark(userID, id string, version int64) error
 	GetAllBookmarks(userID string, archived bool) ([]model.Bookmark, error)
 	GetBookmarksWithPagination(userID string, archived bool, page, pageSize int) (model.BookmarkListResponse, error)
-	ArchiveBookmark(id string) (model.Bookmark, error)
+	ListBookmarks(query model.BookmarkQuery) (model.BookmarkListResponse, error)
+	// ArchiveBookmark, UpdateReadingState and UpdateNotes fail with ErrPreconditionFailed when a
+	// non-zero version is given and the bookmark is no longer at it
+	ArchiveBookmark(userID, id string, version int64) (model.Bookmark, error)
+	OpenBookmark(userID, id string) (model.Bookmark, error)
+	UpdateReadingState(userID, id string, update model.ReadingStateUpdate, version int64) (model.Bookmark, error)
+	UpdateNotes(userID, id, notes string, version int64) (model.Bookmark, error)
+	RefreshBookmark(userID, id string) (model.Bookmark, error)
+	RefreshBookmarks(userID string, ids []string) (model.BulkRefresh, error)
+}
+
+type HighlightService interface {
+	CreateHighlight(userID, bookmarkID string, highlight model.Highlight) (model.Highlight, error)
+	ListHighlights(userID, bookmarkID string) ([]model.Highlight, error)
+	UpdateHighlight(userID, bookmarkID, id string, update model.HighlightUpdate) (model.Highlight, error)
+	DeleteHighlight(userID, bookmarkID, id string) error
+}
+
+type TrashService interface {
+	ListTrash(userID string) ([]model.Bookmark, error)
+	RestoreBookmark(userID, id string) (model.Bookmark, error)
+	EmptyTrash(userID string) (int, error)
+}
+
+type BatchService interface {
+	ApplyBatch(userID string, ops []model.BatchOperation) ([]model.BatchResult, error)
+}
+
+type SnapshotService interface {
+	GetSnapshot(userID, bookmarkID string) (model.Blob, error)
+}
+
+type ThumbnailService interface {
+	GetThumbnail(userID, bookmarkID, size string) (model.Blob, error)
+}
+
+type CollectionService interface {
+	CreateCollection(c model.Collection) (model.Collection, error)
+	GetCollection(userID, id string) (model.Collection, error)
+	ListCollections(userID string) ([]model.Collection, error)
+	ListSharedCollections(userID string) ([]model.Collection, error)
+	ListActivity(userID, collectionID string, limit int) ([]model.Activity, error)
+	UpdateCollection(userID string, update model.Collection) (model.Collection, error)
+	DeleteCollection(userID, id string) error
+	AddBookmark(userID, collectionID, bookmarkID string, position int) (model.Collection, error)
+	RemoveBookmark(userID, collectionID, bookmarkID string) (model.Collection, error)
+	ReorderBookmarks(userID, collectionID string, bookmarkIDs []string) (model.Collection, error)
+	ListCollectionBookmarks(userID, collectionID string) ([]model.CollectionBookmark, error)
+}
+
+type SharingService interface {
+	InviteCollaborator(userID, collectionID, email, role string) (model.Invitation, error)
+	ListCollectionInvitations(userID, collectionID string) ([]model.Invitation, error)
+	RevokeInvitation(userID, collectionID, invitationID string) error
+	ListPendingInvitations(userID string) ([]model.Invitation, error)
+	AcceptInvitation(userID, invitationID string) (model.Collection, error)
+	DeclineInvitation(userID, invitationID string) (model.Invitation, error)
+	UpdateCollaborator(userID, collectionID, collaboratorID, role string) (model.Collection, error)
+	RemoveCollaborator(userID, collectionID, collaboratorID string) error
+}
+
+type ShareService interface {
+	CreateShareLink(userID, resourceType, resourceID, password string, expiresAt time.Time) (model.ShareLink, error)
+	ListShareLinks(userID string) ([]model.ShareLink, error)
+	RevokeShareLink(userID, slug string) error
+	ResolveShareLink(slug, password string) (model.PublicShare, error)
+}
+
+type FeedService interface {
+	CreateFeedToken(userID, collectionID string) (model.FeedToken, error)
+	ListFeedTokens(userID string) ([]model.FeedToken, error)
+	RevokeFeedToken(userID, token string) error
+	GetFeed(token string) (model.Feed, error)
+}
+
+type AdminService interface {
+	ListUsers(search string, page, pageSize int) (model.UserListResponse, error)
+	GetUserStats(id string) (model.UserStats, error)
+	SetTier(id, tier string) (model.User, error)
+	SetRole(actorID, id, role string) (model.User, error)
+	SetDisabled(actorID, id string, disabled bool) (model.User, error)
+	ForceLogout(id string) (model.User, error)
+}
+
+type EntitlementService interface {
+	GetUsage(userID string) (model.Usage, error)
+}
+
+type BillingService interface {
+	SignatureHeader() string
+	HandleWebhook(payload []byte, signature string) error
+}
+
+type EventService interface {
+	Subscribe(userID, lastEventID string) (model.EventSubscription, error)
+}
+
+type WebhookService interface {
+	CreateWebhook(userID, url string, events []string) (model.Webhook, error)
+	ListWebhooks(userID string) ([]model.Webhook, error)
+	UpdateWebhook(userID, id string, update model.WebhookUpdate) (model.Webhook, error)
+	DeleteWebhook(userID, id string) error
+	ListDeliveries(userID, webhookID string, limit int) ([]model.WebhookDelivery, error)
+}
+
+type SyncService interface {
+	GetChanges(userID, token string) (model.SyncDelta, error)
+	PushChanges(userID string, edits []model.SyncEdit) ([]model.SyncResult, error)
 }
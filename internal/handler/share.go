@@ -0,0 +1,190 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/tsongpon/athena/internal/logger"
+	"github.com/tsongpon/athena/internal/model"
+	"github.com/tsongpon/athena/internal/transport"
+	"go.uber.org/zap"
+)
+
+// SharePasswordHeader carries the password of a protected share link for API clients
+const SharePasswordHeader = "X-Share-Password"
+
+type ShareHandler struct {
+	shareService ShareService
+	baseURL      string
+}
+
+// NewShareHandler creates a share handler. baseURL prefixes the share URLs handed to owners;
+// when empty it is derived from the incoming request.
+func NewShareHandler(shareService ShareService, baseURL string) *ShareHandler {
+	return &ShareHandler{
+		shareService: shareService,
+		baseURL:      strings.TrimSuffix(baseURL, "/"),
+	}
+}
+
+// ShareBookmark creates a public share link for a bookmark
+func (h *ShareHandler) ShareBookmark(c echo.Context) error {
+	return h.createShareLink(c, model.ShareResourceBookmark)
+}
+
+// ShareCollection creates a public share link for a collection
+func (h *ShareHandler) ShareCollection(c echo.Context) error {
+	return h.createShareLink(c, model.ShareResourceCollection)
+}
+
+func (h *ShareHandler) createShareLink(c echo.Context, resourceType string) error {
+	req := &transport.CreateShareLinkRequest{}
+	if err := c.Bind(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	authenticatedUser, err := getAuthenticatedUser(c)
+	if err != nil {
+		return err
+	}
+
+	var expiresAt time.Time
+	if req.ExpiresAt != nil {
+		expiresAt = *req.ExpiresAt
+	}
+	link, err := h.shareService.CreateShareLink(authenticatedUser.UserID, resourceType, c.Param("id"), req.Password, expiresAt)
+	if err != nil {
+		return shareError(err)
+	}
+	return c.JSON(http.StatusCreated, h.toShareLinkResponse(c, link))
+}
+
+// ListShareLinks lists the share links of the authenticated user
+func (h *ShareHandler) ListShareLinks(c echo.Context) error {
+	authenticatedUser, err := getAuthenticatedUser(c)
+	if err != nil {
+		return err
+	}
+
+	links, err := h.shareService.ListShareLinks(authenticatedUser.UserID)
+	if err != nil {
+		return shareError(err)
+	}
+
+	resp := make([]transport.ShareLinkResponse, len(links))
+	for i, link := range links {
+		resp[i] = h.toShareLinkResponse(c, link)
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// RevokeShareLink deletes a share link; its slug stops resolving immediately
+func (h *ShareHandler) RevokeShareLink(c echo.Context) error {
+	authenticatedUser, err := getAuthenticatedUser(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.shareService.RevokeShareLink(authenticatedUser.UserID, c.Param("slug")); err != nil {
+		return shareError(err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// ViewShare is the unauthenticated view of a share link. It renders HTML for browsers
+// (or ?format=html) and JSON otherwise. The password of a protected link is read from
+// the X-Share-Password header or a "password" form value.
+func (h *ShareHandler) ViewShare(c echo.Context) error {
+	password := c.Request().Header.Get(SharePasswordHeader)
+	if password == "" {
+		password = c.FormValue("password")
+	}
+
+	// Responses must not be cached so that revocation takes effect immediately
+	c.Response().Header().Set("Cache-Control", "no-store")
+
+	share, err := h.shareService.ResolveShareLink(c.Param("slug"), password)
+	if wantsHTML(c) {
+		if err != nil {
+			return renderSharePageError(c, err, password != "")
+		}
+		return renderSharePage(c, share)
+	}
+	if err != nil {
+		return shareError(err)
+	}
+	return c.JSON(http.StatusOK, toPublicShareResponse(share))
+}
+
+// shareError maps service errors to HTTP errors
+func shareError(err error) error {
+	switch {
+	case errors.Is(err, model.ErrInvalidInput):
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	case errors.Is(err, model.ErrUnauthorized):
+		return echo.NewHTTPError(http.StatusUnauthorized, "Password required")
+	case errors.Is(err, model.ErrForbidden):
+		return echo.NewHTTPError(http.StatusForbidden, "Access denied")
+	case errors.Is(err, model.ErrNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, "Share link not found")
+	case errors.Is(err, model.ErrExpired):
+		return echo.NewHTTPError(http.StatusGone, "Share link has expired")
+	default:
+		logger.Error("Share operation failed", zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to process share link")
+	}
+}
+
+// wantsHTML reports whether the client asked for the server-rendered page
+func wantsHTML(c echo.Context) bool {
+	if format := c.QueryParam("format"); format != "" {
+		return format == "html"
+	}
+	return strings.Contains(c.Request().Header.Get(echo.HeaderAccept), echo.MIMETextHTML)
+}
+
+func (h *ShareHandler) toShareLinkResponse(c echo.Context, link model.ShareLink) transport.ShareLinkResponse {
+	baseURL := h.baseURL
+	if baseURL == "" {
+		baseURL = c.Scheme() + "://" + c.Request().Host
+	}
+	resp := transport.ShareLinkResponse{
+		Slug:              link.Slug,
+		URL:               baseURL + "/s/" + link.Slug,
+		ResourceType:      link.ResourceType,
+		ResourceID:        link.ResourceID,
+		PasswordProtected: link.PasswordHash != "",
+		CreatedAt:         link.CreatedAt,
+	}
+	if !link.ExpiresAt.IsZero() {
+		expiresAt := link.ExpiresAt
+		resp.ExpiresAt = &expiresAt
+	}
+	return resp
+}
+
+func toPublicShareResponse(share model.PublicShare) transport.PublicShareResponse {
+	resp := transport.PublicShareResponse{
+		ResourceType: share.ResourceType,
+		Name:         share.Name,
+		Description:  share.Description,
+		Bookmarks:    make([]transport.PublicBookmarkResponse, len(share.Bookmarks)),
+	}
+	if !share.ExpiresAt.IsZero() {
+		expiresAt := share.ExpiresAt
+		resp.ExpiresAt = &expiresAt
+	}
+	for i, b := range share.Bookmarks {
+		resp.Bookmarks[i] = transport.PublicBookmarkResponse{
+			URL:            b.URL,
+			Title:          b.Title,
+			MainImageURL:   b.MainImageURL,
+			ContentSummary: b.ContentSummary,
+			CreatedAt:      b.CreatedAt,
+		}
+	}
+	return resp
+}
@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"html/template"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/tsongpon/athena/internal/logger"
+	"github.com/tsongpon/athena/internal/model"
+	"go.uber.org/zap"
+)
+
+// sharePageTemplate renders a share link for browsers. html/template escapes every
+// value, so titles and summaries fetched from third-party pages are safe to embed.
+var sharePageTemplate = template.Must(template.New("share").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<meta name="robots" content="noindex">
+<title>{{.Title}}</title>
+{{- with .Share}}
+<meta property="og:title" content="{{.Name}}">
+{{- with .Description}}
+<meta property="og:description" content="{{.}}">
+{{- end}}
+{{- if .Bookmarks}}
+{{- with (index .Bookmarks 0).MainImageURL}}
+<meta property="og:image" content="{{.}}">
+{{- end}}
+{{- end}}
+{{- end}}
+<style>
+body { font-family: system-ui, sans-serif; max-width: 42rem; margin: 2rem auto; padding: 0 1rem; color: #222; }
+article { border-bottom: 1px solid #ddd; padding: 1rem 0; }
+article img { max-width: 100%; border-radius: 4px; }
+.muted { color: #666; }
+</style>
+</head>
+<body>
+{{- if .Share}}
+<h1>{{.Share.Name}}</h1>
+{{- with .Share.Description}}
+<p class="muted">{{.}}</p>
+{{- end}}
+{{- range .Share.Bookmarks}}
+<article>
+{{- with .MainImageURL}}
+<img src="{{.}}" alt="">
+{{- end}}
+<h2><a href="{{.URL}}" rel="noopener nofollow">{{if .Title}}{{.Title}}{{else}}{{.URL}}{{end}}</a></h2>
+{{- with .ContentSummary}}
+<p>{{.}}</p>
+{{- end}}
+<p class="muted">Saved {{.CreatedAt.Format "2 Jan 2006"}}</p>
+</article>
+{{- end}}
+{{- else if .PasswordRequired}}
+<h1>Password required</h1>
+{{- if .WrongPassword}}
+<p>That password is not correct.</p>
+{{- end}}
+<form method="post">
+<input type="password" name="password" autofocus required>
+<button type="submit">View</button>
+</form>
+{{- else}}
+<h1>{{.Title}}</h1>
+<p class="muted">{{.Message}}</p>
+{{- end}}
+</body>
+</html>
+`))
+
+type sharePageData struct {
+	Title            string
+	Message          string
+	Share            *model.PublicShare
+	PasswordRequired bool
+	WrongPassword    bool
+}
+
+func renderSharePage(c echo.Context, share model.PublicShare) error {
+	title := share.Name
+	if title == "" {
+		title = "Shared bookmarks"
+	}
+	return writeSharePage(c, http.StatusOK, sharePageData{Title: title, Share: &share})
+}
+
+// renderSharePageError renders the page for a share link that cannot be shown
+func renderSharePageError(c echo.Context, err error, passwordGiven bool) error {
+	switch {
+	case errors.Is(err, model.ErrUnauthorized):
+		return writeSharePage(c, http.StatusUnauthorized, sharePageData{Title: "Password required", PasswordRequired: true, WrongPassword: passwordGiven})
+	case errors.Is(err, model.ErrExpired):
+		return writeSharePage(c, http.StatusGone, sharePageData{Title: "Link expired", Message: "This share link has expired."})
+	case errors.Is(err, model.ErrNotFound):
+		return writeSharePage(c, http.StatusNotFound, sharePageData{Title: "Not found", Message: "This share link does not exist or has been revoked."})
+	default:
+		logger.Error("Failed to resolve share link", zap.Error(err))
+		return writeSharePage(c, http.StatusInternalServerError, sharePageData{Title: "Something went wrong", Message: "Please try again later."})
+	}
+}
+
+func writeSharePage(c echo.Context, code int, data sharePageData) error {
+	var buf bytes.Buffer
+	if err := sharePageTemplate.Execute(&buf, data); err != nil {
+		logger.Error("Failed to render share page", zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to render share page")
+	}
+	return c.HTMLBlob(code, buf.Bytes())
+}
@@ -0,0 +1,190 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/tsongpon/athena/internal/model"
+	"github.com/tsongpon/athena/internal/transport"
+)
+
+// MockShareService is a mock implementation of ShareService
+type MockShareService struct {
+	mock.Mock
+}
+
+func (m *MockShareService) CreateShareLink(userID, resourceType, resourceID, password string, expiresAt time.Time) (model.ShareLink, error) {
+	args := m.Called(userID, resourceType, resourceID, password, expiresAt)
+	return args.Get(0).(model.ShareLink), args.Error(1)
+}
+
+func (m *MockShareService) ListShareLinks(userID string) ([]model.ShareLink, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]model.ShareLink), args.Error(1)
+}
+
+func (m *MockShareService) RevokeShareLink(userID, slug string) error {
+	args := m.Called(userID, slug)
+	return args.Error(0)
+}
+
+func (m *MockShareService) ResolveShareLink(slug, password string) (model.PublicShare, error) {
+	args := m.Called(slug, password)
+	return args.Get(0).(model.PublicShare), args.Error(1)
+}
+
+func newShareViewContext(method, target string, header map[string]string) (echo.Context, *httptest.ResponseRecorder) {
+	e := echo.New()
+	req := httptest.NewRequest(method, target, nil)
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetParamNames("slug")
+	c.SetParamValues("abc")
+	return c, rec
+}
+
+var sharedBookmark = model.PublicShare{
+	ResourceType: model.ShareResourceBookmark,
+	Name:         "Example <Title>",
+	Bookmarks: []model.Bookmark{{
+		ID:             "b1",
+		UserID:         "user123",
+		URL:            "https://example.com",
+		Title:          "Example <Title>",
+		MainImageURL:   "https://example.com/og.png",
+		ContentSummary: "A summary",
+	}},
+}
+
+func TestShareHandler_ShareBookmark(t *testing.T) {
+	expiresAt := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	c, rec := newCollectionContext(http.MethodPost, "/bookmarks/b1/share", `{"password":"pw","expires_at":"2030-01-01T00:00:00Z"}`)
+	c.SetParamNames("id")
+	c.SetParamValues("b1")
+
+	mockService := new(MockShareService)
+	handler := NewShareHandler(mockService, "https://athena.example/")
+	mockService.On("CreateShareLink", "user123", model.ShareResourceBookmark, "b1", "pw", expiresAt).Return(model.ShareLink{
+		Slug:         "abc",
+		UserID:       "user123",
+		ResourceType: model.ShareResourceBookmark,
+		ResourceID:   "b1",
+		PasswordHash: "hash",
+		ExpiresAt:    expiresAt,
+	}, nil)
+
+	err := handler.ShareBookmark(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	var response transport.ShareLinkResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, "https://athena.example/s/abc", response.URL)
+	assert.True(t, response.PasswordProtected)
+	assert.NotContains(t, rec.Body.String(), "hash")
+	mockService.AssertExpectations(t)
+}
+
+func TestShareHandler_ViewShare_JSON(t *testing.T) {
+	c, rec := newShareViewContext(http.MethodGet, "/s/abc", map[string]string{SharePasswordHeader: "pw"})
+
+	mockService := new(MockShareService)
+	handler := NewShareHandler(mockService, "")
+	mockService.On("ResolveShareLink", "abc", "pw").Return(sharedBookmark, nil)
+
+	err := handler.ViewShare(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "no-store", rec.Header().Get("Cache-Control"))
+	var response transport.PublicShareResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, "Example <Title>", response.Name)
+	assert.Len(t, response.Bookmarks, 1)
+	assert.NotContains(t, rec.Body.String(), "user123")
+}
+
+func TestShareHandler_ViewShare_HTML(t *testing.T) {
+	c, rec := newShareViewContext(http.MethodGet, "/s/abc", map[string]string{echo.HeaderAccept: "text/html,application/xhtml+xml"})
+
+	mockService := new(MockShareService)
+	handler := NewShareHandler(mockService, "")
+	mockService.On("ResolveShareLink", "abc", "").Return(sharedBookmark, nil)
+
+	err := handler.ViewShare(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.True(t, strings.HasPrefix(rec.Header().Get(echo.HeaderContentType), echo.MIMETextHTML))
+	body := rec.Body.String()
+	assert.Contains(t, body, "Example &lt;Title&gt;")
+	assert.NotContains(t, body, "Example <Title>")
+	assert.Contains(t, body, `<meta property="og:image" content="https://example.com/og.png">`)
+	assert.Contains(t, body, "A summary")
+}
+
+func TestShareHandler_ViewShare_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		html       bool
+		wantStatus int
+		wantBody   string
+	}{
+		{"password required json", fmt.Errorf("needs password: %w", model.ErrUnauthorized), false, http.StatusUnauthorized, ""},
+		{"password form html", fmt.Errorf("needs password: %w", model.ErrUnauthorized), true, http.StatusUnauthorized, `type="password"`},
+		{"expired json", fmt.Errorf("expired: %w", model.ErrExpired), false, http.StatusGone, ""},
+		{"revoked html", fmt.Errorf("gone: %w", model.ErrNotFound), true, http.StatusNotFound, "revoked"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target := "/s/abc"
+			if tt.html {
+				target += "?format=html"
+			}
+			c, rec := newShareViewContext(http.MethodGet, target, nil)
+
+			mockService := new(MockShareService)
+			handler := NewShareHandler(mockService, "")
+			mockService.On("ResolveShareLink", "abc", "").Return(model.PublicShare{}, tt.err)
+
+			err := handler.ViewShare(c)
+
+			if tt.html {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.wantStatus, rec.Code)
+				assert.Contains(t, rec.Body.String(), tt.wantBody)
+				return
+			}
+			httpErr, ok := err.(*echo.HTTPError)
+			assert.True(t, ok)
+			assert.Equal(t, tt.wantStatus, httpErr.Code)
+		})
+	}
+}
+
+func TestShareHandler_RevokeShareLink(t *testing.T) {
+	c, rec := newCollectionContext(http.MethodDelete, "/shares/abc", "")
+	c.SetParamNames("slug")
+	c.SetParamValues("abc")
+
+	mockService := new(MockShareService)
+	handler := NewShareHandler(mockService, "")
+	mockService.On("RevokeShareLink", "user123", "abc").Return(nil)
+
+	err := handler.RevokeShareLink(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	mockService.AssertExpectations(t)
+}
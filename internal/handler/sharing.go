@@ -0,0 +1,164 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/tsongpon/athena/internal/model"
+	"github.com/tsongpon/athena/internal/transport"
+)
+
+type SharingHandler struct {
+	sharingService SharingService
+}
+
+func NewSharingHandler(sharingService SharingService) *SharingHandler {
+	return &SharingHandler{
+		sharingService: sharingService,
+	}
+}
+
+// InviteCollaborator invites an email address to a collection owned by the authenticated user
+func (h *SharingHandler) InviteCollaborator(c echo.Context) error {
+	req := &transport.InviteCollaboratorRequest{}
+	if err := c.Bind(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	authenticatedUser, err := getAuthenticatedUser(c)
+	if err != nil {
+		return err
+	}
+
+	invitation, err := h.sharingService.InviteCollaborator(authenticatedUser.UserID, c.Param("id"), req.Email, req.Role)
+	if err != nil {
+		return collectionError(err)
+	}
+	return c.JSON(http.StatusCreated, toInvitationResponse(invitation))
+}
+
+// ListCollectionInvitations lists every invitation sent for a collection
+func (h *SharingHandler) ListCollectionInvitations(c echo.Context) error {
+	authenticatedUser, err := getAuthenticatedUser(c)
+	if err != nil {
+		return err
+	}
+
+	invitations, err := h.sharingService.ListCollectionInvitations(authenticatedUser.UserID, c.Param("id"))
+	if err != nil {
+		return collectionError(err)
+	}
+	return c.JSON(http.StatusOK, toInvitationResponses(invitations))
+}
+
+// RevokeInvitation withdraws a pending invitation
+func (h *SharingHandler) RevokeInvitation(c echo.Context) error {
+	authenticatedUser, err := getAuthenticatedUser(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.sharingService.RevokeInvitation(authenticatedUser.UserID, c.Param("id"), c.Param("invitation_id")); err != nil {
+		return collectionError(err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// ListPendingInvitations lists invitations addressed to the authenticated user's email
+func (h *SharingHandler) ListPendingInvitations(c echo.Context) error {
+	authenticatedUser, err := getAuthenticatedUser(c)
+	if err != nil {
+		return err
+	}
+
+	invitations, err := h.sharingService.ListPendingInvitations(authenticatedUser.UserID)
+	if err != nil {
+		return collectionError(err)
+	}
+	return c.JSON(http.StatusOK, toInvitationResponses(invitations))
+}
+
+// AcceptInvitation joins the invited collection and returns it
+func (h *SharingHandler) AcceptInvitation(c echo.Context) error {
+	authenticatedUser, err := getAuthenticatedUser(c)
+	if err != nil {
+		return err
+	}
+
+	collection, err := h.sharingService.AcceptInvitation(authenticatedUser.UserID, c.Param("id"))
+	if err != nil {
+		return collectionError(err)
+	}
+	return c.JSON(http.StatusOK, toCollectionResponse(collection, authenticatedUser.UserID))
+}
+
+// DeclineInvitation declines an invitation
+func (h *SharingHandler) DeclineInvitation(c echo.Context) error {
+	authenticatedUser, err := getAuthenticatedUser(c)
+	if err != nil {
+		return err
+	}
+
+	invitation, err := h.sharingService.DeclineInvitation(authenticatedUser.UserID, c.Param("id"))
+	if err != nil {
+		return collectionError(err)
+	}
+	return c.JSON(http.StatusOK, toInvitationResponse(invitation))
+}
+
+// UpdateCollaborator changes a collaborator's role
+func (h *SharingHandler) UpdateCollaborator(c echo.Context) error {
+	req := &transport.UpdateCollaboratorRequest{}
+	if err := c.Bind(req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	authenticatedUser, err := getAuthenticatedUser(c)
+	if err != nil {
+		return err
+	}
+
+	collection, err := h.sharingService.UpdateCollaborator(authenticatedUser.UserID, c.Param("id"), c.Param("user_id"), req.Role)
+	if err != nil {
+		return collectionError(err)
+	}
+	return c.JSON(http.StatusOK, toCollectionResponse(collection, authenticatedUser.UserID))
+}
+
+// RemoveCollaborator removes a collaborator, or lets a collaborator leave when user_id is their own
+func (h *SharingHandler) RemoveCollaborator(c echo.Context) error {
+	authenticatedUser, err := getAuthenticatedUser(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.sharingService.RemoveCollaborator(authenticatedUser.UserID, c.Param("id"), c.Param("user_id")); err != nil {
+		return collectionError(err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+func toInvitationResponse(invitation model.Invitation) transport.InvitationResponse {
+	resp := transport.InvitationResponse{
+		ID:           invitation.ID,
+		CollectionID: invitation.CollectionID,
+		InviterID:    invitation.InviterID,
+		Email:        invitation.Email,
+		Role:         invitation.Role,
+		Status:       invitation.Status,
+		CreatedAt:    invitation.CreatedAt,
+	}
+	if !invitation.RespondedAt.IsZero() {
+		respondedAt := invitation.RespondedAt
+		resp.RespondedAt = &respondedAt
+	}
+	return resp
+}
+
+func toInvitationResponses(invitations []model.Invitation) []transport.InvitationResponse {
+	resp := make([]transport.InvitationResponse, len(invitations))
+	for i, invitation := range invitations {
+		resp[i] = toInvitationResponse(invitation)
+	}
+	return resp
+}
@@ -0,0 +1,157 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/tsongpon/athena/internal/model"
+	"github.com/tsongpon/athena/internal/transport"
+)
+
+// MockSharingService is a mock implementation of SharingService
+type MockSharingService struct {
+	mock.Mock
+}
+
+func (m *MockSharingService) InviteCollaborator(userID, collectionID, email, role string) (model.Invitation, error) {
+	args := m.Called(userID, collectionID, email, role)
+	return args.Get(0).(model.Invitation), args.Error(1)
+}
+
+func (m *MockSharingService) ListCollectionInvitations(userID, collectionID string) ([]model.Invitation, error) {
+	args := m.Called(userID, collectionID)
+	return args.Get(0).([]model.Invitation), args.Error(1)
+}
+
+func (m *MockSharingService) RevokeInvitation(userID, collectionID, invitationID string) error {
+	args := m.Called(userID, collectionID, invitationID)
+	return args.Error(0)
+}
+
+func (m *MockSharingService) ListPendingInvitations(userID string) ([]model.Invitation, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]model.Invitation), args.Error(1)
+}
+
+func (m *MockSharingService) AcceptInvitation(userID, invitationID string) (model.Collection, error) {
+	args := m.Called(userID, invitationID)
+	return args.Get(0).(model.Collection), args.Error(1)
+}
+
+func (m *MockSharingService) DeclineInvitation(userID, invitationID string) (model.Invitation, error) {
+	args := m.Called(userID, invitationID)
+	return args.Get(0).(model.Invitation), args.Error(1)
+}
+
+func (m *MockSharingService) UpdateCollaborator(userID, collectionID, collaboratorID, role string) (model.Collection, error) {
+	args := m.Called(userID, collectionID, collaboratorID, role)
+	return args.Get(0).(model.Collection), args.Error(1)
+}
+
+func (m *MockSharingService) RemoveCollaborator(userID, collectionID, collaboratorID string) error {
+	args := m.Called(userID, collectionID, collaboratorID)
+	return args.Error(0)
+}
+
+func TestSharingHandler_InviteCollaborator(t *testing.T) {
+	c, rec := newCollectionContext(http.MethodPost, "/collections/c1/invitations", `{"email":"friend@example.com","role":"editor"}`)
+	c.SetParamNames("id")
+	c.SetParamValues("c1")
+
+	mockService := new(MockSharingService)
+	handler := NewSharingHandler(mockService)
+	mockService.On("InviteCollaborator", "user123", "c1", "friend@example.com", "editor").Return(model.Invitation{
+		ID:           "inv-1",
+		CollectionID: "c1",
+		InviterID:    "user123",
+		Email:        "friend@example.com",
+		Role:         "editor",
+		Status:       model.InvitationPending,
+		CreatedAt:    time.Now(),
+	}, nil)
+
+	err := handler.InviteCollaborator(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	var response transport.InvitationResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, "inv-1", response.ID)
+	assert.Equal(t, model.InvitationPending, response.Status)
+	assert.Nil(t, response.RespondedAt)
+	mockService.AssertExpectations(t)
+}
+
+func TestSharingHandler_InviteCollaborator_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+	}{
+		{"duplicate invitation", fmt.Errorf("already invited: %w", model.ErrConflict), http.StatusConflict},
+		{"not the owner", fmt.Errorf("cannot manage: %w", model.ErrForbidden), http.StatusForbidden},
+		{"invalid role", fmt.Errorf("unknown role: %w", model.ErrInvalidInput), http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := newCollectionContext(http.MethodPost, "/collections/c1/invitations", `{"email":"friend@example.com","role":"editor"}`)
+			c.SetParamNames("id")
+			c.SetParamValues("c1")
+
+			mockService := new(MockSharingService)
+			handler := NewSharingHandler(mockService)
+			mockService.On("InviteCollaborator", "user123", "c1", "friend@example.com", "editor").Return(model.Invitation{}, tt.err)
+
+			err := handler.InviteCollaborator(c)
+
+			httpErr, ok := err.(*echo.HTTPError)
+			assert.True(t, ok)
+			assert.Equal(t, tt.wantStatus, httpErr.Code)
+		})
+	}
+}
+
+func TestSharingHandler_AcceptInvitation(t *testing.T) {
+	c, rec := newCollectionContext(http.MethodPost, "/invitations/inv-1/accept", "")
+	c.SetParamNames("id")
+	c.SetParamValues("inv-1")
+
+	mockService := new(MockSharingService)
+	handler := NewSharingHandler(mockService)
+	mockService.On("AcceptInvitation", "user123", "inv-1").Return(model.Collection{
+		ID:            "c1",
+		UserID:        "owner-1",
+		Name:          "Team",
+		Collaborators: []model.Collaborator{{UserID: "user123", Role: model.CollectionRoleViewer}},
+	}, nil)
+
+	err := handler.AcceptInvitation(c)
+
+	assert.NoError(t, err)
+	var response transport.CollectionResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, "c1", response.ID)
+	assert.Equal(t, model.CollectionRoleViewer, response.Role)
+}
+
+func TestSharingHandler_RemoveCollaborator(t *testing.T) {
+	c, rec := newCollectionContext(http.MethodDelete, "/collections/c1/collaborators/user123", "")
+	c.SetParamNames("id", "user_id")
+	c.SetParamValues("c1", "user123")
+
+	mockService := new(MockSharingService)
+	handler := NewSharingHandler(mockService)
+	mockService.On("RemoveCollaborator", "user123", "c1", "user123").Return(nil)
+
+	err := handler.RemoveCollaborator(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	mockService.AssertExpectations(t)
+}
@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/tsongpon/athena/internal/logger"
+	"github.com/tsongpon/athena/internal/model"
+	"go.uber.org/zap"
+)
+
+// snapshotContentSecurityPolicy sandboxes archived pages: they are third party HTML served
+// from our origin, so they must not run scripts, load remote content or submit forms
+const snapshotContentSecurityPolicy = "default-src 'none'; img-src data:; style-src 'unsafe-inline'; font-src data:; form-action 'none'; sandbox"
+
+type SnapshotHandler struct {
+	snapshotService SnapshotService
+}
+
+func NewSnapshotHandler(snapshotService SnapshotService) *SnapshotHandler {
+	return &SnapshotHandler{
+		snapshotService: snapshotService,
+	}
+}
+
+// GetSnapshot serves the offline snapshot of a bookmarked page
+func (h *SnapshotHandler) GetSnapshot(c echo.Context) error {
+	authenticatedUser, err := getAuthenticatedUser(c)
+	if err != nil {
+		return err
+	}
+
+	snapshot, err := h.snapshotService.GetSnapshot(authenticatedUser.UserID, c.Param("id"))
+	if errors.Is(err, model.ErrNotFound) {
+		return echo.NewHTTPError(http.StatusNotFound, "Snapshot not found")
+	}
+	if errors.Is(err, model.ErrForbidden) {
+		return echo.NewHTTPError(http.StatusForbidden, "Access denied")
+	}
+	if err != nil {
+		logger.Error("Failed to get snapshot", zap.String("bookmark_id", c.Param("id")), zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get snapshot")
+	}
+
+	sum := sha256.Sum256(snapshot.Data)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+	lastModified := snapshot.UpdatedAt.UTC().Truncate(time.Second)
+
+	header := c.Response().Header()
+	header.Set("Content-Security-Policy", snapshotContentSecurityPolicy)
+	header.Set("X-Content-Type-Options", "nosniff")
+	header.Set("Referrer-Policy", "no-referrer")
+	header.Set("ETag", etag)
+	header.Set("Last-Modified", lastModified.Format(http.TimeFormat))
+	header.Set("Cache-Control", "private, no-cache")
+
+	if notModified(c.Request(), etag, lastModified) {
+		return c.NoContent(http.StatusNotModified)
+	}
+	return c.Blob(http.StatusOK, "text/html; charset=utf-8", snapshot.Data)
+}
@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/tsongpon/athena/internal/model"
+)
+
+// MockSnapshotService is a mock implementation of SnapshotService
+type MockSnapshotService struct {
+	mock.Mock
+}
+
+func (m *MockSnapshotService) GetSnapshot(userID, bookmarkID string) (model.Blob, error) {
+	args := m.Called(userID, bookmarkID)
+	return args.Get(0).(model.Blob), args.Error(1)
+}
+
+func newSnapshotContext(header map[string]string) (echo.Context, *httptest.ResponseRecorder) {
+	c, rec := newCollectionContext(http.MethodGet, "/bookmarks/b1/archive.html", "")
+	for k, v := range header {
+		c.Request().Header.Set(k, v)
+	}
+	c.SetParamNames("id")
+	c.SetParamValues("b1")
+	return c, rec
+}
+
+func TestSnapshotHandler_GetSnapshot(t *testing.T) {
+	mockService := new(MockSnapshotService)
+	handler := NewSnapshotHandler(mockService)
+	mockService.On("GetSnapshot", "user123", "b1").Return(model.Blob{
+		Key:       "snapshots/b1.html",
+		Data:      []byte("<html><body>Saved</body></html>"),
+		UpdatedAt: time.Date(2025, 11, 15, 10, 30, 45, 0, time.UTC),
+	}, nil)
+
+	c, rec := newSnapshotContext(nil)
+	err := handler.GetSnapshot(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "text/html; charset=utf-8", rec.Header().Get(echo.HeaderContentType))
+	assert.Contains(t, rec.Header().Get("Content-Security-Policy"), "sandbox")
+	assert.Equal(t, "nosniff", rec.Header().Get("X-Content-Type-Options"))
+	assert.Equal(t, "Sat, 15 Nov 2025 10:30:45 GMT", rec.Header().Get("Last-Modified"))
+	assert.Equal(t, "<html><body>Saved</body></html>", rec.Body.String())
+
+	c, rec = newSnapshotContext(map[string]string{"If-None-Match": rec.Header().Get("ETag")})
+	assert.NoError(t, handler.GetSnapshot(c))
+	assert.Equal(t, http.StatusNotModified, rec.Code)
+}
+
+func TestSnapshotHandler_GetSnapshot_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+	}{
+		{"no snapshot", fmt.Errorf("bookmark b1 has no snapshot: %w", model.ErrNotFound), http.StatusNotFound},
+		{"not allowed", fmt.Errorf("cannot view: %w", model.ErrForbidden), http.StatusForbidden},
+		{"storage failure", fmt.Errorf("failed to download blob"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService := new(MockSnapshotService)
+			handler := NewSnapshotHandler(mockService)
+			mockService.On("GetSnapshot", "user123", "b1").Return(model.Blob{}, tt.err)
+
+			c, _ := newSnapshotContext(nil)
+			err := handler.GetSnapshot(c)
+
+			httpErr, ok := err.(*echo.HTTPError)
+			assert.True(t, ok)
+			assert.Equal(t, tt.wantStatus, httpErr.Code)
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/tsongpon/athena/internal/logger"
+	"github.com/tsongpon/athena/internal/model"
+	"github.com/tsongpon/athena/internal/transport"
+	"go.uber.org/zap"
+)
+
+type SyncHandler struct {
+	syncService SyncService
+}
+
+func NewSyncHandler(syncService SyncService) *SyncHandler {
+	return &SyncHandler{
+		syncService: syncService,
+	}
+}
+
+// GetChanges returns the bookmarks created, updated and deleted since the ?since= token of the
+// previous pull, or every bookmark when it is omitted
+func (h *SyncHandler) GetChanges(c echo.Context) error {
+	authenticatedUser, err := getAuthenticatedUser(c)
+	if err != nil {
+		return err
+	}
+
+	delta, err := h.syncService.GetChanges(authenticatedUser.UserID, c.QueryParam("since"))
+	if err != nil {
+		return syncError(err)
+	}
+
+	resp := transport.SyncResponse{
+		Created: make([]transport.BookmarkTransport, len(delta.Created)),
+		Updated: make([]transport.BookmarkTransport, len(delta.Updated)),
+		Deleted: delta.Deleted,
+		Token:   delta.Token,
+		HasMore: delta.HasMore,
+	}
+	for i, b := range delta.Created {
+		resp.Created[i] = toBookmarkTransport(b)
+	}
+	for i, b := range delta.Updated {
+		resp.Updated[i] = toBookmarkTransport(b)
+	}
+	if resp.Deleted == nil {
+		resp.Deleted = []string{}
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// PushChanges applies the edits a client made offline. It answers 200 even when some of them
+// are not applied, the status of each is in its result.
+func (h *SyncHandler) PushChanges(c echo.Context) error {
+	authenticatedUser, err := getAuthenticatedUser(c)
+	if err != nil {
+		return err
+	}
+
+	var req transport.SyncPushRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	edits := make([]model.SyncEdit, len(req.Changes))
+	for i, change := range req.Changes {
+		edits[i] = model.SyncEdit{
+			BookmarkID:      change.BookmarkID,
+			Delete:          change.Deleted,
+			IsArchived:      change.IsArchived,
+			IsRead:          change.IsRead,
+			IsFavorite:      change.IsFavorite,
+			ReadingProgress: change.ReadingProgress,
+			Notes:           change.Notes,
+			Tags:            change.Tags,
+		}
+		if change.BaseUpdatedAt != nil {
+			edits[i].BaseUpdatedAt = *change.BaseUpdatedAt
+		}
+		if change.EditedAt != nil {
+			edits[i].EditedAt = *change.EditedAt
+		}
+	}
+
+	results, err := h.syncService.PushChanges(authenticatedUser.UserID, edits)
+	if err != nil {
+		return syncError(err)
+	}
+
+	resp := transport.SyncPushResponse{Results: make([]transport.SyncResultTransport, len(results))}
+	for i, result := range results {
+		resp.Results[i] = transport.SyncResultTransport{
+			Index:      i,
+			BookmarkID: result.BookmarkID,
+			Status:     result.Status,
+			Conflicted: result.Conflicted,
+			Error:      result.Error,
+		}
+		if result.Bookmark.ID != "" {
+			b := toBookmarkTransport(result.Bookmark)
+			resp.Results[i].Bookmark = &b
+		}
+	}
+	return c.JSON(http.StatusOK, resp)
+}
+
+// syncError maps the errors of the sync service to HTTP errors
+func syncError(err error) error {
+	switch {
+	case errors.Is(err, model.ErrInvalidInput):
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	case errors.Is(err, model.ErrExpired):
+		return echo.NewHTTPError(http.StatusGone, "Sync token is no longer valid, sync again without since")
+	default:
+		logger.Error("Sync failed", zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "Sync failed")
+	}
+}
@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/tsongpon/athena/internal/model"
+	"github.com/tsongpon/athena/internal/transport"
+)
+
+// MockSyncService is a mock implementation of SyncService
+type MockSyncService struct {
+	mock.Mock
+}
+
+func (m *MockSyncService) GetChanges(userID, token string) (model.SyncDelta, error) {
+	args := m.Called(userID, token)
+	return args.Get(0).(model.SyncDelta), args.Error(1)
+}
+
+func (m *MockSyncService) PushChanges(userID string, edits []model.SyncEdit) ([]model.SyncResult, error) {
+	args := m.Called(userID, edits)
+	results, _ := args.Get(0).([]model.SyncResult)
+	return results, args.Error(1)
+}
+
+func TestSyncHandler_GetChanges(t *testing.T) {
+	c, rec := newCollectionContext(http.MethodGet, "/sync?since=12", "")
+
+	mockService := new(MockSyncService)
+	handler := NewSyncHandler(mockService)
+	mockService.On("GetChanges", "user123", "12").Return(model.SyncDelta{
+		Updated: []model.Bookmark{{ID: "b1", URL: "https://example.com"}},
+		Token:   "15",
+		HasMore: true,
+	}, nil)
+
+	err := handler.GetChanges(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var response transport.SyncResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, []transport.BookmarkTransport{}, response.Created)
+	assert.Len(t, response.Updated, 1)
+	assert.Equal(t, []string{}, response.Deleted)
+	assert.Equal(t, "15", response.Token)
+	assert.True(t, response.HasMore)
+	mockService.AssertExpectations(t)
+}
+
+func TestSyncHandler_PushChanges(t *testing.T) {
+	c, rec := newCollectionContext(http.MethodPost, "/sync", `{"changes":[
+		{"bookmark_id":"b1","base_updated_at":"2025-11-01T10:00:00Z","is_read":true},
+		{"bookmark_id":"b2","deleted":true}]}`)
+
+	mockService := new(MockSyncService)
+	handler := NewSyncHandler(mockService)
+	read := true
+	base := time.Date(2025, 11, 1, 10, 0, 0, 0, time.UTC)
+	mockService.On("PushChanges", "user123", []model.SyncEdit{
+		{BookmarkID: "b1", BaseUpdatedAt: base, IsRead: &read},
+		{BookmarkID: "b2", Delete: true},
+	}).Return([]model.SyncResult{
+		{BookmarkID: "b1", Status: model.SyncStatusConflict, Conflicted: true, Bookmark: model.Bookmark{ID: "b1"}},
+		{BookmarkID: "b2", Status: model.SyncStatusApplied},
+	}, nil)
+
+	err := handler.PushChanges(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var response transport.SyncPushResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Len(t, response.Results, 2)
+	assert.True(t, response.Results[0].Conflicted)
+	assert.Equal(t, "b1", response.Results[0].Bookmark.ID)
+	assert.Equal(t, 1, response.Results[1].Index)
+	assert.Nil(t, response.Results[1].Bookmark)
+	mockService.AssertExpectations(t)
+}
+
+func TestSyncHandler_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		code int
+	}{
+		{"invalid", fmt.Errorf("invalid sync token: %w", model.ErrInvalidInput), http.StatusBadRequest},
+		{"expired", model.ErrExpired, http.StatusGone},
+		{"failed", fmt.Errorf("firestore unavailable"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := newCollectionContext(http.MethodGet, "/sync?since=99", "")
+
+			mockService := new(MockSyncService)
+			handler := NewSyncHandler(mockService)
+			mockService.On("GetChanges", "user123", "99").Return(model.SyncDelta{}, tt.err)
+
+			err := handler.GetChanges(c)
+
+			httpErr, ok := err.(*echo.HTTPError)
+			assert.True(t, ok)
+			assert.Equal(t, tt.code, httpErr.Code)
+		})
+	}
+}
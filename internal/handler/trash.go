@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/tsongpon/athena/internal/logger"
+	"github.com/tsongpon/athena/internal/transport"
+	"go.uber.org/zap"
+)
+
+type TrashHandler struct {
+	trashService TrashService
+}
+
+func NewTrashHandler(trashService TrashService) *TrashHandler {
+	return &TrashHandler{
+		trashService: trashService,
+	}
+}
+
+// ListTrash returns the deleted bookmarks of the user, most recently deleted first
+func (h *TrashHandler) ListTrash(c echo.Context) error {
+	authenticatedUser, err := getAuthenticatedUser(c)
+	if err != nil {
+		return err
+	}
+
+	bookmarks, err := h.trashService.ListTrash(authenticatedUser.UserID)
+	if err != nil {
+		logger.Error("Failed to list trash", zap.String("user_id", authenticatedUser.UserID), zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to list trash")
+	}
+
+	ts := make([]transport.BookmarkTransport, len(bookmarks))
+	for i, b := range bookmarks {
+		ts[i] = toBookmarkTransport(b)
+	}
+	return c.JSON(http.StatusOK, ts)
+}
+
+// RestoreBookmark moves a bookmark out of the trash
+func (h *TrashHandler) RestoreBookmark(c echo.Context) error {
+	id := c.Param("id")
+	if id == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "ID is required")
+	}
+
+	authenticatedUser, err := getAuthenticatedUser(c)
+	if err != nil {
+		return err
+	}
+
+	// Only the owner can restore a bookmark, and it counts against their bookmark quota again
+	bookmark, err := h.trashService.RestoreBookmark(authenticatedUser.UserID, id)
+	if httpErr := entitlementError(err); httpErr != nil {
+		return httpErr
+	}
+	if err != nil {
+		return bookmarkError(err)
+	}
+	return c.JSON(http.StatusOK, toBookmarkTransport(bookmark))
+}
+
+// EmptyTrash permanently deletes every bookmark in the trash of the user
+func (h *TrashHandler) EmptyTrash(c echo.Context) error {
+	authenticatedUser, err := getAuthenticatedUser(c)
+	if err != nil {
+		return err
+	}
+
+	purged, err := h.trashService.EmptyTrash(authenticatedUser.UserID)
+	if err != nil {
+		logger.Error("Failed to empty trash", zap.String("user_id", authenticatedUser.UserID), zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to empty trash")
+	}
+	return c.JSON(http.StatusOK, transport.EmptyTrashResponse{Purged: purged})
+}
@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/tsongpon/athena/internal/model"
+)
+
+// MockTrashService is a mock implementation of TrashService
+type MockTrashService struct {
+	mock.Mock
+}
+
+func (m *MockTrashService) ListTrash(userID string) ([]model.Bookmark, error) {
+	args := m.Called(userID)
+	bookmarks, _ := args.Get(0).([]model.Bookmark)
+	return bookmarks, args.Error(1)
+}
+
+func (m *MockTrashService) RestoreBookmark(userID, id string) (model.Bookmark, error) {
+	args := m.Called(userID, id)
+	return args.Get(0).(model.Bookmark), args.Error(1)
+}
+
+func (m *MockTrashService) EmptyTrash(userID string) (int, error) {
+	args := m.Called(userID)
+	return args.Int(0), args.Error(1)
+}
+
+func TestTrashHandler_ListTrash(t *testing.T) {
+	c, rec := newCollectionContext(http.MethodGet, "/trash", "")
+
+	deletedAt := time.Date(2025, 11, 15, 10, 30, 0, 0, time.UTC)
+	mockService := new(MockTrashService)
+	handler := NewTrashHandler(mockService)
+	mockService.On("ListTrash", "user123").
+		Return([]model.Bookmark{{ID: "b1", UserID: "user123", URL: "https://example.com", DeletedAt: deletedAt}}, nil)
+
+	err := handler.ListTrash(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"deleted_at":"2025-11-15T10:30:00Z"`)
+	mockService.AssertExpectations(t)
+}
+
+func TestTrashHandler_RestoreBookmark(t *testing.T) {
+	c, rec := newCollectionContext(http.MethodPost, "/trash/b1/restore", "")
+	c.SetParamNames("id")
+	c.SetParamValues("b1")
+
+	mockService := new(MockTrashService)
+	handler := NewTrashHandler(mockService)
+	mockService.On("RestoreBookmark", "user123", "b1").
+		Return(model.Bookmark{ID: "b1", UserID: "user123", URL: "https://example.com"}, nil)
+
+	err := handler.RestoreBookmark(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.NotContains(t, rec.Body.String(), "deleted_at")
+	mockService.AssertExpectations(t)
+}
+
+func TestTrashHandler_RestoreBookmark_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{name: "not in the trash", err: model.ErrNotFound, want: http.StatusNotFound},
+		{name: "another user's bookmark", err: model.ErrForbidden, want: http.StatusForbidden},
+		{name: "over quota", err: fmt.Errorf("free tier allows 200 bookmarks: %w", model.ErrQuotaExceeded), want: http.StatusPaymentRequired},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := newCollectionContext(http.MethodPost, "/trash/b1/restore", "")
+			c.SetParamNames("id")
+			c.SetParamValues("b1")
+
+			mockService := new(MockTrashService)
+			handler := NewTrashHandler(mockService)
+			mockService.On("RestoreBookmark", "user123", "b1").Return(model.Bookmark{}, tt.err)
+
+			err := handler.RestoreBookmark(c)
+
+			httpErr, ok := err.(*echo.HTTPError)
+			assert.True(t, ok)
+			assert.Equal(t, tt.want, httpErr.Code)
+		})
+	}
+}
+
+func TestTrashHandler_EmptyTrash(t *testing.T) {
+	c, rec := newCollectionContext(http.MethodDelete, "/trash", "")
+
+	mockService := new(MockTrashService)
+	handler := NewTrashHandler(mockService)
+	mockService.On("EmptyTrash", "user123").Return(3, nil)
+
+	err := handler.EmptyTrash(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"purged":3}`, rec.Body.String())
+	mockService.AssertExpectations(t)
+}
@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/tsongpon/athena/internal/logger"
+	"github.com/tsongpon/athena/internal/model"
+	"github.com/tsongpon/athena/internal/transport"
+	"go.uber.org/zap"
+)
+
+type UsageHandler struct {
+	entitlementService EntitlementService
+}
+
+func NewUsageHandler(entitlementService EntitlementService) *UsageHandler {
+	return &UsageHandler{
+		entitlementService: entitlementService,
+	}
+}
+
+// GetUsage returns the authenticated user's usage against their tier limits
+func (h *UsageHandler) GetUsage(c echo.Context) error {
+	authenticatedUser, err := getAuthenticatedUser(c)
+	if err != nil {
+		return err
+	}
+
+	usage, err := h.entitlementService.GetUsage(authenticatedUser.UserID)
+	if err != nil {
+		logger.Error("Failed to get usage", zap.String("user_id", authenticatedUser.UserID), zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to get usage")
+	}
+
+	return c.JSON(http.StatusOK, transport.UsageResponse{
+		Tier: usage.Tier,
+		Limits: transport.EntitlementsResponse{
+			MaxBookmarks:      usage.Entitlements.MaxBookmarks,
+			SummariesPerMonth: usage.Entitlements.SummariesPerMonth,
+			ImportsPerDay:     usage.Entitlements.ImportsPerDay,
+			MaxAPITokens:      usage.Entitlements.MaxAPITokens,
+			SemanticSearch:    usage.Entitlements.SemanticSearch,
+		},
+		Bookmarks:          usage.Bookmarks,
+		SummariesThisMonth: usage.SummariesThisMonth,
+		ImportsToday:       usage.ImportsToday,
+	})
+}
+
+// entitlementError maps tier limit errors to 402 Payment Required and missing
+// features to 403 Forbidden. It returns nil for any other error.
+func entitlementError(err error) error {
+	switch {
+	case errors.Is(err, model.ErrQuotaExceeded):
+		return echo.NewHTTPError(http.StatusPaymentRequired, err.Error())
+	case errors.Is(err, model.ErrFeatureNotAvailable):
+		return echo.NewHTTPError(http.StatusForbidden, err.Error())
+	default:
+		return nil
+	}
+}
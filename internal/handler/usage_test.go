@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/tsongpon/athena/internal/model"
+	"github.com/tsongpon/athena/internal/transport"
+)
+
+// MockEntitlementService is a mock implementation of EntitlementService
+type MockEntitlementService struct {
+	mock.Mock
+}
+
+func (m *MockEntitlementService) GetUsage(userID string) (model.Usage, error) {
+	args := m.Called(userID)
+	return args.Get(0).(model.Usage), args.Error(1)
+}
+
+func TestUsageHandler_GetUsage(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/me/usage", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.Set("user", &JWTClaims{UserID: "user123"})
+
+	mockService := new(MockEntitlementService)
+	handler := NewUsageHandler(mockService)
+
+	mockService.On("GetUsage", "user123").Return(model.Usage{
+		Tier: model.TierFree,
+		Entitlements: model.Entitlements{
+			MaxBookmarks:  200,
+			ImportsPerDay: 1,
+			MaxAPITokens:  1,
+		},
+		Bookmarks:    42,
+		ImportsToday: 1,
+	}, nil)
+
+	err := handler.GetUsage(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var response transport.UsageResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, model.TierFree, response.Tier)
+	assert.Equal(t, 200, response.Limits.MaxBookmarks)
+	assert.Equal(t, 42, response.Bookmarks)
+	assert.Equal(t, 1, response.ImportsToday)
+	assert.False(t, response.Limits.SemanticSearch)
+}
+
+func TestUsageHandler_GetUsage_Unauthenticated(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/me/usage", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	handler := NewUsageHandler(new(MockEntitlementService))
+
+	err := handler.GetUsage(c)
+
+	httpErr, ok := err.(*echo.HTTPError)
+	assert.True(t, ok)
+	assert.Equal(t, http.StatusUnauthorized, httpErr.Code)
+}
+
+func TestEntitlementError(t *testing.T) {
+	quota := entitlementError(errors.Join(errors.New("limit"), model.ErrQuotaExceeded))
+	assert.Equal(t, http.StatusPaymentRequired, quota.(*echo.HTTPError).Code)
+
+	feature := entitlementError(errors.Join(errors.New("semantic search"), model.ErrFeatureNotAvailable))
+	assert.Equal(t, http.StatusForbidden, feature.(*echo.HTTPError).Code)
+
+	assert.Nil(t, entitlementError(errors.New("database error")))
+	assert.Nil(t, entitlementError(nil))
+}
@@ -0,0 +1,175 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/tsongpon/athena/internal/logger"
+	"github.com/tsongpon/athena/internal/model"
+	"github.com/tsongpon/athena/internal/transport"
+	"go.uber.org/zap"
+)
+
+type WebhookHandler struct {
+	webhookService WebhookService
+}
+
+func NewWebhookHandler(webhookService WebhookService) *WebhookHandler {
+	return &WebhookHandler{
+		webhookService: webhookService,
+	}
+}
+
+// CreateWebhook registers a webhook. The response is the only one that includes its secret.
+func (h *WebhookHandler) CreateWebhook(c echo.Context) error {
+	authenticatedUser, err := getAuthenticatedUser(c)
+	if err != nil {
+		return err
+	}
+
+	var req transport.CreateWebhookRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	webhook, err := h.webhookService.CreateWebhook(authenticatedUser.UserID, req.URL, req.Events)
+	if err != nil {
+		return webhookError(err)
+	}
+	t := toWebhookTransport(webhook)
+	t.Secret = webhook.Secret
+	return c.JSON(http.StatusCreated, t)
+}
+
+// ListWebhooks returns the webhooks of the user, oldest first
+func (h *WebhookHandler) ListWebhooks(c echo.Context) error {
+	authenticatedUser, err := getAuthenticatedUser(c)
+	if err != nil {
+		return err
+	}
+
+	webhooks, err := h.webhookService.ListWebhooks(authenticatedUser.UserID)
+	if err != nil {
+		return webhookError(err)
+	}
+	ts := make([]transport.WebhookTransport, len(webhooks))
+	for i, webhook := range webhooks {
+		ts[i] = toWebhookTransport(webhook)
+	}
+	return c.JSON(http.StatusOK, ts)
+}
+
+// UpdateWebhook changes the URL or event types of a webhook, or turns it on or off
+func (h *WebhookHandler) UpdateWebhook(c echo.Context) error {
+	authenticatedUser, err := getAuthenticatedUser(c)
+	if err != nil {
+		return err
+	}
+
+	var req transport.UpdateWebhookRequest
+	if err := c.Bind(&req); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid request body")
+	}
+
+	update := model.WebhookUpdate{URL: req.URL, Events: req.Events, Active: req.Active}
+	webhook, err := h.webhookService.UpdateWebhook(authenticatedUser.UserID, c.Param("id"), update)
+	if err != nil {
+		return webhookError(err)
+	}
+	return c.JSON(http.StatusOK, toWebhookTransport(webhook))
+}
+
+// DeleteWebhook deletes a webhook and its delivery log
+func (h *WebhookHandler) DeleteWebhook(c echo.Context) error {
+	authenticatedUser, err := getAuthenticatedUser(c)
+	if err != nil {
+		return err
+	}
+
+	if err := h.webhookService.DeleteWebhook(authenticatedUser.UserID, c.Param("id")); err != nil {
+		return webhookError(err)
+	}
+	return c.NoContent(http.StatusNoContent)
+}
+
+// ListDeliveries returns the delivery log of a webhook, newest first. Accepts ?limit= (default 50, max 100).
+func (h *WebhookHandler) ListDeliveries(c echo.Context) error {
+	authenticatedUser, err := getAuthenticatedUser(c)
+	if err != nil {
+		return err
+	}
+	limit, err := strconv.Atoi(c.QueryParam("limit"))
+	if err != nil {
+		limit = 0
+	}
+
+	deliveries, err := h.webhookService.ListDeliveries(authenticatedUser.UserID, c.Param("id"), limit)
+	if err != nil {
+		return webhookError(err)
+	}
+	ts := make([]transport.WebhookDeliveryTransport, len(deliveries))
+	for i, delivery := range deliveries {
+		ts[i] = toWebhookDeliveryTransport(delivery)
+	}
+	return c.JSON(http.StatusOK, ts)
+}
+
+// webhookError maps the errors of the webhook service to HTTP errors
+func webhookError(err error) error {
+	switch {
+	case errors.Is(err, model.ErrInvalidInput):
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	case errors.Is(err, model.ErrForbidden):
+		return echo.NewHTTPError(http.StatusForbidden, "Access denied")
+	case errors.Is(err, model.ErrNotFound):
+		return echo.NewHTTPError(http.StatusNotFound, "Webhook not found")
+	default:
+		logger.Error("Webhook operation failed", zap.Error(err))
+		return echo.NewHTTPError(http.StatusInternalServerError, "Webhook operation failed")
+	}
+}
+
+func toWebhookTransport(webhook model.Webhook) transport.WebhookTransport {
+	events := webhook.Events
+	if events == nil {
+		events = []string{}
+	}
+	t := transport.WebhookTransport{
+		ID:                  webhook.ID,
+		URL:                 webhook.URL,
+		Events:              events,
+		Active:              webhook.Active,
+		ConsecutiveFailures: webhook.ConsecutiveFailures,
+		CreatedAt:           webhook.CreatedAt,
+		UpdatedAt:           webhook.UpdatedAt,
+	}
+	if !webhook.DisabledAt.IsZero() {
+		disabledAt := webhook.DisabledAt
+		t.DisabledAt = &disabledAt
+	}
+	return t
+}
+
+func toWebhookDeliveryTransport(delivery model.WebhookDelivery) transport.WebhookDeliveryTransport {
+	t := transport.WebhookDeliveryTransport{
+		ID:             delivery.ID,
+		EventID:        delivery.EventID,
+		EventType:      delivery.EventType,
+		Status:         delivery.Status,
+		Attempts:       delivery.Attempts,
+		ResponseStatus: delivery.ResponseStatus,
+		Error:          delivery.Error,
+		CreatedAt:      delivery.CreatedAt,
+	}
+	if delivery.Status == model.WebhookDeliveryPending {
+		nextAttemptAt := delivery.NextAttemptAt
+		t.NextAttemptAt = &nextAttemptAt
+	}
+	if !delivery.LastAttemptAt.IsZero() {
+		lastAttemptAt := delivery.LastAttemptAt
+		t.LastAttemptAt = &lastAttemptAt
+	}
+	return t
+}
@@ -0,0 +1,165 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/tsongpon/athena/internal/model"
+	"github.com/tsongpon/athena/internal/transport"
+)
+
+// MockWebhookService is a mock implementation of WebhookService
+type MockWebhookService struct {
+	mock.Mock
+}
+
+func (m *MockWebhookService) CreateWebhook(userID, url string, events []string) (model.Webhook, error) {
+	args := m.Called(userID, url, events)
+	return args.Get(0).(model.Webhook), args.Error(1)
+}
+
+func (m *MockWebhookService) ListWebhooks(userID string) ([]model.Webhook, error) {
+	args := m.Called(userID)
+	webhooks, _ := args.Get(0).([]model.Webhook)
+	return webhooks, args.Error(1)
+}
+
+func (m *MockWebhookService) UpdateWebhook(userID, id string, update model.WebhookUpdate) (model.Webhook, error) {
+	args := m.Called(userID, id, update)
+	return args.Get(0).(model.Webhook), args.Error(1)
+}
+
+func (m *MockWebhookService) DeleteWebhook(userID, id string) error {
+	args := m.Called(userID, id)
+	return args.Error(0)
+}
+
+func (m *MockWebhookService) ListDeliveries(userID, webhookID string, limit int) ([]model.WebhookDelivery, error) {
+	args := m.Called(userID, webhookID, limit)
+	deliveries, _ := args.Get(0).([]model.WebhookDelivery)
+	return deliveries, args.Error(1)
+}
+
+func TestWebhookHandler_CreateWebhook(t *testing.T) {
+	c, rec := newCollectionContext(http.MethodPost, "/webhooks", `{"url":"https://hooks.example.com","events":["bookmark.created"]}`)
+
+	mockService := new(MockWebhookService)
+	handler := NewWebhookHandler(mockService)
+	mockService.On("CreateWebhook", "user123", "https://hooks.example.com", []string{model.EventBookmarkCreated}).
+		Return(model.Webhook{ID: "w1", UserID: "user123", URL: "https://hooks.example.com", Events: []string{model.EventBookmarkCreated},
+			Secret: "whsec_abc", Active: true}, nil)
+
+	err := handler.CreateWebhook(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	var response transport.WebhookTransport
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Equal(t, "w1", response.ID)
+	assert.Equal(t, "whsec_abc", response.Secret)
+	assert.True(t, response.Active)
+	mockService.AssertExpectations(t)
+}
+
+func TestWebhookHandler_ListWebhooks(t *testing.T) {
+	c, rec := newCollectionContext(http.MethodGet, "/webhooks", "")
+
+	mockService := new(MockWebhookService)
+	handler := NewWebhookHandler(mockService)
+	disabledAt := time.Date(2025, 11, 15, 10, 30, 0, 0, time.UTC)
+	mockService.On("ListWebhooks", "user123").Return([]model.Webhook{
+		{ID: "w1", URL: "https://hooks.example.com", Secret: "whsec_abc", ConsecutiveFailures: 20, DisabledAt: disabledAt},
+	}, nil)
+
+	err := handler.ListWebhooks(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.NotContains(t, rec.Body.String(), "whsec_abc")
+	var response []transport.WebhookTransport
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Len(t, response, 1)
+	assert.Equal(t, []string{}, response[0].Events)
+	assert.Equal(t, disabledAt, *response[0].DisabledAt)
+}
+
+func TestWebhookHandler_UpdateWebhook(t *testing.T) {
+	c, rec := newCollectionContext(http.MethodPatch, "/webhooks/w1", `{"active":true}`)
+	c.SetParamNames("id")
+	c.SetParamValues("w1")
+
+	mockService := new(MockWebhookService)
+	handler := NewWebhookHandler(mockService)
+	active := true
+	mockService.On("UpdateWebhook", "user123", "w1", model.WebhookUpdate{Active: &active}).
+		Return(model.Webhook{ID: "w1", Active: true}, nil)
+
+	err := handler.UpdateWebhook(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	mockService.AssertExpectations(t)
+}
+
+func TestWebhookHandler_ListDeliveries(t *testing.T) {
+	c, rec := newCollectionContext(http.MethodGet, "/webhooks/w1/deliveries?limit=10", "")
+	c.SetParamNames("id")
+	c.SetParamValues("w1")
+
+	mockService := new(MockWebhookService)
+	handler := NewWebhookHandler(mockService)
+	at := time.Date(2025, 11, 15, 10, 30, 0, 0, time.UTC)
+	mockService.On("ListDeliveries", "user123", "w1", 10).Return([]model.WebhookDelivery{
+		{ID: "d2", EventID: "e2", EventType: model.EventBookmarkCreated, Status: model.WebhookDeliveryPending, Attempts: 1,
+			ResponseStatus: 503, Error: "unexpected response status 503", NextAttemptAt: at.Add(time.Minute), LastAttemptAt: at},
+		{ID: "d1", EventID: "e1", EventType: model.EventBookmarkCreated, Status: model.WebhookDeliverySucceeded, Attempts: 1,
+			ResponseStatus: 200, NextAttemptAt: at, LastAttemptAt: at},
+	}, nil)
+
+	err := handler.ListDeliveries(c)
+
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var response []transport.WebhookDeliveryTransport
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &response))
+	assert.Len(t, response, 2)
+	assert.Equal(t, at.Add(time.Minute), *response[0].NextAttemptAt)
+	assert.Nil(t, response[1].NextAttemptAt)
+	assert.Equal(t, 200, response[1].ResponseStatus)
+}
+
+func TestWebhookHandler_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		code int
+	}{
+		{"invalid", fmt.Errorf("url must be an absolute http or https URL: %w", model.ErrInvalidInput), http.StatusBadRequest},
+		{"forbidden", model.ErrForbidden, http.StatusForbidden},
+		{"not found", model.ErrNotFound, http.StatusNotFound},
+		{"failed", fmt.Errorf("firestore unavailable"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := newCollectionContext(http.MethodDelete, "/webhooks/w1", "")
+			c.SetParamNames("id")
+			c.SetParamValues("w1")
+
+			mockService := new(MockWebhookService)
+			handler := NewWebhookHandler(mockService)
+			mockService.On("DeleteWebhook", "user123", "w1").Return(tt.err)
+
+			err := handler.DeleteWebhook(c)
+
+			httpErr, ok := err.(*echo.HTTPError)
+			assert.True(t, ok)
+			assert.Equal(t, tt.code, httpErr.Code)
+		})
+	}
+}
@@ -0,0 +1,24 @@
+package model
+
+import "time"
+
+// Actions recorded in a collection's activity log
+const (
+	ActivityBookmarkAdded       = "bookmark_added"
+	ActivityBookmarkRemoved     = "bookmark_removed"
+	ActivityBookmarksReordered  = "bookmarks_reordered"
+	ActivityCollaboratorJoined  = "collaborator_joined"
+	ActivityCollaboratorUpdated = "collaborator_updated"
+	ActivityCollaboratorRemoved = "collaborator_removed"
+)
+
+// Activity attributes a change in a collection to the user who made it
+type Activity struct {
+	ID           string
+	CollectionID string
+	ActorID      string
+	Action       string
+	BookmarkID   string // Set for bookmark actions
+	TargetUserID string // Set for collaborator actions
+	CreatedAt    time.Time
+}
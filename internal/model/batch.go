@@ -0,0 +1,46 @@
+package model
+
+// Operations of a bookmark batch
+const (
+	BatchOpArchive   = "archive"
+	BatchOpUnarchive = "unarchive"
+	BatchOpDelete    = "delete" // Moves the bookmark to the trash
+	BatchOpTag       = "tag"    // Adds Tags
+	BatchOpUntag     = "untag"  // Removes Tags
+	BatchOpMove      = "move"   // Moves the bookmark out of its collections into CollectionID
+)
+
+// MaxBatchOperations is how many operations one batch can hold
+const MaxBatchOperations = 500
+
+// BatchOperation is one change to one bookmark in a batch
+type BatchOperation struct {
+	Op           string
+	BookmarkID   string
+	Tags         []string // For BatchOpTag and BatchOpUntag
+	CollectionID string   // For BatchOpMove
+}
+
+// Outcomes of a batch operation
+const (
+	BatchStatusOK        = "ok"
+	BatchStatusInvalid   = "invalid"   // The operation is malformed
+	BatchStatusNotFound  = "not_found" // The bookmark does not exist, is in the trash or belongs to someone else
+	BatchStatusForbidden = "forbidden" // A collection of a move cannot be edited by the user
+	BatchStatusFailed    = "failed"    // Storing the change failed, it may be retried
+)
+
+// BatchResult is the outcome of the operation at the same index of a batch
+type BatchResult struct {
+	Op         string
+	BookmarkID string
+	Status     string // One of the BatchStatus values
+	Error      string // Why the operation did not succeed, empty when it did
+}
+
+// BookmarkWrite is one write of a bulk write: Bookmark is stored, or moved to the trash when
+// Trash is set
+type BookmarkWrite struct {
+	Bookmark Bookmark
+	Trash    bool
+}
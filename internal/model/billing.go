@@ -0,0 +1,30 @@
+package model
+
+import "time"
+
+// Provider-agnostic billing event types
+const (
+	BillingSubscriptionCreated   = "subscription.created"
+	BillingSubscriptionUpdated   = "subscription.updated"
+	BillingSubscriptionCancelled = "subscription.cancelled"
+)
+
+// BillingEvent is a subscription lifecycle event received from a billing provider
+type BillingEvent struct {
+	ID             string // Provider event ID, used for idempotency
+	Provider       string
+	Type           string
+	UserID         string // Athena user the subscription belongs to
+	CustomerID     string
+	SubscriptionID string
+	Status         string // Provider subscription status, e.g. "active", "past_due"
+	OccurredAt     time.Time
+}
+
+// GrantsPaidTier reports whether the subscription state in the event entitles the user to the paid tier
+func (e BillingEvent) GrantsPaidTier() bool {
+	if e.Type == BillingSubscriptionCancelled {
+		return false
+	}
+	return e.Status == "active" || e.Status == "trialing"
+}
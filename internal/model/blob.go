@@ -0,0 +1,11 @@
+package model
+
+import "time"
+
+// Blob is a binary object kept in a blob store, such as an offline page snapshot
+type Blob struct {
+	Key         string // Slash separated path, e.g. "snapshots/<bookmark id>.html"
+	ContentType string
+	Data        []byte
+	UpdatedAt   time.Time
+}
@@ -1,25 +1,254 @@
 package model
 
-import "time"
+import (
+	"cmp"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
 
 type Bookmark struct {
-	ID             string
-	UserID         string
-	URL            string
-	Title          string
-	IsArchived     bool
-	MainImageURL   string
-	ContentSummary string
-	CreatedAt      time.Time
-	UpdatedAt      time.Time
+	ID              string
+	UserID          string
+	URL             string
+	Title           string
+	IsArchived      bool
+	MainImageURL    string
+	ContentSummary  string
+	CollectionIDs   []string // Collections the bookmark belongs to
+	SnapshotKey     string   // Blob key of the offline snapshot of the page, empty until captured
+	SnapshotAt      time.Time
+	Thumbnails      map[string]string // Blob keys of the resized main image by size name, empty until captured
+	ThumbnailsAt    time.Time
+	Health          LinkHealth // Result of the last dead-link check
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+	Metadata        PageMetadata // Details the page gives about itself
+	Enrichments     []Enrichment // Recent enrichment runs, oldest first
+	Tags            []string     // Normalized with NormalizeTags
+	LastOpenedAt    time.Time    // When the owner last opened the link, zero if never
+	IsRead          bool
+	ReadAt          time.Time // When the bookmark was marked read, zero if it is unread
+	IsFavorite      bool
+	ReadingProgress int       // Percentage of the page read, from 0 to 100
+	Notes           string    // Markdown, private to the owner
+	DeletedAt       time.Time // When the bookmark was moved to the trash, zero if it is not in it
+	Version         int64     // Incremented each time the bookmark is stored, starting at 1
+}
+
+// ReadingStateUpdate changes the reading state of a bookmark. Nil fields are left as they are.
+type ReadingStateUpdate struct {
+	IsRead          *bool
+	IsFavorite      *bool
+	ReadingProgress *int // From 0 to 100; reaching 100 marks the bookmark read
+}
+
+// MaxBookmarkTags is how many tags a bookmark can have
+const MaxBookmarkTags = 20
+
+// NormalizeTags lowercases and trims tags, dropping empty and repeated ones
+func NormalizeTags(tags []string) []string {
+	var normalized []string
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag != "" && !slices.Contains(normalized, tag) {
+			normalized = append(normalized, tag)
+		}
+	}
+	return normalized
+}
+
+// NormalizeDomain lowercases a host name and drops a leading "www.", so that a filter on
+// example.com also matches bookmarks saved from www.example.com
+func NormalizeDomain(host string) string {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	return strings.TrimPrefix(host, "www.")
+}
+
+// VisibleTo returns the bookmark as userID sees it: notes are private to the owner, even in
+// shared collections
+func (b Bookmark) VisibleTo(userID string) Bookmark {
+	if b.UserID != userID {
+		b.Notes = ""
+	}
+	return b
+}
+
+// Domain returns the normalized host name of the bookmark's URL
+func (b Bookmark) Domain() string {
+	u, err := url.Parse(b.URL)
+	if err != nil {
+		return ""
+	}
+	return NormalizeDomain(u.Hostname())
+}
+
+// Sort orders of the bookmark listing
+const (
+	BookmarkSortCreated    = "created"
+	BookmarkSortUpdated    = "updated"
+	BookmarkSortTitle      = "title"       // Case-insensitive
+	BookmarkSortDomain     = "domain"      // See Bookmark.Domain
+	BookmarkSortLastOpened = "last_opened" // Never opened bookmarks count as opened at the zero time
+)
+
+// BookmarkSorts lists every sort order of the bookmark listing
+var BookmarkSorts = []string{BookmarkSortCreated, BookmarkSortUpdated, BookmarkSortTitle, BookmarkSortDomain, BookmarkSortLastOpened}
+
+// Directions of a sort order
+const (
+	SortAscending  = "asc"
+	SortDescending = "desc"
+)
+
+// IsValidBookmarkSort reports whether sort is one of the BookmarkSort orders
+func IsValidBookmarkSort(sort string) bool {
+	return slices.Contains(BookmarkSorts, sort)
+}
+
+// IsTextBookmarkSort reports whether sort orders bookmarks by a string rather than a time
+func IsTextBookmarkSort(sort string) bool {
+	return sort == BookmarkSortTitle || sort == BookmarkSortDomain
+}
+
+// DefaultSortOrder is the direction of sort when none is given: newest first for times,
+// alphabetical for text
+func DefaultSortOrder(sort string) string {
+	if IsTextBookmarkSort(sort) {
+		return SortAscending
+	}
+	return SortDescending
+}
+
+// SortKey returns the value the bookmark is ordered by in sort: a string for text sorts,
+// otherwise a time.Time
+func (b Bookmark) SortKey(sort string) any {
+	switch sort {
+	case BookmarkSortUpdated:
+		return b.UpdatedAt
+	case BookmarkSortTitle:
+		return strings.ToLower(b.Title)
+	case BookmarkSortDomain:
+		return b.Domain()
+	case BookmarkSortLastOpened:
+		return b.LastOpenedAt
+	default:
+		return b.CreatedAt
+	}
 }
 
 // BookmarkQuery represents query parameters for listing bookmarks
 type BookmarkQuery struct {
-	UserID   string
-	Archived bool
-	Page     int // Page number (1-based), 0 means no pagination
-	PageSize int // Number of items per page, 0 means no pagination
+	UserID           string
+	Archived         bool
+	CollectionID     string            // Only bookmarks in this collection, empty means no filter
+	Health           string            // Only bookmarks with this link health status, empty means no filter
+	Domain           string            // Only bookmarks from this normalized domain, empty means no filter
+	Tag              string            // Only bookmarks with this tag, cannot be combined with CollectionID
+	HasSummary       *bool             // Only bookmarks with or without a content summary, nil means no filter
+	EnrichmentStatus string            // Only bookmarks with this EnrichmentStatus, empty means no filter
+	Read             *bool             // Only read or unread bookmarks, nil means no filter
+	Favorite         *bool             // Only favorite or other bookmarks, nil means no filter
+	CreatedFrom      time.Time         // Only bookmarks created at or after this time, zero means no bound
+	CreatedTo        time.Time         // Only bookmarks created before this time, zero means no bound
+	Sort             string            // One of BookmarkSorts, empty means BookmarkSortCreated
+	Order            string            // SortAscending or SortDescending, empty means DefaultSortOrder
+	Page             int               // Page number (1-based), 0 means no pagination
+	PageSize         int               // Number of items per page, 0 means no pagination
+	Cursor           string            // Opaque cursor from a previous response; Page is ignored when set
+	Position         *BookmarkPosition // Cursor once verified by the service, for the repository
+}
+
+// Normalize fills in the default sort order and checks that the filters can be combined,
+// returning ErrInvalidInput otherwise
+func (q *BookmarkQuery) Normalize() error {
+	q.Sort = cmp.Or(q.Sort, BookmarkSortCreated)
+	if !IsValidBookmarkSort(q.Sort) {
+		return fmt.Errorf("unknown sort %q: %w", q.Sort, ErrInvalidInput)
+	}
+	q.Order = cmp.Or(q.Order, DefaultSortOrder(q.Sort))
+	if q.Order != SortAscending && q.Order != SortDescending {
+		return fmt.Errorf("unknown order %q: %w", q.Order, ErrInvalidInput)
+	}
+	if q.Health != "" && !IsValidLinkHealthStatus(q.Health) {
+		return fmt.Errorf("unknown health status %q: %w", q.Health, ErrInvalidInput)
+	}
+	if q.EnrichmentStatus != "" && !IsValidEnrichmentStatus(q.EnrichmentStatus) {
+		return fmt.Errorf("unknown enrichment status %q: %w", q.EnrichmentStatus, ErrInvalidInput)
+	}
+	// Both are array-contains filters in Firestore, which allows one per query
+	if q.Tag != "" && q.CollectionID != "" {
+		return fmt.Errorf("tag and collection filters cannot be combined: %w", ErrInvalidInput)
+	}
+	if !q.CreatedFrom.IsZero() && !q.CreatedTo.IsZero() && !q.CreatedFrom.Before(q.CreatedTo) {
+		return fmt.Errorf("created range is empty: %w", ErrInvalidInput)
+	}
+	q.Domain = NormalizeDomain(q.Domain)
+	q.Tag = strings.ToLower(strings.TrimSpace(q.Tag))
+	return nil
+}
+
+// BookmarkPosition is a place in the bookmark listing, which is ordered by the sort key of
+// the query, then by creation time and then by ID, all in the direction of the query
+type BookmarkPosition struct {
+	Sort      string    // Sort of the listing the position is in
+	Order     string    // Order of the listing the position is in
+	Time      time.Time // Sort key for time sorts other than BookmarkSortCreated
+	Text      string    // Sort key for text sorts
+	CreatedAt time.Time
+	ID        string
+	Backward  bool // List the PageSize bookmarks just before the position instead of after it
+}
+
+// Key returns the sort key of the position, the counterpart of Bookmark.SortKey
+func (p BookmarkPosition) Key() any {
+	switch {
+	case IsTextBookmarkSort(p.Sort):
+		return p.Text
+	case p.Sort == "" || p.Sort == BookmarkSortCreated:
+		return p.CreatedAt
+	default:
+		return p.Time
+	}
+}
+
+// PositionOf returns the position of bookmark in a listing sorted by sort and order
+func PositionOf(bookmark Bookmark, sort, order string, backward bool) BookmarkPosition {
+	p := BookmarkPosition{Sort: sort, Order: order, CreatedAt: bookmark.CreatedAt, ID: bookmark.ID, Backward: backward}
+	switch key := bookmark.SortKey(sort).(type) {
+	case string:
+		p.Text = key
+	case time.Time:
+		p.Time = key
+	}
+	return p
+}
+
+// ListedBefore reports whether bookmark comes before the position p in its listing
+func (p BookmarkPosition) ListedBefore(bookmark Bookmark) bool {
+	c := compareSortKeys(bookmark.SortKey(p.Sort), p.Key())
+	if c == 0 {
+		c = bookmark.CreatedAt.Compare(p.CreatedAt)
+	}
+	if c == 0 {
+		c = strings.Compare(bookmark.ID, p.ID)
+	}
+	if cmp.Or(p.Order, DefaultSortOrder(p.Sort)) == SortDescending {
+		return c > 0
+	}
+	return c < 0
+}
+
+func compareSortKeys(a, b any) int {
+	switch a := a.(type) {
+	case string:
+		return strings.Compare(a, b.(string))
+	case time.Time:
+		return a.Compare(b.(time.Time))
+	}
+	return 0
 }
 
 // BookmarkListResponse represents paginated response for listing bookmarks
@@ -29,4 +258,6 @@ type BookmarkListResponse struct {
 	Page       int        `json:"page"`
 	PageSize   int        `json:"page_size"`
 	TotalPages int        `json:"total_pages"`
+	NextCursor string     `json:"next_cursor,omitempty"` // Empty on the last page
+	PrevCursor string     `json:"prev_cursor,omitempty"` // Empty on the first page
 }
@@ -0,0 +1,59 @@
+package model
+
+import "time"
+
+// Roles a user can hold on a collection. The owner is the collection's UserID and
+// is never stored as a collaborator.
+const (
+	CollectionRoleOwner  = "owner"
+	CollectionRoleEditor = "editor"
+	CollectionRoleViewer = "viewer"
+)
+
+// Collection is a named, ordered group of bookmarks. Collections can be nested
+// through ParentID; an empty ParentID means a top-level collection.
+type Collection struct {
+	ID            string
+	UserID        string
+	ParentID      string
+	Name          string
+	Description   string
+	Position      int               // Sort order among sibling collections
+	BookmarkIDs   []string          // Bookmarks in their manual order
+	AddedBy       map[string]string // Bookmark ID to the ID of the user who added it
+	Collaborators []Collaborator
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	Version       int64 // Incremented each time the collection is stored, starting at 1
+}
+
+// Collaborator is a user other than the owner with access to a shared collection
+type Collaborator struct {
+	UserID  string
+	Role    string // CollectionRoleEditor or CollectionRoleViewer
+	AddedAt time.Time
+}
+
+// RoleOf returns the role userID holds on the collection, or an empty string
+func (c Collection) RoleOf(userID string) string {
+	if c.UserID == userID {
+		return CollectionRoleOwner
+	}
+	for _, collaborator := range c.Collaborators {
+		if collaborator.UserID == userID {
+			return collaborator.Role
+		}
+	}
+	return ""
+}
+
+// CollectionBookmark is a bookmark listed in a collection together with who added it
+type CollectionBookmark struct {
+	Bookmark Bookmark
+	AddedBy  string
+}
+
+// IsValidCollaboratorRole reports whether role can be granted to a collaborator
+func IsValidCollaboratorRole(role string) bool {
+	return role == CollectionRoleEditor || role == CollectionRoleViewer
+}
@@ -0,0 +1,64 @@
+package model
+
+import (
+	"slices"
+	"time"
+)
+
+// What started an enrichment run
+const (
+	EnrichmentTriggerCreate  = "create"
+	EnrichmentTriggerRefresh = "refresh"
+)
+
+// Outcomes of the content summary step of an enrichment run
+const (
+	EnrichmentSummaryGenerated    = "generated"     // A new summary was written
+	EnrichmentSummaryKept         = "kept"          // The bookmark already had one, refreshes do not replace it
+	EnrichmentSummaryDisabled     = "disabled"      // Summaries are turned off on this server
+	EnrichmentSummaryNotAvailable = "not_available" // The tier has no summaries or this month's are used up
+	EnrichmentSummaryFailed       = "failed"        // The provider returned an error or nothing
+)
+
+// Enrichment statuses of a bookmark, from its latest enrichment run
+const (
+	EnrichmentStatusNone   = "none"   // No run was recorded, the bookmark predates the history
+	EnrichmentStatusOK     = "ok"     // Every lookup of the latest run succeeded
+	EnrichmentStatusFailed = "failed" // Some lookups of the latest run failed, a refresh may help
+)
+
+// IsValidEnrichmentStatus reports whether status is one of the EnrichmentStatus values
+func IsValidEnrichmentStatus(status string) bool {
+	return slices.Contains([]string{EnrichmentStatusNone, EnrichmentStatusOK, EnrichmentStatusFailed}, status)
+}
+
+// EnrichmentStatus summarizes the latest enrichment run of the bookmark
+func (b Bookmark) EnrichmentStatus() string {
+	if len(b.Enrichments) == 0 {
+		return EnrichmentStatusNone
+	}
+	if len(b.Enrichments[len(b.Enrichments)-1].Failed) > 0 {
+		return EnrichmentStatusFailed
+	}
+	return EnrichmentStatusOK
+}
+
+// MaxEnrichmentHistory is how many enrichment runs are kept per bookmark, oldest dropped first
+const MaxEnrichmentHistory = 20
+
+// Enrichment records one run of the lookups that fill in a bookmark's title, main image,
+// content summary and page metadata
+type Enrichment struct {
+	At              time.Time
+	Trigger         string   // EnrichmentTriggerCreate or EnrichmentTriggerRefresh
+	Summary         string   // One of the EnrichmentSummary outcomes
+	SummaryProvider string   // LLM that wrote the summary, empty unless one was generated
+	Updated         []string // Fields that got a new value: "title", "main_image", "summary", "metadata"
+	Failed          []string // Lookups that returned an error, named like Updated
+}
+
+// BulkRefresh is the outcome of queueing several bookmarks for enrichment
+type BulkRefresh struct {
+	Queued   []string // IDs refreshed in the background
+	NotFound []string // IDs that do not exist or belong to someone else
+}
@@ -0,0 +1,29 @@
+package model
+
+// Unlimited marks an entitlement limit that is not enforced
+const Unlimited = -1
+
+const (
+	// UsageSummaries counts LLM content summaries, tracked per calendar month
+	UsageSummaries = "summaries"
+	// UsageImports counts bookmark imports, tracked per calendar day
+	UsageImports = "imports"
+)
+
+// Entitlements defines the limits and features granted to a tier
+type Entitlements struct {
+	MaxBookmarks      int  // Total bookmarks including archived ones
+	SummariesPerMonth int  // LLM content summaries per calendar month (UTC)
+	ImportsPerDay     int  // Bookmark imports per calendar day (UTC)
+	MaxAPITokens      int  // Personal API tokens that may exist at the same time
+	SemanticSearch    bool // Access to semantic search
+}
+
+// Usage represents a user's consumption against their tier's entitlements
+type Usage struct {
+	Tier               string
+	Entitlements       Entitlements
+	Bookmarks          int
+	SummariesThisMonth int
+	ImportsToday       int
+}
@@ -0,0 +1,27 @@
+package model
+
+import "errors"
+
+// Sentinel errors shared across layers. Services wrap them with context using
+// fmt.Errorf("...: %w", err) and handlers map them to HTTP status codes with errors.Is.
+var (
+	// ErrNotFound is returned when the requested entity does not exist
+	ErrNotFound = errors.New("not found")
+	// ErrInvalidInput is returned when a request fails validation
+	ErrInvalidInput = errors.New("invalid input")
+	// ErrForbidden is returned when the caller is not allowed to perform an action
+	ErrForbidden = errors.New("forbidden")
+	// ErrUnauthorized is returned when a credential such as a share link password is missing or wrong
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrExpired is returned when an entity such as a share link is past its expiry
+	ErrExpired = errors.New("expired")
+	// ErrConflict is returned when an action conflicts with the current state of an entity
+	ErrConflict = errors.New("conflict")
+	// ErrPreconditionFailed is returned when an entity no longer is at the version the caller
+	// expected, such as a bookmark changed since the client read it
+	ErrPreconditionFailed = errors.New("precondition failed")
+	// ErrQuotaExceeded is returned when an action would exceed a tier limit
+	ErrQuotaExceeded = errors.New("quota exceeded")
+	// ErrFeatureNotAvailable is returned when the user's tier does not include a feature
+	ErrFeatureNotAvailable = errors.New("feature not available for tier")
+)
@@ -0,0 +1,29 @@
+package model
+
+import "time"
+
+// Types of bookmark change events
+const (
+	EventBookmarkCreated  = "bookmark.created"  // Also sent when a bookmark is restored from the trash
+	EventBookmarkUpdated  = "bookmark.updated"  // Changed by the user
+	EventBookmarkEnriched = "bookmark.enriched" // Metadata, snapshot or thumbnails fetched in the background
+	EventBookmarkArchived = "bookmark.archived" // Unarchiving is an update
+	EventBookmarkDeleted  = "bookmark.deleted"  // Moved to the trash
+)
+
+// Event is a change to a bookmark, sent to its owner's open event streams
+type Event struct {
+	ID       string // Orders events: later events have greater IDs
+	UserID   string
+	Type     string
+	Bookmark Bookmark // The bookmark after the change; only the ID is set for deleted bookmarks
+	At       time.Time
+}
+
+// EventSubscription is an open event stream of one user
+type EventSubscription struct {
+	Replay []Event      // Events missed since the Last-Event-ID the stream resumed from
+	Reset  bool         // Events since the Last-Event-ID are no longer kept; reload instead
+	Events <-chan Event // Closed when the subscriber falls too far behind
+	Close  func()       // Ends the subscription
+}
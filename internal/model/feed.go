@@ -0,0 +1,21 @@
+package model
+
+import "time"
+
+// FeedToken authenticates a feed reader for one user's feed. Feed readers cannot send
+// a JWT, so the token is part of the feed URL; deleting it revokes the URL.
+type FeedToken struct {
+	Token        string // Unguessable secret, also the storage key
+	UserID       string
+	CollectionID string // Limits the feed to one collection, empty means all bookmarks
+	CreatedAt    time.Time
+}
+
+// Feed is the content of a bookmark feed, newest entries first
+type Feed struct {
+	Title        string
+	UserID       string
+	CollectionID string
+	Bookmarks    []Bookmark
+	UpdatedAt    time.Time // Latest change among the entries, or when the token was created
+}
@@ -0,0 +1,37 @@
+package model
+
+import "time"
+
+// Highlight is a passage of a bookmarked page that its owner marked, with an optional note
+type Highlight struct {
+	ID         string
+	BookmarkID string
+	UserID     string
+	Quote      string // The highlighted text
+	Note       string // Markdown
+	Anchor     HighlightAnchor
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// HighlightAnchor locates a highlight in the text of the offline snapshot, as character
+// offsets with End exclusive. Both are 0 when the position is unknown.
+type HighlightAnchor struct {
+	Start int
+	End   int
+}
+
+// HighlightUpdate changes the note or anchor of a highlight. Nil fields are left as they are.
+type HighlightUpdate struct {
+	Note   *string
+	Anchor *HighlightAnchor
+}
+
+// Length limits of notes and highlights, in characters
+const (
+	MaxNoteLength  = 20000
+	MaxQuoteLength = 5000
+)
+
+// MaxBookmarkHighlights is how many highlights a bookmark can have
+const MaxBookmarkHighlights = 200
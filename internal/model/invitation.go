@@ -0,0 +1,22 @@
+package model
+
+import "time"
+
+const (
+	InvitationPending  = "pending"
+	InvitationAccepted = "accepted"
+	InvitationDeclined = "declined"
+	InvitationRevoked  = "revoked"
+)
+
+// Invitation offers a collection role to whoever owns Email
+type Invitation struct {
+	ID           string
+	CollectionID string
+	InviterID    string
+	Email        string // Lower-cased invitee email
+	Role         string
+	Status       string
+	CreatedAt    time.Time
+	RespondedAt  time.Time
+}
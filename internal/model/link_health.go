@@ -0,0 +1,24 @@
+package model
+
+import "time"
+
+// Link health statuses recorded by the link checker
+const (
+	LinkHealthOK     = "ok"     // The URL answered with a 2xx or 3xx status
+	LinkHealthBroken = "broken" // 404, 410 or the host name does not resolve
+	LinkHealthError  = "error"  // Any other failure, such as a timeout or 5xx; checked again on the next run
+)
+
+// LinkHealth is the outcome of the last check of a bookmark's URL
+type LinkHealth struct {
+	Status     string // Empty until the first check
+	StatusCode int    // HTTP status of the final response, 0 when no response was received
+	FinalURL   string // URL after following redirects
+	Error      string // Reason the request failed, if it did
+	CheckedAt  time.Time
+}
+
+// IsValidLinkHealthStatus reports whether status is one of the link health statuses
+func IsValidLinkHealthStatus(status string) bool {
+	return status == LinkHealthOK || status == LinkHealthBroken || status == LinkHealthError
+}
@@ -0,0 +1,45 @@
+package model
+
+import "time"
+
+// PageMetadata is what a bookmarked page or document says about itself, beyond the title,
+// image and summary kept on the bookmark
+type PageMetadata struct {
+	Description string
+	SiteName    string
+	Type        string // Open Graph type such as "article" or "video.other"
+	Author      string
+	PublishedAt time.Time
+	FaviconURL  string
+	PageCount   int   // Pages of a PDF, 0 for web pages
+	WordCount   int   // Words of readable text on a web page, 0 for PDFs and unknown
+	Embed       Embed // Player of a video page, found through oEmbed
+}
+
+// Reading speeds behind the estimated reading time
+const (
+	WordsPerMinute = 238 // Average silent reading speed of adults
+	WordsPerPage   = 500 // Words on a typical PDF page
+)
+
+// ReadingTime estimates how long reading the page takes, rounded up to a whole minute. It is
+// 0 when the length of the page is unknown.
+func (m PageMetadata) ReadingTime() time.Duration {
+	words := m.WordCount
+	if words == 0 {
+		words = m.PageCount * WordsPerPage
+	}
+	if words == 0 {
+		return 0
+	}
+	minutes := (words + WordsPerMinute - 1) / WordsPerMinute
+	return time.Duration(minutes) * time.Minute
+}
+
+// Embed is a player that can be shown in an iframe
+type Embed struct {
+	Provider string
+	URL      string // https URL of the provider's iframe
+	Width    int
+	Height   int
+}
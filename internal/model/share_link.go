@@ -0,0 +1,36 @@
+package model
+
+import "time"
+
+// Resources a share link can point at
+const (
+	ShareResourceBookmark   = "bookmark"
+	ShareResourceCollection = "collection"
+)
+
+// ShareLink makes a bookmark or collection readable without authentication under
+// an unguessable slug. Deleting the link revokes it.
+type ShareLink struct {
+	Slug         string // Unguessable public identifier, also the storage key
+	UserID       string
+	ResourceType string
+	ResourceID   string
+	PasswordHash string    // bcrypt hash; empty when the link is not password protected
+	ExpiresAt    time.Time // Zero means the link never expires
+	CreatedAt    time.Time
+}
+
+// IsExpired reports whether the link has expired at now
+func (l ShareLink) IsExpired(now time.Time) bool {
+	return !l.ExpiresAt.IsZero() && !now.Before(l.ExpiresAt)
+}
+
+// PublicShare is what an unauthenticated visitor of a share link gets to see. A shared
+// bookmark is presented as a single-item list named after the bookmark.
+type PublicShare struct {
+	ResourceType string
+	Name         string
+	Description  string
+	Bookmarks    []Bookmark
+	ExpiresAt    time.Time
+}
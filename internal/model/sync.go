@@ -0,0 +1,68 @@
+package model
+
+import "time"
+
+// Kinds of entries in the bookmark change log
+const (
+	BookmarkChangeCreated = "created" // Saved, or restored from the trash
+	BookmarkChangeUpdated = "updated" // Changed by the user or enriched in the background
+	BookmarkChangeDeleted = "deleted" // Moved to the trash
+)
+
+// BookmarkChange is an entry of the change log of a user, which sync clients catch up from
+type BookmarkChange struct {
+	UserID     string
+	Seq        int64 // Position in the user's change log: 1, 2, 3, ... without gaps
+	BookmarkID string
+	Kind       string
+	At         time.Time
+}
+
+// Limits of the sync protocol
+const (
+	MaxSyncChanges = 500 // Change log entries covered by one pull; the rest wait for the next
+	MaxSyncEdits   = 100 // Client edits in one push
+)
+
+// SyncDelta is how the bookmarks of a user changed since a change token. A bookmark is
+// listed once, in its state at the time of the pull.
+type SyncDelta struct {
+	Created []Bookmark // Saved or restored since the token; every bookmark for a full sync
+	Updated []Bookmark
+	Deleted []string // IDs of bookmarks moved to the trash; may include IDs the client never saw
+	Token   string   // Where the next pull starts from
+	HasMore bool     // More changes are waiting; pull again from Token right away
+}
+
+// SyncEdit is a change a client made to one of its bookmarks while it was offline. Nil
+// fields are left as they are.
+type SyncEdit struct {
+	BookmarkID      string
+	BaseUpdatedAt   time.Time // UpdatedAt of the copy the client edited; zero if unknown
+	EditedAt        time.Time // When the client made the edit, for resolving conflicts
+	Delete          bool      // Move the bookmark to the trash; other fields are ignored
+	IsArchived      *bool
+	IsRead          *bool
+	IsFavorite      *bool
+	ReadingProgress *int
+	Notes           *string
+	Tags            *[]string
+}
+
+// Outcomes of a pushed edit
+const (
+	SyncStatusApplied  = "applied"
+	SyncStatusConflict = "conflict"  // The server copy changed after the edit was made and is kept
+	SyncStatusInvalid  = "invalid"   // The edit is malformed
+	SyncStatusNotFound = "not_found" // The bookmark does not exist, is in the trash or belongs to someone else
+	SyncStatusFailed   = "failed"    // Storing the edit failed, it may be retried
+)
+
+// SyncResult is the outcome of the edit at the same index of a push
+type SyncResult struct {
+	BookmarkID string
+	Status     string   // One of the SyncStatus values
+	Conflicted bool     // The server copy changed since BaseUpdatedAt; the later of the two won
+	Bookmark   Bookmark // The server copy after the push; zero when not found or deleted
+	Error      string   // Why the edit was not applied, empty when it was
+}
@@ -2,12 +2,74 @@ package model
 
 import "time"
 
+const (
+	// TierFree is the default tier assigned to new users
+	TierFree = "free"
+	// TierPaid unlocks paid features such as LLM content summaries
+	TierPaid = "paid"
+)
+
+const (
+	// RoleUser is the default role assigned to new users
+	RoleUser = "user"
+	// RoleAdmin grants access to the user management API
+	RoleAdmin = "admin"
+)
+
 type User struct {
-	ID        string
-	Name      string
-	Email     string
-	Password  string
-	Tier      string
-	CreatedAt time.Time
-	UpdatedAt time.Time
+	ID       string
+	Name     string
+	Email    string
+	Password string
+	Tier     string
+	Role     string
+	Disabled bool
+	// TokensRevokedAt invalidates every JWT issued before this time (forced logout)
+	TokensRevokedAt time.Time
+	// AdminBootstrappedAt is when ADMIN_EMAILS promoted the user, zero if it never did.
+	// Promotion happens once, so a later demotion by another admin sticks.
+	AdminBootstrappedAt time.Time
+	// BillingEventAt is when the last billing event applied to the tier occurred. Older
+	// events that arrive late are ignored.
+	BillingEventAt time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// IsAdmin reports whether the user has the admin role
+func (u User) IsAdmin() bool {
+	return u.Role == RoleAdmin
+}
+
+// UserQuery represents query parameters for listing users
+type UserQuery struct {
+	Search   string // Email prefix to match, empty means all users
+	Page     int    // Page number (1-based), 0 means no pagination
+	PageSize int    // Number of items per page, 0 means no pagination
+}
+
+// UserListResponse represents paginated response for listing users
+type UserListResponse struct {
+	Users      []User
+	TotalCount int
+	Page       int
+	PageSize   int
+	TotalPages int
+}
+
+// UserStats holds per-user usage figures shown to administrators
+type UserStats struct {
+	User          User
+	BookmarkCount int
+	ArchivedCount int
+}
+
+// IsValidTier reports whether tier is a known tier name
+func IsValidTier(tier string) bool {
+	return tier == TierFree || tier == TierPaid
+}
+
+// IsValidRole reports whether role is a known role name
+func IsValidRole(role string) bool {
+	return role == RoleUser || role == RoleAdmin
 }
@@ -0,0 +1,113 @@
+package model
+
+import (
+	"slices"
+	"time"
+)
+
+// Limits of webhooks
+const (
+	MaxWebhooks           = 10  // Per user
+	MaxWebhookAttempts    = 8   // Deliveries that fail this often are given up
+	MaxWebhookFailures    = 20  // Failed attempts in a row after which a webhook is disabled
+	MaxWebhookDeliveryLog = 100 // Most deliveries listed by the delivery log
+)
+
+// WebhookEvents are the event types a webhook can subscribe to
+var WebhookEvents = []string{
+	EventBookmarkCreated,
+	EventBookmarkUpdated,
+	EventBookmarkEnriched,
+	EventBookmarkArchived,
+	EventBookmarkDeleted,
+}
+
+// Webhook is a URL that receives the bookmark events of its owner as signed POST requests
+type Webhook struct {
+	ID                  string
+	UserID              string
+	URL                 string
+	Events              []string // Event types delivered; empty means all
+	Secret              string   // Signs the payloads with HMAC-SHA256
+	Active              bool
+	ConsecutiveFailures int       // Failed attempts since the last successful delivery
+	DisabledAt          time.Time // When the webhook was disabled after too many failures; zero otherwise
+	CreatedAt           time.Time
+	UpdatedAt           time.Time
+}
+
+// Subscribes reports whether the webhook is active and receives events of eventType
+func (w Webhook) Subscribes(eventType string) bool {
+	return w.Active && (len(w.Events) == 0 || slices.Contains(w.Events, eventType))
+}
+
+// RecordResult counts the outcome of a delivery attempt. A success resets the failure count; a
+// failure adds to it and disables the webhook once it has failed MaxWebhookFailures times in a
+// row. It only changes ConsecutiveFailures, Active and DisabledAt.
+func (w *Webhook) RecordResult(succeeded bool, at time.Time) {
+	if succeeded {
+		w.ConsecutiveFailures = 0
+		return
+	}
+	w.ConsecutiveFailures++
+	if w.Active && w.ConsecutiveFailures >= MaxWebhookFailures {
+		w.Active = false
+		w.DisabledAt = at
+	}
+}
+
+// WebhookUpdate changes a webhook; nil fields are unchanged
+type WebhookUpdate struct {
+	URL    *string
+	Events *[]string
+	Active *bool // Reactivating a disabled webhook resets its failure count
+}
+
+// ApplyUpdate changes the fields set in update. It only changes URL, Events, Active and, when
+// the webhook is reactivated, ConsecutiveFailures and DisabledAt.
+func (w *Webhook) ApplyUpdate(update WebhookUpdate) {
+	if update.URL != nil {
+		w.URL = *update.URL
+	}
+	if update.Events != nil {
+		w.Events = slices.Clone(*update.Events)
+	}
+	if update.Active != nil {
+		if *update.Active && !w.Active {
+			w.ConsecutiveFailures = 0
+			w.DisabledAt = time.Time{}
+		}
+		w.Active = *update.Active
+	}
+}
+
+// Webhook delivery statuses
+const (
+	WebhookDeliveryPending   = "pending"   // Waiting for its first attempt or a retry
+	WebhookDeliverySucceeded = "succeeded" // The URL answered with a 2xx status
+	WebhookDeliveryFailed    = "failed"    // Given up after MaxWebhookAttempts, or the webhook was disabled
+)
+
+// WebhookDelivery is one event queued for, or sent to, a webhook
+type WebhookDelivery struct {
+	ID             string
+	WebhookID      string
+	UserID         string
+	EventID        string
+	EventType      string
+	Payload        []byte // The signed request body, kept so retries send the same bytes
+	Status         string
+	Attempts       int
+	NextAttemptAt  time.Time // When a pending delivery is due
+	LastAttemptAt  time.Time
+	ResponseStatus int    // HTTP status of the last attempt; zero when no response was received
+	Error          string // Why the last attempt failed
+	CreatedAt      time.Time
+}
+
+// WebhookRequest is a signed request sent to a webhook
+type WebhookRequest struct {
+	URL     string
+	Headers map[string]string
+	Body    []byte
+}
@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/google/uuid"
+	"github.com/tsongpon/athena/internal/logger"
+	"github.com/tsongpon/athena/internal/model"
+	"go.uber.org/zap"
+	"google.golang.org/api/iterator"
+)
+
+const activitiesCollection = "activities"
+
+// ActivityFirestoreRepository implements ActivityRepository interface using GCP Firestore
+type ActivityFirestoreRepository struct {
+	client *firestore.Client
+	ctx    context.Context
+}
+
+// NewActivityFirestoreRepository creates a new instance of ActivityFirestoreRepository
+func NewActivityFirestoreRepository(ctx context.Context, client *firestore.Client) *ActivityFirestoreRepository {
+	return &ActivityFirestoreRepository{
+		client: client,
+		ctx:    ctx,
+	}
+}
+
+// firestoreActivity is the structure used to store/retrieve activities in Firestore
+type firestoreActivity struct {
+	ID           string    `firestore:"id"`
+	CollectionID string    `firestore:"collection_id"`
+	ActorID      string    `firestore:"actor_id"`
+	Action       string    `firestore:"action"`
+	BookmarkID   string    `firestore:"bookmark_id"`
+	TargetUserID string    `firestore:"target_user_id"`
+	CreatedAt    time.Time `firestore:"created_at"`
+}
+
+// CreateActivity stores an activity in Firestore
+func (r *ActivityFirestoreRepository) CreateActivity(activity model.Activity) (model.Activity, error) {
+	activity.ID = uuid.New().String()
+	if activity.CreatedAt.IsZero() {
+		activity.CreatedAt = time.Now()
+	}
+
+	_, err := r.client.Collection(activitiesCollection).Doc(activity.ID).Set(r.ctx, firestoreActivity{
+		ID:           activity.ID,
+		CollectionID: activity.CollectionID,
+		ActorID:      activity.ActorID,
+		Action:       activity.Action,
+		BookmarkID:   activity.BookmarkID,
+		TargetUserID: activity.TargetUserID,
+		CreatedAt:    activity.CreatedAt,
+	})
+	if err != nil {
+		logger.Error("Failed to create activity in Firestore",
+			zap.String("collection_id", activity.CollectionID),
+			zap.Error(err))
+		return model.Activity{}, fmt.Errorf("failed to create activity: %w", err)
+	}
+
+	return activity, nil
+}
+
+// ListActivities retrieves up to limit activities of a collection from Firestore, newest first
+func (r *ActivityFirestoreRepository) ListActivities(collectionID string, limit int) ([]model.Activity, error) {
+	query := r.client.Collection(activitiesCollection).
+		Where("collection_id", "==", collectionID).
+		OrderBy("created_at", firestore.Desc)
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	iter := query.Documents(r.ctx)
+	defer iter.Stop()
+
+	activities := []model.Activity{}
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			logger.Error("Failed to list activities from Firestore",
+				zap.String("collection_id", collectionID),
+				zap.Error(err))
+			return nil, fmt.Errorf("failed to list activities: %w", err)
+		}
+
+		var fsActivity firestoreActivity
+		if err := doc.DataTo(&fsActivity); err != nil {
+			return nil, fmt.Errorf("failed to parse activity data: %w", err)
+		}
+		activities = append(activities, model.Activity{
+			ID:           fsActivity.ID,
+			CollectionID: fsActivity.CollectionID,
+			ActorID:      fsActivity.ActorID,
+			Action:       fsActivity.Action,
+			BookmarkID:   fsActivity.BookmarkID,
+			TargetUserID: fsActivity.TargetUserID,
+			CreatedAt:    fsActivity.CreatedAt,
+		})
+	}
+
+	return activities, nil
+}
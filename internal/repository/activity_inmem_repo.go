@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tsongpon/athena/internal/model"
+)
+
+// ActivityInMemRepository implements ActivityRepository interface using an in-memory log per collection
+type ActivityInMemRepository struct {
+	activities map[string][]model.Activity // Collection ID to activities in insertion order
+	mutex      sync.RWMutex
+}
+
+// NewActivityInMemRepository creates a new instance of ActivityInMemRepository
+func NewActivityInMemRepository() *ActivityInMemRepository {
+	return &ActivityInMemRepository{
+		activities: make(map[string][]model.Activity),
+		mutex:      sync.RWMutex{},
+	}
+}
+
+// CreateActivity appends an activity to its collection's log
+func (r *ActivityInMemRepository) CreateActivity(activity model.Activity) (model.Activity, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	activity.ID = uuid.New().String()
+	if activity.CreatedAt.IsZero() {
+		activity.CreatedAt = time.Now()
+	}
+
+	r.activities[activity.CollectionID] = append(r.activities[activity.CollectionID], activity)
+
+	return activity, nil
+}
+
+// ListActivities retrieves up to limit activities of a collection, newest first
+func (r *ActivityInMemRepository) ListActivities(collectionID string, limit int) ([]model.Activity, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	log := r.activities[collectionID]
+	activities := []model.Activity{}
+	for i := len(log) - 1; i >= 0 && (limit <= 0 || len(activities) < limit); i-- {
+		activities = append(activities, log[i])
+	}
+
+	return activities, nil
+}
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/tsongpon/athena/internal/logger"
+	"github.com/tsongpon/athena/internal/model"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const billingEventsCollection = "billing_events"
+
+// BillingEventFirestoreRepository implements BillingEventRepository interface using GCP Firestore
+type BillingEventFirestoreRepository struct {
+	client *firestore.Client
+	ctx    context.Context
+}
+
+// NewBillingEventFirestoreRepository creates a new instance of BillingEventFirestoreRepository
+func NewBillingEventFirestoreRepository(ctx context.Context, client *firestore.Client) *BillingEventFirestoreRepository {
+	return &BillingEventFirestoreRepository{
+		client: client,
+		ctx:    ctx,
+	}
+}
+
+// firestoreBillingEvent is the structure used to store processed billing events in Firestore
+type firestoreBillingEvent struct {
+	ID             string    `firestore:"id"`
+	Provider       string    `firestore:"provider"`
+	Type           string    `firestore:"type"`
+	UserID         string    `firestore:"user_id"`
+	CustomerID     string    `firestore:"customer_id"`
+	SubscriptionID string    `firestore:"subscription_id"`
+	Status         string    `firestore:"status"`
+	OccurredAt     time.Time `firestore:"occurred_at"`
+	ProcessedAt    time.Time `firestore:"processed_at"`
+}
+
+// IsEventProcessed reports whether the provider event was already handled
+func (r *BillingEventFirestoreRepository) IsEventProcessed(provider, eventID string) (bool, error) {
+	_, err := r.client.Collection(billingEventsCollection).Doc(billingEventKey(provider, eventID)).Get(r.ctx)
+	if status.Code(err) == codes.NotFound {
+		return false, nil
+	}
+	if err != nil {
+		logger.Error("Failed to get billing event from Firestore",
+			zap.String("provider", provider),
+			zap.String("event_id", eventID),
+			zap.Error(err))
+		return false, fmt.Errorf("failed to get billing event: %w", err)
+	}
+	return true, nil
+}
+
+// MarkEventProcessed records the event so that redeliveries are skipped
+func (r *BillingEventFirestoreRepository) MarkEventProcessed(event model.BillingEvent) error {
+	fsEvent := firestoreBillingEvent{
+		ID:             event.ID,
+		Provider:       event.Provider,
+		Type:           event.Type,
+		UserID:         event.UserID,
+		CustomerID:     event.CustomerID,
+		SubscriptionID: event.SubscriptionID,
+		Status:         event.Status,
+		OccurredAt:     event.OccurredAt,
+		ProcessedAt:    time.Now(),
+	}
+
+	_, err := r.client.Collection(billingEventsCollection).Doc(billingEventKey(event.Provider, event.ID)).Set(r.ctx, fsEvent)
+	if err != nil {
+		logger.Error("Failed to store billing event in Firestore",
+			zap.String("provider", event.Provider),
+			zap.String("event_id", event.ID),
+			zap.Error(err))
+		return fmt.Errorf("failed to store billing event: %w", err)
+	}
+	return nil
+}
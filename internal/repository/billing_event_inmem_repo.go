@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"sync"
+
+	"github.com/tsongpon/athena/internal/model"
+)
+
+// BillingEventInMemRepository implements BillingEventRepository interface using an in-memory map
+type BillingEventInMemRepository struct {
+	events map[string]model.BillingEvent
+	mutex  sync.RWMutex
+}
+
+// NewBillingEventInMemRepository creates a new instance of BillingEventInMemRepository
+func NewBillingEventInMemRepository() *BillingEventInMemRepository {
+	return &BillingEventInMemRepository{
+		events: make(map[string]model.BillingEvent),
+		mutex:  sync.RWMutex{},
+	}
+}
+
+// IsEventProcessed reports whether the provider event was already handled
+func (r *BillingEventInMemRepository) IsEventProcessed(provider, eventID string) (bool, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	_, exists := r.events[billingEventKey(provider, eventID)]
+	return exists, nil
+}
+
+// MarkEventProcessed records the event so that redeliveries are skipped
+func (r *BillingEventInMemRepository) MarkEventProcessed(event model.BillingEvent) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.events[billingEventKey(event.Provider, event.ID)] = event
+	return nil
+}
+
+// billingEventKey builds the identifier of a processed event, also used as Firestore document ID
+func billingEventKey(provider, eventID string) string {
+	return provider + "_" + eventID
+}
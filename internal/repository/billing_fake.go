@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/tsongpon/athena/internal/model"
+)
+
+// FakeBillingProvider implements BillingProvider for local development and tests.
+// Payloads use Athena's own event format and are signed with a shared secret, so
+// tier changes can be exercised without a real billing account.
+type FakeBillingProvider struct {
+	webhookSecret string
+}
+
+// NewFakeBillingProvider creates a new instance of FakeBillingProvider
+func NewFakeBillingProvider(webhookSecret string) *FakeBillingProvider {
+	return &FakeBillingProvider{
+		webhookSecret: webhookSecret,
+	}
+}
+
+// fakeBillingEvent is the JSON payload accepted by FakeBillingProvider
+type fakeBillingEvent struct {
+	ID             string    `json:"id"`
+	Type           string    `json:"type"`
+	UserID         string    `json:"user_id"`
+	CustomerID     string    `json:"customer_id"`
+	SubscriptionID string    `json:"subscription_id"`
+	Status         string    `json:"status"`
+	OccurredAt     time.Time `json:"occurred_at"`
+}
+
+func (p *FakeBillingProvider) Name() string {
+	return "fake"
+}
+
+func (p *FakeBillingProvider) SignatureHeader() string {
+	return "X-Fake-Billing-Signature"
+}
+
+// Sign returns the signature header value for payload, e.g. for use with curl
+func (p *FakeBillingProvider) Sign(payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(p.webhookSecret))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// ParseWebhook verifies the signature and decodes the payload
+func (p *FakeBillingProvider) ParseWebhook(payload []byte, signature string) (model.BillingEvent, error) {
+	if !hmac.Equal([]byte(signature), []byte(p.Sign(payload))) {
+		return model.BillingEvent{}, fmt.Errorf("invalid webhook signature: %w", model.ErrInvalidInput)
+	}
+
+	var event fakeBillingEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return model.BillingEvent{}, fmt.Errorf("invalid webhook payload: %v: %w", err, model.ErrInvalidInput)
+	}
+
+	switch event.Type {
+	case model.BillingSubscriptionCreated, model.BillingSubscriptionUpdated, model.BillingSubscriptionCancelled:
+	default:
+		event.Type = ""
+	}
+
+	return model.BillingEvent{
+		ID:             event.ID,
+		Provider:       p.Name(),
+		Type:           event.Type,
+		UserID:         event.UserID,
+		CustomerID:     event.CustomerID,
+		SubscriptionID: event.SubscriptionID,
+		Status:         event.Status,
+		OccurredAt:     event.OccurredAt,
+	}, nil
+}
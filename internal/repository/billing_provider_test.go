@@ -0,0 +1,170 @@
+package repository
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/tsongpon/athena/internal/model"
+)
+
+// billingFixtures holds the signature manifest of testdata/billing
+type billingFixtures struct {
+	Secret     string            `json:"secret"`
+	SignedAt   int64             `json:"signed_at"`
+	Signatures map[string]string `json:"signatures"`
+}
+
+func loadBillingFixtures(t *testing.T) billingFixtures {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", "billing", "signatures.json"))
+	if err != nil {
+		t.Fatalf("failed to read signatures.json: %v", err)
+	}
+	var fixtures billingFixtures
+	if err := json.Unmarshal(data, &fixtures); err != nil {
+		t.Fatalf("failed to parse signatures.json: %v", err)
+	}
+	return fixtures
+}
+
+func readBillingFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	payload, err := os.ReadFile(filepath.Join("testdata", "billing", name))
+	if err != nil {
+		t.Fatalf("failed to read fixture %s: %v", name, err)
+	}
+	return payload
+}
+
+func newFixtureStripeProvider(fixtures billingFixtures) *StripeBillingProvider {
+	provider := NewStripeBillingProvider(fixtures.Secret)
+	provider.now = func() time.Time { return time.Unix(fixtures.SignedAt, 0).Add(time.Minute) }
+	return provider
+}
+
+func TestStripeBillingProvider_ParseWebhook(t *testing.T) {
+	fixtures := loadBillingFixtures(t)
+	provider := newFixtureStripeProvider(fixtures)
+
+	tests := []struct {
+		fixture    string
+		wantType   string
+		wantStatus string
+		wantPaid   bool
+	}{
+		{"stripe_subscription_created.json", model.BillingSubscriptionCreated, "active", true},
+		{"stripe_subscription_updated_past_due.json", model.BillingSubscriptionUpdated, "past_due", false},
+		{"stripe_subscription_deleted.json", model.BillingSubscriptionCancelled, "canceled", false},
+		{"stripe_invoice_paid.json", "", "paid", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			event, err := provider.ParseWebhook(readBillingFixture(t, tt.fixture), fixtures.Signatures[tt.fixture])
+			if err != nil {
+				t.Fatalf("ParseWebhook() unexpected error = %v", err)
+			}
+			if event.Provider != "stripe" {
+				t.Errorf("ParseWebhook() Provider = %v, want stripe", event.Provider)
+			}
+			if event.Type != tt.wantType {
+				t.Errorf("ParseWebhook() Type = %v, want %v", event.Type, tt.wantType)
+			}
+			if event.Status != tt.wantStatus {
+				t.Errorf("ParseWebhook() Status = %v, want %v", event.Status, tt.wantStatus)
+			}
+			if event.GrantsPaidTier() != tt.wantPaid {
+				t.Errorf("ParseWebhook() GrantsPaidTier = %v, want %v", event.GrantsPaidTier(), tt.wantPaid)
+			}
+		})
+	}
+
+	event, _ := provider.ParseWebhook(readBillingFixture(t, "stripe_subscription_created.json"), fixtures.Signatures["stripe_subscription_created.json"])
+	if event.ID != "evt_created_001" || event.UserID != "user-fixture" || event.CustomerID != "cus_QAthena" {
+		t.Errorf("ParseWebhook() decoded event = %+v", event)
+	}
+}
+
+func TestStripeBillingProvider_ParseWebhook_InvalidSignature(t *testing.T) {
+	fixtures := loadBillingFixtures(t)
+	payload := readBillingFixture(t, "stripe_subscription_created.json")
+	signature := fixtures.Signatures["stripe_subscription_created.json"]
+
+	tests := []struct {
+		name      string
+		provider  *StripeBillingProvider
+		payload   []byte
+		signature string
+	}{
+		{"wrong secret", newFixtureStripeProvider(billingFixtures{Secret: "whsec_other", SignedAt: fixtures.SignedAt}), payload, signature},
+		{"tampered payload", newFixtureStripeProvider(fixtures), append([]byte(" "), payload...), signature},
+		{"missing header", newFixtureStripeProvider(fixtures), payload, ""},
+		{"replayed", NewStripeBillingProvider(fixtures.Secret), payload, signature},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.provider.ParseWebhook(tt.payload, tt.signature)
+			if !errors.Is(err, model.ErrInvalidInput) {
+				t.Errorf("ParseWebhook() error = %v, want ErrInvalidInput", err)
+			}
+		})
+	}
+}
+
+func TestFakeBillingProvider_ParseWebhook(t *testing.T) {
+	fixtures := loadBillingFixtures(t)
+	provider := NewFakeBillingProvider(fixtures.Secret)
+
+	created := readBillingFixture(t, "fake_subscription_created.json")
+	if provider.Sign(created) != fixtures.Signatures["fake_subscription_created.json"] {
+		t.Error("Sign() does not match the fixture signature")
+	}
+
+	event, err := provider.ParseWebhook(created, fixtures.Signatures["fake_subscription_created.json"])
+	if err != nil {
+		t.Fatalf("ParseWebhook() unexpected error = %v", err)
+	}
+	if event.Type != model.BillingSubscriptionCreated || !event.GrantsPaidTier() || event.UserID != "user-fixture" {
+		t.Errorf("ParseWebhook() decoded event = %+v", event)
+	}
+
+	cancelled := readBillingFixture(t, "fake_subscription_cancelled.json")
+	event, err = provider.ParseWebhook(cancelled, fixtures.Signatures["fake_subscription_cancelled.json"])
+	if err != nil {
+		t.Fatalf("ParseWebhook() unexpected error = %v", err)
+	}
+	if event.Type != model.BillingSubscriptionCancelled || event.GrantsPaidTier() {
+		t.Errorf("ParseWebhook() decoded event = %+v", event)
+	}
+
+	if _, err := provider.ParseWebhook(created, "sha256=deadbeef"); !errors.Is(err, model.ErrInvalidInput) {
+		t.Errorf("ParseWebhook() with bad signature error = %v, want ErrInvalidInput", err)
+	}
+}
+
+func TestBillingEventInMemRepository(t *testing.T) {
+	repo := NewBillingEventInMemRepository()
+
+	processed, _ := repo.IsEventProcessed("stripe", "evt_1")
+	if processed {
+		t.Error("IsEventProcessed() should be false before MarkEventProcessed")
+	}
+
+	if err := repo.MarkEventProcessed(model.BillingEvent{ID: "evt_1", Provider: "stripe"}); err != nil {
+		t.Fatalf("MarkEventProcessed() unexpected error = %v", err)
+	}
+
+	processed, _ = repo.IsEventProcessed("stripe", "evt_1")
+	if !processed {
+		t.Error("IsEventProcessed() should be true after MarkEventProcessed")
+	}
+	processed, _ = repo.IsEventProcessed("fake", "evt_1")
+	if processed {
+		t.Error("IsEventProcessed() should be scoped by provider")
+	}
+}
@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tsongpon/athena/internal/model"
+)
+
+// stripeSignatureTolerance is how old a signed webhook may be before it is rejected as a replay
+const stripeSignatureTolerance = 5 * time.Minute
+
+// StripeBillingProvider implements BillingProvider for Stripe-compatible webhooks
+type StripeBillingProvider struct {
+	webhookSecret string
+	now           func() time.Time
+}
+
+// NewStripeBillingProvider creates a new instance of StripeBillingProvider
+func NewStripeBillingProvider(webhookSecret string) *StripeBillingProvider {
+	return &StripeBillingProvider{
+		webhookSecret: webhookSecret,
+		now:           time.Now,
+	}
+}
+
+// stripeEvent is the subset of a Stripe event used by Athena
+type stripeEvent struct {
+	ID      string `json:"id"`
+	Type    string `json:"type"`
+	Created int64  `json:"created"`
+	Data    struct {
+		Object struct {
+			ID       string            `json:"id"`
+			Customer string            `json:"customer"`
+			Status   string            `json:"status"`
+			Metadata map[string]string `json:"metadata"`
+		} `json:"object"`
+	} `json:"data"`
+}
+
+// stripeEventTypes maps Stripe subscription event types to provider-agnostic types
+var stripeEventTypes = map[string]string{
+	"customer.subscription.created": model.BillingSubscriptionCreated,
+	"customer.subscription.updated": model.BillingSubscriptionUpdated,
+	"customer.subscription.deleted": model.BillingSubscriptionCancelled,
+}
+
+func (p *StripeBillingProvider) Name() string {
+	return "stripe"
+}
+
+func (p *StripeBillingProvider) SignatureHeader() string {
+	return "Stripe-Signature"
+}
+
+// ParseWebhook verifies the Stripe-Signature header and decodes the event.
+// The Athena user ID is read from the subscription's "user_id" metadata.
+func (p *StripeBillingProvider) ParseWebhook(payload []byte, signature string) (model.BillingEvent, error) {
+	if err := p.verifySignature(payload, signature); err != nil {
+		return model.BillingEvent{}, fmt.Errorf("invalid webhook signature: %v: %w", err, model.ErrInvalidInput)
+	}
+
+	var event stripeEvent
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return model.BillingEvent{}, fmt.Errorf("invalid webhook payload: %v: %w", err, model.ErrInvalidInput)
+	}
+
+	subscription := event.Data.Object
+	return model.BillingEvent{
+		ID:             event.ID,
+		Provider:       p.Name(),
+		Type:           stripeEventTypes[event.Type],
+		UserID:         subscription.Metadata["user_id"],
+		CustomerID:     subscription.Customer,
+		SubscriptionID: subscription.ID,
+		Status:         subscription.Status,
+		OccurredAt:     time.Unix(event.Created, 0),
+	}, nil
+}
+
+// verifySignature checks a header of the form "t=<unix>,v1=<hex hmac>[,v1=...]" where the
+// HMAC-SHA256 is computed over "<t>.<payload>" with the webhook secret
+func (p *StripeBillingProvider) verifySignature(payload []byte, header string) error {
+	var timestamp string
+	var signatures []string
+	for _, part := range strings.Split(header, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			signatures = append(signatures, value)
+		}
+	}
+	if timestamp == "" || len(signatures) == 0 {
+		return fmt.Errorf("malformed signature header")
+	}
+
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("malformed signature timestamp")
+	}
+	if age := p.now().Sub(time.Unix(unix, 0)); age > stripeSignatureTolerance || age < -stripeSignatureTolerance {
+		return fmt.Errorf("signature timestamp outside tolerance")
+	}
+
+	expected := stripeSignature(p.webhookSecret, timestamp, payload)
+	for _, signature := range signatures {
+		if hmac.Equal([]byte(signature), []byte(expected)) {
+			return nil
+		}
+	}
+	return fmt.Errorf("no matching signature")
+}
+
+// stripeSignature computes the hex encoded v1 signature of payload at timestamp
+func stripeSignature(secret, timestamp string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
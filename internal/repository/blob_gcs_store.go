@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/tsongpon/athena/internal/logger"
+	"github.com/tsongpon/athena/internal/model"
+	"go.uber.org/zap"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/option"
+	storage "google.golang.org/api/storage/v1"
+)
+
+// GCSBlobStore implements BlobStore on a Google Cloud Storage bucket through the JSON API,
+// so it also works against GCS compatible servers and emulators via option.WithEndpoint
+type GCSBlobStore struct {
+	service *storage.Service
+	bucket  string
+}
+
+// NewGCSBlobStore creates a store over bucket. Credentials default to Application Default Credentials.
+func NewGCSBlobStore(ctx context.Context, bucket string, opts ...option.ClientOption) (*GCSBlobStore, error) {
+	service, err := storage.NewService(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create storage client: %w", err)
+	}
+	return &GCSBlobStore{
+		service: service,
+		bucket:  bucket,
+	}, nil
+}
+
+// PutBlob uploads the blob, replacing any object stored under the same key
+func (s *GCSBlobStore) PutBlob(ctx context.Context, blob model.Blob) error {
+	object := &storage.Object{
+		Name:        blob.Key,
+		ContentType: blob.ContentType,
+	}
+	_, err := s.service.Objects.Insert(s.bucket, object).
+		Media(bytes.NewReader(blob.Data), googleapi.ContentType(blob.ContentType)).
+		Context(ctx).
+		Do()
+	if err != nil {
+		logger.Error("Failed to upload blob to GCS",
+			zap.String("bucket", s.bucket),
+			zap.String("key", blob.Key),
+			zap.Error(err))
+		return fmt.Errorf("failed to upload blob %s: %w", blob.Key, err)
+	}
+	return nil
+}
+
+// GetBlob downloads an object and its metadata
+func (s *GCSBlobStore) GetBlob(ctx context.Context, key string) (model.Blob, error) {
+	resp, err := s.service.Objects.Get(s.bucket, key).Context(ctx).Download()
+	if isGCSNotFound(err) {
+		return model.Blob{}, fmt.Errorf("blob %s %w", key, model.ErrNotFound)
+	}
+	if err != nil {
+		return model.Blob{}, fmt.Errorf("failed to download blob %s: %w", key, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return model.Blob{}, fmt.Errorf("failed to read blob %s: %w", key, err)
+	}
+	updatedAt, _ := http.ParseTime(resp.Header.Get("Last-Modified"))
+	return model.Blob{
+		Key:         key,
+		ContentType: resp.Header.Get("Content-Type"),
+		Data:        data,
+		UpdatedAt:   updatedAt,
+	}, nil
+}
+
+// DeleteBlob deletes an object, ignoring objects that do not exist
+func (s *GCSBlobStore) DeleteBlob(ctx context.Context, key string) error {
+	err := s.service.Objects.Delete(s.bucket, key).Context(ctx).Do()
+	if err != nil && !isGCSNotFound(err) {
+		logger.Error("Failed to delete blob from GCS",
+			zap.String("bucket", s.bucket),
+			zap.String("key", key),
+			zap.Error(err))
+		return fmt.Errorf("failed to delete blob %s: %w", key, err)
+	}
+	return nil
+}
+
+func isGCSNotFound(err error) bool {
+	var apiErr *googleapi.Error
+	return errors.As(err, &apiErr) && apiErr.Code == http.StatusNotFound
+}
@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"mime"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/tsongpon/athena/internal/model"
+)
+
+// LocalBlobStore implements BlobStore on the local filesystem. Blobs are plain files
+// under the root directory and their content type is derived from the key's extension.
+type LocalBlobStore struct {
+	root string
+}
+
+// NewLocalBlobStore creates the root directory if needed and returns a store over it
+func NewLocalBlobStore(root string) (*LocalBlobStore, error) {
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create blob directory %s: %w", root, err)
+	}
+	return &LocalBlobStore{root: root}, nil
+}
+
+// PutBlob writes the blob to a temporary file and renames it into place so readers
+// never see a partial blob
+func (s *LocalBlobStore) PutBlob(ctx context.Context, blob model.Blob) error {
+	name, err := s.path(blob.Key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		return fmt.Errorf("failed to create directory for blob %s: %w", blob.Key, err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(name), ".blob-*")
+	if err != nil {
+		return fmt.Errorf("failed to create blob %s: %w", blob.Key, err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(blob.Data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write blob %s: %w", blob.Key, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write blob %s: %w", blob.Key, err)
+	}
+	if err := os.Rename(tmp.Name(), name); err != nil {
+		return fmt.Errorf("failed to store blob %s: %w", blob.Key, err)
+	}
+	return nil
+}
+
+// GetBlob reads a blob from disk
+func (s *LocalBlobStore) GetBlob(ctx context.Context, key string) (model.Blob, error) {
+	name, err := s.path(key)
+	if err != nil {
+		return model.Blob{}, err
+	}
+	info, err := os.Stat(name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return model.Blob{}, fmt.Errorf("blob %s %w", key, model.ErrNotFound)
+	}
+	if err != nil {
+		return model.Blob{}, fmt.Errorf("failed to stat blob %s: %w", key, err)
+	}
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return model.Blob{}, fmt.Errorf("failed to read blob %s: %w", key, err)
+	}
+
+	contentType := mime.TypeByExtension(path.Ext(key))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	return model.Blob{
+		Key:         key,
+		ContentType: contentType,
+		Data:        data,
+		UpdatedAt:   info.ModTime(),
+	}, nil
+}
+
+// DeleteBlob removes a blob from disk
+func (s *LocalBlobStore) DeleteBlob(ctx context.Context, key string) error {
+	name, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(name); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to delete blob %s: %w", key, err)
+	}
+	return nil
+}
+
+// path maps a key to a file under the root, rejecting keys that would escape it
+func (s *LocalBlobStore) path(key string) (string, error) {
+	if key == "" || strings.HasPrefix(key, "/") || path.Clean(key) != key || key == ".." || strings.HasPrefix(key, "../") {
+		return "", fmt.Errorf("invalid blob key %q: %w", key, model.ErrInvalidInput)
+	}
+	return filepath.Join(s.root, filepath.FromSlash(key)), nil
+}
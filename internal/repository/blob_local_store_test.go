@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/tsongpon/athena/internal/model"
+)
+
+func TestLocalBlobStore(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewLocalBlobStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewLocalBlobStore() unexpected error = %v", err)
+	}
+
+	if err := store.PutBlob(ctx, model.Blob{Key: "snapshots/b1.html", Data: []byte("<html></html>")}); err != nil {
+		t.Fatalf("PutBlob() unexpected error = %v", err)
+	}
+	blob, err := store.GetBlob(ctx, "snapshots/b1.html")
+	if err != nil {
+		t.Fatalf("GetBlob() unexpected error = %v", err)
+	}
+	if string(blob.Data) != "<html></html>" || blob.ContentType != "text/html; charset=utf-8" || blob.UpdatedAt.IsZero() {
+		t.Errorf("GetBlob() = %+v", blob)
+	}
+
+	if err := store.DeleteBlob(ctx, "snapshots/b1.html"); err != nil {
+		t.Fatalf("DeleteBlob() unexpected error = %v", err)
+	}
+	if _, err := store.GetBlob(ctx, "snapshots/b1.html"); !errors.Is(err, model.ErrNotFound) {
+		t.Errorf("GetBlob() after delete error = %v, want ErrNotFound", err)
+	}
+	if err := store.DeleteBlob(ctx, "snapshots/b1.html"); err != nil {
+		t.Errorf("DeleteBlob() of missing blob error = %v, want nil", err)
+	}
+}
+
+func TestLocalBlobStore_RejectsKeysOutsideRoot(t *testing.T) {
+	store, err := NewLocalBlobStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewLocalBlobStore() unexpected error = %v", err)
+	}
+
+	for _, key := range []string{"", "/etc/passwd", "../escape", "snapshots/../../escape", "./b1.html"} {
+		if err := store.PutBlob(context.Background(), model.Blob{Key: key}); !errors.Is(err, model.ErrInvalidInput) {
+			t.Errorf("PutBlob(%q) error = %v, want ErrInvalidInput", key, err)
+		}
+	}
+}
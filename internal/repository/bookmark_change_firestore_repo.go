@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/tsongpon/athena/internal/logger"
+	"github.com/tsongpon/athena/internal/model"
+	"go.uber.org/zap"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	bookmarkChangesCollection = "bookmark_changes"
+	// bookmarkChangeHeadsCollection holds one document per user with the sequence number of
+	// their last change. Appending changes updates it in the transaction that writes the
+	// bookmarks, which is what keeps the numbers of a user without gaps or duplicates across
+	// instances.
+	bookmarkChangeHeadsCollection = "bookmark_change_heads"
+)
+
+// firestoreBookmarkChange is the structure used to store/retrieve change log entries in Firestore
+type firestoreBookmarkChange struct {
+	UserID     string    `firestore:"user_id"`
+	Seq        int64     `firestore:"seq"`
+	BookmarkID string    `firestore:"bookmark_id"`
+	Kind       string    `firestore:"kind"`
+	At         time.Time `firestore:"at"`
+}
+
+// firestoreBookmarkChangeHead is the last sequence number of a user in Firestore
+type firestoreBookmarkChangeHead struct {
+	UserID string `firestore:"user_id"`
+	Seq    int64  `firestore:"seq"`
+}
+
+// newBookmarkChange is a change of the given kind to bookmark, to append with appendChanges
+func newBookmarkChange(bookmark model.Bookmark, kind string, at time.Time) model.BookmarkChange {
+	return model.BookmarkChange{
+		UserID:     bookmark.UserID,
+		BookmarkID: bookmark.ID,
+		Kind:       kind,
+		At:         at,
+	}
+}
+
+// appendChanges numbers changes with the next sequence numbers of their users and stores them
+// in tx. The numbers of a user are reserved with one read and one write of their head however
+// many changes there are. Firestore transactions read before they write, so it must be called
+// before the bookmark writes of tx.
+func (r *BookmarkFirestoreRepository) appendChanges(tx *firestore.Transaction, changes []model.BookmarkChange) error {
+	heads := make(map[string]*firestoreBookmarkChangeHead)
+	var refs []*firestore.DocumentRef
+	for _, change := range changes {
+		if _, ok := heads[change.UserID]; !ok {
+			heads[change.UserID] = &firestoreBookmarkChangeHead{UserID: change.UserID}
+			refs = append(refs, r.client.Collection(bookmarkChangeHeadsCollection).Doc(change.UserID))
+		}
+	}
+	if len(refs) == 0 {
+		return nil
+	}
+	docSnaps, err := tx.GetAll(refs)
+	if err != nil {
+		return err
+	}
+	for _, docSnap := range docSnaps {
+		if !docSnap.Exists() {
+			continue
+		}
+		if err := docSnap.DataTo(heads[docSnap.Ref.ID]); err != nil {
+			return err
+		}
+	}
+
+	for _, change := range changes {
+		head := heads[change.UserID]
+		head.Seq++
+		ref := r.client.Collection(bookmarkChangesCollection).Doc(fmt.Sprintf("%s-%020d", change.UserID, head.Seq))
+		if err := tx.Create(ref, firestoreBookmarkChange{
+			UserID:     change.UserID,
+			Seq:        head.Seq,
+			BookmarkID: change.BookmarkID,
+			Kind:       change.Kind,
+			At:         change.At,
+		}); err != nil {
+			return err
+		}
+	}
+	for _, ref := range refs {
+		if err := tx.Set(ref, *heads[ref.ID]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ListChanges retrieves up to limit changes of a user numbered after seq from Firestore, in order
+func (r *BookmarkFirestoreRepository) ListChanges(userID string, after int64, limit int) ([]model.BookmarkChange, error) {
+	iter := r.client.Collection(bookmarkChangesCollection).
+		Where("user_id", "==", userID).
+		Where("seq", ">", after).
+		OrderBy("seq", firestore.Asc).
+		Limit(limit).
+		Documents(r.ctx)
+	defer iter.Stop()
+
+	changes := []model.BookmarkChange{}
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			logger.Error("Failed to list bookmark changes from Firestore", zap.String("user_id", userID), zap.Error(err))
+			return nil, fmt.Errorf("failed to list bookmark changes: %w", err)
+		}
+
+		var fsChange firestoreBookmarkChange
+		if err := doc.DataTo(&fsChange); err != nil {
+			return nil, fmt.Errorf("failed to parse bookmark change data: %w", err)
+		}
+		changes = append(changes, model.BookmarkChange{
+			UserID:     fsChange.UserID,
+			Seq:        fsChange.Seq,
+			BookmarkID: fsChange.BookmarkID,
+			Kind:       fsChange.Kind,
+			At:         fsChange.At,
+		})
+	}
+
+	return changes, nil
+}
+
+// LatestChangeSeq returns the sequence number of the last change of a user, zero if none
+func (r *BookmarkFirestoreRepository) LatestChangeSeq(userID string) (int64, error) {
+	docSnap, err := r.client.Collection(bookmarkChangeHeadsCollection).Doc(userID).Get(r.ctx)
+	if status.Code(err) == codes.NotFound {
+		return 0, nil
+	}
+	if err != nil {
+		logger.Error("Failed to get bookmark change head from Firestore", zap.String("user_id", userID), zap.Error(err))
+		return 0, fmt.Errorf("failed to get latest bookmark change: %w", err)
+	}
+
+	var head firestoreBookmarkChangeHead
+	if err := docSnap.DataTo(&head); err != nil {
+		return 0, fmt.Errorf("failed to parse bookmark change head data: %w", err)
+	}
+
+	return head.Seq, nil
+}
@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"sort"
+	"time"
+
+	"github.com/tsongpon/athena/internal/model"
+)
+
+// appendChange numbers a change to bookmark with the next sequence number of its owner and
+// stores it. The caller holds the write lock.
+func (r *BookmarkInMemRepository) appendChange(bookmark model.Bookmark, kind string, at time.Time) {
+	log := r.changes[bookmark.UserID]
+	r.changes[bookmark.UserID] = append(log, model.BookmarkChange{
+		UserID:     bookmark.UserID,
+		Seq:        int64(len(log) + 1),
+		BookmarkID: bookmark.ID,
+		Kind:       kind,
+		At:         at,
+	})
+}
+
+// ListChanges retrieves up to limit changes of a user numbered after seq, in order
+func (r *BookmarkInMemRepository) ListChanges(userID string, after int64, limit int) ([]model.BookmarkChange, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	log := r.changes[userID]
+	start := sort.Search(len(log), func(i int) bool { return log[i].Seq > after })
+	changes := []model.BookmarkChange{}
+	for _, change := range log[start:] {
+		if len(changes) == limit {
+			break
+		}
+		changes = append(changes, change)
+	}
+
+	return changes, nil
+}
+
+// LatestChangeSeq returns the sequence number of the last change of a user, zero if none
+func (r *BookmarkInMemRepository) LatestChangeSeq(userID string) (int64, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	return int64(len(r.changes[userID])), nil
+}
@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/tsongpon/athena/internal/model"
+)
+
+func TestBookmarkInMemRepository_Changes(t *testing.T) {
+	repo := NewBookmarkInMemRepository()
+
+	first, _ := repo.CreateBookmark(model.Bookmark{UserID: "user-1", URL: "https://first.example.com"})
+	second, _ := repo.CreateBookmark(model.Bookmark{UserID: "user-1", URL: "https://second.example.com"})
+	repo.CreateBookmark(model.Bookmark{UserID: "user-2", URL: "https://other.example.com"})
+	// A write that publishes no event, such as a link check, is recorded too
+	checked := first
+	checked.Health.Status = model.LinkHealthBroken
+	checked, _ = repo.UpdateBookmark(checked)
+	archived := checked
+	archived.IsArchived = true
+	repo.WriteBookmarks([]model.BookmarkWrite{
+		{Bookmark: archived},
+		{Bookmark: second, Trash: true},
+		{Bookmark: model.Bookmark{ID: "missing", UserID: "user-1"}},
+	})
+	repo.RestoreBookmark(second.ID)
+	repo.DeleteBookmark(second.ID)
+
+	changes, err := repo.ListChanges("user-1", 0, 10)
+	if err != nil {
+		t.Fatalf("ListChanges() unexpected error = %v", err)
+	}
+	var got []string
+	for i, change := range changes {
+		if change.Seq != int64(i+1) || change.At.IsZero() {
+			t.Errorf("ListChanges()[%d] = %+v, want sequence number %d and a time", i, change, i+1)
+		}
+		got = append(got, change.BookmarkID+" "+change.Kind)
+	}
+	want := []string{
+		first.ID + " created", second.ID + " created", first.ID + " updated", first.ID + " updated",
+		second.ID + " deleted", second.ID + " created", second.ID + " deleted",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("ListChanges() = %v, want %v", got, want)
+	}
+
+	latest, err := repo.LatestChangeSeq("user-1")
+	if err != nil || latest != 7 {
+		t.Errorf("LatestChangeSeq() = %d, %v, want 7", latest, err)
+	}
+	if latest, _ := repo.LatestChangeSeq("user-2"); latest != 1 {
+		t.Errorf("LatestChangeSeq() of another user = %d, want 1", latest)
+	}
+
+	page, _ := repo.ListChanges("user-1", 1, 1)
+	if len(page) != 1 || page[0].Seq != 2 || page[0].BookmarkID != second.ID {
+		t.Errorf("ListChanges() = %+v, want the change numbered 2", page)
+	}
+	if changes, _ := repo.ListChanges("user-1", 7, 10); changes == nil || len(changes) != 0 {
+		t.Errorf("ListChanges() after the last change = %v, want an empty list", changes)
+	}
+	if latest, _ := repo.LatestChangeSeq("user-3"); latest != 0 {
+		t.Errorf("LatestChangeSeq() of a user without changes = %d, want 0", latest)
+	}
+}
+
+func TestBookmarkInMemRepository_Changes_FailedWrites(t *testing.T) {
+	repo := NewBookmarkInMemRepository()
+	bookmark, _ := repo.CreateBookmark(model.Bookmark{UserID: "user-1", URL: "https://example.com"})
+	repo.UpdateBookmark(bookmark)
+
+	// Writes that fail leave the change log alone
+	repo.UpdateBookmark(bookmark)
+	repo.WriteBookmarks([]model.BookmarkWrite{{Bookmark: bookmark, Trash: true}})
+	repo.TrashBookmark("missing", time.Now(), 0)
+
+	if latest, _ := repo.LatestChangeSeq("user-1"); latest != 2 {
+		t.Errorf("LatestChangeSeq() = %d, want 2", latest)
+	}
+}
@@ -1,8 +1,12 @@
 package repository
 
 import (
+	"cmp"
 	"context"
+	"errors"
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
 	"cloud.google.com/go/firestore"
@@ -11,11 +15,20 @@ import (
 	"github.com/tsongpon/athena/internal/model"
 	"go.uber.org/zap"
 	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 const bookmarksCollection = "bookmarks"
 
-// BookmarkFirestoreRepository implements BookmarkRepository interface using GCP Firestore
+// trashedBookmarksCollection holds bookmarks moved to the trash. They are kept out of the
+// bookmarks collection rather than flagged, so every query on it leaves them out, including
+// for documents written before the trash existed.
+const trashedBookmarksCollection = "trashed_bookmarks"
+
+// BookmarkFirestoreRepository implements BookmarkRepository interface using GCP Firestore. It
+// also implements BookmarkChangeRepository: every write appends to the change log of the
+// bookmark's owner in the same transaction.
 type BookmarkFirestoreRepository struct {
 	client *firestore.Client
 	ctx    context.Context
@@ -38,8 +51,73 @@ type firestoreBookmark struct {
 	IsArchived     bool      `firestore:"is_archived"`
 	MainImageURL   string    `firestore:"main_image_url"`
 	ContentSummary string    `firestore:"content_summary"`
+	CollectionIDs  []string  `firestore:"collection_ids"`
+	SnapshotKey    string    `firestore:"snapshot_key,omitempty"`
+	SnapshotAt     time.Time `firestore:"snapshot_at,omitempty"`
 	CreatedAt      time.Time `firestore:"created_at"`
 	UpdatedAt      time.Time `firestore:"updated_at"`
+	Version        int64     `firestore:"version"` // Zero for documents written before versioning
+
+	// Link health is flattened so it can be filtered on. health_checked_at is always written,
+	// as the zero time for unchecked bookmarks, so the link checker's range query finds them.
+	HealthStatus     string    `firestore:"health_status"`
+	HealthStatusCode int       `firestore:"health_status_code,omitempty"`
+	HealthFinalURL   string    `firestore:"health_final_url,omitempty"`
+	HealthError      string    `firestore:"health_error,omitempty"`
+	HealthCheckedAt  time.Time `firestore:"health_checked_at"`
+
+	Metadata firestorePageMetadata `firestore:"metadata"`
+
+	Thumbnails   map[string]string `firestore:"thumbnails,omitempty"`
+	ThumbnailsAt time.Time         `firestore:"thumbnails_at,omitempty"`
+
+	Enrichments []firestoreEnrichment `firestore:"enrichments,omitempty"`
+
+	Tags  []string `firestore:"tags,omitempty"`
+	Notes string   `firestore:"notes,omitempty"`
+
+	// The reading state is always written so the listing can filter on unread bookmarks
+	IsRead          bool      `firestore:"is_read"`
+	ReadAt          time.Time `firestore:"read_at,omitempty"`
+	IsFavorite      bool      `firestore:"is_favorite"`
+	ReadingProgress int       `firestore:"reading_progress"`
+
+	// Derived from the fields above so the listing can be filtered and sorted on them.
+	// last_opened_at is always written, as the zero time for never opened bookmarks, so
+	// sorting on it does not leave them out.
+	Domain           string    `firestore:"domain"`
+	SortTitle        string    `firestore:"sort_title"`
+	HasSummary       bool      `firestore:"has_summary"`
+	EnrichmentStatus string    `firestore:"enrichment_status"`
+	LastOpenedAt     time.Time `firestore:"last_opened_at"`
+
+	DeletedAt time.Time `firestore:"deleted_at,omitempty"` // Only set in the trash
+}
+
+// firestorePageMetadata represents the page metadata of a bookmark in Firestore
+type firestorePageMetadata struct {
+	Description   string    `firestore:"description,omitempty"`
+	SiteName      string    `firestore:"site_name,omitempty"`
+	Type          string    `firestore:"type,omitempty"`
+	Author        string    `firestore:"author,omitempty"`
+	PublishedAt   time.Time `firestore:"published_at,omitempty"`
+	FaviconURL    string    `firestore:"favicon_url,omitempty"`
+	PageCount     int       `firestore:"page_count,omitempty"`
+	WordCount     int       `firestore:"word_count,omitempty"`
+	EmbedProvider string    `firestore:"embed_provider,omitempty"`
+	EmbedURL      string    `firestore:"embed_url,omitempty"`
+	EmbedWidth    int       `firestore:"embed_width,omitempty"`
+	EmbedHeight   int       `firestore:"embed_height,omitempty"`
+}
+
+// firestoreEnrichment represents one entry of a bookmark's enrichment history in Firestore
+type firestoreEnrichment struct {
+	At              time.Time `firestore:"at"`
+	Trigger         string    `firestore:"trigger"`
+	Summary         string    `firestore:"summary"`
+	SummaryProvider string    `firestore:"summary_provider,omitempty"`
+	Updated         []string  `firestore:"updated,omitempty"`
+	Failed          []string  `firestore:"failed,omitempty"`
 }
 
 // toFirestoreBookmark converts model.Bookmark to firestoreBookmark
@@ -52,8 +130,53 @@ func toFirestoreBookmark(bookmark model.Bookmark) firestoreBookmark {
 		IsArchived:     bookmark.IsArchived,
 		MainImageURL:   bookmark.MainImageURL,
 		ContentSummary: bookmark.ContentSummary,
+		CollectionIDs:  bookmark.CollectionIDs,
+		SnapshotKey:    bookmark.SnapshotKey,
+		SnapshotAt:     bookmark.SnapshotAt,
+		Thumbnails:     bookmark.Thumbnails,
+		ThumbnailsAt:   bookmark.ThumbnailsAt,
 		CreatedAt:      bookmark.CreatedAt,
 		UpdatedAt:      bookmark.UpdatedAt,
+		Version:        bookmark.Version,
+
+		HealthStatus:     bookmark.Health.Status,
+		HealthStatusCode: bookmark.Health.StatusCode,
+		HealthFinalURL:   bookmark.Health.FinalURL,
+		HealthError:      bookmark.Health.Error,
+		HealthCheckedAt:  bookmark.Health.CheckedAt,
+
+		Metadata: firestorePageMetadata{
+			Description:   bookmark.Metadata.Description,
+			SiteName:      bookmark.Metadata.SiteName,
+			Type:          bookmark.Metadata.Type,
+			Author:        bookmark.Metadata.Author,
+			PublishedAt:   bookmark.Metadata.PublishedAt,
+			FaviconURL:    bookmark.Metadata.FaviconURL,
+			PageCount:     bookmark.Metadata.PageCount,
+			WordCount:     bookmark.Metadata.WordCount,
+			EmbedProvider: bookmark.Metadata.Embed.Provider,
+			EmbedURL:      bookmark.Metadata.Embed.URL,
+			EmbedWidth:    bookmark.Metadata.Embed.Width,
+			EmbedHeight:   bookmark.Metadata.Embed.Height,
+		},
+
+		Enrichments: toFirestoreEnrichments(bookmark.Enrichments),
+
+		Tags:  bookmark.Tags,
+		Notes: bookmark.Notes,
+
+		IsRead:          bookmark.IsRead,
+		ReadAt:          bookmark.ReadAt,
+		IsFavorite:      bookmark.IsFavorite,
+		ReadingProgress: bookmark.ReadingProgress,
+
+		Domain:           bookmark.Domain(),
+		SortTitle:        strings.ToLower(bookmark.Title),
+		HasSummary:       bookmark.ContentSummary != "",
+		EnrichmentStatus: bookmark.EnrichmentStatus(),
+		LastOpenedAt:     bookmark.LastOpenedAt,
+
+		DeletedAt: bookmark.DeletedAt,
 	}
 }
 
@@ -67,9 +190,80 @@ func toModelBookmark(fsBookmark firestoreBookmark) model.Bookmark {
 		IsArchived:     fsBookmark.IsArchived,
 		MainImageURL:   fsBookmark.MainImageURL,
 		ContentSummary: fsBookmark.ContentSummary,
+		CollectionIDs:  fsBookmark.CollectionIDs,
+		SnapshotKey:    fsBookmark.SnapshotKey,
+		SnapshotAt:     fsBookmark.SnapshotAt,
+		Thumbnails:     fsBookmark.Thumbnails,
+		ThumbnailsAt:   fsBookmark.ThumbnailsAt,
 		CreatedAt:      fsBookmark.CreatedAt,
 		UpdatedAt:      fsBookmark.UpdatedAt,
+		Version:        fsBookmark.Version,
+		Health: model.LinkHealth{
+			Status:     fsBookmark.HealthStatus,
+			StatusCode: fsBookmark.HealthStatusCode,
+			FinalURL:   fsBookmark.HealthFinalURL,
+			Error:      fsBookmark.HealthError,
+			CheckedAt:  fsBookmark.HealthCheckedAt,
+		},
+		Metadata: model.PageMetadata{
+			Description: fsBookmark.Metadata.Description,
+			SiteName:    fsBookmark.Metadata.SiteName,
+			Type:        fsBookmark.Metadata.Type,
+			Author:      fsBookmark.Metadata.Author,
+			PublishedAt: fsBookmark.Metadata.PublishedAt,
+			FaviconURL:  fsBookmark.Metadata.FaviconURL,
+			PageCount:   fsBookmark.Metadata.PageCount,
+			WordCount:   fsBookmark.Metadata.WordCount,
+			Embed: model.Embed{
+				Provider: fsBookmark.Metadata.EmbedProvider,
+				URL:      fsBookmark.Metadata.EmbedURL,
+				Width:    fsBookmark.Metadata.EmbedWidth,
+				Height:   fsBookmark.Metadata.EmbedHeight,
+			},
+		},
+		Enrichments:     toModelEnrichments(fsBookmark.Enrichments),
+		Tags:            fsBookmark.Tags,
+		Notes:           fsBookmark.Notes,
+		LastOpenedAt:    fsBookmark.LastOpenedAt,
+		IsRead:          fsBookmark.IsRead,
+		ReadAt:          fsBookmark.ReadAt,
+		IsFavorite:      fsBookmark.IsFavorite,
+		ReadingProgress: fsBookmark.ReadingProgress,
+		DeletedAt:       fsBookmark.DeletedAt,
+	}
+}
+
+func toFirestoreEnrichments(enrichments []model.Enrichment) []firestoreEnrichment {
+	fsEnrichments := make([]firestoreEnrichment, len(enrichments))
+	for i, e := range enrichments {
+		fsEnrichments[i] = firestoreEnrichment{
+			At:              e.At,
+			Trigger:         e.Trigger,
+			Summary:         e.Summary,
+			SummaryProvider: e.SummaryProvider,
+			Updated:         e.Updated,
+			Failed:          e.Failed,
+		}
+	}
+	return fsEnrichments
+}
+
+func toModelEnrichments(fsEnrichments []firestoreEnrichment) []model.Enrichment {
+	if len(fsEnrichments) == 0 {
+		return nil
+	}
+	enrichments := make([]model.Enrichment, len(fsEnrichments))
+	for i, e := range fsEnrichments {
+		enrichments[i] = model.Enrichment{
+			At:              e.At,
+			Trigger:         e.Trigger,
+			Summary:         e.Summary,
+			SummaryProvider: e.SummaryProvider,
+			Updated:         e.Updated,
+			Failed:          e.Failed,
+		}
 	}
+	return enrichments
 }
 
 // CreateBookmark creates a new bookmark in Firestore
@@ -86,12 +280,16 @@ func (r *BookmarkFirestoreRepository) CreateBookmark(bookmark model.Bookmark) (m
 
 	// Set updated time
 	bookmark.UpdatedAt = time.Now()
+	bookmark.Version = 1
 
-	// Convert to Firestore structure
-	fsBookmark := toFirestoreBookmark(bookmark)
-
-	// Store in Firestore using bookmark ID as document ID
-	_, err := r.client.Collection(bookmarksCollection).Doc(bookmark.ID).Set(r.ctx, fsBookmark)
+	// Store in Firestore using bookmark ID as document ID, along with its change
+	err := r.client.RunTransaction(r.ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		change := newBookmarkChange(bookmark, model.BookmarkChangeCreated, bookmark.UpdatedAt)
+		if err := r.appendChanges(tx, []model.BookmarkChange{change}); err != nil {
+			return err
+		}
+		return tx.Set(r.client.Collection(bookmarksCollection).Doc(bookmark.ID), toFirestoreBookmark(bookmark))
+	})
 	if err != nil {
 		logger.Error("Failed to create bookmark in Firestore",
 			zap.String("bookmark_id", bookmark.ID),
@@ -109,11 +307,14 @@ func (r *BookmarkFirestoreRepository) GetBookmark(id string) (model.Bookmark, er
 	logger.Debug("Getting bookmark from Firestore", zap.String("id", id))
 
 	docSnap, err := r.client.Collection(bookmarksCollection).Doc(id).Get(r.ctx)
+	if status.Code(err) == codes.NotFound {
+		return model.Bookmark{}, fmt.Errorf("bookmark with ID %s %w", id, model.ErrNotFound)
+	}
 	if err != nil {
 		logger.Error("Failed to get bookmark from Firestore",
 			zap.String("id", id),
 			zap.Error(err))
-		return model.Bookmark{}, fmt.Errorf("bookmark with ID %s not found: %w", id, err)
+		return model.Bookmark{}, fmt.Errorf("failed to get bookmark with ID %s: %w", id, err)
 	}
 
 	var fsBookmark firestoreBookmark
@@ -127,18 +328,104 @@ func (r *BookmarkFirestoreRepository) GetBookmark(id string) (model.Bookmark, er
 	return toModelBookmark(fsBookmark), nil
 }
 
+// bookmarkSortFields are the fields the bookmark listing is ordered by, by sort
+var bookmarkSortFields = map[string]string{
+	model.BookmarkSortCreated:    "created_at",
+	model.BookmarkSortUpdated:    "updated_at",
+	model.BookmarkSortTitle:      "sort_title",
+	model.BookmarkSortDomain:     "domain",
+	model.BookmarkSortLastOpened: "last_opened_at",
+}
+
+// bookmarkFilterFields are the optional filters of the bookmark listing, by field and operator
+var bookmarkFilterFields = []struct {
+	field, op string
+}{
+	{"collection_ids", "array-contains"},
+	{"tags", "array-contains"},
+	{"health_status", "=="},
+	{"domain", "=="},
+	{"has_summary", "=="},
+	{"enrichment_status", "=="},
+	{"is_read", "=="},
+	{"is_favorite", "=="},
+}
+
+// filterQuery builds the Firestore query matching the filters of query. Bookmarks written
+// before a filter field existed lack it, and only match once they are next updated.
+func (r *BookmarkFirestoreRepository) filterQuery(query model.BookmarkQuery) firestore.Query {
+	firestoreQuery := r.client.Collection(bookmarksCollection).
+		Where("user_id", "==", query.UserID).
+		Where("is_archived", "==", query.Archived)
+	if query.CollectionID != "" {
+		firestoreQuery = firestoreQuery.Where("collection_ids", "array-contains", query.CollectionID)
+	}
+	if query.Tag != "" {
+		firestoreQuery = firestoreQuery.Where("tags", "array-contains", query.Tag)
+	}
+	if query.Health != "" {
+		firestoreQuery = firestoreQuery.Where("health_status", "==", query.Health)
+	}
+	if query.Domain != "" {
+		firestoreQuery = firestoreQuery.Where("domain", "==", query.Domain)
+	}
+	if query.HasSummary != nil {
+		firestoreQuery = firestoreQuery.Where("has_summary", "==", *query.HasSummary)
+	}
+	if query.EnrichmentStatus != "" {
+		firestoreQuery = firestoreQuery.Where("enrichment_status", "==", query.EnrichmentStatus)
+	}
+	if query.Read != nil {
+		firestoreQuery = firestoreQuery.Where("is_read", "==", *query.Read)
+	}
+	if query.Favorite != nil {
+		firestoreQuery = firestoreQuery.Where("is_favorite", "==", *query.Favorite)
+	}
+	if !query.CreatedFrom.IsZero() {
+		firestoreQuery = firestoreQuery.Where("created_at", ">=", query.CreatedFrom)
+	}
+	if !query.CreatedTo.IsZero() {
+		firestoreQuery = firestoreQuery.Where("created_at", "<", query.CreatedTo)
+	}
+	return firestoreQuery
+}
+
+// bookmarkOrder returns the fields the listing sorted by sortBy is ordered by: the sort
+// field, then the creation time and document ID to break ties
+func bookmarkOrder(sortBy string) []string {
+	if sortBy == model.BookmarkSortCreated {
+		return []string{"created_at", firestore.DocumentID}
+	}
+	return []string{bookmarkSortFields[sortBy], "created_at", firestore.DocumentID}
+}
+
 // ListBookmarks retrieves all bookmarks based on the query parameters from Firestore
-// Returns bookmarks ordered by created date descending (newest first)
-// Supports pagination when Page and PageSize are greater than 0
+// Returns bookmarks in the query's sort order, newest created first by default
+// Supports pagination when Page and PageSize are greater than 0, or from a Position
 func (r *BookmarkFirestoreRepository) ListBookmarks(query model.BookmarkQuery) ([]model.Bookmark, error) {
 	// Build Firestore query
-	firestoreQuery := r.client.Collection(bookmarksCollection).
-		Where("user_id", "==", query.UserID).
-		Where("is_archived", "==", query.Archived).
-		OrderBy("created_at", firestore.Desc)
+	sortBy := cmp.Or(query.Sort, model.BookmarkSortCreated)
+	direction := firestore.Asc
+	if cmp.Or(query.Order, model.DefaultSortOrder(sortBy)) == model.SortDescending {
+		direction = firestore.Desc
+	}
+	firestoreQuery := r.filterQuery(query)
+	for _, field := range bookmarkOrder(sortBy) {
+		firestoreQuery = firestoreQuery.OrderBy(field, direction)
+	}
 
 	// Apply pagination if specified
-	if query.Page > 0 && query.PageSize > 0 {
+	if p := query.Position; p != nil {
+		values := []any{p.CreatedAt, p.ID}
+		if sortBy != model.BookmarkSortCreated {
+			values = append([]any{p.Key()}, values...)
+		}
+		if p.Backward {
+			firestoreQuery = firestoreQuery.EndBefore(values...).LimitToLast(query.PageSize)
+		} else {
+			firestoreQuery = firestoreQuery.StartAfter(values...).Limit(query.PageSize)
+		}
+	} else if query.Page > 0 && query.PageSize > 0 {
 		offset := (query.Page - 1) * query.PageSize
 		firestoreQuery = firestoreQuery.Limit(query.PageSize).Offset(offset)
 	}
@@ -182,14 +469,41 @@ func (r *BookmarkFirestoreRepository) ListBookmarks(query model.BookmarkQuery) (
 	return bookmarks, nil
 }
 
+// ListBookmarksCheckedBefore returns up to limit bookmarks whose link was last checked before
+// the given time, least recently checked first. Documents written before link health existed
+// have no health_checked_at field and are only picked up once they are next updated.
+func (r *BookmarkFirestoreRepository) ListBookmarksCheckedBefore(before time.Time, limit int) ([]model.Bookmark, error) {
+	iter := r.client.Collection(bookmarksCollection).
+		Where("health_checked_at", "<", before).
+		OrderBy("health_checked_at", firestore.Asc).
+		Limit(limit).
+		Documents(r.ctx)
+	defer iter.Stop()
+
+	var bookmarks []model.Bookmark
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			logger.Error("Failed to list bookmarks due for a link check from Firestore", zap.Error(err))
+			return nil, fmt.Errorf("failed to list bookmarks due for a link check: %w", err)
+		}
+
+		var fsBookmark firestoreBookmark
+		if err := doc.DataTo(&fsBookmark); err != nil {
+			return nil, fmt.Errorf("failed to parse bookmark data: %w", err)
+		}
+		bookmarks = append(bookmarks, toModelBookmark(fsBookmark))
+	}
+	return bookmarks, nil
+}
+
 // CountBookmarks returns the total count of bookmarks matching the query
 func (r *BookmarkFirestoreRepository) CountBookmarks(query model.BookmarkQuery) (int, error) {
 	// Build Firestore query and iterate to count
-	firestoreQuery := r.client.Collection(bookmarksCollection).
-		Where("user_id", "==", query.UserID).
-		Where("is_archived", "==", query.Archived)
-
-	iter := firestoreQuery.Documents(r.ctx)
+	iter := r.filterQuery(query).Documents(r.ctx)
 	defer iter.Stop()
 
 	count := 0
@@ -215,46 +529,373 @@ func (r *BookmarkFirestoreRepository) CountBookmarks(query model.BookmarkQuery)
 	return count, nil
 }
 
-// UpdateBookmark updates an existing bookmark in Firestore
+// UpdateBookmark updates an existing bookmark in Firestore in a transaction, if it is still at
+// the version it was read at
 func (r *BookmarkFirestoreRepository) UpdateBookmark(bookmark model.Bookmark) (model.Bookmark, error) {
-	// First, get the existing bookmark to preserve CreatedAt
-	existing, err := r.GetBookmark(bookmark.ID)
-	if err != nil {
+	ref := r.client.Collection(bookmarksCollection).Doc(bookmark.ID)
+	err := r.client.RunTransaction(r.ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		docSnap, err := tx.Get(ref)
+		if err != nil {
+			return err
+		}
+		var existing firestoreBookmark
+		if err := docSnap.DataTo(&existing); err != nil {
+			return err
+		}
+		if err := checkBookmarkVersion(bookmark, existing.Version); err != nil {
+			return err
+		}
+
+		// Preserve creation time
+		bookmark.CreatedAt = existing.CreatedAt
+		bookmark.UpdatedAt = time.Now()
+		bookmark.Version = existing.Version + 1
+		change := newBookmarkChange(bookmark, model.BookmarkChangeUpdated, bookmark.UpdatedAt)
+		if err := r.appendChanges(tx, []model.BookmarkChange{change}); err != nil {
+			return err
+		}
+		return tx.Set(ref, toFirestoreBookmark(bookmark))
+	})
+	if status.Code(err) == codes.NotFound {
+		return model.Bookmark{}, fmt.Errorf("bookmark with ID %s %w", bookmark.ID, model.ErrNotFound)
+	}
+	if errors.Is(err, model.ErrConflict) {
 		return model.Bookmark{}, err
 	}
+	if err != nil {
+		logger.Error("Failed to update bookmark in Firestore",
+			zap.String("bookmark_id", bookmark.ID),
+			zap.Error(err))
+		return model.Bookmark{}, fmt.Errorf("failed to update bookmark: %w", err)
+	}
 
-	// Preserve creation time
-	bookmark.CreatedAt = existing.CreatedAt
+	logger.Debug("Updated bookmark in Firestore", zap.String("id", bookmark.ID), zap.Int64("version", bookmark.Version))
+	return bookmark, nil
+}
 
-	// Set updated time
-	bookmark.UpdatedAt = time.Now()
+// GetBookmarks returns the bookmarks with the given IDs that exist, read in a single call
+func (r *BookmarkFirestoreRepository) GetBookmarks(ids []string) ([]model.Bookmark, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	refs := make([]*firestore.DocumentRef, len(ids))
+	for i, id := range ids {
+		refs[i] = r.client.Collection(bookmarksCollection).Doc(id)
+	}
+	docSnaps, err := r.client.GetAll(r.ctx, refs)
+	if err != nil {
+		logger.Error("Failed to get bookmarks from Firestore",
+			zap.Int("count", len(ids)),
+			zap.Error(err))
+		return nil, fmt.Errorf("failed to get bookmarks: %w", err)
+	}
+
+	var bookmarks []model.Bookmark
+	for _, docSnap := range docSnaps {
+		if !docSnap.Exists() {
+			continue
+		}
+		var fsBookmark firestoreBookmark
+		if err := docSnap.DataTo(&fsBookmark); err != nil {
+			return nil, fmt.Errorf("failed to parse bookmark data: %w", err)
+		}
+		bookmarks = append(bookmarks, toModelBookmark(fsBookmark))
+	}
+	return bookmarks, nil
+}
+
+// bookmarkWriteChunkSize is how many bookmarks WriteBookmarks writes per transaction. Moving a
+// bookmark to the trash takes three document writes with its change, so a chunk stays well
+// within what one transaction can commit.
+const bookmarkWriteChunkSize = 100
+
+// WriteBookmarks stores bookmarks or moves them to the trash in transactions of up to
+// bookmarkWriteChunkSize bookmarks, returning the error of each write. Chunks are written one
+// after the other and independently: a chunk that fails leaves those before it written.
+func (r *BookmarkFirestoreRepository) WriteBookmarks(writes []model.BookmarkWrite) []error {
+	errs := make([]error, 0, len(writes))
+	for chunk := range slices.Chunk(writes, bookmarkWriteChunkSize) {
+		errs = append(errs, r.writeBookmarkChunk(chunk)...)
+	}
+	return errs
+}
 
-	// Convert to Firestore structure
-	fsBookmark := toFirestoreBookmark(bookmark)
+// writeBookmarkChunk writes bookmarks in a transaction, which first reads them all to check
+// their versions. A bookmark that is missing or was changed since it was read is left out with
+// its error; the others are written together with their changes, or all fail.
+func (r *BookmarkFirestoreRepository) writeBookmarkChunk(writes []model.BookmarkWrite) []error {
+	refs := make([]*firestore.DocumentRef, len(writes))
+	for i, write := range writes {
+		refs[i] = r.client.Collection(bookmarksCollection).Doc(write.Bookmark.ID)
+	}
 
-	// Update in Firestore
-	_, err = r.client.Collection(bookmarksCollection).Doc(bookmark.ID).Set(r.ctx, fsBookmark)
+	var errs []error
+	err := r.client.RunTransaction(r.ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		// The transaction may run again on contention, start over each time
+		errs = make([]error, len(writes))
+		docSnaps, err := tx.GetAll(refs)
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		bookmarks := make([]model.Bookmark, len(writes))
+		var changes []model.BookmarkChange
+		for i, docSnap := range docSnaps {
+			bookmark := writes[i].Bookmark
+			if !docSnap.Exists() {
+				errs[i] = fmt.Errorf("bookmark with ID %s %w", bookmark.ID, model.ErrNotFound)
+				continue
+			}
+			var existing firestoreBookmark
+			if err := docSnap.DataTo(&existing); err != nil {
+				return err
+			}
+			if errs[i] = checkBookmarkVersion(bookmark, existing.Version); errs[i] != nil {
+				continue
+			}
+			// Preserve creation time
+			bookmark.CreatedAt = existing.CreatedAt
+			bookmark.UpdatedAt = now
+			bookmark.Version = existing.Version + 1
+			if writes[i].Trash {
+				changes = append(changes, newBookmarkChange(bookmark, model.BookmarkChangeDeleted, now))
+			} else {
+				changes = append(changes, newBookmarkChange(bookmark, model.BookmarkChangeUpdated, now))
+			}
+			bookmarks[i] = bookmark
+		}
+		if err := r.appendChanges(tx, changes); err != nil {
+			return err
+		}
+
+		for i, bookmark := range bookmarks {
+			if errs[i] != nil {
+				continue
+			}
+			if writes[i].Trash {
+				if err := tx.Set(r.client.Collection(trashedBookmarksCollection).Doc(bookmark.ID), toFirestoreBookmark(bookmark)); err != nil {
+					return err
+				}
+				if err := tx.Delete(refs[i]); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := tx.Set(refs[i], toFirestoreBookmark(bookmark)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 	if err != nil {
-		logger.Error("Failed to update bookmark in Firestore",
-			zap.String("bookmark_id", bookmark.ID),
+		logger.Error("Failed to write bookmarks to Firestore",
+			zap.Int("count", len(writes)),
 			zap.Error(err))
-		return model.Bookmark{}, fmt.Errorf("failed to update bookmark: %w", err)
+		errs = make([]error, len(writes))
+		for i := range errs {
+			errs[i] = fmt.Errorf("failed to write bookmark: %w", err)
+		}
+		return errs
 	}
 
-	logger.Debug("Updated bookmark in Firestore", zap.String("id", bookmark.ID))
+	failed := 0
+	for _, err := range errs {
+		if err != nil {
+			failed++
+		}
+	}
+	logger.Debug("Wrote bookmarks to Firestore",
+		zap.Int("count", len(writes)),
+		zap.Int("failed", failed))
+	return errs
+}
+
+// TrashBookmark moves a bookmark to the trash collection in a transaction, if it is still at
+// the given version
+func (r *BookmarkFirestoreRepository) TrashBookmark(id string, deletedAt time.Time, version int64) (model.Bookmark, error) {
+	bookmark, err := r.moveBookmark(id, bookmarksCollection, trashedBookmarksCollection, func(fsBookmark *firestoreBookmark) (model.BookmarkChange, error) {
+		if err := checkBookmarkVersion(model.Bookmark{ID: id, Version: version}, fsBookmark.Version); err != nil {
+			return model.BookmarkChange{}, err
+		}
+		fsBookmark.DeletedAt = deletedAt
+		return newBookmarkChange(toModelBookmark(*fsBookmark), model.BookmarkChangeDeleted, deletedAt), nil
+	})
+	if err != nil {
+		return model.Bookmark{}, err
+	}
+	logger.Debug("Moved bookmark to the trash in Firestore", zap.String("id", id))
 	return bookmark, nil
 }
 
-// DeleteBookmark removes a bookmark from Firestore
-func (r *BookmarkFirestoreRepository) DeleteBookmark(id string) error {
-	// Check if bookmark exists before deleting
-	_, err := r.GetBookmark(id)
+// RestoreBookmark moves a bookmark from the trash collection back in a transaction
+func (r *BookmarkFirestoreRepository) RestoreBookmark(id string) (model.Bookmark, error) {
+	bookmark, err := r.moveBookmark(id, trashedBookmarksCollection, bookmarksCollection, func(fsBookmark *firestoreBookmark) (model.BookmarkChange, error) {
+		fsBookmark.DeletedAt = time.Time{}
+		return newBookmarkChange(toModelBookmark(*fsBookmark), model.BookmarkChangeCreated, time.Now()), nil
+	})
+	if err != nil {
+		return model.Bookmark{}, err
+	}
+	logger.Debug("Restored bookmark from the trash in Firestore", zap.String("id", id))
+	return bookmark, nil
+}
+
+// moveBookmark moves the document of a bookmark from one collection to another at its next
+// version, changed by change, which returns the change to record in the change log. An error
+// from change stops the move as is.
+func (r *BookmarkFirestoreRepository) moveBookmark(id, from, to string, change func(fsBookmark *firestoreBookmark) (model.BookmarkChange, error)) (model.Bookmark, error) {
+	var fsBookmark firestoreBookmark
+	err := r.client.RunTransaction(r.ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		source := r.client.Collection(from).Doc(id)
+		docSnap, err := tx.Get(source)
+		if err != nil {
+			return err
+		}
+		if err := docSnap.DataTo(&fsBookmark); err != nil {
+			return err
+		}
+		bookmarkChange, err := change(&fsBookmark)
+		if err != nil {
+			return err
+		}
+		fsBookmark.UpdatedAt = time.Now()
+		fsBookmark.Version++
+		if err := r.appendChanges(tx, []model.BookmarkChange{bookmarkChange}); err != nil {
+			return err
+		}
+		if err := tx.Set(r.client.Collection(to).Doc(id), fsBookmark); err != nil {
+			return err
+		}
+		return tx.Delete(source)
+	})
+	if status.Code(err) == codes.NotFound {
+		return model.Bookmark{}, fmt.Errorf("bookmark with ID %s %w", id, model.ErrNotFound)
+	}
+	if errors.Is(err, model.ErrConflict) {
+		return model.Bookmark{}, err
+	}
+	if err != nil {
+		logger.Error("Failed to move bookmark in Firestore",
+			zap.String("id", id),
+			zap.String("from", from),
+			zap.String("to", to),
+			zap.Error(err))
+		return model.Bookmark{}, fmt.Errorf("failed to move bookmark: %w", err)
+	}
+	return toModelBookmark(fsBookmark), nil
+}
+
+// GetTrashedBookmark retrieves a bookmark in the trash by its ID
+func (r *BookmarkFirestoreRepository) GetTrashedBookmark(id string) (model.Bookmark, error) {
+	docSnap, err := r.client.Collection(trashedBookmarksCollection).Doc(id).Get(r.ctx)
+	if status.Code(err) == codes.NotFound {
+		return model.Bookmark{}, fmt.Errorf("trashed bookmark with ID %s %w", id, model.ErrNotFound)
+	}
+	if err != nil {
+		logger.Error("Failed to get trashed bookmark from Firestore",
+			zap.String("id", id),
+			zap.Error(err))
+		return model.Bookmark{}, fmt.Errorf("failed to get trashed bookmark with ID %s: %w", id, err)
+	}
+
+	var fsBookmark firestoreBookmark
+	if err := docSnap.DataTo(&fsBookmark); err != nil {
+		return model.Bookmark{}, fmt.Errorf("failed to parse bookmark data: %w", err)
+	}
+	return toModelBookmark(fsBookmark), nil
+}
+
+// ListTrashedBookmarks returns the bookmarks of a user in the trash, most recently deleted first
+func (r *BookmarkFirestoreRepository) ListTrashedBookmarks(userID string) ([]model.Bookmark, error) {
+	query := r.client.Collection(trashedBookmarksCollection).
+		Where("user_id", "==", userID).
+		OrderBy("deleted_at", firestore.Desc)
+	bookmarks, err := r.listTrashed(query)
+	if err != nil {
+		logger.Error("Failed to list trashed bookmarks from Firestore",
+			zap.String("user_id", userID),
+			zap.Error(err))
+		return nil, err
+	}
+	return bookmarks, nil
+}
+
+// ListTrashedBefore returns up to limit bookmarks of any user that were moved to the trash
+// before the given time, oldest first
+func (r *BookmarkFirestoreRepository) ListTrashedBefore(before time.Time, limit int) ([]model.Bookmark, error) {
+	query := r.client.Collection(trashedBookmarksCollection).
+		Where("deleted_at", "<", before).
+		OrderBy("deleted_at", firestore.Asc).
+		Limit(limit)
+	bookmarks, err := r.listTrashed(query)
+	if err != nil {
+		logger.Error("Failed to list expired trashed bookmarks from Firestore", zap.Error(err))
+		return nil, err
+	}
+	return bookmarks, nil
+}
+
+func (r *BookmarkFirestoreRepository) listTrashed(query firestore.Query) ([]model.Bookmark, error) {
+	iter := query.Documents(r.ctx)
+	defer iter.Stop()
+
+	var bookmarks []model.Bookmark
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list trashed bookmarks: %w", err)
+		}
+
+		var fsBookmark firestoreBookmark
+		if err := doc.DataTo(&fsBookmark); err != nil {
+			return nil, fmt.Errorf("failed to parse bookmark data: %w", err)
+		}
+		bookmarks = append(bookmarks, toModelBookmark(fsBookmark))
+	}
+	return bookmarks, nil
+}
+
+// PurgeBookmark permanently deletes a bookmark from the trash collection
+func (r *BookmarkFirestoreRepository) PurgeBookmark(id string) error {
+	_, err := r.client.Collection(trashedBookmarksCollection).Doc(id).Delete(r.ctx, firestore.Exists)
+	if status.Code(err) == codes.NotFound {
+		return fmt.Errorf("trashed bookmark with ID %s %w", id, model.ErrNotFound)
+	}
 	if err != nil {
-		return err
+		logger.Error("Failed to purge bookmark from Firestore",
+			zap.String("id", id),
+			zap.Error(err))
+		return fmt.Errorf("failed to purge bookmark: %w", err)
 	}
 
-	// Delete from Firestore
-	_, err = r.client.Collection(bookmarksCollection).Doc(id).Delete(r.ctx)
+	logger.Debug("Purged bookmark from Firestore", zap.String("id", id))
+	return nil
+}
+
+// DeleteBookmark removes a bookmark from Firestore in a transaction that records its deletion
+func (r *BookmarkFirestoreRepository) DeleteBookmark(id string) error {
+	ref := r.client.Collection(bookmarksCollection).Doc(id)
+	err := r.client.RunTransaction(r.ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		docSnap, err := tx.Get(ref)
+		if err != nil {
+			return err
+		}
+		var fsBookmark firestoreBookmark
+		if err := docSnap.DataTo(&fsBookmark); err != nil {
+			return err
+		}
+		change := newBookmarkChange(toModelBookmark(fsBookmark), model.BookmarkChangeDeleted, time.Now())
+		if err := r.appendChanges(tx, []model.BookmarkChange{change}); err != nil {
+			return err
+		}
+		return tx.Delete(ref)
+	})
+	if status.Code(err) == codes.NotFound {
+		return fmt.Errorf("bookmark with ID %s %w", id, model.ErrNotFound)
+	}
 	if err != nil {
 		logger.Error("Failed to delete bookmark from Firestore",
 			zap.String("id", id),
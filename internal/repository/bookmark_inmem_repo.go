@@ -1,7 +1,9 @@
 package repository
 
 import (
+	"cmp"
 	"fmt"
+	"slices"
 	"sort"
 	"sync"
 	"time"
@@ -12,9 +14,13 @@ import (
 	"go.uber.org/zap"
 )
 
-// BookmarkInMemRepository implements BookmarkRepository interface using an in-memory map
+// BookmarkInMemRepository implements BookmarkRepository interface using an in-memory map. It
+// also implements BookmarkChangeRepository: every write appends to the change log of the
+// bookmark's owner under the same lock.
 type BookmarkInMemRepository struct {
 	bookmarks map[string]model.Bookmark
+	trash     map[string]model.Bookmark         // Bookmarks moved to the trash, kept apart from live ones
+	changes   map[string][]model.BookmarkChange // By user ID, in sequence order
 	mutex     sync.RWMutex
 }
 
@@ -22,6 +28,8 @@ type BookmarkInMemRepository struct {
 func NewBookmarkInMemRepository() *BookmarkInMemRepository {
 	return &BookmarkInMemRepository{
 		bookmarks: make(map[string]model.Bookmark),
+		trash:     make(map[string]model.Bookmark),
+		changes:   make(map[string][]model.BookmarkChange),
 		mutex:     sync.RWMutex{},
 	}
 }
@@ -36,9 +44,12 @@ func (r *BookmarkInMemRepository) CreateBookmark(bookmark model.Bookmark) (model
 	if bookmark.CreatedAt.IsZero() {
 		bookmark.CreatedAt = time.Now()
 	}
+	bookmark.UpdatedAt = time.Now()
+	bookmark.Version = 1
 
 	// Store the bookmark
 	r.bookmarks[bookmark.ID] = bookmark
+	r.appendChange(bookmark, model.BookmarkChangeCreated, bookmark.UpdatedAt)
 
 	return bookmark, nil
 }
@@ -52,15 +63,15 @@ func (r *BookmarkInMemRepository) GetBookmark(id string) (model.Bookmark, error)
 	logger.Debug("Getting bookmark", zap.String("id", id))
 	bookmark, exists := r.bookmarks[id]
 	if !exists {
-		return model.Bookmark{}, fmt.Errorf("bookmark with ID %s not found", id)
+		return model.Bookmark{}, fmt.Errorf("bookmark with ID %s %w", id, model.ErrNotFound)
 	}
 
 	return bookmark, nil
 }
 
 // ListBookmarks retrieves all bookmarks based on the query parameters
-// Returns bookmarks ordered by created date descending (newest first)
-// Supports pagination when Page and PageSize are greater than 0
+// Returns bookmarks in the query's sort order, newest created first by default
+// Supports pagination when Page and PageSize are greater than 0, or from a Position
 func (r *BookmarkInMemRepository) ListBookmarks(query model.BookmarkQuery) ([]model.Bookmark, error) {
 	r.mutex.RLock()
 	defer r.mutex.RUnlock()
@@ -68,16 +79,39 @@ func (r *BookmarkInMemRepository) ListBookmarks(query model.BookmarkQuery) ([]mo
 	var userBookmarks []model.Bookmark
 
 	for _, bookmark := range r.bookmarks {
-		if bookmark.UserID == query.UserID && bookmark.IsArchived == query.Archived {
+		if matchesBookmarkQuery(bookmark, query) {
 			userBookmarks = append(userBookmarks, bookmark)
 		}
 	}
 
-	// Sort by created date descending (newest first)
+	// Sort by the query's sort key, ties broken by ID
+	sortBy := cmp.Or(query.Sort, model.BookmarkSortCreated)
+	order := cmp.Or(query.Order, model.DefaultSortOrder(sortBy))
 	sort.Slice(userBookmarks, func(i, j int) bool {
-		return userBookmarks[i].CreatedAt.After(userBookmarks[j].CreatedAt)
+		return model.PositionOf(userBookmarks[j], sortBy, order, false).ListedBefore(userBookmarks[i])
 	})
 
+	// Keyset pagination from a position
+	if p := query.Position; p != nil {
+		start, end := 0, len(userBookmarks)
+		for i, bookmark := range userBookmarks {
+			if !p.ListedBefore(bookmark) {
+				// The first bookmark at or after the position
+				start, end = i, i
+				break
+			}
+		}
+		if p.Backward {
+			start = max(0, end-query.PageSize)
+		} else {
+			if start < len(userBookmarks) && userBookmarks[start].ID == p.ID {
+				start++
+			}
+			end = min(len(userBookmarks), start+query.PageSize)
+		}
+		return userBookmarks[start:end], nil
+	}
+
 	// Apply pagination if specified
 	if query.Page > 0 && query.PageSize > 0 {
 		start := (query.Page - 1) * query.PageSize
@@ -97,6 +131,35 @@ func (r *BookmarkInMemRepository) ListBookmarks(query model.BookmarkQuery) ([]mo
 	return userBookmarks, nil
 }
 
+// matchesBookmarkQuery reports whether bookmark satisfies the filters of query
+func matchesBookmarkQuery(bookmark model.Bookmark, query model.BookmarkQuery) bool {
+	switch {
+	case bookmark.UserID != query.UserID || bookmark.IsArchived != query.Archived:
+		return false
+	case query.CollectionID != "" && !slices.Contains(bookmark.CollectionIDs, query.CollectionID):
+		return false
+	case query.Health != "" && bookmark.Health.Status != query.Health:
+		return false
+	case query.Domain != "" && bookmark.Domain() != query.Domain:
+		return false
+	case query.Tag != "" && !slices.Contains(bookmark.Tags, query.Tag):
+		return false
+	case query.HasSummary != nil && (bookmark.ContentSummary != "") != *query.HasSummary:
+		return false
+	case query.EnrichmentStatus != "" && bookmark.EnrichmentStatus() != query.EnrichmentStatus:
+		return false
+	case query.Read != nil && bookmark.IsRead != *query.Read:
+		return false
+	case query.Favorite != nil && bookmark.IsFavorite != *query.Favorite:
+		return false
+	case !query.CreatedFrom.IsZero() && bookmark.CreatedAt.Before(query.CreatedFrom):
+		return false
+	case !query.CreatedTo.IsZero() && !bookmark.CreatedAt.Before(query.CreatedTo):
+		return false
+	}
+	return true
+}
+
 // CountBookmarks returns the total count of bookmarks matching the query
 func (r *BookmarkInMemRepository) CountBookmarks(query model.BookmarkQuery) (int, error) {
 	r.mutex.RLock()
@@ -104,7 +167,7 @@ func (r *BookmarkInMemRepository) CountBookmarks(query model.BookmarkQuery) (int
 
 	count := 0
 	for _, bookmark := range r.bookmarks {
-		if bookmark.UserID == query.UserID && bookmark.IsArchived == query.Archived {
+		if matchesBookmarkQuery(bookmark, query) {
 			count++
 		}
 	}
@@ -112,7 +175,8 @@ func (r *BookmarkInMemRepository) CountBookmarks(query model.BookmarkQuery) (int
 	return count, nil
 }
 
-// UpdateBookmark updates an existing bookmark in the repository
+// UpdateBookmark updates an existing bookmark in the repository, if it is still at the version
+// it was read at
 func (r *BookmarkInMemRepository) UpdateBookmark(bookmark model.Bookmark) (model.Bookmark, error) {
 	r.mutex.Lock()
 	defer r.mutex.Unlock()
@@ -120,30 +184,211 @@ func (r *BookmarkInMemRepository) UpdateBookmark(bookmark model.Bookmark) (model
 	// Check if bookmark exists
 	existing, exists := r.bookmarks[bookmark.ID]
 	if !exists {
-		return model.Bookmark{}, fmt.Errorf("bookmark with ID %s not found", bookmark.ID)
+		return model.Bookmark{}, fmt.Errorf("bookmark with ID %s %w", bookmark.ID, model.ErrNotFound)
+	}
+	if err := checkBookmarkVersion(bookmark, existing.Version); err != nil {
+		return model.Bookmark{}, err
 	}
 
 	// Preserve creation time from existing bookmark
 	bookmark.CreatedAt = existing.CreatedAt
+	bookmark.UpdatedAt = time.Now()
+	bookmark.Version = existing.Version + 1
 
 	// Update the bookmark
 	r.bookmarks[bookmark.ID] = bookmark
+	r.appendChange(bookmark, model.BookmarkChangeUpdated, bookmark.UpdatedAt)
 
 	return bookmark, nil
 }
 
+// GetBookmarks returns the bookmarks with the given IDs that exist
+func (r *BookmarkInMemRepository) GetBookmarks(ids []string) ([]model.Bookmark, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	var bookmarks []model.Bookmark
+	for _, id := range ids {
+		if bookmark, exists := r.bookmarks[id]; exists {
+			bookmarks = append(bookmarks, bookmark)
+		}
+	}
+	return bookmarks, nil
+}
+
+// WriteBookmarks stores bookmarks or moves them to the trash if they are still at the version
+// they were read at, returning the error of each write
+func (r *BookmarkInMemRepository) WriteBookmarks(writes []model.BookmarkWrite) []error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	errs := make([]error, len(writes))
+	now := time.Now()
+	for i, write := range writes {
+		existing, exists := r.bookmarks[write.Bookmark.ID]
+		if !exists {
+			errs[i] = fmt.Errorf("bookmark with ID %s %w", write.Bookmark.ID, model.ErrNotFound)
+			continue
+		}
+		if errs[i] = checkBookmarkVersion(write.Bookmark, existing.Version); errs[i] != nil {
+			continue
+		}
+		// Preserve creation time from existing bookmark
+		write.Bookmark.CreatedAt = existing.CreatedAt
+		write.Bookmark.UpdatedAt = now
+		write.Bookmark.Version = existing.Version + 1
+		if write.Trash {
+			delete(r.bookmarks, write.Bookmark.ID)
+			r.trash[write.Bookmark.ID] = write.Bookmark
+			r.appendChange(write.Bookmark, model.BookmarkChangeDeleted, now)
+			continue
+		}
+		r.bookmarks[write.Bookmark.ID] = write.Bookmark
+		r.appendChange(write.Bookmark, model.BookmarkChangeUpdated, now)
+	}
+	return errs
+}
+
+// ListBookmarksCheckedBefore returns up to limit bookmarks whose link was last checked before
+// the given time, least recently checked first
+func (r *BookmarkInMemRepository) ListBookmarksCheckedBefore(before time.Time, limit int) ([]model.Bookmark, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	var due []model.Bookmark
+	for _, bookmark := range r.bookmarks {
+		if bookmark.Health.CheckedAt.Before(before) {
+			due = append(due, bookmark)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool {
+		return due[i].Health.CheckedAt.Before(due[j].Health.CheckedAt)
+	})
+	if limit > 0 && len(due) > limit {
+		due = due[:limit]
+	}
+	return due, nil
+}
+
 // DeleteBookmark removes a bookmark from the repository
 func (r *BookmarkInMemRepository) DeleteBookmark(id string) error {
 	r.mutex.Lock()
 	defer r.mutex.Unlock()
 
 	// Check if bookmark exists
-	if _, exists := r.bookmarks[id]; !exists {
-		return fmt.Errorf("bookmark with ID %s not found", id)
+	bookmark, exists := r.bookmarks[id]
+	if !exists {
+		return fmt.Errorf("bookmark with ID %s %w", id, model.ErrNotFound)
 	}
 
 	// Delete the bookmark
 	delete(r.bookmarks, id)
+	r.appendChange(bookmark, model.BookmarkChangeDeleted, time.Now())
+
+	return nil
+}
+
+// TrashBookmark moves a bookmark to the trash at its next version if it is still at the given
+// one, recording when it was deleted
+func (r *BookmarkInMemRepository) TrashBookmark(id string, deletedAt time.Time, version int64) (model.Bookmark, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	bookmark, exists := r.bookmarks[id]
+	if !exists {
+		return model.Bookmark{}, fmt.Errorf("bookmark with ID %s %w", id, model.ErrNotFound)
+	}
+	if err := checkBookmarkVersion(model.Bookmark{ID: id, Version: version}, bookmark.Version); err != nil {
+		return model.Bookmark{}, err
+	}
+	bookmark.DeletedAt = deletedAt
+	bookmark.UpdatedAt = time.Now()
+	bookmark.Version++
+	delete(r.bookmarks, id)
+	r.trash[id] = bookmark
+	r.appendChange(bookmark, model.BookmarkChangeDeleted, deletedAt)
+
+	return bookmark, nil
+}
+
+// GetTrashedBookmark retrieves a bookmark in the trash by its ID
+func (r *BookmarkInMemRepository) GetTrashedBookmark(id string) (model.Bookmark, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	bookmark, exists := r.trash[id]
+	if !exists {
+		return model.Bookmark{}, fmt.Errorf("trashed bookmark with ID %s %w", id, model.ErrNotFound)
+	}
+	return bookmark, nil
+}
+
+// ListTrashedBookmarks returns the bookmarks of a user in the trash, most recently deleted first
+func (r *BookmarkInMemRepository) ListTrashedBookmarks(userID string) ([]model.Bookmark, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	var trashed []model.Bookmark
+	for _, bookmark := range r.trash {
+		if bookmark.UserID == userID {
+			trashed = append(trashed, bookmark)
+		}
+	}
+	slices.SortFunc(trashed, func(a, b model.Bookmark) int {
+		return cmp.Or(b.DeletedAt.Compare(a.DeletedAt), cmp.Compare(a.ID, b.ID))
+	})
+	return trashed, nil
+}
+
+// ListTrashedBefore returns up to limit bookmarks of any user that were moved to the trash
+// before the given time, oldest first
+func (r *BookmarkInMemRepository) ListTrashedBefore(before time.Time, limit int) ([]model.Bookmark, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	var expired []model.Bookmark
+	for _, bookmark := range r.trash {
+		if bookmark.DeletedAt.Before(before) {
+			expired = append(expired, bookmark)
+		}
+	}
+	slices.SortFunc(expired, func(a, b model.Bookmark) int {
+		return cmp.Or(a.DeletedAt.Compare(b.DeletedAt), cmp.Compare(a.ID, b.ID))
+	})
+	if limit > 0 && len(expired) > limit {
+		expired = expired[:limit]
+	}
+	return expired, nil
+}
+
+// RestoreBookmark moves a bookmark out of the trash at its next version
+func (r *BookmarkInMemRepository) RestoreBookmark(id string) (model.Bookmark, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	bookmark, exists := r.trash[id]
+	if !exists {
+		return model.Bookmark{}, fmt.Errorf("trashed bookmark with ID %s %w", id, model.ErrNotFound)
+	}
+	bookmark.DeletedAt = time.Time{}
+	bookmark.UpdatedAt = time.Now()
+	bookmark.Version++
+	delete(r.trash, id)
+	r.bookmarks[id] = bookmark
+	r.appendChange(bookmark, model.BookmarkChangeCreated, bookmark.UpdatedAt)
+
+	return bookmark, nil
+}
+
+// PurgeBookmark permanently removes a bookmark from the trash
+func (r *BookmarkInMemRepository) PurgeBookmark(id string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, exists := r.trash[id]; !exists {
+		return fmt.Errorf("trashed bookmark with ID %s %w", id, model.ErrNotFound)
+	}
+	delete(r.trash, id)
 
 	return nil
 }
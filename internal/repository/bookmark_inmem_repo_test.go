@@ -1,7 +1,10 @@
 package repository
 
 import (
+	"cmp"
+	"errors"
 	"fmt"
+	"slices"
 	"sync"
 	"testing"
 	"time"
@@ -115,10 +118,11 @@ func TestBookmarkInMemRepository_GetBookmark_AfterUpdate(t *testing.T) {
 
 	// Update the bookmark
 	updatedBookmark := model.Bookmark{
-		ID:     created.ID,
-		UserID: "user1",
-		URL:    "https://updated.com",
-		Title:  "Updated Title",
+		ID:      created.ID,
+		UserID:  "user1",
+		URL:     "https://updated.com",
+		Title:   "Updated Title",
+		Version: created.Version,
 	}
 
 	_, err = repo.UpdateBookmark(updatedBookmark)
@@ -377,10 +381,11 @@ func TestBookmarkInMemRepository_UpdateBookmark(t *testing.T) {
 
 	// Update the bookmark
 	updatedBookmark := model.Bookmark{
-		ID:     created.ID,
-		UserID: "user1",
-		URL:    "https://updated.com",
-		Title:  "Updated Title",
+		ID:      created.ID,
+		UserID:  "user1",
+		URL:     "https://updated.com",
+		Title:   "Updated Title",
+		Version: created.Version,
 	}
 
 	result, err := repo.UpdateBookmark(updatedBookmark)
@@ -407,6 +412,17 @@ func TestBookmarkInMemRepository_UpdateBookmark(t *testing.T) {
 	if !result.CreatedAt.Equal(created.CreatedAt) {
 		t.Errorf("UpdateBookmark() should preserve CreatedAt = %v, got %v", created.CreatedAt, result.CreatedAt)
 	}
+	if result.Version != created.Version+1 {
+		t.Errorf("UpdateBookmark() result Version = %v, want %v", result.Version, created.Version+1)
+	}
+
+	// Test updating with the version that was just replaced
+	if _, err := repo.UpdateBookmark(updatedBookmark); !errors.Is(err, model.ErrConflict) {
+		t.Errorf("UpdateBookmark() of a stale version error = %v, want ErrConflict", err)
+	}
+	if stored, _ := repo.GetBookmark(created.ID); stored.Version != result.Version {
+		t.Errorf("UpdateBookmark() of a stale version changed the bookmark to version %v", stored.Version)
+	}
 
 	// Test updating non-existent bookmark
 	nonExistentBookmark := model.Bookmark{
@@ -653,10 +669,11 @@ func TestBookmarkInMemRepository_ConcurrentAccess(t *testing.T) {
 
 			for j := 0; j < 5 && j < len(ids); j++ {
 				updatedBookmark := model.Bookmark{
-					ID:     ids[j],
-					UserID: fmt.Sprintf("user_%d", goroutineID),
-					URL:    fmt.Sprintf("https://updated%d-%d.com", goroutineID, j),
-					Title:  fmt.Sprintf("Updated Title %d-%d", goroutineID, j),
+					ID:      ids[j],
+					UserID:  fmt.Sprintf("user_%d", goroutineID),
+					URL:     fmt.Sprintf("https://updated%d-%d.com", goroutineID, j),
+					Title:   fmt.Sprintf("Updated Title %d-%d", goroutineID, j),
+					Version: 1,
 				}
 				_, err := repo.UpdateBookmark(updatedBookmark)
 				if err != nil {
@@ -1066,3 +1083,314 @@ func TestBookmarkInMemRepository_ListBookmarks_WithArchivedFilter(t *testing.T)
 		}
 	}
 }
+
+func TestBookmarkInMemRepository_ListBookmarks_WithCollectionFilter(t *testing.T) {
+	repo := NewBookmarkInMemRepository()
+
+	repo.CreateBookmark(model.Bookmark{UserID: "user-1", URL: "https://a.example.com", CollectionIDs: []string{"c1"}})
+	repo.CreateBookmark(model.Bookmark{UserID: "user-1", URL: "https://b.example.com", CollectionIDs: []string{"c1", "c2"}})
+	repo.CreateBookmark(model.Bookmark{UserID: "user-1", URL: "https://c.example.com"})
+
+	query := model.BookmarkQuery{UserID: "user-1", CollectionID: "c1"}
+	bookmarks, err := repo.ListBookmarks(query)
+	if err != nil {
+		t.Fatalf("ListBookmarks() unexpected error = %v", err)
+	}
+	if len(bookmarks) != 2 {
+		t.Errorf("ListBookmarks() returned %d bookmarks, want 2", len(bookmarks))
+	}
+
+	count, _ := repo.CountBookmarks(model.BookmarkQuery{UserID: "user-1", CollectionID: "c2"})
+	if count != 1 {
+		t.Errorf("CountBookmarks() = %d, want 1", count)
+	}
+}
+
+func TestBookmarkInMemRepository_ListBookmarks_WithHealthFilter(t *testing.T) {
+	repo := NewBookmarkInMemRepository()
+
+	repo.CreateBookmark(model.Bookmark{UserID: "user-1", URL: "https://a.example.com", Health: model.LinkHealth{Status: model.LinkHealthBroken}})
+	repo.CreateBookmark(model.Bookmark{UserID: "user-1", URL: "https://b.example.com", Health: model.LinkHealth{Status: model.LinkHealthOK}})
+	repo.CreateBookmark(model.Bookmark{UserID: "user-1", URL: "https://c.example.com"})
+
+	bookmarks, err := repo.ListBookmarks(model.BookmarkQuery{UserID: "user-1", Health: model.LinkHealthBroken})
+	if err != nil {
+		t.Fatalf("ListBookmarks() unexpected error = %v", err)
+	}
+	if len(bookmarks) != 1 || bookmarks[0].URL != "https://a.example.com" {
+		t.Errorf("ListBookmarks() = %+v, want only the broken bookmark", bookmarks)
+	}
+}
+
+func TestBookmarkInMemRepository_ListBookmarks_FromPosition(t *testing.T) {
+	repo := NewBookmarkInMemRepository()
+	// Bookmarks created at the same time are still ordered, by ID
+	createdAt := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	for range 5 {
+		repo.CreateBookmark(model.Bookmark{UserID: "user-1", URL: "https://example.com", CreatedAt: createdAt})
+	}
+	all, err := repo.ListBookmarks(model.BookmarkQuery{UserID: "user-1"})
+	if err != nil {
+		t.Fatalf("ListBookmarks() unexpected error = %v", err)
+	}
+
+	var forward []model.Bookmark
+	position := &model.BookmarkPosition{CreatedAt: createdAt.Add(time.Hour)}
+	for range len(all) {
+		page, err := repo.ListBookmarks(model.BookmarkQuery{UserID: "user-1", PageSize: 2, Position: position})
+		if err != nil {
+			t.Fatalf("ListBookmarks() unexpected error = %v", err)
+		}
+		if len(page) == 0 {
+			break
+		}
+		forward = append(forward, page...)
+		last := page[len(page)-1]
+		position = &model.BookmarkPosition{CreatedAt: last.CreatedAt, ID: last.ID}
+	}
+	if len(forward) != len(all) {
+		t.Fatalf("paging forward returned %d bookmarks, want %d", len(forward), len(all))
+	}
+	for i := range all {
+		if forward[i].ID != all[i].ID {
+			t.Errorf("paging forward bookmark %d = %s, want %s", i, forward[i].ID, all[i].ID)
+		}
+	}
+
+	back, err := repo.ListBookmarks(model.BookmarkQuery{UserID: "user-1", PageSize: 2, Position: &model.BookmarkPosition{CreatedAt: createdAt, ID: all[3].ID, Backward: true}})
+	if err != nil {
+		t.Fatalf("ListBookmarks() unexpected error = %v", err)
+	}
+	if len(back) != 2 || back[0].ID != all[1].ID || back[1].ID != all[2].ID {
+		t.Errorf("ListBookmarks() backward = %+v, want the two bookmarks just before %s", back, all[3].ID)
+	}
+}
+
+func TestBookmarkInMemRepository_ListBookmarks_Sorted(t *testing.T) {
+	repo := NewBookmarkInMemRepository()
+	base := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	repo.CreateBookmark(model.Bookmark{UserID: "user-1", URL: "https://www.zeta.example.com/a", Title: "banana", CreatedAt: base.Add(1 * time.Hour), LastOpenedAt: base.Add(10 * time.Hour)})
+	repo.CreateBookmark(model.Bookmark{UserID: "user-1", URL: "https://alpha.example.com/b", Title: "Cherry", CreatedAt: base.Add(2 * time.Hour)})
+	repo.CreateBookmark(model.Bookmark{UserID: "user-1", URL: "https://mid.example.com/c", Title: "apple", CreatedAt: base.Add(3 * time.Hour), LastOpenedAt: base.Add(5 * time.Hour)})
+
+	tests := []struct {
+		sort, order string
+		want        []string
+	}{
+		{"", "", []string{"apple", "Cherry", "banana"}},
+		{model.BookmarkSortCreated, model.SortAscending, []string{"banana", "Cherry", "apple"}},
+		{model.BookmarkSortTitle, "", []string{"apple", "banana", "Cherry"}},
+		{model.BookmarkSortTitle, model.SortDescending, []string{"Cherry", "banana", "apple"}},
+		{model.BookmarkSortDomain, "", []string{"Cherry", "apple", "banana"}},
+		{model.BookmarkSortLastOpened, "", []string{"banana", "apple", "Cherry"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.sort+" "+tt.order, func(t *testing.T) {
+			bookmarks, err := repo.ListBookmarks(model.BookmarkQuery{UserID: "user-1", Sort: tt.sort, Order: tt.order})
+			if err != nil {
+				t.Fatalf("ListBookmarks() unexpected error = %v", err)
+			}
+			var titles []string
+			for _, b := range bookmarks {
+				titles = append(titles, b.Title)
+			}
+			if !slices.Equal(titles, tt.want) {
+				t.Errorf("ListBookmarks() titles = %v, want %v", titles, tt.want)
+			}
+
+			// Paging from the first bookmark gives the rest of the listing
+			sortBy := cmp.Or(tt.sort, model.BookmarkSortCreated)
+			position := model.PositionOf(bookmarks[0], sortBy, cmp.Or(tt.order, model.DefaultSortOrder(sortBy)), false)
+			rest, err := repo.ListBookmarks(model.BookmarkQuery{UserID: "user-1", Sort: tt.sort, Order: tt.order, PageSize: 10, Position: &position})
+			if err != nil {
+				t.Fatalf("ListBookmarks() unexpected error = %v", err)
+			}
+			if len(rest) != 2 || rest[0].ID != bookmarks[1].ID || rest[1].ID != bookmarks[2].ID {
+				t.Errorf("ListBookmarks() after the first bookmark = %+v, want the other two in order", rest)
+			}
+		})
+	}
+}
+
+func TestBookmarkInMemRepository_ListBookmarks_Filters(t *testing.T) {
+	repo := NewBookmarkInMemRepository()
+	base := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	repo.CreateBookmark(model.Bookmark{UserID: "user-1", URL: "https://www.example.com/a", Title: "a", ContentSummary: "Summary", Tags: []string{"go"}, CreatedAt: base, IsRead: true,
+		Enrichments: []model.Enrichment{{Trigger: model.EnrichmentTriggerCreate}}})
+	repo.CreateBookmark(model.Bookmark{UserID: "user-1", URL: "https://example.com/b", Title: "b", CreatedAt: base.Add(24 * time.Hour),
+		Enrichments: []model.Enrichment{{Trigger: model.EnrichmentTriggerCreate, Failed: []string{"summary"}}}})
+	repo.CreateBookmark(model.Bookmark{UserID: "user-1", URL: "https://other.example.org/c", Title: "c", Tags: []string{"go", "rust"}, CreatedAt: base.Add(48 * time.Hour), IsFavorite: true})
+
+	yes, no := true, false
+	tests := []struct {
+		name  string
+		query model.BookmarkQuery
+		want  []string
+	}{
+		{"domain", model.BookmarkQuery{Domain: "example.com"}, []string{"b", "a"}},
+		{"tag", model.BookmarkQuery{Tag: "go"}, []string{"c", "a"}},
+		{"has summary", model.BookmarkQuery{HasSummary: &yes}, []string{"a"}},
+		{"no summary", model.BookmarkQuery{HasSummary: &no}, []string{"c", "b"}},
+		{"enrichment failed", model.BookmarkQuery{EnrichmentStatus: model.EnrichmentStatusFailed}, []string{"b"}},
+		{"never enriched", model.BookmarkQuery{EnrichmentStatus: model.EnrichmentStatusNone}, []string{"c"}},
+		{"read", model.BookmarkQuery{Read: &yes}, []string{"a"}},
+		{"unread", model.BookmarkQuery{Read: &no}, []string{"c", "b"}},
+		{"favorite", model.BookmarkQuery{Favorite: &yes}, []string{"c"}},
+		{"created from", model.BookmarkQuery{CreatedFrom: base.Add(24 * time.Hour)}, []string{"c", "b"}},
+		{"created to", model.BookmarkQuery{CreatedTo: base.Add(24 * time.Hour)}, []string{"a"}},
+		{"combined", model.BookmarkQuery{Tag: "go", Domain: "example.com"}, []string{"a"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.query.UserID = "user-1"
+			bookmarks, err := repo.ListBookmarks(tt.query)
+			if err != nil {
+				t.Fatalf("ListBookmarks() unexpected error = %v", err)
+			}
+			var titles []string
+			for _, b := range bookmarks {
+				titles = append(titles, b.Title)
+			}
+			if !slices.Equal(titles, tt.want) {
+				t.Errorf("ListBookmarks() titles = %v, want %v", titles, tt.want)
+			}
+			if count, _ := repo.CountBookmarks(tt.query); count != len(tt.want) {
+				t.Errorf("CountBookmarks() = %d, want %d", count, len(tt.want))
+			}
+		})
+	}
+}
+
+func TestBookmarkInMemRepository_ListBookmarksCheckedBefore(t *testing.T) {
+	repo := NewBookmarkInMemRepository()
+	now := time.Now()
+
+	repo.CreateBookmark(model.Bookmark{UserID: "user-1", URL: "https://recent.example.com", Health: model.LinkHealth{CheckedAt: now}})
+	repo.CreateBookmark(model.Bookmark{UserID: "user-2", URL: "https://stale.example.com", Health: model.LinkHealth{CheckedAt: now.Add(-48 * time.Hour)}})
+	repo.CreateBookmark(model.Bookmark{UserID: "user-1", URL: "https://new.example.com"})
+
+	bookmarks, err := repo.ListBookmarksCheckedBefore(now.Add(-24*time.Hour), 10)
+	if err != nil {
+		t.Fatalf("ListBookmarksCheckedBefore() unexpected error = %v", err)
+	}
+	if len(bookmarks) != 2 || bookmarks[0].URL != "https://new.example.com" || bookmarks[1].URL != "https://stale.example.com" {
+		t.Errorf("ListBookmarksCheckedBefore() = %+v, want the unchecked then the stale bookmark", bookmarks)
+	}
+
+	bookmarks, _ = repo.ListBookmarksCheckedBefore(now.Add(time.Hour), 1)
+	if len(bookmarks) != 1 {
+		t.Errorf("ListBookmarksCheckedBefore() with limit 1 returned %d bookmarks", len(bookmarks))
+	}
+}
+
+func TestBookmarkInMemRepository_GetAndWriteBookmarks(t *testing.T) {
+	repo := NewBookmarkInMemRepository()
+	first, _ := repo.CreateBookmark(model.Bookmark{UserID: "user-1", URL: "https://first.example.com"})
+	second, _ := repo.CreateBookmark(model.Bookmark{UserID: "user-1", URL: "https://second.example.com"})
+
+	bookmarks, err := repo.GetBookmarks([]string{second.ID, "missing", first.ID})
+	if err != nil {
+		t.Fatalf("GetBookmarks() unexpected error = %v", err)
+	}
+	if len(bookmarks) != 2 || bookmarks[0].ID != second.ID || bookmarks[1].ID != first.ID {
+		t.Errorf("GetBookmarks() = %+v, want the two existing bookmarks in request order", bookmarks)
+	}
+
+	archived := first
+	archived.IsArchived = true
+	archived.CreatedAt = time.Time{}
+	errs := repo.WriteBookmarks([]model.BookmarkWrite{
+		{Bookmark: archived},
+		{Bookmark: second, Trash: true},
+		{Bookmark: model.Bookmark{ID: "missing"}},
+	})
+	if errs[0] != nil || errs[1] != nil || !errors.Is(errs[2], model.ErrNotFound) {
+		t.Errorf("WriteBookmarks() errors = %v, want only the missing bookmark to fail with ErrNotFound", errs)
+	}
+
+	stored, _ := repo.GetBookmark(first.ID)
+	if !stored.IsArchived || !stored.CreatedAt.Equal(first.CreatedAt) {
+		t.Errorf("WriteBookmarks() stored %+v, want archived with the original creation time", stored)
+	}
+	if _, err := repo.GetBookmark(second.ID); !errors.Is(err, model.ErrNotFound) {
+		t.Errorf("GetBookmark() of deleted bookmark error = %v, want ErrNotFound", err)
+	}
+	if stored.Version != first.Version+1 {
+		t.Errorf("WriteBookmarks() stored version %d, want %d", stored.Version, first.Version+1)
+	}
+
+	// The version the first write was read at is stale now
+	errs = repo.WriteBookmarks([]model.BookmarkWrite{{Bookmark: archived, Trash: true}})
+	if !errors.Is(errs[0], model.ErrConflict) {
+		t.Errorf("WriteBookmarks() of a stale version error = %v, want ErrConflict", errs[0])
+	}
+	if _, err := repo.GetBookmark(first.ID); err != nil {
+		t.Errorf("WriteBookmarks() of a stale version trashed the bookmark: %v", err)
+	}
+}
+
+func TestBookmarkInMemRepository_Trash(t *testing.T) {
+	repo := NewBookmarkInMemRepository()
+	first, _ := repo.CreateBookmark(model.Bookmark{UserID: "user-1", URL: "https://first.example.com"})
+	second, _ := repo.CreateBookmark(model.Bookmark{UserID: "user-1", URL: "https://second.example.com"})
+	now := time.Now()
+
+	if _, err := repo.TrashBookmark(first.ID, now, first.Version-1); !errors.Is(err, model.ErrConflict) {
+		t.Errorf("TrashBookmark() of a stale version error = %v, want ErrConflict", err)
+	}
+	trashed, err := repo.TrashBookmark(first.ID, now.Add(-time.Hour), first.Version)
+	if err != nil {
+		t.Fatalf("TrashBookmark() unexpected error = %v", err)
+	}
+	if !trashed.DeletedAt.Equal(now.Add(-time.Hour)) {
+		t.Errorf("TrashBookmark() DeletedAt = %v, want %v", trashed.DeletedAt, now.Add(-time.Hour))
+	}
+	if trashed.Version != first.Version+1 || !trashed.UpdatedAt.After(first.UpdatedAt) {
+		t.Errorf("TrashBookmark() = version %d updated at %v, want the next version", trashed.Version, trashed.UpdatedAt)
+	}
+	repo.TrashBookmark(second.ID, now, second.Version)
+
+	// Trashed bookmarks are left out of everything that reads live bookmarks
+	if _, err := repo.GetBookmark(first.ID); !errors.Is(err, model.ErrNotFound) {
+		t.Errorf("GetBookmark() of trashed bookmark error = %v, want ErrNotFound", err)
+	}
+	if count, _ := repo.CountBookmarks(model.BookmarkQuery{UserID: "user-1"}); count != 0 {
+		t.Errorf("CountBookmarks() = %d, want 0 with every bookmark in the trash", count)
+	}
+	if bookmarks, _ := repo.ListBookmarks(model.BookmarkQuery{UserID: "user-1"}); len(bookmarks) != 0 {
+		t.Errorf("ListBookmarks() = %+v, want none with every bookmark in the trash", bookmarks)
+	}
+
+	list, _ := repo.ListTrashedBookmarks("user-1")
+	if len(list) != 2 || list[0].ID != second.ID || list[1].ID != first.ID {
+		t.Errorf("ListTrashedBookmarks() = %+v, want the most recently deleted first", list)
+	}
+	expired, _ := repo.ListTrashedBefore(now.Add(-time.Minute), 10)
+	if len(expired) != 1 || expired[0].ID != first.ID {
+		t.Errorf("ListTrashedBefore() = %+v, want only the first bookmark", expired)
+	}
+
+	restored, err := repo.RestoreBookmark(first.ID)
+	if err != nil {
+		t.Fatalf("RestoreBookmark() unexpected error = %v", err)
+	}
+	if !restored.DeletedAt.IsZero() || !restored.CreatedAt.Equal(first.CreatedAt) {
+		t.Errorf("RestoreBookmark() = %+v, want it back unchanged", restored)
+	}
+	if restored.Version != trashed.Version+1 || restored.UpdatedAt.Before(trashed.UpdatedAt) {
+		t.Errorf("RestoreBookmark() = version %d updated at %v, want the next version", restored.Version, restored.UpdatedAt)
+	}
+	if _, err := repo.GetBookmark(first.ID); err != nil {
+		t.Errorf("GetBookmark() of restored bookmark error = %v", err)
+	}
+
+	if err := repo.PurgeBookmark(second.ID); err != nil {
+		t.Fatalf("PurgeBookmark() unexpected error = %v", err)
+	}
+	if _, err := repo.GetTrashedBookmark(second.ID); !errors.Is(err, model.ErrNotFound) {
+		t.Errorf("GetTrashedBookmark() of purged bookmark error = %v, want ErrNotFound", err)
+	}
+	if err := repo.PurgeBookmark(first.ID); !errors.Is(err, model.ErrNotFound) {
+		t.Errorf("PurgeBookmark() of live bookmark error = %v, want ErrNotFound", err)
+	}
+}
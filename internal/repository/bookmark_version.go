@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/tsongpon/athena/internal/model"
+)
+
+// checkBookmarkVersion returns ErrConflict when a bookmark about to be written was read at
+// another version than the stored one, that is when it was changed in between
+func checkBookmarkVersion(bookmark model.Bookmark, stored int64) error {
+	if bookmark.Version != stored {
+		return fmt.Errorf("bookmark with ID %s was changed since it was read, version %d is now %d: %w",
+			bookmark.ID, bookmark.Version, stored, model.ErrConflict)
+	}
+	return nil
+}
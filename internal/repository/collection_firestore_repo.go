@@ -0,0 +1,259 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/google/uuid"
+	"github.com/tsongpon/athena/internal/logger"
+	"github.com/tsongpon/athena/internal/model"
+	"go.uber.org/zap"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const collectionsCollection = "collections"
+
+// CollectionFirestoreRepository implements CollectionRepository interface using GCP Firestore
+type CollectionFirestoreRepository struct {
+	client *firestore.Client
+	ctx    context.Context
+}
+
+// NewCollectionFirestoreRepository creates a new instance of CollectionFirestoreRepository
+func NewCollectionFirestoreRepository(ctx context.Context, client *firestore.Client) *CollectionFirestoreRepository {
+	return &CollectionFirestoreRepository{
+		client: client,
+		ctx:    ctx,
+	}
+}
+
+// firestoreCollection is the structure used to store/retrieve collections in Firestore
+type firestoreCollection struct {
+	ID            string                  `firestore:"id"`
+	UserID        string                  `firestore:"user_id"`
+	ParentID      string                  `firestore:"parent_id"`
+	Name          string                  `firestore:"name"`
+	Description   string                  `firestore:"description"`
+	Position      int                     `firestore:"position"`
+	BookmarkIDs   []string                `firestore:"bookmark_ids"`
+	AddedBy       map[string]string       `firestore:"added_by"`
+	Collaborators []firestoreCollaborator `firestore:"collaborators"`
+	// CollaboratorIDs duplicates Collaborators[].UserID so shared collections can be queried with array-contains
+	CollaboratorIDs []string  `firestore:"collaborator_ids"`
+	CreatedAt       time.Time `firestore:"created_at"`
+	UpdatedAt       time.Time `firestore:"updated_at"`
+	Version         int64     `firestore:"version"` // Zero for documents written before versioning
+}
+
+type firestoreCollaborator struct {
+	UserID  string    `firestore:"user_id"`
+	Role    string    `firestore:"role"`
+	AddedAt time.Time `firestore:"added_at"`
+}
+
+// toFirestoreCollection converts model.Collection to firestoreCollection
+func toFirestoreCollection(collection model.Collection) firestoreCollection {
+	collaborators := make([]firestoreCollaborator, len(collection.Collaborators))
+	collaboratorIDs := make([]string, len(collection.Collaborators))
+	for i, c := range collection.Collaborators {
+		collaborators[i] = firestoreCollaborator{UserID: c.UserID, Role: c.Role, AddedAt: c.AddedAt}
+		collaboratorIDs[i] = c.UserID
+	}
+
+	return firestoreCollection{
+		ID:              collection.ID,
+		UserID:          collection.UserID,
+		ParentID:        collection.ParentID,
+		Name:            collection.Name,
+		Description:     collection.Description,
+		Position:        collection.Position,
+		BookmarkIDs:     collection.BookmarkIDs,
+		AddedBy:         collection.AddedBy,
+		Collaborators:   collaborators,
+		CollaboratorIDs: collaboratorIDs,
+		CreatedAt:       collection.CreatedAt,
+		UpdatedAt:       collection.UpdatedAt,
+		Version:         collection.Version,
+	}
+}
+
+// toModelCollection converts firestoreCollection to model.Collection
+func toModelCollection(fsCollection firestoreCollection) model.Collection {
+	var collaborators []model.Collaborator
+	for _, c := range fsCollection.Collaborators {
+		collaborators = append(collaborators, model.Collaborator{UserID: c.UserID, Role: c.Role, AddedAt: c.AddedAt})
+	}
+
+	return model.Collection{
+		ID:            fsCollection.ID,
+		UserID:        fsCollection.UserID,
+		ParentID:      fsCollection.ParentID,
+		Name:          fsCollection.Name,
+		Description:   fsCollection.Description,
+		Position:      fsCollection.Position,
+		BookmarkIDs:   fsCollection.BookmarkIDs,
+		AddedBy:       fsCollection.AddedBy,
+		Collaborators: collaborators,
+		CreatedAt:     fsCollection.CreatedAt,
+		UpdatedAt:     fsCollection.UpdatedAt,
+		Version:       fsCollection.Version,
+	}
+}
+
+// CreateCollection creates a new collection in Firestore
+func (r *CollectionFirestoreRepository) CreateCollection(collection model.Collection) (model.Collection, error) {
+	if collection.ID == "" {
+		collection.ID = uuid.New().String()
+	}
+	now := time.Now()
+	if collection.CreatedAt.IsZero() {
+		collection.CreatedAt = now
+	}
+	collection.UpdatedAt = now
+	collection.Version = 1
+
+	_, err := r.client.Collection(collectionsCollection).Doc(collection.ID).Set(r.ctx, toFirestoreCollection(collection))
+	if err != nil {
+		logger.Error("Failed to create collection in Firestore",
+			zap.String("collection_id", collection.ID),
+			zap.String("user_id", collection.UserID),
+			zap.Error(err))
+		return model.Collection{}, fmt.Errorf("failed to create collection: %w", err)
+	}
+
+	logger.Debug("Created collection in Firestore", zap.String("id", collection.ID))
+	return collection, nil
+}
+
+// GetCollection retrieves a collection by its ID from Firestore
+func (r *CollectionFirestoreRepository) GetCollection(id string) (model.Collection, error) {
+	docSnap, err := r.client.Collection(collectionsCollection).Doc(id).Get(r.ctx)
+	if status.Code(err) == codes.NotFound {
+		return model.Collection{}, fmt.Errorf("collection with ID %s %w", id, model.ErrNotFound)
+	}
+	if err != nil {
+		logger.Error("Failed to get collection from Firestore",
+			zap.String("id", id),
+			zap.Error(err))
+		return model.Collection{}, fmt.Errorf("failed to get collection: %w", err)
+	}
+
+	var fsCollection firestoreCollection
+	if err := docSnap.DataTo(&fsCollection); err != nil {
+		logger.Error("Failed to parse collection data from Firestore",
+			zap.String("id", id),
+			zap.Error(err))
+		return model.Collection{}, fmt.Errorf("failed to parse collection data: %w", err)
+	}
+
+	return toModelCollection(fsCollection), nil
+}
+
+// ListCollections retrieves all collections of a user from Firestore ordered by position, then name
+func (r *CollectionFirestoreRepository) ListCollections(userID string) ([]model.Collection, error) {
+	query := r.client.Collection(collectionsCollection).
+		Where("user_id", "==", userID).
+		OrderBy("position", firestore.Asc).
+		OrderBy("name", firestore.Asc)
+	return r.listCollections(query, userID)
+}
+
+// ListSharedCollections retrieves collections where userID is a collaborator, ordered by position, then name
+func (r *CollectionFirestoreRepository) ListSharedCollections(userID string) ([]model.Collection, error) {
+	query := r.client.Collection(collectionsCollection).
+		Where("collaborator_ids", "array-contains", userID).
+		OrderBy("position", firestore.Asc).
+		OrderBy("name", firestore.Asc)
+	return r.listCollections(query, userID)
+}
+
+func (r *CollectionFirestoreRepository) listCollections(query firestore.Query, userID string) ([]model.Collection, error) {
+	iter := query.Documents(r.ctx)
+	defer iter.Stop()
+
+	var collections []model.Collection
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			logger.Error("Failed to list collections from Firestore",
+				zap.String("user_id", userID),
+				zap.Error(err))
+			return nil, fmt.Errorf("failed to list collections: %w", err)
+		}
+
+		var fsCollection firestoreCollection
+		if err := doc.DataTo(&fsCollection); err != nil {
+			logger.Error("Failed to parse collection data from Firestore", zap.Error(err))
+			return nil, fmt.Errorf("failed to parse collection data: %w", err)
+		}
+
+		collections = append(collections, toModelCollection(fsCollection))
+	}
+
+	return collections, nil
+}
+
+// UpdateCollection updates an existing collection in Firestore in a transaction, if it is still
+// at the version it was read at
+func (r *CollectionFirestoreRepository) UpdateCollection(collection model.Collection) (model.Collection, error) {
+	ref := r.client.Collection(collectionsCollection).Doc(collection.ID)
+	err := r.client.RunTransaction(r.ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		docSnap, err := tx.Get(ref)
+		if err != nil {
+			return err
+		}
+		var existing firestoreCollection
+		if err := docSnap.DataTo(&existing); err != nil {
+			return err
+		}
+		if err := checkCollectionVersion(collection, existing.Version); err != nil {
+			return err
+		}
+
+		collection.CreatedAt = existing.CreatedAt
+		collection.UpdatedAt = time.Now()
+		collection.Version = existing.Version + 1
+		return tx.Set(ref, toFirestoreCollection(collection))
+	})
+	if status.Code(err) == codes.NotFound {
+		return model.Collection{}, fmt.Errorf("collection with ID %s %w", collection.ID, model.ErrNotFound)
+	}
+	if errors.Is(err, model.ErrConflict) {
+		return model.Collection{}, err
+	}
+	if err != nil {
+		logger.Error("Failed to update collection in Firestore",
+			zap.String("collection_id", collection.ID),
+			zap.Error(err))
+		return model.Collection{}, fmt.Errorf("failed to update collection: %w", err)
+	}
+
+	logger.Debug("Updated collection in Firestore", zap.String("id", collection.ID))
+	return collection, nil
+}
+
+// DeleteCollection removes a collection from Firestore
+func (r *CollectionFirestoreRepository) DeleteCollection(id string) error {
+	if _, err := r.GetCollection(id); err != nil {
+		return err
+	}
+
+	_, err := r.client.Collection(collectionsCollection).Doc(id).Delete(r.ctx)
+	if err != nil {
+		logger.Error("Failed to delete collection from Firestore",
+			zap.String("id", id),
+			zap.Error(err))
+		return fmt.Errorf("failed to delete collection: %w", err)
+	}
+
+	logger.Debug("Deleted collection from Firestore", zap.String("id", id))
+	return nil
+}
@@ -0,0 +1,147 @@
+package repository
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tsongpon/athena/internal/model"
+)
+
+// CollectionInMemRepository implements CollectionRepository interface using an in-memory map
+type CollectionInMemRepository struct {
+	collections map[string]model.Collection
+	mutex       sync.RWMutex
+}
+
+// NewCollectionInMemRepository creates a new instance of CollectionInMemRepository
+func NewCollectionInMemRepository() *CollectionInMemRepository {
+	return &CollectionInMemRepository{
+		collections: make(map[string]model.Collection),
+		mutex:       sync.RWMutex{},
+	}
+}
+
+// CreateCollection creates a new collection in the repository
+func (r *CollectionInMemRepository) CreateCollection(collection model.Collection) (model.Collection, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	collection.ID = uuid.New().String()
+	now := time.Now()
+	if collection.CreatedAt.IsZero() {
+		collection.CreatedAt = now
+	}
+	collection.UpdatedAt = now
+	collection.Version = 1
+	collection = cloneCollection(collection)
+
+	r.collections[collection.ID] = collection
+
+	return cloneCollection(collection), nil
+}
+
+// GetCollection retrieves a collection by its ID
+func (r *CollectionInMemRepository) GetCollection(id string) (model.Collection, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	collection, exists := r.collections[id]
+	if !exists {
+		return model.Collection{}, fmt.Errorf("collection with ID %s %w", id, model.ErrNotFound)
+	}
+
+	return cloneCollection(collection), nil
+}
+
+// ListCollections retrieves all collections of a user ordered by position, then name
+func (r *CollectionInMemRepository) ListCollections(userID string) ([]model.Collection, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	var collections []model.Collection
+	for _, collection := range r.collections {
+		if collection.UserID == userID {
+			collections = append(collections, cloneCollection(collection))
+		}
+	}
+
+	sortCollections(collections)
+	return collections, nil
+}
+
+// ListSharedCollections retrieves collections where userID is a collaborator, ordered by position, then name
+func (r *CollectionInMemRepository) ListSharedCollections(userID string) ([]model.Collection, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	var collections []model.Collection
+	for _, collection := range r.collections {
+		if collection.UserID != userID && collection.RoleOf(userID) != "" {
+			collections = append(collections, cloneCollection(collection))
+		}
+	}
+
+	sortCollections(collections)
+	return collections, nil
+}
+
+// UpdateCollection updates an existing collection in the repository, if it is still at the
+// version it was read at
+func (r *CollectionInMemRepository) UpdateCollection(collection model.Collection) (model.Collection, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	existing, exists := r.collections[collection.ID]
+	if !exists {
+		return model.Collection{}, fmt.Errorf("collection with ID %s %w", collection.ID, model.ErrNotFound)
+	}
+	if err := checkCollectionVersion(collection, existing.Version); err != nil {
+		return model.Collection{}, err
+	}
+
+	collection.CreatedAt = existing.CreatedAt
+	collection.UpdatedAt = time.Now()
+	collection.Version = existing.Version + 1
+	collection = cloneCollection(collection)
+
+	r.collections[collection.ID] = collection
+
+	return cloneCollection(collection), nil
+}
+
+// DeleteCollection removes a collection from the repository
+func (r *CollectionInMemRepository) DeleteCollection(id string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, exists := r.collections[id]; !exists {
+		return fmt.Errorf("collection with ID %s %w", id, model.ErrNotFound)
+	}
+
+	delete(r.collections, id)
+
+	return nil
+}
+
+// cloneCollection copies the slices and maps of a collection so that callers
+// cannot mutate stored state
+func cloneCollection(collection model.Collection) model.Collection {
+	collection.BookmarkIDs = slices.Clone(collection.BookmarkIDs)
+	collection.AddedBy = maps.Clone(collection.AddedBy)
+	collection.Collaborators = slices.Clone(collection.Collaborators)
+	return collection
+}
+
+func sortCollections(collections []model.Collection) {
+	sort.Slice(collections, func(i, j int) bool {
+		if collections[i].Position != collections[j].Position {
+			return collections[i].Position < collections[j].Position
+		}
+		return collections[i].Name < collections[j].Name
+	})
+}
@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/tsongpon/athena/internal/model"
+)
+
+func TestCollectionInMemRepository_CreateAndGet(t *testing.T) {
+	repo := NewCollectionInMemRepository()
+
+	created, err := repo.CreateCollection(model.Collection{
+		UserID:      "user-1",
+		Name:        "Reading",
+		BookmarkIDs: []string{"b1"},
+	})
+	if err != nil {
+		t.Fatalf("CreateCollection() unexpected error = %v", err)
+	}
+	if created.ID == "" {
+		t.Error("CreateCollection() should generate an ID")
+	}
+	if created.CreatedAt.IsZero() || created.UpdatedAt.IsZero() {
+		t.Error("CreateCollection() should set timestamps")
+	}
+
+	got, err := repo.GetCollection(created.ID)
+	if err != nil {
+		t.Fatalf("GetCollection() unexpected error = %v", err)
+	}
+	if got.Name != "Reading" || len(got.BookmarkIDs) != 1 {
+		t.Errorf("GetCollection() = %+v, want the created collection", got)
+	}
+
+	// Mutating a returned collection must not change the stored one
+	got.BookmarkIDs[0] = "changed"
+	again, _ := repo.GetCollection(created.ID)
+	if again.BookmarkIDs[0] != "b1" {
+		t.Errorf("stored BookmarkIDs = %v, want [b1]", again.BookmarkIDs)
+	}
+
+	if _, err := repo.GetCollection("missing"); !errors.Is(err, model.ErrNotFound) {
+		t.Errorf("GetCollection() error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestCollectionInMemRepository_ListCollections(t *testing.T) {
+	repo := NewCollectionInMemRepository()
+
+	repo.CreateCollection(model.Collection{UserID: "user-1", Name: "Work", Position: 1})
+	repo.CreateCollection(model.Collection{UserID: "user-1", Name: "Recipes", Position: 0})
+	repo.CreateCollection(model.Collection{UserID: "user-1", Name: "Articles", Position: 1})
+	repo.CreateCollection(model.Collection{UserID: "user-2", Name: "Other"})
+
+	collections, err := repo.ListCollections("user-1")
+	if err != nil {
+		t.Fatalf("ListCollections() unexpected error = %v", err)
+	}
+
+	want := []string{"Recipes", "Articles", "Work"}
+	if len(collections) != len(want) {
+		t.Fatalf("ListCollections() returned %d collections, want %d", len(collections), len(want))
+	}
+	for i, name := range want {
+		if collections[i].Name != name {
+			t.Errorf("ListCollections()[%d] = %s, want %s", i, collections[i].Name, name)
+		}
+	}
+}
+
+func TestCollectionInMemRepository_UpdateAndDelete(t *testing.T) {
+	repo := NewCollectionInMemRepository()
+
+	created, _ := repo.CreateCollection(model.Collection{UserID: "user-1", Name: "Reading"})
+	created.Name = "Later"
+	created.BookmarkIDs = []string{"b2", "b1"}
+
+	updated, err := repo.UpdateCollection(created)
+	if err != nil {
+		t.Fatalf("UpdateCollection() unexpected error = %v", err)
+	}
+	if updated.Name != "Later" || !updated.CreatedAt.Equal(created.CreatedAt) {
+		t.Errorf("UpdateCollection() = %+v, want renamed collection with original CreatedAt", updated)
+	}
+	if created.Version != 1 || updated.Version != 2 {
+		t.Errorf("UpdateCollection() versions = %d then %d, want 1 then 2", created.Version, updated.Version)
+	}
+
+	// The version the first update was read at is stale now
+	created.Name = "Stale"
+	if _, err := repo.UpdateCollection(created); !errors.Is(err, model.ErrConflict) {
+		t.Errorf("UpdateCollection() of a stale version error = %v, want ErrConflict", err)
+	}
+	if stored, _ := repo.GetCollection(created.ID); stored.Name != "Later" {
+		t.Errorf("UpdateCollection() of a stale version stored %q, want the newer name kept", stored.Name)
+	}
+
+	if err := repo.DeleteCollection(created.ID); err != nil {
+		t.Fatalf("DeleteCollection() unexpected error = %v", err)
+	}
+	if err := repo.DeleteCollection(created.ID); !errors.Is(err, model.ErrNotFound) {
+		t.Errorf("DeleteCollection() twice error = %v, want ErrNotFound", err)
+	}
+	if _, err := repo.UpdateCollection(created); !errors.Is(err, model.ErrNotFound) {
+		t.Errorf("UpdateCollection() after delete error = %v, want ErrNotFound", err)
+	}
+}
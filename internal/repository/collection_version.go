@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"fmt"
+
+	"github.com/tsongpon/athena/internal/model"
+)
+
+// checkCollectionVersion returns ErrConflict when a collection about to be written was read at
+// another version than the stored one, that is when it was changed in between
+func checkCollectionVersion(collection model.Collection, stored int64) error {
+	if collection.Version != stored {
+		return fmt.Errorf("collection with ID %s was changed since it was read, version %d is now %d: %w",
+			collection.ID, collection.Version, stored, model.ErrConflict)
+	}
+	return nil
+}
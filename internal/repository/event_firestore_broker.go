@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/tsongpon/athena/internal/logger"
+	"github.com/tsongpon/athena/internal/model"
+	"go.uber.org/zap"
+)
+
+const eventsCollection = "events"
+
+// eventRetention is how long published events are kept. Firestore deletes them after
+// expire_at once a TTL policy is configured on the field.
+const eventRetention = 24 * time.Hour
+
+// EventFirestoreBroker implements EventBroker interface using GCP Firestore: events are
+// written to a collection that every instance listens to
+type EventFirestoreBroker struct {
+	client *firestore.Client
+	ctx    context.Context
+}
+
+// NewEventFirestoreBroker creates a new instance of EventFirestoreBroker
+func NewEventFirestoreBroker(ctx context.Context, client *firestore.Client) *EventFirestoreBroker {
+	return &EventFirestoreBroker{
+		client: client,
+		ctx:    ctx,
+	}
+}
+
+// firestoreEvent is the structure used to store/retrieve events in Firestore
+type firestoreEvent struct {
+	ID        string            `firestore:"id"`
+	UserID    string            `firestore:"user_id"`
+	Type      string            `firestore:"type"`
+	Bookmark  firestoreBookmark `firestore:"bookmark"`
+	CreatedAt time.Time         `firestore:"created_at"`
+	ExpireAt  time.Time         `firestore:"expire_at"`
+}
+
+// Publish stores an event for every instance to deliver
+func (b *EventFirestoreBroker) Publish(event model.Event) error {
+	fsEvent := firestoreEvent{
+		ID:        event.ID,
+		UserID:    event.UserID,
+		Type:      event.Type,
+		Bookmark:  toFirestoreBookmark(event.Bookmark),
+		CreatedAt: event.At,
+		ExpireAt:  event.At.Add(eventRetention),
+	}
+	if _, err := b.client.Collection(eventsCollection).Doc(event.ID).Set(b.ctx, fsEvent); err != nil {
+		logger.Error("Failed to publish event to Firestore",
+			zap.String("id", event.ID),
+			zap.String("type", event.Type),
+			zap.Error(err))
+		return fmt.Errorf("failed to publish event: %w", err)
+	}
+	return nil
+}
+
+// Subscribe delivers the events published from now on until ctx is done or listening fails
+func (b *EventFirestoreBroker) Subscribe(ctx context.Context, deliver func(model.Event)) error {
+	iter := b.client.Collection(eventsCollection).
+		Where("created_at", ">", time.Now()).
+		Snapshots(ctx)
+	defer iter.Stop()
+
+	for {
+		snap, err := iter.Next()
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return fmt.Errorf("failed to listen to events: %w", err)
+		}
+		for _, change := range snap.Changes {
+			if change.Kind != firestore.DocumentAdded {
+				continue
+			}
+			var fsEvent firestoreEvent
+			if err := change.Doc.DataTo(&fsEvent); err != nil {
+				logger.Warn("Failed to parse event data", zap.String("id", change.Doc.Ref.ID), zap.Error(err))
+				continue
+			}
+			deliver(model.Event{
+				ID:       fsEvent.ID,
+				UserID:   fsEvent.UserID,
+				Type:     fsEvent.Type,
+				Bookmark: toModelBookmark(fsEvent.Bookmark),
+				At:       fsEvent.CreatedAt,
+			})
+		}
+	}
+}
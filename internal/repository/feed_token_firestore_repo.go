@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/tsongpon/athena/internal/logger"
+	"github.com/tsongpon/athena/internal/model"
+	"go.uber.org/zap"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const feedTokensCollection = "feed_tokens"
+
+// FeedTokenFirestoreRepository implements FeedTokenRepository interface using GCP Firestore.
+// Documents are keyed by token. Errors and logs never include the token itself since it is a credential.
+type FeedTokenFirestoreRepository struct {
+	client *firestore.Client
+	ctx    context.Context
+}
+
+// NewFeedTokenFirestoreRepository creates a new instance of FeedTokenFirestoreRepository
+func NewFeedTokenFirestoreRepository(ctx context.Context, client *firestore.Client) *FeedTokenFirestoreRepository {
+	return &FeedTokenFirestoreRepository{
+		client: client,
+		ctx:    ctx,
+	}
+}
+
+// firestoreFeedToken is the structure used to store/retrieve feed tokens in Firestore
+type firestoreFeedToken struct {
+	Token        string    `firestore:"token"`
+	UserID       string    `firestore:"user_id"`
+	CollectionID string    `firestore:"collection_id"`
+	CreatedAt    time.Time `firestore:"created_at"`
+}
+
+func toFirestoreFeedToken(feedToken model.FeedToken) firestoreFeedToken {
+	return firestoreFeedToken{
+		Token:        feedToken.Token,
+		UserID:       feedToken.UserID,
+		CollectionID: feedToken.CollectionID,
+		CreatedAt:    feedToken.CreatedAt,
+	}
+}
+
+func toModelFeedToken(fsFeedToken firestoreFeedToken) model.FeedToken {
+	return model.FeedToken{
+		Token:        fsFeedToken.Token,
+		UserID:       fsFeedToken.UserID,
+		CollectionID: fsFeedToken.CollectionID,
+		CreatedAt:    fsFeedToken.CreatedAt,
+	}
+}
+
+// CreateFeedToken stores a new feed token in Firestore, failing if the token is taken
+func (r *FeedTokenFirestoreRepository) CreateFeedToken(feedToken model.FeedToken) (model.FeedToken, error) {
+	if feedToken.CreatedAt.IsZero() {
+		feedToken.CreatedAt = time.Now()
+	}
+
+	_, err := r.client.Collection(feedTokensCollection).Doc(feedToken.Token).Create(r.ctx, toFirestoreFeedToken(feedToken))
+	if status.Code(err) == codes.AlreadyExists {
+		return model.FeedToken{}, fmt.Errorf("feed token already exists: %w", model.ErrConflict)
+	}
+	if err != nil {
+		logger.Error("Failed to create feed token in Firestore",
+			zap.String("user_id", feedToken.UserID),
+			zap.Error(err))
+		return model.FeedToken{}, fmt.Errorf("failed to create feed token: %w", err)
+	}
+
+	return feedToken, nil
+}
+
+// GetFeedToken retrieves a feed token from Firestore
+func (r *FeedTokenFirestoreRepository) GetFeedToken(token string) (model.FeedToken, error) {
+	docSnap, err := r.client.Collection(feedTokensCollection).Doc(token).Get(r.ctx)
+	if status.Code(err) == codes.NotFound {
+		return model.FeedToken{}, fmt.Errorf("feed token %w", model.ErrNotFound)
+	}
+	if err != nil {
+		logger.Error("Failed to get feed token from Firestore", zap.Error(err))
+		return model.FeedToken{}, fmt.Errorf("failed to get feed token: %w", err)
+	}
+
+	var fsFeedToken firestoreFeedToken
+	if err := docSnap.DataTo(&fsFeedToken); err != nil {
+		return model.FeedToken{}, fmt.Errorf("failed to parse feed token data: %w", err)
+	}
+
+	return toModelFeedToken(fsFeedToken), nil
+}
+
+// ListFeedTokens retrieves the feed tokens of a user from Firestore, newest first
+func (r *FeedTokenFirestoreRepository) ListFeedTokens(userID string) ([]model.FeedToken, error) {
+	iter := r.client.Collection(feedTokensCollection).
+		Where("user_id", "==", userID).
+		OrderBy("created_at", firestore.Desc).
+		Documents(r.ctx)
+	defer iter.Stop()
+
+	tokens := []model.FeedToken{}
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			logger.Error("Failed to list feed tokens from Firestore",
+				zap.String("user_id", userID),
+				zap.Error(err))
+			return nil, fmt.Errorf("failed to list feed tokens: %w", err)
+		}
+
+		var fsFeedToken firestoreFeedToken
+		if err := doc.DataTo(&fsFeedToken); err != nil {
+			return nil, fmt.Errorf("failed to parse feed token data: %w", err)
+		}
+		tokens = append(tokens, toModelFeedToken(fsFeedToken))
+	}
+
+	return tokens, nil
+}
+
+// DeleteFeedToken deletes a feed token from Firestore
+func (r *FeedTokenFirestoreRepository) DeleteFeedToken(token string) error {
+	_, err := r.client.Collection(feedTokensCollection).Doc(token).Delete(r.ctx, firestore.Exists)
+	if status.Code(err) == codes.NotFound {
+		return fmt.Errorf("feed token %w", model.ErrNotFound)
+	}
+	if err != nil {
+		logger.Error("Failed to delete feed token from Firestore", zap.Error(err))
+		return fmt.Errorf("failed to delete feed token: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/tsongpon/athena/internal/model"
+)
+
+// FeedTokenInMemRepository implements FeedTokenRepository interface using an in-memory map keyed by token.
+// Errors never include the token itself since it is a credential.
+type FeedTokenInMemRepository struct {
+	tokens map[string]model.FeedToken
+	mutex  sync.RWMutex
+}
+
+// NewFeedTokenInMemRepository creates a new instance of FeedTokenInMemRepository
+func NewFeedTokenInMemRepository() *FeedTokenInMemRepository {
+	return &FeedTokenInMemRepository{
+		tokens: make(map[string]model.FeedToken),
+		mutex:  sync.RWMutex{},
+	}
+}
+
+// CreateFeedToken stores a new feed token
+func (r *FeedTokenInMemRepository) CreateFeedToken(feedToken model.FeedToken) (model.FeedToken, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, exists := r.tokens[feedToken.Token]; exists {
+		return model.FeedToken{}, fmt.Errorf("feed token already exists: %w", model.ErrConflict)
+	}
+	if feedToken.CreatedAt.IsZero() {
+		feedToken.CreatedAt = time.Now()
+	}
+
+	r.tokens[feedToken.Token] = feedToken
+
+	return feedToken, nil
+}
+
+// GetFeedToken retrieves a feed token
+func (r *FeedTokenInMemRepository) GetFeedToken(token string) (model.FeedToken, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	feedToken, exists := r.tokens[token]
+	if !exists {
+		return model.FeedToken{}, fmt.Errorf("feed token %w", model.ErrNotFound)
+	}
+
+	return feedToken, nil
+}
+
+// ListFeedTokens retrieves the feed tokens of a user, newest first
+func (r *FeedTokenInMemRepository) ListFeedTokens(userID string) ([]model.FeedToken, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	tokens := []model.FeedToken{}
+	for _, feedToken := range r.tokens {
+		if feedToken.UserID == userID {
+			tokens = append(tokens, feedToken)
+		}
+	}
+
+	sort.Slice(tokens, func(i, j int) bool {
+		return tokens[i].CreatedAt.After(tokens[j].CreatedAt)
+	})
+
+	return tokens, nil
+}
+
+// DeleteFeedToken deletes a feed token
+func (r *FeedTokenInMemRepository) DeleteFeedToken(token string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, exists := r.tokens[token]; !exists {
+		return fmt.Errorf("feed token %w", model.ErrNotFound)
+	}
+
+	delete(r.tokens, token)
+
+	return nil
+}
@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/tsongpon/athena/internal/model"
+)
+
+func TestFeedTokenInMemRepository(t *testing.T) {
+	repo := NewFeedTokenInMemRepository()
+
+	created, err := repo.CreateFeedToken(model.FeedToken{Token: "secret-token", UserID: "user-1"})
+	if err != nil {
+		t.Fatalf("CreateFeedToken() unexpected error = %v", err)
+	}
+	if created.CreatedAt.IsZero() {
+		t.Error("CreateFeedToken() should set CreatedAt")
+	}
+	if _, err := repo.CreateFeedToken(model.FeedToken{Token: "secret-token", UserID: "user-2"}); !errors.Is(err, model.ErrConflict) {
+		t.Errorf("CreateFeedToken() with taken token error = %v, want ErrConflict", err)
+	}
+
+	tokens, _ := repo.ListFeedTokens("user-1")
+	if len(tokens) != 1 {
+		t.Errorf("ListFeedTokens() returned %d tokens, want 1", len(tokens))
+	}
+
+	if err := repo.DeleteFeedToken("secret-token"); err != nil {
+		t.Fatalf("DeleteFeedToken() unexpected error = %v", err)
+	}
+	_, err = repo.GetFeedToken("secret-token")
+	if !errors.Is(err, model.ErrNotFound) {
+		t.Errorf("GetFeedToken() after delete error = %v, want ErrNotFound", err)
+	}
+	if strings.Contains(err.Error(), "secret-token") {
+		t.Errorf("GetFeedToken() error %q leaks the token", err)
+	}
+}
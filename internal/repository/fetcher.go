@@ -0,0 +1,344 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/tsongpon/athena/internal/logger"
+	"go.uber.org/zap"
+	"golang.org/x/time/rate"
+)
+
+// DefaultUserAgent identifies Athena to the sites it fetches
+const DefaultUserAgent = "AthenaBot/1.0 (+https://github.com/tsongpon/athena)"
+
+// ErrDisallowedByRobots is returned for requests that the site's robots.txt does not allow
+var ErrDisallowedByRobots = errors.New("disallowed by robots.txt")
+
+// FetcherConfig controls how politely a Fetcher treats each host. Zero values use the defaults.
+type FetcherConfig struct {
+	UserAgent            string        // Sent with every request, defaults to DefaultUserAgent
+	RequestsPerSecond    float64       // Token bucket refill rate per host, defaults to 1
+	Burst                int           // Token bucket size per host, defaults to 3
+	MaxConcurrentPerHost int           // Requests in flight per host, including reading the body, defaults to 2
+	RespectRobots        bool          // Check robots.txt before fetching
+	RobotsCacheTTL       time.Duration // How long a host's robots.txt is reused, defaults to one hour
+	MaxRetryAfter        time.Duration // Longest Retry-After that is waited out, defaults to 30 seconds
+	// AllowPrivateAddresses permits fetching loopback, private and link-local addresses, for
+	// local development. Otherwise they are refused, since every fetched URL comes from a user.
+	AllowPrivateAddresses bool
+}
+
+// Fetcher is an http.RoundTripper that makes outgoing fetches polite: it sets the User-Agent,
+// limits the request rate and concurrency per host, optionally obeys robots.txt, and backs off
+// when a host answers 429 or 503 with Retry-After. Redirects go through it hop by hop.
+type Fetcher struct {
+	transport http.RoundTripper
+	config    FetcherConfig
+
+	mutex     sync.Mutex
+	hosts     map[string]*hostState
+	robots    map[string]robotsEntry // Parsed robots.txt by scheme and host
+	lastSweep time.Time              // When idle hosts and expired robots.txt were last dropped
+}
+
+// hostIdleTimeout is how long a host goes without requests before its state is dropped
+const hostIdleTimeout = 10 * time.Minute
+
+// hostState tracks the politeness budget of one host
+type hostState struct {
+	limiter *rate.Limiter
+	slots   chan struct{} // One token per request allowed in flight
+	// blockedUntil is set from Retry-After; no request is sent to the host before it
+	blockedUntil time.Time
+	lastUsed     time.Time
+}
+
+// robotsFetchKey marks the context of robots.txt requests, which are never checked against
+// robots.txt themselves, including any redirects they follow
+type robotsFetchKey struct{}
+
+type robotsEntry struct {
+	rules     *robotsRules
+	expiresAt time.Time
+}
+
+// NewFetcher creates a Fetcher over a copy of http.DefaultTransport
+func NewFetcher(config FetcherConfig) *Fetcher {
+	if config.UserAgent == "" {
+		config.UserAgent = DefaultUserAgent
+	}
+	if config.RequestsPerSecond <= 0 {
+		config.RequestsPerSecond = 1
+	}
+	if config.Burst <= 0 {
+		config.Burst = 3
+	}
+	if config.MaxConcurrentPerHost <= 0 {
+		config.MaxConcurrentPerHost = 2
+	}
+	if config.RobotsCacheTTL <= 0 {
+		config.RobotsCacheTTL = time.Hour
+	}
+	if config.MaxRetryAfter <= 0 {
+		config.MaxRetryAfter = 30 * time.Second
+	}
+	return &Fetcher{
+		transport: newTransport(config.AllowPrivateAddresses),
+		config:    config,
+		hosts:     make(map[string]*hostState),
+		robots:    make(map[string]robotsEntry),
+	}
+}
+
+// newTransport returns a copy of http.DefaultTransport that, unless allowPrivate is set,
+// refuses to connect to addresses that are not publicly routable, such as the cloud metadata
+// server. Proxies are then not used either, as the check would only see the proxy's address.
+func newTransport(allowPrivate bool) *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if !allowPrivate {
+		dialer := &net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+			Control:   refusePrivateAddress,
+		}
+		transport.DialContext = dialer.DialContext
+		transport.Proxy = nil
+	}
+	return transport
+}
+
+// refusePrivateAddress rejects connections to addresses that are not publicly routable. It
+// runs after name resolution, so host names resolving to private addresses are refused too.
+func refusePrivateAddress(network, address string, conn syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return err
+	}
+	addr := addrPort.Addr().Unmap()
+	if !addr.IsGlobalUnicast() || addr.IsPrivate() {
+		return fmt.Errorf("address %s is not public", addr)
+	}
+	return nil
+}
+
+// RoundTrip implements http.RoundTripper
+func (f *Fetcher) RoundTrip(req *http.Request) (*http.Response, error) {
+	if f.config.RespectRobots && req.Context().Value(robotsFetchKey{}) == nil {
+		if !f.robotsRules(req).allowed(req.URL.RequestURI()) {
+			return nil, fmt.Errorf("%s: %w", req.URL.Redacted(), ErrDisallowedByRobots)
+		}
+	}
+	return f.send(req)
+}
+
+// send performs req within the host's limits, retrying idempotent requests once the host's
+// Retry-After has passed when that is soon enough
+func (f *Fetcher) send(req *http.Request) (*http.Response, error) {
+	host := f.host(req.URL.Host)
+	ctx := req.Context()
+
+	const maxAttempts = 3
+	for attempt := 1; ; attempt++ {
+		if err := f.waitUntilUnblocked(ctx, req.URL.Host, host); err != nil {
+			return nil, err
+		}
+		if err := host.limiter.Wait(ctx); err != nil {
+			return nil, fmt.Errorf("waiting to fetch from %s: %w", req.URL.Host, err)
+		}
+		select {
+		case host.slots <- struct{}{}:
+		case <-ctx.Done():
+			return nil, fmt.Errorf("waiting to fetch from %s: %w", req.URL.Host, ctx.Err())
+		}
+
+		out := req.Clone(ctx)
+		if out.Header.Get("User-Agent") == "" {
+			out.Header.Set("User-Agent", f.config.UserAgent)
+		}
+		resp, err := f.transport.RoundTrip(out)
+		if err != nil {
+			<-host.slots
+			return nil, err
+		}
+		resp.Body = &releaseOnClose{ReadCloser: resp.Body, release: func() { <-host.slots }}
+
+		if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusServiceUnavailable {
+			return resp, nil
+		}
+		wait, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+		if !ok {
+			return resp, nil
+		}
+		f.mutex.Lock()
+		if until := time.Now().Add(wait); until.After(host.blockedUntil) {
+			host.blockedUntil = until
+		}
+		f.mutex.Unlock()
+		logger.Debug("host asked to retry later",
+			zap.String("host", req.URL.Host),
+			zap.Int("status_code", resp.StatusCode),
+			zap.Duration("retry_after", wait))
+
+		retryable := (req.Method == http.MethodGet || req.Method == http.MethodHead) && req.Body == nil
+		if !retryable || attempt == maxAttempts || wait > f.config.MaxRetryAfter {
+			return resp, nil
+		}
+		io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+		resp.Body.Close()
+	}
+}
+
+// waitUntilUnblocked waits out a Retry-After the host sent earlier, failing fast when it is
+// longer than MaxRetryAfter
+func (f *Fetcher) waitUntilUnblocked(ctx context.Context, hostName string, host *hostState) error {
+	f.mutex.Lock()
+	wait := time.Until(host.blockedUntil)
+	f.mutex.Unlock()
+	if wait <= 0 {
+		return nil
+	}
+	if wait > f.config.MaxRetryAfter {
+		return fmt.Errorf("%s asked to retry after %s", hostName, wait.Round(time.Second))
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("waiting to fetch from %s: %w", hostName, ctx.Err())
+	}
+}
+
+// host returns the politeness state of a host, creating it on first use
+func (f *Fetcher) host(name string) *hostState {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	now := time.Now()
+	if now.Sub(f.lastSweep) >= hostIdleTimeout {
+		f.sweep(now)
+	}
+	host, ok := f.hosts[name]
+	if !ok {
+		host = &hostState{
+			limiter: rate.NewLimiter(rate.Limit(f.config.RequestsPerSecond), f.config.Burst),
+			slots:   make(chan struct{}, f.config.MaxConcurrentPerHost),
+		}
+		f.hosts[name] = host
+	}
+	host.lastUsed = now
+	return host
+}
+
+// sweep drops hosts that have been idle for hostIdleTimeout and robots.txt rules that have
+// expired, so the fetcher does not keep every host it ever saw. A host is only dropped once
+// dropping it changes nothing: no request is in flight, its rate budget has refilled and any
+// Retry-After has passed. The caller must hold f.mutex.
+func (f *Fetcher) sweep(now time.Time) {
+	for name, host := range f.hosts {
+		if now.Sub(host.lastUsed) >= hostIdleTimeout && len(host.slots) == 0 &&
+			now.After(host.blockedUntil) && host.limiter.TokensAt(now) >= float64(f.config.Burst) {
+			delete(f.hosts, name)
+		}
+	}
+	for key, entry := range f.robots {
+		if now.After(entry.expiresAt) {
+			delete(f.robots, key)
+		}
+	}
+	f.lastSweep = now
+}
+
+// robotsRules returns the cached robots.txt rules for the request's host, fetching them when
+// missing or expired. Per RFC 9309 a missing robots.txt allows everything and an unreachable
+// one disallows everything until it is fetched again.
+func (f *Fetcher) robotsRules(req *http.Request) *robotsRules {
+	key := req.URL.Scheme + "://" + req.URL.Host
+	f.mutex.Lock()
+	entry, ok := f.robots[key]
+	f.mutex.Unlock()
+	if ok && time.Now().Before(entry.expiresAt) {
+		return entry.rules
+	}
+
+	rules, ttl := f.fetchRobots(req.Context(), key)
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.robots[key] = robotsEntry{rules: rules, expiresAt: time.Now().Add(ttl)}
+	return rules
+}
+
+// fetchRobots downloads and parses robots.txt under origin and says how long to keep the result
+func (f *Fetcher) fetchRobots(ctx context.Context, origin string) (*robotsRules, time.Duration) {
+	const unreachableTTL = 5 * time.Minute
+	// The result is cached for every caller, so a cancelled request must not cut it short
+	ctx = context.WithValue(context.WithoutCancel(ctx), robotsFetchKey{}, true)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, origin+"/robots.txt", nil)
+	if err != nil {
+		return &robotsRules{}, f.config.RobotsCacheTTL
+	}
+	resp, err := (&http.Client{Transport: f, Timeout: 10 * time.Second}).Do(req)
+	if err != nil {
+		logger.Debug("failed to fetch robots.txt", zap.String("origin", origin), zap.Error(err))
+		return disallowAll(), unreachableTTL
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode >= 500:
+		return disallowAll(), unreachableTTL
+	case resp.StatusCode >= 400:
+		return &robotsRules{}, f.config.RobotsCacheTTL
+	case resp.StatusCode >= 300:
+		// Redirects were already followed, so this is a redirect loop or missing Location
+		return &robotsRules{}, f.config.RobotsCacheTTL
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 500<<10))
+	if err != nil {
+		return disallowAll(), unreachableTTL
+	}
+	return parseRobots(string(body), f.config.UserAgent), f.config.RobotsCacheTTL
+}
+
+// parseRetryAfter reads a Retry-After header given as seconds or as an HTTP date
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return max(at.Sub(now), 0), true
+	}
+	return 0, false
+}
+
+// releaseOnClose frees the host's concurrency slot once the caller is done with the body
+type releaseOnClose struct {
+	io.ReadCloser
+	once    sync.Once
+	release func()
+}
+
+func (r *releaseOnClose) Close() error {
+	err := r.ReadCloser.Close()
+	r.once.Do(r.release)
+	return err
+}
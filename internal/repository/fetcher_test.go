@@ -0,0 +1,253 @@
+package repository
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func fetch(t *testing.T, client *http.Client, url string) (*http.Response, error) {
+	t.Helper()
+	resp, err := client.Get(url)
+	if err == nil {
+		resp.Body.Close()
+	}
+	return resp, err
+}
+
+func TestFetcher_SetsUserAgent(t *testing.T) {
+	var got string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.UserAgent()
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: NewFetcher(FetcherConfig{AllowPrivateAddresses: true})}
+	if _, err := fetch(t, client, server.URL); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if got != DefaultUserAgent {
+		t.Errorf("User-Agent = %q, want %q", got, DefaultUserAgent)
+	}
+
+	client = &http.Client{Transport: NewFetcher(FetcherConfig{UserAgent: "custom/2.0", AllowPrivateAddresses: true})}
+	if _, err := fetch(t, client, server.URL); err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if got != "custom/2.0" {
+		t.Errorf("User-Agent = %q, want %q", got, "custom/2.0")
+	}
+}
+
+func TestFetcher_RateLimitsPerHost(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	client := &http.Client{Transport: NewFetcher(FetcherConfig{RequestsPerSecond: 20, Burst: 1, AllowPrivateAddresses: true})}
+	start := time.Now()
+	for range 3 {
+		if _, err := fetch(t, client, server.URL); err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+	}
+	// The first request uses the burst and the other two wait 50ms each
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Errorf("3 requests took %v, want at least 100ms at 20 requests per second", elapsed)
+	}
+}
+
+func TestFetcher_LimitsConcurrencyPerHost(t *testing.T) {
+	var inFlight, maxInFlight atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			current := maxInFlight.Load()
+			if n <= current || maxInFlight.CompareAndSwap(current, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: NewFetcher(FetcherConfig{RequestsPerSecond: 1000, Burst: 10, MaxConcurrentPerHost: 1, AllowPrivateAddresses: true})}
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Go(func() {
+			if _, err := fetch(t, client, server.URL); err != nil {
+				t.Errorf("Get() error = %v", err)
+			}
+		})
+	}
+	wg.Wait()
+
+	if got := maxInFlight.Load(); got != 1 {
+		t.Errorf("max requests in flight = %d, want 1", got)
+	}
+}
+
+func TestFetcher_RetriesAfterRetryAfter(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+		}
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: NewFetcher(FetcherConfig{RequestsPerSecond: 1000, AllowPrivateAddresses: true})}
+	resp, err := fetch(t, client, server.URL)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("status = %d, want %d after retrying", resp.StatusCode, http.StatusOK)
+	}
+	if got := calls.Load(); got != 2 {
+		t.Errorf("server called %d times, want 2", got)
+	}
+}
+
+func TestFetcher_LongRetryAfterBlocksHost(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Retry-After", "120")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: NewFetcher(FetcherConfig{RequestsPerSecond: 1000, AllowPrivateAddresses: true})}
+	resp, err := fetch(t, client, server.URL)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusServiceUnavailable)
+	}
+
+	if _, err := fetch(t, client, server.URL); err == nil {
+		t.Error("Get() while the host is blocked should fail")
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("server called %d times, want 1", got)
+	}
+}
+
+func TestFetcher_RespectsRobots(t *testing.T) {
+	var robotsCalls atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/robots.txt", func(w http.ResponseWriter, r *http.Request) {
+		robotsCalls.Add(1)
+		w.Write([]byte("User-agent: *\nDisallow: /private\n"))
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := &http.Client{Transport: NewFetcher(FetcherConfig{RequestsPerSecond: 1000, RespectRobots: true, AllowPrivateAddresses: true})}
+	if _, err := fetch(t, client, server.URL+"/private/page"); !errors.Is(err, ErrDisallowedByRobots) {
+		t.Errorf("Get() of a disallowed path error = %v, want ErrDisallowedByRobots", err)
+	}
+	if _, err := fetch(t, client, server.URL+"/public?from=/private"); err != nil {
+		t.Errorf("Get() of an allowed path error = %v", err)
+	}
+	if got := robotsCalls.Load(); got != 1 {
+		t.Errorf("robots.txt fetched %d times, want 1", got)
+	}
+}
+
+func TestFetcher_MissingRobotsAllowsAll(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/robots.txt" {
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: NewFetcher(FetcherConfig{RequestsPerSecond: 1000, RespectRobots: true, AllowPrivateAddresses: true})}
+	if _, err := fetch(t, client, server.URL+"/page"); err != nil {
+		t.Errorf("Get() error = %v, want nil without robots.txt", err)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value  string
+		want   time.Duration
+		wantOK bool
+	}{
+		{"", 0, false},
+		{"30", 30 * time.Second, true},
+		{"-1", 0, false},
+		{"soon", 0, false},
+		{"Wed, 01 Jan 2025 12:01:00 GMT", time.Minute, true},
+		{"Wed, 01 Jan 2025 11:00:00 GMT", 0, true},
+	}
+	for _, tt := range tests {
+		got, ok := parseRetryAfter(tt.value, now)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("parseRetryAfter(%q) = %v, %v, want %v, %v", tt.value, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestFetcher_RefusesPrivateAddresses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	// The test server listens on loopback, which is refused unless private addresses are allowed
+	client := &http.Client{Transport: NewFetcher(FetcherConfig{RequestsPerSecond: 1000})}
+	for _, url := range []string{server.URL, strings.Replace(server.URL, "127.0.0.1", "localhost", 1), "http://169.254.169.254/latest/meta-data/"} {
+		if _, err := fetch(t, client, url); err == nil || !strings.Contains(err.Error(), "is not public") {
+			t.Errorf("Get(%s) error = %v, want the address refused", url, err)
+		}
+	}
+
+	client = &http.Client{Transport: NewFetcher(FetcherConfig{RequestsPerSecond: 1000, AllowPrivateAddresses: true})}
+	if _, err := fetch(t, client, server.URL); err != nil {
+		t.Errorf("Get() with private addresses allowed error = %v", err)
+	}
+}
+
+func TestFetcher_SweepsIdleHosts(t *testing.T) {
+	f := NewFetcher(FetcherConfig{RequestsPerSecond: 1000})
+	now := time.Now()
+
+	idle := f.host("idle.example.com")
+	idle.lastUsed = now.Add(-hostIdleTimeout)
+	busy := f.host("busy.example.com")
+	busy.lastUsed = now.Add(-hostIdleTimeout)
+	busy.slots <- struct{}{} // A response body is still being read
+	f.host("recent.example.com")
+	f.robots["https://idle.example.com"] = robotsEntry{rules: &robotsRules{}, expiresAt: now.Add(-time.Second)}
+	f.robots["https://recent.example.com"] = robotsEntry{rules: &robotsRules{}, expiresAt: now.Add(time.Hour)}
+
+	f.mutex.Lock()
+	f.sweep(now)
+	f.mutex.Unlock()
+
+	if _, ok := f.hosts["idle.example.com"]; ok {
+		t.Error("sweep() kept an idle host")
+	}
+	if _, ok := f.hosts["busy.example.com"]; !ok {
+		t.Error("sweep() dropped a host with a request in flight")
+	}
+	if _, ok := f.hosts["recent.example.com"]; !ok {
+		t.Error("sweep() dropped a recently used host")
+	}
+	if _, ok := f.robots["https://idle.example.com"]; ok {
+		t.Error("sweep() kept expired robots.txt rules")
+	}
+	if _, ok := f.robots["https://recent.example.com"]; !ok {
+		t.Error("sweep() dropped robots.txt rules that have not expired")
+	}
+}
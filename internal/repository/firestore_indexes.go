@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"encoding/json"
+
+	"cloud.google.com/go/firestore"
+	"github.com/tsongpon/athena/internal/model"
+)
+
+//go:generate go run ../../cmd/firestore-indexes -o ../../firestore.indexes.json
+
+// firestoreIndexes is the layout of firestore.indexes.json, as deployed by the Firebase CLI
+type firestoreIndexes struct {
+	Indexes        []firestoreIndex `json:"indexes"`
+	FieldOverrides []any            `json:"fieldOverrides"`
+}
+
+type firestoreIndex struct {
+	CollectionGroup string                `json:"collectionGroup"`
+	QueryScope      string                `json:"queryScope"`
+	Fields          []firestoreIndexField `json:"fields"`
+}
+
+type firestoreIndexField struct {
+	FieldPath   string `json:"fieldPath"`
+	Order       string `json:"order,omitempty"`
+	ArrayConfig string `json:"arrayConfig,omitempty"`
+}
+
+// indexOrders are the index orders of Firestore query directions
+var indexOrders = map[firestore.Direction]string{
+	firestore.Asc:  "ASCENDING",
+	firestore.Desc: "DESCENDING",
+}
+
+func newIndex(collection string, fields ...firestoreIndexField) firestoreIndex {
+	return firestoreIndex{CollectionGroup: collection, QueryScope: "COLLECTION", Fields: fields}
+}
+
+func equalityField(field string) firestoreIndexField {
+	return firestoreIndexField{FieldPath: field, Order: indexOrders[firestore.Asc]}
+}
+
+func orderField(field string, direction firestore.Direction) firestoreIndexField {
+	return firestoreIndexField{FieldPath: field, Order: indexOrders[direction]}
+}
+
+// bookmarkIndexes are the composite indexes of the bookmark listing: one per sort order,
+// direction and optional filter. Firestore merges them when a query combines filters.
+func bookmarkIndexes() []firestoreIndex {
+	filters := []*firestoreIndexField{nil}
+	for _, filter := range bookmarkFilterFields {
+		field := equalityField(filter.field)
+		if filter.op == "array-contains" {
+			field = firestoreIndexField{FieldPath: filter.field, ArrayConfig: "CONTAINS"}
+		}
+		filters = append(filters, &field)
+	}
+
+	var indexes []firestoreIndex
+	for _, filter := range filters {
+		for _, sortBy := range model.BookmarkSorts {
+			for _, direction := range []firestore.Direction{firestore.Desc, firestore.Asc} {
+				fields := []firestoreIndexField{equalityField("user_id"), equalityField("is_archived")}
+				if filter != nil {
+					fields = append(fields, *filter)
+				}
+				for _, field := range bookmarkOrder(sortBy) {
+					fields = append(fields, orderField(field, direction))
+				}
+				indexes = append(indexes, newIndex(bookmarksCollection, fields...))
+			}
+		}
+	}
+	return indexes
+}
+
+// FirestoreIndexesJSON returns firestore.indexes.json, the composite indexes the queries of
+// the Firestore repositories need. Run go generate after changing a query.
+func FirestoreIndexesJSON() ([]byte, error) {
+	indexes := []firestoreIndex{
+		newIndex(activitiesCollection, equalityField("collection_id"), orderField("created_at", firestore.Desc)),
+		newIndex(bookmarkChangesCollection, equalityField("user_id"), orderField("seq", firestore.Asc)),
+		newIndex(collectionsCollection, equalityField("user_id"), orderField("position", firestore.Asc), orderField("name", firestore.Asc)),
+		newIndex(collectionsCollection, firestoreIndexField{FieldPath: "collaborator_ids", ArrayConfig: "CONTAINS"}, orderField("position", firestore.Asc), orderField("name", firestore.Asc)),
+		newIndex(feedTokensCollection, equalityField("user_id"), orderField("created_at", firestore.Desc)),
+		newIndex(highlightsCollection, equalityField("bookmark_id"), orderField("anchor_start", firestore.Asc), orderField("created_at", firestore.Asc)),
+		newIndex(invitationsCollection, equalityField("email"), orderField("created_at", firestore.Desc)),
+		newIndex(invitationsCollection, equalityField("collection_id"), orderField("created_at", firestore.Desc)),
+		newIndex(shareLinksCollection, equalityField("user_id"), orderField("created_at", firestore.Desc)),
+		newIndex(trashedBookmarksCollection, equalityField("user_id"), orderField("deleted_at", firestore.Desc)),
+		newIndex(webhooksCollection, equalityField("user_id"), orderField("created_at", firestore.Asc)),
+		newIndex(webhookDeliveriesCollection, equalityField("webhook_id"), orderField("created_at", firestore.Desc)),
+		newIndex(webhookDeliveriesCollection, equalityField("status"), orderField("next_attempt_at", firestore.Asc)),
+	}
+	indexes = append(indexes, bookmarkIndexes()...)
+
+	data, err := json.MarshalIndent(firestoreIndexes{Indexes: indexes, FieldOverrides: []any{}}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"bytes"
+	"os"
+	"testing"
+)
+
+func TestFirestoreIndexesJSON_UpToDate(t *testing.T) {
+	want, err := FirestoreIndexesJSON()
+	if err != nil {
+		t.Fatalf("FirestoreIndexesJSON() unexpected error = %v", err)
+	}
+	got, err := os.ReadFile("../../firestore.indexes.json")
+	if err != nil {
+		t.Fatalf("failed to read firestore.indexes.json: %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Error("firestore.indexes.json is out of date, run go generate ./internal/repository")
+	}
+}
@@ -0,0 +1,219 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/google/uuid"
+	"github.com/tsongpon/athena/internal/logger"
+	"github.com/tsongpon/athena/internal/model"
+	"go.uber.org/zap"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const highlightsCollection = "highlights"
+
+// HighlightFirestoreRepository implements HighlightRepository interface using GCP Firestore
+type HighlightFirestoreRepository struct {
+	client *firestore.Client
+	ctx    context.Context
+}
+
+// NewHighlightFirestoreRepository creates a new instance of HighlightFirestoreRepository
+func NewHighlightFirestoreRepository(ctx context.Context, client *firestore.Client) *HighlightFirestoreRepository {
+	return &HighlightFirestoreRepository{
+		client: client,
+		ctx:    ctx,
+	}
+}
+
+// firestoreHighlight is the structure used to store/retrieve highlights in Firestore
+type firestoreHighlight struct {
+	ID          string    `firestore:"id"`
+	BookmarkID  string    `firestore:"bookmark_id"`
+	UserID      string    `firestore:"user_id"`
+	Quote       string    `firestore:"quote"`
+	Note        string    `firestore:"note,omitempty"`
+	AnchorStart int       `firestore:"anchor_start"`
+	AnchorEnd   int       `firestore:"anchor_end"`
+	CreatedAt   time.Time `firestore:"created_at"`
+	UpdatedAt   time.Time `firestore:"updated_at"`
+}
+
+func toFirestoreHighlight(highlight model.Highlight) firestoreHighlight {
+	return firestoreHighlight{
+		ID:          highlight.ID,
+		BookmarkID:  highlight.BookmarkID,
+		UserID:      highlight.UserID,
+		Quote:       highlight.Quote,
+		Note:        highlight.Note,
+		AnchorStart: highlight.Anchor.Start,
+		AnchorEnd:   highlight.Anchor.End,
+		CreatedAt:   highlight.CreatedAt,
+		UpdatedAt:   highlight.UpdatedAt,
+	}
+}
+
+func toModelHighlight(fsHighlight firestoreHighlight) model.Highlight {
+	return model.Highlight{
+		ID:         fsHighlight.ID,
+		BookmarkID: fsHighlight.BookmarkID,
+		UserID:     fsHighlight.UserID,
+		Quote:      fsHighlight.Quote,
+		Note:       fsHighlight.Note,
+		Anchor:     model.HighlightAnchor{Start: fsHighlight.AnchorStart, End: fsHighlight.AnchorEnd},
+		CreatedAt:  fsHighlight.CreatedAt,
+		UpdatedAt:  fsHighlight.UpdatedAt,
+	}
+}
+
+// CreateHighlight stores a new highlight in Firestore with a generated ID
+func (r *HighlightFirestoreRepository) CreateHighlight(highlight model.Highlight) (model.Highlight, error) {
+	highlight.ID = uuid.New().String()
+	if highlight.CreatedAt.IsZero() {
+		highlight.CreatedAt = time.Now()
+	}
+	highlight.UpdatedAt = highlight.CreatedAt
+
+	_, err := r.client.Collection(highlightsCollection).Doc(highlight.ID).Set(r.ctx, toFirestoreHighlight(highlight))
+	if err != nil {
+		logger.Error("Failed to create highlight in Firestore",
+			zap.String("bookmark_id", highlight.BookmarkID),
+			zap.Error(err))
+		return model.Highlight{}, fmt.Errorf("failed to create highlight: %w", err)
+	}
+
+	return highlight, nil
+}
+
+// GetHighlight retrieves a highlight from Firestore by its ID
+func (r *HighlightFirestoreRepository) GetHighlight(id string) (model.Highlight, error) {
+	docSnap, err := r.client.Collection(highlightsCollection).Doc(id).Get(r.ctx)
+	if status.Code(err) == codes.NotFound {
+		return model.Highlight{}, fmt.Errorf("highlight with ID %s %w", id, model.ErrNotFound)
+	}
+	if err != nil {
+		logger.Error("Failed to get highlight from Firestore", zap.String("id", id), zap.Error(err))
+		return model.Highlight{}, fmt.Errorf("failed to get highlight: %w", err)
+	}
+
+	var fsHighlight firestoreHighlight
+	if err := docSnap.DataTo(&fsHighlight); err != nil {
+		return model.Highlight{}, fmt.Errorf("failed to parse highlight data: %w", err)
+	}
+
+	return toModelHighlight(fsHighlight), nil
+}
+
+// ListHighlights retrieves the highlights of a bookmark from Firestore in the order of their
+// anchors
+func (r *HighlightFirestoreRepository) ListHighlights(bookmarkID string) ([]model.Highlight, error) {
+	iter := r.client.Collection(highlightsCollection).
+		Where("bookmark_id", "==", bookmarkID).
+		OrderBy("anchor_start", firestore.Asc).
+		OrderBy("created_at", firestore.Asc).
+		Documents(r.ctx)
+	defer iter.Stop()
+
+	highlights := []model.Highlight{}
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			logger.Error("Failed to list highlights from Firestore",
+				zap.String("bookmark_id", bookmarkID),
+				zap.Error(err))
+			return nil, fmt.Errorf("failed to list highlights: %w", err)
+		}
+
+		var fsHighlight firestoreHighlight
+		if err := doc.DataTo(&fsHighlight); err != nil {
+			return nil, fmt.Errorf("failed to parse highlight data: %w", err)
+		}
+		highlights = append(highlights, toModelHighlight(fsHighlight))
+	}
+
+	return highlights, nil
+}
+
+// UpdateHighlight replaces an existing highlight in Firestore
+func (r *HighlightFirestoreRepository) UpdateHighlight(highlight model.Highlight) (model.Highlight, error) {
+	highlight.UpdatedAt = time.Now()
+
+	doc := r.client.Collection(highlightsCollection).Doc(highlight.ID)
+	err := r.client.RunTransaction(r.ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		if _, err := tx.Get(doc); err != nil {
+			return err
+		}
+		return tx.Set(doc, toFirestoreHighlight(highlight))
+	})
+	if status.Code(err) == codes.NotFound {
+		return model.Highlight{}, fmt.Errorf("highlight with ID %s %w", highlight.ID, model.ErrNotFound)
+	}
+	if err != nil {
+		logger.Error("Failed to update highlight in Firestore", zap.String("id", highlight.ID), zap.Error(err))
+		return model.Highlight{}, fmt.Errorf("failed to update highlight: %w", err)
+	}
+
+	return highlight, nil
+}
+
+// DeleteHighlight deletes a highlight from Firestore
+func (r *HighlightFirestoreRepository) DeleteHighlight(id string) error {
+	_, err := r.client.Collection(highlightsCollection).Doc(id).Delete(r.ctx, firestore.Exists)
+	if status.Code(err) == codes.NotFound {
+		return fmt.Errorf("highlight with ID %s %w", id, model.ErrNotFound)
+	}
+	if err != nil {
+		logger.Error("Failed to delete highlight from Firestore", zap.String("id", id), zap.Error(err))
+		return fmt.Errorf("failed to delete highlight: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteBookmarkHighlights deletes every highlight of a bookmark from Firestore with a
+// BulkWriter
+func (r *HighlightFirestoreRepository) DeleteBookmarkHighlights(bookmarkID string) error {
+	refs, err := r.client.Collection(highlightsCollection).
+		Where("bookmark_id", "==", bookmarkID).
+		Documents(r.ctx).GetAll()
+	if err != nil {
+		logger.Error("Failed to list highlights from Firestore",
+			zap.String("bookmark_id", bookmarkID),
+			zap.Error(err))
+		return fmt.Errorf("failed to list highlights: %w", err)
+	}
+	if len(refs) == 0 {
+		return nil
+	}
+
+	bulkWriter := r.client.BulkWriter(r.ctx)
+	jobs := make([]*firestore.BulkWriterJob, 0, len(refs))
+	for _, doc := range refs {
+		job, err := bulkWriter.Delete(doc.Ref)
+		if err != nil {
+			bulkWriter.End()
+			return fmt.Errorf("failed to delete highlights: %w", err)
+		}
+		jobs = append(jobs, job)
+	}
+	bulkWriter.End()
+
+	for _, job := range jobs {
+		if _, err := job.Results(); err != nil {
+			logger.Error("Failed to delete highlight from Firestore",
+				zap.String("bookmark_id", bookmarkID),
+				zap.Error(err))
+			return fmt.Errorf("failed to delete highlights: %w", err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tsongpon/athena/internal/model"
+)
+
+// HighlightInMemRepository implements HighlightRepository interface using an in-memory map
+type HighlightInMemRepository struct {
+	highlights map[string]model.Highlight
+	mutex      sync.RWMutex
+}
+
+// NewHighlightInMemRepository creates a new instance of HighlightInMemRepository
+func NewHighlightInMemRepository() *HighlightInMemRepository {
+	return &HighlightInMemRepository{
+		highlights: make(map[string]model.Highlight),
+		mutex:      sync.RWMutex{},
+	}
+}
+
+// CreateHighlight stores a new highlight with a generated ID
+func (r *HighlightInMemRepository) CreateHighlight(highlight model.Highlight) (model.Highlight, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	highlight.ID = uuid.New().String()
+	if highlight.CreatedAt.IsZero() {
+		highlight.CreatedAt = time.Now()
+	}
+	highlight.UpdatedAt = highlight.CreatedAt
+
+	r.highlights[highlight.ID] = highlight
+
+	return highlight, nil
+}
+
+// GetHighlight retrieves a highlight by its ID
+func (r *HighlightInMemRepository) GetHighlight(id string) (model.Highlight, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	highlight, exists := r.highlights[id]
+	if !exists {
+		return model.Highlight{}, fmt.Errorf("highlight with ID %s %w", id, model.ErrNotFound)
+	}
+
+	return highlight, nil
+}
+
+// ListHighlights retrieves the highlights of a bookmark in the order of their anchors
+func (r *HighlightInMemRepository) ListHighlights(bookmarkID string) ([]model.Highlight, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	highlights := []model.Highlight{}
+	for _, highlight := range r.highlights {
+		if highlight.BookmarkID == bookmarkID {
+			highlights = append(highlights, highlight)
+		}
+	}
+
+	sort.Slice(highlights, func(i, j int) bool {
+		if highlights[i].Anchor.Start != highlights[j].Anchor.Start {
+			return highlights[i].Anchor.Start < highlights[j].Anchor.Start
+		}
+		return highlights[i].CreatedAt.Before(highlights[j].CreatedAt)
+	})
+
+	return highlights, nil
+}
+
+// UpdateHighlight replaces an existing highlight
+func (r *HighlightInMemRepository) UpdateHighlight(highlight model.Highlight) (model.Highlight, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, exists := r.highlights[highlight.ID]; !exists {
+		return model.Highlight{}, fmt.Errorf("highlight with ID %s %w", highlight.ID, model.ErrNotFound)
+	}
+	highlight.UpdatedAt = time.Now()
+
+	r.highlights[highlight.ID] = highlight
+
+	return highlight, nil
+}
+
+// DeleteHighlight deletes a highlight by its ID
+func (r *HighlightInMemRepository) DeleteHighlight(id string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, exists := r.highlights[id]; !exists {
+		return fmt.Errorf("highlight with ID %s %w", id, model.ErrNotFound)
+	}
+
+	delete(r.highlights, id)
+
+	return nil
+}
+
+// DeleteBookmarkHighlights deletes every highlight of a bookmark
+func (r *HighlightInMemRepository) DeleteBookmarkHighlights(bookmarkID string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	for id, highlight := range r.highlights {
+		if highlight.BookmarkID == bookmarkID {
+			delete(r.highlights, id)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/tsongpon/athena/internal/model"
+)
+
+func TestHighlightInMemRepository_CRUD(t *testing.T) {
+	repo := NewHighlightInMemRepository()
+	base := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	second, _ := repo.CreateHighlight(model.Highlight{BookmarkID: "b1", Quote: "second", Anchor: model.HighlightAnchor{Start: 50, End: 56}, CreatedAt: base})
+	first, _ := repo.CreateHighlight(model.Highlight{BookmarkID: "b1", Quote: "first", Anchor: model.HighlightAnchor{Start: 5, End: 10}, CreatedAt: base.Add(time.Hour)})
+	repo.CreateHighlight(model.Highlight{BookmarkID: "b2", Quote: "other"})
+	if first.ID == "" || first.ID == second.ID {
+		t.Fatalf("CreateHighlight() IDs = %q and %q, want distinct IDs", first.ID, second.ID)
+	}
+
+	highlights, err := repo.ListHighlights("b1")
+	if err != nil {
+		t.Fatalf("ListHighlights() unexpected error = %v", err)
+	}
+	if len(highlights) != 2 || highlights[0].ID != first.ID || highlights[1].ID != second.ID {
+		t.Errorf("ListHighlights() = %+v, want first then second by anchor", highlights)
+	}
+
+	first.Note = "a note"
+	if _, err := repo.UpdateHighlight(first); err != nil {
+		t.Fatalf("UpdateHighlight() unexpected error = %v", err)
+	}
+	if got, _ := repo.GetHighlight(first.ID); got.Note != "a note" || got.UpdatedAt.IsZero() {
+		t.Errorf("GetHighlight() after update = %+v, want the note and an update time", got)
+	}
+	if _, err := repo.UpdateHighlight(model.Highlight{ID: "missing"}); !errors.Is(err, model.ErrNotFound) {
+		t.Errorf("UpdateHighlight() of missing highlight error = %v, want ErrNotFound", err)
+	}
+
+	if err := repo.DeleteHighlight(second.ID); err != nil {
+		t.Fatalf("DeleteHighlight() unexpected error = %v", err)
+	}
+	if _, err := repo.GetHighlight(second.ID); !errors.Is(err, model.ErrNotFound) {
+		t.Errorf("GetHighlight() after delete error = %v, want ErrNotFound", err)
+	}
+
+	if err := repo.DeleteBookmarkHighlights("b1"); err != nil {
+		t.Fatalf("DeleteBookmarkHighlights() unexpected error = %v", err)
+	}
+	if remaining, _ := repo.ListHighlights("b1"); len(remaining) != 0 {
+		t.Errorf("ListHighlights() after DeleteBookmarkHighlights() = %+v, want none", remaining)
+	}
+	if other, _ := repo.ListHighlights("b2"); len(other) != 1 {
+		t.Errorf("DeleteBookmarkHighlights() removed highlights of another bookmark")
+	}
+}
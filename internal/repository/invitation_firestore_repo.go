@@ -0,0 +1,167 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/google/uuid"
+	"github.com/tsongpon/athena/internal/logger"
+	"github.com/tsongpon/athena/internal/model"
+	"go.uber.org/zap"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const invitationsCollection = "invitations"
+
+// InvitationFirestoreRepository implements InvitationRepository interface using GCP Firestore
+type InvitationFirestoreRepository struct {
+	client *firestore.Client
+	ctx    context.Context
+}
+
+// NewInvitationFirestoreRepository creates a new instance of InvitationFirestoreRepository
+func NewInvitationFirestoreRepository(ctx context.Context, client *firestore.Client) *InvitationFirestoreRepository {
+	return &InvitationFirestoreRepository{
+		client: client,
+		ctx:    ctx,
+	}
+}
+
+// firestoreInvitation is the structure used to store/retrieve invitations in Firestore
+type firestoreInvitation struct {
+	ID           string    `firestore:"id"`
+	CollectionID string    `firestore:"collection_id"`
+	InviterID    string    `firestore:"inviter_id"`
+	Email        string    `firestore:"email"`
+	Role         string    `firestore:"role"`
+	Status       string    `firestore:"status"`
+	CreatedAt    time.Time `firestore:"created_at"`
+	RespondedAt  time.Time `firestore:"responded_at"`
+}
+
+func toFirestoreInvitation(invitation model.Invitation) firestoreInvitation {
+	return firestoreInvitation{
+		ID:           invitation.ID,
+		CollectionID: invitation.CollectionID,
+		InviterID:    invitation.InviterID,
+		Email:        invitation.Email,
+		Role:         invitation.Role,
+		Status:       invitation.Status,
+		CreatedAt:    invitation.CreatedAt,
+		RespondedAt:  invitation.RespondedAt,
+	}
+}
+
+func toModelInvitation(fsInvitation firestoreInvitation) model.Invitation {
+	return model.Invitation{
+		ID:           fsInvitation.ID,
+		CollectionID: fsInvitation.CollectionID,
+		InviterID:    fsInvitation.InviterID,
+		Email:        fsInvitation.Email,
+		Role:         fsInvitation.Role,
+		Status:       fsInvitation.Status,
+		CreatedAt:    fsInvitation.CreatedAt,
+		RespondedAt:  fsInvitation.RespondedAt,
+	}
+}
+
+// CreateInvitation stores a new invitation in Firestore
+func (r *InvitationFirestoreRepository) CreateInvitation(invitation model.Invitation) (model.Invitation, error) {
+	invitation.ID = uuid.New().String()
+	if invitation.CreatedAt.IsZero() {
+		invitation.CreatedAt = time.Now()
+	}
+
+	_, err := r.client.Collection(invitationsCollection).Doc(invitation.ID).Set(r.ctx, toFirestoreInvitation(invitation))
+	if err != nil {
+		logger.Error("Failed to create invitation in Firestore",
+			zap.String("collection_id", invitation.CollectionID),
+			zap.Error(err))
+		return model.Invitation{}, fmt.Errorf("failed to create invitation: %w", err)
+	}
+
+	return invitation, nil
+}
+
+// GetInvitation retrieves an invitation by its ID from Firestore
+func (r *InvitationFirestoreRepository) GetInvitation(id string) (model.Invitation, error) {
+	docSnap, err := r.client.Collection(invitationsCollection).Doc(id).Get(r.ctx)
+	if status.Code(err) == codes.NotFound {
+		return model.Invitation{}, fmt.Errorf("invitation with ID %s %w", id, model.ErrNotFound)
+	}
+	if err != nil {
+		logger.Error("Failed to get invitation from Firestore",
+			zap.String("id", id),
+			zap.Error(err))
+		return model.Invitation{}, fmt.Errorf("failed to get invitation: %w", err)
+	}
+
+	var fsInvitation firestoreInvitation
+	if err := docSnap.DataTo(&fsInvitation); err != nil {
+		return model.Invitation{}, fmt.Errorf("failed to parse invitation data: %w", err)
+	}
+
+	return toModelInvitation(fsInvitation), nil
+}
+
+// UpdateInvitation updates an existing invitation in Firestore
+func (r *InvitationFirestoreRepository) UpdateInvitation(invitation model.Invitation) (model.Invitation, error) {
+	existing, err := r.GetInvitation(invitation.ID)
+	if err != nil {
+		return model.Invitation{}, err
+	}
+
+	invitation.CreatedAt = existing.CreatedAt
+	_, err = r.client.Collection(invitationsCollection).Doc(invitation.ID).Set(r.ctx, toFirestoreInvitation(invitation))
+	if err != nil {
+		logger.Error("Failed to update invitation in Firestore",
+			zap.String("id", invitation.ID),
+			zap.Error(err))
+		return model.Invitation{}, fmt.Errorf("failed to update invitation: %w", err)
+	}
+
+	return invitation, nil
+}
+
+// ListInvitationsByEmail retrieves invitations sent to email from Firestore, newest first
+func (r *InvitationFirestoreRepository) ListInvitationsByEmail(email string) ([]model.Invitation, error) {
+	return r.list(r.client.Collection(invitationsCollection).
+		Where("email", "==", email).
+		OrderBy("created_at", firestore.Desc))
+}
+
+// ListInvitationsByCollection retrieves invitations of a collection from Firestore, newest first
+func (r *InvitationFirestoreRepository) ListInvitationsByCollection(collectionID string) ([]model.Invitation, error) {
+	return r.list(r.client.Collection(invitationsCollection).
+		Where("collection_id", "==", collectionID).
+		OrderBy("created_at", firestore.Desc))
+}
+
+func (r *InvitationFirestoreRepository) list(query firestore.Query) ([]model.Invitation, error) {
+	iter := query.Documents(r.ctx)
+	defer iter.Stop()
+
+	invitations := []model.Invitation{}
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			logger.Error("Failed to list invitations from Firestore", zap.Error(err))
+			return nil, fmt.Errorf("failed to list invitations: %w", err)
+		}
+
+		var fsInvitation firestoreInvitation
+		if err := doc.DataTo(&fsInvitation); err != nil {
+			return nil, fmt.Errorf("failed to parse invitation data: %w", err)
+		}
+		invitations = append(invitations, toModelInvitation(fsInvitation))
+	}
+
+	return invitations, nil
+}
@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tsongpon/athena/internal/model"
+)
+
+// InvitationInMemRepository implements InvitationRepository interface using an in-memory map
+type InvitationInMemRepository struct {
+	invitations map[string]model.Invitation
+	mutex       sync.RWMutex
+}
+
+// NewInvitationInMemRepository creates a new instance of InvitationInMemRepository
+func NewInvitationInMemRepository() *InvitationInMemRepository {
+	return &InvitationInMemRepository{
+		invitations: make(map[string]model.Invitation),
+		mutex:       sync.RWMutex{},
+	}
+}
+
+// CreateInvitation stores a new invitation
+func (r *InvitationInMemRepository) CreateInvitation(invitation model.Invitation) (model.Invitation, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	invitation.ID = uuid.New().String()
+	if invitation.CreatedAt.IsZero() {
+		invitation.CreatedAt = time.Now()
+	}
+
+	r.invitations[invitation.ID] = invitation
+
+	return invitation, nil
+}
+
+// GetInvitation retrieves an invitation by its ID
+func (r *InvitationInMemRepository) GetInvitation(id string) (model.Invitation, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	invitation, exists := r.invitations[id]
+	if !exists {
+		return model.Invitation{}, fmt.Errorf("invitation with ID %s %w", id, model.ErrNotFound)
+	}
+
+	return invitation, nil
+}
+
+// UpdateInvitation updates an existing invitation
+func (r *InvitationInMemRepository) UpdateInvitation(invitation model.Invitation) (model.Invitation, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	existing, exists := r.invitations[invitation.ID]
+	if !exists {
+		return model.Invitation{}, fmt.Errorf("invitation with ID %s %w", invitation.ID, model.ErrNotFound)
+	}
+
+	invitation.CreatedAt = existing.CreatedAt
+	r.invitations[invitation.ID] = invitation
+
+	return invitation, nil
+}
+
+// ListInvitationsByEmail retrieves invitations sent to email, newest first
+func (r *InvitationInMemRepository) ListInvitationsByEmail(email string) ([]model.Invitation, error) {
+	return r.list(func(invitation model.Invitation) bool {
+		return invitation.Email == email
+	}), nil
+}
+
+// ListInvitationsByCollection retrieves invitations of a collection, newest first
+func (r *InvitationInMemRepository) ListInvitationsByCollection(collectionID string) ([]model.Invitation, error) {
+	return r.list(func(invitation model.Invitation) bool {
+		return invitation.CollectionID == collectionID
+	}), nil
+}
+
+func (r *InvitationInMemRepository) list(match func(model.Invitation) bool) []model.Invitation {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	invitations := []model.Invitation{}
+	for _, invitation := range r.invitations {
+		if match(invitation) {
+			invitations = append(invitations, invitation)
+		}
+	}
+
+	sort.Slice(invitations, func(i, j int) bool {
+		return invitations[i].CreatedAt.After(invitations[j].CreatedAt)
+	})
+
+	return invitations
+}
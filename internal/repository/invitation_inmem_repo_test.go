@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/tsongpon/athena/internal/model"
+)
+
+func TestInvitationInMemRepository_CreateUpdateAndList(t *testing.T) {
+	repo := NewInvitationInMemRepository()
+	now := time.Now()
+
+	older, err := repo.CreateInvitation(model.Invitation{CollectionID: "c1", Email: "a@example.com", Status: model.InvitationPending, CreatedAt: now.Add(-time.Hour)})
+	if err != nil {
+		t.Fatalf("CreateInvitation() unexpected error = %v", err)
+	}
+	newer, _ := repo.CreateInvitation(model.Invitation{CollectionID: "c2", Email: "a@example.com", Status: model.InvitationPending, CreatedAt: now})
+	repo.CreateInvitation(model.Invitation{CollectionID: "c1", Email: "b@example.com", Status: model.InvitationPending, CreatedAt: now})
+
+	byEmail, _ := repo.ListInvitationsByEmail("a@example.com")
+	if len(byEmail) != 2 || byEmail[0].ID != newer.ID || byEmail[1].ID != older.ID {
+		t.Errorf("ListInvitationsByEmail() = %+v, want newest first", byEmail)
+	}
+	byCollection, _ := repo.ListInvitationsByCollection("c1")
+	if len(byCollection) != 2 {
+		t.Errorf("ListInvitationsByCollection() returned %d invitations, want 2", len(byCollection))
+	}
+
+	older.Status = model.InvitationAccepted
+	older.CreatedAt = time.Time{}
+	updated, err := repo.UpdateInvitation(older)
+	if err != nil {
+		t.Fatalf("UpdateInvitation() unexpected error = %v", err)
+	}
+	if updated.Status != model.InvitationAccepted || updated.CreatedAt.IsZero() {
+		t.Errorf("UpdateInvitation() = %+v, want accepted with the original CreatedAt", updated)
+	}
+
+	if _, err := repo.GetInvitation("missing"); !errors.Is(err, model.ErrNotFound) {
+		t.Errorf("GetInvitation() error = %v, want ErrNotFound", err)
+	}
+	if _, err := repo.UpdateInvitation(model.Invitation{ID: "missing"}); !errors.Is(err, model.ErrNotFound) {
+		t.Errorf("UpdateInvitation() error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestActivityInMemRepository_ListActivities(t *testing.T) {
+	repo := NewActivityInMemRepository()
+	for _, action := range []string{model.ActivityBookmarkAdded, model.ActivityBookmarkRemoved, model.ActivityBookmarksReordered} {
+		repo.CreateActivity(model.Activity{CollectionID: "c1", ActorID: "user-1", Action: action})
+	}
+	repo.CreateActivity(model.Activity{CollectionID: "c2", ActorID: "user-1", Action: model.ActivityBookmarkAdded})
+
+	activities, err := repo.ListActivities("c1", 2)
+	if err != nil {
+		t.Fatalf("ListActivities() unexpected error = %v", err)
+	}
+	if len(activities) != 2 || activities[0].Action != model.ActivityBookmarksReordered || activities[1].Action != model.ActivityBookmarkRemoved {
+		t.Errorf("ListActivities() = %+v, want the two newest activities of c1", activities)
+	}
+}
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/tsongpon/athena/internal/model"
+)
+
+// HTTPLinkChecker implements LinkChecker with HEAD requests, falling back to GET for servers
+// that do not support HEAD
+type HTTPLinkChecker struct {
+	httpClient *http.Client
+}
+
+// NewHTTPLinkChecker creates a link checker whose requests go through fetcher, which spaces
+// out requests to the same host. A nil fetcher sends requests directly.
+func NewHTTPLinkChecker(fetcher *Fetcher) *HTTPLinkChecker {
+	client := &http.Client{
+		Timeout: 15 * time.Second,
+	}
+	if fetcher != nil {
+		client.Transport = fetcher
+	}
+	return &HTTPLinkChecker{
+		httpClient: client,
+	}
+}
+
+// CheckLink requests rawURL and classifies the outcome. 404, 410 and unknown hosts are broken;
+// other failures are reported as errors since they are often temporary.
+func (c *HTTPLinkChecker) CheckLink(ctx context.Context, rawURL string) model.LinkHealth {
+	health := model.LinkHealth{CheckedAt: time.Now()}
+
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		health.Status = model.LinkHealthBroken
+		health.Error = "invalid URL"
+		return health
+	}
+
+	resp, err := c.request(ctx, http.MethodHead, u)
+	if err == nil && headUnsupported(resp.StatusCode) {
+		resp, err = c.request(ctx, http.MethodGet, u)
+	}
+	health.CheckedAt = time.Now()
+	if err != nil {
+		health.Status = model.LinkHealthError
+		var dnsErr *net.DNSError
+		if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+			health.Status = model.LinkHealthBroken
+		}
+		health.Error = err.Error()
+		return health
+	}
+
+	health.StatusCode = resp.StatusCode
+	health.FinalURL = resp.Request.URL.String()
+	switch {
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		health.Status = model.LinkHealthBroken
+	case resp.StatusCode < http.StatusBadRequest:
+		health.Status = model.LinkHealthOK
+	default:
+		health.Status = model.LinkHealthError
+	}
+	return health
+}
+
+// request sends a single request. Only the status and final URL are needed, so the body is
+// discarded.
+func (c *HTTPLinkChecker) request(ctx context.Context, method string, u *url.URL) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	resp.Body.Close()
+	return resp, nil
+}
+
+// headUnsupported reports whether a HEAD response suggests retrying with GET: many servers
+// reject or mishandle HEAD while serving the page fine
+func headUnsupported(statusCode int) bool {
+	switch statusCode {
+	case http.StatusMethodNotAllowed, http.StatusNotImplemented, http.StatusForbidden, http.StatusBadRequest:
+		return true
+	}
+	return false
+}
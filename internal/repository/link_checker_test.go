@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/tsongpon/athena/internal/model"
+)
+
+func TestHTTPLinkChecker_CheckLink(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ok", func(w http.ResponseWriter, r *http.Request) {})
+	mux.HandleFunc("/gone", func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusGone) })
+	mux.HandleFunc("/moved", func(w http.ResponseWriter, r *http.Request) { http.Redirect(w, r, "/ok", http.StatusMovedPermanently) })
+	mux.HandleFunc("/unavailable", func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusServiceUnavailable) })
+	mux.HandleFunc("/get-only", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodHead {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	tests := []struct {
+		path           string
+		wantStatus     string
+		wantStatusCode int
+		wantFinalURL   string
+	}{
+		{"/ok", model.LinkHealthOK, http.StatusOK, server.URL + "/ok"},
+		{"/missing", model.LinkHealthBroken, http.StatusNotFound, server.URL + "/missing"},
+		{"/gone", model.LinkHealthBroken, http.StatusGone, server.URL + "/gone"},
+		{"/moved", model.LinkHealthOK, http.StatusOK, server.URL + "/ok"},
+		{"/unavailable", model.LinkHealthError, http.StatusServiceUnavailable, server.URL + "/unavailable"},
+		{"/get-only", model.LinkHealthOK, http.StatusOK, server.URL + "/get-only"},
+	}
+	checker := NewHTTPLinkChecker(nil)
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			health := checker.CheckLink(context.Background(), server.URL+tt.path)
+			if health.Status != tt.wantStatus || health.StatusCode != tt.wantStatusCode || health.FinalURL != tt.wantFinalURL {
+				t.Errorf("CheckLink() = %+v, want status %s, code %d, final URL %s", health, tt.wantStatus, tt.wantStatusCode, tt.wantFinalURL)
+			}
+			if health.CheckedAt.IsZero() {
+				t.Error("CheckLink() should set CheckedAt")
+			}
+		})
+	}
+}
+
+func TestHTTPLinkChecker_CheckLink_Unreachable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	url := server.URL
+	server.Close()
+
+	health := NewHTTPLinkChecker(nil).CheckLink(context.Background(), url)
+	if health.Status != model.LinkHealthError || health.Error == "" {
+		t.Errorf("CheckLink() of a closed server = %+v, want error status with a reason", health)
+	}
+}
@@ -0,0 +1,240 @@
+package repository
+
+import (
+	"encoding/json"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// pageMetadata is what a page says about itself in Open Graph and Twitter Card meta tags,
+// schema.org JSON-LD and <link> elements. URLs are absolute when the page URL is known.
+type pageMetadata struct {
+	title       string // og:title, twitter:title or <title>, in that order
+	htmlTitle   string // <title> alone
+	description string
+	siteName    string
+	pageType    string // og:type such as "article" or "video.other"
+	imageURL    string
+	author      string
+	publishedAt time.Time
+	faviconURL  string
+	oembedURL   string // JSON oEmbed endpoint the page advertises
+}
+
+// articleTypes are the schema.org types whose author and publication date describe the page.
+// Other typed nodes are only used when no article is found.
+var articleTypes = map[string]bool{
+	"Article": true, "NewsArticle": true, "BlogPosting": true, "TechArticle": true,
+	"ScholarlyArticle": true, "Report": true, "SocialMediaPosting": true, "VideoObject": true,
+}
+
+// extractMetadata collects the metadata of a parsed page. base is the page URL after
+// redirects and may be nil, in which case relative URLs are kept as they are.
+func extractMetadata(doc *html.Node, base *url.URL) pageMetadata {
+	meta := map[string]string{} // First content of each meta property or name
+	var icon, touchIcon, oembed string
+	var titleText string
+	var ldNodes []map[string]any
+
+	var traverse func(*html.Node)
+	traverse = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			switch n.DataAtom {
+			case atom.Title:
+				if titleText == "" && n.FirstChild != nil {
+					titleText = strings.TrimSpace(n.FirstChild.Data)
+				}
+			case atom.Meta:
+				content := strings.TrimSpace(attr(n, "content"))
+				// Open Graph specifies property=, but name= is common in the wild
+				for _, key := range []string{attr(n, "property"), attr(n, "name")} {
+					key = strings.ToLower(strings.TrimSpace(key))
+					if key != "" && content != "" {
+						if _, seen := meta[key]; !seen {
+							meta[key] = content
+						}
+					}
+				}
+			case atom.Link:
+				rel := strings.ToLower(attr(n, "rel"))
+				href := strings.TrimSpace(attr(n, "href"))
+				switch {
+				case href == "":
+				case hasToken(rel, "apple-touch-icon") || hasToken(rel, "apple-touch-icon-precomposed"):
+					if touchIcon == "" {
+						touchIcon = href
+					}
+				case hasToken(rel, "icon"):
+					if icon == "" {
+						icon = href
+					}
+				case hasToken(rel, "alternate") && strings.EqualFold(attr(n, "type"), "application/json+oembed"):
+					if oembed == "" {
+						oembed = href
+					}
+				}
+			case atom.Script:
+				if strings.EqualFold(strings.TrimSpace(attr(n, "type")), "application/ld+json") && n.FirstChild != nil {
+					ldNodes = append(ldNodes, parseJSONLD(n.FirstChild.Data)...)
+				}
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			traverse(c)
+		}
+	}
+	traverse(doc)
+
+	m := pageMetadata{
+		title:       firstNonEmpty(meta["og:title"], meta["twitter:title"], titleText),
+		htmlTitle:   titleText,
+		description: firstNonEmpty(meta["og:description"], meta["twitter:description"], meta["description"]),
+		siteName:    meta["og:site_name"],
+		pageType:    meta["og:type"],
+		imageURL: resolveMetadataURL(base, firstNonEmpty(
+			meta["og:image"], meta["og:image:secure_url"], meta["og:image:url"],
+			meta["twitter:image"], meta["twitter:image:src"])),
+		faviconURL: resolveMetadataURL(base, firstNonEmpty(icon, touchIcon)),
+		oembedURL:  resolveMetadataURL(base, oembed),
+	}
+	if m.faviconURL == "" && base != nil {
+		// Browsers fall back to the conventional location
+		m.faviconURL = (&url.URL{Scheme: base.Scheme, Host: base.Host, Path: "/favicon.ico"}).String()
+	}
+
+	author, published := jsonLDArticle(ldNodes)
+	if author == "" {
+		// article:author is often a profile URL rather than a name
+		if a := meta["article:author"]; a != "" && !strings.Contains(a, "://") {
+			author = a
+		} else {
+			author = meta["author"]
+		}
+	}
+	m.author = author
+	m.publishedAt = published
+	if m.publishedAt.IsZero() {
+		m.publishedAt = parseMetadataTime(meta["article:published_time"])
+	}
+	return m
+}
+
+// parseJSONLD returns the objects of a JSON-LD script, flattening arrays and @graph
+func parseJSONLD(script string) []map[string]any {
+	var data any
+	if err := json.Unmarshal([]byte(script), &data); err != nil {
+		return nil
+	}
+	var nodes []map[string]any
+	var walk func(any)
+	walk = func(v any) {
+		switch v := v.(type) {
+		case []any:
+			for _, item := range v {
+				walk(item)
+			}
+		case map[string]any:
+			nodes = append(nodes, v)
+			walk(v["@graph"])
+		}
+	}
+	walk(data)
+	return nodes
+}
+
+// jsonLDArticle returns the author and publication date of the first article-like node,
+// falling back to the first node that has either
+func jsonLDArticle(nodes []map[string]any) (string, time.Time) {
+	var fallback map[string]any
+	for _, node := range nodes {
+		if node["author"] == nil && node["datePublished"] == nil {
+			continue
+		}
+		if hasJSONLDType(node, articleTypes) {
+			return jsonLDNames(node["author"]), parseMetadataTime(jsonLDString(node["datePublished"]))
+		}
+		if fallback == nil {
+			fallback = node
+		}
+	}
+	if fallback == nil {
+		return "", time.Time{}
+	}
+	return jsonLDNames(fallback["author"]), parseMetadataTime(jsonLDString(fallback["datePublished"]))
+}
+
+func hasJSONLDType(node map[string]any, types map[string]bool) bool {
+	switch t := node["@type"].(type) {
+	case string:
+		return types[t]
+	case []any:
+		for _, item := range t {
+			if s, ok := item.(string); ok && types[s] {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// jsonLDNames reads a schema.org person or organization, given as a name, an object or a list
+func jsonLDNames(v any) string {
+	switch v := v.(type) {
+	case string:
+		return strings.TrimSpace(v)
+	case map[string]any:
+		return jsonLDString(v["name"])
+	case []any:
+		var names []string
+		for _, item := range v {
+			if name := jsonLDNames(item); name != "" {
+				names = append(names, name)
+			}
+		}
+		return strings.Join(names, ", ")
+	}
+	return ""
+}
+
+func jsonLDString(v any) string {
+	s, _ := v.(string)
+	return strings.TrimSpace(s)
+}
+
+// parseMetadataTime reads the ISO 8601 variants used for publication dates
+func parseMetadataTime(value string) time.Time {
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04:05Z0700", "2006-01-02T15:04:05", "2006-01-02T15:04", "2006-01-02"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// resolveMetadataURL makes ref absolute against base, dropping anything that is not http(s)
+func resolveMetadataURL(base *url.URL, ref string) string {
+	if ref == "" {
+		return ""
+	}
+	if base == nil {
+		return ref
+	}
+	resolved, ok := resolveAgainst(base, ref)
+	if !ok || !(strings.HasPrefix(resolved, "http://") || strings.HasPrefix(resolved, "https://")) {
+		return ""
+	}
+	return resolved
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/net/html"
+)
+
+func parseMetadata(t *testing.T, page, pageURL string) pageMetadata {
+	t.Helper()
+	doc, err := html.Parse(strings.NewReader(page))
+	if err != nil {
+		t.Fatalf("html.Parse() error = %v", err)
+	}
+	base, _ := url.Parse(pageURL)
+	return extractMetadata(doc, base)
+}
+
+func TestExtractMetadata(t *testing.T) {
+	page := `<!DOCTYPE html><html><head>
+		<title>How to Bake Bread | Example Kitchen</title>
+		<meta name="og:title" content="How to Bake Bread">
+		<meta property="og:description" content="A beginner's guide.">
+		<meta property="og:site_name" content="Example Kitchen">
+		<meta property="og:type" content="article">
+		<meta property="og:image" content="/images/bread.jpg">
+		<meta name="twitter:image" content="https://cdn.example.com/twitter.jpg">
+		<link rel="apple-touch-icon" href="/apple-touch-icon.png">
+		<link rel="shortcut icon" href="favicon.png">
+		<link rel="alternate" type="application/json+oembed" href="/oembed?url=bread">
+		<script type="application/ld+json">
+		{"@context": "https://schema.org", "@graph": [
+			{"@type": "WebSite", "name": "Example Kitchen"},
+			{"@type": "Article", "author": [{"@type": "Person", "name": "Ann Baker"}, {"name": "Bo Cook"}],
+			 "datePublished": "2024-03-01T09:30:00+01:00"}
+		]}
+		</script>
+	</head><body></body></html>`
+
+	m := parseMetadata(t, page, "https://example.com/recipes/bread")
+
+	checks := []struct{ field, got, want string }{
+		{"title", m.title, "How to Bake Bread"},
+		{"htmlTitle", m.htmlTitle, "How to Bake Bread | Example Kitchen"},
+		{"description", m.description, "A beginner's guide."},
+		{"siteName", m.siteName, "Example Kitchen"},
+		{"pageType", m.pageType, "article"},
+		{"imageURL", m.imageURL, "https://example.com/images/bread.jpg"},
+		{"author", m.author, "Ann Baker, Bo Cook"},
+		{"faviconURL", m.faviconURL, "https://example.com/recipes/favicon.png"},
+		{"oembedURL", m.oembedURL, "https://example.com/oembed?url=bread"},
+	}
+	for _, c := range checks {
+		if c.got != c.want {
+			t.Errorf("extractMetadata() %s = %q, want %q", c.field, c.got, c.want)
+		}
+	}
+	wantPublished := time.Date(2024, 3, 1, 8, 30, 0, 0, time.UTC)
+	if !m.publishedAt.Equal(wantPublished) {
+		t.Errorf("extractMetadata() publishedAt = %v, want %v", m.publishedAt, wantPublished)
+	}
+}
+
+func TestExtractMetadata_Fallbacks(t *testing.T) {
+	page := `<html><head>
+		<title>Plain Title</title>
+		<meta name="description" content="Meta description">
+		<meta name="twitter:title" content="Twitter Title">
+		<meta name="twitter:image:src" content="//cdn.example.com/card.png">
+		<meta name="author" content="Meta Author">
+		<meta property="article:author" content="https://example.com/authors/someone">
+		<meta property="article:published_time" content="2023-12-24">
+		<link rel="apple-touch-icon-precomposed" href="/touch.png">
+	</head></html>`
+
+	m := parseMetadata(t, page, "https://example.com/post")
+
+	checks := []struct{ field, got, want string }{
+		{"title", m.title, "Twitter Title"},
+		{"description", m.description, "Meta description"},
+		{"imageURL", m.imageURL, "https://cdn.example.com/card.png"},
+		{"author", m.author, "Meta Author"},
+		{"faviconURL", m.faviconURL, "https://example.com/touch.png"},
+	}
+	for _, c := range checks {
+		if c.got != c.want {
+			t.Errorf("extractMetadata() %s = %q, want %q", c.field, c.got, c.want)
+		}
+	}
+	if want := time.Date(2023, 12, 24, 0, 0, 0, 0, time.UTC); !m.publishedAt.Equal(want) {
+		t.Errorf("extractMetadata() publishedAt = %v, want %v", m.publishedAt, want)
+	}
+}
+
+func TestExtractMetadata_DefaultFavicon(t *testing.T) {
+	m := parseMetadata(t, `<html><head><title>x</title></head></html>`, "https://example.com/a/b?c=d")
+	if m.faviconURL != "https://example.com/favicon.ico" {
+		t.Errorf("extractMetadata() faviconURL = %q, want the conventional location", m.faviconURL)
+	}
+}
+
+func TestExtractMetadata_RejectsScriptURLs(t *testing.T) {
+	m := parseMetadata(t, `<html><head><meta property="og:image" content="javascript:alert(1)"></head></html>`, "https://example.com/")
+	if m.imageURL != "" {
+		t.Errorf("extractMetadata() imageURL = %q, want script URLs dropped", m.imageURL)
+	}
+}
+
+func TestJSONLDArticle(t *testing.T) {
+	tests := []struct {
+		name       string
+		script     string
+		wantAuthor string
+		wantDate   time.Time
+	}{
+		{
+			name:       "author as a string",
+			script:     `{"@type": "BlogPosting", "author": "Sam", "datePublished": "2022-05-06"}`,
+			wantAuthor: "Sam",
+			wantDate:   time.Date(2022, 5, 6, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:       "article preferred over other typed nodes",
+			script:     `[{"@type": "WebPage", "author": {"name": "Site Team"}}, {"@type": ["NewsArticle"], "author": {"name": "Reporter"}}]`,
+			wantAuthor: "Reporter",
+		},
+		{
+			name:       "other typed node as fallback",
+			script:     `{"@type": "Recipe", "author": {"@type": "Person", "name": "Chef"}, "datePublished": "2021-01-02T03:04:05"}`,
+			wantAuthor: "Chef",
+			wantDate:   time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC),
+		},
+		{
+			name:   "invalid JSON",
+			script: `{"@type": "Article",`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			author, date := jsonLDArticle(parseJSONLD(tt.script))
+			if author != tt.wantAuthor || !date.Equal(tt.wantDate) {
+				t.Errorf("jsonLDArticle() = %q, %v, want %q, %v", author, date, tt.wantAuthor, tt.wantDate)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/tsongpon/athena/internal/model"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// maxOEmbedBytes bounds an oEmbed response, which is a small JSON document
+const maxOEmbedBytes = 1 << 20
+
+// oembedResponse holds the oEmbed fields Athena uses. Width and height are numbers by the
+// spec but some providers send strings.
+type oembedResponse struct {
+	Type         string      `json:"type"`
+	ProviderName string      `json:"provider_name"`
+	AuthorName   string      `json:"author_name"`
+	HTML         string      `json:"html"`
+	Width        json.Number `json:"width"`
+	Height       json.Number `json:"height"`
+}
+
+// fetchOEmbed requests the JSON oEmbed endpoint a page advertised
+func (r *WebRepository) fetchOEmbed(ctx context.Context, endpoint string) (oembedResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return oembedResponse{}, err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return oembedResponse{}, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return oembedResponse{}, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	var oembed oembedResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxOEmbedBytes)).Decode(&oembed); err != nil {
+		return oembedResponse{}, fmt.Errorf("invalid oEmbed response: %w", err)
+	}
+	return oembed, nil
+}
+
+// embed returns the player of a video or rich oEmbed response. Only the https URL of the
+// provider's iframe is kept, so clients never render markup from a third party.
+func (o oembedResponse) embed() model.Embed {
+	if o.Type != "video" && o.Type != "rich" {
+		return model.Embed{}
+	}
+	doc, err := html.Parse(strings.NewReader(o.HTML))
+	if err != nil {
+		return model.Embed{}
+	}
+	iframe := findElement(doc, atom.Iframe)
+	if iframe == nil {
+		return model.Embed{}
+	}
+	src := strings.TrimSpace(attr(iframe, "src"))
+	if strings.HasPrefix(src, "//") {
+		src = "https:" + src
+	}
+	if !strings.HasPrefix(src, "https://") {
+		return model.Embed{}
+	}
+
+	embed := model.Embed{
+		Provider: strings.TrimSpace(o.ProviderName),
+		URL:      src,
+	}
+	embed.Width = dimension(o.Width, attr(iframe, "width"))
+	embed.Height = dimension(o.Height, attr(iframe, "height"))
+	return embed
+}
+
+// dimension reads a size in pixels from the oEmbed response, or from the iframe without it
+func dimension(value json.Number, fallback string) int {
+	if n, err := value.Int64(); err == nil && n > 0 {
+		return int(n)
+	}
+	if n, err := strconv.Atoi(strings.TrimSpace(fallback)); err == nil && n > 0 {
+		return n
+	}
+	return 0
+}
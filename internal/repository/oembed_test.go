@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/tsongpon/athena/internal/model"
+)
+
+func TestOEmbedResponse_Embed(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		want     model.Embed
+	}{
+		{
+			name:     "video iframe",
+			response: `{"type": "video", "provider_name": "VideoSite", "width": 480, "height": "270", "html": "<iframe src=\"https://player.example.com/embed/1\"></iframe>"}`,
+			want:     model.Embed{Provider: "VideoSite", URL: "https://player.example.com/embed/1", Width: 480, Height: 270},
+		},
+		{
+			name:     "protocol relative iframe with sizes on the element",
+			response: `{"type": "rich", "html": "<iframe width=\"640\" height=\"360\" src=\"//player.example.com/2\"></iframe>"}`,
+			want:     model.Embed{URL: "https://player.example.com/2", Width: 640, Height: 360},
+		},
+		{
+			name:     "plain http iframe",
+			response: `{"type": "video", "html": "<iframe src=\"http://player.example.com/3\"></iframe>"}`,
+		},
+		{
+			name:     "script instead of iframe",
+			response: `{"type": "rich", "html": "<script src=\"https://widgets.example.com/w.js\"></script>"}`,
+		},
+		{
+			name:     "photo",
+			response: `{"type": "photo", "url": "https://example.com/photo.jpg"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var o oembedResponse
+			if err := json.Unmarshal([]byte(tt.response), &o); err != nil {
+				t.Fatalf("json.Unmarshal() error = %v", err)
+			}
+			if got := o.embed(); got != tt.want {
+				t.Errorf("embed() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,419 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/tsongpon/athena/internal/logger"
+	"go.uber.org/zap"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// Limits on what a single snapshot may pull in
+const (
+	maxArchivePageBytes     = 5 << 20
+	maxArchiveResourceBytes = 2 << 20
+	maxArchiveTotalBytes    = 15 << 20
+	maxArchiveImportDepth   = 3
+)
+
+// snapshotCSP is embedded in every snapshot so that anything the sanitizer misses still
+// cannot run scripts or load remote content
+const snapshotCSP = "default-src 'none'; img-src data:; style-src 'unsafe-inline'; font-src data:; form-action 'none'"
+
+// droppedElements are removed from snapshots together with their content
+var droppedElements = map[string]bool{
+	"script": true, "noscript": true, "template": true, "base": true,
+	"iframe": true, "frame": true, "frameset": true, "object": true, "embed": true, "applet": true,
+	"source": true, "track": true, "portal": true,
+}
+
+// droppedAttributes are removed from every element in addition to event handlers
+var droppedAttributes = map[string]bool{
+	"srcset": true, "sizes": true, "integrity": true, "crossorigin": true, "nonce": true,
+	"ping": true, "action": true, "formaction": true, "data-src": true, "data-srcset": true,
+}
+
+var (
+	cssImportPattern = regexp.MustCompile(`@import\s+(?:url\(\s*)?['"]?([^'"\s);]+)['"]?\s*\)?[^;]*;`)
+	cssURLPattern    = regexp.MustCompile(`url\(\s*['"]?([^'")]+?)['"]?\s*\)`)
+)
+
+// WebArchiver implements PageArchiver by fetching a page and rewriting it into a single
+// sanitized HTML document with stylesheets and images inlined as data URIs
+type WebArchiver struct {
+	httpClient *http.Client
+}
+
+// NewWebArchiver creates an archiver whose requests go through fetcher. A nil fetcher sends
+// requests directly.
+func NewWebArchiver(fetcher *Fetcher) *WebArchiver {
+	client := &http.Client{
+		Timeout: 30 * time.Second,
+	}
+	if fetcher != nil {
+		client.Transport = fetcher
+	}
+	return &WebArchiver{
+		httpClient: client,
+	}
+}
+
+// ArchivePage fetches the HTML page at pageURL and returns a self-contained snapshot of it
+func (a *WebArchiver) ArchivePage(ctx context.Context, pageURL string) ([]byte, error) {
+	if pageURL == "" {
+		return nil, fmt.Errorf("URL cannot be empty")
+	}
+
+	body, page, err := a.fetch(ctx, pageURL, maxArchivePageBytes)
+	if err != nil {
+		return nil, err
+	}
+	if page.mediaType != "text/html" && page.mediaType != "application/xhtml+xml" {
+		return nil, fmt.Errorf("cannot archive %s content from %s", page.mediaType, pageURL)
+	}
+
+	// The snapshot is always UTF-8, whatever the page was written in
+	doc, err := html.Parse(strings.NewReader(decodeText(body, page.contentType)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse page %s: %w", pageURL, err)
+	}
+
+	s := &snapshot{
+		archiver: a,
+		ctx:      ctx,
+		base:     page.url,
+		budget:   maxArchiveTotalBytes,
+		dataURIs: map[string]string{},
+	}
+	s.applyBaseElement(doc)
+	s.sanitize(doc)
+	s.addHead(doc, pageURL)
+
+	var buf bytes.Buffer
+	if err := html.Render(&buf, doc); err != nil {
+		return nil, fmt.Errorf("failed to render snapshot of %s: %w", pageURL, err)
+	}
+	return buf.Bytes(), nil
+}
+
+// fetch downloads rawURL and returns at most limit bytes of its decompressed body along
+// with its media type and URL after redirects
+func (a *WebArchiver) fetch(ctx context.Context, rawURL string, limit int64) ([]byte, *decodedPage, error) {
+	req, err := newPageRequest(ctx, rawURL)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid URL %s: %w", rawURL, err)
+	}
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to fetch %s: %w", rawURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, nil, fmt.Errorf("failed to fetch %s: status %d", rawURL, resp.StatusCode)
+	}
+	page, err := decodePage(resp)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to decode %s: %w", rawURL, err)
+	}
+	body, truncated, err := page.read(limit)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read %s: %w", rawURL, err)
+	}
+	if truncated {
+		return nil, nil, fmt.Errorf("%s is larger than %d bytes", rawURL, limit)
+	}
+	return body, page, nil
+}
+
+// snapshot holds the state of archiving one page
+type snapshot struct {
+	archiver *WebArchiver
+	ctx      context.Context
+	base     *url.URL
+	budget   int64             // Bytes left for inlined resources
+	dataURIs map[string]string // Inlined resources by absolute URL, empty when inlining failed
+}
+
+// applyBaseElement honours the first <base href> of the page when resolving relative URLs
+func (s *snapshot) applyBaseElement(n *html.Node) bool {
+	if n.Type == html.ElementNode && n.DataAtom == atom.Base {
+		if href := attr(n, "href"); href != "" {
+			if base, err := s.base.Parse(strings.TrimSpace(href)); err == nil {
+				s.base = base
+			}
+			return true
+		}
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if s.applyBaseElement(c) {
+			return true
+		}
+	}
+	return false
+}
+
+// sanitize walks the children of n, dropping active content and inlining resources
+func (s *snapshot) sanitize(n *html.Node) {
+	for c := n.FirstChild; c != nil; {
+		next := c.NextSibling
+		switch c.Type {
+		case html.CommentNode:
+			n.RemoveChild(c)
+		case html.ElementNode:
+			if s.element(c) {
+				s.sanitize(c)
+			} else {
+				n.RemoveChild(c)
+			}
+		}
+		c = next
+	}
+}
+
+// element rewrites a single element and reports whether it should be kept
+func (s *snapshot) element(n *html.Node) bool {
+	name := strings.ToLower(n.Data)
+	if droppedElements[name] {
+		return false
+	}
+
+	switch name {
+	case "meta":
+		// The snapshot declares its own charset and policy
+		if attr(n, "charset") != "" || attr(n, "http-equiv") != "" {
+			return false
+		}
+	case "link":
+		// Stylesheets become <style> elements, every other link is dropped
+		if hasToken(attr(n, "rel"), "stylesheet") {
+			if css, ok := s.stylesheet(s.base, attr(n, "href"), 0); ok {
+				style := &html.Node{Type: html.ElementNode, Data: "style", DataAtom: atom.Style}
+				if media := attr(n, "media"); media != "" {
+					style.Attr = []html.Attribute{{Key: "media", Val: media}}
+				}
+				style.AppendChild(&html.Node{Type: html.TextNode, Data: css})
+				n.Parent.InsertBefore(style, n)
+			}
+		}
+		return false
+	case "style":
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type == html.TextNode {
+				c.Data = s.css(c.Data, s.base, 0)
+			}
+		}
+	case "img":
+		// Lazy loading scripts keep the real image in data-src
+		src := attr(n, "data-src")
+		if src == "" {
+			src = attr(n, "src")
+		}
+		setAttr(n, "src", s.inline(src))
+	}
+
+	attrs := n.Attr[:0]
+	for _, a := range n.Attr {
+		key := strings.ToLower(a.Key)
+		switch {
+		case strings.HasPrefix(key, "on") || droppedAttributes[key]:
+			continue
+		case key == "style":
+			a.Val = s.css(a.Val, s.base, 0)
+		case key == "href" || (key == "src" && name != "img") || key == "poster" || key == "background" || key == "cite":
+			resolved, ok := s.resolve(a.Val)
+			if !ok {
+				continue
+			}
+			a.Val = resolved
+		}
+		attrs = append(attrs, a)
+	}
+	n.Attr = attrs
+	return true
+}
+
+// stylesheet fetches the stylesheet href relative to base and returns it with imports and
+// url() references inlined
+func (s *snapshot) stylesheet(base *url.URL, href string, depth int) (string, bool) {
+	resolved, ok := resolveAgainst(base, href)
+	if !ok || depth >= maxArchiveImportDepth || s.budget <= 0 {
+		return "", false
+	}
+	body, page, err := s.archiver.fetch(s.ctx, resolved, maxArchiveResourceBytes)
+	if err != nil {
+		logger.Debug("failed to fetch stylesheet for snapshot", zap.String("url", resolved), zap.Error(err))
+		return "", false
+	}
+	s.budget -= int64(len(body))
+	return s.css(decodeText(body, page.contentType), page.url, depth+1), true
+}
+
+// css inlines @import rules and url() references of a stylesheet loaded from base
+func (s *snapshot) css(css string, base *url.URL, depth int) string {
+	css = cssImportPattern.ReplaceAllStringFunc(css, func(rule string) string {
+		imported, _ := s.stylesheet(base, cssImportPattern.FindStringSubmatch(rule)[1], depth)
+		return imported
+	})
+	return cssURLPattern.ReplaceAllStringFunc(css, func(ref string) string {
+		target := strings.TrimSpace(cssURLPattern.FindStringSubmatch(ref)[1])
+		if strings.HasPrefix(target, "data:") || strings.HasPrefix(target, "#") {
+			return ref
+		}
+		resolved, ok := resolveAgainst(base, target)
+		if !ok {
+			return "none"
+		}
+		return "url(" + s.inline(resolved) + ")"
+	})
+}
+
+// inline returns ref as a data URI, or as an absolute URL when it cannot be inlined
+func (s *snapshot) inline(ref string) string {
+	resolved, ok := s.resolve(ref)
+	if !ok {
+		return ""
+	}
+	if strings.HasPrefix(resolved, "data:") {
+		return resolved
+	}
+	if dataURI, seen := s.dataURIs[resolved]; seen {
+		if dataURI == "" {
+			return resolved
+		}
+		return dataURI
+	}
+
+	s.dataURIs[resolved] = ""
+	if s.budget <= 0 {
+		return resolved
+	}
+	body, page, err := s.archiver.fetch(s.ctx, resolved, maxArchiveResourceBytes)
+	if err != nil {
+		logger.Debug("failed to fetch resource for snapshot", zap.String("url", resolved), zap.Error(err))
+		return resolved
+	}
+	contentType := page.mediaType
+	if !inlinableType(contentType) || int64(len(body)) > s.budget {
+		return resolved
+	}
+	s.budget -= int64(len(body))
+
+	dataURI := "data:" + contentType + ";base64," + base64.StdEncoding.EncodeToString(body)
+	s.dataURIs[resolved] = dataURI
+	return dataURI
+}
+
+// resolve turns ref into an absolute URL against the page, rejecting script URLs
+func (s *snapshot) resolve(ref string) (string, bool) {
+	return resolveAgainst(s.base, ref)
+}
+
+func resolveAgainst(base *url.URL, ref string) (string, bool) {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return "", false
+	}
+	if strings.HasPrefix(ref, "#") {
+		return ref, true
+	}
+	u, err := base.Parse(ref)
+	if err != nil {
+		return "", false
+	}
+	switch u.Scheme {
+	case "http", "https", "mailto", "tel":
+		return u.String(), true
+	case "data":
+		// Only images and fonts may stay inline; data:text/html could carry scripts
+		if inlinableType(strings.SplitN(strings.TrimPrefix(ref, "data:"), ";", 2)[0]) {
+			return ref, true
+		}
+	}
+	return "", false
+}
+
+// inlinableType reports whether a resource of contentType is safe to embed as a data URI
+func inlinableType(contentType string) bool {
+	contentType = strings.ToLower(strings.SplitN(contentType, ",", 2)[0])
+	switch {
+	case strings.HasPrefix(contentType, "image/"), strings.HasPrefix(contentType, "font/"):
+		return true
+	case strings.HasPrefix(contentType, "application/font-"), strings.HasPrefix(contentType, "application/x-font-"):
+		return true
+	case contentType == "application/vnd.ms-fontobject":
+		return true
+	}
+	return false
+}
+
+// addHead prepends the snapshot's charset, content security policy and source URL to <head>
+func (s *snapshot) addHead(doc *html.Node, pageURL string) {
+	head := findElement(doc, atom.Head)
+	if head == nil {
+		return
+	}
+	metas := []*html.Node{
+		{Type: html.ElementNode, Data: "meta", DataAtom: atom.Meta, Attr: []html.Attribute{{Key: "charset", Val: "utf-8"}}},
+		{Type: html.ElementNode, Data: "meta", DataAtom: atom.Meta, Attr: []html.Attribute{
+			{Key: "http-equiv", Val: "Content-Security-Policy"},
+			{Key: "content", Val: snapshotCSP},
+		}},
+		{Type: html.ElementNode, Data: "meta", DataAtom: atom.Meta, Attr: []html.Attribute{
+			{Key: "name", Val: "athena:source"},
+			{Key: "content", Val: pageURL},
+		}},
+	}
+	for i := len(metas) - 1; i >= 0; i-- {
+		head.InsertBefore(metas[i], head.FirstChild)
+	}
+}
+
+func findElement(n *html.Node, a atom.Atom) *html.Node {
+	if n.Type == html.ElementNode && n.DataAtom == a {
+		return n
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if found := findElement(c, a); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if strings.EqualFold(a.Key, key) {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+func setAttr(n *html.Node, key, val string) {
+	for i, a := range n.Attr {
+		if strings.EqualFold(a.Key, key) {
+			n.Attr[i].Val = val
+			return
+		}
+	}
+	n.Attr = append(n.Attr, html.Attribute{Key: key, Val: val})
+}
+
+func hasToken(list, token string) bool {
+	for _, t := range strings.Fields(list) {
+		if strings.EqualFold(t, token) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestWebArchiver_ArchivePage(t *testing.T) {
+	pixel := []byte("\x89PNG\r\n\x1a\n0000")
+	mux := http.NewServeMux()
+	mux.HandleFunc("/post", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write([]byte(`<!DOCTYPE html>
+		<html>
+		<head>
+			<title>Post</title>
+			<meta http-equiv="refresh" content="0; url=https://evil.example">
+			<link rel="stylesheet" href="/static/site.css">
+			<link rel="preload" href="/static/app.js">
+			<script>alert(1)</script>
+		</head>
+		<body onload="steal()">
+			<!-- tracking -->
+			<h1 style="background: url('/img/pixel.png')">Hello</h1>
+			<img src="/img/pixel.png" srcset="/img/big.png 2x" alt="pixel">
+			<img data-src="img/pixel.png" src="data:image/gif;base64,R0lGOD" alt="lazy">
+			<a href="javascript:alert(1)">bad</a>
+			<a href="/next">next</a>
+			<iframe src="https://ads.example"></iframe>
+			<form action="https://evil.example/collect"><input name="q"></form>
+		</body>
+		</html>`))
+	})
+	mux.HandleFunc("/static/site.css", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/css")
+		w.Write([]byte(`@import "theme.css"; body { background: url(../img/pixel.png) }`))
+	})
+	mux.HandleFunc("/static/theme.css", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/css")
+		w.Write([]byte(`h1 { color: red }`))
+	})
+	mux.HandleFunc("/img/pixel.png", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		w.Write(pixel)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	page, err := NewWebArchiver(nil).ArchivePage(context.Background(), server.URL+"/post")
+	if err != nil {
+		t.Fatalf("ArchivePage() unexpected error = %v", err)
+	}
+	snapshot := string(page)
+
+	for _, unwanted := range []string{"<script", "alert(1)", "onload", "refresh", "tracking", "<iframe", "preload", "srcset", "evil.example", "data-src", "/img/pixel.png"} {
+		if strings.Contains(snapshot, unwanted) {
+			t.Errorf("snapshot should not contain %q:\n%s", unwanted, snapshot)
+		}
+	}
+	dataURI := "data:image/png;base64,"
+	for _, wanted := range []string{
+		`<meta charset="utf-8"/>`,
+		`http-equiv="Content-Security-Policy"`,
+		`<meta name="athena:source" content="` + server.URL + `/post"/>`,
+		`h1 { color: red }`,
+		`body { background: url(` + dataURI,
+		`style="background: url(` + dataURI,
+		`<img src="` + dataURI,
+		`<a href="` + server.URL + `/next">`,
+	} {
+		if !strings.Contains(snapshot, wanted) {
+			t.Errorf("snapshot should contain %q:\n%s", wanted, snapshot)
+		}
+	}
+	if strings.Count(snapshot, `alt="lazy"`) != 1 || strings.Contains(snapshot, "R0lGOD") {
+		t.Errorf("lazy image should use data-src:\n%s", snapshot)
+	}
+}
+
+func TestWebArchiver_ArchivePage_Errors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/file.pdf" {
+			w.Header().Set("Content-Type", "application/pdf")
+			w.Write([]byte("%PDF-1.7"))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	archiver := NewWebArchiver(nil)
+	for _, url := range []string{"", server.URL + "/missing", server.URL + "/file.pdf"} {
+		if _, err := archiver.ArchivePage(context.Background(), url); err == nil {
+			t.Errorf("ArchivePage(%q) should return an error", url)
+		}
+	}
+}
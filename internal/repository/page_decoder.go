@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"bufio"
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/net/html/charset"
+)
+
+// maxPageBytes bounds how much of a page is read for metadata. Titles and meta tags sit near
+// the top, so longer pages are cut rather than rejected.
+const maxPageBytes = 5 << 20
+
+// sniffLen is how many bytes content sniffing looks at, as in http.DetectContentType
+const sniffLen = 512
+
+// newPageRequest creates a GET request that prefers HTML and accepts the compressions
+// decodePage understands
+func newPageRequest(ctx context.Context, rawURL string) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "text/html,application/xhtml+xml;q=0.9,*/*;q=0.8")
+	// Setting Accept-Encoding turns off the transport's own gzip handling, so deflate can be
+	// accepted as well
+	req.Header.Set("Accept-Encoding", "gzip, deflate")
+	return req, nil
+}
+
+// decodedPage is a response body with its compression removed and its media type known
+type decodedPage struct {
+	url         *url.URL // After redirects
+	contentType string   // Content-Type header as sent
+	mediaType   string   // From the header, or sniffed when it is missing or generic
+	body        *bufio.Reader
+}
+
+// decodePage decompresses resp's body and works out its media type. The caller still closes
+// resp.Body.
+func decodePage(resp *http.Response) (*decodedPage, error) {
+	var body io.Reader = resp.Body
+	switch encoding := strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding"))); encoding {
+	case "", "identity":
+	case "gzip", "x-gzip":
+		gz, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("invalid gzip body: %w", err)
+		}
+		body = gz
+	case "deflate":
+		// Servers disagree on whether deflate means zlib wrapped or raw
+		buffered := bufio.NewReader(resp.Body)
+		header, _ := buffered.Peek(2)
+		if len(header) == 2 && header[0]&0x0f == 8 && (uint16(header[0])<<8|uint16(header[1]))%31 == 0 {
+			zr, err := zlib.NewReader(buffered)
+			if err != nil {
+				return nil, fmt.Errorf("invalid deflate body: %w", err)
+			}
+			body = zr
+		} else {
+			body = flate.NewReader(buffered)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported content encoding %q", encoding)
+	}
+
+	p := &decodedPage{
+		url:         resp.Request.URL,
+		contentType: resp.Header.Get("Content-Type"),
+		body:        bufio.NewReaderSize(body, sniffLen),
+	}
+	p.mediaType, _, _ = mime.ParseMediaType(p.contentType)
+	switch p.mediaType {
+	case "", "application/octet-stream", "binary/octet-stream", "application/unknown":
+		head, _ := p.body.Peek(sniffLen)
+		p.mediaType, _, _ = mime.ParseMediaType(http.DetectContentType(head))
+	}
+	return p, nil
+}
+
+// isHTML reports whether the page should be parsed as HTML. Plain text is included since
+// servers often label HTML that way, and parsing real plain text as HTML finds no markup.
+func (p *decodedPage) isHTML() bool {
+	switch p.mediaType {
+	case "text/html", "application/xhtml+xml", "text/plain":
+		return true
+	}
+	return false
+}
+
+// isImage reports whether the page is an image
+func (p *decodedPage) isImage() bool {
+	return strings.HasPrefix(p.mediaType, "image/")
+}
+
+// read returns at most limit bytes of the body and whether there was more
+func (p *decodedPage) read(limit int64) ([]byte, bool, error) {
+	body, err := io.ReadAll(io.LimitReader(p.body, limit+1))
+	if err != nil {
+		return nil, false, err
+	}
+	if int64(len(body)) > limit {
+		return body[:limit], true, nil
+	}
+	return body, false, nil
+}
+
+// decodeText transcodes body to UTF-8 using the charset it declares
+func decodeText(body []byte, contentType string) string {
+	enc, name, certain := charset.DetermineEncoding(body, contentType)
+	if !certain && name == "windows-1252" && utf8.Valid(body) {
+		// The guess only looks at the first 1024 bytes; UTF-8 is far more likely today
+		return strings.TrimPrefix(string(body), "\ufeff")
+	}
+	decoded, err := enc.NewDecoder().Bytes(body)
+	if err != nil {
+		decoded = bytes.ToValidUTF8(body, []byte("\ufffd"))
+	}
+	return strings.TrimPrefix(string(decoded), "\ufeff")
+}
+
+// fileTitle names a non-HTML resource after the last segment of its URL path, or returns the
+// URL when there is none
+func fileTitle(u *url.URL) string {
+	name := path.Base(u.Path)
+	if name == "." || name == "/" {
+		return u.String()
+	}
+	return name
+}
+
+// truncateText cuts s to at most n bytes without splitting a UTF-8 sequence
+func truncateText(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
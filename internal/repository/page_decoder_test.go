@@ -0,0 +1,208 @@
+package repository
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestDecodeText(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        []byte
+		contentType string
+		want        string
+	}{
+		{
+			name:        "Shift_JIS from header",
+			body:        []byte{0x93, 0xfa, 0x96, 0x7b, 0x8c, 0xea},
+			contentType: "text/html; charset=Shift_JIS",
+			want:        "日本語",
+		},
+		{
+			name:        "Windows-1251 from meta charset",
+			body:        append([]byte(`<meta charset="windows-1251"><title>`), 0xcf, 0xf0, 0xe8, 0xe2, 0xe5, 0xf2),
+			contentType: "text/html",
+			want:        `<meta charset="windows-1251"><title>Привет`,
+		},
+		{
+			name:        "GBK from http-equiv",
+			body:        append([]byte(`<meta http-equiv="Content-Type" content="text/html; charset=gbk">`), 0xd6, 0xd0, 0xce, 0xc4),
+			contentType: "text/html",
+			want:        `<meta http-equiv="Content-Type" content="text/html; charset=gbk">中文`,
+		},
+		{
+			name:        "UTF-8 byte order mark wins over header",
+			body:        append([]byte{0xef, 0xbb, 0xbf}, "héllo"...),
+			contentType: "text/html; charset=iso-8859-1",
+			want:        "héllo",
+		},
+		{
+			name:        "UTF-16 byte order mark",
+			body:        []byte{0xff, 0xfe, 'h', 0, 'i', 0},
+			contentType: "text/html",
+			want:        "hi",
+		},
+		{
+			name:        "undeclared UTF-8 after the first kilobyte",
+			body:        []byte(strings.Repeat(" ", 2000) + "café"),
+			contentType: "text/html",
+			want:        strings.Repeat(" ", 2000) + "café",
+		},
+		{
+			name:        "undeclared Latin-1",
+			body:        []byte{'c', 'a', 'f', 0xe9},
+			contentType: "text/html",
+			want:        "café",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := decodeText(tt.body, tt.contentType); got != tt.want {
+				t.Errorf("decodeText() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func compress(t *testing.T, encoding string, data []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	var w io.WriteCloser
+	switch encoding {
+	case "gzip":
+		w = gzip.NewWriter(&buf)
+	case "deflate":
+		w = zlib.NewWriter(&buf)
+	case "raw-deflate":
+		w, _ = flate.NewWriter(&buf, flate.DefaultCompression)
+	}
+	w.Write(data)
+	w.Close()
+	return buf.Bytes()
+}
+
+func TestDecodePage(t *testing.T) {
+	page := []byte("<html><title>Compressed</title></html>")
+	tests := []struct {
+		name            string
+		contentEncoding string
+		body            []byte
+	}{
+		{"identity", "", page},
+		{"gzip", "gzip", compress(t, "gzip", page)},
+		{"zlib deflate", "deflate", compress(t, "deflate", page)},
+		{"raw deflate", "deflate", compress(t, "raw-deflate", page)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if got := r.Header.Get("Accept-Encoding"); got != "gzip, deflate" {
+					t.Errorf("Accept-Encoding = %q, want %q", got, "gzip, deflate")
+				}
+				w.Header().Set("Content-Type", "text/html")
+				if tt.contentEncoding != "" {
+					w.Header().Set("Content-Encoding", tt.contentEncoding)
+				}
+				w.Write(tt.body)
+			}))
+			defer server.Close()
+
+			req, _ := newPageRequest(t.Context(), server.URL)
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("Do() error = %v", err)
+			}
+			defer resp.Body.Close()
+
+			decoded, err := decodePage(resp)
+			if err != nil {
+				t.Fatalf("decodePage() error = %v", err)
+			}
+			body, truncated, err := decoded.read(maxPageBytes)
+			if err != nil || truncated || string(body) != string(page) {
+				t.Errorf("read() = %q, %v, %v, want %q", body, truncated, err, page)
+			}
+		})
+	}
+}
+
+func TestDecodePage_MediaType(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		want        string
+		wantHTML    bool
+	}{
+		{"declared HTML", "text/html; charset=utf-8", "hello", "text/html", true},
+		{"declared PDF", "application/pdf", "%PDF-1.7", "application/pdf", false},
+		{"sniffed HTML", "", "<!DOCTYPE html><title>x</title>", "text/html", true},
+		{"sniffed PDF behind octet-stream", "application/octet-stream", "%PDF-1.7\n", "application/pdf", false},
+		{"sniffed PNG", "", "\x89PNG\r\n\x1a\n", "image/png", false},
+		{"JSON", "application/json", `{"title": "x"}`, "application/json", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{
+				Header:  http.Header{"Content-Type": {tt.contentType}},
+				Body:    io.NopCloser(strings.NewReader(tt.body)),
+				Request: &http.Request{URL: &url.URL{Scheme: "https", Host: "example.com"}},
+			}
+			page, err := decodePage(resp)
+			if err != nil {
+				t.Fatalf("decodePage() error = %v", err)
+			}
+			if page.mediaType != tt.want || page.isHTML() != tt.wantHTML {
+				t.Errorf("decodePage() media type = %q, isHTML = %v, want %q, %v", page.mediaType, page.isHTML(), tt.want, tt.wantHTML)
+			}
+		})
+	}
+}
+
+func TestDecodedPage_Read_Truncates(t *testing.T) {
+	resp := &http.Response{
+		Header:  http.Header{"Content-Type": {"text/plain"}},
+		Body:    io.NopCloser(strings.NewReader("0123456789")),
+		Request: &http.Request{URL: &url.URL{Scheme: "https", Host: "example.com"}},
+	}
+	page, _ := decodePage(resp)
+	body, truncated, err := page.read(4)
+	if err != nil || !truncated || string(body) != "0123" {
+		t.Errorf("read(4) = %q, %v, %v, want %q, true, nil", body, truncated, err, "0123")
+	}
+}
+
+func TestFileTitle(t *testing.T) {
+	tests := []struct {
+		rawURL string
+		want   string
+	}{
+		{"https://example.com/papers/report%20v2.pdf", "report v2.pdf"},
+		{"https://example.com/images/cat.png?size=large", "cat.png"},
+		{"https://example.com/", "https://example.com/"},
+		{"https://example.com", "https://example.com"},
+	}
+	for _, tt := range tests {
+		u, _ := url.Parse(tt.rawURL)
+		if got := fileTitle(u); got != tt.want {
+			t.Errorf("fileTitle(%q) = %q, want %q", tt.rawURL, got, tt.want)
+		}
+	}
+}
+
+func TestTruncateText(t *testing.T) {
+	if got := truncateText("héllo", 2); got != "h" {
+		t.Errorf("truncateText() = %q, want %q without splitting é", got, "h")
+	}
+	if got := truncateText("hello", 10); got != "hello" {
+		t.Errorf("truncateText() = %q, want %q", got, "hello")
+	}
+}
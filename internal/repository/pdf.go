@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/ledongthuc/pdf"
+)
+
+// maxDocumentBytes bounds the size of a PDF that is parsed. Unlike HTML, a cut PDF cannot be
+// read, so larger documents are only titled after their file name.
+const maxDocumentBytes = 20 << 20
+
+// maxPDFTextBytes is how much page text is extracted from a PDF; summaries only use the start
+const maxPDFTextBytes = 16 << 10
+
+// pdfDocument is what is read from a PDF file
+type pdfDocument struct {
+	title     string
+	author    string
+	pageCount int
+	text      string // Text of the first pages, up to maxPDFTextBytes
+}
+
+// parsePDF reads the document information and the leading page text of a PDF
+func parsePDF(data []byte) (doc pdfDocument, err error) {
+	// The reader panics on malformed files
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("malformed PDF: %v", r)
+		}
+	}()
+
+	reader, err := pdf.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return pdfDocument{}, fmt.Errorf("failed to open PDF: %w", err)
+	}
+	info := reader.Trailer().Key("Info")
+	doc.title = strings.TrimSpace(info.Key("Title").Text())
+	doc.author = strings.TrimSpace(info.Key("Author").Text())
+	doc.pageCount = reader.NumPage()
+
+	var text strings.Builder
+	fonts := make(map[string]*pdf.Font)
+	for i := 1; i <= doc.pageCount && text.Len() < maxPDFTextBytes; i++ {
+		page := reader.Page(i)
+		if page.V.IsNull() {
+			continue
+		}
+		for _, name := range page.Fonts() {
+			if _, ok := fonts[name]; !ok {
+				font := page.Font(name)
+				fonts[name] = &font
+			}
+		}
+		pageText, err := page.GetPlainText(fonts)
+		if err != nil {
+			// Keep the metadata and whatever text came before the bad page
+			break
+		}
+		text.WriteString(strings.Join(strings.Fields(pageText), " "))
+		text.WriteString(" ")
+	}
+	doc.text = truncateText(strings.TrimSpace(text.String()), maxPDFTextBytes)
+	return doc, nil
+}
@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// buildPDF writes a minimal PDF with one line of Helvetica text per page
+func buildPDF(title, author string, pages ...string) []byte {
+	var objects []string
+	objects = append(objects, "<< /Type /Catalog /Pages 2 0 R >>")
+	kids := make([]string, len(pages))
+	for i := range pages {
+		kids[i] = fmt.Sprintf("%d 0 R", 4+2*i)
+	}
+	objects = append(objects, fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages)))
+	objects = append(objects, "<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>")
+	for i, text := range pages {
+		content := fmt.Sprintf("BT /F1 12 Tf 72 720 Td (%s) Tj ET", text)
+		objects = append(objects,
+			fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] /Resources << /Font << /F1 3 0 R >> >> /Contents %d 0 R >>", 5+2*i),
+			fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(content), content))
+	}
+	info := len(objects) + 1
+	objects = append(objects, fmt.Sprintf("<< /Title (%s) /Author (%s) >>", title, author))
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, object := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, object)
+	}
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R /Info %d 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, info, xref)
+	return buf.Bytes()
+}
+
+func TestParsePDF(t *testing.T) {
+	data := buildPDF("Attention Is All You Need", "Vaswani et al.", "The dominant sequence transduction models", "We propose the Transformer")
+
+	doc, err := parsePDF(data)
+	if err != nil {
+		t.Fatalf("parsePDF() error = %v", err)
+	}
+	if doc.title != "Attention Is All You Need" {
+		t.Errorf("parsePDF() title = %q, want %q", doc.title, "Attention Is All You Need")
+	}
+	if doc.author != "Vaswani et al." {
+		t.Errorf("parsePDF() author = %q, want %q", doc.author, "Vaswani et al.")
+	}
+	if doc.pageCount != 2 {
+		t.Errorf("parsePDF() pageCount = %d, want 2", doc.pageCount)
+	}
+	if !strings.Contains(doc.text, "sequence transduction") || !strings.Contains(doc.text, "Transformer") {
+		t.Errorf("parsePDF() text = %q, want the text of both pages", doc.text)
+	}
+}
+
+func TestParsePDF_Malformed(t *testing.T) {
+	tests := map[string][]byte{
+		"not a PDF":   []byte("<html></html>"),
+		"truncated":   buildPDF("Title", "Author", "Text")[:200],
+		"empty":       {},
+		"header only": []byte("%PDF-1.4\n"),
+	}
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := parsePDF(data); err == nil {
+				t.Error("parsePDF() should fail on a malformed file")
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package repository
+
+import "strings"
+
+// robotsRule is one Allow or Disallow line of a robots.txt group
+type robotsRule struct {
+	pattern string
+	allow   bool
+}
+
+// robotsRules are the rules of the robots.txt group that applies to our user agent
+type robotsRules struct {
+	rules []robotsRule
+}
+
+func disallowAll() *robotsRules {
+	return &robotsRules{rules: []robotsRule{{pattern: "/", allow: false}}}
+}
+
+// parseRobots extracts the rules that apply to userAgent from a robots.txt file. The group
+// naming our product token wins over the "*" group, as described in RFC 9309.
+func parseRobots(content, userAgent string) *robotsRules {
+	product := strings.ToLower(strings.SplitN(userAgent, "/", 2)[0])
+
+	var specific, wildcard []robotsRule
+	var agents []string
+	inRules := false // Whether the current group has started listing rules
+	for _, line := range strings.Split(content, "\n") {
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		switch key {
+		case "user-agent":
+			if inRules {
+				agents = nil
+				inRules = false
+			}
+			agents = append(agents, strings.ToLower(value))
+		case "allow", "disallow":
+			inRules = true
+			if value == "" {
+				// An empty Disallow allows everything and an empty Allow means nothing
+				continue
+			}
+			rule := robotsRule{pattern: value, allow: key == "allow"}
+			for _, agent := range agents {
+				switch {
+				case agent == "*":
+					wildcard = append(wildcard, rule)
+				case agent != "" && strings.HasPrefix(product, agent):
+					specific = append(specific, rule)
+				}
+			}
+		}
+	}
+
+	if specific != nil {
+		return &robotsRules{rules: specific}
+	}
+	return &robotsRules{rules: wildcard}
+}
+
+// allowed reports whether path, including any query, may be fetched. The longest matching
+// pattern decides and Allow wins a tie.
+func (r *robotsRules) allowed(path string) bool {
+	if path == "" {
+		path = "/"
+	}
+	if path == "/robots.txt" {
+		return true
+	}
+	best, allow := -1, true
+	for _, rule := range r.rules {
+		if !matchRobotsPattern(rule.pattern, path) {
+			continue
+		}
+		if len(rule.pattern) > best || (len(rule.pattern) == best && rule.allow) {
+			best, allow = len(rule.pattern), rule.allow
+		}
+	}
+	return allow
+}
+
+// matchRobotsPattern matches a path prefix pattern where * is any sequence of characters and
+// a trailing $ anchors the end of the path
+func matchRobotsPattern(pattern, path string) bool {
+	anchored := strings.HasSuffix(pattern, "$")
+	parts := strings.Split(strings.TrimSuffix(pattern, "$"), "*")
+	if !strings.HasPrefix(path, parts[0]) {
+		return false
+	}
+	rest := path[len(parts[0]):]
+	if len(parts) == 1 {
+		return !anchored || rest == ""
+	}
+
+	// Matching each middle part as early as possible leaves the most room for the rest
+	middle, last := parts[1:len(parts)-1], parts[len(parts)-1]
+	for _, part := range middle {
+		idx := strings.Index(rest, part)
+		if idx < 0 {
+			return false
+		}
+		rest = rest[idx+len(part):]
+	}
+	if anchored {
+		return strings.HasSuffix(rest, last)
+	}
+	return strings.Contains(rest, last)
+}
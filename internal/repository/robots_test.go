@@ -0,0 +1,61 @@
+package repository
+
+import "testing"
+
+func TestParseRobots(t *testing.T) {
+	content := `# Example robots.txt
+User-agent: *
+Disallow: /private
+Allow: /private/open
+Disallow: /*.pdf$
+
+User-agent: OtherBot
+Disallow: /
+
+User-agent: AthenaBot
+User-agent: SomeBot
+Disallow: /no-athena
+Disallow: /search*q=
+Allow: /no-athena/ok
+`
+	tests := []struct {
+		name      string
+		userAgent string
+		path      string
+		want      bool
+	}{
+		{"wildcard group allows unmatched path", "crawler/1.0", "/page", true},
+		{"wildcard group disallows prefix", "crawler/1.0", "/private/page", false},
+		{"longer Allow wins", "crawler/1.0", "/private/open/page", true},
+		{"dollar anchors the end", "crawler/1.0", "/docs/file.pdf", false},
+		{"dollar does not match a longer path", "crawler/1.0", "/docs/file.pdf.html", true},
+		{"specific group replaces wildcard group", DefaultUserAgent, "/private/page", true},
+		{"specific group disallows", DefaultUserAgent, "/no-athena/page", false},
+		{"star matches any sequence", DefaultUserAgent, "/search?x=1&q=go", false},
+		{"star does not match a missing part", DefaultUserAgent, "/search?x=1", true},
+		{"specific Allow", DefaultUserAgent, "/no-athena/ok", true},
+		{"robots.txt is always allowed", "OtherBot/1.0", "/robots.txt", true},
+		{"disallow all", "OtherBot/1.0", "/", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseRobots(content, tt.userAgent).allowed(tt.path); got != tt.want {
+				t.Errorf("allowed(%q) for %q = %v, want %v", tt.path, tt.userAgent, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRobotsRules_AllowWinsTie(t *testing.T) {
+	rules := parseRobots("User-agent: *\nDisallow: /page\nAllow: /page\n", DefaultUserAgent)
+	if !rules.allowed("/page") {
+		t.Error("allowed() should prefer Allow when patterns are equally long")
+	}
+}
+
+func TestRobotsRules_EmptyDisallowAllowsAll(t *testing.T) {
+	rules := parseRobots("User-agent: *\nDisallow:\n", DefaultUserAgent)
+	if !rules.allowed("/anything") {
+		t.Error("allowed() should allow everything for an empty Disallow")
+	}
+}
@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/tsongpon/athena/internal/logger"
+	"github.com/tsongpon/athena/internal/model"
+	"go.uber.org/zap"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const shareLinksCollection = "share_links"
+
+// ShareLinkFirestoreRepository implements ShareLinkRepository interface using GCP Firestore.
+// Documents are keyed by slug so resolving a public link is a single read.
+type ShareLinkFirestoreRepository struct {
+	client *firestore.Client
+	ctx    context.Context
+}
+
+// NewShareLinkFirestoreRepository creates a new instance of ShareLinkFirestoreRepository
+func NewShareLinkFirestoreRepository(ctx context.Context, client *firestore.Client) *ShareLinkFirestoreRepository {
+	return &ShareLinkFirestoreRepository{
+		client: client,
+		ctx:    ctx,
+	}
+}
+
+// firestoreShareLink is the structure used to store/retrieve share links in Firestore
+type firestoreShareLink struct {
+	Slug         string    `firestore:"slug"`
+	UserID       string    `firestore:"user_id"`
+	ResourceType string    `firestore:"resource_type"`
+	ResourceID   string    `firestore:"resource_id"`
+	PasswordHash string    `firestore:"password_hash"`
+	ExpiresAt    time.Time `firestore:"expires_at"`
+	CreatedAt    time.Time `firestore:"created_at"`
+}
+
+func toFirestoreShareLink(link model.ShareLink) firestoreShareLink {
+	return firestoreShareLink{
+		Slug:         link.Slug,
+		UserID:       link.UserID,
+		ResourceType: link.ResourceType,
+		ResourceID:   link.ResourceID,
+		PasswordHash: link.PasswordHash,
+		ExpiresAt:    link.ExpiresAt,
+		CreatedAt:    link.CreatedAt,
+	}
+}
+
+func toModelShareLink(fsLink firestoreShareLink) model.ShareLink {
+	return model.ShareLink{
+		Slug:         fsLink.Slug,
+		UserID:       fsLink.UserID,
+		ResourceType: fsLink.ResourceType,
+		ResourceID:   fsLink.ResourceID,
+		PasswordHash: fsLink.PasswordHash,
+		ExpiresAt:    fsLink.ExpiresAt,
+		CreatedAt:    fsLink.CreatedAt,
+	}
+}
+
+// CreateShareLink stores a new share link in Firestore, failing if the slug is taken
+func (r *ShareLinkFirestoreRepository) CreateShareLink(link model.ShareLink) (model.ShareLink, error) {
+	if link.CreatedAt.IsZero() {
+		link.CreatedAt = time.Now()
+	}
+
+	_, err := r.client.Collection(shareLinksCollection).Doc(link.Slug).Create(r.ctx, toFirestoreShareLink(link))
+	if status.Code(err) == codes.AlreadyExists {
+		return model.ShareLink{}, fmt.Errorf("share link %s already exists: %w", link.Slug, model.ErrConflict)
+	}
+	if err != nil {
+		logger.Error("Failed to create share link in Firestore",
+			zap.String("user_id", link.UserID),
+			zap.Error(err))
+		return model.ShareLink{}, fmt.Errorf("failed to create share link: %w", err)
+	}
+
+	return link, nil
+}
+
+// GetShareLink retrieves a share link by its slug from Firestore
+func (r *ShareLinkFirestoreRepository) GetShareLink(slug string) (model.ShareLink, error) {
+	docSnap, err := r.client.Collection(shareLinksCollection).Doc(slug).Get(r.ctx)
+	if status.Code(err) == codes.NotFound {
+		return model.ShareLink{}, fmt.Errorf("share link %s %w", slug, model.ErrNotFound)
+	}
+	if err != nil {
+		logger.Error("Failed to get share link from Firestore", zap.Error(err))
+		return model.ShareLink{}, fmt.Errorf("failed to get share link: %w", err)
+	}
+
+	var fsLink firestoreShareLink
+	if err := docSnap.DataTo(&fsLink); err != nil {
+		return model.ShareLink{}, fmt.Errorf("failed to parse share link data: %w", err)
+	}
+
+	return toModelShareLink(fsLink), nil
+}
+
+// ListShareLinks retrieves the share links of a user from Firestore, newest first
+func (r *ShareLinkFirestoreRepository) ListShareLinks(userID string) ([]model.ShareLink, error) {
+	iter := r.client.Collection(shareLinksCollection).
+		Where("user_id", "==", userID).
+		OrderBy("created_at", firestore.Desc).
+		Documents(r.ctx)
+	defer iter.Stop()
+
+	links := []model.ShareLink{}
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			logger.Error("Failed to list share links from Firestore",
+				zap.String("user_id", userID),
+				zap.Error(err))
+			return nil, fmt.Errorf("failed to list share links: %w", err)
+		}
+
+		var fsLink firestoreShareLink
+		if err := doc.DataTo(&fsLink); err != nil {
+			return nil, fmt.Errorf("failed to parse share link data: %w", err)
+		}
+		links = append(links, toModelShareLink(fsLink))
+	}
+
+	return links, nil
+}
+
+// DeleteShareLink deletes a share link by its slug from Firestore
+func (r *ShareLinkFirestoreRepository) DeleteShareLink(slug string) error {
+	_, err := r.client.Collection(shareLinksCollection).Doc(slug).Delete(r.ctx, firestore.Exists)
+	if status.Code(err) == codes.NotFound {
+		return fmt.Errorf("share link %s %w", slug, model.ErrNotFound)
+	}
+	if err != nil {
+		logger.Error("Failed to delete share link from Firestore", zap.Error(err))
+		return fmt.Errorf("failed to delete share link: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/tsongpon/athena/internal/model"
+)
+
+// ShareLinkInMemRepository implements ShareLinkRepository interface using an in-memory map keyed by slug
+type ShareLinkInMemRepository struct {
+	links map[string]model.ShareLink
+	mutex sync.RWMutex
+}
+
+// NewShareLinkInMemRepository creates a new instance of ShareLinkInMemRepository
+func NewShareLinkInMemRepository() *ShareLinkInMemRepository {
+	return &ShareLinkInMemRepository{
+		links: make(map[string]model.ShareLink),
+		mutex: sync.RWMutex{},
+	}
+}
+
+// CreateShareLink stores a new share link under its slug
+func (r *ShareLinkInMemRepository) CreateShareLink(link model.ShareLink) (model.ShareLink, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, exists := r.links[link.Slug]; exists {
+		return model.ShareLink{}, fmt.Errorf("share link %s already exists: %w", link.Slug, model.ErrConflict)
+	}
+	if link.CreatedAt.IsZero() {
+		link.CreatedAt = time.Now()
+	}
+
+	r.links[link.Slug] = link
+
+	return link, nil
+}
+
+// GetShareLink retrieves a share link by its slug
+func (r *ShareLinkInMemRepository) GetShareLink(slug string) (model.ShareLink, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	link, exists := r.links[slug]
+	if !exists {
+		return model.ShareLink{}, fmt.Errorf("share link %s %w", slug, model.ErrNotFound)
+	}
+
+	return link, nil
+}
+
+// ListShareLinks retrieves the share links of a user, newest first
+func (r *ShareLinkInMemRepository) ListShareLinks(userID string) ([]model.ShareLink, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	links := []model.ShareLink{}
+	for _, link := range r.links {
+		if link.UserID == userID {
+			links = append(links, link)
+		}
+	}
+
+	sort.Slice(links, func(i, j int) bool {
+		return links[i].CreatedAt.After(links[j].CreatedAt)
+	})
+
+	return links, nil
+}
+
+// DeleteShareLink deletes a share link by its slug
+func (r *ShareLinkInMemRepository) DeleteShareLink(slug string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, exists := r.links[slug]; !exists {
+		return fmt.Errorf("share link %s %w", slug, model.ErrNotFound)
+	}
+
+	delete(r.links, slug)
+
+	return nil
+}
@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/tsongpon/athena/internal/model"
+)
+
+func TestShareLinkInMemRepository(t *testing.T) {
+	repo := NewShareLinkInMemRepository()
+	now := time.Now()
+
+	if _, err := repo.CreateShareLink(model.ShareLink{Slug: "older", UserID: "user-1", CreatedAt: now.Add(-time.Hour)}); err != nil {
+		t.Fatalf("CreateShareLink() unexpected error = %v", err)
+	}
+	repo.CreateShareLink(model.ShareLink{Slug: "newer", UserID: "user-1", CreatedAt: now})
+	repo.CreateShareLink(model.ShareLink{Slug: "other", UserID: "user-2"})
+
+	if _, err := repo.CreateShareLink(model.ShareLink{Slug: "older", UserID: "user-2"}); !errors.Is(err, model.ErrConflict) {
+		t.Errorf("CreateShareLink() with taken slug error = %v, want ErrConflict", err)
+	}
+
+	links, _ := repo.ListShareLinks("user-1")
+	if len(links) != 2 || links[0].Slug != "newer" || links[1].Slug != "older" {
+		t.Errorf("ListShareLinks() = %+v, want newer then older", links)
+	}
+
+	if err := repo.DeleteShareLink("older"); err != nil {
+		t.Fatalf("DeleteShareLink() unexpected error = %v", err)
+	}
+	if _, err := repo.GetShareLink("older"); !errors.Is(err, model.ErrNotFound) {
+		t.Errorf("GetShareLink() after delete error = %v, want ErrNotFound", err)
+	}
+	if err := repo.DeleteShareLink("older"); !errors.Is(err, model.ErrNotFound) {
+		t.Errorf("DeleteShareLink() twice error = %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	_ "image/gif" // Registers GIF with image.Decode
+	"image/jpeg"
+	"image/png"
+	"net/http"
+	"time"
+
+	"github.com/tsongpon/athena/internal/model"
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp" // Registers WebP with image.Decode
+)
+
+// Limits on the images that are thumbnailed. The pixel limit keeps a small, highly
+// compressed file from decoding into gigabytes of memory.
+const (
+	maxImageBytes  = 10 << 20
+	maxImagePixels = 40_000_000
+)
+
+// thumbnailJPEGQuality is the quality of JPEG thumbnails
+const thumbnailJPEGQuality = 85
+
+// WebThumbnailer implements Thumbnailer by downloading an image and scaling it down in Go.
+// Only raster formats are accepted; SVG is rejected since it can carry scripts.
+type WebThumbnailer struct {
+	httpClient *http.Client
+}
+
+// NewWebThumbnailer creates a thumbnailer whose requests go through fetcher. A nil fetcher
+// sends requests directly.
+func NewWebThumbnailer(fetcher *Fetcher) *WebThumbnailer {
+	client := &http.Client{
+		Timeout: 30 * time.Second,
+	}
+	if fetcher != nil {
+		client.Transport = fetcher
+	}
+	return &WebThumbnailer{
+		httpClient: client,
+	}
+}
+
+// CreateThumbnails downloads the image at imageURL once and returns it scaled to fit each of
+// the square boxes in sizes, in the same order. Images are never scaled up.
+func (t *WebThumbnailer) CreateThumbnails(ctx context.Context, imageURL string, sizes []int) ([]model.Blob, error) {
+	if imageURL == "" {
+		return nil, fmt.Errorf("URL cannot be empty")
+	}
+
+	img, err := t.fetchImage(ctx, imageURL)
+	if err != nil {
+		return nil, err
+	}
+
+	// JPEG is smaller, but only PNG keeps transparency
+	contentType := "image/jpeg"
+	if opaque, ok := img.(interface{ Opaque() bool }); ok && !opaque.Opaque() {
+		contentType = "image/png"
+	}
+
+	thumbnails := make([]model.Blob, 0, len(sizes))
+	for _, size := range sizes {
+		scaled := scaleToFit(img, size)
+		var buf bytes.Buffer
+		if contentType == "image/png" {
+			err = png.Encode(&buf, scaled)
+		} else {
+			err = jpeg.Encode(&buf, scaled, &jpeg.Options{Quality: thumbnailJPEGQuality})
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode thumbnail of %s: %w", imageURL, err)
+		}
+		thumbnails = append(thumbnails, model.Blob{
+			ContentType: contentType,
+			Data:        buf.Bytes(),
+		})
+	}
+	return thumbnails, nil
+}
+
+// fetchImage downloads and decodes the image at imageURL
+func (t *WebThumbnailer) fetchImage(ctx context.Context, imageURL string) (image.Image, error) {
+	req, err := newPageRequest(ctx, imageURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL %s: %w", imageURL, err)
+	}
+	req.Header.Set("Accept", "image/webp,image/png,image/jpeg,image/gif;q=0.9,*/*;q=0.5")
+	resp, err := t.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", imageURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch %s: status %d", imageURL, resp.StatusCode)
+	}
+	page, err := decodePage(resp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", imageURL, err)
+	}
+	if !page.isImage() {
+		return nil, fmt.Errorf("%s is not an image but %s", imageURL, page.mediaType)
+	}
+	body, truncated, err := page.read(maxImageBytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", imageURL, err)
+	}
+	if truncated {
+		return nil, fmt.Errorf("%s is larger than %d bytes", imageURL, maxImageBytes)
+	}
+
+	// The header is checked before decoding so oversized images are never allocated
+	config, _, err := image.DecodeConfig(bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("unsupported image %s: %w", imageURL, err)
+	}
+	if config.Width <= 0 || config.Height <= 0 || config.Width*config.Height > maxImagePixels {
+		return nil, fmt.Errorf("image %s has unsupported dimensions %dx%d", imageURL, config.Width, config.Height)
+	}
+	// Only the first frame of an animated GIF is decoded
+	img, _, err := image.Decode(bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image %s: %w", imageURL, err)
+	}
+	return img, nil
+}
+
+// scaleToFit returns img scaled down to fit a size x size box, keeping its aspect ratio
+func scaleToFit(img image.Image, size int) image.Image {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width > size || height > size {
+		if width >= height {
+			width, height = size, max(1, height*size/width)
+		} else {
+			width, height = max(1, width*size/height), size
+		}
+	}
+	dst := image.NewNRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, bounds, draw.Src, nil)
+	return dst
+}
@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func encodeTestPNG(t *testing.T, width, height int, fill color.Color) []byte {
+	t.Helper()
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := range height {
+		for x := range width {
+			img.Set(x, y, fill)
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("failed to encode test image: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestWebThumbnailer_CreateThumbnails(t *testing.T) {
+	opaque := encodeTestPNG(t, 800, 400, color.NRGBA{R: 200, A: 255})
+	transparent := encodeTestPNG(t, 100, 300, color.NRGBA{B: 200, A: 100})
+	mux := http.NewServeMux()
+	mux.HandleFunc("/wide.png", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		w.Write(opaque)
+	})
+	mux.HandleFunc("/tall.png", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		w.Write(transparent)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	tests := []struct {
+		path            string
+		wantContentType string
+		wantSizes       []image.Point
+	}{
+		// Scaled to fit each box, never up
+		{"/wide.png", "image/jpeg", []image.Point{{160, 80}, {480, 240}, {800, 400}}},
+		{"/tall.png", "image/png", []image.Point{{53, 160}, {100, 300}, {100, 300}}},
+	}
+	thumbnailer := NewWebThumbnailer(nil)
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			thumbnails, err := thumbnailer.CreateThumbnails(context.Background(), server.URL+tt.path, []int{160, 480, 1200})
+			if err != nil {
+				t.Fatalf("CreateThumbnails() error = %v", err)
+			}
+			if len(thumbnails) != len(tt.wantSizes) {
+				t.Fatalf("CreateThumbnails() returned %d thumbnails, want %d", len(thumbnails), len(tt.wantSizes))
+			}
+			for i, thumbnail := range thumbnails {
+				if thumbnail.ContentType != tt.wantContentType {
+					t.Errorf("thumbnail %d content type = %q, want %q", i, thumbnail.ContentType, tt.wantContentType)
+				}
+				config, format, err := image.DecodeConfig(bytes.NewReader(thumbnail.Data))
+				if err != nil {
+					t.Fatalf("thumbnail %d is not an image: %v", i, err)
+				}
+				if "image/"+format != tt.wantContentType {
+					t.Errorf("thumbnail %d is encoded as %s, want %s", i, format, tt.wantContentType)
+				}
+				if got := (image.Point{config.Width, config.Height}); got != tt.wantSizes[i] {
+					t.Errorf("thumbnail %d size = %v, want %v", i, got, tt.wantSizes[i])
+				}
+			}
+		})
+	}
+}
+
+func TestWebThumbnailer_CreateThumbnails_Rejected(t *testing.T) {
+	var huge bytes.Buffer
+	// A uniform JPEG compresses well, but decoding it would take far too much memory
+	if err := jpeg.Encode(&huge, image.NewGray(image.Rect(0, 0, 8000, 6000)), nil); err != nil {
+		t.Fatalf("failed to encode test image: %v", err)
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/page.html", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte("<html><body>not an image</body></html>"))
+	})
+	mux.HandleFunc("/icon.svg", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/svg+xml")
+		w.Write([]byte(`<svg xmlns="http://www.w3.org/2000/svg"><script>alert(1)</script></svg>`))
+	})
+	mux.HandleFunc("/corrupt.png", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		w.Write([]byte("\x89PNG\r\n\x1a\nnot really"))
+	})
+	mux.HandleFunc("/huge.jpg", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/jpeg")
+		w.Write(huge.Bytes())
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	thumbnailer := NewWebThumbnailer(nil)
+	for _, path := range []string{"/page.html", "/icon.svg", "/corrupt.png", "/huge.jpg", "/missing.png"} {
+		t.Run(path, func(t *testing.T) {
+			if _, err := thumbnailer.CreateThumbnails(context.Background(), server.URL+path, []int{160}); err == nil {
+				t.Errorf("CreateThumbnails(%s) should fail", path)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"cloud.google.com/go/firestore"
+	"github.com/tsongpon/athena/internal/logger"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const usageCollection = "usage"
+
+// UsageFirestoreRepository implements UsageRepository interface using GCP Firestore
+type UsageFirestoreRepository struct {
+	client *firestore.Client
+	ctx    context.Context
+}
+
+// NewUsageFirestoreRepository creates a new instance of UsageFirestoreRepository
+func NewUsageFirestoreRepository(ctx context.Context, client *firestore.Client) *UsageFirestoreRepository {
+	return &UsageFirestoreRepository{
+		client: client,
+		ctx:    ctx,
+	}
+}
+
+// firestoreUsage is the structure used to store/retrieve usage counters in Firestore
+type firestoreUsage struct {
+	UserID string `firestore:"user_id"`
+	Metric string `firestore:"metric"`
+	Period string `firestore:"period"`
+	Count  int    `firestore:"count"`
+}
+
+// IncrementUsage atomically adds one to the counter for metric in period and returns the new value
+func (r *UsageFirestoreRepository) IncrementUsage(userID, metric, period string) (int, error) {
+	docRef := r.client.Collection(usageCollection).Doc(usageKey(userID, metric, period))
+
+	var count int
+	err := r.client.RunTransaction(r.ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		usage := firestoreUsage{UserID: userID, Metric: metric, Period: period}
+		docSnap, err := tx.Get(docRef)
+		if err != nil && status.Code(err) != codes.NotFound {
+			return err
+		}
+		if err == nil {
+			if err := docSnap.DataTo(&usage); err != nil {
+				return err
+			}
+		}
+		usage.Count++
+		count = usage.Count
+		return tx.Set(docRef, usage)
+	})
+	if err != nil {
+		logger.Error("Failed to increment usage in Firestore",
+			zap.String("user_id", userID),
+			zap.String("metric", metric),
+			zap.String("period", period),
+			zap.Error(err))
+		return 0, fmt.Errorf("failed to increment usage: %w", err)
+	}
+
+	return count, nil
+}
+
+// GetUsage returns the counter for metric in period, zero if nothing was recorded
+func (r *UsageFirestoreRepository) GetUsage(userID, metric, period string) (int, error) {
+	docSnap, err := r.client.Collection(usageCollection).Doc(usageKey(userID, metric, period)).Get(r.ctx)
+	if status.Code(err) == codes.NotFound {
+		return 0, nil
+	}
+	if err != nil {
+		logger.Error("Failed to get usage from Firestore",
+			zap.String("user_id", userID),
+			zap.String("metric", metric),
+			zap.String("period", period),
+			zap.Error(err))
+		return 0, fmt.Errorf("failed to get usage: %w", err)
+	}
+
+	var usage firestoreUsage
+	if err := docSnap.DataTo(&usage); err != nil {
+		return 0, fmt.Errorf("failed to parse usage data: %w", err)
+	}
+
+	return usage.Count, nil
+}
@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"sync"
+)
+
+// UsageInMemRepository implements UsageRepository interface using an in-memory map
+type UsageInMemRepository struct {
+	counters map[string]int
+	mutex    sync.Mutex
+}
+
+// NewUsageInMemRepository creates a new instance of UsageInMemRepository
+func NewUsageInMemRepository() *UsageInMemRepository {
+	return &UsageInMemRepository{
+		counters: make(map[string]int),
+		mutex:    sync.Mutex{},
+	}
+}
+
+// IncrementUsage adds one to the counter for metric in period and returns the new value
+func (r *UsageInMemRepository) IncrementUsage(userID, metric, period string) (int, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	key := usageKey(userID, metric, period)
+	r.counters[key]++
+
+	return r.counters[key], nil
+}
+
+// GetUsage returns the counter for metric in period, zero if nothing was recorded
+func (r *UsageInMemRepository) GetUsage(userID, metric, period string) (int, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return r.counters[usageKey(userID, metric, period)], nil
+}
+
+// usageKey builds the identifier of a usage counter, also used as Firestore document ID
+func usageKey(userID, metric, period string) string {
+	return userID + "_" + metric + "_" + period
+}
@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestUsageInMemRepository_IncrementUsage(t *testing.T) {
+	repo := NewUsageInMemRepository()
+
+	for i := 1; i <= 3; i++ {
+		count, err := repo.IncrementUsage("user-1", "summaries", "2025-11")
+		if err != nil {
+			t.Fatalf("IncrementUsage() unexpected error = %v", err)
+		}
+		if count != i {
+			t.Errorf("IncrementUsage() = %d, want %d", count, i)
+		}
+	}
+
+	count, _ := repo.GetUsage("user-1", "summaries", "2025-11")
+	if count != 3 {
+		t.Errorf("GetUsage() = %d, want 3", count)
+	}
+
+	// Counters are scoped by user, metric and period
+	for _, key := range [][3]string{
+		{"user-2", "summaries", "2025-11"},
+		{"user-1", "imports", "2025-11"},
+		{"user-1", "summaries", "2025-12"},
+	} {
+		count, _ := repo.GetUsage(key[0], key[1], key[2])
+		if count != 0 {
+			t.Errorf("GetUsage(%v) = %d, want 0", key, count)
+		}
+	}
+}
+
+func TestUsageInMemRepository_ConcurrentIncrement(t *testing.T) {
+	repo := NewUsageInMemRepository()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Go(func() {
+			repo.IncrementUsage("user-1", "imports", "2025-11-02")
+		})
+	}
+	wg.Wait()
+
+	count, _ := repo.GetUsage("user-1", "imports", "2025-11-02")
+	if count != 50 {
+		t.Errorf("GetUsage() after concurrent increments = %d, want 50", count)
+	}
+}
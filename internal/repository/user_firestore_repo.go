@@ -11,6 +11,8 @@ import (
 	"github.com/tsongpon/athena/internal/model"
 	"go.uber.org/zap"
 	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 const usersCollection = "users"
@@ -31,38 +33,53 @@ func NewUserFirestoreRepository(ctx context.Context, client *firestore.Client) *
 
 // firestoreUser is the structure used to store/retrieve users in Firestore
 type firestoreUser struct {
-	ID        string    `firestore:"id"`
-	Name      string    `firestore:"name"`
-	Email     string    `firestore:"email"`
-	Password  string    `firestore:"password"`
-	Tier      string    `firestore:"tier"`
-	CreatedAt time.Time `firestore:"created_at"`
-	UpdatedAt time.Time `firestore:"updated_at"`
+	ID                  string    `firestore:"id"`
+	Name                string    `firestore:"name"`
+	Email               string    `firestore:"email"`
+	Password            string    `firestore:"password"`
+	Tier                string    `firestore:"tier"`
+	Role                string    `firestore:"role"`
+	Disabled            bool      `firestore:"disabled"`
+	TokensRevokedAt     time.Time `firestore:"tokens_revoked_at"`
+	AdminBootstrappedAt time.Time `firestore:"admin_bootstrapped_at,omitempty"`
+	BillingEventAt      time.Time `firestore:"billing_event_at,omitempty"`
+	CreatedAt           time.Time `firestore:"created_at"`
+	UpdatedAt           time.Time `firestore:"updated_at"`
 }
 
 // toFirestoreUser converts model.User to firestoreUser
 func toFirestoreUser(user model.User) firestoreUser {
 	return firestoreUser{
-		ID:        user.ID,
-		Name:      user.Name,
-		Email:     user.Email,
-		Password:  user.Password,
-		Tier:      user.Tier,
-		CreatedAt: user.CreatedAt,
-		UpdatedAt: user.UpdatedAt,
+		ID:                  user.ID,
+		Name:                user.Name,
+		Email:               user.Email,
+		Password:            user.Password,
+		Tier:                user.Tier,
+		Role:                user.Role,
+		Disabled:            user.Disabled,
+		TokensRevokedAt:     user.TokensRevokedAt,
+		AdminBootstrappedAt: user.AdminBootstrappedAt,
+		BillingEventAt:      user.BillingEventAt,
+		CreatedAt:           user.CreatedAt,
+		UpdatedAt:           user.UpdatedAt,
 	}
 }
 
 // toModelUser converts firestoreUser to model.User
 func toModelUser(fsUser firestoreUser) model.User {
 	return model.User{
-		ID:        fsUser.ID,
-		Name:      fsUser.Name,
-		Email:     fsUser.Email,
-		Password:  fsUser.Password,
-		Tier:      fsUser.Tier,
-		CreatedAt: fsUser.CreatedAt,
-		UpdatedAt: fsUser.UpdatedAt,
+		ID:                  fsUser.ID,
+		Name:                fsUser.Name,
+		Email:               fsUser.Email,
+		Password:            fsUser.Password,
+		Tier:                fsUser.Tier,
+		Role:                fsUser.Role,
+		Disabled:            fsUser.Disabled,
+		TokensRevokedAt:     fsUser.TokensRevokedAt,
+		AdminBootstrappedAt: fsUser.AdminBootstrappedAt,
+		BillingEventAt:      fsUser.BillingEventAt,
+		CreatedAt:           fsUser.CreatedAt,
+		UpdatedAt:           fsUser.UpdatedAt,
 	}
 }
 
@@ -104,6 +121,9 @@ func (r *UserFirestoreRepository) GetUserByID(id string) (model.User, error) {
 	logger.Debug("Getting user from Firestore", zap.String("id", id))
 
 	docSnap, err := r.client.Collection(usersCollection).Doc(id).Get(r.ctx)
+	if status.Code(err) == codes.NotFound {
+		return model.User{}, fmt.Errorf("user with ID %s %w", id, model.ErrNotFound)
+	}
 	if err != nil {
 		logger.Error("Failed to get user from Firestore",
 			zap.String("id", id),
@@ -186,3 +206,143 @@ func (r *UserFirestoreRepository) GetUserByEmailAndPassword(email, hashedPasswor
 
 	return toModelUser(fsUser), nil
 }
+
+// UpdateUser updates an existing user in Firestore
+func (r *UserFirestoreRepository) UpdateUser(user model.User) (model.User, error) {
+	// First, get the existing user to preserve CreatedAt
+	existing, err := r.GetUserByID(user.ID)
+	if err != nil {
+		return model.User{}, err
+	}
+
+	user.CreatedAt = existing.CreatedAt
+	user.UpdatedAt = time.Now()
+
+	_, err = r.client.Collection(usersCollection).Doc(user.ID).Set(r.ctx, toFirestoreUser(user))
+	if err != nil {
+		logger.Error("Failed to update user in Firestore",
+			zap.String("user_id", user.ID),
+			zap.Error(err))
+		return model.User{}, fmt.Errorf("failed to update user: %w", err)
+	}
+
+	logger.Debug("Updated user in Firestore", zap.String("id", user.ID))
+	return user, nil
+}
+
+// ModifyUser changes a user with change and stores the result in a transaction, which runs
+// again when the user is written in between
+func (r *UserFirestoreRepository) ModifyUser(id string, change func(u *model.User) error) (model.User, error) {
+	ref := r.client.Collection(usersCollection).Doc(id)
+	var user model.User
+	var changeErr error
+	err := r.client.RunTransaction(r.ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		docSnap, err := tx.Get(ref)
+		if err != nil {
+			return err
+		}
+		var fsUser firestoreUser
+		if err := docSnap.DataTo(&fsUser); err != nil {
+			return err
+		}
+		existing := toModelUser(fsUser)
+		user = existing
+		if changeErr = change(&user); changeErr != nil {
+			return changeErr
+		}
+
+		// Preserve identity and creation time
+		user.ID = id
+		user.CreatedAt = existing.CreatedAt
+		user.UpdatedAt = time.Now()
+		return tx.Set(ref, toFirestoreUser(user))
+	})
+	if changeErr != nil {
+		return model.User{}, changeErr
+	}
+	if status.Code(err) == codes.NotFound {
+		return model.User{}, fmt.Errorf("user with ID %s %w", id, model.ErrNotFound)
+	}
+	if err != nil {
+		logger.Error("Failed to modify user in Firestore",
+			zap.String("user_id", id),
+			zap.Error(err))
+		return model.User{}, fmt.Errorf("failed to modify user: %w", err)
+	}
+
+	logger.Debug("Modified user in Firestore", zap.String("id", id))
+	return user, nil
+}
+
+// userSearchQuery builds the Firestore query for an email prefix search ordered by email
+func (r *UserFirestoreRepository) userSearchQuery(query model.UserQuery) firestore.Query {
+	firestoreQuery := r.client.Collection(usersCollection).OrderBy("email", firestore.Asc)
+	if query.Search != "" {
+		// \uf8ff is the highest code point Firestore sorts, which turns the range into a prefix match
+		firestoreQuery = firestoreQuery.
+			Where("email", ">=", query.Search).
+			Where("email", "<", query.Search+"\uf8ff")
+	}
+	return firestoreQuery
+}
+
+// ListUsers retrieves users matching the query from Firestore ordered by email ascending
+// Supports pagination when Page and PageSize are greater than 0
+func (r *UserFirestoreRepository) ListUsers(query model.UserQuery) ([]model.User, error) {
+	firestoreQuery := r.userSearchQuery(query)
+
+	if query.Page > 0 && query.PageSize > 0 {
+		offset := (query.Page - 1) * query.PageSize
+		firestoreQuery = firestoreQuery.Limit(query.PageSize).Offset(offset)
+	}
+
+	iter := firestoreQuery.Documents(r.ctx)
+	defer iter.Stop()
+
+	var users []model.User
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			logger.Error("Failed to list users from Firestore",
+				zap.String("search", query.Search),
+				zap.Error(err))
+			return nil, fmt.Errorf("failed to list users: %w", err)
+		}
+
+		var fsUser firestoreUser
+		if err := doc.DataTo(&fsUser); err != nil {
+			logger.Error("Failed to parse user data from Firestore", zap.Error(err))
+			return nil, fmt.Errorf("failed to parse user data: %w", err)
+		}
+
+		users = append(users, toModelUser(fsUser))
+	}
+
+	return users, nil
+}
+
+// CountUsers returns the total count of users matching the query
+func (r *UserFirestoreRepository) CountUsers(query model.UserQuery) (int, error) {
+	iter := r.userSearchQuery(query).Documents(r.ctx)
+	defer iter.Stop()
+
+	count := 0
+	for {
+		_, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			logger.Error("Failed to count users from Firestore",
+				zap.String("search", query.Search),
+				zap.Error(err))
+			return 0, fmt.Errorf("failed to count users: %w", err)
+		}
+		count++
+	}
+
+	return count, nil
+}
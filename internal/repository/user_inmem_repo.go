@@ -2,6 +2,8 @@ package repository
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -62,7 +64,7 @@ func (r *UserInMemRepository) GetUserByID(id string) (model.User, error) {
 
 	user, exists := r.users[id]
 	if !exists {
-		return model.User{}, fmt.Errorf("user with ID %s not found", id)
+		return model.User{}, fmt.Errorf("user with ID %s %w", id, model.ErrNotFound)
 	}
 
 	return user, nil
@@ -95,3 +97,95 @@ func (r *UserInMemRepository) GetUserByEmailAndPassword(email, hashedPassword st
 
 	return model.User{}, fmt.Errorf("user not found with provided credentials")
 }
+
+// UpdateUser updates an existing user in the repository
+func (r *UserInMemRepository) UpdateUser(user model.User) (model.User, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	existing, exists := r.users[user.ID]
+	if !exists {
+		return model.User{}, fmt.Errorf("user with ID %s %w", user.ID, model.ErrNotFound)
+	}
+
+	// Preserve creation time and refresh update time
+	user.CreatedAt = existing.CreatedAt
+	user.UpdatedAt = time.Now()
+
+	r.users[user.ID] = user
+
+	return user, nil
+}
+
+// ModifyUser changes a user with change and stores the result under the lock
+func (r *UserInMemRepository) ModifyUser(id string, change func(u *model.User) error) (model.User, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	existing, exists := r.users[id]
+	if !exists {
+		return model.User{}, fmt.Errorf("user with ID %s %w", id, model.ErrNotFound)
+	}
+	user := existing
+	if err := change(&user); err != nil {
+		return model.User{}, err
+	}
+
+	// Preserve identity and creation time and refresh update time
+	user.ID = id
+	user.CreatedAt = existing.CreatedAt
+	user.UpdatedAt = time.Now()
+
+	r.users[id] = user
+
+	return user, nil
+}
+
+// ListUsers retrieves users matching the query ordered by email ascending
+// Supports pagination when Page and PageSize are greater than 0
+func (r *UserInMemRepository) ListUsers(query model.UserQuery) ([]model.User, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	var users []model.User
+	for _, user := range r.users {
+		if strings.HasPrefix(user.Email, query.Search) {
+			users = append(users, user)
+		}
+	}
+
+	sort.Slice(users, func(i, j int) bool {
+		return users[i].Email < users[j].Email
+	})
+
+	if query.Page > 0 && query.PageSize > 0 {
+		start := (query.Page - 1) * query.PageSize
+		end := start + query.PageSize
+
+		if start >= len(users) {
+			return []model.User{}, nil
+		}
+		if end > len(users) {
+			end = len(users)
+		}
+
+		return users[start:end], nil
+	}
+
+	return users, nil
+}
+
+// CountUsers returns the total count of users matching the query
+func (r *UserInMemRepository) CountUsers(query model.UserQuery) (int, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	count := 0
+	for _, user := range r.users {
+		if strings.HasPrefix(user.Email, query.Search) {
+			count++
+		}
+	}
+
+	return count, nil
+}
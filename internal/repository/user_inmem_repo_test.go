@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"errors"
 	"fmt"
 	"sync"
 	"testing"
@@ -741,3 +742,116 @@ func TestUserInMemRepository_CaseInsensitiveEmail(t *testing.T) {
 		// If implementation becomes case-insensitive, this would be the expected behavior
 	}
 }
+
+func TestUserInMemRepository_UpdateUser(t *testing.T) {
+	repo := NewUserInMemRepository()
+
+	created, _ := repo.CreateUser(model.User{Name: "John", Email: "john@example.com", Tier: model.TierFree})
+
+	created.Tier = model.TierPaid
+	created.Role = model.RoleAdmin
+	created.CreatedAt = time.Time{}
+	updated, err := repo.UpdateUser(created)
+	if err != nil {
+		t.Fatalf("UpdateUser() unexpected error = %v", err)
+	}
+	if updated.CreatedAt.IsZero() {
+		t.Error("UpdateUser() should preserve CreatedAt")
+	}
+
+	retrieved, _ := repo.GetUserByID(created.ID)
+	if retrieved.Tier != model.TierPaid {
+		t.Errorf("UpdateUser() Tier = %v, want %v", retrieved.Tier, model.TierPaid)
+	}
+	if retrieved.Role != model.RoleAdmin {
+		t.Errorf("UpdateUser() Role = %v, want %v", retrieved.Role, model.RoleAdmin)
+	}
+}
+
+func TestUserInMemRepository_UpdateUser_NotFound(t *testing.T) {
+	repo := NewUserInMemRepository()
+
+	_, err := repo.UpdateUser(model.User{ID: "nonexistent"})
+	if !errors.Is(err, model.ErrNotFound) {
+		t.Errorf("UpdateUser() error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestUserInMemRepository_ModifyUser(t *testing.T) {
+	repo := NewUserInMemRepository()
+	created, _ := repo.CreateUser(model.User{Name: "John", Email: "john@example.com", Tier: model.TierFree})
+
+	// Concurrent changes to different fields are all kept
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		repo.ModifyUser(created.ID, func(u *model.User) error {
+			u.Tier = model.TierPaid
+			return nil
+		})
+	}()
+	go func() {
+		defer wg.Done()
+		repo.ModifyUser(created.ID, func(u *model.User) error {
+			u.Disabled = true
+			return nil
+		})
+	}()
+	wg.Wait()
+
+	retrieved, _ := repo.GetUserByID(created.ID)
+	if retrieved.Tier != model.TierPaid || !retrieved.Disabled || !retrieved.CreatedAt.Equal(created.CreatedAt) {
+		t.Errorf("ModifyUser() stored %+v, want both changes and the original creation time", retrieved)
+	}
+
+	stop := errors.New("stop")
+	_, err := repo.ModifyUser(created.ID, func(u *model.User) error {
+		u.Role = model.RoleAdmin
+		return stop
+	})
+	if !errors.Is(err, stop) {
+		t.Errorf("ModifyUser() error = %v, want the error of the change", err)
+	}
+	if retrieved, _ := repo.GetUserByID(created.ID); retrieved.Role == model.RoleAdmin {
+		t.Error("ModifyUser() stored a change that failed")
+	}
+
+	if _, err := repo.ModifyUser("nonexistent", func(u *model.User) error { return nil }); !errors.Is(err, model.ErrNotFound) {
+		t.Errorf("ModifyUser() error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestUserInMemRepository_ListUsers(t *testing.T) {
+	repo := NewUserInMemRepository()
+
+	for _, email := range []string{"carol@example.com", "alice@example.com", "alan@example.org", "bob@example.com"} {
+		repo.CreateUser(model.User{Name: email, Email: email})
+	}
+
+	all, err := repo.ListUsers(model.UserQuery{})
+	if err != nil {
+		t.Fatalf("ListUsers() unexpected error = %v", err)
+	}
+	if len(all) != 4 {
+		t.Fatalf("ListUsers() returned %d users, want 4", len(all))
+	}
+	if all[0].Email != "alan@example.org" || all[3].Email != "carol@example.com" {
+		t.Errorf("ListUsers() should order by email, got %v ... %v", all[0].Email, all[3].Email)
+	}
+
+	matched, _ := repo.ListUsers(model.UserQuery{Search: "al"})
+	if len(matched) != 2 {
+		t.Errorf("ListUsers() with search returned %d users, want 2", len(matched))
+	}
+
+	page, _ := repo.ListUsers(model.UserQuery{Page: 2, PageSize: 3})
+	if len(page) != 1 {
+		t.Errorf("ListUsers() page 2 returned %d users, want 1", len(page))
+	}
+
+	count, _ := repo.CountUsers(model.UserQuery{Search: "al"})
+	if count != 2 {
+		t.Errorf("CountUsers() = %d, want 2", count)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"os"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/tmc/langchaingo/llms"
@@ -14,20 +15,157 @@ import (
 	"github.com/tmc/langchaingo/llms/googleai"
 	"github.com/tmc/langchaingo/llms/openai"
 	"github.com/tsongpon/athena/internal/logger"
+	"github.com/tsongpon/athena/internal/model"
 	"go.uber.org/zap"
 	"golang.org/x/net/html"
 )
 
+// pageCacheTTL is how long a fetched page is reused. Enriching a bookmark asks for its title,
+// image, summary and document details at about the same time, and they share one download.
+const pageCacheTTL = 30 * time.Second
+
 type WebRepository struct {
 	httpClient *http.Client
+
+	mutex sync.Mutex
+	pages map[string]*cachedPage // Recent and in-flight downloads by URL
+}
+
+// cachedPage is a download shared by concurrent and closely following lookups of one URL
+type cachedPage struct {
+	ready     chan struct{} // Closed when page and err are set
+	page      *fetchedPage
+	err       error
+	expiresAt time.Time // Zero while the download is in flight
+}
+
+// fetchedPage is a downloaded page. Only HTML and PDF bodies are kept; for other content the
+// media type and URL are all that is needed.
+type fetchedPage struct {
+	*decodedPage
+	data      []byte
+	truncated bool
+
+	pdfOnce sync.Once
+	pdf     pdfDocument
+	pdfErr  error
+
+	metadataOnce sync.Once
+	meta         pageMetadata
+}
+
+// text returns the body transcoded to UTF-8
+func (p *fetchedPage) text() string {
+	return decodeText(p.data, p.contentType)
 }
 
-func NewWebRepository() *WebRepository {
+// isPDF reports whether the page is a PDF document
+func (p *fetchedPage) isPDF() bool {
+	return p.mediaType == "application/pdf"
+}
+
+// document parses the page as a PDF once, however many lookups need it
+func (p *fetchedPage) document() (pdfDocument, error) {
+	p.pdfOnce.Do(func() {
+		if p.truncated {
+			p.pdfErr = fmt.Errorf("PDF is larger than %d bytes", maxDocumentBytes)
+			return
+		}
+		p.pdf, p.pdfErr = parsePDF(p.data)
+	})
+	return p.pdf, p.pdfErr
+}
+
+// metadata parses the page as HTML once and extracts what it says about itself
+func (p *fetchedPage) metadata() pageMetadata {
+	p.metadataOnce.Do(func() {
+		doc, err := html.Parse(strings.NewReader(p.text()))
+		if err != nil {
+			return
+		}
+		p.meta = extractMetadata(doc, p.url)
+	})
+	return p.meta
+}
+
+// NewWebRepository creates a web repository whose requests go through fetcher. A nil fetcher
+// sends requests directly.
+func NewWebRepository(fetcher *Fetcher) *WebRepository {
+	client := &http.Client{
+		Timeout: 10 * time.Second,
+	}
+	if fetcher != nil {
+		client.Transport = fetcher
+	}
 	return &WebRepository{
-		httpClient: &http.Client{
-			Timeout: 10 * time.Second,
-		},
+		httpClient: client,
+		pages:      make(map[string]*cachedPage),
+	}
+}
+
+// fetchPage returns the page at url, downloading it unless a recent or in-flight download
+// can be shared. A non-200 status is reported as an error.
+func (r *WebRepository) fetchPage(ctx context.Context, url string) (*fetchedPage, error) {
+	r.mutex.Lock()
+	entry, ok := r.pages[url]
+	if ok && (entry.expiresAt.IsZero() || time.Now().Before(entry.expiresAt)) {
+		r.mutex.Unlock()
+		select {
+		case <-entry.ready:
+			return entry.page, entry.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
 	}
+	for key, e := range r.pages {
+		if !e.expiresAt.IsZero() && time.Now().After(e.expiresAt) {
+			delete(r.pages, key)
+		}
+	}
+	entry = &cachedPage{ready: make(chan struct{})}
+	r.pages[url] = entry
+	r.mutex.Unlock()
+
+	page, err := r.download(ctx, url)
+	r.mutex.Lock()
+	entry.page, entry.err = page, err
+	entry.expiresAt = time.Now().Add(pageCacheTTL)
+	r.mutex.Unlock()
+	close(entry.ready)
+	return page, err
+}
+
+// download fetches url and keeps as much of the body as its content type calls for
+func (r *WebRepository) download(ctx context.Context, url string) (*fetchedPage, error) {
+	req, err := newPageRequest(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	decoded, err := decodePage(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &fetchedPage{decodedPage: decoded}
+	switch {
+	case decoded.isHTML():
+		// Metadata sits near the top, so a long page is cut rather than rejected
+		page.data, page.truncated, err = decoded.read(maxPageBytes)
+	case page.isPDF():
+		page.data, page.truncated, err = decoded.read(maxDocumentBytes)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	return page, nil
 }
 
 // GetTitle fetches the HTML content from the given URL and extracts the title
@@ -37,23 +175,30 @@ func (r *WebRepository) GetTitle(ctx context.Context, url string) (string, error
 	}
 
 	// Fetch the URL
-	resp, err := r.httpClient.Get(url)
+	page, err := r.fetchPage(ctx, url)
 	if err != nil {
 		logger.Debug("failed to fetch title from URL", zap.String("url", url), zap.Error(err))
 		return url, nil
 	}
-	defer resp.Body.Close()
 
-	// Check for successful response
-	if resp.StatusCode != http.StatusOK {
-		logger.Debug("failed to fetch title from URL", zap.String("url", url), zap.Int("status_code", resp.StatusCode))
-		return url, nil
+	// PDFs carry their own title; they and other files are otherwise named after the file
+	if page.isPDF() {
+		doc, err := page.document()
+		if err != nil {
+			logger.Debug("failed to read PDF", zap.String("url", url), zap.Error(err))
+		}
+		if doc.title != "" {
+			return doc.title, nil
+		}
+	}
+	if !page.isHTML() {
+		return fileTitle(page.url), nil
 	}
 
-	// Parse the HTML and extract the title
-	title, err := extractTitle(resp.Body)
-	if err != nil {
-		logger.Debug("failed to fetch title from URL", zap.String("url", url), zap.Error(err))
+	// Open Graph and Twitter Card titles leave out the site name most <title>s carry
+	title := page.metadata().title
+	if title == "" {
+		logger.Debug("no title found", zap.String("url", url))
 		return url, nil
 	}
 
@@ -68,37 +213,35 @@ func (r *WebRepository) GetContentSummary(ctx context.Context, url string) (stri
 	}
 
 	// Fetch the URL
-	resp, err := r.httpClient.Get(url)
+	page, err := r.fetchPage(ctx, url)
 	if err != nil {
 		logger.Debug("failed to fetch content from URL", zap.String("url", url), zap.Error(err))
 		return "", nil
 	}
-	defer resp.Body.Close()
 
-	// Check for successful response
-	if resp.StatusCode != http.StatusOK {
-		logger.Debug("failed to fetch content from URL", zap.String("url", url), zap.Int("status_code", resp.StatusCode))
-		return "", nil
-	}
-
-	// Read the HTML body
-	bodyBytes, err := io.ReadAll(resp.Body)
-	if err != nil {
-		logger.Debug("failed to read response body", zap.String("url", url), zap.Error(err))
+	// Extract text content from HTML or the pages of a PDF; other content is not summarized
+	var textContent string
+	switch {
+	case page.isPDF():
+		doc, err := page.document()
+		if err != nil {
+			logger.Debug("failed to read PDF", zap.String("url", url), zap.Error(err))
+			return "", nil
+		}
+		textContent = doc.text
+	case page.isHTML():
+		textContent = extractTextContent(page.text())
+	default:
+		logger.Debug("skipping summary of non-text content", zap.String("url", url), zap.String("content_type", page.mediaType))
 		return "", nil
 	}
-
-	// Extract text content from HTML
-	textContent := extractTextContent(string(bodyBytes))
 	if textContent == "" {
 		logger.Debug("no text content found", zap.String("url", url))
 		return "", nil
 	}
 
-	// Limit the text content to avoid token limits (first 4000 characters)
-	if len(textContent) > 4000 {
-		textContent = textContent[:4000]
-	}
+	// Limit the text content to avoid token limits (first 4000 bytes)
+	textContent = truncateText(textContent, 4000)
 
 	llmModelName := os.Getenv("LLM_MODEL")
 	var llmModel llms.Model
@@ -186,115 +329,113 @@ func extractTextContent(htmlContent string) string {
 	return strings.TrimSpace(textBuilder.String())
 }
 
-// GetMainImage fetches the HTML content from the given URL and extracts the OpenGraph image URL
+// GetMainImage fetches the HTML content from the given URL and extracts the Open Graph or
+// Twitter Card image URL, made absolute against the page
 func (r *WebRepository) GetMainImage(ctx context.Context, url string) (string, error) {
 	if url == "" {
 		return "", fmt.Errorf("URL cannot be empty")
 	}
 
 	// Fetch the URL
-	resp, err := r.httpClient.Get(url)
+	page, err := r.fetchPage(ctx, url)
 	if err != nil {
 		logger.Debug("failed to fetch main image from URL", zap.String("url", url), zap.Error(err))
 		return "", nil
 	}
-	defer resp.Body.Close()
 
-	// Check for successful response
-	if resp.StatusCode != http.StatusOK {
-		logger.Debug("failed to fetch main image from URL", zap.String("url", url), zap.Int("status_code", resp.StatusCode))
+	// A bookmarked image is its own main image; other files have none
+	if page.isImage() {
+		return page.url.String(), nil
+	}
+	if !page.isHTML() {
 		return "", nil
 	}
 
-	// Parse the HTML and extract the OG image
-	imageURL, err := extractOGImage(resp.Body)
-	if err != nil {
-		logger.Debug("failed to extract OG image from URL", zap.String("url", url), zap.Error(err))
-		return "", nil
+	imageURL := page.metadata().imageURL
+	if imageURL == "" {
+		logger.Debug("no og:image found", zap.String("url", url))
 	}
 
 	return imageURL, nil
 }
 
-// extractOGImage parses HTML and extracts the content of the og:image meta tag
-func extractOGImage(body io.Reader) (string, error) {
-	doc, err := html.Parse(body)
+// GetPageMetadata fetches the given URL and returns what the page says about itself: Open
+// Graph, Twitter Card and JSON-LD details, its icon and an oEmbed player for video pages. For
+// PDFs it returns the author and page count, and for web pages their word count.
+func (r *WebRepository) GetPageMetadata(ctx context.Context, url string) (model.PageMetadata, error) {
+	if url == "" {
+		return model.PageMetadata{}, fmt.Errorf("URL cannot be empty")
+	}
+
+	// Fetch the URL
+	page, err := r.fetchPage(ctx, url)
 	if err != nil {
-		return "", err
+		logger.Debug("failed to fetch metadata from URL", zap.String("url", url), zap.Error(err))
+		return model.PageMetadata{}, nil
 	}
 
-	var imageURL string
-	var found bool
-	var traverse func(*html.Node)
-	traverse = func(n *html.Node) {
-		if found {
-			return
+	switch {
+	case page.isPDF():
+		doc, err := page.document()
+		if err != nil {
+			logger.Debug("failed to read PDF", zap.String("url", url), zap.Error(err))
+			return model.PageMetadata{}, nil
 		}
-		if n.Type == html.ElementNode && n.Data == "meta" {
-			var property, content string
-			for _, attr := range n.Attr {
-				if attr.Key == "property" && attr.Val == "og:image" {
-					property = attr.Val
-				}
-				if attr.Key == "content" {
-					content = attr.Val
-				}
-			}
-			if property == "og:image" && content != "" {
-				imageURL = strings.TrimSpace(content)
-				found = true
-				return
-			}
+		return model.PageMetadata{
+			Author:    doc.author,
+			PageCount: doc.pageCount,
+		}, nil
+	case !page.isHTML():
+		return model.PageMetadata{}, nil
+	}
+
+	meta := page.metadata()
+	metadata := model.PageMetadata{
+		Description: meta.description,
+		SiteName:    meta.siteName,
+		Type:        meta.pageType,
+		Author:      meta.author,
+		PublishedAt: meta.publishedAt,
+		FaviconURL:  meta.faviconURL,
+		WordCount:   len(strings.Fields(extractTextContent(page.text()))),
+	}
+	if meta.oembedURL != "" {
+		oembed, err := r.fetchOEmbed(ctx, meta.oembedURL)
+		if err != nil {
+			logger.Debug("failed to fetch oEmbed", zap.String("url", url), zap.String("oembed_url", meta.oembedURL), zap.Error(err))
+			return metadata, nil
 		}
-		for c := n.FirstChild; c != nil; c = c.NextSibling {
-			if found {
-				return
-			}
-			traverse(c)
+		metadata.Embed = oembed.embed()
+		if metadata.Author == "" {
+			metadata.Author = strings.TrimSpace(oembed.AuthorName)
 		}
 	}
-	traverse(doc)
+	return metadata, nil
+}
 
+// extractOGImage parses HTML and extracts its Open Graph or Twitter Card image URL
+func extractOGImage(body io.Reader) (string, error) {
+	doc, err := html.Parse(body)
+	if err != nil {
+		return "", err
+	}
+	imageURL := extractMetadata(doc, nil).imageURL
 	if imageURL == "" {
 		return "", fmt.Errorf("no og:image found")
 	}
-
 	return imageURL, nil
 }
 
-// extractTitle parses HTML and extracts the content of the <title> tag
+// extractTitle parses HTML and extracts its title, preferring Open Graph and Twitter Card
+// titles over the <title> tag
 func extractTitle(body io.Reader) (string, error) {
 	doc, err := html.Parse(body)
 	if err != nil {
 		return "", err
 	}
-
-	var title string
-	var found bool
-	var traverse func(*html.Node)
-	traverse = func(n *html.Node) {
-		if found {
-			return
-		}
-		if n.Type == html.ElementNode && n.Data == "title" {
-			if n.FirstChild != nil {
-				title = strings.TrimSpace(n.FirstChild.Data)
-				found = true
-			}
-			return
-		}
-		for c := n.FirstChild; c != nil; c = c.NextSibling {
-			if found {
-				return
-			}
-			traverse(c)
-		}
-	}
-	traverse(doc)
-
+	title := extractMetadata(doc, nil).title
 	if title == "" {
 		return "", fmt.Errorf("no title found")
 	}
-
 	return title, nil
 }
@@ -8,10 +8,12 @@ import (
 	"os"
 	"strings"
 	"testing"
+
+	"github.com/tsongpon/athena/internal/model"
 )
 
 func TestWebRepository_GetTitle(t *testing.T) {
-	repo := NewWebRepository()
+	repo := NewWebRepository(nil)
 
 	// Create a test server that returns HTML with a title
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -45,7 +47,7 @@ func TestWebRepository_GetTitle(t *testing.T) {
 }
 
 func TestWebRepository_GetTitle_EmptyURL(t *testing.T) {
-	repo := NewWebRepository()
+	repo := NewWebRepository(nil)
 
 	// Test with empty URL
 	_, err := repo.GetTitle(context.Background(), "")
@@ -61,7 +63,7 @@ func TestWebRepository_GetTitle_EmptyURL(t *testing.T) {
 }
 
 func TestWebRepository_GetTitle_InvalidURL(t *testing.T) {
-	repo := NewWebRepository()
+	repo := NewWebRepository(nil)
 
 	// Test with invalid URL - should return URL as fallback title
 	invalidURL := "not-a-valid-url"
@@ -78,7 +80,7 @@ func TestWebRepository_GetTitle_InvalidURL(t *testing.T) {
 }
 
 func TestWebRepository_GetTitle_NotFound(t *testing.T) {
-	repo := NewWebRepository()
+	repo := NewWebRepository(nil)
 
 	// Create a test server that returns 404
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -100,7 +102,7 @@ func TestWebRepository_GetTitle_NotFound(t *testing.T) {
 }
 
 func TestWebRepository_GetTitle_NoTitle(t *testing.T) {
-	repo := NewWebRepository()
+	repo := NewWebRepository(nil)
 
 	// Create a test server that returns HTML without a title
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -133,7 +135,7 @@ func TestWebRepository_GetTitle_NoTitle(t *testing.T) {
 }
 
 func TestWebRepository_GetTitle_EmptyTitle(t *testing.T) {
-	repo := NewWebRepository()
+	repo := NewWebRepository(nil)
 
 	// Create a test server that returns HTML with empty title
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -167,7 +169,7 @@ func TestWebRepository_GetTitle_EmptyTitle(t *testing.T) {
 }
 
 func TestWebRepository_GetTitle_TitleWithWhitespace(t *testing.T) {
-	repo := NewWebRepository()
+	repo := NewWebRepository(nil)
 
 	// Create a test server that returns HTML with title containing whitespace
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -201,7 +203,7 @@ func TestWebRepository_GetTitle_TitleWithWhitespace(t *testing.T) {
 }
 
 func TestWebRepository_GetTitle_SpecialCharacters(t *testing.T) {
-	repo := NewWebRepository()
+	repo := NewWebRepository(nil)
 
 	// Create a test server that returns HTML with special characters in title
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -235,7 +237,7 @@ func TestWebRepository_GetTitle_SpecialCharacters(t *testing.T) {
 }
 
 func TestWebRepository_GetTitle_InternalServerError(t *testing.T) {
-	repo := NewWebRepository()
+	repo := NewWebRepository(nil)
 
 	// Create a test server that returns 500
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -257,7 +259,7 @@ func TestWebRepository_GetTitle_InternalServerError(t *testing.T) {
 }
 
 func TestWebRepository_GetTitle_InvalidHTML(t *testing.T) {
-	repo := NewWebRepository()
+	repo := NewWebRepository(nil)
 
 	// Create a test server that returns invalid HTML
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -281,20 +283,20 @@ func TestWebRepository_GetTitle_InvalidHTML(t *testing.T) {
 }
 
 func TestNewWebRepository(t *testing.T) {
-	repo := NewWebRepository()
+	repo := NewWebRepository(nil)
 
 	if repo == nil {
-		t.Error("NewWebRepository() should not return nil")
+		t.Error("NewWebRepository(nil) should not return nil")
 	}
 
 	if repo.httpClient == nil {
-		t.Error("NewWebRepository() should initialize httpClient")
+		t.Error("NewWebRepository(nil) should initialize httpClient")
 	}
 
 	// Verify timeout is set
 	expectedTimeout := 10 * 1000000000 // 10 seconds in nanoseconds
 	if repo.httpClient.Timeout.Nanoseconds() != int64(expectedTimeout) {
-		t.Errorf("NewWebRepository() httpClient timeout = %v, want 10s", repo.httpClient.Timeout)
+		t.Errorf("NewWebRepository(nil) httpClient timeout = %v, want 10s", repo.httpClient.Timeout)
 	}
 }
 
@@ -371,7 +373,7 @@ func TestExtractTitle(t *testing.T) {
 }
 
 func TestWebRepository_GetContentSummary_EmptyURL(t *testing.T) {
-	repo := NewWebRepository()
+	repo := NewWebRepository(nil)
 
 	// Test with empty URL
 	_, err := repo.GetContentSummary(context.Background(), "")
@@ -387,7 +389,7 @@ func TestWebRepository_GetContentSummary_EmptyURL(t *testing.T) {
 }
 
 func TestWebRepository_GetContentSummary_NoAPIKey(t *testing.T) {
-	repo := NewWebRepository()
+	repo := NewWebRepository(nil)
 
 	// Ensure ANTHROPIC_API_KEY is not set
 	originalAPIKey := os.Getenv("ANTHROPIC_API_KEY")
@@ -430,7 +432,7 @@ func TestWebRepository_GetContentSummary_NoAPIKey(t *testing.T) {
 }
 
 func TestWebRepository_GetContentSummary_InvalidURL(t *testing.T) {
-	repo := NewWebRepository()
+	repo := NewWebRepository(nil)
 
 	// Test with invalid URL - should return empty string
 	summary, err := repo.GetContentSummary(context.Background(), "not-a-valid-url")
@@ -445,7 +447,7 @@ func TestWebRepository_GetContentSummary_InvalidURL(t *testing.T) {
 }
 
 func TestWebRepository_GetContentSummary_NotFound(t *testing.T) {
-	repo := NewWebRepository()
+	repo := NewWebRepository(nil)
 
 	// Create a test server that returns 404
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -466,7 +468,7 @@ func TestWebRepository_GetContentSummary_NotFound(t *testing.T) {
 }
 
 func TestWebRepository_GetContentSummary_NoTextContent(t *testing.T) {
-	repo := NewWebRepository()
+	repo := NewWebRepository(nil)
 
 	// Create a test server that returns HTML with no text content
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -500,7 +502,7 @@ func TestWebRepository_GetContentSummary_NoTextContent(t *testing.T) {
 }
 
 func TestWebRepository_GetContentSummary_LongContent(t *testing.T) {
-	repo := NewWebRepository()
+	repo := NewWebRepository(nil)
 
 	// Create content longer than 4000 characters
 	longText := strings.Repeat("This is a long text. ", 300) // Creates ~6000 chars
@@ -557,7 +559,7 @@ func TestWebRepository_GetContentSummary_LongContent(t *testing.T) {
 }
 
 func TestWebRepository_GetContentSummary_UnsupportedModel(t *testing.T) {
-	repo := NewWebRepository()
+	repo := NewWebRepository(nil)
 
 	// Create a test server that returns HTML with text
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -600,7 +602,7 @@ func TestWebRepository_GetContentSummary_UnsupportedModel(t *testing.T) {
 }
 
 func TestWebRepository_GetContentSummary_NoModelSet(t *testing.T) {
-	repo := NewWebRepository()
+	repo := NewWebRepository(nil)
 
 	// Create a test server that returns HTML with text
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -643,7 +645,7 @@ func TestWebRepository_GetContentSummary_NoModelSet(t *testing.T) {
 }
 
 func TestWebRepository_GetContentSummary_OpenAINoAPIKey(t *testing.T) {
-	repo := NewWebRepository()
+	repo := NewWebRepository(nil)
 
 	// Create a test server that returns HTML with text
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -693,7 +695,7 @@ func TestWebRepository_GetContentSummary_OpenAINoAPIKey(t *testing.T) {
 }
 
 func TestWebRepository_GetContentSummary_GeminiNoAPIKey(t *testing.T) {
-	repo := NewWebRepository()
+	repo := NewWebRepository(nil)
 
 	// Create a test server that returns HTML with text
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -743,7 +745,7 @@ func TestWebRepository_GetContentSummary_GeminiNoAPIKey(t *testing.T) {
 }
 
 func TestWebRepository_GetContentSummary_AnthropicInvalidAPIKey(t *testing.T) {
-	repo := NewWebRepository()
+	repo := NewWebRepository(nil)
 
 	// Create a test server that returns HTML with text
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -846,7 +848,7 @@ func TestExtractTextContent(t *testing.T) {
 }
 
 func TestWebRepository_GetMainImage(t *testing.T) {
-	repo := NewWebRepository()
+	repo := NewWebRepository(nil)
 
 	// Create a test server that returns HTML with og:image
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -881,7 +883,7 @@ func TestWebRepository_GetMainImage(t *testing.T) {
 }
 
 func TestWebRepository_GetMainImage_EmptyURL(t *testing.T) {
-	repo := NewWebRepository()
+	repo := NewWebRepository(nil)
 
 	// Test with empty URL
 	_, err := repo.GetMainImage(context.Background(), "")
@@ -897,7 +899,7 @@ func TestWebRepository_GetMainImage_EmptyURL(t *testing.T) {
 }
 
 func TestWebRepository_GetMainImage_InvalidURL(t *testing.T) {
-	repo := NewWebRepository()
+	repo := NewWebRepository(nil)
 
 	// Test with invalid URL - should return empty string without error
 	invalidURL := "not-a-valid-url"
@@ -914,7 +916,7 @@ func TestWebRepository_GetMainImage_InvalidURL(t *testing.T) {
 }
 
 func TestWebRepository_GetMainImage_NotFound(t *testing.T) {
-	repo := NewWebRepository()
+	repo := NewWebRepository(nil)
 
 	// Create a test server that returns 404
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -936,7 +938,7 @@ func TestWebRepository_GetMainImage_NotFound(t *testing.T) {
 }
 
 func TestWebRepository_GetMainImage_NoOGImage(t *testing.T) {
-	repo := NewWebRepository()
+	repo := NewWebRepository(nil)
 
 	// Create a test server that returns HTML without og:image
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -970,7 +972,7 @@ func TestWebRepository_GetMainImage_NoOGImage(t *testing.T) {
 }
 
 func TestWebRepository_GetMainImage_EmptyOGImage(t *testing.T) {
-	repo := NewWebRepository()
+	repo := NewWebRepository(nil)
 
 	// Create a test server that returns HTML with empty og:image
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -1005,7 +1007,7 @@ func TestWebRepository_GetMainImage_EmptyOGImage(t *testing.T) {
 }
 
 func TestWebRepository_GetMainImage_OGImageWithWhitespace(t *testing.T) {
-	repo := NewWebRepository()
+	repo := NewWebRepository(nil)
 
 	// Create a test server that returns HTML with og:image containing whitespace
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -1040,7 +1042,7 @@ func TestWebRepository_GetMainImage_OGImageWithWhitespace(t *testing.T) {
 }
 
 func TestWebRepository_GetMainImage_InternalServerError(t *testing.T) {
-	repo := NewWebRepository()
+	repo := NewWebRepository(nil)
 
 	// Create a test server that returns 500
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -1062,7 +1064,7 @@ func TestWebRepository_GetMainImage_InternalServerError(t *testing.T) {
 }
 
 func TestWebRepository_GetMainImage_MultipleOGImages(t *testing.T) {
-	repo := NewWebRepository()
+	repo := NewWebRepository(nil)
 
 	// Create a test server that returns HTML with multiple og:image tags
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -1186,3 +1188,182 @@ func TestExtractOGImage(t *testing.T) {
 		})
 	}
 }
+
+func TestWebRepository_GetTitle_LegacyCharset(t *testing.T) {
+	repo := NewWebRepository(nil)
+
+	// "日本語" in Shift_JIS, declared only in a meta tag
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write([]byte(`<html><head><meta charset="shift_jis"><title>`))
+		w.Write([]byte{0x93, 0xfa, 0x96, 0x7b, 0x8c, 0xea})
+		w.Write([]byte(`</title></head></html>`))
+	}))
+	defer server.Close()
+
+	title, err := repo.GetTitle(context.Background(), server.URL)
+	if err != nil {
+		t.Fatalf("GetTitle() unexpected error = %v", err)
+	}
+	if title != "日本語" {
+		t.Errorf("GetTitle() title = %q, want %q", title, "日本語")
+	}
+}
+
+func TestWebRepository_GetTitle_NonHTML(t *testing.T) {
+	repo := NewWebRepository(nil)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/pdf")
+		w.Write([]byte("%PDF-1.7 <title>Not a title</title>"))
+	}))
+	defer server.Close()
+
+	title, err := repo.GetTitle(context.Background(), server.URL+"/docs/annual-report.pdf")
+	if err != nil {
+		t.Fatalf("GetTitle() unexpected error = %v", err)
+	}
+	if title != "annual-report.pdf" {
+		t.Errorf("GetTitle() title = %q, want the file name", title)
+	}
+}
+
+func TestWebRepository_GetMainImage_Image(t *testing.T) {
+	repo := NewWebRepository(nil)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("\x89PNG\r\n\x1a\n"))
+	}))
+	defer server.Close()
+
+	imageURL, err := repo.GetMainImage(context.Background(), server.URL+"/cat.png")
+	if err != nil {
+		t.Fatalf("GetMainImage() unexpected error = %v", err)
+	}
+	if imageURL != server.URL+"/cat.png" {
+		t.Errorf("GetMainImage() = %q, want the image URL itself", imageURL)
+	}
+}
+
+func TestWebRepository_PDF(t *testing.T) {
+	repo := NewWebRepository(nil)
+
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/pdf")
+		w.Write(buildPDF("A Study of Things", "Jane Doe", "Page one", "Page two", "Page three"))
+	}))
+	defer server.Close()
+	url := server.URL + "/paper.pdf"
+
+	title, err := repo.GetTitle(context.Background(), url)
+	if err != nil {
+		t.Fatalf("GetTitle() unexpected error = %v", err)
+	}
+	if title != "A Study of Things" {
+		t.Errorf("GetTitle() title = %q, want the PDF title", title)
+	}
+
+	info, err := repo.GetPageMetadata(context.Background(), url)
+	if err != nil {
+		t.Fatalf("GetPageMetadata() unexpected error = %v", err)
+	}
+	if info.Author != "Jane Doe" || info.PageCount != 3 {
+		t.Errorf("GetPageMetadata() = %+v, want author Jane Doe and 3 pages", info)
+	}
+
+	imageURL, err := repo.GetMainImage(context.Background(), url)
+	if err != nil || imageURL != "" {
+		t.Errorf("GetMainImage() = %q, %v, want no image for a PDF", imageURL, err)
+	}
+
+	if requests != 1 {
+		t.Errorf("server received %d requests, want 1 shared by all lookups", requests)
+	}
+}
+
+func TestWebRepository_GetTitle_PDFWithoutTitle(t *testing.T) {
+	repo := NewWebRepository(nil)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Write(buildPDF("", "", "Only text"))
+	}))
+	defer server.Close()
+
+	title, err := repo.GetTitle(context.Background(), server.URL+"/files/spec.pdf")
+	if err != nil {
+		t.Fatalf("GetTitle() unexpected error = %v", err)
+	}
+	if title != "spec.pdf" {
+		t.Errorf("GetTitle() title = %q, want the file name", title)
+	}
+}
+
+func TestWebRepository_GetPageMetadata_NoDocument(t *testing.T) {
+	repo := NewWebRepository(nil)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("<html><title>Page</title></html>"))
+	}))
+	defer server.Close()
+
+	info, err := repo.GetPageMetadata(context.Background(), server.URL)
+	if err != nil {
+		t.Fatalf("GetPageMetadata() unexpected error = %v", err)
+	}
+	if info.Author != "" || info.PageCount != 0 {
+		t.Errorf("GetPageMetadata() = %+v, want no document details for a web page", info)
+	}
+}
+
+func TestWebRepository_GetPageMetadata(t *testing.T) {
+	repo := NewWebRepository(nil)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/watch", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write([]byte(`<html><head>
+			<title>Cat Video</title>
+			<meta property="og:type" content="video.other">
+			<meta property="og:site_name" content="VideoSite">
+			<meta property="og:image" content="/thumbs/cat.jpg">
+			<link rel="icon" href="/icon.svg">
+			<link rel="alternate" type="application/json+oembed" href="/oembed?id=cat">
+		</head></html>`))
+	})
+	mux.HandleFunc("/oembed", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"type": "video", "provider_name": "VideoSite", "author_name": "Cat Lover",
+			"width": 560, "height": 315, "html": "<iframe src=\"https://player.example.com/cat\"></iframe>"}`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	metadata, err := repo.GetPageMetadata(context.Background(), server.URL+"/watch")
+	if err != nil {
+		t.Fatalf("GetPageMetadata() unexpected error = %v", err)
+	}
+	if metadata.Type != "video.other" || metadata.SiteName != "VideoSite" {
+		t.Errorf("GetPageMetadata() type = %q, site name = %q, want video.other and VideoSite", metadata.Type, metadata.SiteName)
+	}
+	if metadata.FaviconURL != server.URL+"/icon.svg" {
+		t.Errorf("GetPageMetadata() favicon = %q, want %q", metadata.FaviconURL, server.URL+"/icon.svg")
+	}
+	if metadata.Author != "Cat Lover" {
+		t.Errorf("GetPageMetadata() author = %q, want the oEmbed author", metadata.Author)
+	}
+	if metadata.WordCount != 2 {
+		t.Errorf("GetPageMetadata() word count = %d, want the 2 words of the title", metadata.WordCount)
+	}
+	want := model.Embed{Provider: "VideoSite", URL: "https://player.example.com/cat", Width: 560, Height: 315}
+	if metadata.Embed != want {
+		t.Errorf("GetPageMetadata() embed = %+v, want %+v", metadata.Embed, want)
+	}
+
+	imageURL, err := repo.GetMainImage(context.Background(), server.URL+"/watch")
+	if err != nil || imageURL != server.URL+"/thumbs/cat.jpg" {
+		t.Errorf("GetMainImage() = %q, %v, want the og:image resolved against the page", imageURL, err)
+	}
+}
@@ -0,0 +1,436 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/google/uuid"
+	"github.com/tsongpon/athena/internal/logger"
+	"github.com/tsongpon/athena/internal/model"
+	"go.uber.org/zap"
+	"google.golang.org/api/iterator"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	webhooksCollection          = "webhooks"
+	webhookDeliveriesCollection = "webhook_deliveries"
+)
+
+// webhookDeliveryRetention is how long deliveries are kept for the delivery log. Firestore
+// deletes them after expire_at once a TTL policy is configured on the field.
+const webhookDeliveryRetention = 30 * 24 * time.Hour
+
+// WebhookFirestoreRepository implements WebhookRepository interface using GCP Firestore
+type WebhookFirestoreRepository struct {
+	client *firestore.Client
+	ctx    context.Context
+}
+
+// NewWebhookFirestoreRepository creates a new instance of WebhookFirestoreRepository
+func NewWebhookFirestoreRepository(ctx context.Context, client *firestore.Client) *WebhookFirestoreRepository {
+	return &WebhookFirestoreRepository{
+		client: client,
+		ctx:    ctx,
+	}
+}
+
+// firestoreWebhook is the structure used to store/retrieve webhooks in Firestore
+type firestoreWebhook struct {
+	ID                  string    `firestore:"id"`
+	UserID              string    `firestore:"user_id"`
+	URL                 string    `firestore:"url"`
+	Events              []string  `firestore:"events"`
+	Secret              string    `firestore:"secret"`
+	Active              bool      `firestore:"active"`
+	ConsecutiveFailures int       `firestore:"consecutive_failures"`
+	DisabledAt          time.Time `firestore:"disabled_at,omitempty"`
+	CreatedAt           time.Time `firestore:"created_at"`
+	UpdatedAt           time.Time `firestore:"updated_at"`
+}
+
+// firestoreWebhookDelivery is the structure used to store/retrieve webhook deliveries in Firestore
+type firestoreWebhookDelivery struct {
+	ID             string    `firestore:"id"`
+	WebhookID      string    `firestore:"webhook_id"`
+	UserID         string    `firestore:"user_id"`
+	EventID        string    `firestore:"event_id"`
+	EventType      string    `firestore:"event_type"`
+	Payload        []byte    `firestore:"payload"`
+	Status         string    `firestore:"status"`
+	Attempts       int       `firestore:"attempts"`
+	NextAttemptAt  time.Time `firestore:"next_attempt_at"`
+	LastAttemptAt  time.Time `firestore:"last_attempt_at,omitempty"`
+	ResponseStatus int       `firestore:"response_status,omitempty"`
+	Error          string    `firestore:"error,omitempty"`
+	CreatedAt      time.Time `firestore:"created_at"`
+	ExpireAt       time.Time `firestore:"expire_at"`
+}
+
+func toFirestoreWebhook(webhook model.Webhook) firestoreWebhook {
+	return firestoreWebhook{
+		ID:                  webhook.ID,
+		UserID:              webhook.UserID,
+		URL:                 webhook.URL,
+		Events:              webhook.Events,
+		Secret:              webhook.Secret,
+		Active:              webhook.Active,
+		ConsecutiveFailures: webhook.ConsecutiveFailures,
+		DisabledAt:          webhook.DisabledAt,
+		CreatedAt:           webhook.CreatedAt,
+		UpdatedAt:           webhook.UpdatedAt,
+	}
+}
+
+func toModelWebhook(fsWebhook firestoreWebhook) model.Webhook {
+	return model.Webhook{
+		ID:                  fsWebhook.ID,
+		UserID:              fsWebhook.UserID,
+		URL:                 fsWebhook.URL,
+		Events:              fsWebhook.Events,
+		Secret:              fsWebhook.Secret,
+		Active:              fsWebhook.Active,
+		ConsecutiveFailures: fsWebhook.ConsecutiveFailures,
+		DisabledAt:          fsWebhook.DisabledAt,
+		CreatedAt:           fsWebhook.CreatedAt,
+		UpdatedAt:           fsWebhook.UpdatedAt,
+	}
+}
+
+func toFirestoreWebhookDelivery(delivery model.WebhookDelivery) firestoreWebhookDelivery {
+	return firestoreWebhookDelivery{
+		ID:             delivery.ID,
+		WebhookID:      delivery.WebhookID,
+		UserID:         delivery.UserID,
+		EventID:        delivery.EventID,
+		EventType:      delivery.EventType,
+		Payload:        delivery.Payload,
+		Status:         delivery.Status,
+		Attempts:       delivery.Attempts,
+		NextAttemptAt:  delivery.NextAttemptAt,
+		LastAttemptAt:  delivery.LastAttemptAt,
+		ResponseStatus: delivery.ResponseStatus,
+		Error:          delivery.Error,
+		CreatedAt:      delivery.CreatedAt,
+		ExpireAt:       delivery.CreatedAt.Add(webhookDeliveryRetention),
+	}
+}
+
+func toModelWebhookDelivery(fsDelivery firestoreWebhookDelivery) model.WebhookDelivery {
+	return model.WebhookDelivery{
+		ID:             fsDelivery.ID,
+		WebhookID:      fsDelivery.WebhookID,
+		UserID:         fsDelivery.UserID,
+		EventID:        fsDelivery.EventID,
+		EventType:      fsDelivery.EventType,
+		Payload:        fsDelivery.Payload,
+		Status:         fsDelivery.Status,
+		Attempts:       fsDelivery.Attempts,
+		NextAttemptAt:  fsDelivery.NextAttemptAt,
+		LastAttemptAt:  fsDelivery.LastAttemptAt,
+		ResponseStatus: fsDelivery.ResponseStatus,
+		Error:          fsDelivery.Error,
+		CreatedAt:      fsDelivery.CreatedAt,
+	}
+}
+
+// CreateWebhook stores a new webhook in Firestore with a generated ID
+func (r *WebhookFirestoreRepository) CreateWebhook(webhook model.Webhook) (model.Webhook, error) {
+	webhook.ID = uuid.New().String()
+	if webhook.CreatedAt.IsZero() {
+		webhook.CreatedAt = time.Now()
+	}
+	webhook.UpdatedAt = webhook.CreatedAt
+
+	_, err := r.client.Collection(webhooksCollection).Doc(webhook.ID).Set(r.ctx, toFirestoreWebhook(webhook))
+	if err != nil {
+		logger.Error("Failed to create webhook in Firestore", zap.String("user_id", webhook.UserID), zap.Error(err))
+		return model.Webhook{}, fmt.Errorf("failed to create webhook: %w", err)
+	}
+
+	return webhook, nil
+}
+
+// GetWebhook retrieves a webhook from Firestore by its ID
+func (r *WebhookFirestoreRepository) GetWebhook(id string) (model.Webhook, error) {
+	docSnap, err := r.client.Collection(webhooksCollection).Doc(id).Get(r.ctx)
+	if status.Code(err) == codes.NotFound {
+		return model.Webhook{}, fmt.Errorf("webhook with ID %s %w", id, model.ErrNotFound)
+	}
+	if err != nil {
+		logger.Error("Failed to get webhook from Firestore", zap.String("id", id), zap.Error(err))
+		return model.Webhook{}, fmt.Errorf("failed to get webhook: %w", err)
+	}
+
+	var fsWebhook firestoreWebhook
+	if err := docSnap.DataTo(&fsWebhook); err != nil {
+		return model.Webhook{}, fmt.Errorf("failed to parse webhook data: %w", err)
+	}
+
+	return toModelWebhook(fsWebhook), nil
+}
+
+// ListWebhooks retrieves the webhooks of a user from Firestore, oldest first
+func (r *WebhookFirestoreRepository) ListWebhooks(userID string) ([]model.Webhook, error) {
+	iter := r.client.Collection(webhooksCollection).
+		Where("user_id", "==", userID).
+		OrderBy("created_at", firestore.Asc).
+		Documents(r.ctx)
+	defer iter.Stop()
+
+	webhooks := []model.Webhook{}
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			logger.Error("Failed to list webhooks from Firestore", zap.String("user_id", userID), zap.Error(err))
+			return nil, fmt.Errorf("failed to list webhooks: %w", err)
+		}
+
+		var fsWebhook firestoreWebhook
+		if err := doc.DataTo(&fsWebhook); err != nil {
+			return nil, fmt.Errorf("failed to parse webhook data: %w", err)
+		}
+		webhooks = append(webhooks, toModelWebhook(fsWebhook))
+	}
+
+	return webhooks, nil
+}
+
+// UpdateWebhook applies an update to a webhook in a transaction. Only the fields the owner
+// can change are written, so a delivery result recorded meanwhile is kept.
+func (r *WebhookFirestoreRepository) UpdateWebhook(id string, update model.WebhookUpdate) (model.Webhook, error) {
+	var webhook model.Webhook
+	doc := r.client.Collection(webhooksCollection).Doc(id)
+	err := r.client.RunTransaction(r.ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		docSnap, err := tx.Get(doc)
+		if err != nil {
+			return err
+		}
+		var fsWebhook firestoreWebhook
+		if err := docSnap.DataTo(&fsWebhook); err != nil {
+			return err
+		}
+		webhook = toModelWebhook(fsWebhook)
+		webhook.ApplyUpdate(update)
+		webhook.UpdatedAt = time.Now()
+		return tx.Update(doc, []firestore.Update{
+			{Path: "url", Value: webhook.URL},
+			{Path: "events", Value: webhook.Events},
+			{Path: "active", Value: webhook.Active},
+			{Path: "consecutive_failures", Value: webhook.ConsecutiveFailures},
+			{Path: "disabled_at", Value: webhook.DisabledAt},
+			{Path: "updated_at", Value: webhook.UpdatedAt},
+		})
+	})
+	if status.Code(err) == codes.NotFound {
+		return model.Webhook{}, fmt.Errorf("webhook with ID %s %w", id, model.ErrNotFound)
+	}
+	if err != nil {
+		logger.Error("Failed to update webhook in Firestore", zap.String("id", id), zap.Error(err))
+		return model.Webhook{}, fmt.Errorf("failed to update webhook: %w", err)
+	}
+
+	return webhook, nil
+}
+
+// RecordWebhookResult applies the outcome of a delivery attempt to a webhook in a transaction.
+// Only the failure fields are written, so concurrent changes of the URL or events are kept.
+func (r *WebhookFirestoreRepository) RecordWebhookResult(id string, succeeded bool, at time.Time) (model.Webhook, error) {
+	var webhook model.Webhook
+	doc := r.client.Collection(webhooksCollection).Doc(id)
+	err := r.client.RunTransaction(r.ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		docSnap, err := tx.Get(doc)
+		if err != nil {
+			return err
+		}
+		var fsWebhook firestoreWebhook
+		if err := docSnap.DataTo(&fsWebhook); err != nil {
+			return err
+		}
+		webhook = toModelWebhook(fsWebhook)
+		webhook.RecordResult(succeeded, at)
+		webhook.UpdatedAt = time.Now()
+		return tx.Update(doc, []firestore.Update{
+			{Path: "consecutive_failures", Value: webhook.ConsecutiveFailures},
+			{Path: "active", Value: webhook.Active},
+			{Path: "disabled_at", Value: webhook.DisabledAt},
+			{Path: "updated_at", Value: webhook.UpdatedAt},
+		})
+	})
+	if status.Code(err) == codes.NotFound {
+		return model.Webhook{}, fmt.Errorf("webhook with ID %s %w", id, model.ErrNotFound)
+	}
+	if err != nil {
+		logger.Error("Failed to record webhook result in Firestore", zap.String("id", id), zap.Error(err))
+		return model.Webhook{}, fmt.Errorf("failed to record webhook result: %w", err)
+	}
+
+	return webhook, nil
+}
+
+// DeleteWebhook deletes a webhook and its deliveries from Firestore with a BulkWriter
+func (r *WebhookFirestoreRepository) DeleteWebhook(id string) error {
+	_, err := r.client.Collection(webhooksCollection).Doc(id).Delete(r.ctx, firestore.Exists)
+	if status.Code(err) == codes.NotFound {
+		return fmt.Errorf("webhook with ID %s %w", id, model.ErrNotFound)
+	}
+	if err != nil {
+		logger.Error("Failed to delete webhook from Firestore", zap.String("id", id), zap.Error(err))
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+
+	refs, err := r.client.Collection(webhookDeliveriesCollection).
+		Where("webhook_id", "==", id).
+		Documents(r.ctx).GetAll()
+	if err != nil {
+		logger.Error("Failed to list webhook deliveries from Firestore", zap.String("webhook_id", id), zap.Error(err))
+		return fmt.Errorf("failed to list webhook deliveries: %w", err)
+	}
+	if len(refs) == 0 {
+		return nil
+	}
+
+	bulkWriter := r.client.BulkWriter(r.ctx)
+	jobs := make([]*firestore.BulkWriterJob, 0, len(refs))
+	for _, doc := range refs {
+		job, err := bulkWriter.Delete(doc.Ref)
+		if err != nil {
+			bulkWriter.End()
+			return fmt.Errorf("failed to delete webhook deliveries: %w", err)
+		}
+		jobs = append(jobs, job)
+	}
+	bulkWriter.End()
+
+	for _, job := range jobs {
+		if _, err := job.Results(); err != nil {
+			logger.Error("Failed to delete webhook delivery from Firestore", zap.String("webhook_id", id), zap.Error(err))
+			return fmt.Errorf("failed to delete webhook deliveries: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// CreateDelivery stores a new webhook delivery in Firestore with a generated ID
+func (r *WebhookFirestoreRepository) CreateDelivery(delivery model.WebhookDelivery) (model.WebhookDelivery, error) {
+	delivery.ID = uuid.New().String()
+	if delivery.CreatedAt.IsZero() {
+		delivery.CreatedAt = time.Now()
+	}
+
+	_, err := r.client.Collection(webhookDeliveriesCollection).Doc(delivery.ID).Set(r.ctx, toFirestoreWebhookDelivery(delivery))
+	if err != nil {
+		logger.Error("Failed to create webhook delivery in Firestore",
+			zap.String("webhook_id", delivery.WebhookID),
+			zap.Error(err))
+		return model.WebhookDelivery{}, fmt.Errorf("failed to create webhook delivery: %w", err)
+	}
+
+	return delivery, nil
+}
+
+// UpdateDelivery replaces an existing webhook delivery in Firestore
+func (r *WebhookFirestoreRepository) UpdateDelivery(delivery model.WebhookDelivery) (model.WebhookDelivery, error) {
+	_, err := r.client.Collection(webhookDeliveriesCollection).Doc(delivery.ID).
+		Update(r.ctx, []firestore.Update{
+			{Path: "status", Value: delivery.Status},
+			{Path: "attempts", Value: delivery.Attempts},
+			{Path: "next_attempt_at", Value: delivery.NextAttemptAt},
+			{Path: "last_attempt_at", Value: delivery.LastAttemptAt},
+			{Path: "response_status", Value: delivery.ResponseStatus},
+			{Path: "error", Value: delivery.Error},
+		})
+	if status.Code(err) == codes.NotFound {
+		return model.WebhookDelivery{}, fmt.Errorf("webhook delivery with ID %s %w", delivery.ID, model.ErrNotFound)
+	}
+	if err != nil {
+		logger.Error("Failed to update webhook delivery in Firestore", zap.String("id", delivery.ID), zap.Error(err))
+		return model.WebhookDelivery{}, fmt.Errorf("failed to update webhook delivery: %w", err)
+	}
+
+	return delivery, nil
+}
+
+// ListDeliveries retrieves up to limit deliveries of a webhook from Firestore, newest first
+func (r *WebhookFirestoreRepository) ListDeliveries(webhookID string, limit int) ([]model.WebhookDelivery, error) {
+	return r.listDeliveries(r.client.Collection(webhookDeliveriesCollection).
+		Where("webhook_id", "==", webhookID).
+		OrderBy("created_at", firestore.Desc).
+		Limit(limit))
+}
+
+// ListDueDeliveries retrieves up to limit pending deliveries due at now from Firestore,
+// earliest due first
+func (r *WebhookFirestoreRepository) ListDueDeliveries(now time.Time, limit int) ([]model.WebhookDelivery, error) {
+	return r.listDeliveries(r.client.Collection(webhookDeliveriesCollection).
+		Where("status", "==", model.WebhookDeliveryPending).
+		Where("next_attempt_at", "<=", now).
+		OrderBy("next_attempt_at", firestore.Asc).
+		Limit(limit))
+}
+
+func (r *WebhookFirestoreRepository) listDeliveries(query firestore.Query) ([]model.WebhookDelivery, error) {
+	iter := query.Documents(r.ctx)
+	defer iter.Stop()
+
+	deliveries := []model.WebhookDelivery{}
+	for {
+		doc, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			logger.Error("Failed to list webhook deliveries from Firestore", zap.Error(err))
+			return nil, fmt.Errorf("failed to list webhook deliveries: %w", err)
+		}
+
+		var fsDelivery firestoreWebhookDelivery
+		if err := doc.DataTo(&fsDelivery); err != nil {
+			return nil, fmt.Errorf("failed to parse webhook delivery data: %w", err)
+		}
+		deliveries = append(deliveries, toModelWebhookDelivery(fsDelivery))
+	}
+
+	return deliveries, nil
+}
+
+// ClaimDelivery postpones a pending delivery due at now to until in a transaction, so that
+// only one instance claims it
+func (r *WebhookFirestoreRepository) ClaimDelivery(id string, now, until time.Time) (model.WebhookDelivery, error) {
+	var fsDelivery firestoreWebhookDelivery
+	doc := r.client.Collection(webhookDeliveriesCollection).Doc(id)
+	err := r.client.RunTransaction(r.ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		docSnap, err := tx.Get(doc)
+		if err != nil {
+			return err
+		}
+		if err := docSnap.DataTo(&fsDelivery); err != nil {
+			return err
+		}
+		if fsDelivery.Status != model.WebhookDeliveryPending || fsDelivery.NextAttemptAt.After(now) {
+			return fmt.Errorf("webhook delivery with ID %s is not due: %w", id, model.ErrConflict)
+		}
+		fsDelivery.NextAttemptAt = until
+		return tx.Update(doc, []firestore.Update{{Path: "next_attempt_at", Value: until}})
+	})
+	if status.Code(err) == codes.NotFound {
+		return model.WebhookDelivery{}, fmt.Errorf("webhook delivery with ID %s %w", id, model.ErrNotFound)
+	}
+	if errors.Is(err, model.ErrConflict) {
+		return model.WebhookDelivery{}, err
+	}
+	if err != nil {
+		logger.Error("Failed to claim webhook delivery in Firestore", zap.String("id", id), zap.Error(err))
+		return model.WebhookDelivery{}, fmt.Errorf("failed to claim webhook delivery: %w", err)
+	}
+
+	return toModelWebhookDelivery(fsDelivery), nil
+}
@@ -0,0 +1,238 @@
+package repository
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/tsongpon/athena/internal/model"
+)
+
+// WebhookInMemRepository implements WebhookRepository interface using in-memory maps
+type WebhookInMemRepository struct {
+	webhooks   map[string]model.Webhook
+	deliveries map[string]model.WebhookDelivery
+	mutex      sync.RWMutex
+}
+
+// NewWebhookInMemRepository creates a new instance of WebhookInMemRepository
+func NewWebhookInMemRepository() *WebhookInMemRepository {
+	return &WebhookInMemRepository{
+		webhooks:   make(map[string]model.Webhook),
+		deliveries: make(map[string]model.WebhookDelivery),
+		mutex:      sync.RWMutex{},
+	}
+}
+
+// CreateWebhook stores a new webhook with a generated ID
+func (r *WebhookInMemRepository) CreateWebhook(webhook model.Webhook) (model.Webhook, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	webhook.ID = uuid.New().String()
+	if webhook.CreatedAt.IsZero() {
+		webhook.CreatedAt = time.Now()
+	}
+	webhook.UpdatedAt = webhook.CreatedAt
+	webhook.Events = slices.Clone(webhook.Events)
+
+	r.webhooks[webhook.ID] = webhook
+
+	return webhook, nil
+}
+
+// GetWebhook retrieves a webhook by its ID
+func (r *WebhookInMemRepository) GetWebhook(id string) (model.Webhook, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	webhook, exists := r.webhooks[id]
+	if !exists {
+		return model.Webhook{}, fmt.Errorf("webhook with ID %s %w", id, model.ErrNotFound)
+	}
+
+	return webhook, nil
+}
+
+// ListWebhooks retrieves the webhooks of a user, oldest first
+func (r *WebhookInMemRepository) ListWebhooks(userID string) ([]model.Webhook, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	webhooks := []model.Webhook{}
+	for _, webhook := range r.webhooks {
+		if webhook.UserID == userID {
+			webhooks = append(webhooks, webhook)
+		}
+	}
+
+	sort.Slice(webhooks, func(i, j int) bool {
+		return webhooks[i].CreatedAt.Before(webhooks[j].CreatedAt)
+	})
+
+	return webhooks, nil
+}
+
+// UpdateWebhook applies an update to an existing webhook
+func (r *WebhookInMemRepository) UpdateWebhook(id string, update model.WebhookUpdate) (model.Webhook, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	webhook, exists := r.webhooks[id]
+	if !exists {
+		return model.Webhook{}, fmt.Errorf("webhook with ID %s %w", id, model.ErrNotFound)
+	}
+	webhook.ApplyUpdate(update)
+	webhook.UpdatedAt = time.Now()
+	r.webhooks[id] = webhook
+
+	return webhook, nil
+}
+
+// RecordWebhookResult applies the outcome of a delivery attempt to a webhook
+func (r *WebhookInMemRepository) RecordWebhookResult(id string, succeeded bool, at time.Time) (model.Webhook, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	webhook, exists := r.webhooks[id]
+	if !exists {
+		return model.Webhook{}, fmt.Errorf("webhook with ID %s %w", id, model.ErrNotFound)
+	}
+	webhook.RecordResult(succeeded, at)
+	webhook.UpdatedAt = time.Now()
+	r.webhooks[id] = webhook
+
+	return webhook, nil
+}
+
+// DeleteWebhook removes a webhook and its deliveries
+func (r *WebhookInMemRepository) DeleteWebhook(id string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, exists := r.webhooks[id]; !exists {
+		return fmt.Errorf("webhook with ID %s %w", id, model.ErrNotFound)
+	}
+	delete(r.webhooks, id)
+	for deliveryID, delivery := range r.deliveries {
+		if delivery.WebhookID == id {
+			delete(r.deliveries, deliveryID)
+		}
+	}
+
+	return nil
+}
+
+// CreateDelivery stores a new delivery with a generated ID. Only the latest
+// model.MaxWebhookDeliveryLog finished deliveries of a webhook are kept.
+func (r *WebhookInMemRepository) CreateDelivery(delivery model.WebhookDelivery) (model.WebhookDelivery, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	delivery.ID = uuid.New().String()
+	if delivery.CreatedAt.IsZero() {
+		delivery.CreatedAt = time.Now()
+	}
+	r.deliveries[delivery.ID] = delivery
+	r.pruneDeliveries(delivery.WebhookID)
+
+	return delivery, nil
+}
+
+// pruneDeliveries forgets the oldest finished deliveries of a webhook beyond the delivery
+// log; the caller holds the mutex
+func (r *WebhookInMemRepository) pruneDeliveries(webhookID string) {
+	var finished []model.WebhookDelivery
+	for _, delivery := range r.deliveries {
+		if delivery.WebhookID == webhookID && delivery.Status != model.WebhookDeliveryPending {
+			finished = append(finished, delivery)
+		}
+	}
+	if len(finished) <= model.MaxWebhookDeliveryLog {
+		return
+	}
+	sort.Slice(finished, func(i, j int) bool {
+		return finished[i].CreatedAt.After(finished[j].CreatedAt)
+	})
+	for _, delivery := range finished[model.MaxWebhookDeliveryLog:] {
+		delete(r.deliveries, delivery.ID)
+	}
+}
+
+// UpdateDelivery replaces an existing delivery
+func (r *WebhookInMemRepository) UpdateDelivery(delivery model.WebhookDelivery) (model.WebhookDelivery, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, exists := r.deliveries[delivery.ID]; !exists {
+		return model.WebhookDelivery{}, fmt.Errorf("webhook delivery with ID %s %w", delivery.ID, model.ErrNotFound)
+	}
+	r.deliveries[delivery.ID] = delivery
+
+	return delivery, nil
+}
+
+// ListDeliveries retrieves up to limit deliveries of a webhook, newest first
+func (r *WebhookInMemRepository) ListDeliveries(webhookID string, limit int) ([]model.WebhookDelivery, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	deliveries := []model.WebhookDelivery{}
+	for _, delivery := range r.deliveries {
+		if delivery.WebhookID == webhookID {
+			deliveries = append(deliveries, delivery)
+		}
+	}
+
+	sort.Slice(deliveries, func(i, j int) bool {
+		return deliveries[i].CreatedAt.After(deliveries[j].CreatedAt)
+	})
+	if len(deliveries) > limit {
+		deliveries = deliveries[:limit]
+	}
+
+	return deliveries, nil
+}
+
+// ListDueDeliveries retrieves up to limit pending deliveries due at now, earliest due first
+func (r *WebhookInMemRepository) ListDueDeliveries(now time.Time, limit int) ([]model.WebhookDelivery, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	deliveries := []model.WebhookDelivery{}
+	for _, delivery := range r.deliveries {
+		if delivery.Status == model.WebhookDeliveryPending && !delivery.NextAttemptAt.After(now) {
+			deliveries = append(deliveries, delivery)
+		}
+	}
+
+	sort.Slice(deliveries, func(i, j int) bool {
+		return deliveries[i].NextAttemptAt.Before(deliveries[j].NextAttemptAt)
+	})
+	if len(deliveries) > limit {
+		deliveries = deliveries[:limit]
+	}
+
+	return deliveries, nil
+}
+
+// ClaimDelivery postpones a pending delivery due at now to until
+func (r *WebhookInMemRepository) ClaimDelivery(id string, now, until time.Time) (model.WebhookDelivery, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	delivery, exists := r.deliveries[id]
+	if !exists {
+		return model.WebhookDelivery{}, fmt.Errorf("webhook delivery with ID %s %w", id, model.ErrNotFound)
+	}
+	if delivery.Status != model.WebhookDeliveryPending || delivery.NextAttemptAt.After(now) {
+		return model.WebhookDelivery{}, fmt.Errorf("webhook delivery with ID %s is not due: %w", id, model.ErrConflict)
+	}
+	delivery.NextAttemptAt = until
+	r.deliveries[id] = delivery
+
+	return delivery, nil
+}
@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/tsongpon/athena/internal/model"
+)
+
+func TestWebhookInMemRepository_UpdateWebhook(t *testing.T) {
+	repo := NewWebhookInMemRepository()
+	webhook, _ := repo.CreateWebhook(model.Webhook{UserID: "user-1", URL: "https://hooks.example.com", Active: true})
+	repo.RecordWebhookResult(webhook.ID, false, time.Now())
+
+	// A delivery failure recorded after the owner read the webhook is kept
+	url := "https://hooks.example.com/moved"
+	updated, err := repo.UpdateWebhook(webhook.ID, model.WebhookUpdate{URL: &url})
+	if err != nil {
+		t.Fatalf("UpdateWebhook() unexpected error = %v", err)
+	}
+	if updated.URL != url || updated.ConsecutiveFailures != 1 || !updated.Active {
+		t.Errorf("UpdateWebhook() = %+v, want the new URL and 1 failure", updated)
+	}
+	if stored, _ := repo.GetWebhook(webhook.ID); stored.URL != url || stored.ConsecutiveFailures != 1 {
+		t.Errorf("GetWebhook() = %+v, want the update stored", stored)
+	}
+
+	if _, err := repo.UpdateWebhook("missing", model.WebhookUpdate{URL: &url}); !errors.Is(err, model.ErrNotFound) {
+		t.Errorf("UpdateWebhook() of a missing webhook error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestWebhookInMemRepository_Deliveries(t *testing.T) {
+	repo := NewWebhookInMemRepository()
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	webhook, _ := repo.CreateWebhook(model.Webhook{UserID: "user-1", URL: "https://hooks.example.com", Active: true})
+
+	later, _ := repo.CreateDelivery(model.WebhookDelivery{WebhookID: webhook.ID, Status: model.WebhookDeliveryPending, NextAttemptAt: now.Add(-time.Minute), CreatedAt: now.Add(-time.Minute)})
+	earlier, _ := repo.CreateDelivery(model.WebhookDelivery{WebhookID: webhook.ID, Status: model.WebhookDeliveryPending, NextAttemptAt: now.Add(-time.Hour), CreatedAt: now.Add(-time.Hour)})
+	repo.CreateDelivery(model.WebhookDelivery{WebhookID: webhook.ID, Status: model.WebhookDeliveryPending, NextAttemptAt: now.Add(time.Minute), CreatedAt: now})
+	repo.CreateDelivery(model.WebhookDelivery{WebhookID: webhook.ID, Status: model.WebhookDeliverySucceeded, NextAttemptAt: now.Add(-time.Hour), CreatedAt: now.Add(-2 * time.Hour)})
+
+	due, err := repo.ListDueDeliveries(now, 10)
+	if err != nil {
+		t.Fatalf("ListDueDeliveries() unexpected error = %v", err)
+	}
+	if len(due) != 2 || due[0].ID != earlier.ID || due[1].ID != later.ID {
+		t.Errorf("ListDueDeliveries() = %+v, want the 2 due pending deliveries, earliest first", due)
+	}
+
+	claimed, err := repo.ClaimDelivery(earlier.ID, now, now.Add(time.Minute))
+	if err != nil || !claimed.NextAttemptAt.Equal(now.Add(time.Minute)) {
+		t.Fatalf("ClaimDelivery() = %+v, %v, want the delivery postponed", claimed, err)
+	}
+	if _, err := repo.ClaimDelivery(earlier.ID, now, now.Add(time.Minute)); !errors.Is(err, model.ErrConflict) {
+		t.Errorf("ClaimDelivery() of a claimed delivery error = %v, want ErrConflict", err)
+	}
+
+	deliveries, _ := repo.ListDeliveries(webhook.ID, 3)
+	if len(deliveries) != 3 || deliveries[2].ID != earlier.ID {
+		t.Errorf("ListDeliveries() = %+v, want the latest 3, newest first", deliveries)
+	}
+
+	if err := repo.DeleteWebhook(webhook.ID); err != nil {
+		t.Fatalf("DeleteWebhook() unexpected error = %v", err)
+	}
+	if deliveries, _ := repo.ListDeliveries(webhook.ID, 10); len(deliveries) != 0 {
+		t.Errorf("ListDeliveries() after DeleteWebhook() = %d deliveries, want none", len(deliveries))
+	}
+	if _, err := repo.GetWebhook(webhook.ID); !errors.Is(err, model.ErrNotFound) {
+		t.Errorf("GetWebhook() after DeleteWebhook() error = %v, want ErrNotFound", err)
+	}
+}
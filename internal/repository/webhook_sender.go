@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/tsongpon/athena/internal/model"
+)
+
+// HTTPWebhookSender implements WebhookSender with plain HTTP POST requests. Webhook URLs are
+// chosen by users, so unless private addresses are allowed it refuses to connect to loopback,
+// private and link-local addresses, such as the cloud metadata server.
+type HTTPWebhookSender struct {
+	httpClient *http.Client
+}
+
+// NewHTTPWebhookSender creates a webhook sender. allowPrivate permits webhooks on private
+// networks, for local development.
+func NewHTTPWebhookSender(allowPrivate bool) *HTTPWebhookSender {
+	return &HTTPWebhookSender{
+		httpClient: &http.Client{
+			Timeout:   10 * time.Second,
+			Transport: newTransport(allowPrivate),
+			// Redirects are not followed: the signature is for the registered URL
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+// SendWebhook posts the request and returns the HTTP status of the response
+func (s *HTTPWebhookSender) SendWebhook(ctx context.Context, request model.WebhookRequest) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, request.URL, bytes.NewReader(request.Body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("User-Agent", "Athena-Webhook/1.0")
+	for name, value := range request.Headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	// Drain a little of the body so the connection can be reused
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/tsongpon/athena/internal/model"
+)
+
+func TestHTTPWebhookSender_SendWebhook(t *testing.T) {
+	var got *http.Request
+	var body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		got, body = r, string(data)
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+	request := model.WebhookRequest{
+		URL:     server.URL + "/hook",
+		Headers: map[string]string{"X-Athena-Event": "bookmark.created"},
+		Body:    []byte(`{"id":"e1"}`),
+	}
+
+	status, err := NewHTTPWebhookSender(true).SendWebhook(context.Background(), request)
+	if err != nil || status != http.StatusAccepted {
+		t.Fatalf("SendWebhook() = %d, %v, want 202", status, err)
+	}
+	if got.Method != http.MethodPost || got.Header.Get("X-Athena-Event") != "bookmark.created" || body != `{"id":"e1"}` {
+		t.Errorf("SendWebhook() sent %s %v %q, want the request posted as is", got.Method, got.Header, body)
+	}
+
+	// The test server listens on loopback, which webhooks cannot reach by default
+	if _, err := NewHTTPWebhookSender(false).SendWebhook(context.Background(), request); err == nil {
+		t.Error("SendWebhook() to a loopback address succeeded, want it refused")
+	}
+}
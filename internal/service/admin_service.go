@@ -0,0 +1,173 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/tsongpon/athena/internal/logger"
+	"github.com/tsongpon/athena/internal/model"
+	"go.uber.org/zap"
+)
+
+// AdminService implements user management operations available to administrators
+type AdminService struct {
+	userRepository     UserRepository
+	bookmarkRepository BookmarkRepository
+}
+
+// NewAdminService creates a new instance of AdminService
+func NewAdminService(userRepo UserRepository, bookmarkRepo BookmarkRepository) *AdminService {
+	return &AdminService{
+		userRepository:     userRepo,
+		bookmarkRepository: bookmarkRepo,
+	}
+}
+
+// ListUsers retrieves users whose email starts with search, with pagination support
+func (s *AdminService) ListUsers(search string, page, pageSize int) (model.UserListResponse, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 20
+	}
+	if pageSize > 100 {
+		pageSize = 100
+	}
+
+	query := model.UserQuery{
+		Search:   search,
+		Page:     page,
+		PageSize: pageSize,
+	}
+
+	users, err := s.userRepository.ListUsers(query)
+	if err != nil {
+		return model.UserListResponse{}, fmt.Errorf("failed to list users: %w", err)
+	}
+
+	totalCount, err := s.userRepository.CountUsers(query)
+	if err != nil {
+		return model.UserListResponse{}, fmt.Errorf("failed to count users: %w", err)
+	}
+
+	totalPages := (totalCount + pageSize - 1) / pageSize
+	if totalPages == 0 {
+		totalPages = 1
+	}
+
+	return model.UserListResponse{
+		Users:      users,
+		TotalCount: totalCount,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: totalPages,
+	}, nil
+}
+
+// GetUserStats retrieves a user together with their bookmark counts
+func (s *AdminService) GetUserStats(id string) (model.UserStats, error) {
+	user, err := s.getUser(id)
+	if err != nil {
+		return model.UserStats{}, err
+	}
+
+	active, err := s.bookmarkRepository.CountBookmarks(model.BookmarkQuery{UserID: id, Archived: false})
+	if err != nil {
+		return model.UserStats{}, fmt.Errorf("failed to count bookmarks for user %s: %w", id, err)
+	}
+	archived, err := s.bookmarkRepository.CountBookmarks(model.BookmarkQuery{UserID: id, Archived: true})
+	if err != nil {
+		return model.UserStats{}, fmt.Errorf("failed to count archived bookmarks for user %s: %w", id, err)
+	}
+
+	return model.UserStats{
+		User:          user,
+		BookmarkCount: active + archived,
+		ArchivedCount: archived,
+	}, nil
+}
+
+// SetTier changes the tier of a user
+func (s *AdminService) SetTier(id, tier string) (model.User, error) {
+	if !model.IsValidTier(tier) {
+		return model.User{}, fmt.Errorf("unknown tier %q: %w", tier, model.ErrInvalidInput)
+	}
+
+	return s.updateUser(id, func(u *model.User) {
+		u.Tier = tier
+	})
+}
+
+// SetRole changes the role of a user. Admins cannot change their own role so
+// that the last admin cannot lock everyone out by accident.
+func (s *AdminService) SetRole(actorID, id, role string) (model.User, error) {
+	if !model.IsValidRole(role) {
+		return model.User{}, fmt.Errorf("unknown role %q: %w", role, model.ErrInvalidInput)
+	}
+	if actorID == id {
+		return model.User{}, fmt.Errorf("admins cannot change their own role: %w", model.ErrForbidden)
+	}
+
+	return s.updateUser(id, func(u *model.User) {
+		u.Role = role
+	})
+}
+
+// SetDisabled disables or enables a user account. Disabling an account also
+// revokes every token issued to it.
+func (s *AdminService) SetDisabled(actorID, id string, disabled bool) (model.User, error) {
+	if actorID == id {
+		return model.User{}, fmt.Errorf("admins cannot disable or enable their own account: %w", model.ErrForbidden)
+	}
+
+	return s.updateUser(id, func(u *model.User) {
+		u.Disabled = disabled
+		if disabled {
+			u.TokensRevokedAt = time.Now().Truncate(time.Second)
+		}
+	})
+}
+
+// ForceLogout revokes every token issued to a user up to now. The time is kept to the second,
+// the precision of a token's issue time.
+func (s *AdminService) ForceLogout(id string) (model.User, error) {
+	return s.updateUser(id, func(u *model.User) {
+		u.TokensRevokedAt = time.Now().Truncate(time.Second)
+	})
+}
+
+func (s *AdminService) getUser(id string) (model.User, error) {
+	if id == "" {
+		return model.User{}, fmt.Errorf("id is required: %w", model.ErrInvalidInput)
+	}
+	user, err := s.userRepository.GetUserByID(id)
+	if err != nil {
+		return model.User{}, fmt.Errorf("failed to get user with ID %s: %w", id, err)
+	}
+	return user, nil
+}
+
+// updateUser applies mutate to a user and persists the result atomically, so that it cannot
+// undo a concurrent change such as a billing event
+func (s *AdminService) updateUser(id string, mutate func(u *model.User)) (model.User, error) {
+	if id == "" {
+		return model.User{}, fmt.Errorf("id is required: %w", model.ErrInvalidInput)
+	}
+
+	updated, err := s.userRepository.ModifyUser(id, func(u *model.User) error {
+		mutate(u)
+		return nil
+	})
+	if err != nil {
+		return model.User{}, fmt.Errorf("failed to update user with ID %s: %w", id, err)
+	}
+
+	logger.Info("Admin updated user",
+		zap.String("user_id", updated.ID),
+		zap.String("tier", updated.Tier),
+		zap.String("role", updated.Role),
+		zap.Bool("disabled", updated.Disabled))
+
+	return updated, nil
+}
@@ -0,0 +1,165 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/tsongpon/athena/internal/model"
+)
+
+func TestAdminService_ListUsers(t *testing.T) {
+	var capturedQuery model.UserQuery
+	mockUserRepo := &MockUserRepository{
+		listUsersFunc: func(query model.UserQuery) ([]model.User, error) {
+			capturedQuery = query
+			return []model.User{{ID: "user-1", Email: "alice@example.com"}}, nil
+		},
+		countUsersFunc: func(query model.UserQuery) (int, error) {
+			return 41, nil
+		},
+	}
+
+	service := NewAdminService(mockUserRepo, &MockBookmarkRepository{})
+	result, err := service.ListUsers("alice", 0, 500)
+	if err != nil {
+		t.Fatalf("ListUsers() unexpected error = %v", err)
+	}
+
+	if capturedQuery.Search != "alice" {
+		t.Errorf("ListUsers() query Search = %v, want alice", capturedQuery.Search)
+	}
+	if result.Page != 1 {
+		t.Errorf("ListUsers() Page = %v, want 1", result.Page)
+	}
+	if result.PageSize != 100 {
+		t.Errorf("ListUsers() PageSize = %v, want 100", result.PageSize)
+	}
+	if result.TotalPages != 1 {
+		t.Errorf("ListUsers() TotalPages = %v, want 1", result.TotalPages)
+	}
+	if len(result.Users) != 1 {
+		t.Errorf("ListUsers() returned %d users, want 1", len(result.Users))
+	}
+}
+
+func TestAdminService_GetUserStats(t *testing.T) {
+	mockUserRepo := &MockUserRepository{
+		getUserByIDFunc: func(id string) (model.User, error) {
+			return model.User{ID: id}, nil
+		},
+	}
+	mockBookmarkRepo := &MockBookmarkRepository{
+		countBookmarksFunc: func(query model.BookmarkQuery) (int, error) {
+			if query.Archived {
+				return 2, nil
+			}
+			return 5, nil
+		},
+	}
+
+	service := NewAdminService(mockUserRepo, mockBookmarkRepo)
+	stats, err := service.GetUserStats("user-1")
+	if err != nil {
+		t.Fatalf("GetUserStats() unexpected error = %v", err)
+	}
+	if stats.BookmarkCount != 7 {
+		t.Errorf("GetUserStats() BookmarkCount = %v, want 7", stats.BookmarkCount)
+	}
+	if stats.ArchivedCount != 2 {
+		t.Errorf("GetUserStats() ArchivedCount = %v, want 2", stats.ArchivedCount)
+	}
+}
+
+func TestAdminService_GetUserStats_NotFound(t *testing.T) {
+	mockUserRepo := &MockUserRepository{
+		getUserByIDFunc: func(id string) (model.User, error) {
+			return model.User{}, model.ErrNotFound
+		},
+	}
+
+	service := NewAdminService(mockUserRepo, &MockBookmarkRepository{})
+	_, err := service.GetUserStats("missing")
+	if !errors.Is(err, model.ErrNotFound) {
+		t.Errorf("GetUserStats() error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestAdminService_SetTier(t *testing.T) {
+	var saved model.User
+	mockUserRepo := &MockUserRepository{
+		getUserByIDFunc: func(id string) (model.User, error) {
+			return model.User{ID: id, Tier: model.TierFree}, nil
+		},
+		updateUserFunc: func(user model.User) (model.User, error) {
+			saved = user
+			return user, nil
+		},
+	}
+
+	service := NewAdminService(mockUserRepo, &MockBookmarkRepository{})
+	updated, err := service.SetTier("user-1", model.TierPaid)
+	if err != nil {
+		t.Fatalf("SetTier() unexpected error = %v", err)
+	}
+	if updated.Tier != model.TierPaid || saved.Tier != model.TierPaid {
+		t.Errorf("SetTier() tier = %v, want %v", updated.Tier, model.TierPaid)
+	}
+}
+
+func TestAdminService_SetTier_InvalidTier(t *testing.T) {
+	service := NewAdminService(&MockUserRepository{}, &MockBookmarkRepository{})
+	_, err := service.SetTier("user-1", "platinum")
+	if !errors.Is(err, model.ErrInvalidInput) {
+		t.Errorf("SetTier() error = %v, want ErrInvalidInput", err)
+	}
+}
+
+func TestAdminService_SetRole_Self(t *testing.T) {
+	service := NewAdminService(&MockUserRepository{}, &MockBookmarkRepository{})
+	_, err := service.SetRole("admin-1", "admin-1", model.RoleUser)
+	if !errors.Is(err, model.ErrForbidden) {
+		t.Errorf("SetRole() error = %v, want ErrForbidden", err)
+	}
+}
+
+func TestAdminService_SetDisabled_RevokesTokens(t *testing.T) {
+	mockUserRepo := &MockUserRepository{
+		getUserByIDFunc: func(id string) (model.User, error) {
+			return model.User{ID: id}, nil
+		},
+	}
+
+	service := NewAdminService(mockUserRepo, &MockBookmarkRepository{})
+	updated, err := service.SetDisabled("admin-1", "user-1", true)
+	if err != nil {
+		t.Fatalf("SetDisabled() unexpected error = %v", err)
+	}
+	if !updated.Disabled {
+		t.Error("SetDisabled() user should be disabled")
+	}
+	if updated.TokensRevokedAt.IsZero() {
+		t.Error("SetDisabled() should revoke existing tokens")
+	}
+
+	_, err = service.SetDisabled("admin-1", "admin-1", true)
+	if !errors.Is(err, model.ErrForbidden) {
+		t.Errorf("SetDisabled() on self error = %v, want ErrForbidden", err)
+	}
+}
+
+func TestAdminService_ForceLogout(t *testing.T) {
+	mockUserRepo := &MockUserRepository{
+		getUserByIDFunc: func(id string) (model.User, error) {
+			return model.User{ID: id}, nil
+		},
+	}
+
+	service := NewAdminService(mockUserRepo, &MockBookmarkRepository{})
+	updated, err := service.ForceLogout("user-1")
+	if err != nil {
+		t.Fatalf("ForceLogout() unexpected error = %v", err)
+	}
+	if updated.TokensRevokedAt.IsZero() {
+		t.Error("ForceLogout() should set TokensRevokedAt")
+	}
+}
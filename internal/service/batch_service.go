@@ -0,0 +1,306 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/tsongpon/athena/internal/logger"
+	"github.com/tsongpon/athena/internal/model"
+	"go.uber.org/zap"
+)
+
+// BatchService applies many bookmark changes in one request. Bookmarks are read and written
+// in bulk rather than with a round trip each, and every operation gets its own result so
+// one bad ID does not fail the rest.
+type BatchService struct {
+	bookmarkRepository   BookmarkRepository
+	collectionRepository CollectionRepository
+	activityRepository   ActivityRepository
+	policy               *Policy
+	events               *EventHub
+}
+
+// NewBatchService creates a new instance of BatchService
+func NewBatchService(bookmarkRepo BookmarkRepository, collectionRepo CollectionRepository, activityRepo ActivityRepository, policy *Policy, events *EventHub) *BatchService {
+	return &BatchService{
+		bookmarkRepository:   bookmarkRepo,
+		collectionRepository: collectionRepo,
+		activityRepository:   activityRepo,
+		policy:               policy,
+		events:               events,
+	}
+}
+
+// batchedBookmark is a bookmark changed by a batch, with the operations that changed it
+type batchedBookmark struct {
+	original model.Bookmark
+	bookmark model.Bookmark
+	trashed  bool
+	ops      []int // Indexes of the operations applied to the bookmark
+}
+
+// ApplyBatch applies ops on behalf of userID and returns the result of each, in order.
+// Operations on the same bookmark are combined into a single write, so they succeed or
+// fail together. Bookmarks that do not exist or belong to someone else are reported as
+// not found.
+func (s *BatchService) ApplyBatch(userID string, ops []model.BatchOperation) ([]model.BatchResult, error) {
+	if len(ops) == 0 || len(ops) > model.MaxBatchOperations {
+		return nil, fmt.Errorf("between 1 and %d operations are required: %w", model.MaxBatchOperations, model.ErrInvalidInput)
+	}
+
+	results := make([]model.BatchResult, len(ops))
+	var ids []string
+	for i, op := range ops {
+		results[i] = model.BatchResult{Op: op.Op, BookmarkID: op.BookmarkID}
+		if err := validateBatchOperation(op); err != nil {
+			results[i].Status, results[i].Error = model.BatchStatusInvalid, err.Error()
+			continue
+		}
+		if !slices.Contains(ids, op.BookmarkID) {
+			ids = append(ids, op.BookmarkID)
+		}
+	}
+
+	bookmarks, err := s.bookmarkRepository.GetBookmarks(ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bookmarks: %w", err)
+	}
+	batched := make(map[string]*batchedBookmark, len(bookmarks))
+	for _, b := range bookmarks {
+		if s.policy.CanModifyBookmark(userID, b) == nil {
+			batched[b.ID] = &batchedBookmark{original: b, bookmark: b}
+		}
+	}
+
+	collections := make(map[string]*model.Collection)
+	for i, op := range ops {
+		if results[i].Status != "" {
+			continue
+		}
+		bb, ok := batched[op.BookmarkID]
+		if !ok || bb.trashed {
+			results[i].Status, results[i].Error = model.BatchStatusNotFound, "bookmark not found"
+			continue
+		}
+		if err := s.apply(userID, bb, op, collections); err != nil {
+			results[i].Status, results[i].Error = batchStatus(err), err.Error()
+			continue
+		}
+		bb.ops = append(bb.ops, i)
+	}
+
+	// One write per changed bookmark, in the order of the operations
+	var writes []model.BookmarkWrite
+	var written []*batchedBookmark
+	now := time.Now()
+	for _, id := range ids {
+		if bb, ok := batched[id]; ok && len(bb.ops) > 0 {
+			if bb.trashed {
+				bb.bookmark.DeletedAt = now
+			}
+			writes = append(writes, model.BookmarkWrite{Bookmark: bb.bookmark, Trash: bb.trashed})
+			written = append(written, bb)
+		}
+	}
+	var stored []*batchedBookmark
+	for i, err := range s.bookmarkRepository.WriteBookmarks(writes) {
+		bb := written[i]
+		if err != nil {
+			logger.Error("Failed to write bookmark of a batch", zap.String("bookmark_id", bb.bookmark.ID), zap.Error(err))
+			for _, op := range bb.ops {
+				results[op].Status, results[op].Error = batchStatus(err), "failed to save bookmark"
+			}
+			continue
+		}
+		for _, op := range bb.ops {
+			results[op].Status = model.BatchStatusOK
+		}
+		stored = append(stored, bb)
+		switch {
+		case bb.trashed:
+			s.events.Publish(model.EventBookmarkDeleted, bb.bookmark)
+		case bb.bookmark.IsArchived && !bb.original.IsArchived:
+			s.events.Publish(model.EventBookmarkArchived, bb.bookmark)
+		default:
+			s.events.Publish(model.EventBookmarkUpdated, bb.bookmark)
+		}
+	}
+
+	s.updateCollections(userID, stored, collections, results)
+
+	failed := 0
+	for _, result := range results {
+		if result.Status != model.BatchStatusOK {
+			failed++
+		}
+	}
+	logger.Info("Applied bookmark batch",
+		zap.String("user_id", userID),
+		zap.Int("operations", len(ops)),
+		zap.Int("bookmarks", len(writes)),
+		zap.Int("failed", failed))
+	return results, nil
+}
+
+// validateBatchOperation checks that op is complete, before any bookmark is read
+func validateBatchOperation(op model.BatchOperation) error {
+	if op.BookmarkID == "" {
+		return errors.New("bookmark_id is required")
+	}
+	switch op.Op {
+	case model.BatchOpArchive, model.BatchOpUnarchive, model.BatchOpDelete:
+		return nil
+	case model.BatchOpTag, model.BatchOpUntag:
+		if len(model.NormalizeTags(op.Tags)) == 0 {
+			return errors.New("tags are required")
+		}
+		return nil
+	case model.BatchOpMove:
+		if op.CollectionID == "" {
+			return errors.New("collection_id is required")
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown operation %q", op.Op)
+	}
+}
+
+// apply makes the change of op to the batched copy of a bookmark
+func (s *BatchService) apply(userID string, bb *batchedBookmark, op model.BatchOperation, collections map[string]*model.Collection) error {
+	b := &bb.bookmark
+	switch op.Op {
+	case model.BatchOpArchive:
+		b.IsArchived = true
+	case model.BatchOpUnarchive:
+		b.IsArchived = false
+	case model.BatchOpDelete:
+		bb.trashed = true
+	case model.BatchOpTag:
+		tags := model.NormalizeTags(append(slices.Clone(b.Tags), op.Tags...))
+		if len(tags) > model.MaxBookmarkTags {
+			return fmt.Errorf("a bookmark can have at most %d tags: %w", model.MaxBookmarkTags, model.ErrInvalidInput)
+		}
+		b.Tags = tags
+	case model.BatchOpUntag:
+		remove := model.NormalizeTags(op.Tags)
+		b.Tags = slices.DeleteFunc(slices.Clone(b.Tags), func(tag string) bool { return slices.Contains(remove, tag) })
+	case model.BatchOpMove:
+		// The bookmark leaves every collection it is in, so the user must be able to edit them all
+		target, err := s.editableCollection(userID, op.CollectionID, collections)
+		if err != nil {
+			return err
+		}
+		for _, id := range b.CollectionIDs {
+			if id == target.ID {
+				continue
+			}
+			if _, err := s.editableCollection(userID, id, collections); err != nil && !errors.Is(err, model.ErrNotFound) {
+				return err
+			}
+		}
+		b.CollectionIDs = []string{target.ID}
+	}
+	return nil
+}
+
+// editableCollection loads a collection whose bookmarks userID may change, once per batch. The
+// copy is only used for permission checks; updateCollections reads it again to write it.
+func (s *BatchService) editableCollection(userID, id string, collections map[string]*model.Collection) (*model.Collection, error) {
+	c, ok := collections[id]
+	if !ok {
+		loaded, err := s.collectionRepository.GetCollection(id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get collection with ID %s: %w", id, err)
+		}
+		c = &loaded
+		collections[id] = c
+	}
+	if err := s.policy.CanEditCollectionBookmarks(userID, *c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// updateCollections brings the bookmark lists of collections in line with the collection
+// IDs of the stored bookmarks that moved. Trashed bookmarks stay listed in their collections
+// so that restoring them puts them back. Moves into a collection that cannot be updated are
+// reported as failed.
+func (s *BatchService) updateCollections(userID string, stored []*batchedBookmark, collections map[string]*model.Collection, results []model.BatchResult) {
+	added := make(map[string][]*batchedBookmark)
+	removed := make(map[string][]string)
+	for _, bb := range stored {
+		for _, id := range bb.bookmark.CollectionIDs {
+			if !slices.Contains(bb.original.CollectionIDs, id) {
+				added[id] = append(added[id], bb)
+			}
+		}
+		for _, id := range bb.original.CollectionIDs {
+			if !slices.Contains(bb.bookmark.CollectionIDs, id) {
+				removed[id] = append(removed[id], bb.bookmark.ID)
+			}
+		}
+	}
+
+	for _, id := range slices.Sorted(maps.Keys(collections)) {
+		if len(added[id]) == 0 && len(removed[id]) == 0 {
+			continue
+		}
+		// The collection is read again, so that changes made to it during the batch are kept
+		_, err := updateCollection(s.collectionRepository, id, func(c *model.Collection) error {
+			if err := s.policy.CanEditCollectionBookmarks(userID, *c); err != nil {
+				return err
+			}
+			c.BookmarkIDs = slices.DeleteFunc(slices.Clone(c.BookmarkIDs), func(bookmarkID string) bool {
+				return slices.Contains(removed[id], bookmarkID)
+			})
+			c.AddedBy = maps.Clone(c.AddedBy)
+			if c.AddedBy == nil {
+				c.AddedBy = make(map[string]string)
+			}
+			for _, bookmarkID := range removed[id] {
+				delete(c.AddedBy, bookmarkID)
+			}
+			for _, bb := range added[id] {
+				if !slices.Contains(c.BookmarkIDs, bb.bookmark.ID) {
+					c.BookmarkIDs = append(c.BookmarkIDs, bb.bookmark.ID)
+					c.AddedBy[bb.bookmark.ID] = userID
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			logger.Error("Failed to update collection of a batch", zap.String("collection_id", id), zap.Error(err))
+			for _, bb := range added[id] {
+				for _, op := range bb.ops {
+					if results[op].Op == model.BatchOpMove {
+						results[op].Status, results[op].Error = model.BatchStatusFailed, "failed to update collection"
+					}
+				}
+			}
+			continue
+		}
+		for _, bookmarkID := range removed[id] {
+			recordActivity(s.activityRepository, model.Activity{CollectionID: id, ActorID: userID, Action: model.ActivityBookmarkRemoved, BookmarkID: bookmarkID})
+		}
+		for _, bb := range added[id] {
+			recordActivity(s.activityRepository, model.Activity{CollectionID: id, ActorID: userID, Action: model.ActivityBookmarkAdded, BookmarkID: bb.bookmark.ID})
+		}
+	}
+}
+
+// batchStatus maps the error of an operation to its status
+func batchStatus(err error) string {
+	switch {
+	case errors.Is(err, model.ErrInvalidInput):
+		return model.BatchStatusInvalid
+	case errors.Is(err, model.ErrNotFound):
+		return model.BatchStatusNotFound
+	case errors.Is(err, model.ErrForbidden):
+		return model.BatchStatusForbidden
+	default:
+		return model.BatchStatusFailed
+	}
+}
@@ -0,0 +1,264 @@
+package service
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/tsongpon/athena/internal/model"
+)
+
+func batchStatuses(results []model.BatchResult) []string {
+	statuses := make([]string, len(results))
+	for i, result := range results {
+		statuses[i] = result.Status
+	}
+	return statuses
+}
+
+func TestBatchService_ApplyBatch(t *testing.T) {
+	bookmarks := []model.Bookmark{
+		{ID: "b1", UserID: "user-1", Tags: []string{"go"}},
+		{ID: "b2", UserID: "user-1", IsArchived: true},
+		{ID: "b3", UserID: "user-1"},
+		{ID: "foreign", UserID: "user-2"},
+	}
+	var writes []model.BookmarkWrite
+	bookmarkRepo := &MockBookmarkRepository{
+		getBookmarksFunc: func(ids []string) ([]model.Bookmark, error) {
+			var found []model.Bookmark
+			for _, b := range bookmarks {
+				if slices.Contains(ids, b.ID) {
+					found = append(found, b)
+				}
+			}
+			return found, nil
+		},
+		writeBookmarksFunc: func(w []model.BookmarkWrite) []error {
+			writes = append(writes, w...)
+			return make([]error, len(w))
+		},
+	}
+	collectionRepo := &MockCollectionRepository{}
+	service := NewBatchService(bookmarkRepo, collectionRepo, &MockActivityRepository{}, NewPolicy(collectionRepo), nil)
+
+	results, err := service.ApplyBatch("user-1", []model.BatchOperation{
+		{Op: model.BatchOpArchive, BookmarkID: "b1"},
+		{Op: model.BatchOpTag, BookmarkID: "b1", Tags: []string{"Reading", "go"}},
+		{Op: model.BatchOpUnarchive, BookmarkID: "b2"},
+		{Op: model.BatchOpUntag, BookmarkID: "b1", Tags: []string{"go"}},
+		{Op: model.BatchOpDelete, BookmarkID: "b3"},
+		{Op: model.BatchOpArchive, BookmarkID: "b3"},
+		{Op: model.BatchOpArchive, BookmarkID: "foreign"},
+		{Op: model.BatchOpArchive, BookmarkID: "missing"},
+		{Op: "rename", BookmarkID: "b1"},
+		{Op: model.BatchOpTag, BookmarkID: "b2"},
+		{Op: model.BatchOpMove, BookmarkID: "b2"},
+	})
+	if err != nil {
+		t.Fatalf("ApplyBatch() unexpected error = %v", err)
+	}
+	want := []string{
+		model.BatchStatusOK, model.BatchStatusOK, model.BatchStatusOK, model.BatchStatusOK, model.BatchStatusOK,
+		model.BatchStatusNotFound, // Archiving a bookmark deleted earlier in the batch
+		model.BatchStatusNotFound, model.BatchStatusNotFound,
+		model.BatchStatusInvalid, model.BatchStatusInvalid, model.BatchStatusInvalid,
+	}
+	if got := batchStatuses(results); !slices.Equal(got, want) {
+		t.Errorf("ApplyBatch() statuses = %v, want %v", got, want)
+	}
+	if results[6].BookmarkID != "foreign" || results[6].Op != model.BatchOpArchive {
+		t.Errorf("ApplyBatch() result = %+v, want the operation it answers", results[6])
+	}
+
+	// One write per bookmark, combining its operations
+	if len(writes) != 3 {
+		t.Fatalf("ApplyBatch() wrote %d bookmarks, want 3", len(writes))
+	}
+	b1, b2, b3 := writes[0], writes[1], writes[2]
+	if b1.Bookmark.ID != "b1" || !b1.Bookmark.IsArchived || !slices.Equal(b1.Bookmark.Tags, []string{"reading"}) || b1.Trash {
+		t.Errorf("write of b1 = %+v, want archived with tags [reading]", b1)
+	}
+	if b2.Bookmark.ID != "b2" || b2.Bookmark.IsArchived {
+		t.Errorf("write of b2 = %+v, want unarchived", b2)
+	}
+	if b3.Bookmark.ID != "b3" || !b3.Trash || b3.Bookmark.DeletedAt.IsZero() {
+		t.Errorf("write of b3 = %+v, want it moved to the trash", b3)
+	}
+}
+
+func TestBatchService_ApplyBatch_InvalidSize(t *testing.T) {
+	collectionRepo := &MockCollectionRepository{}
+	service := NewBatchService(&MockBookmarkRepository{}, collectionRepo, &MockActivityRepository{}, NewPolicy(collectionRepo), nil)
+
+	if _, err := service.ApplyBatch("user-1", nil); !errors.Is(err, model.ErrInvalidInput) {
+		t.Errorf("ApplyBatch() empty error = %v, want ErrInvalidInput", err)
+	}
+	ops := make([]model.BatchOperation, model.MaxBatchOperations+1)
+	if _, err := service.ApplyBatch("user-1", ops); !errors.Is(err, model.ErrInvalidInput) {
+		t.Errorf("ApplyBatch() oversized error = %v, want ErrInvalidInput", err)
+	}
+}
+
+func TestBatchService_ApplyBatch_Move(t *testing.T) {
+	bookmarks := []model.Bookmark{
+		{ID: "b1", UserID: "user-1", CollectionIDs: []string{"from"}},
+		{ID: "b2", UserID: "user-1", CollectionIDs: []string{"shared"}},
+		{ID: "b3", UserID: "user-1"},
+	}
+	collections := []model.Collection{
+		{ID: "from", UserID: "user-1", BookmarkIDs: []string{"b1", "other"}, AddedBy: map[string]string{"b1": "user-1"}},
+		{ID: "to", UserID: "user-1", BookmarkIDs: []string{"old"}},
+		{ID: "shared", UserID: "user-2", BookmarkIDs: []string{"b2"},
+			Collaborators: []model.Collaborator{{UserID: "user-1", Role: model.CollectionRoleViewer}}},
+	}
+	var writes []model.BookmarkWrite
+	bookmarkRepo := &MockBookmarkRepository{
+		getBookmarksFunc: func(ids []string) ([]model.Bookmark, error) {
+			var found []model.Bookmark
+			for _, b := range bookmarks {
+				if slices.Contains(ids, b.ID) {
+					found = append(found, b)
+				}
+			}
+			return found, nil
+		},
+		writeBookmarksFunc: func(w []model.BookmarkWrite) []error {
+			writes = append(writes, w...)
+			return make([]error, len(w))
+		},
+	}
+	updated := make(map[string]model.Collection)
+	collectionRepo := &MockCollectionRepository{
+		getCollectionFunc: collectionsByID(collections...),
+		updateCollectionFunc: func(c model.Collection) (model.Collection, error) {
+			updated[c.ID] = c
+			return c, nil
+		},
+	}
+	activityRepo := &MockActivityRepository{}
+	service := NewBatchService(bookmarkRepo, collectionRepo, activityRepo, NewPolicy(collectionRepo), nil)
+
+	results, err := service.ApplyBatch("user-1", []model.BatchOperation{
+		{Op: model.BatchOpMove, BookmarkID: "b1", CollectionID: "to"},
+		{Op: model.BatchOpMove, BookmarkID: "b2", CollectionID: "to"},      // Viewers cannot take bookmarks out of a collection
+		{Op: model.BatchOpMove, BookmarkID: "b3", CollectionID: "shared"},  // Nor add them
+		{Op: model.BatchOpMove, BookmarkID: "b3", CollectionID: "missing"}, // The collection does not exist
+		{Op: model.BatchOpMove, BookmarkID: "b3", CollectionID: "to"},
+	})
+	if err != nil {
+		t.Fatalf("ApplyBatch() unexpected error = %v", err)
+	}
+	want := []string{model.BatchStatusOK, model.BatchStatusForbidden, model.BatchStatusForbidden, model.BatchStatusNotFound, model.BatchStatusOK}
+	if got := batchStatuses(results); !slices.Equal(got, want) {
+		t.Errorf("ApplyBatch() statuses = %v, want %v", got, want)
+	}
+
+	if len(writes) != 2 || !slices.Equal(writes[0].Bookmark.CollectionIDs, []string{"to"}) || !slices.Equal(writes[1].Bookmark.CollectionIDs, []string{"to"}) {
+		t.Errorf("ApplyBatch() writes = %+v, want b1 and b3 in [to]", writes)
+	}
+	if got := updated["from"]; !slices.Equal(got.BookmarkIDs, []string{"other"}) || got.AddedBy["b1"] != "" {
+		t.Errorf("source collection = %+v, want b1 removed", got)
+	}
+	if got := updated["to"]; !slices.Equal(got.BookmarkIDs, []string{"old", "b1", "b3"}) || got.AddedBy["b3"] != "user-1" {
+		t.Errorf("target collection = %+v, want b1 and b3 added by user-1", got)
+	}
+	if _, ok := updated["shared"]; ok {
+		t.Errorf("shared collection was updated, want it untouched")
+	}
+	if len(activityRepo.activities) != 3 {
+		t.Errorf("ApplyBatch() recorded %d activities, want 3", len(activityRepo.activities))
+	}
+}
+
+func TestBatchService_ApplyBatch_MoveKeepsConcurrentChanges(t *testing.T) {
+	stored := model.Collection{ID: "to", UserID: "user-1", BookmarkIDs: []string{"old"}, Version: 1}
+	bookmarkRepo := &MockBookmarkRepository{
+		getBookmarksFunc: func(ids []string) ([]model.Bookmark, error) {
+			return []model.Bookmark{{ID: "b1", UserID: "user-1"}}, nil
+		},
+		// Another bookmark is added to the collection while the batch is written
+		writeBookmarksFunc: func(writes []model.BookmarkWrite) []error {
+			stored.BookmarkIDs = append(slices.Clone(stored.BookmarkIDs), "concurrent")
+			stored.Version++
+			return make([]error, len(writes))
+		},
+	}
+	collectionRepo := &MockCollectionRepository{
+		getCollectionFunc: func(id string) (model.Collection, error) {
+			return stored, nil
+		},
+		updateCollectionFunc: func(c model.Collection) (model.Collection, error) {
+			if c.Version != stored.Version {
+				return model.Collection{}, model.ErrConflict
+			}
+			c.Version++
+			stored = c
+			return c, nil
+		},
+	}
+	service := NewBatchService(bookmarkRepo, collectionRepo, &MockActivityRepository{}, NewPolicy(collectionRepo), nil)
+
+	results, err := service.ApplyBatch("user-1", []model.BatchOperation{
+		{Op: model.BatchOpMove, BookmarkID: "b1", CollectionID: "to"},
+	})
+	if err != nil {
+		t.Fatalf("ApplyBatch() unexpected error = %v", err)
+	}
+	if results[0].Status != model.BatchStatusOK {
+		t.Errorf("ApplyBatch() status = %q, want %q", results[0].Status, model.BatchStatusOK)
+	}
+	if !slices.Equal(stored.BookmarkIDs, []string{"old", "concurrent", "b1"}) {
+		t.Errorf("collection bookmarks = %v, want the concurrent addition kept", stored.BookmarkIDs)
+	}
+}
+
+func TestBatchService_ApplyBatch_PartialWriteFailure(t *testing.T) {
+	bookmarks := []model.Bookmark{
+		{ID: "b1", UserID: "user-1"},
+		{ID: "b2", UserID: "user-1"},
+		{ID: "b3", UserID: "user-1"},
+	}
+	bookmarkRepo := &MockBookmarkRepository{
+		getBookmarksFunc: func(ids []string) ([]model.Bookmark, error) {
+			var found []model.Bookmark
+			for _, b := range bookmarks {
+				if slices.Contains(ids, b.ID) {
+					found = append(found, b)
+				}
+			}
+			return found, nil
+		},
+		writeBookmarksFunc: func(writes []model.BookmarkWrite) []error {
+			errs := make([]error, len(writes))
+			for i, w := range writes {
+				switch w.Bookmark.ID {
+				case "b2":
+					errs[i] = errors.New("deadline exceeded")
+				case "b3":
+					errs[i] = model.ErrNotFound // Deleted since it was read
+				}
+			}
+			return errs
+		},
+	}
+	collectionRepo := &MockCollectionRepository{}
+	service := NewBatchService(bookmarkRepo, collectionRepo, &MockActivityRepository{}, NewPolicy(collectionRepo), nil)
+
+	results, err := service.ApplyBatch("user-1", []model.BatchOperation{
+		{Op: model.BatchOpArchive, BookmarkID: "b1"},
+		{Op: model.BatchOpArchive, BookmarkID: "b2"},
+		{Op: model.BatchOpTag, BookmarkID: "b2", Tags: []string{"later"}},
+		{Op: model.BatchOpDelete, BookmarkID: "b3"},
+	})
+	if err != nil {
+		t.Fatalf("ApplyBatch() unexpected error = %v", err)
+	}
+	want := []string{model.BatchStatusOK, model.BatchStatusFailed, model.BatchStatusFailed, model.BatchStatusNotFound}
+	if got := batchStatuses(results); !slices.Equal(got, want) {
+		t.Errorf("ApplyBatch() statuses = %v, want %v", got, want)
+	}
+	if results[1].Error == "" {
+		t.Errorf("ApplyBatch() failed result has no error")
+	}
+}
@@ -0,0 +1,134 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/tsongpon/athena/internal/logger"
+	"github.com/tsongpon/athena/internal/model"
+	"go.uber.org/zap"
+)
+
+// BillingService applies subscription events from a billing provider to user tiers
+type BillingService struct {
+	provider        BillingProvider
+	userRepository  UserRepository
+	eventRepository BillingEventRepository
+}
+
+// NewBillingService creates a new instance of BillingService
+func NewBillingService(provider BillingProvider, userRepo UserRepository, eventRepo BillingEventRepository) *BillingService {
+	return &BillingService{
+		provider:        provider,
+		userRepository:  userRepo,
+		eventRepository: eventRepo,
+	}
+}
+
+// SignatureHeader returns the HTTP header the provider sends the webhook signature in
+func (s *BillingService) SignatureHeader() string {
+	return s.provider.SignatureHeader()
+}
+
+// HandleWebhook verifies a webhook payload and applies it to the user's tier.
+// Redelivered events are skipped, so providers can safely retry.
+func (s *BillingService) HandleWebhook(payload []byte, signature string) error {
+	event, err := s.provider.ParseWebhook(payload, signature)
+	if err != nil {
+		return err
+	}
+	if event.ID == "" {
+		return fmt.Errorf("webhook event has no ID: %w", model.ErrInvalidInput)
+	}
+
+	processed, err := s.eventRepository.IsEventProcessed(event.Provider, event.ID)
+	if err != nil {
+		return fmt.Errorf("failed to check billing event %s: %w", event.ID, err)
+	}
+	if processed {
+		logger.Info("Skipping already processed billing event",
+			zap.String("provider", event.Provider),
+			zap.String("event_id", event.ID))
+		return nil
+	}
+
+	if err := s.applyEvent(event); err != nil {
+		return err
+	}
+
+	if err := s.eventRepository.MarkEventProcessed(event); err != nil {
+		return fmt.Errorf("failed to mark billing event %s as processed: %w", event.ID, err)
+	}
+
+	return nil
+}
+
+// errStaleBillingEvent stops applying an event older than the last one applied to the user
+var errStaleBillingEvent = errors.New("billing event is older than the last applied one")
+
+// applyEvent updates the user's tier according to the subscription state in event. Providers
+// do not deliver events in order, so an event that occurred before the last applied one is
+// stale and ignored; events from the same instant are applied in delivery order.
+func (s *BillingService) applyEvent(event model.BillingEvent) error {
+	if event.Type == "" {
+		logger.Debug("Ignoring unsupported billing event", zap.String("event_id", event.ID))
+		return nil
+	}
+	if event.UserID == "" {
+		// Nothing to apply; retrying will not help, so the event is still marked processed
+		logger.Warn("Billing event without user ID",
+			zap.String("event_id", event.ID),
+			zap.String("subscription_id", event.SubscriptionID))
+		return nil
+	}
+
+	tier := model.TierFree
+	if event.GrantsPaidTier() {
+		tier = model.TierPaid
+	}
+	// The user is changed atomically, so that an admin changing them at the same time cannot
+	// undo the event
+	var lastAppliedAt time.Time
+	_, err := s.userRepository.ModifyUser(event.UserID, func(user *model.User) error {
+		lastAppliedAt = user.BillingEventAt
+		if event.OccurredAt.Before(user.BillingEventAt) {
+			return errStaleBillingEvent
+		}
+		if user.Tier == tier && !event.OccurredAt.After(user.BillingEventAt) {
+			return errUserUnchanged
+		}
+		// The event time is recorded even when the tier stays the same, so that older events
+		// cannot undo it
+		user.Tier = tier
+		user.BillingEventAt = event.OccurredAt
+		return nil
+	})
+	switch {
+	case errors.Is(err, errUserUnchanged):
+		return nil
+	case errors.Is(err, errStaleBillingEvent):
+		logger.Info("Ignoring out of order billing event",
+			zap.String("event_id", event.ID),
+			zap.String("user_id", event.UserID),
+			zap.Time("occurred_at", event.OccurredAt),
+			zap.Time("last_applied_at", lastAppliedAt))
+		return nil
+	case errors.Is(err, model.ErrNotFound):
+		logger.Warn("Billing event for unknown user",
+			zap.String("event_id", event.ID),
+			zap.String("user_id", event.UserID))
+		return nil
+	case err != nil:
+		return fmt.Errorf("failed to update tier of user %s: %w", event.UserID, err)
+	}
+
+	logger.Info("Changed user tier from billing event",
+		zap.String("provider", event.Provider),
+		zap.String("event_id", event.ID),
+		zap.String("event_type", event.Type),
+		zap.String("user_id", event.UserID),
+		zap.String("tier", tier))
+
+	return nil
+}
@@ -0,0 +1,202 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/tsongpon/athena/internal/model"
+)
+
+// MockBillingProvider is a mock implementation of BillingProvider for testing
+type MockBillingProvider struct {
+	parseWebhookFunc func(payload []byte, signature string) (model.BillingEvent, error)
+}
+
+func (m *MockBillingProvider) Name() string {
+	return "mock"
+}
+
+func (m *MockBillingProvider) SignatureHeader() string {
+	return "X-Mock-Signature"
+}
+
+func (m *MockBillingProvider) ParseWebhook(payload []byte, signature string) (model.BillingEvent, error) {
+	if m.parseWebhookFunc != nil {
+		return m.parseWebhookFunc(payload, signature)
+	}
+	return model.BillingEvent{}, nil
+}
+
+// MockBillingEventRepository is a mock implementation of BillingEventRepository for testing
+type MockBillingEventRepository struct {
+	processed map[string]bool
+}
+
+func (m *MockBillingEventRepository) IsEventProcessed(provider, eventID string) (bool, error) {
+	return m.processed[provider+eventID], nil
+}
+
+func (m *MockBillingEventRepository) MarkEventProcessed(event model.BillingEvent) error {
+	if m.processed == nil {
+		m.processed = map[string]bool{}
+	}
+	m.processed[event.Provider+event.ID] = true
+	return nil
+}
+
+func newBillingTestProvider(event model.BillingEvent) *MockBillingProvider {
+	return &MockBillingProvider{
+		parseWebhookFunc: func(payload []byte, signature string) (model.BillingEvent, error) {
+			return event, nil
+		},
+	}
+}
+
+func TestBillingService_HandleWebhook_UpgradesTier(t *testing.T) {
+	user := model.User{ID: "user-1", Tier: model.TierFree}
+	updates := 0
+	mockUserRepo := &MockUserRepository{
+		getUserByIDFunc: func(id string) (model.User, error) {
+			return user, nil
+		},
+		updateUserFunc: func(u model.User) (model.User, error) {
+			updates++
+			user = u
+			return u, nil
+		},
+	}
+	provider := newBillingTestProvider(model.BillingEvent{
+		ID: "evt_1", Provider: "mock", Type: model.BillingSubscriptionCreated, UserID: "user-1", Status: "active",
+	})
+
+	service := NewBillingService(provider, mockUserRepo, &MockBillingEventRepository{})
+
+	if err := service.HandleWebhook([]byte("{}"), "sig"); err != nil {
+		t.Fatalf("HandleWebhook() unexpected error = %v", err)
+	}
+	if user.Tier != model.TierPaid {
+		t.Errorf("HandleWebhook() Tier = %v, want %v", user.Tier, model.TierPaid)
+	}
+
+	// Redelivery of the same event is skipped
+	user.Tier = model.TierFree
+	if err := service.HandleWebhook([]byte("{}"), "sig"); err != nil {
+		t.Fatalf("HandleWebhook() redelivery unexpected error = %v", err)
+	}
+	if updates != 1 || user.Tier != model.TierFree {
+		t.Errorf("HandleWebhook() redelivery should be ignored, got %d updates", updates)
+	}
+}
+
+func TestBillingService_HandleWebhook_CancelDowngrades(t *testing.T) {
+	user := model.User{ID: "user-1", Tier: model.TierPaid}
+	mockUserRepo := &MockUserRepository{
+		getUserByIDFunc: func(id string) (model.User, error) {
+			return user, nil
+		},
+		updateUserFunc: func(u model.User) (model.User, error) {
+			user = u
+			return u, nil
+		},
+	}
+	provider := newBillingTestProvider(model.BillingEvent{
+		ID: "evt_2", Provider: "mock", Type: model.BillingSubscriptionCancelled, UserID: "user-1", Status: "canceled",
+	})
+
+	service := NewBillingService(provider, mockUserRepo, &MockBillingEventRepository{})
+
+	if err := service.HandleWebhook([]byte("{}"), "sig"); err != nil {
+		t.Fatalf("HandleWebhook() unexpected error = %v", err)
+	}
+	if user.Tier != model.TierFree {
+		t.Errorf("HandleWebhook() Tier = %v, want %v", user.Tier, model.TierFree)
+	}
+}
+
+// TestBillingService_HandleWebhook_OutOfOrder tests that an event delivered after a newer one
+// does not undo it
+func TestBillingService_HandleWebhook_OutOfOrder(t *testing.T) {
+	user := model.User{ID: "user-1", Tier: model.TierFree}
+	mockUserRepo := &MockUserRepository{
+		getUserByIDFunc: func(id string) (model.User, error) {
+			return user, nil
+		},
+		updateUserFunc: func(u model.User) (model.User, error) {
+			user = u
+			return u, nil
+		},
+	}
+	created := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	var event model.BillingEvent
+	provider := &MockBillingProvider{
+		parseWebhookFunc: func(payload []byte, signature string) (model.BillingEvent, error) {
+			return event, nil
+		},
+	}
+	service := NewBillingService(provider, mockUserRepo, &MockBillingEventRepository{})
+
+	// The cancellation is delivered first, the subscription creation it follows arrives late
+	event = model.BillingEvent{
+		ID: "evt_cancel", Provider: "mock", Type: model.BillingSubscriptionCancelled, UserID: "user-1",
+		Status: "canceled", OccurredAt: created.Add(time.Minute),
+	}
+	if err := service.HandleWebhook([]byte("{}"), "sig"); err != nil {
+		t.Fatalf("HandleWebhook() cancellation unexpected error = %v", err)
+	}
+	event = model.BillingEvent{
+		ID: "evt_create", Provider: "mock", Type: model.BillingSubscriptionCreated, UserID: "user-1",
+		Status: "active", OccurredAt: created,
+	}
+	if err := service.HandleWebhook([]byte("{}"), "sig"); err != nil {
+		t.Fatalf("HandleWebhook() late creation unexpected error = %v", err)
+	}
+
+	if user.Tier != model.TierFree {
+		t.Errorf("HandleWebhook() Tier = %v, want %v", user.Tier, model.TierFree)
+	}
+	if !user.BillingEventAt.Equal(created.Add(time.Minute)) {
+		t.Errorf("HandleWebhook() BillingEventAt = %v, want the cancellation time", user.BillingEventAt)
+	}
+}
+
+func TestBillingService_HandleWebhook_InvalidSignature(t *testing.T) {
+	provider := &MockBillingProvider{
+		parseWebhookFunc: func(payload []byte, signature string) (model.BillingEvent, error) {
+			return model.BillingEvent{}, fmt.Errorf("invalid webhook signature: %w", model.ErrInvalidInput)
+		},
+	}
+
+	service := NewBillingService(provider, &MockUserRepository{}, &MockBillingEventRepository{})
+
+	err := service.HandleWebhook([]byte("{}"), "bad")
+	if !errors.Is(err, model.ErrInvalidInput) {
+		t.Errorf("HandleWebhook() error = %v, want ErrInvalidInput", err)
+	}
+}
+
+func TestBillingService_HandleWebhook_UnknownUser(t *testing.T) {
+	mockUserRepo := &MockUserRepository{
+		getUserByIDFunc: func(id string) (model.User, error) {
+			return model.User{}, fmt.Errorf("user with ID %s %w", id, model.ErrNotFound)
+		},
+		updateUserFunc: func(u model.User) (model.User, error) {
+			t.Error("HandleWebhook() should not update an unknown user")
+			return u, nil
+		},
+	}
+	eventRepo := &MockBillingEventRepository{}
+	provider := newBillingTestProvider(model.BillingEvent{
+		ID: "evt_3", Provider: "mock", Type: model.BillingSubscriptionUpdated, UserID: "ghost", Status: "active",
+	})
+
+	service := NewBillingService(provider, mockUserRepo, eventRepo)
+
+	if err := service.HandleWebhook([]byte("{}"), "sig"); err != nil {
+		t.Fatalf("HandleWebhook() unexpected error = %v", err)
+	}
+	if !eventRepo.processed["mockevt_3"] {
+		t.Error("HandleWebhook() should mark events for unknown users as processed")
+	}
+}
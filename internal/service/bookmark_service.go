@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"sync"
@@ -12,21 +13,43 @@ import (
 	"go.uber.org/zap"
 )
 
+// enrichmentTimeout bounds the lookups run for a bookmark's URL
+const enrichmentTimeout = 10 * time.Second
+
+// maxBulkRefresh is how many bookmarks one bulk refresh may queue
+const maxBulkRefresh = 100
+
 // bookmarkService is the concrete implementation of BookmarkService interface
 type BookmarkService struct {
 	bookmarkRepository BookmarkRepository
 	userRepository     UserRepository
 	webRepository      WebRepository
+	entitlements       *EntitlementService
+	policy             *Policy
+	snapshots          *SnapshotService  // nil when offline snapshots are disabled
+	thumbnails         *ThumbnailService // nil when image thumbnails are disabled
+	events             *EventHub         // nil when change events are not streamed
 	llmSummaryContent  string
+	llmProvider        string         // LLM_MODEL, recorded in the enrichment history
+	background         sync.WaitGroup // Bulk refreshes in progress
+	cursors            cursorSigner
 }
 
-// NewBookmarkService creates a new instance of BookmarkService
-func NewBookmarkService(bookmarkRepo BookmarkRepository, userRepo UserRepository, webrepo WebRepository) *BookmarkService {
+// NewBookmarkService creates a new instance of BookmarkService. snapshots, thumbnails and events may be nil.
+// List cursors are signed with cursorKey, or with a random key when it is empty.
+func NewBookmarkService(bookmarkRepo BookmarkRepository, userRepo UserRepository, webrepo WebRepository, entitlements *EntitlementService, policy *Policy, snapshots *SnapshotService, thumbnails *ThumbnailService, events *EventHub, cursorKey []byte) *BookmarkService {
 	return &BookmarkService{
 		bookmarkRepository: bookmarkRepo,
 		userRepository:     userRepo,
 		webRepository:      webrepo,
+		entitlements:       entitlements,
+		policy:             policy,
+		snapshots:          snapshots,
+		thumbnails:         thumbnails,
+		events:             events,
 		llmSummaryContent:  os.Getenv("LLM_SUMMARY_CONTENT"),
+		llmProvider:        os.Getenv("LLM_MODEL"),
+		cursors:            newCursorSigner(cursorKey),
 	}
 }
 
@@ -34,50 +57,23 @@ func (s *BookmarkService) CreateBookmark(b model.Bookmark) (model.Bookmark, erro
 	if b.ID != "" {
 		return model.Bookmark{}, fmt.Errorf("bookmark ID must be empty")
 	}
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
-	var wg sync.WaitGroup
-	var title string
-	var MainImageURL string
-
-	wg.Go(func() {
-		var e error
-		title, e = s.webRepository.GetTitle(ctx, b.URL)
-		if e != nil {
-			logger.Warn("failed to fetch title for URL", zap.String("url", b.URL), zap.Error(e))
-		}
-	})
-
-	wg.Go(func() {
-		var e error
-		MainImageURL, e = s.webRepository.GetMainImage(ctx, b.URL)
-		if e != nil {
-			logger.Warn("failed to fetch main image URL for URL", zap.String("url", b.URL), zap.Error(e))
-		}
-	})
-
-	var content string
+	b.Tags = model.NormalizeTags(b.Tags)
+	if len(b.Tags) > model.MaxBookmarkTags {
+		return model.Bookmark{}, fmt.Errorf("a bookmark can have at most %d tags: %w", model.MaxBookmarkTags, model.ErrInvalidInput)
+	}
+	if err := validateNote(b.Notes); err != nil {
+		return model.Bookmark{}, err
+	}
 	user, err := s.userRepository.GetUserByID(b.UserID)
 	if err != nil {
 		return model.Bookmark{}, fmt.Errorf("failed to fetch user for ID %s: %w", b.UserID, err)
 	}
-	if user.Tier == "paid" {
-		if s.llmSummaryContent == "true" {
-			logger.Info("LLM content summary is enabled")
-			wg.Go(func() {
-				var e error
-				content, e = s.webRepository.GetContentSummary(ctx, b.URL)
-				if e != nil {
-					logger.Warn("failed to fetch content summary for URL", zap.String("url", b.URL), zap.Error(e))
-				}
-			})
-		}
+	if err := s.entitlements.CheckBookmarkQuota(user); err != nil {
+		return model.Bookmark{}, err
 	}
-	wg.Wait()
-	b.Title = title
-	b.ContentSummary = content
-	b.MainImageURL = MainImageURL
+
+	page := s.enrich(user, b.URL, true)
+	s.applyEnrichment(&b, page, model.EnrichmentTriggerCreate)
 	b.IsArchived = false
 	createdBookmark, err := s.bookmarkRepository.CreateBookmark(b)
 	if err != nil {
@@ -91,25 +87,236 @@ func (s *BookmarkService) CreateBookmark(b model.Bookmark) (model.Bookmark, erro
 		zap.String("content_summary", createdBookmark.ContentSummary),
 		zap.String("main_image_url", createdBookmark.MainImageURL),
 		zap.Bool("is_archived", createdBookmark.IsArchived))
+	s.events.Publish(model.EventBookmarkCreated, createdBookmark)
+
+	if s.snapshots != nil {
+		s.snapshots.CaptureSnapshotAsync(createdBookmark.ID)
+	}
+	if s.thumbnails != nil && createdBookmark.MainImageURL != "" {
+		s.thumbnails.CaptureThumbnailsAsync(createdBookmark.ID)
+	}
 
 	return createdBookmark, nil
 }
 
-func (s *BookmarkService) ArchiveBookmark(id string) (model.Bookmark, error) {
+// RefreshBookmark re-runs enrichment of a bookmark userID owns, for bookmarks saved while a
+// site was down or before the user's tier had summaries. Values a lookup cannot find are kept,
+// and a summary is only generated for bookmarks without one.
+func (s *BookmarkService) RefreshBookmark(userID, id string) (model.Bookmark, error) {
 	b, err := s.bookmarkRepository.GetBookmark(id)
 	if err != nil {
 		return model.Bookmark{}, fmt.Errorf("failed to get bookmark with ID %s: %w", id, err)
 	}
-	b.IsArchived = true
-	updated, err := s.bookmarkRepository.UpdateBookmark(b)
+	if err := s.policy.CanModifyBookmark(userID, b); err != nil {
+		return model.Bookmark{}, err
+	}
+	return s.refresh(b)
+}
+
+// RefreshBookmarks queues bookmarks userID owns for RefreshBookmark and refreshes them in the
+// background. IDs of bookmarks that do not exist or belong to someone else are reported
+// rather than failing the whole request.
+func (s *BookmarkService) RefreshBookmarks(userID string, ids []string) (model.BulkRefresh, error) {
+	if len(ids) == 0 || len(ids) > maxBulkRefresh {
+		return model.BulkRefresh{}, fmt.Errorf("between 1 and %d bookmark IDs are required: %w", maxBulkRefresh, model.ErrInvalidInput)
+	}
+
+	var result model.BulkRefresh
+	var queued []model.Bookmark
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		b, err := s.bookmarkRepository.GetBookmark(id)
+		if errors.Is(err, model.ErrNotFound) || (err == nil && s.policy.CanModifyBookmark(userID, b) != nil) {
+			// Other users' bookmarks are reported like missing ones so their IDs cannot be probed
+			result.NotFound = append(result.NotFound, id)
+			continue
+		}
+		if err != nil {
+			return model.BulkRefresh{}, fmt.Errorf("failed to get bookmark with ID %s: %w", id, err)
+		}
+		queued = append(queued, b)
+		result.Queued = append(result.Queued, id)
+	}
+
+	// One at a time, so a bulk refresh does not crowd out the fetches of new bookmarks
+	s.background.Go(func() {
+		for _, b := range queued {
+			if _, err := s.refresh(b); err != nil {
+				logger.Warn("failed to refresh bookmark", zap.String("bookmark_id", b.ID), zap.Error(err))
+			}
+		}
+	})
+	logger.Info("Queued bookmark refresh",
+		zap.String("user_id", userID),
+		zap.Int("queued", len(result.Queued)),
+		zap.Int("not_found", len(result.NotFound)))
+	return result, nil
+}
+
+// refresh re-runs enrichment of b and retries the snapshot and thumbnails if they are missing
+func (s *BookmarkService) refresh(b model.Bookmark) (model.Bookmark, error) {
+	user, err := s.userRepository.GetUserByID(b.UserID)
 	if err != nil {
-		return model.Bookmark{}, fmt.Errorf("failed to update bookmark with ID %s: %w", b.ID, err)
+		return model.Bookmark{}, fmt.Errorf("failed to fetch user for ID %s: %w", b.UserID, err)
 	}
+	page := s.enrich(user, b.URL, b.ContentSummary == "")
 
+	// Update a fresh read of the bookmark so changes made while the page was fetched are kept
+	var previousImageURL string
+	var enrichment model.Enrichment
+	updated, err := updateBookmark(s.bookmarkRepository, b.ID, func(b *model.Bookmark) error {
+		previousImageURL = b.MainImageURL
+		s.applyEnrichment(b, page, model.EnrichmentTriggerRefresh)
+		enrichment = b.Enrichments[len(b.Enrichments)-1]
+		return nil
+	})
+	if err != nil {
+		return model.Bookmark{}, err
+	}
+	logger.Info("Refreshed bookmark",
+		zap.String("id", updated.ID),
+		zap.String("url", updated.URL),
+		zap.Strings("updated", enrichment.Updated))
+	s.events.Publish(model.EventBookmarkEnriched, updated)
+
+	if s.snapshots != nil && updated.SnapshotKey == "" {
+		s.snapshots.CaptureSnapshotAsync(updated.ID)
+	}
+	if s.thumbnails != nil && updated.MainImageURL != "" &&
+		(updated.MainImageURL != previousImageURL || len(updated.Thumbnails) == 0) {
+		s.thumbnails.CaptureThumbnailsAsync(updated.ID)
+	}
 	return updated, nil
 }
 
-func (s *BookmarkService) GetBookmark(id string) (model.Bookmark, error) {
+// ArchiveBookmark archives a bookmark on behalf of userID, who must own it. A non-zero version
+// is the version the client last read; the bookmark is only archived if it is still at it.
+func (s *BookmarkService) ArchiveBookmark(userID, id string, version int64) (model.Bookmark, error) {
+	updated, err := updateBookmark(s.bookmarkRepository, id, func(b *model.Bookmark) error {
+		if err := s.policy.CanModifyBookmark(userID, *b); err != nil {
+			return err
+		}
+		if err := checkVersion(*b, version); err != nil {
+			return err
+		}
+		b.IsArchived = true
+		return nil
+	})
+	if err != nil {
+		return model.Bookmark{}, err
+	}
+	s.events.Publish(model.EventBookmarkArchived, updated)
+
+	return updated, nil
+}
+
+// OpenBookmark records that userID, who must own the bookmark, opened its link, for sorting
+// by last opened
+func (s *BookmarkService) OpenBookmark(userID, id string) (model.Bookmark, error) {
+	updated, err := updateBookmark(s.bookmarkRepository, id, func(b *model.Bookmark) error {
+		if err := s.policy.CanModifyBookmark(userID, *b); err != nil {
+			return err
+		}
+		b.LastOpenedAt = time.Now()
+		return nil
+	})
+	if err != nil {
+		return model.Bookmark{}, err
+	}
+	s.events.Publish(model.EventBookmarkUpdated, updated)
+
+	return updated, nil
+}
+
+// UpdateReadingState marks a bookmark that userID owns read or unread, favorite or not, and
+// records how far it has been read. Reading to 100% marks it read unless the update says
+// otherwise; marking it unread resets the progress. A non-zero version is checked as in
+// ArchiveBookmark.
+func (s *BookmarkService) UpdateReadingState(userID, id string, update model.ReadingStateUpdate, version int64) (model.Bookmark, error) {
+	if update.IsRead == nil && update.IsFavorite == nil && update.ReadingProgress == nil {
+		return model.Bookmark{}, fmt.Errorf("is_read, is_favorite or reading_progress is required: %w", model.ErrInvalidInput)
+	}
+	if err := validateReadingProgress(update.ReadingProgress); err != nil {
+		return model.Bookmark{}, err
+	}
+	updated, err := updateBookmark(s.bookmarkRepository, id, func(b *model.Bookmark) error {
+		if err := s.policy.CanModifyBookmark(userID, *b); err != nil {
+			return err
+		}
+		if err := checkVersion(*b, version); err != nil {
+			return err
+		}
+		applyReadingState(b, update)
+		return nil
+	})
+	if err != nil {
+		return model.Bookmark{}, err
+	}
+	s.events.Publish(model.EventBookmarkUpdated, updated)
+	return updated, nil
+}
+
+// validateReadingProgress checks that a reading progress, if set, is a percentage
+func validateReadingProgress(progress *int) error {
+	if progress != nil && (*progress < 0 || *progress > 100) {
+		return fmt.Errorf("reading_progress must be between 0 and 100: %w", model.ErrInvalidInput)
+	}
+	return nil
+}
+
+// applyReadingState changes the reading state of b as UpdateReadingState describes
+func applyReadingState(b *model.Bookmark, update model.ReadingStateUpdate) {
+	read := b.IsRead
+	if update.ReadingProgress != nil {
+		b.ReadingProgress = *update.ReadingProgress
+		read = read || b.ReadingProgress == 100
+	}
+	if update.IsRead != nil {
+		read = *update.IsRead
+	}
+	switch {
+	case read && !b.IsRead:
+		b.IsRead, b.ReadAt = true, time.Now()
+	case !read && b.IsRead:
+		b.IsRead, b.ReadAt = false, time.Time{}
+		if update.ReadingProgress == nil {
+			b.ReadingProgress = 0
+		}
+	}
+	if update.IsFavorite != nil {
+		b.IsFavorite = *update.IsFavorite
+	}
+}
+
+// UpdateNotes replaces the Markdown notes of a bookmark that userID owns. Empty notes clear them.
+// A non-zero version is checked as in ArchiveBookmark.
+func (s *BookmarkService) UpdateNotes(userID, id, notes string, version int64) (model.Bookmark, error) {
+	if err := validateNote(notes); err != nil {
+		return model.Bookmark{}, err
+	}
+	updated, err := updateBookmark(s.bookmarkRepository, id, func(b *model.Bookmark) error {
+		if err := s.policy.CanModifyBookmark(userID, *b); err != nil {
+			return err
+		}
+		if err := checkVersion(*b, version); err != nil {
+			return err
+		}
+		b.Notes = notes
+		return nil
+	})
+	if err != nil {
+		return model.Bookmark{}, err
+	}
+	s.events.Publish(model.EventBookmarkUpdated, updated)
+	return updated, nil
+}
+
+// GetBookmark retrieves a bookmark that userID owns or can see through a shared collection
+func (s *BookmarkService) GetBookmark(userID, id string) (model.Bookmark, error) {
 	if id == "" {
 		return model.Bookmark{}, fmt.Errorf("id is required")
 	}
@@ -117,8 +324,11 @@ func (s *BookmarkService) GetBookmark(id string) (model.Bookmark, error) {
 	if err != nil {
 		return model.Bookmark{}, fmt.Errorf("failed to get bookmarks for id %s: %w", id, err)
 	}
+	if err := s.policy.CanViewBookmark(userID, bookmarks); err != nil {
+		return model.Bookmark{}, err
+	}
 
-	return bookmarks, nil
+	return bookmarks.VisibleTo(userID), nil
 }
 
 func (s *BookmarkService) GetAllBookmarks(userID string, archived bool) ([]model.Bookmark, error) {
@@ -140,18 +350,47 @@ func (s *BookmarkService) GetBookmarksWithPagination(userID string, archived boo
 	if page < 1 {
 		page = 1
 	}
-	if pageSize < 1 {
-		pageSize = 20 // Default page size
-	}
-	if pageSize > 100 {
-		pageSize = 100 // Maximum page size
-	}
 
-	query := model.BookmarkQuery{
+	return s.ListBookmarks(model.BookmarkQuery{
 		UserID:   userID,
 		Archived: archived,
 		Page:     page,
 		PageSize: pageSize,
+	})
+}
+
+// ListBookmarks retrieves bookmarks matching query. Results are paginated when Page or
+// PageSize is set, otherwise every match is returned as a single page.
+func (s *BookmarkService) ListBookmarks(query model.BookmarkQuery) (model.BookmarkListResponse, error) {
+	if err := query.Normalize(); err != nil {
+		return model.BookmarkListResponse{}, err
+	}
+	if query.Cursor != "" {
+		return s.listBookmarksFromCursor(query)
+	}
+	if query.Page == 0 && query.PageSize == 0 {
+		bookmarks, err := s.bookmarkRepository.ListBookmarks(query)
+		if err != nil {
+			return model.BookmarkListResponse{}, fmt.Errorf("failed to get bookmarks: %w", err)
+		}
+		return model.BookmarkListResponse{
+			Bookmarks:  bookmarks,
+			TotalCount: len(bookmarks),
+			Page:       1,
+			PageSize:   len(bookmarks),
+			TotalPages: 1,
+		}, nil
+	}
+
+	// Validate pagination parameters
+	if query.Page < 1 {
+		query.Page = 1
+	}
+	if query.PageSize < 1 {
+		query.PageSize = 20 // Default page size
+	}
+	if query.PageSize > 100 {
+		query.PageSize = 100 // Maximum page size
 	}
 
 	// Get paginated bookmarks
@@ -167,7 +406,7 @@ func (s *BookmarkService) GetBookmarksWithPagination(userID string, archived boo
 	}
 
 	// Calculate total pages
-	totalPages := (totalCount + pageSize - 1) / pageSize
+	totalPages := (totalCount + query.PageSize - 1) / query.PageSize
 	if totalPages == 0 {
 		totalPages = 1
 	}
@@ -175,22 +414,233 @@ func (s *BookmarkService) GetBookmarksWithPagination(userID string, archived boo
 	response := model.BookmarkListResponse{
 		Bookmarks:  bookmarks,
 		TotalCount: totalCount,
-		Page:       page,
-		PageSize:   pageSize,
+		Page:       query.Page,
+		PageSize:   query.PageSize,
 		TotalPages: totalPages,
 	}
 
+	// Clients can switch to cursors from any page
+	if len(bookmarks) > 0 {
+		if query.Page < totalPages {
+			response.NextCursor = s.cursors.encode(model.PositionOf(bookmarks[len(bookmarks)-1], query.Sort, query.Order, false))
+		}
+		if query.Page > 1 {
+			response.PrevCursor = s.cursors.encode(model.PositionOf(bookmarks[0], query.Sort, query.Order, true))
+		}
+	}
+
 	return response, nil
 }
 
-func (s *BookmarkService) DeleteBookmark(id string) error {
+// listBookmarksFromCursor returns the page of bookmarks next to the position of query.Cursor.
+// Unlike offsets, positions do not shift when bookmarks are added while a client scrolls.
+// Counting every match would defeat the point, so the response carries no totals.
+func (s *BookmarkService) listBookmarksFromCursor(query model.BookmarkQuery) (model.BookmarkListResponse, error) {
+	position, err := s.cursors.decode(query.Cursor)
+	if err != nil {
+		return model.BookmarkListResponse{}, err
+	}
+	if position.Sort != query.Sort || position.Order != query.Order {
+		return model.BookmarkListResponse{}, fmt.Errorf("cursor belongs to another sort order: %w", model.ErrInvalidInput)
+	}
+	pageSize := query.PageSize
+	if pageSize < 1 {
+		pageSize = 20 // Default page size
+	}
+	if pageSize > 100 {
+		pageSize = 100 // Maximum page size
+	}
+
+	query.Page = 0
+	query.Position = &position
+	query.PageSize = pageSize + 1 // The extra bookmark tells whether there is another page
+	bookmarks, err := s.bookmarkRepository.ListBookmarks(query)
+	if err != nil {
+		return model.BookmarkListResponse{}, fmt.Errorf("failed to get bookmarks: %w", err)
+	}
+	more := len(bookmarks) > pageSize
+	if more && position.Backward {
+		bookmarks = bookmarks[len(bookmarks)-pageSize:]
+	} else if more {
+		bookmarks = bookmarks[:pageSize]
+	}
+
+	response := model.BookmarkListResponse{
+		Bookmarks: bookmarks,
+		PageSize:  pageSize,
+	}
+	if len(bookmarks) == 0 {
+		// Past the end of the listing, the way back starts at the position itself
+		back := position
+		back.Backward = !position.Backward
+		if position.Backward {
+			response.NextCursor = s.cursors.encode(back)
+		} else {
+			response.PrevCursor = s.cursors.encode(back)
+		}
+		return response, nil
+	}
+	// Having come from a position, there is always a page on that side of it
+	if more || position.Backward {
+		response.NextCursor = s.cursors.encode(model.PositionOf(bookmarks[len(bookmarks)-1], query.Sort, query.Order, false))
+	}
+	if more || !position.Backward {
+		response.PrevCursor = s.cursors.encode(model.PositionOf(bookmarks[0], query.Sort, query.Order, true))
+	}
+	return response, nil
+}
+
+// DeleteBookmark moves a bookmark to the trash on behalf of userID, who must own it. A non-zero
+// version must match the bookmark's. Its snapshot and thumbnails are kept until the trash is
+// purged, see TrashService.
+func (s *BookmarkService) DeleteBookmark(userID, id string, version int64) error {
 	if id == "" {
 		return fmt.Errorf("id is required")
 	}
-	err := s.bookmarkRepository.DeleteBookmark(id)
-	if err != nil {
-		return fmt.Errorf("failed to delete bookmark with ID %s: %w", id, err)
+	for attempt := 1; ; attempt++ {
+		b, err := s.bookmarkRepository.GetBookmark(id)
+		if err != nil {
+			return fmt.Errorf("failed to get bookmark with ID %s: %w", id, err)
+		}
+		if err := s.policy.CanModifyBookmark(userID, b); err != nil {
+			return err
+		}
+		if err := checkVersion(b, version); err != nil {
+			return err
+		}
+		// Trashing fails when the bookmark was changed since it was checked; check it again
+		_, err = s.bookmarkRepository.TrashBookmark(id, time.Now(), b.Version)
+		if errors.Is(err, model.ErrConflict) && attempt < maxUpdateAttempts {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to delete bookmark with ID %s: %w", id, err)
+		}
+		s.events.Publish(model.EventBookmarkDeleted, b)
+		return nil
 	}
+}
 
-	return nil
+// enrichedPage is what the enrichment lookups found for a URL. Values that were not found are empty.
+type enrichedPage struct {
+	title          string
+	mainImageURL   string
+	summary        string
+	metadata       model.PageMetadata
+	summaryOutcome string   // One of the model.EnrichmentSummary outcomes
+	failed         []string // Lookups that returned an error
+}
+
+// enrich runs the title, main image, metadata and content summary lookups for url
+// concurrently. A summary is only requested when wantSummary is set and user's tier has one
+// left this month.
+func (s *BookmarkService) enrich(user model.User, url string, wantSummary bool) enrichedPage {
+	ctx, cancel := context.WithTimeout(context.Background(), enrichmentTimeout)
+	defer cancel()
+
+	var page enrichedPage
+	var titleErr, imageErr, metadataErr, summaryErr error
+	var wg sync.WaitGroup
+
+	wg.Go(func() {
+		page.title, titleErr = s.webRepository.GetTitle(ctx, url)
+		if titleErr != nil {
+			logger.Warn("failed to fetch title for URL", zap.String("url", url), zap.Error(titleErr))
+		}
+	})
+
+	wg.Go(func() {
+		page.mainImageURL, imageErr = s.webRepository.GetMainImage(ctx, url)
+		if imageErr != nil {
+			logger.Warn("failed to fetch main image URL for URL", zap.String("url", url), zap.Error(imageErr))
+		}
+	})
+
+	wg.Go(func() {
+		page.metadata, metadataErr = s.webRepository.GetPageMetadata(ctx, url)
+		if metadataErr != nil {
+			logger.Warn("failed to fetch page metadata for URL", zap.String("url", url), zap.Error(metadataErr))
+		}
+	})
+
+	switch {
+	case !wantSummary:
+		page.summaryOutcome = model.EnrichmentSummaryKept
+	case s.llmSummaryContent != "true":
+		page.summaryOutcome = model.EnrichmentSummaryDisabled
+	default:
+		// Summaries are optional: a tier without summaries or an exhausted quota
+		// still enriches the bookmark, just without a summary
+		if e := s.entitlements.CheckSummaryQuota(user); e != nil {
+			logger.Debug("skipping content summary", zap.String("user_id", user.ID), zap.Error(e))
+			page.summaryOutcome = model.EnrichmentSummaryNotAvailable
+			break
+		}
+		logger.Info("LLM content summary is enabled")
+		wg.Go(func() {
+			page.summary, summaryErr = s.webRepository.GetContentSummary(ctx, url)
+			if summaryErr != nil {
+				logger.Warn("failed to fetch content summary for URL", zap.String("url", url), zap.Error(summaryErr))
+				page.summary = ""
+				return
+			}
+			if page.summary != "" {
+				if e := s.entitlements.RecordSummary(user.ID); e != nil {
+					logger.Warn("failed to record summary usage", zap.String("user_id", user.ID), zap.Error(e))
+				}
+			}
+		})
+	}
+	wg.Wait()
+
+	if page.summaryOutcome == "" {
+		page.summaryOutcome = model.EnrichmentSummaryFailed
+		if page.summary != "" {
+			page.summaryOutcome = model.EnrichmentSummaryGenerated
+		}
+	}
+	for _, lookup := range []struct {
+		field string
+		err   error
+	}{{"title", titleErr}, {"main_image", imageErr}, {"metadata", metadataErr}, {"summary", summaryErr}} {
+		if lookup.err != nil {
+			page.failed = append(page.failed, lookup.field)
+		}
+	}
+	return page
+}
+
+// applyEnrichment copies what was found onto b, keeping the current value wherever a lookup
+// came back empty, and records the run in b's enrichment history
+func (s *BookmarkService) applyEnrichment(b *model.Bookmark, page enrichedPage, trigger string) {
+	record := model.Enrichment{
+		At:      time.Now(),
+		Trigger: trigger,
+		Summary: page.summaryOutcome,
+		Failed:  page.failed,
+	}
+	if page.title != "" && page.title != b.Title {
+		b.Title = page.title
+		record.Updated = append(record.Updated, "title")
+	}
+	if page.mainImageURL != "" && page.mainImageURL != b.MainImageURL {
+		b.MainImageURL = page.mainImageURL
+		record.Updated = append(record.Updated, "main_image")
+	}
+	if page.summary != "" && page.summary != b.ContentSummary {
+		b.ContentSummary = page.summary
+		record.Updated = append(record.Updated, "summary")
+	}
+	if page.summaryOutcome == model.EnrichmentSummaryGenerated {
+		record.SummaryProvider = s.llmProvider
+	}
+	if page.metadata != (model.PageMetadata{}) && page.metadata != b.Metadata {
+		b.Metadata = page.metadata
+		record.Updated = append(record.Updated, "metadata")
+	}
+
+	b.Enrichments = append(b.Enrichments, record)
+	if len(b.Enrichments) > model.MaxEnrichmentHistory {
+		b.Enrichments = b.Enrichments[len(b.Enrichments)-model.MaxEnrichmentHistory:]
+	}
 }
@@ -1,9 +1,14 @@
 package service
 
 import (
+	"bytes"
 	"context"
+	"encoding/base64"
+	"errors"
 	"fmt"
 	"os"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
@@ -15,9 +20,20 @@ type MockBookmarkRepository struct {
 	createBookmarkFunc func(bookmark model.Bookmark) (model.Bookmark, error)
 	getBookmarkFunc    func(id string) (model.Bookmark, error)
 	listBookmarksFunc  func(userID string, archived bool) ([]model.Bookmark, error)
-	countBookmarksFunc func(query model.BookmarkQuery) (int, error)
-	updateBookmarkFunc func(bookmark model.Bookmark) (model.Bookmark, error)
-	deleteBookmarkFunc func(id string) error
+	// listBookmarksByQueryFunc takes precedence over listBookmarksFunc when a test needs the whole query
+	listBookmarksByQueryFunc func(query model.BookmarkQuery) ([]model.Bookmark, error)
+	countBookmarksFunc       func(query model.BookmarkQuery) (int, error)
+	updateBookmarkFunc       func(bookmark model.Bookmark) (model.Bookmark, error)
+	deleteBookmarkFunc       func(id string) error
+	listCheckedBeforeFunc    func(before time.Time, limit int) ([]model.Bookmark, error)
+	// getBookmarksFunc and writeBookmarksFunc default to getBookmarkFunc, updateBookmarkFunc and deleteBookmarkFunc
+	getBookmarksFunc   func(ids []string) ([]model.Bookmark, error)
+	writeBookmarksFunc func(writes []model.BookmarkWrite) []error
+	// trash is keyed by bookmark ID; the trash methods work on it unless a func is set
+	trash               map[string]model.Bookmark
+	trashBookmarkFunc   func(id string, deletedAt time.Time, version int64) (model.Bookmark, error)
+	restoreBookmarkFunc func(id string) (model.Bookmark, error)
+	purgeBookmarkFunc   func(id string) error
 }
 
 // MockWebRepository is a mock implementation of WebRepository for testing
@@ -25,6 +41,27 @@ type MockWebRepository struct {
 	getTitleFunc          func(ctx context.Context, url string) (string, error)
 	getMainImageFunc      func(ctx context.Context, url string) (string, error)
 	getContentSummaryFunc func(ctx context.Context, url string) (string, error)
+	getPageMetadataFunc   func(ctx context.Context, url string) (model.PageMetadata, error)
+}
+
+// MockUsageRepository is a mock implementation of UsageRepository for testing
+type MockUsageRepository struct {
+	incrementUsageFunc func(userID, metric, period string) (int, error)
+	getUsageFunc       func(userID, metric, period string) (int, error)
+}
+
+func (m *MockUsageRepository) IncrementUsage(userID, metric, period string) (int, error) {
+	if m.incrementUsageFunc != nil {
+		return m.incrementUsageFunc(userID, metric, period)
+	}
+	return 1, nil
+}
+
+func (m *MockUsageRepository) GetUsage(userID, metric, period string) (int, error) {
+	if m.getUsageFunc != nil {
+		return m.getUsageFunc(userID, metric, period)
+	}
+	return 0, nil
 }
 
 // MockUserRepository is a mock implementation of UserRepository for testing
@@ -33,6 +70,9 @@ type MockUserRepository struct {
 	getUserByIDFunc               func(id string) (model.User, error)
 	getUserByEmailFunc            func(email string) (model.User, error)
 	getUserByEmailAndPasswordFunc func(email, hashedPassword string) (model.User, error)
+	updateUserFunc                func(user model.User) (model.User, error)
+	listUsersFunc                 func(query model.UserQuery) ([]model.User, error)
+	countUsersFunc                func(query model.UserQuery) (int, error)
 }
 
 func (m *MockBookmarkRepository) CreateBookmark(bookmark model.Bookmark) (model.Bookmark, error) {
@@ -50,6 +90,9 @@ func (m *MockBookmarkRepository) GetBookmark(id string) (model.Bookmark, error)
 }
 
 func (m *MockBookmarkRepository) ListBookmarks(query model.BookmarkQuery) ([]model.Bookmark, error) {
+	if m.listBookmarksByQueryFunc != nil {
+		return m.listBookmarksByQueryFunc(query)
+	}
 	if m.listBookmarksFunc != nil {
 		return m.listBookmarksFunc(query.UserID, query.Archived)
 	}
@@ -77,6 +120,118 @@ func (m *MockBookmarkRepository) DeleteBookmark(id string) error {
 	return nil
 }
 
+func (m *MockBookmarkRepository) GetBookmarks(ids []string) ([]model.Bookmark, error) {
+	if m.getBookmarksFunc != nil {
+		return m.getBookmarksFunc(ids)
+	}
+	var bookmarks []model.Bookmark
+	for _, id := range ids {
+		bookmark, err := m.GetBookmark(id)
+		if errors.Is(err, model.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		bookmarks = append(bookmarks, bookmark)
+	}
+	return bookmarks, nil
+}
+
+func (m *MockBookmarkRepository) WriteBookmarks(writes []model.BookmarkWrite) []error {
+	if m.writeBookmarksFunc != nil {
+		return m.writeBookmarksFunc(writes)
+	}
+	errs := make([]error, len(writes))
+	for i, write := range writes {
+		if write.Trash {
+			_, errs[i] = m.TrashBookmark(write.Bookmark.ID, write.Bookmark.DeletedAt, write.Bookmark.Version)
+		} else {
+			_, errs[i] = m.UpdateBookmark(write.Bookmark)
+		}
+	}
+	return errs
+}
+
+func (m *MockBookmarkRepository) TrashBookmark(id string, deletedAt time.Time, version int64) (model.Bookmark, error) {
+	if m.trashBookmarkFunc != nil {
+		return m.trashBookmarkFunc(id, deletedAt, version)
+	}
+	bookmark, err := m.GetBookmark(id)
+	if err != nil {
+		return model.Bookmark{}, err
+	}
+	bookmark.DeletedAt = deletedAt
+	if m.trash == nil {
+		m.trash = make(map[string]model.Bookmark)
+	}
+	m.trash[id] = bookmark
+	return bookmark, nil
+}
+
+func (m *MockBookmarkRepository) GetTrashedBookmark(id string) (model.Bookmark, error) {
+	if bookmark, ok := m.trash[id]; ok {
+		return bookmark, nil
+	}
+	return model.Bookmark{}, model.ErrNotFound
+}
+
+func (m *MockBookmarkRepository) ListTrashedBookmarks(userID string) ([]model.Bookmark, error) {
+	var trashed []model.Bookmark
+	for _, bookmark := range m.trash {
+		if bookmark.UserID == userID {
+			trashed = append(trashed, bookmark)
+		}
+	}
+	slices.SortFunc(trashed, func(a, b model.Bookmark) int { return b.DeletedAt.Compare(a.DeletedAt) })
+	return trashed, nil
+}
+
+func (m *MockBookmarkRepository) ListTrashedBefore(before time.Time, limit int) ([]model.Bookmark, error) {
+	var expired []model.Bookmark
+	for _, bookmark := range m.trash {
+		if bookmark.DeletedAt.Before(before) {
+			expired = append(expired, bookmark)
+		}
+	}
+	slices.SortFunc(expired, func(a, b model.Bookmark) int { return a.DeletedAt.Compare(b.DeletedAt) })
+	if len(expired) > limit {
+		expired = expired[:limit]
+	}
+	return expired, nil
+}
+
+func (m *MockBookmarkRepository) RestoreBookmark(id string) (model.Bookmark, error) {
+	if m.restoreBookmarkFunc != nil {
+		return m.restoreBookmarkFunc(id)
+	}
+	bookmark, ok := m.trash[id]
+	if !ok {
+		return model.Bookmark{}, model.ErrNotFound
+	}
+	delete(m.trash, id)
+	bookmark.DeletedAt = time.Time{}
+	return bookmark, nil
+}
+
+func (m *MockBookmarkRepository) PurgeBookmark(id string) error {
+	if m.purgeBookmarkFunc != nil {
+		return m.purgeBookmarkFunc(id)
+	}
+	if _, ok := m.trash[id]; !ok {
+		return model.ErrNotFound
+	}
+	delete(m.trash, id)
+	return nil
+}
+
+func (m *MockBookmarkRepository) ListBookmarksCheckedBefore(before time.Time, limit int) ([]model.Bookmark, error) {
+	if m.listCheckedBeforeFunc != nil {
+		return m.listCheckedBeforeFunc(before, limit)
+	}
+	return []model.Bookmark{}, nil
+}
+
 func (m *MockWebRepository) GetTitle(ctx context.Context, url string) (string, error) {
 	if m.getTitleFunc != nil {
 		return m.getTitleFunc(ctx, url)
@@ -98,6 +253,13 @@ func (m *MockWebRepository) GetContentSummary(ctx context.Context, url string) (
 	return "", nil
 }
 
+func (m *MockWebRepository) GetPageMetadata(ctx context.Context, url string) (model.PageMetadata, error) {
+	if m.getPageMetadataFunc != nil {
+		return m.getPageMetadataFunc(ctx, url)
+	}
+	return model.PageMetadata{}, nil
+}
+
 func (m *MockUserRepository) CreateUser(user model.User) (model.User, error) {
 	if m.createUserFunc != nil {
 		return m.createUserFunc(user)
@@ -126,6 +288,39 @@ func (m *MockUserRepository) GetUserByEmailAndPassword(email, hashedPassword str
 	return model.User{}, nil
 }
 
+func (m *MockUserRepository) UpdateUser(user model.User) (model.User, error) {
+	if m.updateUserFunc != nil {
+		return m.updateUserFunc(user)
+	}
+	return user, nil
+}
+
+// ModifyUser runs change on GetUserByID and stores the result with UpdateUser
+func (m *MockUserRepository) ModifyUser(id string, change func(u *model.User) error) (model.User, error) {
+	user, err := m.GetUserByID(id)
+	if err != nil {
+		return model.User{}, err
+	}
+	if err := change(&user); err != nil {
+		return model.User{}, err
+	}
+	return m.UpdateUser(user)
+}
+
+func (m *MockUserRepository) ListUsers(query model.UserQuery) ([]model.User, error) {
+	if m.listUsersFunc != nil {
+		return m.listUsersFunc(query)
+	}
+	return []model.User{}, nil
+}
+
+func (m *MockUserRepository) CountUsers(query model.UserQuery) (int, error) {
+	if m.countUsersFunc != nil {
+		return m.countUsersFunc(query)
+	}
+	return 0, nil
+}
+
 // TestBookmarkService_CreateBookmark tests successful bookmark creation
 func TestBookmarkService_CreateBookmark(t *testing.T) {
 	// Set environment variable to enable content summary
@@ -182,7 +377,7 @@ func TestBookmarkService_CreateBookmark(t *testing.T) {
 			return model.User{ID: "user-1", Tier: "paid"}, nil
 		},
 	}
-	service := NewBookmarkService(mockRepo, mockUserRepo, mockWebRepo)
+	service := NewBookmarkService(mockRepo, mockUserRepo, mockWebRepo, NewEntitlementService(mockUserRepo, mockRepo, &MockUsageRepository{}), NewPolicy(&MockCollectionRepository{}), nil, nil, nil, nil)
 	result, err := service.CreateBookmark(model.Bookmark{
 		UserID: "user-1",
 		URL:    "https://example.com",
@@ -234,7 +429,7 @@ func TestBookmarkService_CreateBookmark_RepositoryError(t *testing.T) {
 			return model.User{ID: "user-1", Tier: "free"}, nil
 		},
 	}
-	service := NewBookmarkService(mockRepo, mockUserRepo, mockWebRepo)
+	service := NewBookmarkService(mockRepo, mockUserRepo, mockWebRepo, NewEntitlementService(mockUserRepo, mockRepo, &MockUsageRepository{}), NewPolicy(&MockCollectionRepository{}), nil, nil, nil, nil)
 	_, err := service.CreateBookmark(model.Bookmark{
 		UserID: "user-1",
 		URL:    "https://example.com",
@@ -251,6 +446,40 @@ func TestBookmarkService_CreateBookmark_RepositoryError(t *testing.T) {
 	}
 }
 
+// TestBookmarkService_CreateBookmark_Metadata tests that page metadata is recorded on the bookmark
+func TestBookmarkService_CreateBookmark_Metadata(t *testing.T) {
+	mockRepo := &MockBookmarkRepository{
+		createBookmarkFunc: func(bookmark model.Bookmark) (model.Bookmark, error) {
+			bookmark.ID = "bookmark-1"
+			return bookmark, nil
+		},
+	}
+	mockWebRepo := &MockWebRepository{
+		getTitleFunc: func(ctx context.Context, url string) (string, error) {
+			return "A Study of Things", nil
+		},
+		getPageMetadataFunc: func(ctx context.Context, url string) (model.PageMetadata, error) {
+			return model.PageMetadata{Author: "Jane Doe", PageCount: 12}, nil
+		},
+	}
+	mockUserRepo := &MockUserRepository{
+		getUserByIDFunc: func(id string) (model.User, error) {
+			return model.User{ID: "user-1", Tier: "free"}, nil
+		},
+	}
+	service := NewBookmarkService(mockRepo, mockUserRepo, mockWebRepo, NewEntitlementService(mockUserRepo, mockRepo, &MockUsageRepository{}), NewPolicy(&MockCollectionRepository{}), nil, nil, nil, nil)
+	result, err := service.CreateBookmark(model.Bookmark{
+		UserID: "user-1",
+		URL:    "https://example.com/paper.pdf",
+	})
+	if err != nil {
+		t.Fatalf("CreateBookmark() unexpected error = %v", err)
+	}
+	if result.Metadata.Author != "Jane Doe" || result.Metadata.PageCount != 12 {
+		t.Errorf("CreateBookmark() metadata = %+v, want author Jane Doe and 12 pages", result.Metadata)
+	}
+}
+
 // TestBookmarkService_CreateBookmark_GetMainImageError tests that GetMainImage errors are logged but don't fail the operation
 func TestBookmarkService_CreateBookmark_GetMainImageError(t *testing.T) {
 	expectedBookmark := model.Bookmark{
@@ -289,7 +518,7 @@ func TestBookmarkService_CreateBookmark_GetMainImageError(t *testing.T) {
 			return model.User{ID: "user-1", Tier: "free"}, nil
 		},
 	}
-	service := NewBookmarkService(mockRepo, mockUserRepo, mockWebRepo)
+	service := NewBookmarkService(mockRepo, mockUserRepo, mockWebRepo, NewEntitlementService(mockUserRepo, mockRepo, &MockUsageRepository{}), NewPolicy(&MockCollectionRepository{}), nil, nil, nil, nil)
 	result, err := service.CreateBookmark(model.Bookmark{
 		UserID: "user-1",
 		URL:    "https://example.com",
@@ -350,7 +579,7 @@ func TestBookmarkService_CreateBookmark_GetContentSummaryError(t *testing.T) {
 			return model.User{ID: "user-1", Tier: "paid"}, nil
 		},
 	}
-	service := NewBookmarkService(mockRepo, mockUserRepo, mockWebRepo)
+	service := NewBookmarkService(mockRepo, mockUserRepo, mockWebRepo, NewEntitlementService(mockUserRepo, mockRepo, &MockUsageRepository{}), NewPolicy(&MockCollectionRepository{}), nil, nil, nil, nil)
 	result, err := service.CreateBookmark(model.Bookmark{
 		UserID: "user-1",
 		URL:    "https://example.com",
@@ -413,7 +642,7 @@ func TestBookmarkService_CreateBookmark_FreeTierNoContentSummary(t *testing.T) {
 		},
 	}
 
-	service := NewBookmarkService(mockRepo, mockUserRepo, mockWebRepo)
+	service := NewBookmarkService(mockRepo, mockUserRepo, mockWebRepo, NewEntitlementService(mockUserRepo, mockRepo, &MockUsageRepository{}), NewPolicy(&MockCollectionRepository{}), nil, nil, nil, nil)
 	result, err := service.CreateBookmark(model.Bookmark{
 		UserID: "user-free",
 		URL:    "https://example.com",
@@ -476,7 +705,7 @@ func TestBookmarkService_CreateBookmark_PaidTierWithContentSummary(t *testing.T)
 		},
 	}
 
-	service := NewBookmarkService(mockRepo, mockUserRepo, mockWebRepo)
+	service := NewBookmarkService(mockRepo, mockUserRepo, mockWebRepo, NewEntitlementService(mockUserRepo, mockRepo, &MockUsageRepository{}), NewPolicy(&MockCollectionRepository{}), nil, nil, nil, nil)
 	result, err := service.CreateBookmark(model.Bookmark{
 		UserID: "user-paid",
 		URL:    "https://example.com",
@@ -538,7 +767,7 @@ func TestBookmarkService_CreateBookmark_LLMFeatureDisabled(t *testing.T) {
 		},
 	}
 
-	service := NewBookmarkService(mockRepo, mockUserRepo, mockWebRepo)
+	service := NewBookmarkService(mockRepo, mockUserRepo, mockWebRepo, NewEntitlementService(mockUserRepo, mockRepo, &MockUsageRepository{}), NewPolicy(&MockCollectionRepository{}), nil, nil, nil, nil)
 	result, err := service.CreateBookmark(model.Bookmark{
 		UserID: "user-paid",
 		URL:    "https://example.com",
@@ -554,6 +783,93 @@ func TestBookmarkService_CreateBookmark_LLMFeatureDisabled(t *testing.T) {
 	}
 }
 
+// TestBookmarkService_CreateBookmark_QuotaExceeded tests that the bookmark limit of the tier is enforced
+func TestBookmarkService_CreateBookmark_QuotaExceeded(t *testing.T) {
+	mockRepo := &MockBookmarkRepository{
+		countBookmarksFunc: func(query model.BookmarkQuery) (int, error) {
+			if query.Archived {
+				return 0, nil
+			}
+			return DefaultEntitlements()[model.TierFree].MaxBookmarks, nil
+		},
+		createBookmarkFunc: func(bookmark model.Bookmark) (model.Bookmark, error) {
+			t.Error("CreateBookmark() should not store a bookmark over the tier limit")
+			return bookmark, nil
+		},
+	}
+	mockWebRepo := &MockWebRepository{
+		getTitleFunc: func(ctx context.Context, url string) (string, error) {
+			t.Error("CreateBookmark() should not fetch metadata over the tier limit")
+			return "", nil
+		},
+	}
+	mockUserRepo := &MockUserRepository{
+		getUserByIDFunc: func(id string) (model.User, error) {
+			return model.User{ID: id, Tier: model.TierFree}, nil
+		},
+	}
+
+	service := NewBookmarkService(mockRepo, mockUserRepo, mockWebRepo, NewEntitlementService(mockUserRepo, mockRepo, &MockUsageRepository{}), NewPolicy(&MockCollectionRepository{}), nil, nil, nil, nil)
+	_, err := service.CreateBookmark(model.Bookmark{UserID: "user-1", URL: "https://example.com"})
+
+	if !errors.Is(err, model.ErrQuotaExceeded) {
+		t.Errorf("CreateBookmark() error = %v, want ErrQuotaExceeded", err)
+	}
+}
+
+// TestBookmarkService_CreateBookmark_SummaryQuota tests that summaries are counted and skipped once the monthly quota is used up
+func TestBookmarkService_CreateBookmark_SummaryQuota(t *testing.T) {
+	os.Setenv("LLM_SUMMARY_CONTENT", "true")
+	defer os.Unsetenv("LLM_SUMMARY_CONTENT")
+
+	used := 0
+	mockUsageRepo := &MockUsageRepository{
+		getUsageFunc: func(userID, metric, period string) (int, error) {
+			return used, nil
+		},
+		incrementUsageFunc: func(userID, metric, period string) (int, error) {
+			used++
+			return used, nil
+		},
+	}
+	summaryCalls := 0
+	mockWebRepo := &MockWebRepository{
+		getContentSummaryFunc: func(ctx context.Context, url string) (string, error) {
+			summaryCalls++
+			return "summary", nil
+		},
+	}
+	mockRepo := &MockBookmarkRepository{
+		createBookmarkFunc: func(bookmark model.Bookmark) (model.Bookmark, error) {
+			return bookmark, nil
+		},
+	}
+	mockUserRepo := &MockUserRepository{
+		getUserByIDFunc: func(id string) (model.User, error) {
+			return model.User{ID: id, Tier: model.TierPaid}, nil
+		},
+	}
+
+	service := NewBookmarkService(mockRepo, mockUserRepo, mockWebRepo, NewEntitlementService(mockUserRepo, mockRepo, mockUsageRepo), NewPolicy(&MockCollectionRepository{}), nil, nil, nil, nil)
+
+	created, err := service.CreateBookmark(model.Bookmark{UserID: "user-1", URL: "https://example.com"})
+	if err != nil {
+		t.Fatalf("CreateBookmark() unexpected error = %v", err)
+	}
+	if created.ContentSummary != "summary" || used != 1 {
+		t.Errorf("CreateBookmark() summary = %q usage = %d, want summary recorded once", created.ContentSummary, used)
+	}
+
+	used = DefaultEntitlements()[model.TierPaid].SummariesPerMonth
+	created, err = service.CreateBookmark(model.Bookmark{UserID: "user-1", URL: "https://example.com/2"})
+	if err != nil {
+		t.Fatalf("CreateBookmark() over summary quota unexpected error = %v", err)
+	}
+	if created.ContentSummary != "" || summaryCalls != 1 {
+		t.Errorf("CreateBookmark() over summary quota should skip summarization, got %q after %d calls", created.ContentSummary, summaryCalls)
+	}
+}
+
 // TestBookmarkService_CreateBookmark_GetUserByIDError tests error handling when GetUserByID fails
 func TestBookmarkService_CreateBookmark_GetUserByIDError(t *testing.T) {
 	mockRepo := &MockBookmarkRepository{}
@@ -577,7 +893,7 @@ func TestBookmarkService_CreateBookmark_GetUserByIDError(t *testing.T) {
 		},
 	}
 
-	service := NewBookmarkService(mockRepo, mockUserRepo, mockWebRepo)
+	service := NewBookmarkService(mockRepo, mockUserRepo, mockWebRepo, NewEntitlementService(mockUserRepo, mockRepo, &MockUsageRepository{}), NewPolicy(&MockCollectionRepository{}), nil, nil, nil, nil)
 	_, err := service.CreateBookmark(model.Bookmark{
 		UserID: "nonexistent-user",
 		URL:    "https://example.com",
@@ -621,7 +937,7 @@ func TestBookmarkService_CreateBookmark_EmptyURL(t *testing.T) {
 			return model.User{ID: "user-1", Tier: "free"}, nil
 		},
 	}
-	service := NewBookmarkService(mockRepo, mockUserRepo, mockWebRepo)
+	service := NewBookmarkService(mockRepo, mockUserRepo, mockWebRepo, NewEntitlementService(mockUserRepo, mockRepo, &MockUsageRepository{}), NewPolicy(&MockCollectionRepository{}), nil, nil, nil, nil)
 	result, err := service.CreateBookmark(model.Bookmark{
 		UserID: "user-1",
 		URL:    "",
@@ -662,7 +978,7 @@ func TestBookmarkService_CreateBookmark_EmptyUserID(t *testing.T) {
 			return model.User{}, fmt.Errorf("user not found")
 		},
 	}
-	service := NewBookmarkService(mockRepo, mockUserRepo, mockWebRepo)
+	service := NewBookmarkService(mockRepo, mockUserRepo, mockWebRepo, NewEntitlementService(mockUserRepo, mockRepo, &MockUsageRepository{}), NewPolicy(&MockCollectionRepository{}), nil, nil, nil, nil)
 	_, err := service.CreateBookmark(model.Bookmark{
 		UserID: "",
 		URL:    "https://example.com",
@@ -699,7 +1015,7 @@ func TestBookmarkService_CreateBookmark_NonEmptyID(t *testing.T) {
 		},
 	}
 	mockUserRepo := &MockUserRepository{}
-	service := NewBookmarkService(mockRepo, mockUserRepo, mockWebRepo)
+	service := NewBookmarkService(mockRepo, mockUserRepo, mockWebRepo, NewEntitlementService(mockUserRepo, mockRepo, &MockUsageRepository{}), NewPolicy(&MockCollectionRepository{}), nil, nil, nil, nil)
 	_, err := service.CreateBookmark(model.Bookmark{
 		ID:     "existing-id",
 		UserID: "user-1",
@@ -751,8 +1067,8 @@ func TestBookmarkService_ArchiveBookmark(t *testing.T) {
 
 	mockWebRepo := &MockWebRepository{}
 	mockUserRepo := &MockUserRepository{}
-	service := NewBookmarkService(mockRepo, mockUserRepo, mockWebRepo)
-	result, err := service.ArchiveBookmark("bookmark-1")
+	service := NewBookmarkService(mockRepo, mockUserRepo, mockWebRepo, NewEntitlementService(mockUserRepo, mockRepo, &MockUsageRepository{}), NewPolicy(&MockCollectionRepository{}), nil, nil, nil, nil)
+	result, err := service.ArchiveBookmark("user-1", "bookmark-1", 0)
 
 	if err != nil {
 		t.Errorf("ArchiveBookmark() unexpected error = %v", err)
@@ -777,8 +1093,8 @@ func TestBookmarkService_ArchiveBookmark_BookmarkNotFound(t *testing.T) {
 
 	mockWebRepo := &MockWebRepository{}
 	mockUserRepo := &MockUserRepository{}
-	service := NewBookmarkService(mockRepo, mockUserRepo, mockWebRepo)
-	_, err := service.ArchiveBookmark("nonexistent")
+	service := NewBookmarkService(mockRepo, mockUserRepo, mockWebRepo, NewEntitlementService(mockUserRepo, mockRepo, &MockUsageRepository{}), NewPolicy(&MockCollectionRepository{}), nil, nil, nil, nil)
+	_, err := service.ArchiveBookmark("user-1", "nonexistent", 0)
 
 	if err == nil {
 		t.Error("ArchiveBookmark() should return error when bookmark not found")
@@ -791,8 +1107,7 @@ func TestBookmarkService_ArchiveBookmark_BookmarkNotFound(t *testing.T) {
 	}
 }
 
-// TestBookmarkService_ArchiveBookmark_UnauthorizedUser tests that the service archives bookmark regardless of user
-// Note: The current implementation doesn't check authorization - it's the caller's responsibility
+// TestBookmarkService_ArchiveBookmark_UnauthorizedUser tests that only the owner can archive a bookmark
 func TestBookmarkService_ArchiveBookmark_UnauthorizedUser(t *testing.T) {
 	unauthorizedBookmark := model.Bookmark{
 		ID:         "bookmark-1",
@@ -803,9 +1118,6 @@ func TestBookmarkService_ArchiveBookmark_UnauthorizedUser(t *testing.T) {
 		CreatedAt:  time.Now(),
 	}
 
-	archivedBookmark := unauthorizedBookmark
-	archivedBookmark.IsArchived = true
-
 	mockRepo := &MockBookmarkRepository{
 		getBookmarkFunc: func(id string) (model.Bookmark, error) {
 			if id != "bookmark-1" {
@@ -814,25 +1126,18 @@ func TestBookmarkService_ArchiveBookmark_UnauthorizedUser(t *testing.T) {
 			return unauthorizedBookmark, nil
 		},
 		updateBookmarkFunc: func(bookmark model.Bookmark) (model.Bookmark, error) {
-			if !bookmark.IsArchived {
-				t.Error("UpdateBookmark() should receive bookmark with IsArchived = true")
-			}
-			return archivedBookmark, nil
+			t.Error("UpdateBookmark() should not be called for another user's bookmark")
+			return bookmark, nil
 		},
 	}
 
 	mockWebRepo := &MockWebRepository{}
 	mockUserRepo := &MockUserRepository{}
-	service := NewBookmarkService(mockRepo, mockUserRepo, mockWebRepo)
-	result, err := service.ArchiveBookmark("bookmark-1")
-
-	if err != nil {
-		t.Errorf("ArchiveBookmark() unexpected error = %v", err)
-		return
-	}
+	service := NewBookmarkService(mockRepo, mockUserRepo, mockWebRepo, NewEntitlementService(mockUserRepo, mockRepo, &MockUsageRepository{}), NewPolicy(&MockCollectionRepository{}), nil, nil, nil, nil)
+	_, err := service.ArchiveBookmark("user-1", "bookmark-1", 0)
 
-	if !result.IsArchived {
-		t.Error("ArchiveBookmark() result should have IsArchived = true")
+	if !errors.Is(err, model.ErrForbidden) {
+		t.Errorf("ArchiveBookmark() error = %v, want ErrForbidden", err)
 	}
 }
 
@@ -858,8 +1163,8 @@ func TestBookmarkService_ArchiveBookmark_UpdateError(t *testing.T) {
 
 	mockWebRepo := &MockWebRepository{}
 	mockUserRepo := &MockUserRepository{}
-	service := NewBookmarkService(mockRepo, mockUserRepo, mockWebRepo)
-	_, err := service.ArchiveBookmark("bookmark-1")
+	service := NewBookmarkService(mockRepo, mockUserRepo, mockWebRepo, NewEntitlementService(mockUserRepo, mockRepo, &MockUsageRepository{}), NewPolicy(&MockCollectionRepository{}), nil, nil, nil, nil)
+	_, err := service.ArchiveBookmark("user-1", "bookmark-1", 0)
 
 	if err == nil {
 		t.Error("ArchiveBookmark() should return error when update fails")
@@ -897,8 +1202,8 @@ func TestBookmarkService_ArchiveBookmark_AlreadyArchived(t *testing.T) {
 
 	mockWebRepo := &MockWebRepository{}
 	mockUserRepo := &MockUserRepository{}
-	service := NewBookmarkService(mockRepo, mockUserRepo, mockWebRepo)
-	result, err := service.ArchiveBookmark("bookmark-1")
+	service := NewBookmarkService(mockRepo, mockUserRepo, mockWebRepo, NewEntitlementService(mockUserRepo, mockRepo, &MockUsageRepository{}), NewPolicy(&MockCollectionRepository{}), nil, nil, nil, nil)
+	result, err := service.ArchiveBookmark("user-1", "bookmark-1", 0)
 
 	if err != nil {
 		t.Errorf("ArchiveBookmark() on already archived bookmark unexpected error = %v", err)
@@ -933,8 +1238,8 @@ func TestBookmarkService_ArchiveBookmark_EmptyUserID(t *testing.T) {
 
 	mockWebRepo := &MockWebRepository{}
 	mockUserRepo := &MockUserRepository{}
-	service := NewBookmarkService(mockRepo, mockUserRepo, mockWebRepo)
-	result, err := service.ArchiveBookmark("bookmark-1")
+	service := NewBookmarkService(mockRepo, mockUserRepo, mockWebRepo, NewEntitlementService(mockUserRepo, mockRepo, &MockUsageRepository{}), NewPolicy(&MockCollectionRepository{}), nil, nil, nil, nil)
+	result, err := service.ArchiveBookmark("", "bookmark-1", 0)
 
 	if err != nil {
 		t.Errorf("ArchiveBookmark() with empty userID unexpected error = %v", err)
@@ -959,8 +1264,8 @@ func TestBookmarkService_ArchiveBookmark_EmptyBookmarkID(t *testing.T) {
 
 	mockWebRepo := &MockWebRepository{}
 	mockUserRepo := &MockUserRepository{}
-	service := NewBookmarkService(mockRepo, mockUserRepo, mockWebRepo)
-	_, err := service.ArchiveBookmark("")
+	service := NewBookmarkService(mockRepo, mockUserRepo, mockWebRepo, NewEntitlementService(mockUserRepo, mockRepo, &MockUsageRepository{}), NewPolicy(&MockCollectionRepository{}), nil, nil, nil, nil)
+	_, err := service.ArchiveBookmark("user-1", "", 0)
 
 	if err == nil {
 		t.Error("ArchiveBookmark() with empty bookmarkID should return error")
@@ -996,8 +1301,8 @@ func TestBookmarkService_GetBookmark(t *testing.T) {
 
 	mockWebRepo := &MockWebRepository{}
 	mockUserRepo := &MockUserRepository{}
-	service := NewBookmarkService(mockRepo, mockUserRepo, mockWebRepo)
-	result, err := service.GetBookmark("bookmark-1")
+	service := NewBookmarkService(mockRepo, mockUserRepo, mockWebRepo, NewEntitlementService(mockUserRepo, mockRepo, &MockUsageRepository{}), NewPolicy(&MockCollectionRepository{}), nil, nil, nil, nil)
+	result, err := service.GetBookmark("user-1", "bookmark-1")
 
 	if err != nil {
 		t.Errorf("GetBookmark() unexpected error = %v", err)
@@ -1035,8 +1340,8 @@ func TestBookmarkService_GetBookmark_EmptyID(t *testing.T) {
 
 	mockWebRepo := &MockWebRepository{}
 	mockUserRepo := &MockUserRepository{}
-	service := NewBookmarkService(mockRepo, mockUserRepo, mockWebRepo)
-	_, err := service.GetBookmark("")
+	service := NewBookmarkService(mockRepo, mockUserRepo, mockWebRepo, NewEntitlementService(mockUserRepo, mockRepo, &MockUsageRepository{}), NewPolicy(&MockCollectionRepository{}), nil, nil, nil, nil)
+	_, err := service.GetBookmark("user-1", "")
 
 	if err == nil {
 		t.Error("GetBookmark() should return error when ID is empty")
@@ -1059,8 +1364,8 @@ func TestBookmarkService_GetBookmark_RepositoryError(t *testing.T) {
 
 	mockWebRepo := &MockWebRepository{}
 	mockUserRepo := &MockUserRepository{}
-	service := NewBookmarkService(mockRepo, mockUserRepo, mockWebRepo)
-	_, err := service.GetBookmark("bookmark-1")
+	service := NewBookmarkService(mockRepo, mockUserRepo, mockWebRepo, NewEntitlementService(mockUserRepo, mockRepo, &MockUsageRepository{}), NewPolicy(&MockCollectionRepository{}), nil, nil, nil, nil)
+	_, err := service.GetBookmark("user-1", "bookmark-1")
 
 	if err == nil {
 		t.Error("GetBookmark() should return error when repository fails")
@@ -1083,8 +1388,8 @@ func TestBookmarkService_GetBookmark_NotFound(t *testing.T) {
 
 	mockWebRepo := &MockWebRepository{}
 	mockUserRepo := &MockUserRepository{}
-	service := NewBookmarkService(mockRepo, mockUserRepo, mockWebRepo)
-	_, err := service.GetBookmark("nonexistent-id")
+	service := NewBookmarkService(mockRepo, mockUserRepo, mockWebRepo, NewEntitlementService(mockUserRepo, mockRepo, &MockUsageRepository{}), NewPolicy(&MockCollectionRepository{}), nil, nil, nil, nil)
+	_, err := service.GetBookmark("user-1", "nonexistent-id")
 
 	if err == nil {
 		t.Error("GetBookmark() should return error when bookmark not found")
@@ -1131,7 +1436,7 @@ func TestBookmarkService_GetAllBookmarks(t *testing.T) {
 
 	mockWebRepo := &MockWebRepository{}
 	mockUserRepo := &MockUserRepository{}
-	service := NewBookmarkService(mockRepo, mockUserRepo, mockWebRepo)
+	service := NewBookmarkService(mockRepo, mockUserRepo, mockWebRepo, NewEntitlementService(mockUserRepo, mockRepo, &MockUsageRepository{}), NewPolicy(&MockCollectionRepository{}), nil, nil, nil, nil)
 	result, err := service.GetAllBookmarks("user-1", false)
 
 	if err != nil {
@@ -1179,7 +1484,7 @@ func TestBookmarkService_GetAllBookmarks_EmptyUserID(t *testing.T) {
 
 	mockWebRepo := &MockWebRepository{}
 	mockUserRepo := &MockUserRepository{}
-	service := NewBookmarkService(mockRepo, mockUserRepo, mockWebRepo)
+	service := NewBookmarkService(mockRepo, mockUserRepo, mockWebRepo, NewEntitlementService(mockUserRepo, mockRepo, &MockUsageRepository{}), NewPolicy(&MockCollectionRepository{}), nil, nil, nil, nil)
 	result, err := service.GetAllBookmarks("", false)
 
 	if err != nil {
@@ -1202,7 +1507,7 @@ func TestBookmarkService_GetAllBookmarks_RepositoryError(t *testing.T) {
 
 	mockWebRepo := &MockWebRepository{}
 	mockUserRepo := &MockUserRepository{}
-	service := NewBookmarkService(mockRepo, mockUserRepo, mockWebRepo)
+	service := NewBookmarkService(mockRepo, mockUserRepo, mockWebRepo, NewEntitlementService(mockUserRepo, mockRepo, &MockUsageRepository{}), NewPolicy(&MockCollectionRepository{}), nil, nil, nil, nil)
 	_, err := service.GetAllBookmarks("user-1", false)
 
 	if err == nil {
@@ -1226,7 +1531,7 @@ func TestBookmarkService_GetAllBookmarks_NoBookmarks(t *testing.T) {
 
 	mockWebRepo := &MockWebRepository{}
 	mockUserRepo := &MockUserRepository{}
-	service := NewBookmarkService(mockRepo, mockUserRepo, mockWebRepo)
+	service := NewBookmarkService(mockRepo, mockUserRepo, mockWebRepo, NewEntitlementService(mockUserRepo, mockRepo, &MockUsageRepository{}), NewPolicy(&MockCollectionRepository{}), nil, nil, nil, nil)
 	result, err := service.GetAllBookmarks("user-1", false)
 
 	if err != nil {
@@ -1239,40 +1544,90 @@ func TestBookmarkService_GetAllBookmarks_NoBookmarks(t *testing.T) {
 	}
 }
 
-// TestBookmarkService_DeleteBookmark tests successful bookmark deletion
+// TestBookmarkService_DeleteBookmark tests that deleting moves the bookmark to the trash
 func TestBookmarkService_DeleteBookmark(t *testing.T) {
 	mockRepo := &MockBookmarkRepository{
-		deleteBookmarkFunc: func(id string) error {
+		getBookmarkFunc: func(id string) (model.Bookmark, error) {
+			return model.Bookmark{ID: id, UserID: "user-1"}, nil
+		},
+		trashBookmarkFunc: func(id string, deletedAt time.Time, version int64) (model.Bookmark, error) {
 			if id != "bookmark-1" {
-				t.Errorf("DeleteBookmark() received ID = %v, want bookmark-1", id)
+				t.Errorf("TrashBookmark() received ID = %v, want bookmark-1", id)
 			}
+			if deletedAt.IsZero() {
+				t.Error("TrashBookmark() received no deletion time")
+			}
+			return model.Bookmark{ID: id, UserID: "user-1", DeletedAt: deletedAt}, nil
+		},
+		deleteBookmarkFunc: func(id string) error {
+			t.Error("DeleteBookmark() should not permanently delete the bookmark")
 			return nil
 		},
 	}
 
 	mockWebRepo := &MockWebRepository{}
 	mockUserRepo := &MockUserRepository{}
-	service := NewBookmarkService(mockRepo, mockUserRepo, mockWebRepo)
-	err := service.DeleteBookmark("bookmark-1")
+	service := NewBookmarkService(mockRepo, mockUserRepo, mockWebRepo, NewEntitlementService(mockUserRepo, mockRepo, &MockUsageRepository{}), NewPolicy(&MockCollectionRepository{}), nil, nil, nil, nil)
+	err := service.DeleteBookmark("user-1", "bookmark-1", 0)
 
 	if err != nil {
 		t.Errorf("DeleteBookmark() unexpected error = %v", err)
 	}
 }
 
+// TestBookmarkService_DeleteBookmark_Version tests that deleting checks the version of the
+// bookmark up to the moment it is trashed
+func TestBookmarkService_DeleteBookmark_Version(t *testing.T) {
+	stored := model.Bookmark{ID: "bookmark-1", UserID: "user-1", Version: 3}
+	var trashedAt []int64
+	mockRepo := &MockBookmarkRepository{
+		getBookmarkFunc: func(id string) (model.Bookmark, error) {
+			return stored, nil
+		},
+		trashBookmarkFunc: func(id string, deletedAt time.Time, version int64) (model.Bookmark, error) {
+			trashedAt = append(trashedAt, version)
+			if len(trashedAt) == 1 {
+				// Changed by another write since it was read
+				stored.Version++
+				return model.Bookmark{}, model.ErrConflict
+			}
+			return stored, nil
+		},
+	}
+	mockUserRepo := &MockUserRepository{}
+	service := NewBookmarkService(mockRepo, mockUserRepo, &MockWebRepository{}, NewEntitlementService(mockUserRepo, mockRepo, &MockUsageRepository{}), NewPolicy(&MockCollectionRepository{}), nil, nil, nil, nil)
+
+	if err := service.DeleteBookmark("user-1", "bookmark-1", 2); !errors.Is(err, model.ErrPreconditionFailed) {
+		t.Errorf("DeleteBookmark() at a stale version error = %v, want ErrPreconditionFailed", err)
+	}
+	if len(trashedAt) != 0 {
+		t.Fatalf("DeleteBookmark() at a stale version trashed the bookmark")
+	}
+
+	if err := service.DeleteBookmark("user-1", "bookmark-1", 0); err != nil {
+		t.Fatalf("DeleteBookmark() unexpected error = %v", err)
+	}
+	if !slices.Equal(trashedAt, []int64{3, 4}) {
+		t.Errorf("TrashBookmark() received versions %v, want [3 4]", trashedAt)
+	}
+}
+
 // TestBookmarkService_DeleteBookmark_EmptyID tests deleting bookmark with empty ID
 func TestBookmarkService_DeleteBookmark_EmptyID(t *testing.T) {
 	mockRepo := &MockBookmarkRepository{
-		deleteBookmarkFunc: func(id string) error {
-			t.Error("DeleteBookmark() should not be called with empty ID")
-			return nil
+		getBookmarkFunc: func(id string) (model.Bookmark, error) {
+			return model.Bookmark{ID: id, UserID: "user-1"}, nil
+		},
+		trashBookmarkFunc: func(id string, deletedAt time.Time, version int64) (model.Bookmark, error) {
+			t.Error("TrashBookmark() should not be called with empty ID")
+			return model.Bookmark{}, nil
 		},
 	}
 
 	mockWebRepo := &MockWebRepository{}
 	mockUserRepo := &MockUserRepository{}
-	service := NewBookmarkService(mockRepo, mockUserRepo, mockWebRepo)
-	err := service.DeleteBookmark("")
+	service := NewBookmarkService(mockRepo, mockUserRepo, mockWebRepo, NewEntitlementService(mockUserRepo, mockRepo, &MockUsageRepository{}), NewPolicy(&MockCollectionRepository{}), nil, nil, nil, nil)
+	err := service.DeleteBookmark("user-1", "", 0)
 
 	if err == nil {
 		t.Error("DeleteBookmark() should return error when ID is empty")
@@ -1288,15 +1643,18 @@ func TestBookmarkService_DeleteBookmark_EmptyID(t *testing.T) {
 // TestBookmarkService_DeleteBookmark_RepositoryError tests error handling when repository fails
 func TestBookmarkService_DeleteBookmark_RepositoryError(t *testing.T) {
 	mockRepo := &MockBookmarkRepository{
-		deleteBookmarkFunc: func(id string) error {
-			return fmt.Errorf("database connection failed")
+		getBookmarkFunc: func(id string) (model.Bookmark, error) {
+			return model.Bookmark{ID: id, UserID: "user-1"}, nil
+		},
+		trashBookmarkFunc: func(id string, deletedAt time.Time, version int64) (model.Bookmark, error) {
+			return model.Bookmark{}, fmt.Errorf("database connection failed")
 		},
 	}
 
 	mockWebRepo := &MockWebRepository{}
 	mockUserRepo := &MockUserRepository{}
-	service := NewBookmarkService(mockRepo, mockUserRepo, mockWebRepo)
-	err := service.DeleteBookmark("bookmark-1")
+	service := NewBookmarkService(mockRepo, mockUserRepo, mockWebRepo, NewEntitlementService(mockUserRepo, mockRepo, &MockUsageRepository{}), NewPolicy(&MockCollectionRepository{}), nil, nil, nil, nil)
+	err := service.DeleteBookmark("user-1", "bookmark-1", 0)
 
 	if err == nil {
 		t.Error("DeleteBookmark() should return error when repository fails")
@@ -1312,15 +1670,18 @@ func TestBookmarkService_DeleteBookmark_RepositoryError(t *testing.T) {
 // TestBookmarkService_DeleteBookmark_NotFound tests deleting non-existent bookmark
 func TestBookmarkService_DeleteBookmark_NotFound(t *testing.T) {
 	mockRepo := &MockBookmarkRepository{
-		deleteBookmarkFunc: func(id string) error {
-			return fmt.Errorf("bookmark with ID %s not found", id)
+		getBookmarkFunc: func(id string) (model.Bookmark, error) {
+			return model.Bookmark{ID: id, UserID: "user-1"}, nil
+		},
+		trashBookmarkFunc: func(id string, deletedAt time.Time, version int64) (model.Bookmark, error) {
+			return model.Bookmark{}, fmt.Errorf("bookmark with ID %s not found", id)
 		},
 	}
 
 	mockWebRepo := &MockWebRepository{}
 	mockUserRepo := &MockUserRepository{}
-	service := NewBookmarkService(mockRepo, mockUserRepo, mockWebRepo)
-	err := service.DeleteBookmark("nonexistent-id")
+	service := NewBookmarkService(mockRepo, mockUserRepo, mockWebRepo, NewEntitlementService(mockUserRepo, mockRepo, &MockUsageRepository{}), NewPolicy(&MockCollectionRepository{}), nil, nil, nil, nil)
+	err := service.DeleteBookmark("user-1", "nonexistent-id", 0)
 
 	if err == nil {
 		t.Error("DeleteBookmark() should return error when bookmark not found")
@@ -1337,20 +1698,20 @@ func TestBookmarkService_DeleteBookmark_NotFound(t *testing.T) {
 func TestNewBookmarkService(t *testing.T) {
 	mockRepo := &MockBookmarkRepository{
 		getBookmarkFunc: func(id string) (model.Bookmark, error) {
-			return model.Bookmark{ID: "test-id"}, nil
+			return model.Bookmark{ID: "test-id", UserID: "user-1"}, nil
 		},
 	}
 	mockWebRepo := &MockWebRepository{}
 	mockUserRepo := &MockUserRepository{}
-	service := NewBookmarkService(mockRepo, mockUserRepo, mockWebRepo)
+	service := NewBookmarkService(mockRepo, mockUserRepo, mockWebRepo, NewEntitlementService(mockUserRepo, mockRepo, &MockUsageRepository{}), NewPolicy(&MockCollectionRepository{}), nil, nil, nil, nil)
 
 	// Verify service is usable by calling a method
-	result, err := service.GetBookmark("test-id")
+	result, err := service.GetBookmark("user-1", "test-id")
 	if err != nil {
-		t.Errorf("NewBookmarkService() service should be functional, got error = %v", err)
+		t.Errorf("NewBookmarkService(, nil, nil) service should be functional, got error = %v", err)
 	}
 	if result.ID != "test-id" {
-		t.Error("NewBookmarkService() service should work correctly")
+		t.Error("NewBookmarkService(, nil, nil) service should work correctly")
 	}
 }
 
@@ -1372,7 +1733,7 @@ func TestBookmarkService_GetBookmarksWithPagination(t *testing.T) {
 
 	mockWebRepo := &MockWebRepository{}
 	mockUserRepo := &MockUserRepository{}
-	service := NewBookmarkService(mockRepo, mockUserRepo, mockWebRepo)
+	service := NewBookmarkService(mockRepo, mockUserRepo, mockWebRepo, NewEntitlementService(mockUserRepo, mockRepo, &MockUsageRepository{}), NewPolicy(&MockCollectionRepository{}), nil, nil, nil, nil)
 	result, err := service.GetBookmarksWithPagination("user-1", false, 1, 20)
 
 	if err != nil {
@@ -1422,7 +1783,7 @@ func TestBookmarkService_GetBookmarksWithPagination_PageValidation(t *testing.T)
 
 	mockWebRepo := &MockWebRepository{}
 	mockUserRepo := &MockUserRepository{}
-	service := NewBookmarkService(mockRepo, mockUserRepo, mockWebRepo)
+	service := NewBookmarkService(mockRepo, mockUserRepo, mockWebRepo, NewEntitlementService(mockUserRepo, mockRepo, &MockUsageRepository{}), NewPolicy(&MockCollectionRepository{}), nil, nil, nil, nil)
 
 	// Test with page < 1 (should default to 1)
 	result, err := service.GetBookmarksWithPagination("user-1", false, 0, 20)
@@ -1458,7 +1819,7 @@ func TestBookmarkService_GetBookmarksWithPagination_PageSizeValidation(t *testin
 
 	mockWebRepo := &MockWebRepository{}
 	mockUserRepo := &MockUserRepository{}
-	service := NewBookmarkService(mockRepo, mockUserRepo, mockWebRepo)
+	service := NewBookmarkService(mockRepo, mockUserRepo, mockWebRepo, NewEntitlementService(mockUserRepo, mockRepo, &MockUsageRepository{}), NewPolicy(&MockCollectionRepository{}), nil, nil, nil, nil)
 
 	// Test with pageSize < 1 (should default to 20)
 	result, err := service.GetBookmarksWithPagination("user-1", false, 1, 0)
@@ -1520,7 +1881,7 @@ func TestBookmarkService_GetBookmarksWithPagination_TotalPagesCalculation(t *tes
 
 			mockWebRepo := &MockWebRepository{}
 			mockUserRepo := &MockUserRepository{}
-			service := NewBookmarkService(mockRepo, mockUserRepo, mockWebRepo)
+			service := NewBookmarkService(mockRepo, mockUserRepo, mockWebRepo, NewEntitlementService(mockUserRepo, mockRepo, &MockUsageRepository{}), NewPolicy(&MockCollectionRepository{}), nil, nil, nil, nil)
 			result, err := service.GetBookmarksWithPagination("user-1", false, 1, tc.pageSize)
 
 			if err != nil {
@@ -1548,7 +1909,7 @@ func TestBookmarkService_GetBookmarksWithPagination_EmptyResult(t *testing.T) {
 
 	mockWebRepo := &MockWebRepository{}
 	mockUserRepo := &MockUserRepository{}
-	service := NewBookmarkService(mockRepo, mockUserRepo, mockWebRepo)
+	service := NewBookmarkService(mockRepo, mockUserRepo, mockWebRepo, NewEntitlementService(mockUserRepo, mockRepo, &MockUsageRepository{}), NewPolicy(&MockCollectionRepository{}), nil, nil, nil, nil)
 	result, err := service.GetBookmarksWithPagination("user-1", false, 1, 20)
 
 	if err != nil {
@@ -1577,7 +1938,7 @@ func TestBookmarkService_GetBookmarksWithPagination_ListError(t *testing.T) {
 
 	mockWebRepo := &MockWebRepository{}
 	mockUserRepo := &MockUserRepository{}
-	service := NewBookmarkService(mockRepo, mockUserRepo, mockWebRepo)
+	service := NewBookmarkService(mockRepo, mockUserRepo, mockWebRepo, NewEntitlementService(mockUserRepo, mockRepo, &MockUsageRepository{}), NewPolicy(&MockCollectionRepository{}), nil, nil, nil, nil)
 	_, err := service.GetBookmarksWithPagination("user-1", false, 1, 20)
 
 	if err == nil {
@@ -1604,7 +1965,7 @@ func TestBookmarkService_GetBookmarksWithPagination_CountError(t *testing.T) {
 
 	mockWebRepo := &MockWebRepository{}
 	mockUserRepo := &MockUserRepository{}
-	service := NewBookmarkService(mockRepo, mockUserRepo, mockWebRepo)
+	service := NewBookmarkService(mockRepo, mockUserRepo, mockWebRepo, NewEntitlementService(mockUserRepo, mockRepo, &MockUsageRepository{}), NewPolicy(&MockCollectionRepository{}), nil, nil, nil, nil)
 	_, err := service.GetBookmarksWithPagination("user-1", false, 1, 20)
 
 	if err == nil {
@@ -1641,7 +2002,7 @@ func TestBookmarkService_GetBookmarksWithPagination_ArchivedFilter(t *testing.T)
 
 	mockWebRepo := &MockWebRepository{}
 	mockUserRepo := &MockUserRepository{}
-	service := NewBookmarkService(mockRepo, mockUserRepo, mockWebRepo)
+	service := NewBookmarkService(mockRepo, mockUserRepo, mockWebRepo, NewEntitlementService(mockUserRepo, mockRepo, &MockUsageRepository{}), NewPolicy(&MockCollectionRepository{}), nil, nil, nil, nil)
 
 	// Test with archived = false
 	result, err := service.GetBookmarksWithPagination("user-1", false, 1, 20)
@@ -1663,3 +2024,821 @@ func TestBookmarkService_GetBookmarksWithPagination_ArchivedFilter(t *testing.T)
 		t.Error("GetBookmarksWithPagination() with archived=true should return archived bookmarks")
 	}
 }
+
+func bookmarkIDs(bookmarks []model.Bookmark) string {
+	ids := make([]string, len(bookmarks))
+	for i, b := range bookmarks {
+		ids[i] = b.ID
+	}
+	return strings.Join(ids, ",")
+}
+
+func TestBookmarkService_ListBookmarks_Cursor(t *testing.T) {
+	// Five bookmarks of user-1, b5 the newest, paged from positions the way the real repositories do
+	base := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	var bookmarks []model.Bookmark
+	for i := 5; i >= 1; i-- {
+		bookmarks = append(bookmarks, model.Bookmark{ID: fmt.Sprintf("b%d", i), UserID: "user-1", CreatedAt: base.Add(time.Duration(i) * time.Hour)})
+	}
+	mockRepo := &MockBookmarkRepository{
+		listBookmarksByQueryFunc: func(query model.BookmarkQuery) ([]model.Bookmark, error) {
+			p := query.Position
+			if p == nil {
+				start := (query.Page - 1) * query.PageSize
+				return bookmarks[start:min(len(bookmarks), start+query.PageSize)], nil
+			}
+			var page []model.Bookmark
+			for _, b := range bookmarks {
+				if p.Backward && b.CreatedAt.After(p.CreatedAt) || !p.Backward && b.CreatedAt.Before(p.CreatedAt) {
+					page = append(page, b)
+				}
+			}
+			if p.Backward {
+				return page[max(0, len(page)-query.PageSize):], nil
+			}
+			return page[:min(len(page), query.PageSize)], nil
+		},
+		countBookmarksFunc: func(query model.BookmarkQuery) (int, error) {
+			return len(bookmarks), nil
+		},
+	}
+	service := NewBookmarkService(mockRepo, &MockUserRepository{}, &MockWebRepository{}, nil, NewPolicy(&MockCollectionRepository{}), nil, nil, nil, nil)
+
+	first, err := service.ListBookmarks(model.BookmarkQuery{UserID: "user-1", Page: 1, PageSize: 2})
+	if err != nil {
+		t.Fatalf("ListBookmarks() unexpected error = %v", err)
+	}
+	if bookmarkIDs(first.Bookmarks) != "b5,b4" || first.NextCursor == "" || first.PrevCursor != "" {
+		t.Fatalf("ListBookmarks() first page = %+v, want b5,b4 with only a next cursor", first)
+	}
+
+	// Walk forward to the end
+	second, err := service.ListBookmarks(model.BookmarkQuery{UserID: "user-1", PageSize: 2, Cursor: first.NextCursor})
+	if err != nil {
+		t.Fatalf("ListBookmarks() unexpected error = %v", err)
+	}
+	if bookmarkIDs(second.Bookmarks) != "b3,b2" || second.NextCursor == "" || second.PrevCursor == "" {
+		t.Fatalf("ListBookmarks() second page = %+v, want b3,b2 with both cursors", second)
+	}
+	if second.TotalCount != 0 || second.TotalPages != 0 {
+		t.Errorf("ListBookmarks() from a cursor counted %d bookmarks, want no totals", second.TotalCount)
+	}
+	last, err := service.ListBookmarks(model.BookmarkQuery{UserID: "user-1", PageSize: 2, Cursor: second.NextCursor})
+	if err != nil {
+		t.Fatalf("ListBookmarks() unexpected error = %v", err)
+	}
+	if bookmarkIDs(last.Bookmarks) != "b1" || last.NextCursor != "" || last.PrevCursor == "" {
+		t.Fatalf("ListBookmarks() last page = %+v, want b1 with only a previous cursor", last)
+	}
+
+	// And back again
+	back, err := service.ListBookmarks(model.BookmarkQuery{UserID: "user-1", PageSize: 2, Cursor: last.PrevCursor})
+	if err != nil {
+		t.Fatalf("ListBookmarks() unexpected error = %v", err)
+	}
+	if bookmarkIDs(back.Bookmarks) != "b3,b2" || back.NextCursor == "" || back.PrevCursor == "" {
+		t.Fatalf("ListBookmarks() going back = %+v, want b3,b2 with both cursors", back)
+	}
+	top, err := service.ListBookmarks(model.BookmarkQuery{UserID: "user-1", PageSize: 2, Cursor: back.PrevCursor})
+	if err != nil {
+		t.Fatalf("ListBookmarks() unexpected error = %v", err)
+	}
+	if bookmarkIDs(top.Bookmarks) != "b5,b4" || top.NextCursor == "" || top.PrevCursor != "" {
+		t.Errorf("ListBookmarks() back at the top = %+v, want b5,b4 with only a next cursor", top)
+	}
+}
+
+func TestBookmarkService_ListBookmarks_InvalidCursor(t *testing.T) {
+	// Five bookmarks of user-1, b5 the newest, paged from positions the way the real repositories do
+	base := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	var bookmarks []model.Bookmark
+	for i := 5; i >= 1; i-- {
+		bookmarks = append(bookmarks, model.Bookmark{ID: fmt.Sprintf("b%d", i), UserID: "user-1", CreatedAt: base.Add(time.Duration(i) * time.Hour)})
+	}
+	mockRepo := &MockBookmarkRepository{
+		listBookmarksByQueryFunc: func(query model.BookmarkQuery) ([]model.Bookmark, error) {
+			p := query.Position
+			if p == nil {
+				start := (query.Page - 1) * query.PageSize
+				return bookmarks[start:min(len(bookmarks), start+query.PageSize)], nil
+			}
+			var page []model.Bookmark
+			for _, b := range bookmarks {
+				if p.Backward && b.CreatedAt.After(p.CreatedAt) || !p.Backward && b.CreatedAt.Before(p.CreatedAt) {
+					page = append(page, b)
+				}
+			}
+			if p.Backward {
+				return page[max(0, len(page)-query.PageSize):], nil
+			}
+			return page[:min(len(page), query.PageSize)], nil
+		},
+		countBookmarksFunc: func(query model.BookmarkQuery) (int, error) {
+			return len(bookmarks), nil
+		},
+	}
+	service := NewBookmarkService(mockRepo, &MockUserRepository{}, &MockWebRepository{}, nil, NewPolicy(&MockCollectionRepository{}), nil, nil, nil, nil)
+	page, err := service.ListBookmarks(model.BookmarkQuery{UserID: "user-1", Page: 1, PageSize: 2})
+	if err != nil {
+		t.Fatalf("ListBookmarks() unexpected error = %v", err)
+	}
+	payload, signature, _ := strings.Cut(page.NextCursor, ".")
+	forged := base64.RawURLEncoding.EncodeToString([]byte(`{"c":"2030-01-01T00:00:00Z","i":"b9"}`)) + "." + signature
+
+	// A cursor signed with another key is as good as a forged one
+	other := NewBookmarkService(mockRepo, &MockUserRepository{}, &MockWebRepository{}, nil, NewPolicy(&MockCollectionRepository{}), nil, nil, nil, []byte("another-secret"))
+
+	for name, tc := range map[string]struct {
+		service *BookmarkService
+		cursor  string
+	}{
+		"garbage":      {service, "not-a-cursor"},
+		"bad encoding": {service, payload + ".!!!"},
+		"forged":       {service, forged},
+		"other key":    {other, page.NextCursor},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := tc.service.ListBookmarks(model.BookmarkQuery{UserID: "user-1", PageSize: 2, Cursor: tc.cursor})
+			if !errors.Is(err, model.ErrInvalidInput) {
+				t.Errorf("ListBookmarks() error = %v, want ErrInvalidInput", err)
+			}
+		})
+	}
+}
+
+func TestDeriveCursorKey(t *testing.T) {
+	key := DeriveCursorKey("jwt-secret")
+	if !bytes.Equal(key, DeriveCursorKey("jwt-secret")) {
+		t.Errorf("DeriveCursorKey() = %x, want the same key for the same secret", key)
+	}
+	if bytes.Equal(key, []byte("jwt-secret")) || bytes.Equal(key, DeriveCursorKey("other-secret")) {
+		t.Errorf("DeriveCursorKey() = %x, want a key of its own for each secret", key)
+	}
+}
+
+func TestBookmarkService_ListBookmarks_NormalizesQuery(t *testing.T) {
+	var got model.BookmarkQuery
+	mockRepo := &MockBookmarkRepository{
+		listBookmarksByQueryFunc: func(query model.BookmarkQuery) ([]model.Bookmark, error) {
+			got = query
+			return []model.Bookmark{}, nil
+		},
+	}
+	service := NewBookmarkService(mockRepo, &MockUserRepository{}, &MockWebRepository{}, nil, NewPolicy(&MockCollectionRepository{}), nil, nil, nil, nil)
+
+	if _, err := service.ListBookmarks(model.BookmarkQuery{UserID: "user-1", Domain: "WWW.Example.com", Tag: " Go ", Sort: model.BookmarkSortTitle}); err != nil {
+		t.Fatalf("ListBookmarks() unexpected error = %v", err)
+	}
+	if got.Domain != "example.com" || got.Tag != "go" || got.Order != model.SortAscending {
+		t.Errorf("repository query = %+v, want normalized domain and tag, ascending by title", got)
+	}
+	if _, err := service.ListBookmarks(model.BookmarkQuery{UserID: "user-1"}); err != nil {
+		t.Fatalf("ListBookmarks() unexpected error = %v", err)
+	}
+	if got.Sort != model.BookmarkSortCreated || got.Order != model.SortDescending {
+		t.Errorf("repository query = %+v, want newest created first by default", got)
+	}
+}
+
+func TestBookmarkService_ListBookmarks_InvalidQuery(t *testing.T) {
+	mockRepo := &MockBookmarkRepository{
+		listBookmarksByQueryFunc: func(query model.BookmarkQuery) ([]model.Bookmark, error) {
+			t.Errorf("ListBookmarks() should not reach the repository with query %+v", query)
+			return nil, nil
+		},
+	}
+	service := NewBookmarkService(mockRepo, &MockUserRepository{}, &MockWebRepository{}, nil, NewPolicy(&MockCollectionRepository{}), nil, nil, nil, nil)
+	now := time.Now()
+
+	for name, query := range map[string]model.BookmarkQuery{
+		"unknown sort":           {Sort: "popularity"},
+		"unknown order":          {Order: "random"},
+		"unknown status":         {EnrichmentStatus: "pending"},
+		"tag and collection":     {Tag: "go", CollectionID: "c1"},
+		"empty created range":    {CreatedFrom: now, CreatedTo: now.Add(-time.Hour)},
+		"cursor of another sort": {Sort: model.BookmarkSortTitle, Cursor: service.cursors.encode(model.BookmarkPosition{Sort: model.BookmarkSortCreated, Order: model.SortDescending, ID: "b1"})},
+	} {
+		t.Run(name, func(t *testing.T) {
+			query.UserID = "user-1"
+			if _, err := service.ListBookmarks(query); !errors.Is(err, model.ErrInvalidInput) {
+				t.Errorf("ListBookmarks() error = %v, want ErrInvalidInput", err)
+			}
+		})
+	}
+}
+
+func TestBookmarkService_ListBookmarks_CursorSortedByTitle(t *testing.T) {
+	bookmarks := []model.Bookmark{
+		{ID: "b1", UserID: "user-1", Title: "Apple"},
+		{ID: "b2", UserID: "user-1", Title: "banana"},
+		{ID: "b3", UserID: "user-1", Title: "Cherry"},
+	}
+	var positions []model.BookmarkPosition
+	mockRepo := &MockBookmarkRepository{
+		listBookmarksByQueryFunc: func(query model.BookmarkQuery) ([]model.Bookmark, error) {
+			if query.Position != nil {
+				positions = append(positions, *query.Position)
+				return bookmarks[2:], nil
+			}
+			return bookmarks[:2], nil
+		},
+		countBookmarksFunc: func(query model.BookmarkQuery) (int, error) {
+			return len(bookmarks), nil
+		},
+	}
+	service := NewBookmarkService(mockRepo, &MockUserRepository{}, &MockWebRepository{}, nil, NewPolicy(&MockCollectionRepository{}), nil, nil, nil, nil)
+
+	first, err := service.ListBookmarks(model.BookmarkQuery{UserID: "user-1", Sort: model.BookmarkSortTitle, Page: 1, PageSize: 2})
+	if err != nil {
+		t.Fatalf("ListBookmarks() unexpected error = %v", err)
+	}
+	if _, err := service.ListBookmarks(model.BookmarkQuery{UserID: "user-1", Sort: model.BookmarkSortTitle, PageSize: 2, Cursor: first.NextCursor}); err != nil {
+		t.Fatalf("ListBookmarks() unexpected error = %v", err)
+	}
+	want := model.BookmarkPosition{Sort: model.BookmarkSortTitle, Order: model.SortAscending, Text: "banana", ID: "b2"}
+	if len(positions) != 1 || positions[0] != want {
+		t.Errorf("repository positions = %+v, want %+v", positions, want)
+	}
+}
+
+func TestBookmarkService_CreateBookmark_Tags(t *testing.T) {
+	mockRepo := &MockBookmarkRepository{
+		createBookmarkFunc: func(bookmark model.Bookmark) (model.Bookmark, error) {
+			return bookmark, nil
+		},
+	}
+	mockUserRepo := &MockUserRepository{}
+	service := NewBookmarkService(mockRepo, mockUserRepo, &MockWebRepository{}, NewEntitlementService(mockUserRepo, mockRepo, &MockUsageRepository{}), NewPolicy(&MockCollectionRepository{}), nil, nil, nil, nil)
+
+	created, err := service.CreateBookmark(model.Bookmark{UserID: "user-1", URL: "https://example.com", Tags: []string{" Go", "go", "", "Reading List"}})
+	if err != nil {
+		t.Fatalf("CreateBookmark() unexpected error = %v", err)
+	}
+	if !slices.Equal(created.Tags, []string{"go", "reading list"}) {
+		t.Errorf("CreateBookmark() tags = %v, want [go reading list]", created.Tags)
+	}
+
+	tags := make([]string, model.MaxBookmarkTags+1)
+	for i := range tags {
+		tags[i] = fmt.Sprintf("tag-%d", i)
+	}
+	if _, err := service.CreateBookmark(model.Bookmark{UserID: "user-1", URL: "https://example.com", Tags: tags}); !errors.Is(err, model.ErrInvalidInput) {
+		t.Errorf("CreateBookmark() with %d tags error = %v, want ErrInvalidInput", len(tags), err)
+	}
+}
+
+func TestBookmarkService_OpenBookmark(t *testing.T) {
+	var updated model.Bookmark
+	mockRepo := &MockBookmarkRepository{
+		getBookmarkFunc: func(id string) (model.Bookmark, error) {
+			return model.Bookmark{ID: id, UserID: "user-1"}, nil
+		},
+		updateBookmarkFunc: func(bookmark model.Bookmark) (model.Bookmark, error) {
+			updated = bookmark
+			return bookmark, nil
+		},
+	}
+	service := NewBookmarkService(mockRepo, &MockUserRepository{}, &MockWebRepository{}, nil, NewPolicy(&MockCollectionRepository{}), nil, nil, nil, nil)
+
+	before := time.Now()
+	if _, err := service.OpenBookmark("user-1", "b1"); err != nil {
+		t.Fatalf("OpenBookmark() unexpected error = %v", err)
+	}
+	if updated.LastOpenedAt.Before(before) {
+		t.Errorf("OpenBookmark() LastOpenedAt = %v, want the current time", updated.LastOpenedAt)
+	}
+	if _, err := service.OpenBookmark("user-2", "b1"); !errors.Is(err, model.ErrForbidden) {
+		t.Errorf("OpenBookmark() by another user error = %v, want ErrForbidden", err)
+	}
+}
+
+func TestBookmarkService_UpdateReadingState(t *testing.T) {
+	yes, no := true, false
+	progress := func(p int) *int { return &p }
+	readAt := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		stored   model.Bookmark
+		update   model.ReadingStateUpdate
+		read     bool
+		favorite bool
+		progress int
+	}{
+		{"mark read", model.Bookmark{}, model.ReadingStateUpdate{IsRead: &yes}, true, false, 0},
+		{"favorite", model.Bookmark{}, model.ReadingStateUpdate{IsFavorite: &yes}, false, true, 0},
+		{"progress", model.Bookmark{}, model.ReadingStateUpdate{ReadingProgress: progress(40)}, false, false, 40},
+		{"progress to the end", model.Bookmark{}, model.ReadingStateUpdate{ReadingProgress: progress(100)}, true, false, 100},
+		{"finished but unread", model.Bookmark{}, model.ReadingStateUpdate{ReadingProgress: progress(100), IsRead: &no}, false, false, 100},
+		{"mark unread", model.Bookmark{IsRead: true, ReadAt: readAt, ReadingProgress: 100, IsFavorite: true}, model.ReadingStateUpdate{IsRead: &no}, false, true, 0},
+		{"read again", model.Bookmark{IsRead: true, ReadAt: readAt, ReadingProgress: 100}, model.ReadingStateUpdate{IsRead: &yes, ReadingProgress: progress(20)}, true, false, 20},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stored := tt.stored
+			stored.ID, stored.UserID = "b1", "user-1"
+			var updated model.Bookmark
+			mockRepo := &MockBookmarkRepository{
+				getBookmarkFunc: func(id string) (model.Bookmark, error) {
+					return stored, nil
+				},
+				updateBookmarkFunc: func(bookmark model.Bookmark) (model.Bookmark, error) {
+					updated = bookmark
+					return bookmark, nil
+				},
+			}
+			service := NewBookmarkService(mockRepo, &MockUserRepository{}, &MockWebRepository{}, nil, NewPolicy(&MockCollectionRepository{}), nil, nil, nil, nil)
+
+			if _, err := service.UpdateReadingState("user-1", "b1", tt.update, 0); err != nil {
+				t.Fatalf("UpdateReadingState() unexpected error = %v", err)
+			}
+			if updated.IsRead != tt.read || updated.IsFavorite != tt.favorite || updated.ReadingProgress != tt.progress {
+				t.Errorf("UpdateReadingState() = read %v, favorite %v, progress %d, want %v, %v, %d",
+					updated.IsRead, updated.IsFavorite, updated.ReadingProgress, tt.read, tt.favorite, tt.progress)
+			}
+			switch {
+			case updated.IsRead && updated.ReadAt.IsZero():
+				t.Error("UpdateReadingState() left ReadAt unset on a read bookmark")
+			case !updated.IsRead && !updated.ReadAt.IsZero():
+				t.Error("UpdateReadingState() kept ReadAt on an unread bookmark")
+			case stored.IsRead && updated.IsRead && !updated.ReadAt.Equal(readAt):
+				t.Errorf("UpdateReadingState() ReadAt = %v, want the time it was first read", updated.ReadAt)
+			}
+		})
+	}
+}
+
+func TestBookmarkService_UpdateReadingState_Rejected(t *testing.T) {
+	yes := true
+	mockRepo := &MockBookmarkRepository{
+		getBookmarkFunc: func(id string) (model.Bookmark, error) {
+			return model.Bookmark{ID: id, UserID: "user-1"}, nil
+		},
+		updateBookmarkFunc: func(bookmark model.Bookmark) (model.Bookmark, error) {
+			t.Errorf("UpdateReadingState() updated a bookmark it should have rejected")
+			return bookmark, nil
+		},
+	}
+	service := NewBookmarkService(mockRepo, &MockUserRepository{}, &MockWebRepository{}, nil, NewPolicy(&MockCollectionRepository{}), nil, nil, nil, nil)
+
+	for _, p := range []int{-1, 101} {
+		if _, err := service.UpdateReadingState("user-1", "b1", model.ReadingStateUpdate{ReadingProgress: &p}, 0); !errors.Is(err, model.ErrInvalidInput) {
+			t.Errorf("UpdateReadingState() with progress %d error = %v, want ErrInvalidInput", p, err)
+		}
+	}
+	if _, err := service.UpdateReadingState("user-1", "b1", model.ReadingStateUpdate{}, 0); !errors.Is(err, model.ErrInvalidInput) {
+		t.Errorf("UpdateReadingState() without changes error = %v, want ErrInvalidInput", err)
+	}
+	if _, err := service.UpdateReadingState("user-2", "b1", model.ReadingStateUpdate{IsFavorite: &yes}, 0); !errors.Is(err, model.ErrForbidden) {
+		t.Errorf("UpdateReadingState() by another user error = %v, want ErrForbidden", err)
+	}
+}
+
+func TestBookmarkService_UpdateNotes(t *testing.T) {
+	var updated model.Bookmark
+	mockRepo := &MockBookmarkRepository{
+		getBookmarkFunc: func(id string) (model.Bookmark, error) {
+			return model.Bookmark{ID: id, UserID: "user-1", Notes: "old"}, nil
+		},
+		updateBookmarkFunc: func(bookmark model.Bookmark) (model.Bookmark, error) {
+			updated = bookmark
+			return bookmark, nil
+		},
+	}
+	service := NewBookmarkService(mockRepo, &MockUserRepository{}, &MockWebRepository{}, nil, NewPolicy(&MockCollectionRepository{}), nil, nil, nil, nil)
+
+	if _, err := service.UpdateNotes("user-1", "b1", "## Takeaways\n- Read twice", 0); err != nil {
+		t.Fatalf("UpdateNotes() unexpected error = %v", err)
+	}
+	if updated.Notes != "## Takeaways\n- Read twice" {
+		t.Errorf("UpdateNotes() notes = %q, want the new Markdown", updated.Notes)
+	}
+	if _, err := service.UpdateNotes("user-1", "b1", strings.Repeat("x", model.MaxNoteLength+1), 0); !errors.Is(err, model.ErrInvalidInput) {
+		t.Errorf("UpdateNotes() too long error = %v, want ErrInvalidInput", err)
+	}
+	if _, err := service.UpdateNotes("user-2", "b1", "", 0); !errors.Is(err, model.ErrForbidden) {
+		t.Errorf("UpdateNotes() by another user error = %v, want ErrForbidden", err)
+	}
+}
+
+func TestBookmarkService_PublishesChangeEvents(t *testing.T) {
+	mockRepo := &MockBookmarkRepository{
+		getBookmarkFunc: func(id string) (model.Bookmark, error) {
+			return model.Bookmark{ID: id, UserID: "user-1", Title: "Go"}, nil
+		},
+		updateBookmarkFunc: func(bookmark model.Bookmark) (model.Bookmark, error) {
+			return bookmark, nil
+		},
+	}
+	events := NewEventHub(nil)
+	sub, _ := events.Subscribe("user-1", "")
+	defer sub.Close()
+	service := NewBookmarkService(mockRepo, &MockUserRepository{}, &MockWebRepository{}, nil, NewPolicy(&MockCollectionRepository{}), nil, nil, events, nil)
+
+	if _, err := service.UpdateNotes("user-1", "b1", "Worth a reread", 0); err != nil {
+		t.Fatalf("UpdateNotes() unexpected error = %v", err)
+	}
+	if _, err := service.ArchiveBookmark("user-1", "b1", 0); err != nil {
+		t.Fatalf("ArchiveBookmark() unexpected error = %v", err)
+	}
+	if err := service.DeleteBookmark("user-1", "b1", 0); err != nil {
+		t.Fatalf("DeleteBookmark() unexpected error = %v", err)
+	}
+	if _, err := service.UpdateNotes("user-2", "b1", "", 0); err == nil {
+		t.Fatal("UpdateNotes() by another user should fail")
+	}
+
+	if event := receiveEvent(t, sub); event.Type != model.EventBookmarkUpdated || event.Bookmark.Notes != "Worth a reread" {
+		t.Errorf("UpdateNotes() published %+v, want the updated bookmark", event)
+	}
+	if event := receiveEvent(t, sub); event.Type != model.EventBookmarkArchived || !event.Bookmark.IsArchived {
+		t.Errorf("ArchiveBookmark() published %+v, want the archived bookmark", event)
+	}
+	if event := receiveEvent(t, sub); event.Type != model.EventBookmarkDeleted || event.Bookmark.ID != "b1" {
+		t.Errorf("DeleteBookmark() published %+v, want the deleted bookmark", event)
+	}
+	select {
+	case event := <-sub.Events:
+		t.Errorf("rejected change published %+v", event)
+	default:
+	}
+}
+
+func TestBookmarkService_CreateBookmark_RecordsEnrichment(t *testing.T) {
+	t.Setenv("LLM_SUMMARY_CONTENT", "true")
+	mockRepo := &MockBookmarkRepository{
+		createBookmarkFunc: func(bookmark model.Bookmark) (model.Bookmark, error) {
+			return bookmark, nil
+		},
+	}
+	mockWebRepo := &MockWebRepository{
+		getMainImageFunc: func(ctx context.Context, url string) (string, error) {
+			return "", errors.New("timeout")
+		},
+	}
+	mockUserRepo := &MockUserRepository{
+		getUserByIDFunc: func(id string) (model.User, error) {
+			return model.User{ID: "user-1", Tier: model.TierFree}, nil
+		},
+	}
+
+	service := NewBookmarkService(mockRepo, mockUserRepo, mockWebRepo, NewEntitlementService(mockUserRepo, mockRepo, &MockUsageRepository{}), NewPolicy(&MockCollectionRepository{}), nil, nil, nil, nil)
+	result, err := service.CreateBookmark(model.Bookmark{UserID: "user-1", URL: "https://example.com"})
+	if err != nil {
+		t.Fatalf("CreateBookmark() unexpected error = %v", err)
+	}
+
+	if len(result.Enrichments) != 1 {
+		t.Fatalf("CreateBookmark() recorded %d enrichments, want 1", len(result.Enrichments))
+	}
+	e := result.Enrichments[0]
+	if e.Trigger != model.EnrichmentTriggerCreate || e.Summary != model.EnrichmentSummaryNotAvailable || e.At.IsZero() {
+		t.Errorf("CreateBookmark() enrichment = %+v, want a create run without a summary", e)
+	}
+	if fmt.Sprint(e.Updated) != "[title]" || fmt.Sprint(e.Failed) != "[main_image]" {
+		t.Errorf("CreateBookmark() enrichment updated %v and failed %v, want [title] and [main_image]", e.Updated, e.Failed)
+	}
+}
+
+func TestBookmarkService_RefreshBookmark_AfterUpgrade(t *testing.T) {
+	t.Setenv("LLM_SUMMARY_CONTENT", "true")
+	t.Setenv("LLM_MODEL", "anthropic")
+	recorded := 0
+	usageRepo := &MockUsageRepository{
+		incrementUsageFunc: func(userID, metric, period string) (int, error) {
+			recorded++
+			return recorded, nil
+		},
+	}
+	webRepo := &MockWebRepository{
+		getTitleFunc: func(ctx context.Context, url string) (string, error) {
+			return "Example Domain", nil
+		},
+		getContentSummaryFunc: func(ctx context.Context, url string) (string, error) {
+			return "A page used in documentation.", nil
+		},
+	}
+	// Saved on the free tier while the site was down
+	bookmark := model.Bookmark{
+		ID:          "b1",
+		UserID:      "user-1",
+		URL:         "https://example.com",
+		Enrichments: []model.Enrichment{{Trigger: model.EnrichmentTriggerCreate, Summary: model.EnrichmentSummaryNotAvailable, Failed: []string{"title"}}},
+	}
+	bookmarkRepo := &MockBookmarkRepository{
+		getBookmarkFunc: func(id string) (model.Bookmark, error) {
+			if id != bookmark.ID {
+				return model.Bookmark{}, model.ErrNotFound
+			}
+			return bookmark, nil
+		},
+		updateBookmarkFunc: func(b model.Bookmark) (model.Bookmark, error) {
+			bookmark = b
+			return b, nil
+		},
+	}
+	userRepo := &MockUserRepository{
+		getUserByIDFunc: func(id string) (model.User, error) {
+			return model.User{ID: "user-1", Tier: model.TierPaid}, nil
+		},
+	}
+	service := NewBookmarkService(bookmarkRepo, userRepo, webRepo, NewEntitlementService(userRepo, bookmarkRepo, usageRepo), NewPolicy(&MockCollectionRepository{}), nil, nil, nil, nil)
+
+	updated, err := service.RefreshBookmark("user-1", "b1")
+	if err != nil {
+		t.Fatalf("RefreshBookmark() unexpected error = %v", err)
+	}
+
+	if updated.Title != "Example Domain" || updated.ContentSummary != "A page used in documentation." || bookmark.Title != updated.Title {
+		t.Errorf("RefreshBookmark() = %+v, want title and summary filled in", updated)
+	}
+	if recorded != 1 {
+		t.Errorf("RefreshBookmark() recorded %d summaries, want 1", recorded)
+	}
+	if len(updated.Enrichments) != 2 {
+		t.Fatalf("RefreshBookmark() history has %d runs, want 2", len(updated.Enrichments))
+	}
+	e := updated.Enrichments[1]
+	if e.Trigger != model.EnrichmentTriggerRefresh || e.Summary != model.EnrichmentSummaryGenerated || e.SummaryProvider != "anthropic" {
+		t.Errorf("RefreshBookmark() enrichment = %+v, want a refresh that generated a summary with anthropic", e)
+	}
+	if fmt.Sprint(e.Updated) != "[title summary]" {
+		t.Errorf("RefreshBookmark() updated = %v, want [title summary]", e.Updated)
+	}
+}
+
+func TestBookmarkService_RefreshBookmark_KeepsExistingValues(t *testing.T) {
+	t.Setenv("LLM_SUMMARY_CONTENT", "true")
+	webRepo := &MockWebRepository{
+		getTitleFunc: func(ctx context.Context, url string) (string, error) {
+			return "", errors.New("status 503")
+		},
+		getContentSummaryFunc: func(ctx context.Context, url string) (string, error) {
+			t.Error("RefreshBookmark() should not replace an existing summary")
+			return "", nil
+		},
+	}
+	bookmark := model.Bookmark{ID: "b1", UserID: "user-1", URL: "https://example.com", Title: "Saved Title", ContentSummary: "Saved summary"}
+	bookmarkRepo := &MockBookmarkRepository{
+		getBookmarkFunc: func(id string) (model.Bookmark, error) {
+			if id != bookmark.ID {
+				return model.Bookmark{}, model.ErrNotFound
+			}
+			return bookmark, nil
+		},
+		updateBookmarkFunc: func(b model.Bookmark) (model.Bookmark, error) {
+			return b, nil
+		},
+	}
+	userRepo := &MockUserRepository{
+		getUserByIDFunc: func(id string) (model.User, error) {
+			return model.User{ID: "user-1", Tier: model.TierPaid}, nil
+		},
+	}
+	service := NewBookmarkService(bookmarkRepo, userRepo, webRepo, NewEntitlementService(userRepo, bookmarkRepo, &MockUsageRepository{}), NewPolicy(&MockCollectionRepository{}), nil, nil, nil, nil)
+
+	updated, err := service.RefreshBookmark("user-1", "b1")
+	if err != nil {
+		t.Fatalf("RefreshBookmark() unexpected error = %v", err)
+	}
+
+	if updated.Title != "Saved Title" || updated.ContentSummary != "Saved summary" {
+		t.Errorf("RefreshBookmark() = %+v, want the saved title and summary kept", updated)
+	}
+	e := updated.Enrichments[len(updated.Enrichments)-1]
+	if e.Summary != model.EnrichmentSummaryKept || fmt.Sprint(e.Failed) != "[title]" || len(e.Updated) != 0 {
+		t.Errorf("RefreshBookmark() enrichment = %+v, want a failed title lookup and a kept summary", e)
+	}
+}
+
+func TestBookmarkService_RefreshBookmark_FreeTier(t *testing.T) {
+	t.Setenv("LLM_SUMMARY_CONTENT", "true")
+	webRepo := &MockWebRepository{
+		getContentSummaryFunc: func(ctx context.Context, url string) (string, error) {
+			t.Error("RefreshBookmark() should not summarize for the free tier")
+			return "", nil
+		},
+	}
+	bookmark := model.Bookmark{ID: "b1", UserID: "user-1", URL: "https://example.com"}
+	bookmarkRepo := &MockBookmarkRepository{
+		getBookmarkFunc: func(id string) (model.Bookmark, error) {
+			if id != bookmark.ID {
+				return model.Bookmark{}, model.ErrNotFound
+			}
+			return bookmark, nil
+		},
+		updateBookmarkFunc: func(b model.Bookmark) (model.Bookmark, error) {
+			return b, nil
+		},
+	}
+	userRepo := &MockUserRepository{
+		getUserByIDFunc: func(id string) (model.User, error) {
+			return model.User{ID: "user-1", Tier: model.TierFree}, nil
+		},
+	}
+	service := NewBookmarkService(bookmarkRepo, userRepo, webRepo, NewEntitlementService(userRepo, bookmarkRepo, &MockUsageRepository{}), NewPolicy(&MockCollectionRepository{}), nil, nil, nil, nil)
+
+	updated, err := service.RefreshBookmark("user-1", "b1")
+	if err != nil {
+		t.Fatalf("RefreshBookmark() unexpected error = %v", err)
+	}
+	if e := updated.Enrichments[0]; e.Summary != model.EnrichmentSummaryNotAvailable || updated.ContentSummary != "" {
+		t.Errorf("RefreshBookmark() enrichment = %+v, want the summary not available", e)
+	}
+}
+
+func TestBookmarkService_RefreshBookmark_Errors(t *testing.T) {
+	bookmark := model.Bookmark{ID: "b1", UserID: "user-1", URL: "https://example.com"}
+	bookmarkRepo := &MockBookmarkRepository{
+		getBookmarkFunc: func(id string) (model.Bookmark, error) {
+			if id != bookmark.ID {
+				return model.Bookmark{}, model.ErrNotFound
+			}
+			return bookmark, nil
+		},
+		updateBookmarkFunc: func(b model.Bookmark) (model.Bookmark, error) {
+			return b, nil
+		},
+	}
+	userRepo := &MockUserRepository{
+		getUserByIDFunc: func(id string) (model.User, error) {
+			return model.User{ID: "user-1"}, nil
+		},
+	}
+	service := NewBookmarkService(bookmarkRepo, userRepo, &MockWebRepository{}, NewEntitlementService(userRepo, bookmarkRepo, &MockUsageRepository{}), NewPolicy(&MockCollectionRepository{}), nil, nil, nil, nil)
+
+	if _, err := service.RefreshBookmark("user-2", "b1"); !errors.Is(err, model.ErrForbidden) {
+		t.Errorf("RefreshBookmark() by another user error = %v, want ErrForbidden", err)
+	}
+	if _, err := service.RefreshBookmark("user-1", "missing"); !errors.Is(err, model.ErrNotFound) {
+		t.Errorf("RefreshBookmark() of a missing bookmark error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestBookmarkService_RefreshBookmark_HistoryLimit(t *testing.T) {
+	bookmark := model.Bookmark{ID: "b1", UserID: "user-1", URL: "https://example.com"}
+	for range model.MaxEnrichmentHistory {
+		bookmark.Enrichments = append(bookmark.Enrichments, model.Enrichment{Trigger: model.EnrichmentTriggerCreate})
+	}
+	bookmarkRepo := &MockBookmarkRepository{
+		getBookmarkFunc: func(id string) (model.Bookmark, error) {
+			if id != bookmark.ID {
+				return model.Bookmark{}, model.ErrNotFound
+			}
+			return bookmark, nil
+		},
+		updateBookmarkFunc: func(b model.Bookmark) (model.Bookmark, error) {
+			return b, nil
+		},
+	}
+	userRepo := &MockUserRepository{
+		getUserByIDFunc: func(id string) (model.User, error) {
+			return model.User{ID: "user-1"}, nil
+		},
+	}
+	service := NewBookmarkService(bookmarkRepo, userRepo, &MockWebRepository{}, NewEntitlementService(userRepo, bookmarkRepo, &MockUsageRepository{}), NewPolicy(&MockCollectionRepository{}), nil, nil, nil, nil)
+
+	updated, err := service.RefreshBookmark("user-1", "b1")
+	if err != nil {
+		t.Fatalf("RefreshBookmark() unexpected error = %v", err)
+	}
+	if len(updated.Enrichments) != model.MaxEnrichmentHistory {
+		t.Errorf("RefreshBookmark() history has %d runs, want %d", len(updated.Enrichments), model.MaxEnrichmentHistory)
+	}
+	if last := updated.Enrichments[len(updated.Enrichments)-1]; last.Trigger != model.EnrichmentTriggerRefresh {
+		t.Errorf("RefreshBookmark() last run = %+v, want the refresh", last)
+	}
+}
+
+func TestBookmarkService_RefreshBookmarks(t *testing.T) {
+	bookmarks := map[string]*model.Bookmark{
+		"b1": {ID: "b1", UserID: "user-1", URL: "https://example.com/1"},
+		"b2": {ID: "b2", UserID: "user-2", URL: "https://example.com/2"},
+	}
+	bookmarkRepo := &MockBookmarkRepository{
+		getBookmarkFunc: func(id string) (model.Bookmark, error) {
+			b, ok := bookmarks[id]
+			if !ok {
+				return model.Bookmark{}, model.ErrNotFound
+			}
+			return *b, nil
+		},
+		updateBookmarkFunc: func(b model.Bookmark) (model.Bookmark, error) {
+			*bookmarks[b.ID] = b
+			return b, nil
+		},
+	}
+	userRepo := &MockUserRepository{
+		getUserByIDFunc: func(id string) (model.User, error) {
+			return model.User{ID: id}, nil
+		},
+	}
+	service := NewBookmarkService(bookmarkRepo, userRepo, &MockWebRepository{}, NewEntitlementService(userRepo, bookmarkRepo, &MockUsageRepository{}), NewPolicy(&MockCollectionRepository{}), nil, nil, nil, nil)
+
+	result, err := service.RefreshBookmarks("user-1", []string{"b1", "b2", "missing", "b1"})
+	if err != nil {
+		t.Fatalf("RefreshBookmarks() unexpected error = %v", err)
+	}
+	if fmt.Sprint(result.Queued) != "[b1]" || fmt.Sprint(result.NotFound) != "[b2 missing]" {
+		t.Errorf("RefreshBookmarks() = %+v, want b1 queued and b2, missing not found", result)
+	}
+
+	service.background.Wait()
+	if bookmarks["b1"].Title != "Default Title" || len(bookmarks["b1"].Enrichments) != 1 {
+		t.Errorf("queued bookmark after refresh = %+v, want it enriched", bookmarks["b1"])
+	}
+	if len(bookmarks["b2"].Enrichments) != 0 {
+		t.Errorf("another user's bookmark was refreshed: %+v", bookmarks["b2"])
+	}
+
+	tooMany := make([]string, maxBulkRefresh+1)
+	for _, ids := range [][]string{nil, tooMany} {
+		if _, err := service.RefreshBookmarks("user-1", ids); !errors.Is(err, model.ErrInvalidInput) {
+			t.Errorf("RefreshBookmarks() with %d IDs error = %v, want ErrInvalidInput", len(ids), err)
+		}
+	}
+}
+
+func TestBookmarkService_Update_RetriesConcurrentWrites(t *testing.T) {
+	stored := model.Bookmark{ID: "b1", UserID: "user-1", Version: 3}
+	interrupted := 0
+	mockRepo := &MockBookmarkRepository{
+		// Another write lands between each read and write of the update, until the last attempt
+		getBookmarkFunc: func(id string) (model.Bookmark, error) {
+			b := stored
+			if interrupted < maxUpdateAttempts-1 {
+				interrupted++
+				stored.Title, stored.Version = "Enriched", stored.Version+1
+			}
+			return b, nil
+		},
+		// Like the repositories, updates fail unless they were read at the stored version
+		updateBookmarkFunc: func(bookmark model.Bookmark) (model.Bookmark, error) {
+			if bookmark.Version != stored.Version {
+				return model.Bookmark{}, model.ErrConflict
+			}
+			bookmark.Version++
+			stored = bookmark
+			return bookmark, nil
+		},
+	}
+	service := NewBookmarkService(mockRepo, &MockUserRepository{}, &MockWebRepository{}, nil, NewPolicy(&MockCollectionRepository{}), nil, nil, nil, nil)
+
+	updated, err := service.ArchiveBookmark("user-1", "b1", 0)
+	if err != nil {
+		t.Fatalf("ArchiveBookmark() unexpected error = %v", err)
+	}
+	if !updated.IsArchived || updated.Title != "Enriched" || updated.Version != 6 {
+		t.Errorf("ArchiveBookmark() = %+v, want archived on top of the concurrent writes at version 6", updated)
+	}
+
+	// A bookmark that keeps changing is given up on
+	mockRepo.getBookmarkFunc = func(id string) (model.Bookmark, error) {
+		b := stored
+		stored.Version++
+		return b, nil
+	}
+	if _, err := service.OpenBookmark("user-1", "b1"); !errors.Is(err, model.ErrConflict) {
+		t.Errorf("OpenBookmark() of a bookmark that keeps changing error = %v, want ErrConflict", err)
+	}
+}
+
+func TestBookmarkService_Update_Version(t *testing.T) {
+	stored := model.Bookmark{ID: "b1", UserID: "user-1", Version: 3}
+	mockRepo := &MockBookmarkRepository{
+		getBookmarkFunc: func(id string) (model.Bookmark, error) {
+			return stored, nil
+		},
+		// Like the repositories, updates fail unless they were read at the stored version
+		updateBookmarkFunc: func(bookmark model.Bookmark) (model.Bookmark, error) {
+			if bookmark.Version != stored.Version {
+				return model.Bookmark{}, model.ErrConflict
+			}
+			bookmark.Version++
+			stored = bookmark
+			return bookmark, nil
+		},
+	}
+	service := NewBookmarkService(mockRepo, &MockUserRepository{}, &MockWebRepository{}, nil, NewPolicy(&MockCollectionRepository{}), nil, nil, nil, nil)
+	read := true
+
+	if _, err := service.ArchiveBookmark("user-1", "b1", 2); !errors.Is(err, model.ErrPreconditionFailed) {
+		t.Errorf("ArchiveBookmark() at a stale version error = %v, want ErrPreconditionFailed", err)
+	}
+	if _, err := service.UpdateReadingState("user-1", "b1", model.ReadingStateUpdate{IsRead: &read}, 4); !errors.Is(err, model.ErrPreconditionFailed) {
+		t.Errorf("UpdateReadingState() at a stale version error = %v, want ErrPreconditionFailed", err)
+	}
+	if _, err := service.UpdateNotes("user-2", "b1", "", 2); !errors.Is(err, model.ErrForbidden) {
+		t.Errorf("UpdateNotes() of another user's bookmark error = %v, want ErrForbidden before the version check", err)
+	}
+	if stored.Version != 3 || stored.IsArchived || stored.IsRead {
+		t.Errorf("failed preconditions changed the bookmark to %+v", stored)
+	}
+
+	updated, err := service.UpdateNotes("user-1", "b1", "Worth a reread", 3)
+	if err != nil {
+		t.Fatalf("UpdateNotes() at the current version unexpected error = %v", err)
+	}
+	if updated.Notes != "Worth a reread" || updated.Version != 4 {
+		t.Errorf("UpdateNotes() = %+v, want the notes at version 4", updated)
+	}
+}
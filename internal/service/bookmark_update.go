@@ -0,0 +1,49 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/tsongpon/athena/internal/logger"
+	"github.com/tsongpon/athena/internal/model"
+	"go.uber.org/zap"
+)
+
+// maxUpdateAttempts is how many times updateBookmark reads and changes a bookmark that other
+// writes keep changing before it gives up
+const maxUpdateAttempts = 3
+
+// updateBookmark reads a bookmark, changes it with change and stores it. Storing fails when
+// another write got in between; the bookmark is then read and changed again, so change must
+// only depend on the bookmark it is given. An error from change stops the update as is.
+func updateBookmark(repo BookmarkRepository, id string, change func(b *model.Bookmark) error) (model.Bookmark, error) {
+	for attempt := 1; ; attempt++ {
+		b, err := repo.GetBookmark(id)
+		if err != nil {
+			return model.Bookmark{}, fmt.Errorf("failed to get bookmark with ID %s: %w", id, err)
+		}
+		if err := change(&b); err != nil {
+			return model.Bookmark{}, err
+		}
+		updated, err := repo.UpdateBookmark(b)
+		if errors.Is(err, model.ErrConflict) && attempt < maxUpdateAttempts {
+			logger.Debug("Bookmark was changed concurrently, updating it again",
+				zap.String("id", id),
+				zap.Int("attempt", attempt))
+			continue
+		}
+		if err != nil {
+			return model.Bookmark{}, fmt.Errorf("failed to update bookmark with ID %s: %w", id, err)
+		}
+		return updated, nil
+	}
+}
+
+// checkVersion returns ErrPreconditionFailed when the caller expects b at a version it no
+// longer is at. A zero version skips the check.
+func checkVersion(b model.Bookmark, version int64) error {
+	if version != 0 && b.Version != version {
+		return fmt.Errorf("bookmark with ID %s is at version %d, not %d: %w", b.ID, b.Version, version, model.ErrPreconditionFailed)
+	}
+	return nil
+}
@@ -0,0 +1,452 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/tsongpon/athena/internal/logger"
+	"github.com/tsongpon/athena/internal/model"
+	"go.uber.org/zap"
+)
+
+// CollectionService implements collection management. Bookmark membership is stored
+// on both sides: Bookmark.CollectionIDs drives filtering and Collection.BookmarkIDs
+// holds the manual order. Every method takes the acting user and checks it against Policy.
+type CollectionService struct {
+	collectionRepository CollectionRepository
+	bookmarkRepository   BookmarkRepository
+	activityRepository   ActivityRepository
+	policy               *Policy
+	events               *EventHub
+}
+
+// NewCollectionService creates a new instance of CollectionService
+func NewCollectionService(collectionRepo CollectionRepository, bookmarkRepo BookmarkRepository, activityRepo ActivityRepository, policy *Policy, events *EventHub) *CollectionService {
+	return &CollectionService{
+		collectionRepository: collectionRepo,
+		bookmarkRepository:   bookmarkRepo,
+		activityRepository:   activityRepo,
+		policy:               policy,
+		events:               events,
+	}
+}
+
+// CreateCollection creates a new collection, optionally nested under ParentID
+func (s *CollectionService) CreateCollection(c model.Collection) (model.Collection, error) {
+	if c.ID != "" {
+		return model.Collection{}, fmt.Errorf("collection ID must be empty: %w", model.ErrInvalidInput)
+	}
+	c.Name = strings.TrimSpace(c.Name)
+	if c.Name == "" {
+		return model.Collection{}, fmt.Errorf("name is required: %w", model.ErrInvalidInput)
+	}
+	if c.ParentID != "" {
+		if _, err := s.getManagedCollection(c.UserID, c.ParentID); err != nil {
+			return model.Collection{}, fmt.Errorf("invalid parent collection: %w", err)
+		}
+	}
+	c.BookmarkIDs = nil
+	c.AddedBy = nil
+	c.Collaborators = nil
+
+	created, err := s.collectionRepository.CreateCollection(c)
+	if err != nil {
+		return model.Collection{}, fmt.Errorf("failed to create collection: %w", err)
+	}
+
+	logger.Info("Created collection",
+		zap.String("id", created.ID),
+		zap.String("user_id", created.UserID),
+		zap.String("parent_id", created.ParentID))
+
+	return created, nil
+}
+
+// GetCollection retrieves a collection userID owns or collaborates on
+func (s *CollectionService) GetCollection(userID, id string) (model.Collection, error) {
+	c, err := s.loadCollection(id)
+	if err != nil {
+		return model.Collection{}, err
+	}
+	if err := s.policy.CanViewCollection(userID, c); err != nil {
+		return model.Collection{}, err
+	}
+	return c, nil
+}
+
+// ListCollections retrieves every collection of a user. Clients build the tree from ParentID.
+func (s *CollectionService) ListCollections(userID string) ([]model.Collection, error) {
+	collections, err := s.collectionRepository.ListCollections(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list collections: %w", err)
+	}
+	return collections, nil
+}
+
+// ListSharedCollections retrieves collections other users have shared with userID
+func (s *CollectionService) ListSharedCollections(userID string) ([]model.Collection, error) {
+	collections, err := s.collectionRepository.ListSharedCollections(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list shared collections: %w", err)
+	}
+	return collections, nil
+}
+
+// ListActivity returns the most recent changes made to a collection and who made them
+func (s *CollectionService) ListActivity(userID, collectionID string, limit int) ([]model.Activity, error) {
+	if _, err := s.GetCollection(userID, collectionID); err != nil {
+		return nil, err
+	}
+	if limit < 1 || limit > 100 {
+		limit = 50
+	}
+
+	activities, err := s.activityRepository.ListActivities(collectionID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list activity of collection %s: %w", collectionID, err)
+	}
+	return activities, nil
+}
+
+// UpdateCollection replaces name, description, parent and position of a collection
+func (s *CollectionService) UpdateCollection(userID string, update model.Collection) (model.Collection, error) {
+	update.Name = strings.TrimSpace(update.Name)
+	return updateCollection(s.collectionRepository, update.ID, func(c *model.Collection) error {
+		if err := s.policy.CanManageCollection(userID, *c); err != nil {
+			return err
+		}
+		if update.Name == "" {
+			return fmt.Errorf("name is required: %w", model.ErrInvalidInput)
+		}
+		if update.ParentID != c.ParentID && update.ParentID != "" {
+			if err := s.checkParent(userID, c.ID, update.ParentID); err != nil {
+				return err
+			}
+		}
+
+		c.Name = update.Name
+		c.Description = update.Description
+		c.ParentID = update.ParentID
+		c.Position = update.Position
+		return nil
+	})
+}
+
+// DeleteCollection deletes a collection and its nested collections. Bookmarks are kept
+// and only lose their membership.
+func (s *CollectionService) DeleteCollection(userID, id string) error {
+	c, err := s.getManagedCollection(userID, id)
+	if err != nil {
+		return err
+	}
+
+	all, err := s.collectionRepository.ListCollections(userID)
+	if err != nil {
+		return fmt.Errorf("failed to list collections: %w", err)
+	}
+
+	// Delete children before parents so a partial failure never leaves orphans
+	toDelete := descendants(all, c.ID)
+	slices.Reverse(toDelete)
+	toDelete = append(toDelete, c)
+
+	for _, collection := range toDelete {
+		for _, bookmarkID := range collection.BookmarkIDs {
+			if err := s.removeMembership(bookmarkID, collection.ID); err != nil {
+				return err
+			}
+		}
+		if err := s.collectionRepository.DeleteCollection(collection.ID); err != nil {
+			return fmt.Errorf("failed to delete collection with ID %s: %w", collection.ID, err)
+		}
+	}
+
+	logger.Info("Deleted collection",
+		zap.String("id", id),
+		zap.String("user_id", userID),
+		zap.Int("nested_deleted", len(toDelete)-1))
+
+	return nil
+}
+
+// AddBookmark adds a bookmark to a collection at position (0-based). A negative or
+// out of range position appends the bookmark. Adding a bookmark that is already in
+// the collection moves it to position. Editors of a shared collection can add their
+// own bookmarks to it.
+func (s *CollectionService) AddBookmark(userID, collectionID, bookmarkID string, position int) (model.Collection, error) {
+	c, err := s.getEditableCollection(userID, collectionID)
+	if err != nil {
+		return model.Collection{}, err
+	}
+
+	// Moving a bookmark that is already in the collection only needs edit rights on the
+	// collection; adding one also needs ownership of the bookmark
+	alreadyAdded := slices.Contains(c.BookmarkIDs, bookmarkID)
+	if !alreadyAdded {
+		updated, err := updateBookmark(s.bookmarkRepository, bookmarkID, func(b *model.Bookmark) error {
+			if err := s.policy.CanModifyBookmark(userID, *b); err != nil {
+				return err
+			}
+			if slices.Contains(b.CollectionIDs, c.ID) {
+				return errMembershipUnchanged
+			}
+			b.CollectionIDs = append(slices.Clone(b.CollectionIDs), c.ID)
+			return nil
+		})
+		switch {
+		case errors.Is(err, errMembershipUnchanged):
+		case err != nil:
+			return model.Collection{}, err
+		default:
+			s.events.Publish(model.EventBookmarkUpdated, updated)
+		}
+	}
+
+	updated, err := updateCollection(s.collectionRepository, c.ID, func(c *model.Collection) error {
+		if err := s.policy.CanEditCollectionBookmarks(userID, *c); err != nil {
+			return err
+		}
+		inCollection := slices.Contains(c.BookmarkIDs, bookmarkID)
+		if alreadyAdded && !inCollection {
+			// Only moving was allowed, and the bookmark was removed in the meantime
+			return fmt.Errorf("bookmark %s was removed from collection %s: %w", bookmarkID, c.ID, model.ErrConflict)
+		}
+
+		ids := slices.DeleteFunc(slices.Clone(c.BookmarkIDs), func(id string) bool { return id == bookmarkID })
+		at := position
+		if at < 0 || at > len(ids) {
+			at = len(ids)
+		}
+		c.BookmarkIDs = slices.Insert(ids, at, bookmarkID)
+		if !inCollection {
+			c.AddedBy = maps.Clone(c.AddedBy)
+			if c.AddedBy == nil {
+				c.AddedBy = make(map[string]string)
+			}
+			c.AddedBy[bookmarkID] = userID
+		}
+		return nil
+	})
+	if err != nil {
+		return model.Collection{}, err
+	}
+
+	action := model.ActivityBookmarkAdded
+	if alreadyAdded {
+		action = model.ActivityBookmarksReordered
+	}
+	recordActivity(s.activityRepository, model.Activity{CollectionID: c.ID, ActorID: userID, Action: action, BookmarkID: bookmarkID})
+
+	return updated, nil
+}
+
+// RemoveBookmark removes a bookmark from a collection
+func (s *CollectionService) RemoveBookmark(userID, collectionID, bookmarkID string) (model.Collection, error) {
+	c, err := s.getEditableCollection(userID, collectionID)
+	if err != nil {
+		return model.Collection{}, err
+	}
+	if !slices.Contains(c.BookmarkIDs, bookmarkID) {
+		return model.Collection{}, fmt.Errorf("bookmark %s is not in collection %s: %w", bookmarkID, c.ID, model.ErrNotFound)
+	}
+
+	if err := s.removeMembership(bookmarkID, c.ID); err != nil {
+		return model.Collection{}, err
+	}
+
+	updated, err := updateCollection(s.collectionRepository, c.ID, func(c *model.Collection) error {
+		if err := s.policy.CanEditCollectionBookmarks(userID, *c); err != nil {
+			return err
+		}
+		c.BookmarkIDs = slices.DeleteFunc(slices.Clone(c.BookmarkIDs), func(id string) bool { return id == bookmarkID })
+		c.AddedBy = maps.Clone(c.AddedBy)
+		delete(c.AddedBy, bookmarkID)
+		return nil
+	})
+	if err != nil {
+		return model.Collection{}, err
+	}
+
+	recordActivity(s.activityRepository, model.Activity{CollectionID: c.ID, ActorID: userID, Action: model.ActivityBookmarkRemoved, BookmarkID: bookmarkID})
+
+	return updated, nil
+}
+
+// ReorderBookmarks sets the manual order of a collection. bookmarkIDs must contain
+// exactly the bookmarks currently in the collection.
+func (s *CollectionService) ReorderBookmarks(userID, collectionID string, bookmarkIDs []string) (model.Collection, error) {
+	updated, err := updateCollection(s.collectionRepository, collectionID, func(c *model.Collection) error {
+		if err := s.policy.CanEditCollectionBookmarks(userID, *c); err != nil {
+			return err
+		}
+		current := slices.Sorted(slices.Values(c.BookmarkIDs))
+		requested := slices.Sorted(slices.Values(bookmarkIDs))
+		if !slices.Equal(current, requested) {
+			return fmt.Errorf("order must list every bookmark of the collection exactly once: %w", model.ErrInvalidInput)
+		}
+		c.BookmarkIDs = slices.Clone(bookmarkIDs)
+		return nil
+	})
+	if err != nil {
+		return model.Collection{}, err
+	}
+
+	recordActivity(s.activityRepository, model.Activity{CollectionID: updated.ID, ActorID: userID, Action: model.ActivityBookmarksReordered})
+
+	return updated, nil
+}
+
+// ListCollectionBookmarks returns the bookmarks of a collection in their manual order
+// together with who added them. IDs of bookmarks that no longer exist are pruned from
+// the collection; bookmarks in the trash are left out but keep their place.
+func (s *CollectionService) ListCollectionBookmarks(userID, collectionID string) ([]model.CollectionBookmark, error) {
+	c, err := s.GetCollection(userID, collectionID)
+	if err != nil {
+		return nil, err
+	}
+
+	bookmarks := make([]model.CollectionBookmark, 0, len(c.BookmarkIDs))
+	var stale []string
+	for _, id := range c.BookmarkIDs {
+		b, err := s.bookmarkRepository.GetBookmark(id)
+		if errors.Is(err, model.ErrNotFound) {
+			if _, err := s.bookmarkRepository.GetTrashedBookmark(id); err != nil {
+				stale = append(stale, id)
+			}
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get bookmark with ID %s: %w", id, err)
+		}
+		addedBy := c.AddedBy[id]
+		if addedBy == "" {
+			addedBy = c.UserID
+		}
+		bookmarks = append(bookmarks, model.CollectionBookmark{Bookmark: b.VisibleTo(userID), AddedBy: addedBy})
+	}
+
+	if len(stale) > 0 {
+		_, err := updateCollection(s.collectionRepository, c.ID, func(c *model.Collection) error {
+			c.BookmarkIDs = slices.DeleteFunc(slices.Clone(c.BookmarkIDs), func(id string) bool { return slices.Contains(stale, id) })
+			return nil
+		})
+		if err != nil {
+			logger.Warn("Failed to prune deleted bookmarks from collection",
+				zap.String("collection_id", c.ID),
+				zap.Strings("bookmark_ids", stale),
+				zap.Error(err))
+		}
+	}
+
+	return bookmarks, nil
+}
+
+func (s *CollectionService) loadCollection(id string) (model.Collection, error) {
+	if id == "" {
+		return model.Collection{}, fmt.Errorf("id is required: %w", model.ErrInvalidInput)
+	}
+	c, err := s.collectionRepository.GetCollection(id)
+	if err != nil {
+		return model.Collection{}, fmt.Errorf("failed to get collection with ID %s: %w", id, err)
+	}
+	return c, nil
+}
+
+// getEditableCollection loads a collection whose bookmarks userID may change
+func (s *CollectionService) getEditableCollection(userID, id string) (model.Collection, error) {
+	c, err := s.loadCollection(id)
+	if err != nil {
+		return model.Collection{}, err
+	}
+	if err := s.policy.CanEditCollectionBookmarks(userID, c); err != nil {
+		return model.Collection{}, err
+	}
+	return c, nil
+}
+
+// getManagedCollection loads a collection that userID owns
+func (s *CollectionService) getManagedCollection(userID, id string) (model.Collection, error) {
+	c, err := s.loadCollection(id)
+	if err != nil {
+		return model.Collection{}, err
+	}
+	if err := s.policy.CanManageCollection(userID, c); err != nil {
+		return model.Collection{}, err
+	}
+	return c, nil
+}
+
+// recordActivity appends to a collection's activity log. The log is informational,
+// so a failure is logged instead of failing the change it describes.
+func recordActivity(activityRepo ActivityRepository, activity model.Activity) {
+	if _, err := activityRepo.CreateActivity(activity); err != nil {
+		logger.Warn("Failed to record collection activity",
+			zap.String("collection_id", activity.CollectionID),
+			zap.String("action", activity.Action),
+			zap.Error(err))
+	}
+}
+
+// checkParent validates that parentID can become the parent of collection id
+func (s *CollectionService) checkParent(userID, id, parentID string) error {
+	if parentID == id {
+		return fmt.Errorf("a collection cannot be its own parent: %w", model.ErrInvalidInput)
+	}
+	if _, err := s.getManagedCollection(userID, parentID); err != nil {
+		return fmt.Errorf("invalid parent collection: %w", err)
+	}
+
+	all, err := s.collectionRepository.ListCollections(userID)
+	if err != nil {
+		return fmt.Errorf("failed to list collections: %w", err)
+	}
+	for _, d := range descendants(all, id) {
+		if d.ID == parentID {
+			return fmt.Errorf("a collection cannot be moved into its own descendant: %w", model.ErrInvalidInput)
+		}
+	}
+	return nil
+}
+
+// errMembershipUnchanged stops the update of a bookmark whose collections are already as wanted
+var errMembershipUnchanged = errors.New("bookmark collections unchanged")
+
+// removeMembership removes collectionID from a bookmark's collections
+func (s *CollectionService) removeMembership(bookmarkID, collectionID string) error {
+	updated, err := updateBookmark(s.bookmarkRepository, bookmarkID, func(b *model.Bookmark) error {
+		if !slices.Contains(b.CollectionIDs, collectionID) {
+			return errMembershipUnchanged
+		}
+		b.CollectionIDs = slices.DeleteFunc(slices.Clone(b.CollectionIDs), func(id string) bool { return id == collectionID })
+		return nil
+	})
+	// Nothing to clean up on a bookmark that no longer exists
+	if errors.Is(err, errMembershipUnchanged) || errors.Is(err, model.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	s.events.Publish(model.EventBookmarkUpdated, updated)
+	return nil
+}
+
+// descendants returns every collection nested below id in breadth-first order
+func descendants(all []model.Collection, id string) []model.Collection {
+	var result []model.Collection
+	queue := []string{id}
+	for len(queue) > 0 {
+		parent := queue[0]
+		queue = queue[1:]
+		for _, c := range all {
+			if c.ParentID == parent {
+				result = append(result, c)
+				queue = append(queue, c.ID)
+			}
+		}
+	}
+	return result
+}
@@ -0,0 +1,484 @@
+package service
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/tsongpon/athena/internal/model"
+)
+
+// MockCollectionRepository is a mock implementation of CollectionRepository for testing
+type MockCollectionRepository struct {
+	createCollectionFunc func(collection model.Collection) (model.Collection, error)
+	getCollectionFunc    func(id string) (model.Collection, error)
+	listCollectionsFunc  func(userID string) ([]model.Collection, error)
+	listSharedFunc       func(userID string) ([]model.Collection, error)
+	updateCollectionFunc func(collection model.Collection) (model.Collection, error)
+	deleteCollectionFunc func(id string) error
+}
+
+func (m *MockCollectionRepository) CreateCollection(collection model.Collection) (model.Collection, error) {
+	if m.createCollectionFunc != nil {
+		return m.createCollectionFunc(collection)
+	}
+	collection.ID = "collection-new"
+	return collection, nil
+}
+
+func (m *MockCollectionRepository) GetCollection(id string) (model.Collection, error) {
+	if m.getCollectionFunc != nil {
+		return m.getCollectionFunc(id)
+	}
+	return model.Collection{}, model.ErrNotFound
+}
+
+func (m *MockCollectionRepository) ListCollections(userID string) ([]model.Collection, error) {
+	if m.listCollectionsFunc != nil {
+		return m.listCollectionsFunc(userID)
+	}
+	return []model.Collection{}, nil
+}
+
+func (m *MockCollectionRepository) ListSharedCollections(userID string) ([]model.Collection, error) {
+	if m.listSharedFunc != nil {
+		return m.listSharedFunc(userID)
+	}
+	return []model.Collection{}, nil
+}
+
+func (m *MockCollectionRepository) UpdateCollection(collection model.Collection) (model.Collection, error) {
+	if m.updateCollectionFunc != nil {
+		return m.updateCollectionFunc(collection)
+	}
+	return collection, nil
+}
+
+func (m *MockCollectionRepository) DeleteCollection(id string) error {
+	if m.deleteCollectionFunc != nil {
+		return m.deleteCollectionFunc(id)
+	}
+	return nil
+}
+
+// MockActivityRepository is a mock implementation of ActivityRepository that keeps what it records
+type MockActivityRepository struct {
+	activities []model.Activity
+}
+
+func (m *MockActivityRepository) CreateActivity(activity model.Activity) (model.Activity, error) {
+	m.activities = append(m.activities, activity)
+	return activity, nil
+}
+
+func (m *MockActivityRepository) ListActivities(collectionID string, limit int) ([]model.Activity, error) {
+	var activities []model.Activity
+	for i := len(m.activities) - 1; i >= 0 && len(activities) < limit; i-- {
+		if m.activities[i].CollectionID == collectionID {
+			activities = append(activities, m.activities[i])
+		}
+	}
+	return activities, nil
+}
+
+// collectionsByID returns a getCollectionFunc serving the given collections
+func collectionsByID(collections ...model.Collection) func(id string) (model.Collection, error) {
+	return func(id string) (model.Collection, error) {
+		for _, c := range collections {
+			if c.ID == id {
+				return c, nil
+			}
+		}
+		return model.Collection{}, model.ErrNotFound
+	}
+}
+
+func TestCollectionService_CreateCollection(t *testing.T) {
+	service := NewCollectionService(&MockCollectionRepository{}, &MockBookmarkRepository{}, &MockActivityRepository{}, NewPolicy(&MockCollectionRepository{}), nil)
+
+	created, err := service.CreateCollection(model.Collection{UserID: "user-1", Name: "  Reading  "})
+	if err != nil {
+		t.Fatalf("CreateCollection() unexpected error = %v", err)
+	}
+	if created.Name != "Reading" {
+		t.Errorf("CreateCollection() Name = %q, want Reading", created.Name)
+	}
+
+	_, err = service.CreateCollection(model.Collection{UserID: "user-1", Name: " "})
+	if !errors.Is(err, model.ErrInvalidInput) {
+		t.Errorf("CreateCollection() with blank name error = %v, want ErrInvalidInput", err)
+	}
+}
+
+func TestCollectionService_CreateCollection_ParentOwnedByAnotherUser(t *testing.T) {
+	mockRepo := &MockCollectionRepository{
+		getCollectionFunc: collectionsByID(model.Collection{ID: "parent", UserID: "user-2"}),
+	}
+	service := NewCollectionService(mockRepo, &MockBookmarkRepository{}, &MockActivityRepository{}, NewPolicy(mockRepo), nil)
+
+	_, err := service.CreateCollection(model.Collection{UserID: "user-1", Name: "Child", ParentID: "parent"})
+	if !errors.Is(err, model.ErrForbidden) {
+		t.Errorf("CreateCollection() error = %v, want ErrForbidden", err)
+	}
+}
+
+func TestCollectionService_GetCollection_Forbidden(t *testing.T) {
+	mockRepo := &MockCollectionRepository{
+		getCollectionFunc: collectionsByID(model.Collection{ID: "c1", UserID: "user-2"}),
+	}
+	service := NewCollectionService(mockRepo, &MockBookmarkRepository{}, &MockActivityRepository{}, NewPolicy(mockRepo), nil)
+
+	if _, err := service.GetCollection("user-1", "c1"); !errors.Is(err, model.ErrForbidden) {
+		t.Errorf("GetCollection() error = %v, want ErrForbidden", err)
+	}
+	if _, err := service.GetCollection("user-1", "missing"); !errors.Is(err, model.ErrNotFound) {
+		t.Errorf("GetCollection() error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestCollectionService_UpdateCollection_RejectsCycle(t *testing.T) {
+	collections := []model.Collection{
+		{ID: "root", UserID: "user-1", Name: "Root"},
+		{ID: "child", UserID: "user-1", Name: "Child", ParentID: "root"},
+		{ID: "grandchild", UserID: "user-1", Name: "Grandchild", ParentID: "child"},
+	}
+	mockRepo := &MockCollectionRepository{
+		getCollectionFunc: collectionsByID(collections...),
+		listCollectionsFunc: func(userID string) ([]model.Collection, error) {
+			return collections, nil
+		},
+	}
+	service := NewCollectionService(mockRepo, &MockBookmarkRepository{}, &MockActivityRepository{}, NewPolicy(mockRepo), nil)
+
+	_, err := service.UpdateCollection("user-1", model.Collection{ID: "root", Name: "Root", ParentID: "grandchild"})
+	if !errors.Is(err, model.ErrInvalidInput) {
+		t.Errorf("UpdateCollection() moving into descendant error = %v, want ErrInvalidInput", err)
+	}
+
+	_, err = service.UpdateCollection("user-1", model.Collection{ID: "root", Name: "Root", ParentID: "root"})
+	if !errors.Is(err, model.ErrInvalidInput) {
+		t.Errorf("UpdateCollection() self parent error = %v, want ErrInvalidInput", err)
+	}
+
+	updated, err := service.UpdateCollection("user-1", model.Collection{ID: "grandchild", Name: "Moved", ParentID: "root"})
+	if err != nil {
+		t.Fatalf("UpdateCollection() unexpected error = %v", err)
+	}
+	if updated.ParentID != "root" || updated.Name != "Moved" {
+		t.Errorf("UpdateCollection() = %+v, want parent root and name Moved", updated)
+	}
+}
+
+func TestCollectionService_DeleteCollection_Cascades(t *testing.T) {
+	collections := []model.Collection{
+		{ID: "root", UserID: "user-1", BookmarkIDs: []string{"b1"}},
+		{ID: "child", UserID: "user-1", ParentID: "root", BookmarkIDs: []string{"b2"}},
+		{ID: "other", UserID: "user-1"},
+	}
+	var deleted []string
+	mockRepo := &MockCollectionRepository{
+		getCollectionFunc: collectionsByID(collections...),
+		listCollectionsFunc: func(userID string) ([]model.Collection, error) {
+			return collections, nil
+		},
+		deleteCollectionFunc: func(id string) error {
+			deleted = append(deleted, id)
+			return nil
+		},
+	}
+	updatedBookmarks := map[string][]string{}
+	mockBookmarkRepo := &MockBookmarkRepository{
+		getBookmarkFunc: func(id string) (model.Bookmark, error) {
+			return model.Bookmark{ID: id, UserID: "user-1", CollectionIDs: []string{"root", "child", "other"}}, nil
+		},
+		updateBookmarkFunc: func(bookmark model.Bookmark) (model.Bookmark, error) {
+			updatedBookmarks[bookmark.ID] = bookmark.CollectionIDs
+			return bookmark, nil
+		},
+	}
+	service := NewCollectionService(mockRepo, mockBookmarkRepo, &MockActivityRepository{}, NewPolicy(mockRepo), nil)
+
+	if err := service.DeleteCollection("user-1", "root"); err != nil {
+		t.Fatalf("DeleteCollection() unexpected error = %v", err)
+	}
+
+	if !slices.Equal(deleted, []string{"child", "root"}) {
+		t.Errorf("DeleteCollection() deleted %v, want [child root]", deleted)
+	}
+	if !slices.Equal(updatedBookmarks["b1"], []string{"child", "other"}) {
+		t.Errorf("bookmark b1 collections = %v, want [child other]", updatedBookmarks["b1"])
+	}
+	if !slices.Equal(updatedBookmarks["b2"], []string{"root", "other"}) {
+		t.Errorf("bookmark b2 collections = %v, want [root other]", updatedBookmarks["b2"])
+	}
+}
+
+func TestCollectionService_AddBookmark(t *testing.T) {
+	mockRepo := &MockCollectionRepository{
+		getCollectionFunc: collectionsByID(model.Collection{ID: "c1", UserID: "user-1", BookmarkIDs: []string{"b1", "b2"}}),
+	}
+	var updatedBookmark model.Bookmark
+	mockBookmarkRepo := &MockBookmarkRepository{
+		getBookmarkFunc: func(id string) (model.Bookmark, error) {
+			owner := "user-1"
+			if id == "foreign" {
+				owner = "user-2"
+			}
+			return model.Bookmark{ID: id, UserID: owner}, nil
+		},
+		updateBookmarkFunc: func(bookmark model.Bookmark) (model.Bookmark, error) {
+			updatedBookmark = bookmark
+			return bookmark, nil
+		},
+	}
+	service := NewCollectionService(mockRepo, mockBookmarkRepo, &MockActivityRepository{}, NewPolicy(mockRepo), nil)
+
+	tests := []struct {
+		name     string
+		position int
+		want     []string
+	}{
+		{name: "append", position: -1, want: []string{"b1", "b2", "b3"}},
+		{name: "front", position: 0, want: []string{"b3", "b1", "b2"}},
+		{name: "out of range appends", position: 10, want: []string{"b1", "b2", "b3"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			updated, err := service.AddBookmark("user-1", "c1", "b3", tt.position)
+			if err != nil {
+				t.Fatalf("AddBookmark() unexpected error = %v", err)
+			}
+			if !slices.Equal(updated.BookmarkIDs, tt.want) {
+				t.Errorf("AddBookmark() order = %v, want %v", updated.BookmarkIDs, tt.want)
+			}
+			if !slices.Equal(updatedBookmark.CollectionIDs, []string{"c1"}) {
+				t.Errorf("bookmark collections = %v, want [c1]", updatedBookmark.CollectionIDs)
+			}
+		})
+	}
+
+	// Re-adding an existing bookmark moves it
+	updated, err := service.AddBookmark("user-1", "c1", "b2", 0)
+	if err != nil {
+		t.Fatalf("AddBookmark() unexpected error = %v", err)
+	}
+	if !slices.Equal(updated.BookmarkIDs, []string{"b2", "b1"}) {
+		t.Errorf("AddBookmark() move order = %v, want [b2 b1]", updated.BookmarkIDs)
+	}
+
+	if _, err := service.AddBookmark("user-1", "c1", "foreign", -1); !errors.Is(err, model.ErrForbidden) {
+		t.Errorf("AddBookmark() foreign bookmark error = %v, want ErrForbidden", err)
+	}
+}
+
+func TestCollectionService_AddBookmark_ConcurrentWrite(t *testing.T) {
+	mockRepo := &MockCollectionRepository{
+		getCollectionFunc: collectionsByID(model.Collection{ID: "c1", UserID: "user-1"}),
+	}
+	stored := model.Bookmark{ID: "b1", UserID: "user-1", Version: 1}
+	interrupted := false
+	mockBookmarkRepo := &MockBookmarkRepository{
+		getBookmarkFunc: func(id string) (model.Bookmark, error) {
+			b := stored
+			// Another write lands between the first read and write
+			if !interrupted {
+				interrupted = true
+				stored.IsRead, stored.Version = true, stored.Version+1
+			}
+			return b, nil
+		},
+		updateBookmarkFunc: func(bookmark model.Bookmark) (model.Bookmark, error) {
+			if bookmark.Version != stored.Version {
+				return model.Bookmark{}, model.ErrConflict
+			}
+			bookmark.Version++
+			stored = bookmark
+			return bookmark, nil
+		},
+	}
+	service := NewCollectionService(mockRepo, mockBookmarkRepo, &MockActivityRepository{}, NewPolicy(mockRepo), nil)
+
+	if _, err := service.AddBookmark("user-1", "c1", "b1", -1); err != nil {
+		t.Fatalf("AddBookmark() unexpected error = %v", err)
+	}
+	if !stored.IsRead || !slices.Equal(stored.CollectionIDs, []string{"c1"}) {
+		t.Errorf("AddBookmark() stored %+v, want the collection added on top of the concurrent write", stored)
+	}
+}
+
+func TestCollectionService_AddBookmark_ConcurrentAdd(t *testing.T) {
+	stored := model.Collection{ID: "c1", UserID: "user-1", BookmarkIDs: []string{"b1"}, Version: 1}
+	interrupted := false
+	mockRepo := &MockCollectionRepository{
+		getCollectionFunc: func(id string) (model.Collection, error) {
+			return stored, nil
+		},
+		updateCollectionFunc: func(collection model.Collection) (model.Collection, error) {
+			// Another editor adds a bookmark between the first read and write
+			if !interrupted {
+				interrupted = true
+				stored.BookmarkIDs = append(slices.Clone(stored.BookmarkIDs), "b2")
+				stored.Version++
+			}
+			if collection.Version != stored.Version {
+				return model.Collection{}, model.ErrConflict
+			}
+			collection.Version++
+			stored = collection
+			return collection, nil
+		},
+	}
+	bookmarkRepo := &MockBookmarkRepository{
+		getBookmarkFunc: func(id string) (model.Bookmark, error) {
+			return model.Bookmark{ID: id, UserID: "user-1"}, nil
+		},
+	}
+	service := NewCollectionService(mockRepo, bookmarkRepo, &MockActivityRepository{}, NewPolicy(mockRepo), nil)
+
+	if _, err := service.AddBookmark("user-1", "c1", "b3", -1); err != nil {
+		t.Fatalf("AddBookmark() unexpected error = %v", err)
+	}
+	if !slices.Equal(stored.BookmarkIDs, []string{"b1", "b2", "b3"}) || stored.AddedBy["b3"] != "user-1" {
+		t.Errorf("AddBookmark() stored %v, want the bookmark added on top of the concurrent add", stored.BookmarkIDs)
+	}
+}
+
+func TestCollectionService_ReorderBookmarks(t *testing.T) {
+	mockRepo := &MockCollectionRepository{
+		getCollectionFunc: collectionsByID(model.Collection{ID: "c1", UserID: "user-1", BookmarkIDs: []string{"b1", "b2", "b3"}}),
+	}
+	service := NewCollectionService(mockRepo, &MockBookmarkRepository{}, &MockActivityRepository{}, NewPolicy(mockRepo), nil)
+
+	updated, err := service.ReorderBookmarks("user-1", "c1", []string{"b3", "b1", "b2"})
+	if err != nil {
+		t.Fatalf("ReorderBookmarks() unexpected error = %v", err)
+	}
+	if !slices.Equal(updated.BookmarkIDs, []string{"b3", "b1", "b2"}) {
+		t.Errorf("ReorderBookmarks() = %v, want [b3 b1 b2]", updated.BookmarkIDs)
+	}
+
+	for _, ids := range [][]string{
+		{"b1", "b2"},
+		{"b1", "b2", "b4"},
+		{"b1", "b1", "b2", "b3"},
+	} {
+		if _, err := service.ReorderBookmarks("user-1", "c1", ids); !errors.Is(err, model.ErrInvalidInput) {
+			t.Errorf("ReorderBookmarks(%v) error = %v, want ErrInvalidInput", ids, err)
+		}
+	}
+}
+
+func TestCollectionService_ListCollectionBookmarks_PrunesDeleted(t *testing.T) {
+	var updated model.Collection
+	mockRepo := &MockCollectionRepository{
+		getCollectionFunc: collectionsByID(model.Collection{ID: "c1", UserID: "user-1", BookmarkIDs: []string{"b2", "gone", "trashed", "b1"}}),
+		updateCollectionFunc: func(collection model.Collection) (model.Collection, error) {
+			updated = collection
+			return collection, nil
+		},
+	}
+	mockBookmarkRepo := &MockBookmarkRepository{
+		getBookmarkFunc: func(id string) (model.Bookmark, error) {
+			if id == "gone" || id == "trashed" {
+				return model.Bookmark{}, model.ErrNotFound
+			}
+			return model.Bookmark{ID: id, UserID: "user-1"}, nil
+		},
+		trash: map[string]model.Bookmark{"trashed": {ID: "trashed", UserID: "user-1"}},
+	}
+	service := NewCollectionService(mockRepo, mockBookmarkRepo, &MockActivityRepository{}, NewPolicy(mockRepo), nil)
+
+	bookmarks, err := service.ListCollectionBookmarks("user-1", "c1")
+	if err != nil {
+		t.Fatalf("ListCollectionBookmarks() unexpected error = %v", err)
+	}
+	if len(bookmarks) != 2 || bookmarks[0].Bookmark.ID != "b2" || bookmarks[1].Bookmark.ID != "b1" {
+		t.Errorf("ListCollectionBookmarks() = %v, want [b2 b1] in manual order", bookmarks)
+	}
+	// Bookmarks in the trash keep their place so that restoring them puts them back
+	if !slices.Equal(updated.BookmarkIDs, []string{"b2", "trashed", "b1"}) {
+		t.Errorf("pruned collection = %v, want [b2 trashed b1]", updated.BookmarkIDs)
+	}
+}
+
+func TestCollectionService_ListCollectionBookmarks_HidesNotes(t *testing.T) {
+	mockRepo := &MockCollectionRepository{
+		getCollectionFunc: collectionsByID(model.Collection{ID: "c1", UserID: "owner", BookmarkIDs: []string{"b1"},
+			Collaborators: []model.Collaborator{{UserID: "viewer", Role: model.CollectionRoleViewer}}}),
+	}
+	mockBookmarkRepo := &MockBookmarkRepository{
+		getBookmarkFunc: func(id string) (model.Bookmark, error) {
+			return model.Bookmark{ID: id, UserID: "owner", Notes: "private thoughts"}, nil
+		},
+	}
+	service := NewCollectionService(mockRepo, mockBookmarkRepo, &MockActivityRepository{}, NewPolicy(mockRepo), nil)
+
+	for user, want := range map[string]string{"owner": "private thoughts", "viewer": ""} {
+		bookmarks, err := service.ListCollectionBookmarks(user, "c1")
+		if err != nil {
+			t.Fatalf("ListCollectionBookmarks() by %s unexpected error = %v", user, err)
+		}
+		if len(bookmarks) != 1 || bookmarks[0].Bookmark.Notes != want {
+			t.Errorf("ListCollectionBookmarks() by %s = %+v, want notes %q", user, bookmarks, want)
+		}
+	}
+}
+
+func TestCollectionService_SharedCollectionRoles(t *testing.T) {
+	collection := model.Collection{
+		ID:          "c1",
+		UserID:      "owner",
+		BookmarkIDs: []string{"b1"},
+		Collaborators: []model.Collaborator{
+			{UserID: "editor", Role: model.CollectionRoleEditor},
+			{UserID: "viewer", Role: model.CollectionRoleViewer},
+		},
+	}
+	mockRepo := &MockCollectionRepository{
+		getCollectionFunc: func(id string) (model.Collection, error) {
+			return collectionsByID(collection)(id)
+		},
+		updateCollectionFunc: func(c model.Collection) (model.Collection, error) {
+			collection = c
+			return c, nil
+		},
+	}
+	mockBookmarkRepo := &MockBookmarkRepository{
+		getBookmarkFunc: func(id string) (model.Bookmark, error) {
+			owners := map[string]string{"b1": "owner", "b2": "editor", "b3": "viewer"}
+			return model.Bookmark{ID: id, UserID: owners[id]}, nil
+		},
+	}
+	activityRepo := &MockActivityRepository{}
+	service := NewCollectionService(mockRepo, mockBookmarkRepo, activityRepo, NewPolicy(mockRepo), nil)
+
+	if _, err := service.AddBookmark("viewer", "c1", "b3", -1); !errors.Is(err, model.ErrForbidden) {
+		t.Errorf("AddBookmark() by viewer error = %v, want ErrForbidden", err)
+	}
+	if _, err := service.AddBookmark("editor", "c1", "b2", 0); err != nil {
+		t.Fatalf("AddBookmark() by editor unexpected error = %v", err)
+	}
+	if _, err := service.UpdateCollection("editor", model.Collection{ID: "c1", Name: "Renamed"}); !errors.Is(err, model.ErrForbidden) {
+		t.Errorf("UpdateCollection() by editor error = %v, want ErrForbidden", err)
+	}
+
+	bookmarks, err := service.ListCollectionBookmarks("viewer", "c1")
+	if err != nil {
+		t.Fatalf("ListCollectionBookmarks() by viewer unexpected error = %v", err)
+	}
+	if len(bookmarks) != 2 || bookmarks[0].AddedBy != "editor" || bookmarks[1].AddedBy != "owner" {
+		t.Errorf("ListCollectionBookmarks() = %+v, want b2 added by editor then b1 added by owner", bookmarks)
+	}
+
+	activities, err := service.ListActivity("viewer", "c1", 0)
+	if err != nil {
+		t.Fatalf("ListActivity() unexpected error = %v", err)
+	}
+	if len(activities) != 1 || activities[0].ActorID != "editor" || activities[0].Action != model.ActivityBookmarkAdded {
+		t.Errorf("ListActivity() = %+v, want one bookmark_added by editor", activities)
+	}
+
+	if _, err := service.ListActivity("stranger", "c1", 0); !errors.Is(err, model.ErrForbidden) {
+		t.Errorf("ListActivity() by stranger error = %v, want ErrForbidden", err)
+	}
+}
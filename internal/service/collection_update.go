@@ -0,0 +1,40 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/tsongpon/athena/internal/logger"
+	"github.com/tsongpon/athena/internal/model"
+	"go.uber.org/zap"
+)
+
+// updateCollection reads a collection, changes it with change and stores it, reading and
+// changing it again when another write got in between, as updateBookmark does for bookmarks.
+// change must only depend on the collection it is given. An error from change stops the update
+// as is.
+func updateCollection(repo CollectionRepository, id string, change func(c *model.Collection) error) (model.Collection, error) {
+	if id == "" {
+		return model.Collection{}, fmt.Errorf("id is required: %w", model.ErrInvalidInput)
+	}
+	for attempt := 1; ; attempt++ {
+		c, err := repo.GetCollection(id)
+		if err != nil {
+			return model.Collection{}, fmt.Errorf("failed to get collection with ID %s: %w", id, err)
+		}
+		if err := change(&c); err != nil {
+			return model.Collection{}, err
+		}
+		updated, err := repo.UpdateCollection(c)
+		if errors.Is(err, model.ErrConflict) && attempt < maxUpdateAttempts {
+			logger.Debug("Collection was changed concurrently, updating it again",
+				zap.String("id", id),
+				zap.Int("attempt", attempt))
+			continue
+		}
+		if err != nil {
+			return model.Collection{}, fmt.Errorf("failed to update collection with ID %s: %w", id, err)
+		}
+		return updated, nil
+	}
+}
@@ -0,0 +1,106 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/tsongpon/athena/internal/model"
+)
+
+// cursorPayload is the signed content of a pagination cursor
+type cursorPayload struct {
+	Sort      string     `json:"s"`
+	Order     string     `json:"o"`
+	Time      *time.Time `json:"t,omitempty"`
+	Text      string     `json:"x,omitempty"`
+	CreatedAt time.Time  `json:"c"`
+	ID        string     `json:"i"`
+	Backward  bool       `json:"b,omitempty"`
+}
+
+// cursorSigner turns listing positions into opaque cursors and back. Cursors are signed so
+// that clients cannot craft positions, and only need to be valid for as long as the key is.
+type cursorSigner struct {
+	key []byte
+}
+
+// newCursorSigner creates a signer with key, or with a random key when key is empty, in which
+// case cursors stop working when the process restarts
+func newCursorSigner(key []byte) cursorSigner {
+	if len(key) == 0 {
+		random := make([]byte, 32)
+		rand.Read(random)
+		return cursorSigner{key: random}
+	}
+	return cursorSigner{key: key}
+}
+
+// DeriveCursorKey derives a cursor key from another secret, such as the JWT secret, so that
+// the secret itself never signs cursors
+func DeriveCursorKey(secret string) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("cursor"))
+	return mac.Sum(nil)
+}
+
+// encode returns the cursor of a position
+func (s cursorSigner) encode(position model.BookmarkPosition) string {
+	p := cursorPayload{
+		Sort:      position.Sort,
+		Order:     position.Order,
+		Text:      position.Text,
+		CreatedAt: position.CreatedAt,
+		ID:        position.ID,
+		Backward:  position.Backward,
+	}
+	if !position.Time.IsZero() && position.Sort != model.BookmarkSortCreated {
+		p.Time = &position.Time
+	}
+	payload, _ := json.Marshal(p)
+	return base64.RawURLEncoding.EncodeToString(payload) + "." + base64.RawURLEncoding.EncodeToString(s.sign(payload))
+}
+
+// decode verifies a cursor and returns its position
+func (s cursorSigner) decode(cursor string) (model.BookmarkPosition, error) {
+	encodedPayload, encodedMAC, ok := strings.Cut(cursor, ".")
+	if !ok {
+		return model.BookmarkPosition{}, fmt.Errorf("malformed cursor: %w", model.ErrInvalidInput)
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if err != nil {
+		return model.BookmarkPosition{}, fmt.Errorf("malformed cursor: %w", model.ErrInvalidInput)
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(encodedMAC)
+	if err != nil || !hmac.Equal(mac, s.sign(payload)) {
+		return model.BookmarkPosition{}, fmt.Errorf("invalid cursor signature: %w", model.ErrInvalidInput)
+	}
+
+	var p cursorPayload
+	if err := json.Unmarshal(payload, &p); err != nil || p.ID == "" {
+		return model.BookmarkPosition{}, fmt.Errorf("malformed cursor: %w", model.ErrInvalidInput)
+	}
+	position := model.BookmarkPosition{
+		Sort:      p.Sort,
+		Order:     p.Order,
+		Text:      p.Text,
+		CreatedAt: p.CreatedAt,
+		ID:        p.ID,
+		Backward:  p.Backward,
+	}
+	if p.Time != nil {
+		position.Time = *p.Time
+	}
+	return position, nil
+}
+
+func (s cursorSigner) sign(payload []byte) []byte {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
@@ -0,0 +1,162 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/tsongpon/athena/internal/model"
+)
+
+// DefaultEntitlements returns the limits granted to each tier
+func DefaultEntitlements() map[string]model.Entitlements {
+	return map[string]model.Entitlements{
+		model.TierFree: {
+			MaxBookmarks:      200,
+			SummariesPerMonth: 0,
+			ImportsPerDay:     1,
+			MaxAPITokens:      1,
+			SemanticSearch:    false,
+		},
+		model.TierPaid: {
+			MaxBookmarks:      model.Unlimited,
+			SummariesPerMonth: 300,
+			ImportsPerDay:     20,
+			MaxAPITokens:      10,
+			SemanticSearch:    true,
+		},
+	}
+}
+
+// EntitlementService enforces per-tier limits and tracks usage counters
+type EntitlementService struct {
+	userRepository     UserRepository
+	bookmarkRepository BookmarkRepository
+	usageRepository    UsageRepository
+	tiers              map[string]model.Entitlements
+}
+
+// NewEntitlementService creates a new instance of EntitlementService using DefaultEntitlements
+func NewEntitlementService(userRepo UserRepository, bookmarkRepo BookmarkRepository, usageRepo UsageRepository) *EntitlementService {
+	return &EntitlementService{
+		userRepository:     userRepo,
+		bookmarkRepository: bookmarkRepo,
+		usageRepository:    usageRepo,
+		tiers:              DefaultEntitlements(),
+	}
+}
+
+// EntitlementsFor returns the entitlements of a tier. Unknown tiers get the free tier limits.
+func (s *EntitlementService) EntitlementsFor(tier string) model.Entitlements {
+	if e, ok := s.tiers[tier]; ok {
+		return e
+	}
+	return s.tiers[model.TierFree]
+}
+
+// CheckBookmarkQuota returns ErrQuotaExceeded when the user cannot create another bookmark. The
+// limit is soft: counting and the create that follows are not atomic, so concurrent creates or
+// restores can each pass the check and leave the user a few bookmarks over it.
+func (s *EntitlementService) CheckBookmarkQuota(user model.User) error {
+	limit := s.EntitlementsFor(user.Tier).MaxBookmarks
+	if limit == model.Unlimited {
+		return nil
+	}
+
+	count, err := s.countBookmarks(user.ID)
+	if err != nil {
+		return err
+	}
+	if count >= limit {
+		return fmt.Errorf("%s tier allows %d bookmarks: %w", user.Tier, limit, model.ErrQuotaExceeded)
+	}
+
+	return nil
+}
+
+// CheckSummaryQuota returns ErrFeatureNotAvailable when the tier has no summaries and
+// ErrQuotaExceeded when this month's summaries are used up
+func (s *EntitlementService) CheckSummaryQuota(user model.User) error {
+	limit := s.EntitlementsFor(user.Tier).SummariesPerMonth
+	if limit == 0 {
+		return fmt.Errorf("content summaries: %w", model.ErrFeatureNotAvailable)
+	}
+	return s.checkCounter(user, model.UsageSummaries, monthPeriod(time.Now()), limit)
+}
+
+// RecordSummary counts a generated summary against the current month
+func (s *EntitlementService) RecordSummary(userID string) error {
+	if _, err := s.usageRepository.IncrementUsage(userID, model.UsageSummaries, monthPeriod(time.Now())); err != nil {
+		return fmt.Errorf("failed to record summary usage for user %s: %w", userID, err)
+	}
+	return nil
+}
+
+// GetUsage returns the user's consumption against their tier's entitlements
+func (s *EntitlementService) GetUsage(userID string) (model.Usage, error) {
+	user, err := s.userRepository.GetUserByID(userID)
+	if err != nil {
+		return model.Usage{}, fmt.Errorf("failed to get user with ID %s: %w", userID, err)
+	}
+
+	bookmarks, err := s.countBookmarks(userID)
+	if err != nil {
+		return model.Usage{}, err
+	}
+
+	now := time.Now()
+	summaries, err := s.usageRepository.GetUsage(userID, model.UsageSummaries, monthPeriod(now))
+	if err != nil {
+		return model.Usage{}, fmt.Errorf("failed to get summary usage for user %s: %w", userID, err)
+	}
+	imports, err := s.usageRepository.GetUsage(userID, model.UsageImports, dayPeriod(now))
+	if err != nil {
+		return model.Usage{}, fmt.Errorf("failed to get import usage for user %s: %w", userID, err)
+	}
+
+	return model.Usage{
+		Tier:               user.Tier,
+		Entitlements:       s.EntitlementsFor(user.Tier),
+		Bookmarks:          bookmarks,
+		SummariesThisMonth: summaries,
+		ImportsToday:       imports,
+	}, nil
+}
+
+func (s *EntitlementService) checkCounter(user model.User, metric, period string, limit int) error {
+	if limit == model.Unlimited {
+		return nil
+	}
+
+	used, err := s.usageRepository.GetUsage(user.ID, metric, period)
+	if err != nil {
+		return fmt.Errorf("failed to get %s usage for user %s: %w", metric, user.ID, err)
+	}
+	if used >= limit {
+		return fmt.Errorf("%s tier allows %d %s per period %s: %w", user.Tier, limit, metric, period, model.ErrQuotaExceeded)
+	}
+
+	return nil
+}
+
+// countBookmarks counts both active and archived bookmarks of a user
+func (s *EntitlementService) countBookmarks(userID string) (int, error) {
+	active, err := s.bookmarkRepository.CountBookmarks(model.BookmarkQuery{UserID: userID, Archived: false})
+	if err != nil {
+		return 0, fmt.Errorf("failed to count bookmarks for user %s: %w", userID, err)
+	}
+	archived, err := s.bookmarkRepository.CountBookmarks(model.BookmarkQuery{UserID: userID, Archived: true})
+	if err != nil {
+		return 0, fmt.Errorf("failed to count archived bookmarks for user %s: %w", userID, err)
+	}
+	return active + archived, nil
+}
+
+// monthPeriod returns the usage period key for the calendar month of t in UTC
+func monthPeriod(t time.Time) string {
+	return t.UTC().Format("2006-01")
+}
+
+// dayPeriod returns the usage period key for the calendar day of t in UTC
+func dayPeriod(t time.Time) string {
+	return t.UTC().Format("2006-01-02")
+}
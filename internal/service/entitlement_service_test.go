@@ -0,0 +1,110 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/tsongpon/athena/internal/model"
+)
+
+func TestEntitlementService_EntitlementsFor_UnknownTier(t *testing.T) {
+	service := NewEntitlementService(&MockUserRepository{}, &MockBookmarkRepository{}, &MockUsageRepository{})
+
+	got := service.EntitlementsFor("legacy")
+	want := DefaultEntitlements()[model.TierFree]
+	if got != want {
+		t.Errorf("EntitlementsFor() = %+v, want free tier %+v", got, want)
+	}
+}
+
+func TestEntitlementService_CheckBookmarkQuota(t *testing.T) {
+	limit := DefaultEntitlements()[model.TierFree].MaxBookmarks
+	mockBookmarkRepo := &MockBookmarkRepository{
+		countBookmarksFunc: func(query model.BookmarkQuery) (int, error) {
+			if query.Archived {
+				return 1, nil
+			}
+			return limit - 1, nil
+		},
+	}
+	service := NewEntitlementService(&MockUserRepository{}, mockBookmarkRepo, &MockUsageRepository{})
+
+	err := service.CheckBookmarkQuota(model.User{ID: "user-1", Tier: model.TierFree})
+	if !errors.Is(err, model.ErrQuotaExceeded) {
+		t.Errorf("CheckBookmarkQuota() free tier at limit error = %v, want ErrQuotaExceeded", err)
+	}
+
+	if err := service.CheckBookmarkQuota(model.User{ID: "user-1", Tier: model.TierPaid}); err != nil {
+		t.Errorf("CheckBookmarkQuota() paid tier unexpected error = %v", err)
+	}
+}
+
+func TestEntitlementService_CheckSummaryQuota(t *testing.T) {
+	used := 0
+	mockUsageRepo := &MockUsageRepository{
+		getUsageFunc: func(userID, metric, period string) (int, error) {
+			if metric != model.UsageSummaries {
+				t.Errorf("CheckSummaryQuota() metric = %v, want %v", metric, model.UsageSummaries)
+			}
+			return used, nil
+		},
+	}
+	service := NewEntitlementService(&MockUserRepository{}, &MockBookmarkRepository{}, mockUsageRepo)
+
+	err := service.CheckSummaryQuota(model.User{ID: "user-1", Tier: model.TierFree})
+	if !errors.Is(err, model.ErrFeatureNotAvailable) {
+		t.Errorf("CheckSummaryQuota() free tier error = %v, want ErrFeatureNotAvailable", err)
+	}
+
+	paid := model.User{ID: "user-2", Tier: model.TierPaid}
+	if err := service.CheckSummaryQuota(paid); err != nil {
+		t.Errorf("CheckSummaryQuota() paid tier unexpected error = %v", err)
+	}
+
+	used = DefaultEntitlements()[model.TierPaid].SummariesPerMonth
+	if err := service.CheckSummaryQuota(paid); !errors.Is(err, model.ErrQuotaExceeded) {
+		t.Errorf("CheckSummaryQuota() exhausted error = %v, want ErrQuotaExceeded", err)
+	}
+}
+
+func TestEntitlementService_GetUsage(t *testing.T) {
+	mockUserRepo := &MockUserRepository{
+		getUserByIDFunc: func(id string) (model.User, error) {
+			return model.User{ID: id, Tier: model.TierPaid}, nil
+		},
+	}
+	mockBookmarkRepo := &MockBookmarkRepository{
+		countBookmarksFunc: func(query model.BookmarkQuery) (int, error) {
+			return 3, nil
+		},
+	}
+	mockUsageRepo := &MockUsageRepository{
+		getUsageFunc: func(userID, metric, period string) (int, error) {
+			if metric == model.UsageSummaries {
+				return 12, nil
+			}
+			return 2, nil
+		},
+	}
+	service := NewEntitlementService(mockUserRepo, mockBookmarkRepo, mockUsageRepo)
+
+	usage, err := service.GetUsage("user-1")
+	if err != nil {
+		t.Fatalf("GetUsage() unexpected error = %v", err)
+	}
+	if usage.Tier != model.TierPaid {
+		t.Errorf("GetUsage() Tier = %v, want %v", usage.Tier, model.TierPaid)
+	}
+	if usage.Bookmarks != 6 {
+		t.Errorf("GetUsage() Bookmarks = %v, want 6", usage.Bookmarks)
+	}
+	if usage.SummariesThisMonth != 12 {
+		t.Errorf("GetUsage() SummariesThisMonth = %v, want 12", usage.SummariesThisMonth)
+	}
+	if usage.ImportsToday != 2 {
+		t.Errorf("GetUsage() ImportsToday = %v, want 2", usage.ImportsToday)
+	}
+	if usage.Entitlements.MaxBookmarks != model.Unlimited {
+		t.Errorf("GetUsage() MaxBookmarks = %v, want unlimited", usage.Entitlements.MaxBookmarks)
+	}
+}
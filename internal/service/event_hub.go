@@ -0,0 +1,204 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/tsongpon/athena/internal/logger"
+	"github.com/tsongpon/athena/internal/model"
+	"go.uber.org/zap"
+)
+
+// Limits of the per-user event buffers
+const (
+	eventReplaySize     = 100       // Recent events kept for streams resuming from a Last-Event-ID
+	eventReplayAge      = time.Hour // How long events are kept for resuming
+	eventSubscriberSize = 64        // Events queued for a stream before it is dropped as too slow
+)
+
+// EventHub fans bookmark changes out to the open event streams of their owner. Without a
+// broker events stay in this process; with one they go through the broker so that every
+// instance delivers them. A nil *EventHub publishes nothing, for services used without streams.
+type EventHub struct {
+	broker      EventBroker
+	listeners   []func(model.Event)
+	mutex       sync.Mutex
+	subscribers map[string]map[chan model.Event]struct{} // By user ID
+	recent      map[string][]model.Event                 // By user ID, oldest first
+	prunedAt    time.Time
+	lastNanos   int64
+}
+
+// NewEventHub creates a new instance of EventHub. broker may be nil for a single instance.
+func NewEventHub(broker EventBroker) *EventHub {
+	return &EventHub{
+		broker:      broker,
+		subscribers: make(map[string]map[chan model.Event]struct{}),
+		recent:      make(map[string][]model.Event),
+	}
+}
+
+// Listen calls listener with every event published through this hub, before it reaches
+// the streams. Unlike streams, listeners only get the events of their own instance, so each
+// event is handled once however many instances run. Listen is not safe to call concurrently
+// with Publish; register listeners at startup.
+func (h *EventHub) Listen(listener func(model.Event)) {
+	h.listeners = append(h.listeners, listener)
+}
+
+// Run delivers the events of the broker until ctx is done, resubscribing after errors. It
+// returns at once without a broker.
+func (h *EventHub) Run(ctx context.Context) {
+	if h.broker == nil {
+		return
+	}
+	for {
+		err := h.broker.Subscribe(ctx, h.deliver)
+		if ctx.Err() != nil {
+			return
+		}
+		logger.Warn("Event broker subscription ended, resubscribing", zap.Error(err))
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(5 * time.Second):
+		}
+	}
+}
+
+// Publish sends a change of bookmark b to the streams of its owner. Failures are logged
+// rather than returned: the change itself has been saved, and clients catch up on reconnect.
+func (h *EventHub) Publish(eventType string, b model.Bookmark) {
+	if h == nil {
+		return
+	}
+	if eventType == model.EventBookmarkDeleted {
+		b = model.Bookmark{ID: b.ID, UserID: b.UserID}
+	}
+	event := model.Event{ID: h.nextID(), UserID: b.UserID, Type: eventType, Bookmark: b, At: time.Now()}
+	for _, listener := range h.listeners {
+		listener(event)
+	}
+	if h.broker == nil {
+		h.deliver(event)
+		return
+	}
+	if err := h.broker.Publish(event); err != nil {
+		logger.Warn("Failed to publish event",
+			zap.String("type", eventType),
+			zap.String("bookmark_id", b.ID),
+			zap.Error(err))
+	}
+}
+
+// Subscribe opens a stream of the events of userID. Events after lastEventID are replayed
+// when they are still kept; otherwise the subscription asks the client to reload.
+func (h *EventHub) Subscribe(userID, lastEventID string) (model.EventSubscription, error) {
+	if userID == "" {
+		return model.EventSubscription{}, fmt.Errorf("user ID is required: %w", model.ErrInvalidInput)
+	}
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	var sub model.EventSubscription
+	if lastEventID != "" {
+		sub.Replay, sub.Reset = replayAfter(h.recent[userID], lastEventID)
+	}
+
+	events := make(chan model.Event, eventSubscriberSize)
+	if h.subscribers[userID] == nil {
+		h.subscribers[userID] = make(map[chan model.Event]struct{})
+	}
+	h.subscribers[userID][events] = struct{}{}
+	sub.Events = events
+
+	var once sync.Once
+	sub.Close = func() {
+		once.Do(func() {
+			h.mutex.Lock()
+			defer h.mutex.Unlock()
+			h.unsubscribe(userID, events)
+		})
+	}
+	return sub, nil
+}
+
+// replayAfter returns the events of recent after lastEventID. reset is true when events
+// after it may have been dropped from the buffer.
+func replayAfter(recent []model.Event, lastEventID string) (replay []model.Event, reset bool) {
+	for i, event := range recent {
+		if event.ID == lastEventID {
+			return recent[i+1:], false
+		}
+	}
+	// Unknown IDs newer than everything kept missed nothing; older ones may have missed events
+	if len(recent) == 0 || lastEventID > recent[len(recent)-1].ID {
+		return nil, false
+	}
+	return nil, true
+}
+
+// deliver records an event for replay and queues it for the streams of its user
+func (h *EventHub) deliver(event model.Event) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+
+	recent := append(h.recent[event.UserID], event)
+	if len(recent) > eventReplaySize {
+		recent = recent[len(recent)-eventReplaySize:]
+	}
+	h.recent[event.UserID] = recent
+	h.prune(event.At)
+
+	for events := range h.subscribers[event.UserID] {
+		select {
+		case events <- event:
+		default:
+			// A stream that cannot keep up is closed; the client resumes with Last-Event-ID
+			logger.Warn("Dropping slow event stream", zap.String("user_id", event.UserID))
+			h.unsubscribe(event.UserID, events)
+		}
+	}
+}
+
+// prune forgets the replay buffers of users without events in eventReplayAge, at most once
+// a minute; the caller holds the mutex
+func (h *EventHub) prune(now time.Time) {
+	if now.Sub(h.prunedAt) < time.Minute {
+		return
+	}
+	h.prunedAt = now
+	for userID, recent := range h.recent {
+		if now.Sub(recent[len(recent)-1].At) > eventReplayAge {
+			delete(h.recent, userID)
+		}
+	}
+}
+
+// unsubscribe removes and closes a stream; the caller holds the mutex
+func (h *EventHub) unsubscribe(userID string, events chan model.Event) {
+	if _, ok := h.subscribers[userID][events]; !ok {
+		return
+	}
+	delete(h.subscribers[userID], events)
+	if len(h.subscribers[userID]) == 0 {
+		delete(h.subscribers, userID)
+	}
+	close(events)
+}
+
+// nextID returns an event ID that sorts after every earlier ID of this hub: the time in
+// nanoseconds, then random digits that keep IDs of different instances apart
+func (h *EventHub) nextID() string {
+	suffix := make([]byte, 4)
+	rand.Read(suffix)
+
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.lastNanos = max(time.Now().UnixNano(), h.lastNanos+1)
+	return fmt.Sprintf("%016x-%s", h.lastNanos, hex.EncodeToString(suffix))
+}
@@ -0,0 +1,165 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/tsongpon/athena/internal/model"
+)
+
+// MockEventBroker is a mock implementation of EventBroker that delivers published events
+// to its subscriber
+type MockEventBroker struct {
+	events chan model.Event
+}
+
+func (m *MockEventBroker) Publish(event model.Event) error {
+	m.events <- event
+	return nil
+}
+
+func (m *MockEventBroker) Subscribe(ctx context.Context, deliver func(model.Event)) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case event := <-m.events:
+			deliver(event)
+		}
+	}
+}
+
+// receiveEvent waits for the next event of a subscription
+func receiveEvent(t *testing.T, sub model.EventSubscription) model.Event {
+	t.Helper()
+	select {
+	case event := <-sub.Events:
+		return event
+	case <-time.After(time.Second):
+		t.Fatal("no event received")
+		return model.Event{}
+	}
+}
+
+func TestEventHub_PublishDeliversToOwner(t *testing.T) {
+	hub := NewEventHub(nil)
+	owner, err := hub.Subscribe("user-1", "")
+	if err != nil {
+		t.Fatalf("Subscribe() unexpected error = %v", err)
+	}
+	defer owner.Close()
+	other, _ := hub.Subscribe("user-2", "")
+	defer other.Close()
+
+	hub.Publish(model.EventBookmarkCreated, model.Bookmark{ID: "b1", UserID: "user-1", Title: "Go"})
+
+	event := receiveEvent(t, owner)
+	if event.Type != model.EventBookmarkCreated || event.Bookmark.Title != "Go" || event.ID == "" {
+		t.Errorf("Publish() delivered %+v, want the created bookmark with an ID", event)
+	}
+	select {
+	case event := <-other.Events:
+		t.Errorf("Publish() delivered %+v to another user", event)
+	default:
+	}
+}
+
+func TestEventHub_PublishDeletedSendsOnlyID(t *testing.T) {
+	hub := NewEventHub(nil)
+	sub, _ := hub.Subscribe("user-1", "")
+	defer sub.Close()
+
+	hub.Publish(model.EventBookmarkDeleted, model.Bookmark{ID: "b1", UserID: "user-1", Title: "Go", Notes: "private"})
+
+	event := receiveEvent(t, sub)
+	if event.Bookmark.ID != "b1" || event.Bookmark.Title != "" || event.Bookmark.Notes != "" {
+		t.Errorf("Publish() deleted bookmark = %+v, want only the IDs", event.Bookmark)
+	}
+}
+
+func TestEventHub_SubscribeResumesFromLastEventID(t *testing.T) {
+	hub := NewEventHub(nil)
+	hub.Publish(model.EventBookmarkCreated, model.Bookmark{ID: "b1", UserID: "user-1"})
+	hub.Publish(model.EventBookmarkUpdated, model.Bookmark{ID: "b1", UserID: "user-1"})
+	hub.Publish(model.EventBookmarkDeleted, model.Bookmark{ID: "b1", UserID: "user-1"})
+	first, _ := hub.Subscribe("user-1", "")
+	first.Close()
+	recent := hub.recent["user-1"]
+
+	tests := []struct {
+		name        string
+		lastEventID string
+		wantReplay  []string
+		wantReset   bool
+	}{
+		{name: "missed events", lastEventID: recent[0].ID, wantReplay: []string{model.EventBookmarkUpdated, model.EventBookmarkDeleted}},
+		{name: "up to date", lastEventID: recent[2].ID},
+		{name: "newer than kept events", lastEventID: "ffffffffffffffff-00000000"},
+		{name: "older than kept events", lastEventID: "0000000000000000-00000000", wantReset: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sub, err := hub.Subscribe("user-1", tt.lastEventID)
+			if err != nil {
+				t.Fatalf("Subscribe() unexpected error = %v", err)
+			}
+			defer sub.Close()
+
+			var replay []string
+			for _, event := range sub.Replay {
+				replay = append(replay, event.Type)
+			}
+			if len(replay) != len(tt.wantReplay) {
+				t.Fatalf("Subscribe() replay = %v, want %v", replay, tt.wantReplay)
+			}
+			for i := range replay {
+				if replay[i] != tt.wantReplay[i] {
+					t.Errorf("Subscribe() replay = %v, want %v", replay, tt.wantReplay)
+				}
+			}
+			if sub.Reset != tt.wantReset {
+				t.Errorf("Subscribe() reset = %v, want %v", sub.Reset, tt.wantReset)
+			}
+		})
+	}
+}
+
+func TestEventHub_DropsSlowSubscriber(t *testing.T) {
+	hub := NewEventHub(nil)
+	sub, _ := hub.Subscribe("user-1", "")
+	defer sub.Close()
+
+	for range eventSubscriberSize + 1 {
+		hub.Publish(model.EventBookmarkUpdated, model.Bookmark{ID: "b1", UserID: "user-1"})
+	}
+
+	received := 0
+	for range sub.Events {
+		received++
+	}
+	if received != eventSubscriberSize {
+		t.Errorf("slow subscriber received %d events before being closed, want %d", received, eventSubscriberSize)
+	}
+}
+
+func TestEventHub_PublishesThroughBroker(t *testing.T) {
+	broker := &MockEventBroker{events: make(chan model.Event, 1)}
+	hub := NewEventHub(broker)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go hub.Run(ctx)
+	sub, _ := hub.Subscribe("user-1", "")
+	defer sub.Close()
+
+	hub.Publish(model.EventBookmarkEnriched, model.Bookmark{ID: "b1", UserID: "user-1"})
+
+	if event := receiveEvent(t, sub); event.Type != model.EventBookmarkEnriched {
+		t.Errorf("Publish() through broker delivered %+v, want the enriched event", event)
+	}
+}
+
+func TestEventHub_NilPublishesNothing(t *testing.T) {
+	var hub *EventHub
+	hub.Publish(model.EventBookmarkCreated, model.Bookmark{ID: "b1", UserID: "user-1"})
+}
@@ -0,0 +1,174 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/tsongpon/athena/internal/logger"
+	"github.com/tsongpon/athena/internal/model"
+	"go.uber.org/zap"
+)
+
+const (
+	// feedTokenBytes is the amount of randomness in a feed token (256 bits)
+	feedTokenBytes = 32
+	// feedSize is the number of newest bookmarks included in a feed
+	feedSize = 50
+)
+
+// FeedService issues revocable feed tokens and builds the bookmark feeds they unlock
+type FeedService struct {
+	feedTokenRepository  FeedTokenRepository
+	bookmarkRepository   BookmarkRepository
+	collectionRepository CollectionRepository
+	policy               *Policy
+}
+
+// NewFeedService creates a new instance of FeedService
+func NewFeedService(feedTokenRepo FeedTokenRepository, bookmarkRepo BookmarkRepository, collectionRepo CollectionRepository, policy *Policy) *FeedService {
+	return &FeedService{
+		feedTokenRepository:  feedTokenRepo,
+		bookmarkRepository:   bookmarkRepo,
+		collectionRepository: collectionRepo,
+		policy:               policy,
+	}
+}
+
+// CreateFeedToken issues a feed token for userID's bookmarks, or for one collection
+// userID can view when collectionID is set
+func (s *FeedService) CreateFeedToken(userID, collectionID string) (model.FeedToken, error) {
+	if collectionID != "" {
+		if _, err := s.getViewableCollection(userID, collectionID); err != nil {
+			return model.FeedToken{}, err
+		}
+	}
+
+	token, err := newRandomToken(feedTokenBytes)
+	if err != nil {
+		return model.FeedToken{}, fmt.Errorf("failed to generate feed token: %w", err)
+	}
+	created, err := s.feedTokenRepository.CreateFeedToken(model.FeedToken{
+		Token:        token,
+		UserID:       userID,
+		CollectionID: collectionID,
+	})
+	if err != nil {
+		return model.FeedToken{}, fmt.Errorf("failed to create feed token: %w", err)
+	}
+
+	logger.Info("Created feed token",
+		zap.String("user_id", userID),
+		zap.String("collection_id", collectionID))
+
+	return created, nil
+}
+
+// ListFeedTokens lists the feed tokens of userID, newest first
+func (s *FeedService) ListFeedTokens(userID string) ([]model.FeedToken, error) {
+	tokens, err := s.feedTokenRepository.ListFeedTokens(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list feed tokens for user %s: %w", userID, err)
+	}
+	return tokens, nil
+}
+
+// RevokeFeedToken deletes a feed token so its feed URLs stop working immediately
+func (s *FeedService) RevokeFeedToken(userID, token string) error {
+	feedToken, err := s.feedTokenRepository.GetFeedToken(token)
+	if err != nil {
+		return fmt.Errorf("failed to get feed token: %w", err)
+	}
+	if err := s.policy.CanManageFeedToken(userID, feedToken); err != nil {
+		return err
+	}
+	if err := s.feedTokenRepository.DeleteFeedToken(token); err != nil {
+		return fmt.Errorf("failed to delete feed token: %w", err)
+	}
+
+	logger.Info("Revoked feed token", zap.String("user_id", userID))
+
+	return nil
+}
+
+// GetFeed builds the feed unlocked by token: the newest unarchived bookmarks of its owner,
+// or the newest bookmarks of its collection. Access to a collection is checked again on
+// every read so a collaborator who leaves loses the feed too.
+func (s *FeedService) GetFeed(token string) (model.Feed, error) {
+	feedToken, err := s.feedTokenRepository.GetFeedToken(token)
+	if err != nil {
+		return model.Feed{}, fmt.Errorf("failed to get feed token: %w", err)
+	}
+
+	feed := model.Feed{
+		Title:        "Bookmarks",
+		UserID:       feedToken.UserID,
+		CollectionID: feedToken.CollectionID,
+		UpdatedAt:    feedToken.CreatedAt,
+	}
+	if feedToken.CollectionID == "" {
+		feed.Bookmarks, err = s.bookmarkRepository.ListBookmarks(model.BookmarkQuery{
+			UserID:   feedToken.UserID,
+			Page:     1,
+			PageSize: feedSize,
+		})
+		if err != nil {
+			return model.Feed{}, fmt.Errorf("failed to get bookmarks: %w", err)
+		}
+	} else {
+		c, err := s.getViewableCollection(feedToken.UserID, feedToken.CollectionID)
+		if err != nil {
+			return model.Feed{}, err
+		}
+		feed.Title = c.Name
+		feed.Bookmarks, err = s.collectionBookmarks(c)
+		if err != nil {
+			return model.Feed{}, err
+		}
+	}
+
+	for _, b := range feed.Bookmarks {
+		if b.CreatedAt.After(feed.UpdatedAt) {
+			feed.UpdatedAt = b.CreatedAt
+		}
+		if b.UpdatedAt.After(feed.UpdatedAt) {
+			feed.UpdatedAt = b.UpdatedAt
+		}
+	}
+
+	return feed, nil
+}
+
+// collectionBookmarks returns the newest bookmarks of a collection, skipping deleted ones
+func (s *FeedService) collectionBookmarks(c model.Collection) ([]model.Bookmark, error) {
+	bookmarks := []model.Bookmark{}
+	for _, id := range c.BookmarkIDs {
+		b, err := s.bookmarkRepository.GetBookmark(id)
+		if errors.Is(err, model.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get bookmark with ID %s: %w", id, err)
+		}
+		bookmarks = append(bookmarks, b)
+	}
+
+	sort.SliceStable(bookmarks, func(i, j int) bool {
+		return bookmarks[i].CreatedAt.After(bookmarks[j].CreatedAt)
+	})
+	if len(bookmarks) > feedSize {
+		bookmarks = bookmarks[:feedSize]
+	}
+	return bookmarks, nil
+}
+
+func (s *FeedService) getViewableCollection(userID, collectionID string) (model.Collection, error) {
+	c, err := s.collectionRepository.GetCollection(collectionID)
+	if err != nil {
+		return model.Collection{}, fmt.Errorf("failed to get collection with ID %s: %w", collectionID, err)
+	}
+	if err := s.policy.CanViewCollection(userID, c); err != nil {
+		return model.Collection{}, err
+	}
+	return c, nil
+}
@@ -0,0 +1,143 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/tsongpon/athena/internal/model"
+)
+
+// MockFeedTokenRepository is a mock implementation of FeedTokenRepository that keeps tokens in a map
+type MockFeedTokenRepository struct {
+	tokens map[string]model.FeedToken
+}
+
+func (m *MockFeedTokenRepository) CreateFeedToken(feedToken model.FeedToken) (model.FeedToken, error) {
+	if m.tokens == nil {
+		m.tokens = make(map[string]model.FeedToken)
+	}
+	m.tokens[feedToken.Token] = feedToken
+	return feedToken, nil
+}
+
+func (m *MockFeedTokenRepository) GetFeedToken(token string) (model.FeedToken, error) {
+	feedToken, exists := m.tokens[token]
+	if !exists {
+		return model.FeedToken{}, model.ErrNotFound
+	}
+	return feedToken, nil
+}
+
+func (m *MockFeedTokenRepository) ListFeedTokens(userID string) ([]model.FeedToken, error) {
+	var tokens []model.FeedToken
+	for _, feedToken := range m.tokens {
+		if feedToken.UserID == userID {
+			tokens = append(tokens, feedToken)
+		}
+	}
+	return tokens, nil
+}
+
+func (m *MockFeedTokenRepository) DeleteFeedToken(token string) error {
+	if _, exists := m.tokens[token]; !exists {
+		return model.ErrNotFound
+	}
+	delete(m.tokens, token)
+	return nil
+}
+
+func TestFeedService_UserFeed(t *testing.T) {
+	now := time.Now()
+	var gotQuery model.BookmarkQuery
+	bookmarkRepo := &MockBookmarkRepository{
+		listBookmarksByQueryFunc: func(query model.BookmarkQuery) ([]model.Bookmark, error) {
+			gotQuery = query
+			return []model.Bookmark{
+				{ID: "b2", UserID: "user-1", CreatedAt: now},
+				{ID: "b1", UserID: "user-1", CreatedAt: now.Add(-time.Hour), UpdatedAt: now.Add(time.Minute)},
+			}, nil
+		},
+	}
+	collectionRepo := &MockCollectionRepository{}
+	service := NewFeedService(&MockFeedTokenRepository{}, bookmarkRepo, collectionRepo, NewPolicy(collectionRepo))
+
+	feedToken, err := service.CreateFeedToken("user-1", "")
+	if err != nil {
+		t.Fatalf("CreateFeedToken() unexpected error = %v", err)
+	}
+	if len(feedToken.Token) < 43 {
+		t.Errorf("CreateFeedToken() token = %q, want at least 256 bits of randomness", feedToken.Token)
+	}
+
+	feed, err := service.GetFeed(feedToken.Token)
+	if err != nil {
+		t.Fatalf("GetFeed() unexpected error = %v", err)
+	}
+	if gotQuery.UserID != "user-1" || gotQuery.Archived || gotQuery.PageSize != feedSize {
+		t.Errorf("GetFeed() queried %+v, want the newest unarchived bookmarks of user-1", gotQuery)
+	}
+	if len(feed.Bookmarks) != 2 || !feed.UpdatedAt.Equal(now.Add(time.Minute)) {
+		t.Errorf("GetFeed() = %+v, want two bookmarks updated at the latest change", feed)
+	}
+
+	if err := service.RevokeFeedToken("user-2", feedToken.Token); !errors.Is(err, model.ErrForbidden) {
+		t.Errorf("RevokeFeedToken() by another user error = %v, want ErrForbidden", err)
+	}
+	if err := service.RevokeFeedToken("user-1", feedToken.Token); err != nil {
+		t.Fatalf("RevokeFeedToken() unexpected error = %v", err)
+	}
+	if _, err := service.GetFeed(feedToken.Token); !errors.Is(err, model.ErrNotFound) {
+		t.Errorf("GetFeed() after revoke error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestFeedService_CollectionFeed(t *testing.T) {
+	now := time.Now()
+	collection := model.Collection{
+		ID:            "c1",
+		UserID:        "owner",
+		Name:          "Team reading",
+		BookmarkIDs:   []string{"old", "deleted", "new"},
+		Collaborators: []model.Collaborator{{UserID: "viewer", Role: model.CollectionRoleViewer}},
+	}
+	collectionRepo := &MockCollectionRepository{
+		getCollectionFunc: func(id string) (model.Collection, error) {
+			return collectionsByID(collection)(id)
+		},
+	}
+	bookmarkRepo := &MockBookmarkRepository{
+		getBookmarkFunc: func(id string) (model.Bookmark, error) {
+			switch id {
+			case "old":
+				return model.Bookmark{ID: id, CreatedAt: now.Add(-time.Hour)}, nil
+			case "new":
+				return model.Bookmark{ID: id, CreatedAt: now}, nil
+			}
+			return model.Bookmark{}, model.ErrNotFound
+		},
+	}
+	service := NewFeedService(&MockFeedTokenRepository{}, bookmarkRepo, collectionRepo, NewPolicy(collectionRepo))
+
+	if _, err := service.CreateFeedToken("stranger", "c1"); !errors.Is(err, model.ErrForbidden) {
+		t.Errorf("CreateFeedToken() by stranger error = %v, want ErrForbidden", err)
+	}
+
+	feedToken, err := service.CreateFeedToken("viewer", "c1")
+	if err != nil {
+		t.Fatalf("CreateFeedToken() unexpected error = %v", err)
+	}
+	feed, err := service.GetFeed(feedToken.Token)
+	if err != nil {
+		t.Fatalf("GetFeed() unexpected error = %v", err)
+	}
+	if feed.Title != "Team reading" || len(feed.Bookmarks) != 2 || feed.Bookmarks[0].ID != "new" {
+		t.Errorf("GetFeed() = %+v, want new then old", feed)
+	}
+
+	// Leaving the collection also ends access through existing feed tokens
+	collection.Collaborators = nil
+	if _, err := service.GetFeed(feedToken.Token); !errors.Is(err, model.ErrForbidden) {
+		t.Errorf("GetFeed() after leaving error = %v, want ErrForbidden", err)
+	}
+}
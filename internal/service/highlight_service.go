@@ -0,0 +1,162 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/tsongpon/athena/internal/model"
+)
+
+// HighlightService manages the highlights of bookmarks. Highlights are personal, so only the
+// owner of a bookmark can see or change them, even when it is in a shared collection.
+type HighlightService struct {
+	highlightRepository HighlightRepository
+	bookmarkRepository  BookmarkRepository
+	policy              *Policy
+}
+
+// NewHighlightService creates a new instance of HighlightService
+func NewHighlightService(highlightRepo HighlightRepository, bookmarkRepo BookmarkRepository, policy *Policy) *HighlightService {
+	return &HighlightService{
+		highlightRepository: highlightRepo,
+		bookmarkRepository:  bookmarkRepo,
+		policy:              policy,
+	}
+}
+
+// CreateHighlight adds a highlight to a bookmark that userID owns
+func (s *HighlightService) CreateHighlight(userID, bookmarkID string, highlight model.Highlight) (model.Highlight, error) {
+	highlight.Quote = strings.TrimSpace(highlight.Quote)
+	if highlight.Quote == "" {
+		return model.Highlight{}, fmt.Errorf("quote is required: %w", model.ErrInvalidInput)
+	}
+	if utf8.RuneCountInString(highlight.Quote) > model.MaxQuoteLength {
+		return model.Highlight{}, fmt.Errorf("quote can be at most %d characters: %w", model.MaxQuoteLength, model.ErrInvalidInput)
+	}
+	if err := validateNote(highlight.Note); err != nil {
+		return model.Highlight{}, err
+	}
+	if err := validateAnchor(highlight.Anchor); err != nil {
+		return model.Highlight{}, err
+	}
+	if _, err := s.ownedBookmark(userID, bookmarkID); err != nil {
+		return model.Highlight{}, err
+	}
+	existing, err := s.highlightRepository.ListHighlights(bookmarkID)
+	if err != nil {
+		return model.Highlight{}, fmt.Errorf("failed to list highlights of bookmark %s: %w", bookmarkID, err)
+	}
+	if len(existing) >= model.MaxBookmarkHighlights {
+		return model.Highlight{}, fmt.Errorf("a bookmark can have at most %d highlights: %w", model.MaxBookmarkHighlights, model.ErrInvalidInput)
+	}
+
+	highlight.BookmarkID = bookmarkID
+	highlight.UserID = userID
+	created, err := s.highlightRepository.CreateHighlight(highlight)
+	if err != nil {
+		return model.Highlight{}, fmt.Errorf("failed to create highlight: %w", err)
+	}
+	return created, nil
+}
+
+// ListHighlights returns the highlights of a bookmark that userID owns, in the order of the page
+func (s *HighlightService) ListHighlights(userID, bookmarkID string) ([]model.Highlight, error) {
+	if _, err := s.ownedBookmark(userID, bookmarkID); err != nil {
+		return nil, err
+	}
+	highlights, err := s.highlightRepository.ListHighlights(bookmarkID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list highlights of bookmark %s: %w", bookmarkID, err)
+	}
+	return highlights, nil
+}
+
+// UpdateHighlight changes the note or anchor of a highlight. The quote cannot be changed; a
+// different passage is a new highlight.
+func (s *HighlightService) UpdateHighlight(userID, bookmarkID, id string, update model.HighlightUpdate) (model.Highlight, error) {
+	if update.Note == nil && update.Anchor == nil {
+		return model.Highlight{}, fmt.Errorf("note or anchor is required: %w", model.ErrInvalidInput)
+	}
+	if update.Note != nil {
+		if err := validateNote(*update.Note); err != nil {
+			return model.Highlight{}, err
+		}
+	}
+	if update.Anchor != nil {
+		if err := validateAnchor(*update.Anchor); err != nil {
+			return model.Highlight{}, err
+		}
+	}
+	highlight, err := s.getHighlight(userID, bookmarkID, id)
+	if err != nil {
+		return model.Highlight{}, err
+	}
+
+	if update.Note != nil {
+		highlight.Note = *update.Note
+	}
+	if update.Anchor != nil {
+		highlight.Anchor = *update.Anchor
+	}
+	updated, err := s.highlightRepository.UpdateHighlight(highlight)
+	if err != nil {
+		return model.Highlight{}, fmt.Errorf("failed to update highlight with ID %s: %w", id, err)
+	}
+	return updated, nil
+}
+
+// DeleteHighlight deletes a highlight of a bookmark that userID owns
+func (s *HighlightService) DeleteHighlight(userID, bookmarkID, id string) error {
+	if _, err := s.getHighlight(userID, bookmarkID, id); err != nil {
+		return err
+	}
+	if err := s.highlightRepository.DeleteHighlight(id); err != nil {
+		return fmt.Errorf("failed to delete highlight with ID %s: %w", id, err)
+	}
+	return nil
+}
+
+// ownedBookmark loads a bookmark whose highlights userID may see and change
+func (s *HighlightService) ownedBookmark(userID, bookmarkID string) (model.Bookmark, error) {
+	b, err := s.bookmarkRepository.GetBookmark(bookmarkID)
+	if err != nil {
+		return model.Bookmark{}, fmt.Errorf("failed to get bookmark with ID %s: %w", bookmarkID, err)
+	}
+	if err := s.policy.CanModifyBookmark(userID, b); err != nil {
+		return model.Bookmark{}, err
+	}
+	return b, nil
+}
+
+// getHighlight loads a highlight of a bookmark that userID owns. A highlight of another
+// bookmark is reported as not found.
+func (s *HighlightService) getHighlight(userID, bookmarkID, id string) (model.Highlight, error) {
+	if _, err := s.ownedBookmark(userID, bookmarkID); err != nil {
+		return model.Highlight{}, err
+	}
+	highlight, err := s.highlightRepository.GetHighlight(id)
+	if err != nil {
+		return model.Highlight{}, fmt.Errorf("failed to get highlight with ID %s: %w", id, err)
+	}
+	if highlight.BookmarkID != bookmarkID {
+		return model.Highlight{}, fmt.Errorf("highlight with ID %s %w", id, model.ErrNotFound)
+	}
+	return highlight, nil
+}
+
+// validateNote checks the length of a Markdown note of a bookmark or highlight
+func validateNote(note string) error {
+	if utf8.RuneCountInString(note) > model.MaxNoteLength {
+		return fmt.Errorf("notes can be at most %d characters: %w", model.MaxNoteLength, model.ErrInvalidInput)
+	}
+	return nil
+}
+
+// validateAnchor checks that an anchor is a range of the snapshot text, or unknown
+func validateAnchor(anchor model.HighlightAnchor) error {
+	if anchor.Start < 0 || anchor.End < anchor.Start || (anchor.End == anchor.Start && anchor.Start != 0) {
+		return fmt.Errorf("anchor must have 0 <= start < end: %w", model.ErrInvalidInput)
+	}
+	return nil
+}
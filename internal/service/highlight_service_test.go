@@ -0,0 +1,210 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/tsongpon/athena/internal/model"
+)
+
+type MockHighlightRepository struct {
+	highlights map[string]model.Highlight
+	nextID     int
+}
+
+func (m *MockHighlightRepository) CreateHighlight(highlight model.Highlight) (model.Highlight, error) {
+	if m.highlights == nil {
+		m.highlights = make(map[string]model.Highlight)
+	}
+	m.nextID++
+	highlight.ID = fmt.Sprintf("h%d", m.nextID)
+	m.highlights[highlight.ID] = highlight
+	return highlight, nil
+}
+
+func (m *MockHighlightRepository) GetHighlight(id string) (model.Highlight, error) {
+	highlight, exists := m.highlights[id]
+	if !exists {
+		return model.Highlight{}, model.ErrNotFound
+	}
+	return highlight, nil
+}
+
+func (m *MockHighlightRepository) ListHighlights(bookmarkID string) ([]model.Highlight, error) {
+	var highlights []model.Highlight
+	for _, highlight := range m.highlights {
+		if highlight.BookmarkID == bookmarkID {
+			highlights = append(highlights, highlight)
+		}
+	}
+	sort.Slice(highlights, func(i, j int) bool { return highlights[i].Anchor.Start < highlights[j].Anchor.Start })
+	return highlights, nil
+}
+
+func (m *MockHighlightRepository) UpdateHighlight(highlight model.Highlight) (model.Highlight, error) {
+	if _, exists := m.highlights[highlight.ID]; !exists {
+		return model.Highlight{}, model.ErrNotFound
+	}
+	m.highlights[highlight.ID] = highlight
+	return highlight, nil
+}
+
+func (m *MockHighlightRepository) DeleteHighlight(id string) error {
+	if _, exists := m.highlights[id]; !exists {
+		return model.ErrNotFound
+	}
+	delete(m.highlights, id)
+	return nil
+}
+
+func (m *MockHighlightRepository) DeleteBookmarkHighlights(bookmarkID string) error {
+	for id, highlight := range m.highlights {
+		if highlight.BookmarkID == bookmarkID {
+			delete(m.highlights, id)
+		}
+	}
+	return nil
+}
+
+func TestHighlightService_CreateAndListHighlights(t *testing.T) {
+	bookmarkRepo := &MockBookmarkRepository{
+		getBookmarkFunc: func(id string) (model.Bookmark, error) {
+			switch id {
+			case "b1":
+				return model.Bookmark{ID: id, UserID: "user-1"}, nil
+			case "b2":
+				return model.Bookmark{ID: id, UserID: "user-2"}, nil
+			}
+			return model.Bookmark{}, model.ErrNotFound
+		},
+	}
+	service := NewHighlightService(&MockHighlightRepository{}, bookmarkRepo, NewPolicy(&MockCollectionRepository{}))
+
+	later, err := service.CreateHighlight("user-1", "b1", model.Highlight{Quote: "  the second passage ", Anchor: model.HighlightAnchor{Start: 120, End: 138}})
+	if err != nil {
+		t.Fatalf("CreateHighlight() unexpected error = %v", err)
+	}
+	if later.BookmarkID != "b1" || later.UserID != "user-1" || later.Quote != "the second passage" {
+		t.Errorf("CreateHighlight() = %+v, want a trimmed quote on b1 by user-1", later)
+	}
+	if _, err := service.CreateHighlight("user-1", "b1", model.Highlight{Quote: "the first", Note: "*Worth* quoting", Anchor: model.HighlightAnchor{Start: 10, End: 19}}); err != nil {
+		t.Fatalf("CreateHighlight() unexpected error = %v", err)
+	}
+
+	highlights, err := service.ListHighlights("user-1", "b1")
+	if err != nil {
+		t.Fatalf("ListHighlights() unexpected error = %v", err)
+	}
+	if len(highlights) != 2 || highlights[0].Quote != "the first" || highlights[1].ID != later.ID {
+		t.Errorf("ListHighlights() = %+v, want both in the order of the page", highlights)
+	}
+	if _, err := service.ListHighlights("user-1", "b2"); !errors.Is(err, model.ErrForbidden) {
+		t.Errorf("ListHighlights() of another user's bookmark error = %v, want ErrForbidden", err)
+	}
+}
+
+func TestHighlightService_CreateHighlight_Invalid(t *testing.T) {
+	bookmarkRepo := &MockBookmarkRepository{
+		getBookmarkFunc: func(id string) (model.Bookmark, error) {
+			switch id {
+			case "b1":
+				return model.Bookmark{ID: id, UserID: "user-1"}, nil
+			case "b2":
+				return model.Bookmark{ID: id, UserID: "user-2"}, nil
+			}
+			return model.Bookmark{}, model.ErrNotFound
+		},
+	}
+	highlightRepo := &MockHighlightRepository{}
+	service := NewHighlightService(highlightRepo, bookmarkRepo, NewPolicy(&MockCollectionRepository{}))
+
+	tests := []struct {
+		name       string
+		bookmarkID string
+		highlight  model.Highlight
+		want       error
+	}{
+		{"no quote", "b1", model.Highlight{Quote: "  "}, model.ErrInvalidInput},
+		{"long quote", "b1", model.Highlight{Quote: strings.Repeat("a", model.MaxQuoteLength+1)}, model.ErrInvalidInput},
+		{"long note", "b1", model.Highlight{Quote: "a", Note: strings.Repeat("a", model.MaxNoteLength+1)}, model.ErrInvalidInput},
+		{"reversed anchor", "b1", model.Highlight{Quote: "a", Anchor: model.HighlightAnchor{Start: 10, End: 5}}, model.ErrInvalidInput},
+		{"empty anchor", "b1", model.Highlight{Quote: "a", Anchor: model.HighlightAnchor{Start: 10, End: 10}}, model.ErrInvalidInput},
+		{"negative anchor", "b1", model.Highlight{Quote: "a", Anchor: model.HighlightAnchor{Start: -1, End: 5}}, model.ErrInvalidInput},
+		{"other user", "b2", model.Highlight{Quote: "a"}, model.ErrForbidden},
+		{"missing bookmark", "b3", model.Highlight{Quote: "a"}, model.ErrNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := service.CreateHighlight("user-1", tt.bookmarkID, tt.highlight); !errors.Is(err, tt.want) {
+				t.Errorf("CreateHighlight() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+	if len(highlightRepo.highlights) != 0 {
+		t.Errorf("CreateHighlight() stored %d rejected highlights", len(highlightRepo.highlights))
+	}
+}
+
+func TestHighlightService_CreateHighlight_Limit(t *testing.T) {
+	bookmarkRepo := &MockBookmarkRepository{
+		getBookmarkFunc: func(id string) (model.Bookmark, error) {
+			return model.Bookmark{ID: id, UserID: "user-1"}, nil
+		},
+	}
+	highlightRepo := &MockHighlightRepository{}
+	service := NewHighlightService(highlightRepo, bookmarkRepo, NewPolicy(&MockCollectionRepository{}))
+	for range model.MaxBookmarkHighlights {
+		highlightRepo.CreateHighlight(model.Highlight{BookmarkID: "b1", UserID: "user-1", Quote: "a"})
+	}
+
+	if _, err := service.CreateHighlight("user-1", "b1", model.Highlight{Quote: "one too many"}); !errors.Is(err, model.ErrInvalidInput) {
+		t.Errorf("CreateHighlight() over the limit error = %v, want ErrInvalidInput", err)
+	}
+}
+
+func TestHighlightService_UpdateAndDeleteHighlight(t *testing.T) {
+	bookmarkRepo := &MockBookmarkRepository{
+		getBookmarkFunc: func(id string) (model.Bookmark, error) {
+			switch id {
+			case "b1":
+				return model.Bookmark{ID: id, UserID: "user-1"}, nil
+			case "b2":
+				return model.Bookmark{ID: id, UserID: "user-2"}, nil
+			}
+			return model.Bookmark{}, model.ErrNotFound
+		},
+	}
+	highlightRepo := &MockHighlightRepository{}
+	service := NewHighlightService(highlightRepo, bookmarkRepo, NewPolicy(&MockCollectionRepository{}))
+	created, _ := service.CreateHighlight("user-1", "b1", model.Highlight{Quote: "passage", Note: "first thought", Anchor: model.HighlightAnchor{Start: 1, End: 8}})
+	other, _ := highlightRepo.CreateHighlight(model.Highlight{BookmarkID: "b2", UserID: "user-2", Quote: "theirs"})
+
+	note := "second thought"
+	updated, err := service.UpdateHighlight("user-1", "b1", created.ID, model.HighlightUpdate{Note: &note})
+	if err != nil {
+		t.Fatalf("UpdateHighlight() unexpected error = %v", err)
+	}
+	if updated.Note != note || updated.Quote != "passage" || updated.Anchor != created.Anchor {
+		t.Errorf("UpdateHighlight() = %+v, want only the note changed", updated)
+	}
+	if _, err := service.UpdateHighlight("user-1", "b1", created.ID, model.HighlightUpdate{}); !errors.Is(err, model.ErrInvalidInput) {
+		t.Errorf("UpdateHighlight() without changes error = %v, want ErrInvalidInput", err)
+	}
+	// A highlight is only reachable through its own bookmark
+	if _, err := service.UpdateHighlight("user-1", "b1", other.ID, model.HighlightUpdate{Note: &note}); !errors.Is(err, model.ErrNotFound) {
+		t.Errorf("UpdateHighlight() of another bookmark's highlight error = %v, want ErrNotFound", err)
+	}
+	if err := service.DeleteHighlight("user-1", "b2", other.ID); !errors.Is(err, model.ErrForbidden) {
+		t.Errorf("DeleteHighlight() of another user's highlight error = %v, want ErrForbidden", err)
+	}
+
+	if err := service.DeleteHighlight("user-1", "b1", created.ID); err != nil {
+		t.Fatalf("DeleteHighlight() unexpected error = %v", err)
+	}
+	if _, exists := highlightRepo.highlights[created.ID]; exists {
+		t.Error("DeleteHighlight() kept the highlight")
+	}
+}
@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/tsongpon/athena/internal/logger"
+	"github.com/tsongpon/athena/internal/model"
+	"go.uber.org/zap"
+)
+
+const (
+	// linkCheckBatchSize is the most bookmarks checked in one run; the rest wait for the next
+	linkCheckBatchSize = 500
+	// linkCheckWorkers is the number of links checked concurrently. The checker's per-host
+	// rate limit keeps a batch dominated by one site from hammering it.
+	linkCheckWorkers = 8
+)
+
+// LinkHealthService periodically revalidates bookmarked URLs and records their health
+type LinkHealthService struct {
+	bookmarkRepository BookmarkRepository
+	checker            LinkChecker
+	recheckAfter       time.Duration
+}
+
+// NewLinkHealthService creates a new instance of LinkHealthService. Each bookmark is checked
+// again once recheckAfter has passed since its last check.
+func NewLinkHealthService(bookmarkRepo BookmarkRepository, checker LinkChecker, recheckAfter time.Duration) *LinkHealthService {
+	return &LinkHealthService{
+		bookmarkRepository: bookmarkRepo,
+		checker:            checker,
+		recheckAfter:       recheckAfter,
+	}
+}
+
+// Run checks due bookmarks immediately and then every interval until ctx is cancelled
+func (s *LinkHealthService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if checked, err := s.CheckDueLinks(ctx); err != nil {
+			logger.Error("Link health check failed", zap.Error(err))
+		} else if checked > 0 {
+			logger.Info("Link health check finished", zap.Int("checked", checked))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// CheckDueLinks checks one batch of bookmarks whose last check is older than recheckAfter and
+// returns how many were checked
+func (s *LinkHealthService) CheckDueLinks(ctx context.Context) (int, error) {
+	due, err := s.bookmarkRepository.ListBookmarksCheckedBefore(time.Now().Add(-s.recheckAfter), linkCheckBatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list bookmarks due for a link check: %w", err)
+	}
+
+	jobs := make(chan model.Bookmark)
+	var wg sync.WaitGroup
+	var mutex sync.Mutex
+	checked := 0
+	for range linkCheckWorkers {
+		wg.Go(func() {
+			for b := range jobs {
+				if err := s.checkBookmark(ctx, b); err != nil {
+					if ctx.Err() != nil {
+						continue
+					}
+					logger.Warn("failed to record link health", zap.String("bookmark_id", b.ID), zap.Error(err))
+					continue
+				}
+				mutex.Lock()
+				checked++
+				mutex.Unlock()
+			}
+		})
+	}
+	for _, b := range due {
+		if ctx.Err() != nil {
+			break
+		}
+		jobs <- b
+	}
+	close(jobs)
+	wg.Wait()
+
+	return checked, ctx.Err()
+}
+
+// checkBookmark checks one bookmark's URL and stores the result
+func (s *LinkHealthService) checkBookmark(ctx context.Context, b model.Bookmark) error {
+	health := s.checker.CheckLink(ctx, b.URL)
+	if ctx.Err() != nil {
+		// Shutting down; a cancelled request says nothing about the link
+		return ctx.Err()
+	}
+
+	// Update a fresh read of the bookmark so changes made during the request are kept
+	var previous model.LinkHealth
+	_, err := updateBookmark(s.bookmarkRepository, b.ID, func(current *model.Bookmark) error {
+		previous = current.Health
+		current.Health = health
+		return nil
+	})
+	if errors.Is(err, model.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if previous.Status != health.Status && health.Status == model.LinkHealthBroken {
+		logger.Info("Bookmark link is broken",
+			zap.String("bookmark_id", b.ID),
+			zap.String("url", b.URL),
+			zap.Int("status_code", health.StatusCode))
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/tsongpon/athena/internal/model"
+)
+
+// MockLinkChecker is a mock implementation of LinkChecker that answers from a map of URL to status
+type MockLinkChecker struct {
+	statuses map[string]string
+}
+
+func (m *MockLinkChecker) CheckLink(ctx context.Context, url string) model.LinkHealth {
+	return model.LinkHealth{Status: m.statuses[url], FinalURL: url, CheckedAt: time.Now()}
+}
+
+func TestLinkHealthService_CheckDueLinks(t *testing.T) {
+	bookmarks := map[string]model.Bookmark{
+		"b1": {ID: "b1", URL: "https://ok.example.com", Title: "Kept"},
+		"b2": {ID: "b2", URL: "https://gone.example.com"},
+		"b3": {ID: "b3", URL: "https://deleted.example.com"},
+	}
+	var before time.Time
+	var mutex sync.Mutex // The service checks links concurrently
+	bookmarkRepo := &MockBookmarkRepository{
+		listCheckedBeforeFunc: func(b time.Time, limit int) ([]model.Bookmark, error) {
+			before = b
+			return []model.Bookmark{bookmarks["b1"], bookmarks["b2"], bookmarks["b3"]}, nil
+		},
+		getBookmarkFunc: func(id string) (model.Bookmark, error) {
+			mutex.Lock()
+			defer mutex.Unlock()
+			if id == "b3" {
+				return model.Bookmark{}, model.ErrNotFound
+			}
+			return bookmarks[id], nil
+		},
+		updateBookmarkFunc: func(b model.Bookmark) (model.Bookmark, error) {
+			mutex.Lock()
+			defer mutex.Unlock()
+			bookmarks[b.ID] = b
+			return b, nil
+		},
+	}
+	checker := &MockLinkChecker{statuses: map[string]string{
+		"https://ok.example.com":   model.LinkHealthOK,
+		"https://gone.example.com": model.LinkHealthBroken,
+	}}
+	service := NewLinkHealthService(bookmarkRepo, checker, 24*time.Hour)
+
+	checked, err := service.CheckDueLinks(context.Background())
+	if err != nil {
+		t.Fatalf("CheckDueLinks() unexpected error = %v", err)
+	}
+	if checked != 3 {
+		t.Errorf("CheckDueLinks() checked %d bookmarks, want 3", checked)
+	}
+	if since := time.Since(before); since < 24*time.Hour || since > 25*time.Hour {
+		t.Errorf("CheckDueLinks() listed bookmarks checked before %v, want about a day ago", before)
+	}
+	if b := bookmarks["b1"]; b.Health.Status != model.LinkHealthOK || b.Title != "Kept" {
+		t.Errorf("bookmark b1 = %+v, want ok health and other fields kept", b)
+	}
+	if b := bookmarks["b2"]; b.Health.Status != model.LinkHealthBroken || b.Health.CheckedAt.IsZero() {
+		t.Errorf("bookmark b2 health = %+v, want broken", b.Health)
+	}
+	if _, ok := bookmarks["b3"]; ok && bookmarks["b3"].Health.Status != "" {
+		t.Errorf("deleted bookmark b3 should not be updated")
+	}
+}
@@ -0,0 +1,106 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/tsongpon/athena/internal/model"
+)
+
+// Policy decides what a user may do with bookmarks and collections. Services call
+// it before acting so that handlers never make authorization decisions themselves.
+//
+// Owners can do anything with their own bookmarks and collections. Collaborators
+// of a shared collection can view it and the bookmarks in it; editors can also add,
+// remove and reorder its bookmarks. Only the owner can change the collection itself,
+// manage its collaborators or delete it.
+type Policy struct {
+	collectionRepository CollectionRepository
+}
+
+// NewPolicy creates a new instance of Policy
+func NewPolicy(collectionRepo CollectionRepository) *Policy {
+	return &Policy{
+		collectionRepository: collectionRepo,
+	}
+}
+
+// CanViewBookmark allows the owner and collaborators of any collection holding the bookmark
+func (p *Policy) CanViewBookmark(userID string, bookmark model.Bookmark) error {
+	if bookmark.UserID == userID {
+		return nil
+	}
+
+	for _, collectionID := range bookmark.CollectionIDs {
+		collection, err := p.collectionRepository.GetCollection(collectionID)
+		if errors.Is(err, model.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to get collection with ID %s: %w", collectionID, err)
+		}
+		if collection.RoleOf(userID) != "" {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("user %s cannot view bookmark %s: %w", userID, bookmark.ID, model.ErrForbidden)
+}
+
+// CanModifyBookmark allows only the owner to change or delete a bookmark
+func (p *Policy) CanModifyBookmark(userID string, bookmark model.Bookmark) error {
+	if bookmark.UserID != userID {
+		return fmt.Errorf("user %s cannot modify bookmark %s: %w", userID, bookmark.ID, model.ErrForbidden)
+	}
+	return nil
+}
+
+// CanViewCollection allows the owner and every collaborator
+func (p *Policy) CanViewCollection(userID string, collection model.Collection) error {
+	if collection.RoleOf(userID) == "" {
+		return fmt.Errorf("user %s cannot view collection %s: %w", userID, collection.ID, model.ErrForbidden)
+	}
+	return nil
+}
+
+// CanEditCollectionBookmarks allows the owner and editors to add, remove and reorder bookmarks
+func (p *Policy) CanEditCollectionBookmarks(userID string, collection model.Collection) error {
+	switch collection.RoleOf(userID) {
+	case model.CollectionRoleOwner, model.CollectionRoleEditor:
+		return nil
+	default:
+		return fmt.Errorf("user %s cannot edit bookmarks of collection %s: %w", userID, collection.ID, model.ErrForbidden)
+	}
+}
+
+// CanManageCollection allows only the owner to change, share or delete a collection
+func (p *Policy) CanManageCollection(userID string, collection model.Collection) error {
+	if collection.UserID != userID {
+		return fmt.Errorf("user %s cannot manage collection %s: %w", userID, collection.ID, model.ErrForbidden)
+	}
+	return nil
+}
+
+// CanManageShareLink allows only the user who created a share link to revoke it
+func (p *Policy) CanManageShareLink(userID string, link model.ShareLink) error {
+	if link.UserID != userID {
+		return fmt.Errorf("user %s cannot manage share link: %w", userID, model.ErrForbidden)
+	}
+	return nil
+}
+
+// CanManageFeedToken allows only the owner of a feed token to revoke it
+func (p *Policy) CanManageFeedToken(userID string, feedToken model.FeedToken) error {
+	if feedToken.UserID != userID {
+		return fmt.Errorf("user %s cannot manage feed token: %w", userID, model.ErrForbidden)
+	}
+	return nil
+}
+
+// CanManageWebhook allows only the owner of a webhook to change it or read its deliveries
+func (p *Policy) CanManageWebhook(userID string, webhook model.Webhook) error {
+	if webhook.UserID != userID {
+		return fmt.Errorf("user %s cannot manage webhook: %w", userID, model.ErrForbidden)
+	}
+	return nil
+}
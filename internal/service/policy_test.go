@@ -0,0 +1,68 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/tsongpon/athena/internal/model"
+)
+
+func TestPolicy_CollectionRoles(t *testing.T) {
+	policy := NewPolicy(&MockCollectionRepository{})
+	collection := model.Collection{
+		ID:     "c1",
+		UserID: "owner",
+		Collaborators: []model.Collaborator{
+			{UserID: "editor", Role: model.CollectionRoleEditor},
+			{UserID: "viewer", Role: model.CollectionRoleViewer},
+		},
+	}
+
+	tests := []struct {
+		userID                      string
+		canView, canEdit, canManage bool
+	}{
+		{"owner", true, true, true},
+		{"editor", true, true, false},
+		{"viewer", true, false, false},
+		{"stranger", false, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.userID, func(t *testing.T) {
+			if got := policy.CanViewCollection(tt.userID, collection) == nil; got != tt.canView {
+				t.Errorf("CanViewCollection() allowed = %v, want %v", got, tt.canView)
+			}
+			if got := policy.CanEditCollectionBookmarks(tt.userID, collection) == nil; got != tt.canEdit {
+				t.Errorf("CanEditCollectionBookmarks() allowed = %v, want %v", got, tt.canEdit)
+			}
+			if got := policy.CanManageCollection(tt.userID, collection) == nil; got != tt.canManage {
+				t.Errorf("CanManageCollection() allowed = %v, want %v", got, tt.canManage)
+			}
+		})
+	}
+
+	if err := policy.CanManageCollection("viewer", collection); !errors.Is(err, model.ErrForbidden) {
+		t.Errorf("CanManageCollection() error = %v, want ErrForbidden", err)
+	}
+}
+
+func TestPolicy_CanViewBookmark_ThroughSharedCollection(t *testing.T) {
+	policy := NewPolicy(&MockCollectionRepository{
+		getCollectionFunc: collectionsByID(model.Collection{
+			ID:            "shared",
+			UserID:        "owner",
+			Collaborators: []model.Collaborator{{UserID: "viewer", Role: model.CollectionRoleViewer}},
+		}),
+	})
+	bookmark := model.Bookmark{ID: "b1", UserID: "owner", CollectionIDs: []string{"deleted", "shared"}}
+
+	if err := policy.CanViewBookmark("viewer", bookmark); err != nil {
+		t.Errorf("CanViewBookmark() by collaborator error = %v", err)
+	}
+	if err := policy.CanViewBookmark("stranger", bookmark); !errors.Is(err, model.ErrForbidden) {
+		t.Errorf("CanViewBookmark() by stranger error = %v, want ErrForbidden", err)
+	}
+	if err := policy.CanModifyBookmark("viewer", bookmark); !errors.Is(err, model.ErrForbidden) {
+		t.Errorf("CanModifyBookmark() by collaborator error = %v, want ErrForbidden", err)
+	}
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"time"
 
 	"github.com/tsongpon/athena/internal/model"
 )
@@ -11,6 +12,14 @@ type UserRepository interface {
 	GetUserByID(id string) (model.User, error)
 	GetUserByEmail(email string) (model.User, error)
 	GetUserByEmailAndPassword(email, hashedPassword string) (model.User, error)
+	UpdateUser(user model.User) (model.User, error)
+	// ModifyUser changes a user with change and stores the result atomically, so that writes
+	// in between are not lost. change is given a fresh read and may run more than once, so it
+	// must only depend on the user it is given. An error from change is returned as is, and
+	// nothing is stored.
+	ModifyUser(id string, change func(u *model.User) error) (model.User, error)
+	ListUsers(query model.UserQuery) ([]model.User, error)
+	CountUsers(query model.UserQuery) (int, error)
 }
 
 type BookmarkRepository interface {
@@ -18,12 +27,199 @@ type BookmarkRepository interface {
 	GetBookmark(id string) (model.Bookmark, error)
 	ListBookmarks(query model.BookmarkQuery) ([]model.Bookmark, error)
 	CountBookmarks(query model.BookmarkQuery) (int, error)
+	// UpdateBookmark stores a bookmark only if it is still at the Version it was read at, and
+	// returns it with the next one. A bookmark changed in between fails with ErrConflict.
 	UpdateBookmark(bookmark model.Bookmark) (model.Bookmark, error)
 	DeleteBookmark(id string) error
+	// GetBookmarks returns the bookmarks with the given IDs that exist, in one round trip
+	GetBookmarks(ids []string) ([]model.Bookmark, error)
+	// WriteBookmarks stores bookmarks or moves them to the trash in bulk, at most one write per
+	// bookmark, checking versions as UpdateBookmark does. Writes are not atomic as a whole: the
+	// error of each is returned at its index, nil when it succeeded.
+	WriteBookmarks(writes []model.BookmarkWrite) []error
+	// TrashBookmark moves a bookmark to the trash if it is still at the given version, failing
+	// with ErrConflict otherwise. Trashed bookmarks are kept apart: they are not returned by
+	// GetBookmark, GetBookmarks or any listing or count of live bookmarks.
+	TrashBookmark(id string, deletedAt time.Time, version int64) (model.Bookmark, error)
+	GetTrashedBookmark(id string) (model.Bookmark, error)
+	// ListTrashedBookmarks returns the trash of a user, most recently deleted first
+	ListTrashedBookmarks(userID string) ([]model.Bookmark, error)
+	// ListTrashedBefore returns up to limit bookmarks of any user trashed before the given
+	// time, oldest first
+	ListTrashedBefore(before time.Time, limit int) ([]model.Bookmark, error)
+	// RestoreBookmark moves a bookmark out of the trash. Both moves store the bookmark at its
+	// next version, so a client holding the version it was deleted at sees it changed.
+	RestoreBookmark(id string) (model.Bookmark, error)
+	// PurgeBookmark permanently deletes a bookmark in the trash
+	PurgeBookmark(id string) error
+	// ListBookmarksCheckedBefore returns up to limit bookmarks of any user whose link was last
+	// checked before the given time, never checked ones first
+	ListBookmarksCheckedBefore(before time.Time, limit int) ([]model.Bookmark, error)
 }
 
 type WebRepository interface {
 	GetTitle(ctx context.Context, url string) (string, error)
 	GetMainImage(ctx context.Context, url string) (string, error)
 	GetContentSummary(ctx context.Context, url string) (string, error)
+	GetPageMetadata(ctx context.Context, url string) (model.PageMetadata, error)
+}
+
+// LinkChecker requests a URL to find out whether it still works
+type LinkChecker interface {
+	CheckLink(ctx context.Context, url string) model.LinkHealth
+}
+
+// PageArchiver captures a self-contained snapshot of a web page
+type PageArchiver interface {
+	// ArchivePage returns the page at url as a single HTML document with scripts removed
+	// and stylesheets and images inlined
+	ArchivePage(ctx context.Context, url string) ([]byte, error)
+}
+
+// Thumbnailer turns a remote image into thumbnails
+type Thumbnailer interface {
+	// CreateThumbnails downloads the image at url and returns it scaled down to fit each of
+	// the square boxes in sizes, in the same order. Key is left for the caller to set.
+	CreateThumbnails(ctx context.Context, url string, sizes []int) ([]model.Blob, error)
+}
+
+// BlobStore keeps binary objects under slash separated keys
+type BlobStore interface {
+	PutBlob(ctx context.Context, blob model.Blob) error
+	// GetBlob returns ErrNotFound when nothing is stored under key
+	GetBlob(ctx context.Context, key string) (model.Blob, error)
+	// DeleteBlob removes the blob under key; deleting a missing blob is not an error
+	DeleteBlob(ctx context.Context, key string) error
+}
+
+type UsageRepository interface {
+	// IncrementUsage adds one to the counter for metric in period and returns the new value
+	IncrementUsage(userID, metric, period string) (int, error)
+	GetUsage(userID, metric, period string) (int, error)
+}
+
+// BillingProvider verifies and decodes webhook payloads from a billing provider
+type BillingProvider interface {
+	Name() string
+	// SignatureHeader is the HTTP header carrying the webhook signature
+	SignatureHeader() string
+	// ParseWebhook verifies the signature and decodes the payload. Events the
+	// provider does not map to a subscription event have an empty Type.
+	ParseWebhook(payload []byte, signature string) (model.BillingEvent, error)
+}
+
+type BillingEventRepository interface {
+	IsEventProcessed(provider, eventID string) (bool, error)
+	MarkEventProcessed(event model.BillingEvent) error
+}
+
+type CollectionRepository interface {
+	CreateCollection(collection model.Collection) (model.Collection, error)
+	GetCollection(id string) (model.Collection, error)
+	ListCollections(userID string) ([]model.Collection, error)
+	// ListSharedCollections returns collections where userID is a collaborator
+	ListSharedCollections(userID string) ([]model.Collection, error)
+	// UpdateCollection stores a collection only if it is still at the Version it was read at,
+	// and returns it with the next one. A collection changed in between fails with ErrConflict.
+	UpdateCollection(collection model.Collection) (model.Collection, error)
+	DeleteCollection(id string) error
+}
+
+type InvitationRepository interface {
+	CreateInvitation(invitation model.Invitation) (model.Invitation, error)
+	GetInvitation(id string) (model.Invitation, error)
+	UpdateInvitation(invitation model.Invitation) (model.Invitation, error)
+	// ListInvitationsByEmail returns invitations sent to email, newest first
+	ListInvitationsByEmail(email string) ([]model.Invitation, error)
+	// ListInvitationsByCollection returns invitations of a collection, newest first
+	ListInvitationsByCollection(collectionID string) ([]model.Invitation, error)
+}
+
+type ShareLinkRepository interface {
+	// CreateShareLink stores a link under its slug and returns ErrConflict if the slug is taken
+	CreateShareLink(link model.ShareLink) (model.ShareLink, error)
+	GetShareLink(slug string) (model.ShareLink, error)
+	// ListShareLinks returns the links created by userID, newest first
+	ListShareLinks(userID string) ([]model.ShareLink, error)
+	DeleteShareLink(slug string) error
+}
+
+type FeedTokenRepository interface {
+	CreateFeedToken(token model.FeedToken) (model.FeedToken, error)
+	GetFeedToken(token string) (model.FeedToken, error)
+	// ListFeedTokens returns the feed tokens of userID, newest first
+	ListFeedTokens(userID string) ([]model.FeedToken, error)
+	DeleteFeedToken(token string) error
+}
+
+type HighlightRepository interface {
+	CreateHighlight(highlight model.Highlight) (model.Highlight, error)
+	GetHighlight(id string) (model.Highlight, error)
+	// ListHighlights returns the highlights of a bookmark in the order of their anchors, then
+	// oldest first
+	ListHighlights(bookmarkID string) ([]model.Highlight, error)
+	UpdateHighlight(highlight model.Highlight) (model.Highlight, error)
+	DeleteHighlight(id string) error
+	// DeleteBookmarkHighlights deletes every highlight of a bookmark
+	DeleteBookmarkHighlights(bookmarkID string) error
+}
+
+// EventBroker carries events between the instances of the server, so that a stream open on
+// one instance gets the changes made through another
+type EventBroker interface {
+	Publish(event model.Event) error
+	// Subscribe delivers the events published by every instance, including this one, until
+	// ctx is done
+	Subscribe(ctx context.Context, deliver func(model.Event)) error
+}
+
+// WebhookRepository stores webhooks and their delivery queue
+type WebhookRepository interface {
+	CreateWebhook(webhook model.Webhook) (model.Webhook, error)
+	GetWebhook(id string) (model.Webhook, error)
+	// ListWebhooks returns the webhooks of a user, oldest first
+	ListWebhooks(userID string) ([]model.Webhook, error)
+	// UpdateWebhook applies an update to a webhook with model.Webhook.ApplyUpdate, atomically
+	// and without touching its other fields, and returns the updated webhook
+	UpdateWebhook(id string, update model.WebhookUpdate) (model.Webhook, error)
+	// RecordWebhookResult applies the outcome of a delivery attempt to a webhook with
+	// model.Webhook.RecordResult, atomically and without touching its other fields, and
+	// returns the updated webhook
+	RecordWebhookResult(id string, succeeded bool, at time.Time) (model.Webhook, error)
+	// DeleteWebhook deletes a webhook and its deliveries
+	DeleteWebhook(id string) error
+	CreateDelivery(delivery model.WebhookDelivery) (model.WebhookDelivery, error)
+	UpdateDelivery(delivery model.WebhookDelivery) (model.WebhookDelivery, error)
+	// ListDeliveries returns up to limit deliveries of a webhook, newest first
+	ListDeliveries(webhookID string, limit int) ([]model.WebhookDelivery, error)
+	// ListDueDeliveries returns up to limit pending deliveries due at now, earliest due first
+	ListDueDeliveries(now time.Time, limit int) ([]model.WebhookDelivery, error)
+	// ClaimDelivery postpones a pending delivery due at now to until, so that no other
+	// instance sends it meanwhile. It fails with ErrConflict when the delivery is no longer
+	// due, because another instance claimed it first.
+	ClaimDelivery(id string, now, until time.Time) (model.WebhookDelivery, error)
+}
+
+// BookmarkChangeRepository reads the per-user change log of bookmarks that sync clients catch
+// up from. It is implemented by the bookmark repository, which numbers and appends a change in
+// the same write as every bookmark it creates, updates, trashes, restores or deletes. Sequence
+// numbers of a user increase by one with every change, and a change is never visible before
+// those numbered lower.
+type BookmarkChangeRepository interface {
+	// ListChanges returns up to limit changes of a user numbered after seq, in order
+	ListChanges(userID string, after int64, limit int) ([]model.BookmarkChange, error)
+	// LatestChangeSeq returns the sequence number of the last change of a user, zero if none
+	LatestChangeSeq(userID string) (int64, error)
+}
+
+// WebhookSender sends signed requests to webhook URLs
+type WebhookSender interface {
+	// SendWebhook posts the request and returns the HTTP status of the response
+	SendWebhook(ctx context.Context, request model.WebhookRequest) (int, error)
+}
+
+type ActivityRepository interface {
+	CreateActivity(activity model.Activity) (model.Activity, error)
+	// ListActivities returns up to limit activities of a collection, newest first
+	ListActivities(collectionID string, limit int) ([]model.Activity, error)
 }
@@ -0,0 +1,197 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/tsongpon/athena/internal/logger"
+	"github.com/tsongpon/athena/internal/model"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// shareSlugBytes is the amount of randomness in a share slug (128 bits)
+const shareSlugBytes = 16
+
+// ShareService publishes bookmarks and collections under unguessable share links and
+// resolves those links for unauthenticated visitors
+type ShareService struct {
+	shareLinkRepository  ShareLinkRepository
+	bookmarkRepository   BookmarkRepository
+	collectionRepository CollectionRepository
+	policy               *Policy
+}
+
+// NewShareService creates a new instance of ShareService
+func NewShareService(shareLinkRepo ShareLinkRepository, bookmarkRepo BookmarkRepository, collectionRepo CollectionRepository, policy *Policy) *ShareService {
+	return &ShareService{
+		shareLinkRepository:  shareLinkRepo,
+		bookmarkRepository:   bookmarkRepo,
+		collectionRepository: collectionRepo,
+		policy:               policy,
+	}
+}
+
+// CreateShareLink makes a bookmark or collection owned by userID public. An empty password
+// leaves the link unprotected and a zero expiresAt makes it never expire.
+func (s *ShareService) CreateShareLink(userID, resourceType, resourceID, password string, expiresAt time.Time) (model.ShareLink, error) {
+	if resourceID == "" {
+		return model.ShareLink{}, fmt.Errorf("id is required: %w", model.ErrInvalidInput)
+	}
+	if !expiresAt.IsZero() && !expiresAt.After(time.Now()) {
+		return model.ShareLink{}, fmt.Errorf("expires_at must be in the future: %w", model.ErrInvalidInput)
+	}
+	// bcrypt has a 72-byte limit
+	if len(password) > 72 {
+		return model.ShareLink{}, fmt.Errorf("password must not exceed 72 bytes: %w", model.ErrInvalidInput)
+	}
+
+	switch resourceType {
+	case model.ShareResourceBookmark:
+		b, err := s.bookmarkRepository.GetBookmark(resourceID)
+		if err != nil {
+			return model.ShareLink{}, fmt.Errorf("failed to get bookmark with ID %s: %w", resourceID, err)
+		}
+		if err := s.policy.CanModifyBookmark(userID, b); err != nil {
+			return model.ShareLink{}, err
+		}
+	case model.ShareResourceCollection:
+		c, err := s.collectionRepository.GetCollection(resourceID)
+		if err != nil {
+			return model.ShareLink{}, fmt.Errorf("failed to get collection with ID %s: %w", resourceID, err)
+		}
+		if err := s.policy.CanManageCollection(userID, c); err != nil {
+			return model.ShareLink{}, err
+		}
+	default:
+		return model.ShareLink{}, fmt.Errorf("unknown share resource type %q: %w", resourceType, model.ErrInvalidInput)
+	}
+
+	link := model.ShareLink{
+		UserID:       userID,
+		ResourceType: resourceType,
+		ResourceID:   resourceID,
+		ExpiresAt:    expiresAt,
+	}
+	if password != "" {
+		hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+		if err != nil {
+			return model.ShareLink{}, fmt.Errorf("failed to hash share link password: %w", err)
+		}
+		link.PasswordHash = string(hash)
+	}
+
+	slug, err := newRandomToken(shareSlugBytes)
+	if err != nil {
+		return model.ShareLink{}, fmt.Errorf("failed to generate share slug: %w", err)
+	}
+	link.Slug = slug
+	created, err := s.shareLinkRepository.CreateShareLink(link)
+	if err != nil {
+		return model.ShareLink{}, fmt.Errorf("failed to create share link: %w", err)
+	}
+
+	logger.Info("Created share link",
+		zap.String("user_id", userID),
+		zap.String("resource_type", resourceType),
+		zap.String("resource_id", resourceID),
+		zap.Bool("password_protected", created.PasswordHash != ""))
+
+	return created, nil
+}
+
+// ListShareLinks lists the share links userID created, newest first
+func (s *ShareService) ListShareLinks(userID string) ([]model.ShareLink, error) {
+	links, err := s.shareLinkRepository.ListShareLinks(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list share links for user %s: %w", userID, err)
+	}
+	return links, nil
+}
+
+// RevokeShareLink deletes a share link so its slug stops resolving immediately
+func (s *ShareService) RevokeShareLink(userID, slug string) error {
+	link, err := s.shareLinkRepository.GetShareLink(slug)
+	if err != nil {
+		return fmt.Errorf("failed to get share link: %w", err)
+	}
+	if err := s.policy.CanManageShareLink(userID, link); err != nil {
+		return err
+	}
+	if err := s.shareLinkRepository.DeleteShareLink(slug); err != nil {
+		return fmt.Errorf("failed to delete share link: %w", err)
+	}
+
+	logger.Info("Revoked share link",
+		zap.String("user_id", userID),
+		zap.String("resource_type", link.ResourceType),
+		zap.String("resource_id", link.ResourceID))
+
+	return nil
+}
+
+// ResolveShareLink returns what a visitor of slug may see. It fails with ErrNotFound for
+// unknown or revoked slugs, ErrExpired past the expiry and ErrUnauthorized when the link
+// is password protected and password does not match.
+func (s *ShareService) ResolveShareLink(slug, password string) (model.PublicShare, error) {
+	link, err := s.shareLinkRepository.GetShareLink(slug)
+	if err != nil {
+		return model.PublicShare{}, fmt.Errorf("failed to get share link: %w", err)
+	}
+	if link.IsExpired(time.Now()) {
+		return model.PublicShare{}, fmt.Errorf("share link has expired: %w", model.ErrExpired)
+	}
+	if link.PasswordHash != "" {
+		if password == "" {
+			return model.PublicShare{}, fmt.Errorf("share link requires a password: %w", model.ErrUnauthorized)
+		}
+		if err := bcrypt.CompareHashAndPassword([]byte(link.PasswordHash), []byte(password)); err != nil {
+			return model.PublicShare{}, fmt.Errorf("incorrect share link password: %w", model.ErrUnauthorized)
+		}
+	}
+
+	share := model.PublicShare{ResourceType: link.ResourceType, ExpiresAt: link.ExpiresAt}
+	switch link.ResourceType {
+	case model.ShareResourceBookmark:
+		b, err := s.bookmarkRepository.GetBookmark(link.ResourceID)
+		if err != nil {
+			return model.PublicShare{}, fmt.Errorf("failed to get bookmark with ID %s: %w", link.ResourceID, err)
+		}
+		share.Name = b.Title
+		share.Bookmarks = []model.Bookmark{b}
+	case model.ShareResourceCollection:
+		c, err := s.collectionRepository.GetCollection(link.ResourceID)
+		if err != nil {
+			return model.PublicShare{}, fmt.Errorf("failed to get collection with ID %s: %w", link.ResourceID, err)
+		}
+		share.Name = c.Name
+		share.Description = c.Description
+		share.Bookmarks = []model.Bookmark{}
+		for _, id := range c.BookmarkIDs {
+			b, err := s.bookmarkRepository.GetBookmark(id)
+			if errors.Is(err, model.ErrNotFound) {
+				continue
+			}
+			if err != nil {
+				return model.PublicShare{}, fmt.Errorf("failed to get bookmark with ID %s: %w", id, err)
+			}
+			share.Bookmarks = append(share.Bookmarks, b)
+		}
+	default:
+		return model.PublicShare{}, fmt.Errorf("share link has unknown resource type %q", link.ResourceType)
+	}
+
+	return share, nil
+}
+
+// newRandomToken returns size random bytes encoded as a URL-safe string
+func newRandomToken(size int) (string, error) {
+	b := make([]byte, size)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,204 @@
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/tsongpon/athena/internal/model"
+)
+
+// MockShareLinkRepository is a mock implementation of ShareLinkRepository that keeps links in a map
+type MockShareLinkRepository struct {
+	links map[string]model.ShareLink
+}
+
+func (m *MockShareLinkRepository) CreateShareLink(link model.ShareLink) (model.ShareLink, error) {
+	if m.links == nil {
+		m.links = make(map[string]model.ShareLink)
+	}
+	if _, exists := m.links[link.Slug]; exists {
+		return model.ShareLink{}, model.ErrConflict
+	}
+	m.links[link.Slug] = link
+	return link, nil
+}
+
+func (m *MockShareLinkRepository) GetShareLink(slug string) (model.ShareLink, error) {
+	link, exists := m.links[slug]
+	if !exists {
+		return model.ShareLink{}, model.ErrNotFound
+	}
+	return link, nil
+}
+
+func (m *MockShareLinkRepository) ListShareLinks(userID string) ([]model.ShareLink, error) {
+	var links []model.ShareLink
+	for _, link := range m.links {
+		if link.UserID == userID {
+			links = append(links, link)
+		}
+	}
+	return links, nil
+}
+
+func (m *MockShareLinkRepository) DeleteShareLink(slug string) error {
+	if _, exists := m.links[slug]; !exists {
+		return model.ErrNotFound
+	}
+	delete(m.links, slug)
+	return nil
+}
+
+func TestShareService_ShareAndResolveBookmark(t *testing.T) {
+	bookmarkRepo := &MockBookmarkRepository{
+		getBookmarkFunc: func(id string) (model.Bookmark, error) {
+			if id != "b1" {
+				return model.Bookmark{}, model.ErrNotFound
+			}
+			return model.Bookmark{ID: "b1", UserID: "user-1", Title: "First", URL: "https://example.com/1"}, nil
+		},
+	}
+	collectionRepo := &MockCollectionRepository{}
+	service := NewShareService(&MockShareLinkRepository{}, bookmarkRepo, collectionRepo, NewPolicy(collectionRepo))
+
+	link, err := service.CreateShareLink("user-1", model.ShareResourceBookmark, "b1", "", time.Time{})
+	if err != nil {
+		t.Fatalf("CreateShareLink() unexpected error = %v", err)
+	}
+	if len(link.Slug) < 22 {
+		t.Errorf("CreateShareLink() slug = %q, want at least 128 bits of randomness", link.Slug)
+	}
+
+	share, err := service.ResolveShareLink(link.Slug, "")
+	if err != nil {
+		t.Fatalf("ResolveShareLink() unexpected error = %v", err)
+	}
+	if share.Name != "First" || len(share.Bookmarks) != 1 || share.Bookmarks[0].ID != "b1" {
+		t.Errorf("ResolveShareLink() = %+v, want bookmark b1", share)
+	}
+
+	if err := service.RevokeShareLink("user-2", link.Slug); !errors.Is(err, model.ErrForbidden) {
+		t.Errorf("RevokeShareLink() by another user error = %v, want ErrForbidden", err)
+	}
+	if err := service.RevokeShareLink("user-1", link.Slug); err != nil {
+		t.Fatalf("RevokeShareLink() unexpected error = %v", err)
+	}
+	if _, err := service.ResolveShareLink(link.Slug, ""); !errors.Is(err, model.ErrNotFound) {
+		t.Errorf("ResolveShareLink() after revoke error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestShareService_ResolveCollection_SkipsDeletedBookmarks(t *testing.T) {
+	bookmarks := map[string]model.Bookmark{
+		"b1": {ID: "b1", UserID: "user-1", Title: "First", URL: "https://example.com/1"},
+		"b2": {ID: "b2", UserID: "user-1", Title: "Second", URL: "https://example.com/2"},
+	}
+	bookmarkRepo := &MockBookmarkRepository{
+		getBookmarkFunc: func(id string) (model.Bookmark, error) {
+			b, exists := bookmarks[id]
+			if !exists {
+				return model.Bookmark{}, model.ErrNotFound
+			}
+			return b, nil
+		},
+	}
+	collectionRepo := &MockCollectionRepository{
+		getCollectionFunc: collectionsByID(model.Collection{ID: "c1", UserID: "user-1", Name: "Reading", BookmarkIDs: []string{"b2", "deleted", "b1"}}),
+	}
+	service := NewShareService(&MockShareLinkRepository{}, bookmarkRepo, collectionRepo, NewPolicy(collectionRepo))
+
+	link, err := service.CreateShareLink("user-1", model.ShareResourceCollection, "c1", "", time.Time{})
+	if err != nil {
+		t.Fatalf("CreateShareLink() unexpected error = %v", err)
+	}
+
+	share, err := service.ResolveShareLink(link.Slug, "")
+	if err != nil {
+		t.Fatalf("ResolveShareLink() unexpected error = %v", err)
+	}
+	if share.Name != "Reading" || len(share.Bookmarks) != 2 || share.Bookmarks[0].ID != "b2" || share.Bookmarks[1].ID != "b1" {
+		t.Errorf("ResolveShareLink() = %+v, want b2 then b1", share)
+	}
+}
+
+func TestShareService_CreateShareLink_Errors(t *testing.T) {
+	bookmarkRepo := &MockBookmarkRepository{
+		getBookmarkFunc: func(id string) (model.Bookmark, error) {
+			if id != "b1" {
+				return model.Bookmark{}, model.ErrNotFound
+			}
+			return model.Bookmark{ID: "b1", UserID: "user-1", Title: "First", URL: "https://example.com/1"}, nil
+		},
+	}
+	collectionRepo := &MockCollectionRepository{
+		getCollectionFunc: collectionsByID(model.Collection{
+			ID:            "c1",
+			UserID:        "user-1",
+			Collaborators: []model.Collaborator{{UserID: "user-2", Role: model.CollectionRoleEditor}},
+		}),
+	}
+	service := NewShareService(&MockShareLinkRepository{}, bookmarkRepo, collectionRepo, NewPolicy(collectionRepo))
+
+	tests := []struct {
+		name         string
+		userID       string
+		resourceType string
+		resourceID   string
+		expiresAt    time.Time
+		wantErr      error
+	}{
+		{"unknown resource type", "user-1", "profile", "b1", time.Time{}, model.ErrInvalidInput},
+		{"expiry in the past", "user-1", model.ShareResourceBookmark, "b1", time.Now().Add(-time.Minute), model.ErrInvalidInput},
+		{"bookmark of another user", "user-2", model.ShareResourceBookmark, "b1", time.Time{}, model.ErrForbidden},
+		{"collection editor", "user-2", model.ShareResourceCollection, "c1", time.Time{}, model.ErrForbidden},
+		{"missing bookmark", "user-1", model.ShareResourceBookmark, "missing", time.Time{}, model.ErrNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.CreateShareLink(tt.userID, tt.resourceType, tt.resourceID, "", tt.expiresAt)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("CreateShareLink() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestShareService_ResolveShareLink_PasswordAndExpiry(t *testing.T) {
+	bookmarkRepo := &MockBookmarkRepository{
+		getBookmarkFunc: func(id string) (model.Bookmark, error) {
+			if id != "b1" {
+				return model.Bookmark{}, model.ErrNotFound
+			}
+			return model.Bookmark{ID: "b1", UserID: "user-1", Title: "First", URL: "https://example.com/1"}, nil
+		},
+	}
+	collectionRepo := &MockCollectionRepository{}
+	shareLinkRepo := &MockShareLinkRepository{}
+	service := NewShareService(shareLinkRepo, bookmarkRepo, collectionRepo, NewPolicy(collectionRepo))
+
+	link, err := service.CreateShareLink("user-1", model.ShareResourceBookmark, "b1", "s3cret", time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatalf("CreateShareLink() unexpected error = %v", err)
+	}
+	if link.PasswordHash == "" || link.PasswordHash == "s3cret" {
+		t.Errorf("CreateShareLink() PasswordHash = %q, want a bcrypt hash", link.PasswordHash)
+	}
+
+	if _, err := service.ResolveShareLink(link.Slug, ""); !errors.Is(err, model.ErrUnauthorized) {
+		t.Errorf("ResolveShareLink() without password error = %v, want ErrUnauthorized", err)
+	}
+	if _, err := service.ResolveShareLink(link.Slug, "wrong"); !errors.Is(err, model.ErrUnauthorized) {
+		t.Errorf("ResolveShareLink() with wrong password error = %v, want ErrUnauthorized", err)
+	}
+	if _, err := service.ResolveShareLink(link.Slug, "s3cret"); err != nil {
+		t.Errorf("ResolveShareLink() with password error = %v", err)
+	}
+
+	expired := shareLinkRepo.links[link.Slug]
+	expired.ExpiresAt = time.Now().Add(-time.Second)
+	shareLinkRepo.links[link.Slug] = expired
+	if _, err := service.ResolveShareLink(link.Slug, "s3cret"); !errors.Is(err, model.ErrExpired) {
+		t.Errorf("ResolveShareLink() after expiry error = %v, want ErrExpired", err)
+	}
+}
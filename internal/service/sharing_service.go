@@ -0,0 +1,311 @@
+package service
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/tsongpon/athena/internal/logger"
+	"github.com/tsongpon/athena/internal/model"
+	"go.uber.org/zap"
+)
+
+// SharingService manages collaborators of shared collections and the email
+// invitations that add them. Invitations are addressed to an email rather than a
+// user, so people can be invited before they register; the invitee sees pending
+// invitations once they log in with that email.
+type SharingService struct {
+	collectionRepository CollectionRepository
+	invitationRepository InvitationRepository
+	activityRepository   ActivityRepository
+	userRepository       UserRepository
+	policy               *Policy
+}
+
+// NewSharingService creates a new instance of SharingService
+func NewSharingService(collectionRepo CollectionRepository, invitationRepo InvitationRepository, activityRepo ActivityRepository, userRepo UserRepository, policy *Policy) *SharingService {
+	return &SharingService{
+		collectionRepository: collectionRepo,
+		invitationRepository: invitationRepo,
+		activityRepository:   activityRepo,
+		userRepository:       userRepo,
+		policy:               policy,
+	}
+}
+
+// InviteCollaborator invites email to a collection owned by userID with the given role
+func (s *SharingService) InviteCollaborator(userID, collectionID, email, role string) (model.Invitation, error) {
+	email = strings.ToLower(strings.TrimSpace(email))
+	if email == "" || !strings.Contains(email, "@") {
+		return model.Invitation{}, fmt.Errorf("a valid email is required: %w", model.ErrInvalidInput)
+	}
+	if !model.IsValidCollaboratorRole(role) {
+		return model.Invitation{}, fmt.Errorf("unknown collaborator role %q: %w", role, model.ErrInvalidInput)
+	}
+
+	collection, err := s.getManagedCollection(userID, collectionID)
+	if err != nil {
+		return model.Invitation{}, err
+	}
+
+	owner, err := s.userRepository.GetUserByID(userID)
+	if err != nil {
+		return model.Invitation{}, fmt.Errorf("failed to get user with ID %s: %w", userID, err)
+	}
+	if strings.EqualFold(owner.Email, email) {
+		return model.Invitation{}, fmt.Errorf("you cannot invite yourself: %w", model.ErrInvalidInput)
+	}
+
+	existing, err := s.invitationRepository.ListInvitationsByCollection(collection.ID)
+	if err != nil {
+		return model.Invitation{}, fmt.Errorf("failed to list invitations of collection %s: %w", collection.ID, err)
+	}
+	for _, invitation := range existing {
+		if invitation.Email == email && invitation.Status == model.InvitationPending {
+			return model.Invitation{}, fmt.Errorf("%s already has a pending invitation: %w", email, model.ErrConflict)
+		}
+	}
+
+	invitation, err := s.invitationRepository.CreateInvitation(model.Invitation{
+		CollectionID: collection.ID,
+		InviterID:    userID,
+		Email:        email,
+		Role:         role,
+		Status:       model.InvitationPending,
+	})
+	if err != nil {
+		return model.Invitation{}, fmt.Errorf("failed to create invitation: %w", err)
+	}
+
+	logger.Info("Invited collaborator to collection",
+		zap.String("collection_id", collection.ID),
+		zap.String("inviter_id", userID),
+		zap.String("invitation_id", invitation.ID),
+		zap.String("role", role))
+
+	return invitation, nil
+}
+
+// ListCollectionInvitations lists every invitation of a collection owned by userID
+func (s *SharingService) ListCollectionInvitations(userID, collectionID string) ([]model.Invitation, error) {
+	collection, err := s.getManagedCollection(userID, collectionID)
+	if err != nil {
+		return nil, err
+	}
+
+	invitations, err := s.invitationRepository.ListInvitationsByCollection(collection.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list invitations of collection %s: %w", collection.ID, err)
+	}
+	return invitations, nil
+}
+
+// RevokeInvitation withdraws a pending invitation of a collection owned by userID
+func (s *SharingService) RevokeInvitation(userID, collectionID, invitationID string) error {
+	collection, err := s.getManagedCollection(userID, collectionID)
+	if err != nil {
+		return err
+	}
+
+	invitation, err := s.getPendingInvitation(invitationID)
+	if err != nil {
+		return err
+	}
+	if invitation.CollectionID != collection.ID {
+		return fmt.Errorf("invitation %s does not belong to collection %s: %w", invitationID, collection.ID, model.ErrNotFound)
+	}
+
+	invitation.Status = model.InvitationRevoked
+	invitation.RespondedAt = time.Now()
+	if _, err := s.invitationRepository.UpdateInvitation(invitation); err != nil {
+		return fmt.Errorf("failed to update invitation with ID %s: %w", invitationID, err)
+	}
+	return nil
+}
+
+// ListPendingInvitations lists invitations waiting for userID to accept or decline
+func (s *SharingService) ListPendingInvitations(userID string) ([]model.Invitation, error) {
+	user, err := s.userRepository.GetUserByID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user with ID %s: %w", userID, err)
+	}
+
+	invitations, err := s.invitationRepository.ListInvitationsByEmail(strings.ToLower(user.Email))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list invitations for user %s: %w", userID, err)
+	}
+
+	return slices.DeleteFunc(invitations, func(invitation model.Invitation) bool {
+		return invitation.Status != model.InvitationPending
+	}), nil
+}
+
+// AcceptInvitation adds userID to the invited collection with the invited role
+func (s *SharingService) AcceptInvitation(userID, invitationID string) (model.Collection, error) {
+	invitation, err := s.getInvitationFor(userID, invitationID)
+	if err != nil {
+		return model.Collection{}, err
+	}
+
+	updated, err := updateCollection(s.collectionRepository, invitation.CollectionID, func(collection *model.Collection) error {
+		if collection.UserID == userID {
+			return fmt.Errorf("you already own this collection: %w", model.ErrInvalidInput)
+		}
+		collection.Collaborators = slices.DeleteFunc(slices.Clone(collection.Collaborators), func(c model.Collaborator) bool {
+			return c.UserID == userID
+		})
+		collection.Collaborators = append(collection.Collaborators, model.Collaborator{
+			UserID:  userID,
+			Role:    invitation.Role,
+			AddedAt: time.Now(),
+		})
+		return nil
+	})
+	if err != nil {
+		return model.Collection{}, err
+	}
+
+	invitation.Status = model.InvitationAccepted
+	invitation.RespondedAt = time.Now()
+	if _, err := s.invitationRepository.UpdateInvitation(invitation); err != nil {
+		return model.Collection{}, fmt.Errorf("failed to update invitation with ID %s: %w", invitationID, err)
+	}
+
+	recordActivity(s.activityRepository, model.Activity{
+		CollectionID: updated.ID,
+		ActorID:      userID,
+		Action:       model.ActivityCollaboratorJoined,
+		TargetUserID: userID,
+	})
+
+	logger.Info("Collaborator joined collection",
+		zap.String("collection_id", updated.ID),
+		zap.String("user_id", userID),
+		zap.String("role", invitation.Role))
+
+	return updated, nil
+}
+
+// DeclineInvitation declines an invitation addressed to userID
+func (s *SharingService) DeclineInvitation(userID, invitationID string) (model.Invitation, error) {
+	invitation, err := s.getInvitationFor(userID, invitationID)
+	if err != nil {
+		return model.Invitation{}, err
+	}
+
+	invitation.Status = model.InvitationDeclined
+	invitation.RespondedAt = time.Now()
+	updated, err := s.invitationRepository.UpdateInvitation(invitation)
+	if err != nil {
+		return model.Invitation{}, fmt.Errorf("failed to update invitation with ID %s: %w", invitationID, err)
+	}
+	return updated, nil
+}
+
+// UpdateCollaborator changes the role of a collaborator on a collection owned by userID
+func (s *SharingService) UpdateCollaborator(userID, collectionID, collaboratorID, role string) (model.Collection, error) {
+	if !model.IsValidCollaboratorRole(role) {
+		return model.Collection{}, fmt.Errorf("unknown collaborator role %q: %w", role, model.ErrInvalidInput)
+	}
+
+	updated, err := updateCollection(s.collectionRepository, collectionID, func(collection *model.Collection) error {
+		if err := s.policy.CanManageCollection(userID, *collection); err != nil {
+			return err
+		}
+		i := slices.IndexFunc(collection.Collaborators, func(c model.Collaborator) bool { return c.UserID == collaboratorID })
+		if i < 0 {
+			return fmt.Errorf("user %s is not a collaborator of collection %s: %w", collaboratorID, collection.ID, model.ErrNotFound)
+		}
+		collection.Collaborators = slices.Clone(collection.Collaborators)
+		collection.Collaborators[i].Role = role
+		return nil
+	})
+	if err != nil {
+		return model.Collection{}, err
+	}
+
+	recordActivity(s.activityRepository, model.Activity{
+		CollectionID: updated.ID,
+		ActorID:      userID,
+		Action:       model.ActivityCollaboratorUpdated,
+		TargetUserID: collaboratorID,
+	})
+
+	return updated, nil
+}
+
+// RemoveCollaborator removes a collaborator from a collection. The owner can remove
+// anyone; collaborators can only remove themselves to leave a collection. Bookmarks
+// the collaborator added stay in the collection.
+func (s *SharingService) RemoveCollaborator(userID, collectionID, collaboratorID string) error {
+	_, err := updateCollection(s.collectionRepository, collectionID, func(collection *model.Collection) error {
+		if userID != collaboratorID {
+			if err := s.policy.CanManageCollection(userID, *collection); err != nil {
+				return err
+			}
+		}
+		if !slices.ContainsFunc(collection.Collaborators, func(c model.Collaborator) bool { return c.UserID == collaboratorID }) {
+			return fmt.Errorf("user %s is not a collaborator of collection %s: %w", collaboratorID, collectionID, model.ErrNotFound)
+		}
+		collection.Collaborators = slices.DeleteFunc(slices.Clone(collection.Collaborators), func(c model.Collaborator) bool {
+			return c.UserID == collaboratorID
+		})
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	recordActivity(s.activityRepository, model.Activity{
+		CollectionID: collectionID,
+		ActorID:      userID,
+		Action:       model.ActivityCollaboratorRemoved,
+		TargetUserID: collaboratorID,
+	})
+
+	return nil
+}
+
+// getManagedCollection loads a collection that userID owns
+func (s *SharingService) getManagedCollection(userID, collectionID string) (model.Collection, error) {
+	if collectionID == "" {
+		return model.Collection{}, fmt.Errorf("id is required: %w", model.ErrInvalidInput)
+	}
+	collection, err := s.collectionRepository.GetCollection(collectionID)
+	if err != nil {
+		return model.Collection{}, fmt.Errorf("failed to get collection with ID %s: %w", collectionID, err)
+	}
+	if err := s.policy.CanManageCollection(userID, collection); err != nil {
+		return model.Collection{}, err
+	}
+	return collection, nil
+}
+
+func (s *SharingService) getPendingInvitation(invitationID string) (model.Invitation, error) {
+	invitation, err := s.invitationRepository.GetInvitation(invitationID)
+	if err != nil {
+		return model.Invitation{}, fmt.Errorf("failed to get invitation with ID %s: %w", invitationID, err)
+	}
+	if invitation.Status != model.InvitationPending {
+		return model.Invitation{}, fmt.Errorf("invitation %s is already %s: %w", invitationID, invitation.Status, model.ErrConflict)
+	}
+	return invitation, nil
+}
+
+// getInvitationFor loads a pending invitation addressed to userID's email
+func (s *SharingService) getInvitationFor(userID, invitationID string) (model.Invitation, error) {
+	invitation, err := s.getPendingInvitation(invitationID)
+	if err != nil {
+		return model.Invitation{}, err
+	}
+
+	user, err := s.userRepository.GetUserByID(userID)
+	if err != nil {
+		return model.Invitation{}, fmt.Errorf("failed to get user with ID %s: %w", userID, err)
+	}
+	if !strings.EqualFold(user.Email, invitation.Email) {
+		return model.Invitation{}, fmt.Errorf("invitation %s is addressed to another user: %w", invitationID, model.ErrForbidden)
+	}
+	return invitation, nil
+}
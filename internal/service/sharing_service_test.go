@@ -0,0 +1,308 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/tsongpon/athena/internal/model"
+)
+
+// MockInvitationRepository is a mock implementation of InvitationRepository that keeps invitations in a slice
+type MockInvitationRepository struct {
+	invitations []model.Invitation
+}
+
+func (m *MockInvitationRepository) CreateInvitation(invitation model.Invitation) (model.Invitation, error) {
+	invitation.ID = fmt.Sprintf("invitation-%d", len(m.invitations)+1)
+	m.invitations = append(m.invitations, invitation)
+	return invitation, nil
+}
+
+func (m *MockInvitationRepository) GetInvitation(id string) (model.Invitation, error) {
+	for _, invitation := range m.invitations {
+		if invitation.ID == id {
+			return invitation, nil
+		}
+	}
+	return model.Invitation{}, model.ErrNotFound
+}
+
+func (m *MockInvitationRepository) UpdateInvitation(invitation model.Invitation) (model.Invitation, error) {
+	for i := range m.invitations {
+		if m.invitations[i].ID == invitation.ID {
+			m.invitations[i] = invitation
+			return invitation, nil
+		}
+	}
+	return model.Invitation{}, model.ErrNotFound
+}
+
+func (m *MockInvitationRepository) ListInvitationsByEmail(email string) ([]model.Invitation, error) {
+	var invitations []model.Invitation
+	for _, invitation := range m.invitations {
+		if invitation.Email == email {
+			invitations = append(invitations, invitation)
+		}
+	}
+	return invitations, nil
+}
+
+func (m *MockInvitationRepository) ListInvitationsByCollection(collectionID string) ([]model.Invitation, error) {
+	var invitations []model.Invitation
+	for _, invitation := range m.invitations {
+		if invitation.CollectionID == collectionID {
+			invitations = append(invitations, invitation)
+		}
+	}
+	return invitations, nil
+}
+
+func TestSharingService_InviteAndAccept(t *testing.T) {
+	// Collection c1 is owned by owner@example.com (user-1), users user-2 and user-3 are registered
+	collection := model.Collection{ID: "c1", UserID: "user-1", Name: "Team"}
+	collectionRepo := &MockCollectionRepository{
+		getCollectionFunc: func(id string) (model.Collection, error) {
+			if id != collection.ID {
+				return model.Collection{}, model.ErrNotFound
+			}
+			return collection, nil
+		},
+		updateCollectionFunc: func(c model.Collection) (model.Collection, error) {
+			collection = c
+			return c, nil
+		},
+	}
+	userRepo := &MockUserRepository{
+		getUserByIDFunc: func(id string) (model.User, error) {
+			emails := map[string]string{
+				"user-1": "owner@example.com",
+				"user-2": "Editor@Example.com",
+				"user-3": "viewer@example.com",
+			}
+			return model.User{ID: id, Email: emails[id]}, nil
+		},
+	}
+	activityRepo := &MockActivityRepository{}
+	service := NewSharingService(collectionRepo, &MockInvitationRepository{}, activityRepo, userRepo, NewPolicy(collectionRepo))
+
+	invitation, err := service.InviteCollaborator("user-1", "c1", " editor@example.COM ", model.CollectionRoleEditor)
+	if err != nil {
+		t.Fatalf("InviteCollaborator() unexpected error = %v", err)
+	}
+	if invitation.Email != "editor@example.com" || invitation.Status != model.InvitationPending {
+		t.Errorf("InviteCollaborator() = %+v, want pending invitation for editor@example.com", invitation)
+	}
+
+	pending, err := service.ListPendingInvitations("user-2")
+	if err != nil || len(pending) != 1 {
+		t.Fatalf("ListPendingInvitations() = %v, %v, want one invitation", pending, err)
+	}
+
+	updated, err := service.AcceptInvitation("user-2", invitation.ID)
+	if err != nil {
+		t.Fatalf("AcceptInvitation() unexpected error = %v", err)
+	}
+	if updated.RoleOf("user-2") != model.CollectionRoleEditor || collection.RoleOf("user-2") != model.CollectionRoleEditor {
+		t.Errorf("AcceptInvitation() role = %q, want editor", updated.RoleOf("user-2"))
+	}
+	if len(activityRepo.activities) != 1 || activityRepo.activities[0].Action != model.ActivityCollaboratorJoined {
+		t.Errorf("activities = %+v, want one collaborator_joined", activityRepo.activities)
+	}
+
+	if _, err := service.AcceptInvitation("user-2", invitation.ID); !errors.Is(err, model.ErrConflict) {
+		t.Errorf("AcceptInvitation() twice error = %v, want ErrConflict", err)
+	}
+}
+
+func TestSharingService_InviteCollaborator_Errors(t *testing.T) {
+	// Collection c1 is owned by owner@example.com (user-1), users user-2 and user-3 are registered
+	collection := model.Collection{ID: "c1", UserID: "user-1", Name: "Team"}
+	collectionRepo := &MockCollectionRepository{
+		getCollectionFunc: func(id string) (model.Collection, error) {
+			if id != collection.ID {
+				return model.Collection{}, model.ErrNotFound
+			}
+			return collection, nil
+		},
+		updateCollectionFunc: func(c model.Collection) (model.Collection, error) {
+			collection = c
+			return c, nil
+		},
+	}
+	userRepo := &MockUserRepository{
+		getUserByIDFunc: func(id string) (model.User, error) {
+			emails := map[string]string{
+				"user-1": "owner@example.com",
+				"user-2": "Editor@Example.com",
+				"user-3": "viewer@example.com",
+			}
+			return model.User{ID: id, Email: emails[id]}, nil
+		},
+	}
+	service := NewSharingService(collectionRepo, &MockInvitationRepository{}, &MockActivityRepository{}, userRepo, NewPolicy(collectionRepo))
+
+	tests := []struct {
+		name    string
+		userID  string
+		email   string
+		role    string
+		wantErr error
+	}{
+		{"invalid email", "user-1", "nobody", model.CollectionRoleViewer, model.ErrInvalidInput},
+		{"owner role", "user-1", "viewer@example.com", model.CollectionRoleOwner, model.ErrInvalidInput},
+		{"self invite", "user-1", "owner@example.com", model.CollectionRoleViewer, model.ErrInvalidInput},
+		{"not the owner", "user-2", "viewer@example.com", model.CollectionRoleViewer, model.ErrForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := service.InviteCollaborator(tt.userID, "c1", tt.email, tt.role); !errors.Is(err, tt.wantErr) {
+				t.Errorf("InviteCollaborator() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	if _, err := service.InviteCollaborator("user-1", "c1", "viewer@example.com", model.CollectionRoleViewer); err != nil {
+		t.Fatalf("InviteCollaborator() unexpected error = %v", err)
+	}
+	if _, err := service.InviteCollaborator("user-1", "c1", "viewer@example.com", model.CollectionRoleEditor); !errors.Is(err, model.ErrConflict) {
+		t.Errorf("InviteCollaborator() duplicate error = %v, want ErrConflict", err)
+	}
+}
+
+func TestSharingService_AcceptInvitation_OtherUser(t *testing.T) {
+	// Collection c1 is owned by owner@example.com (user-1), users user-2 and user-3 are registered
+	collection := model.Collection{ID: "c1", UserID: "user-1", Name: "Team"}
+	collectionRepo := &MockCollectionRepository{
+		getCollectionFunc: func(id string) (model.Collection, error) {
+			if id != collection.ID {
+				return model.Collection{}, model.ErrNotFound
+			}
+			return collection, nil
+		},
+		updateCollectionFunc: func(c model.Collection) (model.Collection, error) {
+			collection = c
+			return c, nil
+		},
+	}
+	userRepo := &MockUserRepository{
+		getUserByIDFunc: func(id string) (model.User, error) {
+			emails := map[string]string{
+				"user-1": "owner@example.com",
+				"user-2": "Editor@Example.com",
+				"user-3": "viewer@example.com",
+			}
+			return model.User{ID: id, Email: emails[id]}, nil
+		},
+	}
+	service := NewSharingService(collectionRepo, &MockInvitationRepository{}, &MockActivityRepository{}, userRepo, NewPolicy(collectionRepo))
+
+	invitation, err := service.InviteCollaborator("user-1", "c1", "viewer@example.com", model.CollectionRoleViewer)
+	if err != nil {
+		t.Fatalf("InviteCollaborator() unexpected error = %v", err)
+	}
+	if _, err := service.AcceptInvitation("user-2", invitation.ID); !errors.Is(err, model.ErrForbidden) {
+		t.Errorf("AcceptInvitation() by another user error = %v, want ErrForbidden", err)
+	}
+}
+
+func TestSharingService_DeclineAndRevoke(t *testing.T) {
+	// Collection c1 is owned by owner@example.com (user-1), users user-2 and user-3 are registered
+	collection := model.Collection{ID: "c1", UserID: "user-1", Name: "Team"}
+	collectionRepo := &MockCollectionRepository{
+		getCollectionFunc: func(id string) (model.Collection, error) {
+			if id != collection.ID {
+				return model.Collection{}, model.ErrNotFound
+			}
+			return collection, nil
+		},
+		updateCollectionFunc: func(c model.Collection) (model.Collection, error) {
+			collection = c
+			return c, nil
+		},
+	}
+	userRepo := &MockUserRepository{
+		getUserByIDFunc: func(id string) (model.User, error) {
+			emails := map[string]string{
+				"user-1": "owner@example.com",
+				"user-2": "Editor@Example.com",
+				"user-3": "viewer@example.com",
+			}
+			return model.User{ID: id, Email: emails[id]}, nil
+		},
+	}
+	service := NewSharingService(collectionRepo, &MockInvitationRepository{}, &MockActivityRepository{}, userRepo, NewPolicy(collectionRepo))
+
+	declined, _ := service.InviteCollaborator("user-1", "c1", "viewer@example.com", model.CollectionRoleViewer)
+	invitation, err := service.DeclineInvitation("user-3", declined.ID)
+	if err != nil || invitation.Status != model.InvitationDeclined {
+		t.Errorf("DeclineInvitation() = %+v, %v, want declined", invitation, err)
+	}
+
+	revoked, _ := service.InviteCollaborator("user-1", "c1", "editor@example.com", model.CollectionRoleEditor)
+	if err := service.RevokeInvitation("user-1", "c1", revoked.ID); err != nil {
+		t.Fatalf("RevokeInvitation() unexpected error = %v", err)
+	}
+	if _, err := service.AcceptInvitation("user-2", revoked.ID); !errors.Is(err, model.ErrConflict) {
+		t.Errorf("AcceptInvitation() of revoked invitation error = %v, want ErrConflict", err)
+	}
+	if len(collection.Collaborators) != 0 {
+		t.Errorf("Collaborators = %+v, want none", collection.Collaborators)
+	}
+}
+
+func TestSharingService_UpdateAndRemoveCollaborator(t *testing.T) {
+	// Collection c1 is owned by owner@example.com (user-1), users user-2 and user-3 are registered
+	collection := model.Collection{ID: "c1", UserID: "user-1", Name: "Team"}
+	collectionRepo := &MockCollectionRepository{
+		getCollectionFunc: func(id string) (model.Collection, error) {
+			if id != collection.ID {
+				return model.Collection{}, model.ErrNotFound
+			}
+			return collection, nil
+		},
+		updateCollectionFunc: func(c model.Collection) (model.Collection, error) {
+			collection = c
+			return c, nil
+		},
+	}
+	userRepo := &MockUserRepository{
+		getUserByIDFunc: func(id string) (model.User, error) {
+			emails := map[string]string{
+				"user-1": "owner@example.com",
+				"user-2": "Editor@Example.com",
+				"user-3": "viewer@example.com",
+			}
+			return model.User{ID: id, Email: emails[id]}, nil
+		},
+	}
+	service := NewSharingService(collectionRepo, &MockInvitationRepository{}, &MockActivityRepository{}, userRepo, NewPolicy(collectionRepo))
+	collection.Collaborators = []model.Collaborator{
+		{UserID: "user-2", Role: model.CollectionRoleEditor},
+		{UserID: "user-3", Role: model.CollectionRoleViewer},
+	}
+
+	if _, err := service.UpdateCollaborator("user-2", "c1", "user-3", model.CollectionRoleEditor); !errors.Is(err, model.ErrForbidden) {
+		t.Errorf("UpdateCollaborator() by editor error = %v, want ErrForbidden", err)
+	}
+	updated, err := service.UpdateCollaborator("user-1", "c1", "user-3", model.CollectionRoleEditor)
+	if err != nil || updated.RoleOf("user-3") != model.CollectionRoleEditor {
+		t.Errorf("UpdateCollaborator() role = %q, %v, want editor", updated.RoleOf("user-3"), err)
+	}
+
+	if err := service.RemoveCollaborator("user-2", "c1", "user-3"); !errors.Is(err, model.ErrForbidden) {
+		t.Errorf("RemoveCollaborator() of another collaborator error = %v, want ErrForbidden", err)
+	}
+	if err := service.RemoveCollaborator("user-2", "c1", "user-2"); err != nil {
+		t.Errorf("RemoveCollaborator() leaving error = %v", err)
+	}
+	if err := service.RemoveCollaborator("user-1", "c1", "user-3"); err != nil {
+		t.Errorf("RemoveCollaborator() by owner error = %v", err)
+	}
+	if len(collection.Collaborators) != 0 {
+		t.Errorf("Collaborators = %+v, want none", collection.Collaborators)
+	}
+	if err := service.RemoveCollaborator("user-1", "c1", "user-3"); !errors.Is(err, model.ErrNotFound) {
+		t.Errorf("RemoveCollaborator() of non-collaborator error = %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/tsongpon/athena/internal/logger"
+	"github.com/tsongpon/athena/internal/model"
+	"go.uber.org/zap"
+)
+
+// snapshotTimeout bounds fetching a page and all of its stylesheets and images
+const snapshotTimeout = time.Minute
+
+// SnapshotService keeps offline snapshots of bookmarked pages so they survive link rot.
+// Snapshots are unrelated to archiving a bookmark, which only hides it from the default list.
+type SnapshotService struct {
+	bookmarkRepository BookmarkRepository
+	archiver           PageArchiver
+	blobStore          BlobStore
+	policy             *Policy
+	events             *EventHub
+}
+
+// NewSnapshotService creates a new instance of SnapshotService
+func NewSnapshotService(bookmarkRepo BookmarkRepository, archiver PageArchiver, blobStore BlobStore, policy *Policy, events *EventHub) *SnapshotService {
+	return &SnapshotService{
+		bookmarkRepository: bookmarkRepo,
+		archiver:           archiver,
+		blobStore:          blobStore,
+		policy:             policy,
+		events:             events,
+	}
+}
+
+// snapshotKey is the blob key of a bookmark's snapshot
+func snapshotKey(bookmarkID string) string {
+	return "snapshots/" + bookmarkID + ".html"
+}
+
+// CaptureSnapshot archives the bookmarked page and records the snapshot on the bookmark,
+// replacing any earlier snapshot
+func (s *SnapshotService) CaptureSnapshot(bookmarkID string) (model.Bookmark, error) {
+	b, err := s.bookmarkRepository.GetBookmark(bookmarkID)
+	if err != nil {
+		return model.Bookmark{}, fmt.Errorf("failed to get bookmark with ID %s: %w", bookmarkID, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), snapshotTimeout)
+	defer cancel()
+
+	page, err := s.archiver.ArchivePage(ctx, b.URL)
+	if err != nil {
+		return model.Bookmark{}, fmt.Errorf("failed to archive %s: %w", b.URL, err)
+	}
+	blob := model.Blob{
+		Key:         snapshotKey(b.ID),
+		ContentType: "text/html; charset=utf-8",
+		Data:        page,
+	}
+	if err := s.blobStore.PutBlob(ctx, blob); err != nil {
+		return model.Bookmark{}, fmt.Errorf("failed to store snapshot of bookmark %s: %w", b.ID, err)
+	}
+
+	// Update a fresh read of the bookmark so changes made while the page was archived are kept
+	updated, err := updateBookmark(s.bookmarkRepository, bookmarkID, func(b *model.Bookmark) error {
+		b.SnapshotKey = blob.Key
+		b.SnapshotAt = time.Now()
+		return nil
+	})
+	if errors.Is(err, model.ErrNotFound) {
+		// Deleted while archiving, do not leave the snapshot behind
+		s.deleteBlob(blob.Key)
+	}
+	if err != nil {
+		return model.Bookmark{}, err
+	}
+
+	logger.Info("Captured bookmark snapshot",
+		zap.String("bookmark_id", b.ID),
+		zap.String("url", b.URL),
+		zap.Int("size", len(page)))
+	s.events.Publish(model.EventBookmarkEnriched, updated)
+	return updated, nil
+}
+
+// CaptureSnapshotAsync captures a snapshot in the background, logging failures.
+// Bookmark creation uses it so saving a link never waits for the whole page to download.
+func (s *SnapshotService) CaptureSnapshotAsync(bookmarkID string) {
+	go func() {
+		if _, err := s.CaptureSnapshot(bookmarkID); err != nil {
+			logger.Warn("failed to capture bookmark snapshot", zap.String("bookmark_id", bookmarkID), zap.Error(err))
+		}
+	}()
+}
+
+// GetSnapshot returns the snapshot of a bookmark that userID can view
+func (s *SnapshotService) GetSnapshot(userID, bookmarkID string) (model.Blob, error) {
+	b, err := s.bookmarkRepository.GetBookmark(bookmarkID)
+	if err != nil {
+		return model.Blob{}, fmt.Errorf("failed to get bookmark with ID %s: %w", bookmarkID, err)
+	}
+	if err := s.policy.CanViewBookmark(userID, b); err != nil {
+		return model.Blob{}, err
+	}
+	if b.SnapshotKey == "" {
+		return model.Blob{}, fmt.Errorf("bookmark %s has no snapshot: %w", b.ID, model.ErrNotFound)
+	}
+
+	blob, err := s.blobStore.GetBlob(context.Background(), b.SnapshotKey)
+	if err != nil {
+		return model.Blob{}, fmt.Errorf("failed to get snapshot of bookmark %s: %w", b.ID, err)
+	}
+	if blob.UpdatedAt.IsZero() {
+		blob.UpdatedAt = b.SnapshotAt
+	}
+	return blob, nil
+}
+
+// DeleteSnapshot removes the stored snapshot of a bookmark that is being deleted
+func (s *SnapshotService) DeleteSnapshot(b model.Bookmark) {
+	if b.SnapshotKey != "" {
+		s.deleteBlob(b.SnapshotKey)
+	}
+}
+
+func (s *SnapshotService) deleteBlob(key string) {
+	if err := s.blobStore.DeleteBlob(context.Background(), key); err != nil {
+		logger.Warn("failed to delete snapshot", zap.String("key", key), zap.Error(err))
+	}
+}
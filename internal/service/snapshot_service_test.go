@@ -0,0 +1,162 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/tsongpon/athena/internal/model"
+)
+
+// MockPageArchiver is a mock implementation of PageArchiver for testing
+type MockPageArchiver struct {
+	archivePageFunc func(ctx context.Context, url string) ([]byte, error)
+}
+
+func (m *MockPageArchiver) ArchivePage(ctx context.Context, url string) ([]byte, error) {
+	if m.archivePageFunc != nil {
+		return m.archivePageFunc(ctx, url)
+	}
+	return []byte("<html>" + url + "</html>"), nil
+}
+
+// MockBlobStore is a mock implementation of BlobStore that keeps blobs in a map
+type MockBlobStore struct {
+	blobs map[string]model.Blob
+}
+
+func (m *MockBlobStore) PutBlob(ctx context.Context, blob model.Blob) error {
+	if m.blobs == nil {
+		m.blobs = map[string]model.Blob{}
+	}
+	m.blobs[blob.Key] = blob
+	return nil
+}
+
+func (m *MockBlobStore) GetBlob(ctx context.Context, key string) (model.Blob, error) {
+	blob, ok := m.blobs[key]
+	if !ok {
+		return model.Blob{}, model.ErrNotFound
+	}
+	return blob, nil
+}
+
+func (m *MockBlobStore) DeleteBlob(ctx context.Context, key string) error {
+	delete(m.blobs, key)
+	return nil
+}
+
+func TestSnapshotService_CaptureAndGet(t *testing.T) {
+	bookmark := model.Bookmark{ID: "b1", UserID: "user-1", URL: "https://example.com/post"}
+	bookmarkRepo := &MockBookmarkRepository{
+		getBookmarkFunc: func(id string) (model.Bookmark, error) {
+			if id != bookmark.ID {
+				return model.Bookmark{}, model.ErrNotFound
+			}
+			return bookmark, nil
+		},
+		updateBookmarkFunc: func(b model.Bookmark) (model.Bookmark, error) {
+			bookmark = b
+			return b, nil
+		},
+	}
+	blobStore := &MockBlobStore{}
+	service := NewSnapshotService(bookmarkRepo, &MockPageArchiver{}, blobStore, NewPolicy(&MockCollectionRepository{}), nil)
+
+	if _, err := service.GetSnapshot("user-1", "b1"); !errors.Is(err, model.ErrNotFound) {
+		t.Errorf("GetSnapshot() before capture error = %v, want ErrNotFound", err)
+	}
+
+	updated, err := service.CaptureSnapshot("b1")
+	if err != nil {
+		t.Fatalf("CaptureSnapshot() unexpected error = %v", err)
+	}
+	if updated.SnapshotKey != "snapshots/b1.html" || updated.SnapshotAt.IsZero() || bookmark.SnapshotKey != updated.SnapshotKey {
+		t.Errorf("CaptureSnapshot() bookmark = %+v, want snapshot recorded", updated)
+	}
+	if blob := blobStore.blobs["snapshots/b1.html"]; blob.ContentType != "text/html; charset=utf-8" {
+		t.Errorf("stored blob content type = %q, want text/html", blob.ContentType)
+	}
+
+	snapshot, err := service.GetSnapshot("user-1", "b1")
+	if err != nil {
+		t.Fatalf("GetSnapshot() unexpected error = %v", err)
+	}
+	if string(snapshot.Data) != "<html>https://example.com/post</html>" {
+		t.Errorf("GetSnapshot() data = %q", snapshot.Data)
+	}
+	if !snapshot.UpdatedAt.Equal(updated.SnapshotAt) {
+		t.Errorf("GetSnapshot() UpdatedAt = %v, want the capture time %v", snapshot.UpdatedAt, updated.SnapshotAt)
+	}
+
+	if _, err := service.GetSnapshot("user-2", "b1"); !errors.Is(err, model.ErrForbidden) {
+		t.Errorf("GetSnapshot() by another user error = %v, want ErrForbidden", err)
+	}
+}
+
+func TestSnapshotService_CaptureSnapshot_ArchiveFails(t *testing.T) {
+	bookmark := model.Bookmark{ID: "b1", UserID: "user-1", URL: "https://example.com/post"}
+	bookmarkRepo := &MockBookmarkRepository{
+		getBookmarkFunc: func(id string) (model.Bookmark, error) {
+			if id != bookmark.ID {
+				return model.Bookmark{}, model.ErrNotFound
+			}
+			return bookmark, nil
+		},
+		updateBookmarkFunc: func(b model.Bookmark) (model.Bookmark, error) {
+			bookmark = b
+			return b, nil
+		},
+	}
+	blobStore := &MockBlobStore{}
+	service := NewSnapshotService(bookmarkRepo, &MockPageArchiver{
+		archivePageFunc: func(ctx context.Context, url string) ([]byte, error) {
+			return nil, errors.New("status 404")
+		},
+	}, blobStore, NewPolicy(&MockCollectionRepository{}), nil)
+
+	if _, err := service.CaptureSnapshot("b1"); err == nil {
+		t.Error("CaptureSnapshot() should fail when the page cannot be archived")
+	}
+	if bookmark.SnapshotKey != "" || len(blobStore.blobs) != 0 {
+		t.Errorf("failed capture left bookmark %+v and blobs %v", bookmark, blobStore.blobs)
+	}
+}
+
+func TestSnapshotService_PurgedBookmarkRemovesSnapshot(t *testing.T) {
+	bookmark := model.Bookmark{ID: "b1", UserID: "user-1", URL: "https://example.com/post"}
+	bookmarkRepo := &MockBookmarkRepository{
+		getBookmarkFunc: func(id string) (model.Bookmark, error) {
+			if id != bookmark.ID {
+				return model.Bookmark{}, model.ErrNotFound
+			}
+			return bookmark, nil
+		},
+		updateBookmarkFunc: func(b model.Bookmark) (model.Bookmark, error) {
+			bookmark = b
+			return b, nil
+		},
+	}
+	blobStore := &MockBlobStore{}
+	service := NewSnapshotService(bookmarkRepo, &MockPageArchiver{}, blobStore, NewPolicy(&MockCollectionRepository{}), nil)
+	if _, err := service.CaptureSnapshot("b1"); err != nil {
+		t.Fatalf("CaptureSnapshot() unexpected error = %v", err)
+	}
+
+	bookmarkService := NewBookmarkService(bookmarkRepo, &MockUserRepository{}, &MockWebRepository{}, nil, NewPolicy(&MockCollectionRepository{}), service, nil, nil, nil)
+	if err := bookmarkService.DeleteBookmark("user-1", "b1", 0); err != nil {
+		t.Fatalf("DeleteBookmark() unexpected error = %v", err)
+	}
+	// Kept while the bookmark can be restored
+	if len(blobStore.blobs) == 0 {
+		t.Errorf("blobs after DeleteBookmark() = none, want them kept in the trash")
+	}
+
+	trashService := NewTrashService(bookmarkRepo, &MockHighlightRepository{}, &MockUserRepository{}, nil, NewPolicy(&MockCollectionRepository{}), service, nil, 0, nil)
+	if purged, err := trashService.EmptyTrash("user-1"); err != nil || purged != 1 {
+		t.Fatalf("EmptyTrash() = %d, %v, want 1 purged", purged, err)
+	}
+	if len(blobStore.blobs) != 0 {
+		t.Errorf("blobs after EmptyTrash() = %v, want none", blobStore.blobs)
+	}
+}
@@ -0,0 +1,333 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/tsongpon/athena/internal/logger"
+	"github.com/tsongpon/athena/internal/model"
+	"go.uber.org/zap"
+)
+
+// SyncService lets offline-first clients catch up on the changes to their bookmarks and push
+// the edits they made while offline. Changes are read from a per-user change log, which the
+// bookmark repository appends to with every write.
+type SyncService struct {
+	bookmarkRepository BookmarkRepository
+	changeRepository   BookmarkChangeRepository
+	policy             *Policy
+	events             *EventHub // nil when change events are not streamed
+}
+
+// NewSyncService creates a new instance of SyncService. events may be nil.
+func NewSyncService(bookmarkRepo BookmarkRepository, changeRepo BookmarkChangeRepository, policy *Policy, events *EventHub) *SyncService {
+	return &SyncService{
+		bookmarkRepository: bookmarkRepo,
+		changeRepository:   changeRepo,
+		policy:             policy,
+		events:             events,
+	}
+}
+
+// GetChanges returns how the bookmarks of userID changed since token. An empty token starts a
+// full sync, which returns every bookmark as created. Tokens from before the change log was
+// reset, such as after a restart with in-memory storage, fail with ErrExpired.
+func (s *SyncService) GetChanges(userID, token string) (model.SyncDelta, error) {
+	if token == "" {
+		return s.getAll(userID)
+	}
+	since, err := parseSyncToken(token)
+	if err != nil {
+		return model.SyncDelta{}, err
+	}
+	latest, err := s.changeRepository.LatestChangeSeq(userID)
+	if err != nil {
+		return model.SyncDelta{}, fmt.Errorf("failed to get latest bookmark change: %w", err)
+	}
+	if since > latest {
+		return model.SyncDelta{}, fmt.Errorf("sync token is ahead of the change log: %w", model.ErrExpired)
+	}
+
+	changes, err := s.changeRepository.ListChanges(userID, since, model.MaxSyncChanges+1)
+	if err != nil {
+		return model.SyncDelta{}, fmt.Errorf("failed to list bookmark changes: %w", err)
+	}
+	delta := model.SyncDelta{Token: token}
+	if len(changes) > model.MaxSyncChanges {
+		changes, delta.HasMore = changes[:model.MaxSyncChanges], true
+	}
+	if len(changes) == 0 {
+		return delta, nil
+	}
+	delta.Token = formatSyncToken(changes[len(changes)-1].Seq)
+
+	// A bookmark changed several times is listed once: deleted if its last change deleted it,
+	// otherwise created if it was created since the token
+	var ids []string
+	created := make(map[string]bool)
+	deleted := make(map[string]bool)
+	for _, change := range changes {
+		if _, seen := deleted[change.BookmarkID]; !seen {
+			ids = append(ids, change.BookmarkID)
+		}
+		created[change.BookmarkID] = created[change.BookmarkID] || change.Kind == model.BookmarkChangeCreated
+		deleted[change.BookmarkID] = change.Kind == model.BookmarkChangeDeleted
+	}
+	var live []string
+	for _, id := range ids {
+		if deleted[id] {
+			delta.Deleted = append(delta.Deleted, id)
+		} else {
+			live = append(live, id)
+		}
+	}
+
+	bookmarks, err := s.bookmarkRepository.GetBookmarks(live)
+	if err != nil {
+		return model.SyncDelta{}, fmt.Errorf("failed to get bookmarks: %w", err)
+	}
+	found := make(map[string]bool, len(bookmarks))
+	for _, b := range bookmarks {
+		found[b.ID] = true
+		if created[b.ID] {
+			delta.Created = append(delta.Created, b)
+		} else {
+			delta.Updated = append(delta.Updated, b)
+		}
+	}
+	// Deleted after the changes listed; the deletion follows in the change log
+	for _, id := range live {
+		if !found[id] {
+			delta.Deleted = append(delta.Deleted, id)
+		}
+	}
+	return delta, nil
+}
+
+// getAll returns every bookmark of userID, with a token from before they were read so that
+// changes made meanwhile are pulled again next time
+func (s *SyncService) getAll(userID string) (model.SyncDelta, error) {
+	latest, err := s.changeRepository.LatestChangeSeq(userID)
+	if err != nil {
+		return model.SyncDelta{}, fmt.Errorf("failed to get latest bookmark change: %w", err)
+	}
+	delta := model.SyncDelta{Token: formatSyncToken(latest)}
+	for _, archived := range []bool{false, true} {
+		bookmarks, err := s.bookmarkRepository.ListBookmarks(model.BookmarkQuery{UserID: userID, Archived: archived})
+		if err != nil {
+			return model.SyncDelta{}, fmt.Errorf("failed to list bookmarks: %w", err)
+		}
+		delta.Created = append(delta.Created, bookmarks...)
+	}
+	return delta, nil
+}
+
+// PushChanges applies edits userID made offline and returns the result of each, in order.
+// An edit of a bookmark that changed on the server since the copy the client edited is a
+// conflict, which the later of the two changes wins: the edit is applied if it was made after
+// the server's last update, and dropped otherwise. Bookmarks that do not exist or belong to
+// someone else are reported as not found.
+func (s *SyncService) PushChanges(userID string, edits []model.SyncEdit) ([]model.SyncResult, error) {
+	if len(edits) == 0 || len(edits) > model.MaxSyncEdits {
+		return nil, fmt.Errorf("between 1 and %d changes are required: %w", model.MaxSyncEdits, model.ErrInvalidInput)
+	}
+
+	results := make([]model.SyncResult, len(edits))
+	var ids []string
+	seen := make(map[string]bool, len(edits))
+	for i, edit := range edits {
+		results[i] = model.SyncResult{BookmarkID: edit.BookmarkID}
+		if err := validateSyncEdit(edit); err != nil {
+			results[i].Status, results[i].Error = model.SyncStatusInvalid, err.Error()
+			continue
+		}
+		if seen[edit.BookmarkID] {
+			results[i].Status, results[i].Error = model.SyncStatusInvalid, "bookmark is changed more than once"
+			continue
+		}
+		seen[edit.BookmarkID] = true
+		ids = append(ids, edit.BookmarkID)
+	}
+
+	bookmarks, err := s.bookmarkRepository.GetBookmarks(ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bookmarks: %w", err)
+	}
+	owned := make(map[string]model.Bookmark, len(bookmarks))
+	for _, b := range bookmarks {
+		if s.policy.CanModifyBookmark(userID, b) == nil {
+			owned[b.ID] = b
+		}
+	}
+
+	now := time.Now()
+	var writes []model.BookmarkWrite
+	var written []int // Index of the edit of each write
+	for i, edit := range edits {
+		if results[i].Status != "" {
+			continue
+		}
+		b, ok := owned[edit.BookmarkID]
+		if !ok {
+			results[i].Status, results[i].Error = model.SyncStatusNotFound, "bookmark not found"
+			continue
+		}
+		results[i].Conflicted = !sameSyncTime(b.UpdatedAt, edit.BaseUpdatedAt)
+		editedAt := edit.EditedAt
+		if editedAt.IsZero() || editedAt.After(now) {
+			editedAt = now
+		}
+		if results[i].Conflicted && !editedAt.After(b.UpdatedAt) {
+			results[i].Status, results[i].Error = model.SyncStatusConflict, "bookmark changed on the server after the edit"
+			results[i].Bookmark = b
+			continue
+		}
+
+		if edit.Delete {
+			b.DeletedAt = now
+		} else {
+			applySyncEdit(&b, edit)
+		}
+		writes = append(writes, model.BookmarkWrite{Bookmark: b, Trash: edit.Delete})
+		written = append(written, i)
+	}
+
+	var stored []string
+	for j, err := range s.bookmarkRepository.WriteBookmarks(writes) {
+		i := written[j]
+		if errors.Is(err, model.ErrNotFound) {
+			results[i].Status, results[i].Error = model.SyncStatusNotFound, "bookmark not found"
+			continue
+		}
+		if errors.Is(err, model.ErrConflict) {
+			// Changed on the server since it was read above; the client gets the new copy
+			results[i].Status, results[i].Error = model.SyncStatusConflict, "bookmark changed on the server during the sync"
+			results[i].Conflicted = true
+			stored = append(stored, edits[i].BookmarkID)
+			continue
+		}
+		if err != nil {
+			logger.Error("Failed to write bookmark of a sync push", zap.String("bookmark_id", edits[i].BookmarkID), zap.Error(err))
+			results[i].Status, results[i].Error = model.SyncStatusFailed, "failed to save bookmark"
+			continue
+		}
+		results[i].Status = model.SyncStatusApplied
+		if edits[i].Delete {
+			s.events.Publish(model.EventBookmarkDeleted, writes[j].Bookmark)
+		} else {
+			stored = append(stored, edits[i].BookmarkID)
+		}
+	}
+
+	// Re-read the stored bookmarks so that results and events carry the UpdatedAt the
+	// repository set, which the client edits from next, and the conflicting ones for their
+	// server copy
+	updated, err := s.bookmarkRepository.GetBookmarks(stored)
+	if err != nil {
+		logger.Warn("Failed to read back bookmarks of a sync push", zap.String("user_id", userID), zap.Error(err))
+	}
+	fresh := make(map[string]model.Bookmark, len(updated))
+	for _, b := range updated {
+		fresh[b.ID] = b
+	}
+	for j, i := range written {
+		if results[i].Status == model.SyncStatusConflict {
+			results[i].Bookmark = fresh[edits[i].BookmarkID]
+			continue
+		}
+		if results[i].Status != model.SyncStatusApplied || edits[i].Delete {
+			continue
+		}
+		b, ok := fresh[edits[i].BookmarkID]
+		if !ok {
+			b = writes[j].Bookmark
+		}
+		results[i].Bookmark = b
+		if b.IsArchived && !owned[b.ID].IsArchived {
+			s.events.Publish(model.EventBookmarkArchived, b)
+		} else {
+			s.events.Publish(model.EventBookmarkUpdated, b)
+		}
+	}
+
+	applied := 0
+	for _, result := range results {
+		if result.Status == model.SyncStatusApplied {
+			applied++
+		}
+	}
+	logger.Info("Applied sync push",
+		zap.String("user_id", userID),
+		zap.Int("changes", len(edits)),
+		zap.Int("applied", applied))
+	return results, nil
+}
+
+// validateSyncEdit checks that an edit is complete, before any bookmark is read
+func validateSyncEdit(edit model.SyncEdit) error {
+	if edit.BookmarkID == "" {
+		return fmt.Errorf("bookmark_id is required: %w", model.ErrInvalidInput)
+	}
+	if edit.Delete {
+		return nil
+	}
+	if edit.IsArchived == nil && edit.IsRead == nil && edit.IsFavorite == nil && edit.ReadingProgress == nil &&
+		edit.Notes == nil && edit.Tags == nil {
+		return fmt.Errorf("deleted or a field to change is required: %w", model.ErrInvalidInput)
+	}
+	if err := validateReadingProgress(edit.ReadingProgress); err != nil {
+		return err
+	}
+	if edit.Notes != nil {
+		if err := validateNote(*edit.Notes); err != nil {
+			return err
+		}
+	}
+	if edit.Tags != nil && len(model.NormalizeTags(*edit.Tags)) > model.MaxBookmarkTags {
+		return fmt.Errorf("a bookmark can have at most %d tags: %w", model.MaxBookmarkTags, model.ErrInvalidInput)
+	}
+	return nil
+}
+
+// applySyncEdit changes the fields of b that edit sets
+func applySyncEdit(b *model.Bookmark, edit model.SyncEdit) {
+	if edit.IsArchived != nil {
+		b.IsArchived = *edit.IsArchived
+	}
+	if edit.IsRead != nil || edit.IsFavorite != nil || edit.ReadingProgress != nil {
+		applyReadingState(b, model.ReadingStateUpdate{
+			IsRead:          edit.IsRead,
+			IsFavorite:      edit.IsFavorite,
+			ReadingProgress: edit.ReadingProgress,
+		})
+	}
+	if edit.Notes != nil {
+		b.Notes = *edit.Notes
+	}
+	if edit.Tags != nil {
+		b.Tags = model.NormalizeTags(*edit.Tags)
+	}
+}
+
+// sameSyncTime reports whether an UpdatedAt a client sent back is the one stored. Firestore
+// keeps times to the microsecond, so finer differences are ignored.
+func sameSyncTime(stored, base time.Time) bool {
+	return stored.Truncate(time.Microsecond).Equal(base.Truncate(time.Microsecond))
+}
+
+// formatSyncToken returns the change token of a position in the change log. Tokens are
+// opaque to clients.
+func formatSyncToken(seq int64) string {
+	return strconv.FormatInt(seq, 10)
+}
+
+// parseSyncToken returns the position in the change log of a change token
+func parseSyncToken(token string) (int64, error) {
+	seq, err := strconv.ParseInt(token, 10, 64)
+	if err != nil || seq < 0 {
+		return 0, fmt.Errorf("malformed sync token: %w", model.ErrInvalidInput)
+	}
+	return seq, nil
+}
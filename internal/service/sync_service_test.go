@@ -0,0 +1,336 @@
+package service
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/tsongpon/athena/internal/model"
+)
+
+// MockBookmarkChangeRepository is a slice-backed mock of BookmarkChangeRepository
+type MockBookmarkChangeRepository struct {
+	changes []model.BookmarkChange
+}
+
+// record appends a change to bookmark, as the bookmark repository does with every write
+func (m *MockBookmarkChangeRepository) record(bookmark model.Bookmark, kind string) {
+	latest, _ := m.LatestChangeSeq(bookmark.UserID)
+	m.changes = append(m.changes, model.BookmarkChange{
+		UserID:     bookmark.UserID,
+		Seq:        latest + 1,
+		BookmarkID: bookmark.ID,
+		Kind:       kind,
+		At:         time.Now(),
+	})
+}
+
+func (m *MockBookmarkChangeRepository) ListChanges(userID string, after int64, limit int) ([]model.BookmarkChange, error) {
+	var changes []model.BookmarkChange
+	for _, change := range m.changes {
+		if change.UserID == userID && change.Seq > after && len(changes) < limit {
+			changes = append(changes, change)
+		}
+	}
+	return changes, nil
+}
+
+func (m *MockBookmarkChangeRepository) LatestChangeSeq(userID string) (int64, error) {
+	var latest int64
+	for _, change := range m.changes {
+		if change.UserID == userID {
+			latest = change.Seq
+		}
+	}
+	return latest, nil
+}
+
+func sortedBookmarkIDs(bookmarks []model.Bookmark) []string {
+	ids := make([]string, len(bookmarks))
+	for i, b := range bookmarks {
+		ids[i] = b.ID
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+func TestSyncService_GetChanges(t *testing.T) {
+	stored := map[string]model.Bookmark{
+		"old":      {ID: "old", UserID: "user-1"},
+		"archived": {ID: "archived", UserID: "user-1", IsArchived: true},
+		"foreign":  {ID: "foreign", UserID: "user-2"},
+	}
+	changeRepo := &MockBookmarkChangeRepository{}
+	bookmarkRepo := &MockBookmarkRepository{
+		getBookmarkFunc: func(id string) (model.Bookmark, error) {
+			b, ok := stored[id]
+			if !ok {
+				return model.Bookmark{}, model.ErrNotFound
+			}
+			return b, nil
+		},
+		listBookmarksFunc: func(userID string, archived bool) ([]model.Bookmark, error) {
+			var listed []model.Bookmark
+			for _, b := range stored {
+				if b.UserID == userID && b.IsArchived == archived {
+					listed = append(listed, b)
+				}
+			}
+			return listed, nil
+		},
+	}
+	service := NewSyncService(bookmarkRepo, changeRepo, NewPolicy(&MockCollectionRepository{}), nil)
+
+	full, err := service.GetChanges("user-1", "")
+	if err != nil {
+		t.Fatalf("GetChanges() full sync unexpected error = %v", err)
+	}
+	if got := sortedBookmarkIDs(full.Created); !slices.Equal(got, []string{"archived", "old"}) {
+		t.Errorf("GetChanges() full sync created = %v, want every bookmark of the user", got)
+	}
+	if full.Token != "0" || full.HasMore {
+		t.Errorf("GetChanges() full sync token = %q, has more = %v, want \"0\" and false", full.Token, full.HasMore)
+	}
+
+	stored["new"] = model.Bookmark{ID: "new", UserID: "user-1"}
+	changeRepo.record(stored["new"], model.BookmarkChangeCreated)
+	changeRepo.record(stored["new"], model.BookmarkChangeUpdated)
+	changeRepo.record(stored["old"], model.BookmarkChangeUpdated)
+	stored["gone"] = model.Bookmark{ID: "gone", UserID: "user-1"}
+	changeRepo.record(stored["gone"], model.BookmarkChangeCreated)
+	delete(stored, "gone")
+	changeRepo.record(model.Bookmark{ID: "gone", UserID: "user-1"}, model.BookmarkChangeDeleted)
+	changeRepo.record(stored["archived"], model.BookmarkChangeUpdated)
+	delete(stored, "archived") // Trashed, but the deletion is not in the log yet
+	changeRepo.record(stored["foreign"], model.BookmarkChangeUpdated)
+
+	delta, err := service.GetChanges("user-1", full.Token)
+	if err != nil {
+		t.Fatalf("GetChanges() unexpected error = %v", err)
+	}
+	if got := sortedBookmarkIDs(delta.Created); !slices.Equal(got, []string{"new"}) {
+		t.Errorf("GetChanges() created = %v, want [new]", got)
+	}
+	if got := sortedBookmarkIDs(delta.Updated); !slices.Equal(got, []string{"old"}) {
+		t.Errorf("GetChanges() updated = %v, want [old]", got)
+	}
+	if !slices.Equal(delta.Deleted, []string{"gone", "archived"}) {
+		t.Errorf("GetChanges() deleted = %v, want [gone archived]", delta.Deleted)
+	}
+	if delta.Token != "6" || delta.HasMore {
+		t.Errorf("GetChanges() token = %q, has more = %v, want \"6\" and false", delta.Token, delta.HasMore)
+	}
+
+	empty, err := service.GetChanges("user-1", delta.Token)
+	if err != nil {
+		t.Fatalf("GetChanges() unexpected error = %v", err)
+	}
+	if len(empty.Created)+len(empty.Updated)+len(empty.Deleted) != 0 || empty.Token != delta.Token {
+		t.Errorf("GetChanges() without changes = %+v, want nothing and the same token", empty)
+	}
+
+	if _, err := service.GetChanges("user-1", "7"); !errors.Is(err, model.ErrExpired) {
+		t.Errorf("GetChanges() ahead of the log error = %v, want ErrExpired", err)
+	}
+	if _, err := service.GetChanges("user-1", "abc"); !errors.Is(err, model.ErrInvalidInput) {
+		t.Errorf("GetChanges() malformed token error = %v, want ErrInvalidInput", err)
+	}
+}
+
+func TestSyncService_GetChanges_HasMore(t *testing.T) {
+	bookmark := model.Bookmark{ID: "b1", UserID: "user-1"}
+	changeRepo := &MockBookmarkChangeRepository{}
+	bookmarkRepo := &MockBookmarkRepository{
+		getBookmarkFunc: func(id string) (model.Bookmark, error) {
+			return bookmark, nil
+		},
+	}
+	service := NewSyncService(bookmarkRepo, changeRepo, NewPolicy(&MockCollectionRepository{}), nil)
+	for range model.MaxSyncChanges + 1 {
+		changeRepo.record(bookmark, model.BookmarkChangeUpdated)
+	}
+
+	delta, err := service.GetChanges("user-1", "0")
+	if err != nil {
+		t.Fatalf("GetChanges() unexpected error = %v", err)
+	}
+	if !delta.HasMore || delta.Token != "500" || len(delta.Updated) != 1 {
+		t.Errorf("GetChanges() token = %q, has more = %v, updated = %d, want \"500\", true and 1", delta.Token, delta.HasMore, len(delta.Updated))
+	}
+	rest, err := service.GetChanges("user-1", delta.Token)
+	if err != nil {
+		t.Fatalf("GetChanges() unexpected error = %v", err)
+	}
+	if rest.HasMore || rest.Token != "501" {
+		t.Errorf("GetChanges() rest token = %q, has more = %v, want \"501\" and false", rest.Token, rest.HasMore)
+	}
+}
+
+func TestSyncService_PushChanges(t *testing.T) {
+	base := time.Date(2025, 11, 1, 10, 0, 0, 0, time.UTC)
+	serverUpdate := base.Add(time.Hour)
+	stored := map[string]model.Bookmark{
+		"clean":   {ID: "clean", UserID: "user-1", UpdatedAt: base},
+		"stale":   {ID: "stale", UserID: "user-1", UpdatedAt: serverUpdate},
+		"newer":   {ID: "newer", UserID: "user-1", UpdatedAt: serverUpdate, Notes: "server"},
+		"trash":   {ID: "trash", UserID: "user-1", UpdatedAt: base},
+		"foreign": {ID: "foreign", UserID: "user-2", UpdatedAt: base},
+	}
+	changeRepo := &MockBookmarkChangeRepository{}
+	bookmarkRepo := &MockBookmarkRepository{
+		getBookmarkFunc: func(id string) (model.Bookmark, error) {
+			b, ok := stored[id]
+			if !ok {
+				return model.Bookmark{}, model.ErrNotFound
+			}
+			return b, nil
+		},
+		// Writes are recorded in the change log, as the bookmark repository does
+		writeBookmarksFunc: func(writes []model.BookmarkWrite) []error {
+			errs := make([]error, len(writes))
+			for i, write := range writes {
+				existing, ok := stored[write.Bookmark.ID]
+				if !ok {
+					errs[i] = model.ErrNotFound
+					continue
+				}
+				if existing.Version != write.Bookmark.Version {
+					errs[i] = model.ErrConflict
+					continue
+				}
+				if write.Trash {
+					delete(stored, write.Bookmark.ID)
+					changeRepo.record(write.Bookmark, model.BookmarkChangeDeleted)
+					continue
+				}
+				write.Bookmark.UpdatedAt = time.Now()
+				write.Bookmark.Version++
+				stored[write.Bookmark.ID] = write.Bookmark
+				changeRepo.record(write.Bookmark, model.BookmarkChangeUpdated)
+			}
+			return errs
+		},
+	}
+	service := NewSyncService(bookmarkRepo, changeRepo, NewPolicy(&MockCollectionRepository{}), nil)
+	archived, read, progress, notes := true, true, 40, "client"
+	tags := []string{"Go", "go", "sync"}
+
+	results, err := service.PushChanges("user-1", []model.SyncEdit{
+		{BookmarkID: "clean", BaseUpdatedAt: base, EditedAt: base.Add(time.Minute), IsArchived: &archived, ReadingProgress: &progress, Tags: &tags},
+		{BookmarkID: "stale", BaseUpdatedAt: base, EditedAt: base.Add(time.Minute), IsRead: &read},
+		{BookmarkID: "newer", BaseUpdatedAt: base, EditedAt: serverUpdate.Add(time.Minute), Notes: &notes},
+		{BookmarkID: "trash", BaseUpdatedAt: base, Delete: true},
+		{BookmarkID: "foreign", BaseUpdatedAt: base, IsRead: &read},
+		{BookmarkID: "clean", BaseUpdatedAt: base, IsRead: &read},
+		{BookmarkID: "missing", IsRead: &read},
+		{BookmarkID: "clean"},
+	})
+	if err != nil {
+		t.Fatalf("PushChanges() unexpected error = %v", err)
+	}
+	statuses := make([]string, len(results))
+	for i, result := range results {
+		statuses[i] = result.Status
+	}
+	want := []string{
+		model.SyncStatusApplied, model.SyncStatusConflict, model.SyncStatusApplied, model.SyncStatusApplied,
+		model.SyncStatusNotFound, model.SyncStatusInvalid, model.SyncStatusNotFound, model.SyncStatusInvalid,
+	}
+	if !slices.Equal(statuses, want) {
+		t.Fatalf("PushChanges() statuses = %v, want %v", statuses, want)
+	}
+
+	clean := stored["clean"]
+	if !clean.IsArchived || clean.ReadingProgress != 40 || !slices.Equal(clean.Tags, []string{"go", "sync"}) {
+		t.Errorf("PushChanges() stored %+v, want archived, 40%% read and normalized tags", clean)
+	}
+	if results[0].Conflicted || !results[0].Bookmark.UpdatedAt.Equal(clean.UpdatedAt) {
+		t.Errorf("PushChanges() result = %+v, want no conflict and the stored copy", results[0])
+	}
+	if stored["stale"].IsRead || !results[1].Conflicted || !results[1].Bookmark.UpdatedAt.Equal(serverUpdate) {
+		t.Errorf("PushChanges() older edit of a changed bookmark = %+v, want it dropped with the server copy", results[1])
+	}
+	if stored["newer"].Notes != "client" || !results[2].Conflicted {
+		t.Errorf("PushChanges() newer edit of a changed bookmark = %+v, want it applied as a conflict", results[2])
+	}
+	if _, ok := stored["trash"]; ok || results[3].Bookmark.ID != "" {
+		t.Errorf("PushChanges() delete = %+v, want the bookmark trashed", results[3])
+	}
+	if stored["foreign"].IsRead {
+		t.Error("PushChanges() changed a bookmark of another user")
+	}
+
+	var kinds []string
+	for _, change := range changeRepo.changes {
+		kinds = append(kinds, change.BookmarkID+" "+change.Kind)
+	}
+	wantKinds := []string{"clean updated", "newer updated", "trash deleted"}
+	if !slices.Equal(kinds, wantKinds) {
+		t.Errorf("PushChanges() recorded changes %v, want %v", kinds, wantKinds)
+	}
+
+	if _, err := service.PushChanges("user-1", nil); !errors.Is(err, model.ErrInvalidInput) {
+		t.Errorf("PushChanges() without changes error = %v, want ErrInvalidInput", err)
+	}
+}
+
+func TestSyncService_PushChanges_ConcurrentWrite(t *testing.T) {
+	base := time.Date(2025, 11, 1, 10, 0, 0, 0, time.UTC)
+	stored := map[string]model.Bookmark{"b1": {ID: "b1", UserID: "user-1", UpdatedAt: base, Version: 1}}
+	changeRepo := &MockBookmarkChangeRepository{}
+	bookmarkRepo := &MockBookmarkRepository{
+		getBookmarkFunc: func(id string) (model.Bookmark, error) {
+			b, ok := stored[id]
+			if !ok {
+				return model.Bookmark{}, model.ErrNotFound
+			}
+			return b, nil
+		},
+		// Writes are recorded in the change log, as the bookmark repository does
+		writeBookmarksFunc: func(writes []model.BookmarkWrite) []error {
+			// Another write lands between the read of the push and its write
+			b := stored["b1"]
+			b.Title, b.Version = "Enriched", b.Version+1
+			stored["b1"] = b
+			errs := make([]error, len(writes))
+			for i, write := range writes {
+				existing, ok := stored[write.Bookmark.ID]
+				if !ok {
+					errs[i] = model.ErrNotFound
+					continue
+				}
+				if existing.Version != write.Bookmark.Version {
+					errs[i] = model.ErrConflict
+					continue
+				}
+				if write.Trash {
+					delete(stored, write.Bookmark.ID)
+					changeRepo.record(write.Bookmark, model.BookmarkChangeDeleted)
+					continue
+				}
+				write.Bookmark.UpdatedAt = time.Now()
+				write.Bookmark.Version++
+				stored[write.Bookmark.ID] = write.Bookmark
+				changeRepo.record(write.Bookmark, model.BookmarkChangeUpdated)
+			}
+			return errs
+		},
+	}
+	service := NewSyncService(bookmarkRepo, changeRepo, NewPolicy(&MockCollectionRepository{}), nil)
+	read := true
+
+	results, err := service.PushChanges("user-1", []model.SyncEdit{{BookmarkID: "b1", BaseUpdatedAt: base, IsRead: &read}})
+	if err != nil {
+		t.Fatalf("PushChanges() unexpected error = %v", err)
+	}
+	if results[0].Status != model.SyncStatusConflict || !results[0].Conflicted || results[0].Bookmark.Title != "Enriched" {
+		t.Errorf("PushChanges() result = %+v, want a conflict with the server copy", results[0])
+	}
+	if stored["b1"].IsRead {
+		t.Error("PushChanges() overwrote the concurrent write")
+	}
+	if len(changeRepo.changes) != 0 {
+		t.Errorf("PushChanges() recorded changes %+v, want none", changeRepo.changes)
+	}
+}
@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"time"
+
+	"github.com/tsongpon/athena/internal/logger"
+	"github.com/tsongpon/athena/internal/model"
+	"go.uber.org/zap"
+)
+
+// thumbnailTimeout bounds downloading and resizing a bookmark's main image
+const thumbnailTimeout = 30 * time.Second
+
+// thumbnailSizes are the square boxes the main image of a bookmark is scaled to fit, by name
+var thumbnailSizes = []struct {
+	name string
+	box  int
+}{
+	{"small", 160},
+	{"medium", 480},
+	{"large", 1200},
+}
+
+// ThumbnailService keeps resized copies of the main image of bookmarks, so clients do not
+// hotlink third party images that leak their IP address, move or weigh megabytes
+type ThumbnailService struct {
+	bookmarkRepository BookmarkRepository
+	thumbnailer        Thumbnailer
+	blobStore          BlobStore
+	policy             *Policy
+	events             *EventHub
+}
+
+// NewThumbnailService creates a new instance of ThumbnailService
+func NewThumbnailService(bookmarkRepo BookmarkRepository, thumbnailer Thumbnailer, blobStore BlobStore, policy *Policy, events *EventHub) *ThumbnailService {
+	return &ThumbnailService{
+		bookmarkRepository: bookmarkRepo,
+		thumbnailer:        thumbnailer,
+		blobStore:          blobStore,
+		policy:             policy,
+		events:             events,
+	}
+}
+
+// thumbnailKey is the blob key of one size of a bookmark's thumbnail. The extension gives
+// the content type to stores that do not keep it.
+func thumbnailKey(bookmarkID, size, contentType string) string {
+	ext := ".jpg"
+	if contentType == "image/png" {
+		ext = ".png"
+	}
+	return "thumbnails/" + bookmarkID + "/" + size + ext
+}
+
+// CaptureThumbnails fetches the main image of a bookmark and stores it in every thumbnail
+// size, replacing any earlier thumbnails
+func (s *ThumbnailService) CaptureThumbnails(bookmarkID string) (model.Bookmark, error) {
+	b, err := s.bookmarkRepository.GetBookmark(bookmarkID)
+	if err != nil {
+		return model.Bookmark{}, fmt.Errorf("failed to get bookmark with ID %s: %w", bookmarkID, err)
+	}
+	if b.MainImageURL == "" {
+		return model.Bookmark{}, fmt.Errorf("bookmark %s has no main image: %w", b.ID, model.ErrNotFound)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), thumbnailTimeout)
+	defer cancel()
+
+	boxes := make([]int, len(thumbnailSizes))
+	for i, size := range thumbnailSizes {
+		boxes[i] = size.box
+	}
+	blobs, err := s.thumbnailer.CreateThumbnails(ctx, b.MainImageURL, boxes)
+	if err != nil {
+		return model.Bookmark{}, fmt.Errorf("failed to create thumbnails of %s: %w", b.MainImageURL, err)
+	}
+	if len(blobs) != len(thumbnailSizes) {
+		return model.Bookmark{}, fmt.Errorf("got %d thumbnails of %s, want %d", len(blobs), b.MainImageURL, len(thumbnailSizes))
+	}
+	keys := make(map[string]string, len(blobs))
+	for i, blob := range blobs {
+		blob.Key = thumbnailKey(b.ID, thumbnailSizes[i].name, blob.ContentType)
+		if err := s.blobStore.PutBlob(ctx, blob); err != nil {
+			s.deleteBlobs(keys)
+			return model.Bookmark{}, fmt.Errorf("failed to store thumbnail of bookmark %s: %w", b.ID, err)
+		}
+		keys[thumbnailSizes[i].name] = blob.Key
+	}
+
+	// Update a fresh read of the bookmark so changes made while the image was fetched are kept
+	var stale map[string]string
+	updated, err := updateBookmark(s.bookmarkRepository, bookmarkID, func(b *model.Bookmark) error {
+		// A PNG may replace a JPEG of the same size under a different key
+		stale = maps.Clone(b.Thumbnails)
+		maps.DeleteFunc(stale, func(size, key string) bool { return keys[size] == key })
+		b.Thumbnails = keys
+		b.ThumbnailsAt = time.Now()
+		return nil
+	})
+	if errors.Is(err, model.ErrNotFound) {
+		// Deleted while fetching, do not leave the thumbnails behind
+		s.deleteBlobs(keys)
+	}
+	if err != nil {
+		return model.Bookmark{}, err
+	}
+	s.deleteBlobs(stale)
+
+	logger.Info("Captured bookmark thumbnails",
+		zap.String("bookmark_id", b.ID),
+		zap.String("image_url", b.MainImageURL))
+	s.events.Publish(model.EventBookmarkEnriched, updated)
+	return updated, nil
+}
+
+// CaptureThumbnailsAsync captures thumbnails in the background, logging failures
+func (s *ThumbnailService) CaptureThumbnailsAsync(bookmarkID string) {
+	go func() {
+		if _, err := s.CaptureThumbnails(bookmarkID); err != nil {
+			logger.Warn("failed to capture bookmark thumbnails", zap.String("bookmark_id", bookmarkID), zap.Error(err))
+		}
+	}()
+}
+
+// GetThumbnail returns one size of the thumbnail of a bookmark that userID can view
+func (s *ThumbnailService) GetThumbnail(userID, bookmarkID, size string) (model.Blob, error) {
+	b, err := s.bookmarkRepository.GetBookmark(bookmarkID)
+	if err != nil {
+		return model.Blob{}, fmt.Errorf("failed to get bookmark with ID %s: %w", bookmarkID, err)
+	}
+	if err := s.policy.CanViewBookmark(userID, b); err != nil {
+		return model.Blob{}, err
+	}
+	key, ok := b.Thumbnails[size]
+	if !ok {
+		return model.Blob{}, fmt.Errorf("bookmark %s has no %q thumbnail: %w", b.ID, size, model.ErrNotFound)
+	}
+
+	blob, err := s.blobStore.GetBlob(context.Background(), key)
+	if err != nil {
+		return model.Blob{}, fmt.Errorf("failed to get thumbnail of bookmark %s: %w", b.ID, err)
+	}
+	if blob.UpdatedAt.IsZero() {
+		blob.UpdatedAt = b.ThumbnailsAt
+	}
+	return blob, nil
+}
+
+// DeleteThumbnails removes the stored thumbnails of a bookmark that is being deleted
+func (s *ThumbnailService) DeleteThumbnails(b model.Bookmark) {
+	s.deleteBlobs(b.Thumbnails)
+}
+
+func (s *ThumbnailService) deleteBlobs(keys map[string]string) {
+	for _, key := range keys {
+		if err := s.blobStore.DeleteBlob(context.Background(), key); err != nil {
+			logger.Warn("failed to delete thumbnail", zap.String("key", key), zap.Error(err))
+		}
+	}
+}
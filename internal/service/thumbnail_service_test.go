@@ -0,0 +1,186 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/tsongpon/athena/internal/model"
+)
+
+// MockThumbnailer is a mock implementation of Thumbnailer for testing
+type MockThumbnailer struct {
+	createThumbnailsFunc func(ctx context.Context, url string, sizes []int) ([]model.Blob, error)
+}
+
+func (m *MockThumbnailer) CreateThumbnails(ctx context.Context, url string, sizes []int) ([]model.Blob, error) {
+	if m.createThumbnailsFunc != nil {
+		return m.createThumbnailsFunc(ctx, url, sizes)
+	}
+	blobs := make([]model.Blob, len(sizes))
+	for i := range sizes {
+		blobs[i] = model.Blob{ContentType: "image/jpeg", Data: []byte(url)}
+	}
+	return blobs, nil
+}
+
+func TestThumbnailService_CaptureAndGet(t *testing.T) {
+	bookmark := model.Bookmark{ID: "b1", UserID: "user-1", URL: "https://example.com/post", MainImageURL: "https://cdn.example.com/cover.jpg"}
+	bookmarkRepo := &MockBookmarkRepository{
+		getBookmarkFunc: func(id string) (model.Bookmark, error) {
+			if id != bookmark.ID {
+				return model.Bookmark{}, model.ErrNotFound
+			}
+			return bookmark, nil
+		},
+		updateBookmarkFunc: func(b model.Bookmark) (model.Bookmark, error) {
+			bookmark = b
+			return b, nil
+		},
+	}
+	blobStore := &MockBlobStore{}
+	service := NewThumbnailService(bookmarkRepo, &MockThumbnailer{}, blobStore, NewPolicy(&MockCollectionRepository{}), nil)
+
+	if _, err := service.GetThumbnail("user-1", "b1", "small"); !errors.Is(err, model.ErrNotFound) {
+		t.Errorf("GetThumbnail() before capture error = %v, want ErrNotFound", err)
+	}
+
+	updated, err := service.CaptureThumbnails("b1")
+	if err != nil {
+		t.Fatalf("CaptureThumbnails() unexpected error = %v", err)
+	}
+	if len(updated.Thumbnails) != 3 || updated.ThumbnailsAt.IsZero() || bookmark.Thumbnails["small"] != "thumbnails/b1/small.jpg" {
+		t.Errorf("CaptureThumbnails() bookmark = %+v, want thumbnails recorded", updated)
+	}
+	if len(blobStore.blobs) != 3 {
+		t.Errorf("stored blobs = %v, want one per size", blobStore.blobs)
+	}
+
+	thumbnail, err := service.GetThumbnail("user-1", "b1", "medium")
+	if err != nil {
+		t.Fatalf("GetThumbnail() unexpected error = %v", err)
+	}
+	if thumbnail.ContentType != "image/jpeg" || !thumbnail.UpdatedAt.Equal(updated.ThumbnailsAt) {
+		t.Errorf("GetThumbnail() = %+v, want a JPEG updated at %v", thumbnail, updated.ThumbnailsAt)
+	}
+
+	if _, err := service.GetThumbnail("user-1", "b1", "huge"); !errors.Is(err, model.ErrNotFound) {
+		t.Errorf("GetThumbnail() of an unknown size error = %v, want ErrNotFound", err)
+	}
+	if _, err := service.GetThumbnail("user-2", "b1", "small"); !errors.Is(err, model.ErrForbidden) {
+		t.Errorf("GetThumbnail() by another user error = %v, want ErrForbidden", err)
+	}
+}
+
+func TestThumbnailService_CaptureThumbnails_ReplacesFormat(t *testing.T) {
+	bookmark := model.Bookmark{ID: "b1", UserID: "user-1", URL: "https://example.com/post", MainImageURL: "https://cdn.example.com/cover.jpg"}
+	bookmarkRepo := &MockBookmarkRepository{
+		getBookmarkFunc: func(id string) (model.Bookmark, error) {
+			if id != bookmark.ID {
+				return model.Bookmark{}, model.ErrNotFound
+			}
+			return bookmark, nil
+		},
+		updateBookmarkFunc: func(b model.Bookmark) (model.Bookmark, error) {
+			bookmark = b
+			return b, nil
+		},
+	}
+	blobStore := &MockBlobStore{}
+	service := NewThumbnailService(bookmarkRepo, &MockThumbnailer{}, blobStore, NewPolicy(&MockCollectionRepository{}), nil)
+	if _, err := service.CaptureThumbnails("b1"); err != nil {
+		t.Fatalf("CaptureThumbnails() unexpected error = %v", err)
+	}
+
+	service.thumbnailer = &MockThumbnailer{
+		createThumbnailsFunc: func(ctx context.Context, url string, sizes []int) ([]model.Blob, error) {
+			blobs := make([]model.Blob, len(sizes))
+			for i := range sizes {
+				blobs[i] = model.Blob{ContentType: "image/png", Data: []byte(url)}
+			}
+			return blobs, nil
+		},
+	}
+	if _, err := service.CaptureThumbnails("b1"); err != nil {
+		t.Fatalf("CaptureThumbnails() unexpected error = %v", err)
+	}
+	if bookmark.Thumbnails["large"] != "thumbnails/b1/large.png" {
+		t.Errorf("large thumbnail key = %q, want the PNG", bookmark.Thumbnails["large"])
+	}
+	if _, ok := blobStore.blobs["thumbnails/b1/large.jpg"]; ok || len(blobStore.blobs) != 3 {
+		t.Errorf("stored blobs = %v, want only the PNG thumbnails", blobStore.blobs)
+	}
+}
+
+func TestThumbnailService_CaptureThumbnails_Fails(t *testing.T) {
+	bookmark := model.Bookmark{ID: "b1", UserID: "user-1", URL: "https://example.com/post", MainImageURL: "https://cdn.example.com/cover.jpg"}
+	bookmarkRepo := &MockBookmarkRepository{
+		getBookmarkFunc: func(id string) (model.Bookmark, error) {
+			if id != bookmark.ID {
+				return model.Bookmark{}, model.ErrNotFound
+			}
+			return bookmark, nil
+		},
+		updateBookmarkFunc: func(b model.Bookmark) (model.Bookmark, error) {
+			bookmark = b
+			return b, nil
+		},
+	}
+	blobStore := &MockBlobStore{}
+	service := NewThumbnailService(bookmarkRepo, &MockThumbnailer{}, blobStore, NewPolicy(&MockCollectionRepository{}), nil)
+	service.thumbnailer = &MockThumbnailer{
+		createThumbnailsFunc: func(ctx context.Context, url string, sizes []int) ([]model.Blob, error) {
+			return nil, errors.New("not an image")
+		},
+	}
+
+	if _, err := service.CaptureThumbnails("b1"); err == nil {
+		t.Error("CaptureThumbnails() should fail when the image cannot be thumbnailed")
+	}
+	if len(bookmark.Thumbnails) != 0 || len(blobStore.blobs) != 0 {
+		t.Errorf("failed capture left bookmark %+v and blobs %v", bookmark, blobStore.blobs)
+	}
+
+	bookmark.MainImageURL = ""
+	if _, err := service.CaptureThumbnails("b1"); !errors.Is(err, model.ErrNotFound) {
+		t.Errorf("CaptureThumbnails() without a main image error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestThumbnailService_PurgedBookmarkRemovesThumbnails(t *testing.T) {
+	bookmark := model.Bookmark{ID: "b1", UserID: "user-1", URL: "https://example.com/post", MainImageURL: "https://cdn.example.com/cover.jpg"}
+	bookmarkRepo := &MockBookmarkRepository{
+		getBookmarkFunc: func(id string) (model.Bookmark, error) {
+			if id != bookmark.ID {
+				return model.Bookmark{}, model.ErrNotFound
+			}
+			return bookmark, nil
+		},
+		updateBookmarkFunc: func(b model.Bookmark) (model.Bookmark, error) {
+			bookmark = b
+			return b, nil
+		},
+	}
+	blobStore := &MockBlobStore{}
+	service := NewThumbnailService(bookmarkRepo, &MockThumbnailer{}, blobStore, NewPolicy(&MockCollectionRepository{}), nil)
+	if _, err := service.CaptureThumbnails("b1"); err != nil {
+		t.Fatalf("CaptureThumbnails() unexpected error = %v", err)
+	}
+
+	bookmarkService := NewBookmarkService(bookmarkRepo, &MockUserRepository{}, &MockWebRepository{}, nil, NewPolicy(&MockCollectionRepository{}), nil, service, nil, nil)
+	if err := bookmarkService.DeleteBookmark("user-1", "b1", 0); err != nil {
+		t.Fatalf("DeleteBookmark() unexpected error = %v", err)
+	}
+	// Kept while the bookmark can be restored
+	if len(blobStore.blobs) == 0 {
+		t.Errorf("blobs after DeleteBookmark() = none, want them kept in the trash")
+	}
+
+	trashService := NewTrashService(bookmarkRepo, &MockHighlightRepository{}, &MockUserRepository{}, nil, NewPolicy(&MockCollectionRepository{}), nil, service, 0, nil)
+	if purged, err := trashService.EmptyTrash("user-1"); err != nil || purged != 1 {
+		t.Fatalf("EmptyTrash() = %d, %v, want 1 purged", purged, err)
+	}
+	if len(blobStore.blobs) != 0 {
+		t.Errorf("blobs after EmptyTrash() = %v, want none", blobStore.blobs)
+	}
+}
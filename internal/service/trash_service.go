@@ -0,0 +1,167 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/tsongpon/athena/internal/logger"
+	"github.com/tsongpon/athena/internal/model"
+	"go.uber.org/zap"
+)
+
+// trashPurgeBatchSize is the most bookmarks purged in one run; the rest wait for the next
+const trashPurgeBatchSize = 500
+
+// TrashService lists and restores deleted bookmarks and permanently deletes them once they
+// have been in the trash for the retention period
+type TrashService struct {
+	bookmarkRepository  BookmarkRepository
+	highlightRepository HighlightRepository
+	userRepository      UserRepository
+	entitlements        *EntitlementService
+	policy              *Policy
+	snapshots           *SnapshotService
+	thumbnails          *ThumbnailService
+	retention           time.Duration
+	events              *EventHub
+}
+
+// NewTrashService creates a new instance of TrashService. Bookmarks are purged once they have
+// been in the trash for retention. snapshots and thumbnails may be nil when no blob store is
+// configured, and events when change events are not streamed.
+func NewTrashService(bookmarkRepo BookmarkRepository, highlightRepo HighlightRepository, userRepo UserRepository, entitlements *EntitlementService, policy *Policy, snapshots *SnapshotService, thumbnails *ThumbnailService, retention time.Duration, events *EventHub) *TrashService {
+	return &TrashService{
+		bookmarkRepository:  bookmarkRepo,
+		highlightRepository: highlightRepo,
+		userRepository:      userRepo,
+		entitlements:        entitlements,
+		policy:              policy,
+		snapshots:           snapshots,
+		thumbnails:          thumbnails,
+		retention:           retention,
+		events:              events,
+	}
+}
+
+// ListTrash returns the deleted bookmarks of userID, most recently deleted first
+func (s *TrashService) ListTrash(userID string) ([]model.Bookmark, error) {
+	bookmarks, err := s.bookmarkRepository.ListTrashedBookmarks(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list trash of user %s: %w", userID, err)
+	}
+	return bookmarks, nil
+}
+
+// RestoreBookmark moves a bookmark out of the trash on behalf of userID, who must own it.
+// Restoring counts against the bookmark quota like creating.
+func (s *TrashService) RestoreBookmark(userID, id string) (model.Bookmark, error) {
+	b, err := s.bookmarkRepository.GetTrashedBookmark(id)
+	if err != nil {
+		return model.Bookmark{}, fmt.Errorf("failed to get trashed bookmark with ID %s: %w", id, err)
+	}
+	if err := s.policy.CanModifyBookmark(userID, b); err != nil {
+		return model.Bookmark{}, err
+	}
+	user, err := s.userRepository.GetUserByID(userID)
+	if err != nil {
+		return model.Bookmark{}, fmt.Errorf("failed to fetch user for ID %s: %w", userID, err)
+	}
+	if err := s.entitlements.CheckBookmarkQuota(user); err != nil {
+		return model.Bookmark{}, err
+	}
+
+	restored, err := s.bookmarkRepository.RestoreBookmark(id)
+	if err != nil {
+		return model.Bookmark{}, fmt.Errorf("failed to restore bookmark with ID %s: %w", id, err)
+	}
+	logger.Info("Restored bookmark from the trash", zap.String("user_id", userID), zap.String("bookmark_id", id))
+	s.events.Publish(model.EventBookmarkCreated, restored)
+	return restored, nil
+}
+
+// EmptyTrash permanently deletes every bookmark in the trash of userID and returns how many
+// were deleted
+func (s *TrashService) EmptyTrash(userID string) (int, error) {
+	bookmarks, err := s.ListTrash(userID)
+	if err != nil {
+		return 0, err
+	}
+	purged := 0
+	for _, b := range bookmarks {
+		err := s.purge(b)
+		if errors.Is(err, model.ErrNotFound) {
+			// Restored or purged meanwhile
+			continue
+		}
+		if err != nil {
+			return purged, err
+		}
+		purged++
+	}
+	logger.Info("Emptied trash", zap.String("user_id", userID), zap.Int("purged", purged))
+	return purged, nil
+}
+
+// Run purges expired bookmarks immediately and then every interval until ctx is cancelled
+func (s *TrashService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if purged, err := s.PurgeExpired(ctx); err != nil {
+			logger.Error("Trash purge failed", zap.Error(err))
+		} else if purged > 0 {
+			logger.Info("Trash purge finished", zap.Int("purged", purged))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// PurgeExpired permanently deletes one batch of bookmarks that have been in the trash for
+// longer than the retention period and returns how many were deleted
+func (s *TrashService) PurgeExpired(ctx context.Context) (int, error) {
+	expired, err := s.bookmarkRepository.ListTrashedBefore(time.Now().Add(-s.retention), trashPurgeBatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list expired trashed bookmarks: %w", err)
+	}
+
+	purged := 0
+	for _, b := range expired {
+		if ctx.Err() != nil {
+			break
+		}
+		err := s.purge(b)
+		if errors.Is(err, model.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			logger.Warn("failed to purge trashed bookmark", zap.String("bookmark_id", b.ID), zap.Error(err))
+			continue
+		}
+		purged++
+	}
+	return purged, ctx.Err()
+}
+
+// purge permanently deletes a trashed bookmark together with its snapshot and thumbnails
+func (s *TrashService) purge(b model.Bookmark) error {
+	if err := s.bookmarkRepository.PurgeBookmark(b.ID); err != nil {
+		return fmt.Errorf("failed to purge bookmark with ID %s: %w", b.ID, err)
+	}
+	if err := s.highlightRepository.DeleteBookmarkHighlights(b.ID); err != nil {
+		logger.Warn("Failed to delete highlights of purged bookmark", zap.String("bookmark_id", b.ID), zap.Error(err))
+	}
+	if s.snapshots != nil {
+		s.snapshots.DeleteSnapshot(b)
+	}
+	if s.thumbnails != nil {
+		s.thumbnails.DeleteThumbnails(b)
+	}
+	return nil
+}
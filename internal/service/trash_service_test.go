@@ -0,0 +1,129 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/tsongpon/athena/internal/model"
+)
+
+func TestTrashService_ListTrash(t *testing.T) {
+	now := time.Now()
+	bookmarkRepo := &MockBookmarkRepository{trash: map[string]model.Bookmark{
+		"older":   {ID: "older", UserID: "user-1", DeletedAt: now.Add(-time.Hour)},
+		"newer":   {ID: "newer", UserID: "user-1", DeletedAt: now},
+		"foreign": {ID: "foreign", UserID: "user-2", DeletedAt: now},
+	}}
+	service := NewTrashService(bookmarkRepo, &MockHighlightRepository{}, &MockUserRepository{}, nil, NewPolicy(&MockCollectionRepository{}), nil, nil, 30*24*time.Hour, nil)
+
+	trashed, err := service.ListTrash("user-1")
+	if err != nil {
+		t.Fatalf("ListTrash() unexpected error = %v", err)
+	}
+	if len(trashed) != 2 || trashed[0].ID != "newer" || trashed[1].ID != "older" {
+		t.Errorf("ListTrash() = %+v, want newer then older", trashed)
+	}
+}
+
+func TestTrashService_RestoreBookmark(t *testing.T) {
+	bookmarkRepo := &MockBookmarkRepository{trash: map[string]model.Bookmark{
+		"b1":      {ID: "b1", UserID: "user-1", DeletedAt: time.Now()},
+		"foreign": {ID: "foreign", UserID: "user-2", DeletedAt: time.Now()},
+	}}
+	userRepo := &MockUserRepository{
+		getUserByIDFunc: func(id string) (model.User, error) {
+			return model.User{ID: id, Tier: model.TierFree}, nil
+		},
+	}
+	entitlements := NewEntitlementService(userRepo, bookmarkRepo, &MockUsageRepository{})
+	service := NewTrashService(bookmarkRepo, &MockHighlightRepository{}, userRepo, entitlements, NewPolicy(&MockCollectionRepository{}), nil, nil, 30*24*time.Hour, nil)
+
+	if _, err := service.RestoreBookmark("user-1", "foreign"); !errors.Is(err, model.ErrForbidden) {
+		t.Errorf("RestoreBookmark() of another user's bookmark error = %v, want ErrForbidden", err)
+	}
+	if _, err := service.RestoreBookmark("user-1", "missing"); !errors.Is(err, model.ErrNotFound) {
+		t.Errorf("RestoreBookmark() of missing bookmark error = %v, want ErrNotFound", err)
+	}
+
+	restored, err := service.RestoreBookmark("user-1", "b1")
+	if err != nil {
+		t.Fatalf("RestoreBookmark() unexpected error = %v", err)
+	}
+	if restored.ID != "b1" || !restored.DeletedAt.IsZero() {
+		t.Errorf("RestoreBookmark() = %+v, want b1 out of the trash", restored)
+	}
+	if _, ok := bookmarkRepo.trash["b1"]; ok {
+		t.Error("RestoreBookmark() left the bookmark in the trash")
+	}
+}
+
+func TestTrashService_RestoreBookmark_QuotaExceeded(t *testing.T) {
+	bookmarkRepo := &MockBookmarkRepository{
+		trash: map[string]model.Bookmark{"b1": {ID: "b1", UserID: "user-1", DeletedAt: time.Now()}},
+		countBookmarksFunc: func(query model.BookmarkQuery) (int, error) {
+			if query.Archived {
+				return 0, nil
+			}
+			return 200, nil
+		},
+	}
+	userRepo := &MockUserRepository{
+		getUserByIDFunc: func(id string) (model.User, error) {
+			return model.User{ID: id, Tier: model.TierFree}, nil
+		},
+	}
+	entitlements := NewEntitlementService(userRepo, bookmarkRepo, &MockUsageRepository{})
+	service := NewTrashService(bookmarkRepo, &MockHighlightRepository{}, userRepo, entitlements, NewPolicy(&MockCollectionRepository{}), nil, nil, 30*24*time.Hour, nil)
+
+	if _, err := service.RestoreBookmark("user-1", "b1"); !errors.Is(err, model.ErrQuotaExceeded) {
+		t.Errorf("RestoreBookmark() over quota error = %v, want ErrQuotaExceeded", err)
+	}
+	if _, ok := bookmarkRepo.trash["b1"]; !ok {
+		t.Error("RestoreBookmark() over quota took the bookmark out of the trash")
+	}
+}
+
+func TestTrashService_EmptyTrash(t *testing.T) {
+	bookmarkRepo := &MockBookmarkRepository{trash: map[string]model.Bookmark{
+		"b1":      {ID: "b1", UserID: "user-1", DeletedAt: time.Now()},
+		"b2":      {ID: "b2", UserID: "user-1", DeletedAt: time.Now()},
+		"foreign": {ID: "foreign", UserID: "user-2", DeletedAt: time.Now()},
+	}}
+	highlightRepo := &MockHighlightRepository{}
+	service := NewTrashService(bookmarkRepo, highlightRepo, &MockUserRepository{}, nil, NewPolicy(&MockCollectionRepository{}), nil, nil, 30*24*time.Hour, nil)
+	highlightRepo.CreateHighlight(model.Highlight{BookmarkID: "b1", Quote: "gone with the bookmark"})
+	highlightRepo.CreateHighlight(model.Highlight{BookmarkID: "foreign", Quote: "kept"})
+
+	purged, err := service.EmptyTrash("user-1")
+	if err != nil {
+		t.Fatalf("EmptyTrash() unexpected error = %v", err)
+	}
+	if purged != 2 || len(bookmarkRepo.trash) != 1 {
+		t.Errorf("EmptyTrash() purged %d leaving %v, want 2 purged and the other user's bookmark kept", purged, bookmarkRepo.trash)
+	}
+	if len(highlightRepo.highlights) != 1 {
+		t.Errorf("highlights after EmptyTrash() = %v, want only those of the other user's bookmark", highlightRepo.highlights)
+	}
+}
+
+func TestTrashService_PurgeExpired(t *testing.T) {
+	now := time.Now()
+	bookmarkRepo := &MockBookmarkRepository{trash: map[string]model.Bookmark{
+		"expired": {ID: "expired", UserID: "user-1", DeletedAt: now.Add(-31 * 24 * time.Hour)},
+		"recent":  {ID: "recent", UserID: "user-2", DeletedAt: now.Add(-24 * time.Hour)},
+	}}
+	service := NewTrashService(bookmarkRepo, &MockHighlightRepository{}, &MockUserRepository{}, nil, NewPolicy(&MockCollectionRepository{}), nil, nil, 30*24*time.Hour, nil)
+
+	purged, err := service.PurgeExpired(context.Background())
+	if err != nil {
+		t.Fatalf("PurgeExpired() unexpected error = %v", err)
+	}
+	if purged != 1 {
+		t.Errorf("PurgeExpired() purged %d, want 1", purged)
+	}
+	if _, ok := bookmarkRepo.trash["recent"]; !ok || len(bookmarkRepo.trash) != 1 {
+		t.Errorf("trash after PurgeExpired() = %v, want only the recent bookmark", bookmarkRepo.trash)
+	}
+}
@@ -1,22 +1,42 @@
 package service
 
 import (
+	"errors"
 	"fmt"
+	"os"
+	"strings"
+	"time"
 
+	"github.com/tsongpon/athena/internal/logger"
 	"github.com/tsongpon/athena/internal/model"
+	"go.uber.org/zap"
 	"golang.org/x/crypto/bcrypt"
 )
 
 type UserService struct {
-	repo UserRepository
+	repo        UserRepository
+	adminEmails []string
 }
 
 func NewUserService(repo UserRepository) *UserService {
 	return &UserService{
-		repo: repo,
+		repo:        repo,
+		adminEmails: parseAdminEmails(os.Getenv("ADMIN_EMAILS")),
 	}
 }
 
+// parseAdminEmails splits the comma separated ADMIN_EMAILS value into a list of emails
+func parseAdminEmails(value string) []string {
+	var emails []string
+	for _, email := range strings.Split(value, ",") {
+		email = strings.TrimSpace(email)
+		if email != "" {
+			emails = append(emails, email)
+		}
+	}
+	return emails
+}
+
 // CreateUser creates a new user with hashed password
 func (s *UserService) CreateUser(user model.User) (model.User, error) {
 	// Validate required fields
@@ -46,7 +66,8 @@ func (s *UserService) CreateUser(user model.User) (model.User, error) {
 		return model.User{}, fmt.Errorf("failed to hash password: %w", err)
 	}
 	user.Password = string(hashedPassword)
-	user.Tier = "free"
+	user.Tier = model.TierFree
+	user.Role = model.RoleUser
 
 	// Create user in repository
 	created, err := s.repo.CreateUser(user)
@@ -79,5 +100,56 @@ func (s *UserService) AuthenticateUser(email, plainPassword string) (model.User,
 		return model.User{}, fmt.Errorf("invalid email or password")
 	}
 
+	if user.Disabled {
+		return model.User{}, fmt.Errorf("account is disabled: %w", model.ErrForbidden)
+	}
+
 	return user, nil
 }
+
+// GetUser retrieves a user by ID
+func (s *UserService) GetUser(id string) (model.User, error) {
+	if id == "" {
+		return model.User{}, fmt.Errorf("id is required")
+	}
+	user, err := s.repo.GetUserByID(id)
+	if err != nil {
+		return model.User{}, fmt.Errorf("failed to get user with ID %s: %w", id, err)
+	}
+
+	return user, nil
+}
+
+// errUserUnchanged stops a ModifyUser change that has nothing to store
+var errUserUnchanged = errors.New("user unchanged")
+
+// BootstrapAdmins promotes already registered users listed in ADMIN_EMAILS to admins. Each
+// account is promoted once: accounts promoted before are left alone, so an admin can still
+// demote them. Listed emails are never granted the admin role at signup, since anyone can
+// register an email address they do not own.
+func (s *UserService) BootstrapAdmins() error {
+	for _, email := range s.adminEmails {
+		user, err := s.repo.GetUserByEmail(email)
+		if err != nil {
+			logger.Info("Bootstrap admin not registered yet", zap.String("email", email))
+			continue
+		}
+		_, err = s.repo.ModifyUser(user.ID, func(u *model.User) error {
+			if !u.AdminBootstrappedAt.IsZero() {
+				return errUserUnchanged
+			}
+			u.Role = model.RoleAdmin
+			u.AdminBootstrappedAt = time.Now()
+			return nil
+		})
+		if errors.Is(err, errUserUnchanged) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to promote %s to admin: %w", email, err)
+		}
+		logger.Info("Promoted bootstrap admin", zap.String("user_id", user.ID), zap.String("email", email))
+	}
+
+	return nil
+}
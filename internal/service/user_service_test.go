@@ -1,9 +1,11 @@
 package service
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/tsongpon/athena/internal/model"
 	"golang.org/x/crypto/bcrypt"
@@ -502,3 +504,88 @@ func TestNewUserService(t *testing.T) {
 		t.Error("NewUserService() should set repository")
 	}
 }
+
+// TestUserService_CreateUser_BootstrapAdmin tests that emails listed in ADMIN_EMAILS do not get
+// the admin role at signup
+func TestUserService_CreateUser_BootstrapAdmin(t *testing.T) {
+	t.Setenv("ADMIN_EMAILS", "ops@example.com, root@example.com")
+
+	mockRepo := &MockUserRepository{
+		createUserFunc: func(user model.User) (model.User, error) {
+			return user, nil
+		},
+	}
+
+	service := NewUserService(mockRepo)
+	admin, err := service.CreateUser(model.User{Name: "Root", Email: "ROOT@example.com", Password: "secret"})
+	if err != nil {
+		t.Fatalf("CreateUser() unexpected error = %v", err)
+	}
+	if admin.Role != model.RoleUser {
+		t.Errorf("CreateUser() Role = %v, want %v", admin.Role, model.RoleUser)
+	}
+}
+
+// TestUserService_BootstrapAdmins tests promotion of already registered users
+func TestUserService_BootstrapAdmins(t *testing.T) {
+	t.Setenv("ADMIN_EMAILS", "ops@example.com,demoted@example.com,missing@example.com")
+
+	users := []model.User{
+		{ID: "user-1", Email: "ops@example.com", Role: model.RoleUser},
+		// Promoted on an earlier startup, then demoted by an admin
+		{ID: "user-2", Email: "demoted@example.com", Role: model.RoleUser, AdminBootstrappedAt: time.Now().Add(-time.Hour)},
+	}
+	var promoted []string
+	mockRepo := &MockUserRepository{
+		getUserByEmailFunc: func(email string) (model.User, error) {
+			for _, u := range users {
+				if u.Email == email {
+					return u, nil
+				}
+			}
+			return model.User{}, fmt.Errorf("user with email %s not found", email)
+		},
+		getUserByIDFunc: func(id string) (model.User, error) {
+			for _, u := range users {
+				if u.ID == id {
+					return u, nil
+				}
+			}
+			return model.User{}, model.ErrNotFound
+		},
+		updateUserFunc: func(user model.User) (model.User, error) {
+			promoted = append(promoted, user.ID)
+			if user.Role != model.RoleAdmin {
+				t.Errorf("BootstrapAdmins() Role = %v, want %v", user.Role, model.RoleAdmin)
+			}
+			if user.AdminBootstrappedAt.IsZero() {
+				t.Error("BootstrapAdmins() should record when the user was promoted")
+			}
+			return user, nil
+		},
+	}
+
+	service := NewUserService(mockRepo)
+	if err := service.BootstrapAdmins(); err != nil {
+		t.Fatalf("BootstrapAdmins() unexpected error = %v", err)
+	}
+	if len(promoted) != 1 || promoted[0] != "user-1" {
+		t.Errorf("BootstrapAdmins() promoted = %v, want [user-1]", promoted)
+	}
+}
+
+// TestUserService_AuthenticateUser_Disabled tests that disabled accounts cannot log in
+func TestUserService_AuthenticateUser_Disabled(t *testing.T) {
+	hashed, _ := bcrypt.GenerateFromPassword([]byte("secret"), bcrypt.MinCost)
+	mockRepo := &MockUserRepository{
+		getUserByEmailFunc: func(email string) (model.User, error) {
+			return model.User{ID: "user-1", Email: email, Password: string(hashed), Disabled: true}, nil
+		},
+	}
+
+	service := NewUserService(mockRepo)
+	_, err := service.AuthenticateUser("jane@example.com", "secret")
+	if !errors.Is(err, model.ErrForbidden) {
+		t.Errorf("AuthenticateUser() error = %v, want ErrForbidden", err)
+	}
+}
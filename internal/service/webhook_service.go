@@ -0,0 +1,508 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/tsongpon/athena/internal/logger"
+	"github.com/tsongpon/athena/internal/model"
+	"go.uber.org/zap"
+)
+
+const (
+	// webhookBatchSize is the most deliveries sent in one run; the rest wait for the next
+	webhookBatchSize = 200
+	// webhookWorkers is the number of webhooks sent to concurrently. The deliveries of one
+	// webhook are sent in order, one at a time.
+	webhookWorkers = 8
+	// webhookRetryDelay is the wait before the first retry; it doubles with every attempt
+	webhookRetryDelay = 30 * time.Second
+	// webhookClaim is how long a delivery is reserved for the instance sending it. It is
+	// retried once the claim lapses if the instance stops before recording the outcome.
+	webhookClaim = 2 * time.Minute
+	// webhookCacheTTL is how long the webhooks of a user are reused to queue events. Changes
+	// made through this instance apply at once; those made through others within the TTL.
+	webhookCacheTTL = time.Minute
+)
+
+// Headers of webhook requests
+const (
+	WebhookEventHeader     = "X-Athena-Event"
+	WebhookDeliveryHeader  = "X-Athena-Delivery" // Stays the same across retries, for deduplication
+	WebhookSignatureHeader = "X-Athena-Signature-256"
+)
+
+// WebhookService manages the webhooks of users and delivers bookmark events to them
+type WebhookService struct {
+	webhookRepository WebhookRepository
+	sender            WebhookSender
+	policy            *Policy
+
+	// Webhooks by user ID, so that events of users without webhooks, the common case, do
+	// not each read the repository on the request path
+	cacheMutex     sync.Mutex
+	cache          map[string]webhookCacheEntry
+	cacheExpiredAt time.Time // When expired entries were last dropped
+	cacheVersion   int64     // Increases with every change, so reads from before it are not cached
+}
+
+type webhookCacheEntry struct {
+	webhooks  []model.Webhook
+	expiresAt time.Time
+}
+
+// NewWebhookService creates a new instance of WebhookService
+func NewWebhookService(webhookRepo WebhookRepository, sender WebhookSender, policy *Policy) *WebhookService {
+	return &WebhookService{
+		webhookRepository: webhookRepo,
+		sender:            sender,
+		policy:            policy,
+		cache:             make(map[string]webhookCacheEntry),
+	}
+}
+
+// webhookPayload is the body of webhook requests
+type webhookPayload struct {
+	ID         string           `json:"id"` // Event ID
+	Type       string           `json:"type"`
+	BookmarkID string           `json:"bookmark_id"`
+	Bookmark   *webhookBookmark `json:"bookmark,omitempty"` // Omitted for deleted bookmarks
+	CreatedAt  time.Time        `json:"created_at"`
+}
+
+type webhookBookmark struct {
+	ID             string    `json:"id"`
+	URL            string    `json:"url"`
+	Title          string    `json:"title"`
+	MainImageURL   string    `json:"main_image_url,omitempty"`
+	ContentSummary string    `json:"content_summary,omitempty"`
+	Tags           []string  `json:"tags,omitempty"`
+	CollectionIDs  []string  `json:"collection_ids,omitempty"`
+	IsArchived     bool      `json:"is_archived"`
+	IsRead         bool      `json:"is_read"`
+	IsFavorite     bool      `json:"is_favorite"`
+	Notes          string    `json:"notes,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+func newWebhookPayload(event model.Event) webhookPayload {
+	payload := webhookPayload{
+		ID:         event.ID,
+		Type:       event.Type,
+		BookmarkID: event.Bookmark.ID,
+		CreatedAt:  event.At,
+	}
+	if event.Type != model.EventBookmarkDeleted {
+		b := event.Bookmark
+		payload.Bookmark = &webhookBookmark{
+			ID:             b.ID,
+			URL:            b.URL,
+			Title:          b.Title,
+			MainImageURL:   b.MainImageURL,
+			ContentSummary: b.ContentSummary,
+			Tags:           b.Tags,
+			CollectionIDs:  b.CollectionIDs,
+			IsArchived:     b.IsArchived,
+			IsRead:         b.IsRead,
+			IsFavorite:     b.IsFavorite,
+			Notes:          b.Notes,
+			CreatedAt:      b.CreatedAt,
+			UpdatedAt:      b.UpdatedAt,
+		}
+	}
+	return payload
+}
+
+// CreateWebhook registers a webhook for userID that receives events of the given types, or
+// all of them when events is empty. The returned webhook holds its signing secret.
+func (s *WebhookService) CreateWebhook(userID, rawURL string, events []string) (model.Webhook, error) {
+	if err := validateWebhookURL(rawURL); err != nil {
+		return model.Webhook{}, err
+	}
+	events, err := normalizeWebhookEvents(events)
+	if err != nil {
+		return model.Webhook{}, err
+	}
+	webhooks, err := s.webhookRepository.ListWebhooks(userID)
+	if err != nil {
+		return model.Webhook{}, fmt.Errorf("failed to list webhooks: %w", err)
+	}
+	if len(webhooks) >= model.MaxWebhooks {
+		return model.Webhook{}, fmt.Errorf("a user can have at most %d webhooks: %w", model.MaxWebhooks, model.ErrInvalidInput)
+	}
+	secret, err := newRandomToken(32)
+	if err != nil {
+		return model.Webhook{}, fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+
+	webhook, err := s.webhookRepository.CreateWebhook(model.Webhook{
+		UserID: userID,
+		URL:    rawURL,
+		Events: events,
+		Secret: "whsec_" + secret,
+		Active: true,
+	})
+	if err != nil {
+		return model.Webhook{}, fmt.Errorf("failed to create webhook: %w", err)
+	}
+	s.forgetWebhooks(userID)
+	logger.Info("Created webhook", zap.String("user_id", userID), zap.String("webhook_id", webhook.ID))
+	return webhook, nil
+}
+
+// ListWebhooks returns the webhooks of userID, oldest first
+func (s *WebhookService) ListWebhooks(userID string) ([]model.Webhook, error) {
+	webhooks, err := s.webhookRepository.ListWebhooks(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhooks: %w", err)
+	}
+	return webhooks, nil
+}
+
+// UpdateWebhook changes the URL, event types or state of a webhook userID owns
+func (s *WebhookService) UpdateWebhook(userID, id string, update model.WebhookUpdate) (model.Webhook, error) {
+	if update.URL == nil && update.Events == nil && update.Active == nil {
+		return model.Webhook{}, fmt.Errorf("url, events or active is required: %w", model.ErrInvalidInput)
+	}
+	if _, err := s.getWebhook(userID, id); err != nil {
+		return model.Webhook{}, err
+	}
+
+	if update.URL != nil {
+		if err := validateWebhookURL(*update.URL); err != nil {
+			return model.Webhook{}, err
+		}
+	}
+	if update.Events != nil {
+		events, err := normalizeWebhookEvents(*update.Events)
+		if err != nil {
+			return model.Webhook{}, err
+		}
+		update.Events = &events
+	}
+
+	// Applied to the stored webhook, so delivery results recorded since it was read are kept
+	updated, err := s.webhookRepository.UpdateWebhook(id, update)
+	if err != nil {
+		return model.Webhook{}, fmt.Errorf("failed to update webhook with ID %s: %w", id, err)
+	}
+	s.forgetWebhooks(userID)
+	return updated, nil
+}
+
+// DeleteWebhook deletes a webhook userID owns along with its deliveries
+func (s *WebhookService) DeleteWebhook(userID, id string) error {
+	if _, err := s.getWebhook(userID, id); err != nil {
+		return err
+	}
+	if err := s.webhookRepository.DeleteWebhook(id); err != nil {
+		return fmt.Errorf("failed to delete webhook with ID %s: %w", id, err)
+	}
+	s.forgetWebhooks(userID)
+	logger.Info("Deleted webhook", zap.String("user_id", userID), zap.String("webhook_id", id))
+	return nil
+}
+
+// ListDeliveries returns the latest deliveries of a webhook userID owns, newest first.
+// limit defaults to 50 and is capped at model.MaxWebhookDeliveryLog.
+func (s *WebhookService) ListDeliveries(userID, webhookID string, limit int) ([]model.WebhookDelivery, error) {
+	if _, err := s.getWebhook(userID, webhookID); err != nil {
+		return nil, err
+	}
+	if limit < 1 || limit > model.MaxWebhookDeliveryLog {
+		limit = 50
+	}
+	deliveries, err := s.webhookRepository.ListDeliveries(webhookID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deliveries of webhook %s: %w", webhookID, err)
+	}
+	return deliveries, nil
+}
+
+// HandleEvent queues an event for the webhooks of its owner that subscribe to it. It is
+// registered as a listener of the EventHub, so failures are logged rather than returned.
+func (s *WebhookService) HandleEvent(event model.Event) {
+	webhooks, err := s.cachedWebhooks(event.UserID)
+	if err != nil {
+		logger.Error("Failed to list webhooks for event",
+			zap.String("user_id", event.UserID),
+			zap.String("event_id", event.ID),
+			zap.Error(err))
+		return
+	}
+
+	var payload []byte
+	for _, webhook := range webhooks {
+		if !webhook.Subscribes(event.Type) {
+			continue
+		}
+		if payload == nil {
+			if payload, err = json.Marshal(newWebhookPayload(event)); err != nil {
+				logger.Error("Failed to encode webhook payload", zap.String("event_id", event.ID), zap.Error(err))
+				return
+			}
+		}
+		_, err := s.webhookRepository.CreateDelivery(model.WebhookDelivery{
+			WebhookID:     webhook.ID,
+			UserID:        event.UserID,
+			EventID:       event.ID,
+			EventType:     event.Type,
+			Payload:       payload,
+			Status:        model.WebhookDeliveryPending,
+			NextAttemptAt: event.At,
+		})
+		if err != nil {
+			logger.Error("Failed to queue webhook delivery",
+				zap.String("webhook_id", webhook.ID),
+				zap.String("event_id", event.ID),
+				zap.Error(err))
+		}
+	}
+}
+
+// cachedWebhooks returns the webhooks of a user, reading them from the repository at most once
+// per webhookCacheTTL
+func (s *WebhookService) cachedWebhooks(userID string) ([]model.Webhook, error) {
+	now := time.Now()
+	s.cacheMutex.Lock()
+	entry, ok := s.cache[userID]
+	version := s.cacheVersion
+	s.cacheMutex.Unlock()
+	if ok && now.Before(entry.expiresAt) {
+		return entry.webhooks, nil
+	}
+
+	webhooks, err := s.webhookRepository.ListWebhooks(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.cacheMutex.Lock()
+	defer s.cacheMutex.Unlock()
+	if version != s.cacheVersion {
+		// A webhook changed while they were read
+		return webhooks, nil
+	}
+	if now.Sub(s.cacheExpiredAt) >= webhookCacheTTL {
+		for id, entry := range s.cache {
+			if !now.Before(entry.expiresAt) {
+				delete(s.cache, id)
+			}
+		}
+		s.cacheExpiredAt = now
+	}
+	s.cache[userID] = webhookCacheEntry{webhooks: webhooks, expiresAt: now.Add(webhookCacheTTL)}
+	return webhooks, nil
+}
+
+// forgetWebhooks drops the cached webhooks of a user after one of them changed
+func (s *WebhookService) forgetWebhooks(userID string) {
+	s.cacheMutex.Lock()
+	defer s.cacheMutex.Unlock()
+	delete(s.cache, userID)
+	s.cacheVersion++
+}
+
+// Run sends due deliveries immediately and then every interval until ctx is cancelled
+func (s *WebhookService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if sent, err := s.SendDueDeliveries(ctx); err != nil {
+			logger.Error("Webhook delivery failed", zap.Error(err))
+		} else if sent > 0 {
+			logger.Info("Webhook delivery finished", zap.Int("sent", sent))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// SendDueDeliveries sends one batch of pending deliveries that are due and returns how many
+// were attempted. Deliveries another instance claimed first are skipped.
+func (s *WebhookService) SendDueDeliveries(ctx context.Context) (int, error) {
+	now := time.Now()
+	due, err := s.webhookRepository.ListDueDeliveries(now, webhookBatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list due webhook deliveries: %w", err)
+	}
+
+	byWebhook := make(map[string][]model.WebhookDelivery)
+	var webhookIDs []string
+	for _, delivery := range due {
+		if _, ok := byWebhook[delivery.WebhookID]; !ok {
+			webhookIDs = append(webhookIDs, delivery.WebhookID)
+		}
+		byWebhook[delivery.WebhookID] = append(byWebhook[delivery.WebhookID], delivery)
+	}
+
+	var sent sync.WaitGroup
+	var mutex sync.Mutex
+	attempted := 0
+	workers := make(chan struct{}, webhookWorkers)
+	for _, webhookID := range webhookIDs {
+		workers <- struct{}{}
+		sent.Go(func() {
+			defer func() { <-workers }()
+			for _, delivery := range byWebhook[webhookID] {
+				if ctx.Err() != nil {
+					return
+				}
+				claimed, err := s.webhookRepository.ClaimDelivery(delivery.ID, now, time.Now().Add(webhookClaim))
+				if errors.Is(err, model.ErrConflict) || errors.Is(err, model.ErrNotFound) {
+					continue
+				}
+				if err != nil {
+					logger.Warn("Failed to claim webhook delivery", zap.String("delivery_id", delivery.ID), zap.Error(err))
+					continue
+				}
+				s.attempt(ctx, claimed)
+				mutex.Lock()
+				attempted++
+				mutex.Unlock()
+			}
+		})
+	}
+	sent.Wait()
+	return attempted, nil
+}
+
+// attempt sends a claimed delivery and records the outcome on it and on its webhook
+func (s *WebhookService) attempt(ctx context.Context, delivery model.WebhookDelivery) {
+	webhook, err := s.webhookRepository.GetWebhook(delivery.WebhookID)
+	if errors.Is(err, model.ErrNotFound) {
+		// Deleted meanwhile, along with its deliveries
+		return
+	}
+	if err != nil {
+		logger.Warn("Failed to get webhook of delivery", zap.String("delivery_id", delivery.ID), zap.Error(err))
+		return
+	}
+	if !webhook.Active {
+		delivery.Status = model.WebhookDeliveryFailed
+		delivery.Error = "webhook is disabled"
+		s.saveDelivery(delivery)
+		return
+	}
+
+	now := time.Now()
+	status, err := s.sender.SendWebhook(ctx, signWebhookRequest(webhook, delivery))
+	delivery.Attempts++
+	delivery.LastAttemptAt = now
+	delivery.ResponseStatus = status
+	delivery.Error = ""
+	switch {
+	case err != nil:
+		delivery.Error = err.Error()
+	case status < 200 || status > 299:
+		delivery.Error = fmt.Sprintf("unexpected response status %d", status)
+	}
+
+	if delivery.Error == "" {
+		delivery.Status = model.WebhookDeliverySucceeded
+		s.saveDelivery(delivery)
+		if webhook.ConsecutiveFailures > 0 {
+			s.recordWebhookResult(webhook.ID, true, now)
+		}
+		return
+	}
+
+	if delivery.Attempts >= model.MaxWebhookAttempts {
+		delivery.Status = model.WebhookDeliveryFailed
+	} else {
+		delivery.NextAttemptAt = now.Add(webhookRetryDelay << (delivery.Attempts - 1))
+	}
+	s.saveDelivery(delivery)
+
+	updated, ok := s.recordWebhookResult(webhook.ID, false, now)
+	if ok && !updated.Active && updated.DisabledAt.Equal(now) {
+		s.forgetWebhooks(updated.UserID)
+		logger.Warn("Disabled failing webhook",
+			zap.String("webhook_id", updated.ID),
+			zap.String("user_id", updated.UserID),
+			zap.Int("failures", updated.ConsecutiveFailures))
+	}
+}
+
+func (s *WebhookService) saveDelivery(delivery model.WebhookDelivery) {
+	if _, err := s.webhookRepository.UpdateDelivery(delivery); err != nil {
+		logger.Error("Failed to record webhook delivery", zap.String("delivery_id", delivery.ID), zap.Error(err))
+	}
+}
+
+// recordWebhookResult updates the failure count of a webhook after an attempt. Only the failure
+// fields are written, so changes the owner made during the attempt are kept.
+func (s *WebhookService) recordWebhookResult(id string, succeeded bool, at time.Time) (model.Webhook, bool) {
+	webhook, err := s.webhookRepository.RecordWebhookResult(id, succeeded, at)
+	if err != nil {
+		logger.Error("Failed to record webhook failures", zap.String("webhook_id", id), zap.Error(err))
+		return model.Webhook{}, false
+	}
+	return webhook, true
+}
+
+// signWebhookRequest builds the request of a delivery. The signature is the hex encoded
+// HMAC-SHA256 of the body with the webhook secret as key.
+func signWebhookRequest(webhook model.Webhook, delivery model.WebhookDelivery) model.WebhookRequest {
+	mac := hmac.New(sha256.New, []byte(webhook.Secret))
+	mac.Write(delivery.Payload)
+	return model.WebhookRequest{
+		URL: webhook.URL,
+		Headers: map[string]string{
+			"Content-Type":         "application/json",
+			WebhookEventHeader:     delivery.EventType,
+			WebhookDeliveryHeader:  delivery.ID,
+			WebhookSignatureHeader: "sha256=" + hex.EncodeToString(mac.Sum(nil)),
+		},
+		Body: delivery.Payload,
+	}
+}
+
+// getWebhook returns a webhook userID owns
+func (s *WebhookService) getWebhook(userID, id string) (model.Webhook, error) {
+	webhook, err := s.webhookRepository.GetWebhook(id)
+	if err != nil {
+		return model.Webhook{}, fmt.Errorf("failed to get webhook with ID %s: %w", id, err)
+	}
+	if err := s.policy.CanManageWebhook(userID, webhook); err != nil {
+		return model.Webhook{}, err
+	}
+	return webhook, nil
+}
+
+// validateWebhookURL checks that rawURL is an absolute http or https URL
+func validateWebhookURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return fmt.Errorf("url must be an absolute http or https URL: %w", model.ErrInvalidInput)
+	}
+	return nil
+}
+
+// normalizeWebhookEvents checks and deduplicates event types
+func normalizeWebhookEvents(events []string) ([]string, error) {
+	var normalized []string
+	for _, event := range events {
+		if !slices.Contains(model.WebhookEvents, event) {
+			return nil, fmt.Errorf("unknown event type %q: %w", event, model.ErrInvalidInput)
+		}
+		if !slices.Contains(normalized, event) {
+			normalized = append(normalized, event)
+		}
+	}
+	return normalized, nil
+}
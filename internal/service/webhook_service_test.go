@@ -0,0 +1,473 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/tsongpon/athena/internal/model"
+)
+
+// MockWebhookRepository is a map-backed mock of WebhookRepository; deliveries are sent
+// concurrently, so it is guarded by a mutex
+type MockWebhookRepository struct {
+	mutex      sync.Mutex
+	webhooks   map[string]model.Webhook
+	deliveries map[string]model.WebhookDelivery
+	nextID     int
+	listCalls  int
+}
+
+func (m *MockWebhookRepository) CreateWebhook(webhook model.Webhook) (model.Webhook, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if m.webhooks == nil {
+		m.webhooks = make(map[string]model.Webhook)
+	}
+	m.nextID++
+	webhook.ID = fmt.Sprintf("w%d", m.nextID)
+	webhook.CreatedAt = time.Now()
+	m.webhooks[webhook.ID] = webhook
+	return webhook, nil
+}
+
+func (m *MockWebhookRepository) GetWebhook(id string) (model.Webhook, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	webhook, exists := m.webhooks[id]
+	if !exists {
+		return model.Webhook{}, model.ErrNotFound
+	}
+	return webhook, nil
+}
+
+func (m *MockWebhookRepository) ListWebhooks(userID string) ([]model.Webhook, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.listCalls++
+	var webhooks []model.Webhook
+	for _, webhook := range m.webhooks {
+		if webhook.UserID == userID {
+			webhooks = append(webhooks, webhook)
+		}
+	}
+	sort.Slice(webhooks, func(i, j int) bool { return webhooks[i].ID < webhooks[j].ID })
+	return webhooks, nil
+}
+
+func (m *MockWebhookRepository) UpdateWebhook(id string, update model.WebhookUpdate) (model.Webhook, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	webhook, exists := m.webhooks[id]
+	if !exists {
+		return model.Webhook{}, model.ErrNotFound
+	}
+	webhook.ApplyUpdate(update)
+	m.webhooks[id] = webhook
+	return webhook, nil
+}
+
+func (m *MockWebhookRepository) RecordWebhookResult(id string, succeeded bool, at time.Time) (model.Webhook, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	webhook, exists := m.webhooks[id]
+	if !exists {
+		return model.Webhook{}, model.ErrNotFound
+	}
+	webhook.RecordResult(succeeded, at)
+	m.webhooks[id] = webhook
+	return webhook, nil
+}
+
+func (m *MockWebhookRepository) DeleteWebhook(id string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if _, exists := m.webhooks[id]; !exists {
+		return model.ErrNotFound
+	}
+	delete(m.webhooks, id)
+	for deliveryID, delivery := range m.deliveries {
+		if delivery.WebhookID == id {
+			delete(m.deliveries, deliveryID)
+		}
+	}
+	return nil
+}
+
+func (m *MockWebhookRepository) CreateDelivery(delivery model.WebhookDelivery) (model.WebhookDelivery, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if m.deliveries == nil {
+		m.deliveries = make(map[string]model.WebhookDelivery)
+	}
+	m.nextID++
+	delivery.ID = fmt.Sprintf("d%d", m.nextID)
+	m.deliveries[delivery.ID] = delivery
+	return delivery, nil
+}
+
+func (m *MockWebhookRepository) UpdateDelivery(delivery model.WebhookDelivery) (model.WebhookDelivery, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if _, exists := m.deliveries[delivery.ID]; !exists {
+		return model.WebhookDelivery{}, model.ErrNotFound
+	}
+	m.deliveries[delivery.ID] = delivery
+	return delivery, nil
+}
+
+func (m *MockWebhookRepository) ListDeliveries(webhookID string, limit int) ([]model.WebhookDelivery, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	var deliveries []model.WebhookDelivery
+	for _, delivery := range m.deliveries {
+		if delivery.WebhookID == webhookID {
+			deliveries = append(deliveries, delivery)
+		}
+	}
+	sort.Slice(deliveries, func(i, j int) bool { return deliveries[i].ID > deliveries[j].ID })
+	return deliveries[:min(limit, len(deliveries))], nil
+}
+
+func (m *MockWebhookRepository) ListDueDeliveries(now time.Time, limit int) ([]model.WebhookDelivery, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	var deliveries []model.WebhookDelivery
+	for _, delivery := range m.deliveries {
+		if delivery.Status == model.WebhookDeliveryPending && !delivery.NextAttemptAt.After(now) {
+			deliveries = append(deliveries, delivery)
+		}
+	}
+	sort.Slice(deliveries, func(i, j int) bool { return deliveries[i].ID < deliveries[j].ID })
+	return deliveries[:min(limit, len(deliveries))], nil
+}
+
+func (m *MockWebhookRepository) ClaimDelivery(id string, now, until time.Time) (model.WebhookDelivery, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	delivery, exists := m.deliveries[id]
+	if !exists {
+		return model.WebhookDelivery{}, model.ErrNotFound
+	}
+	if delivery.Status != model.WebhookDeliveryPending || delivery.NextAttemptAt.After(now) {
+		return model.WebhookDelivery{}, model.ErrConflict
+	}
+	delivery.NextAttemptAt = until
+	m.deliveries[id] = delivery
+	return delivery, nil
+}
+
+// delivery returns the only delivery of the repository
+func (m *MockWebhookRepository) delivery(t *testing.T) model.WebhookDelivery {
+	t.Helper()
+	if len(m.deliveries) != 1 {
+		t.Fatalf("got %d deliveries, want 1", len(m.deliveries))
+	}
+	for _, delivery := range m.deliveries {
+		return delivery
+	}
+	return model.WebhookDelivery{}
+}
+
+// MockWebhookSender records the requests it is given and answers with status, or err. onSend,
+// when set, runs while the request is in flight.
+type MockWebhookSender struct {
+	mutex    sync.Mutex
+	status   int
+	err      error
+	requests []model.WebhookRequest
+	onSend   func()
+}
+
+func (m *MockWebhookSender) SendWebhook(ctx context.Context, request model.WebhookRequest) (int, error) {
+	if m.onSend != nil {
+		m.onSend()
+	}
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.requests = append(m.requests, request)
+	if m.err != nil {
+		return 0, m.err
+	}
+	return m.status, nil
+}
+
+func TestWebhookService_CreateWebhook(t *testing.T) {
+	service := NewWebhookService(&MockWebhookRepository{}, &MockWebhookSender{}, NewPolicy(&MockCollectionRepository{}))
+	webhook, err := service.CreateWebhook("user-1", "https://hooks.example.com/athena", []string{model.EventBookmarkCreated, model.EventBookmarkCreated, model.EventBookmarkDeleted})
+	if err != nil {
+		t.Fatalf("CreateWebhook() unexpected error = %v", err)
+	}
+
+	if !strings.HasPrefix(webhook.Secret, "whsec_") || len(webhook.Secret) < 40 {
+		t.Errorf("CreateWebhook() secret = %q, want a random whsec_ secret", webhook.Secret)
+	}
+	if !webhook.Active || len(webhook.Events) != 2 {
+		t.Errorf("CreateWebhook() = %+v, want an active webhook with the 2 distinct events", webhook)
+	}
+
+	tests := []struct {
+		name   string
+		url    string
+		events []string
+	}{
+		{name: "relative URL", url: "/hooks"},
+		{name: "unsupported scheme", url: "ftp://hooks.example.com"},
+		{name: "unknown event", url: "https://hooks.example.com", events: []string{"bookmark.starred"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := service.CreateWebhook("user-1", tt.url, tt.events); !errors.Is(err, model.ErrInvalidInput) {
+				t.Errorf("CreateWebhook() error = %v, want ErrInvalidInput", err)
+			}
+		})
+	}
+}
+
+func TestWebhookService_CreateWebhook_Limit(t *testing.T) {
+	service := NewWebhookService(&MockWebhookRepository{}, &MockWebhookSender{}, NewPolicy(&MockCollectionRepository{}))
+	for i := range model.MaxWebhooks {
+		if _, err := service.CreateWebhook("user-1", fmt.Sprintf("https://hooks.example.com/%d", i), nil); err != nil {
+			t.Fatalf("CreateWebhook() unexpected error = %v", err)
+		}
+	}
+	if _, err := service.CreateWebhook("user-1", "https://hooks.example.com/extra", nil); !errors.Is(err, model.ErrInvalidInput) {
+		t.Errorf("CreateWebhook() over the limit error = %v, want ErrInvalidInput", err)
+	}
+}
+
+func TestWebhookService_HandleEventQueuesSubscribedWebhooks(t *testing.T) {
+	repo := &MockWebhookRepository{}
+	service := NewWebhookService(repo, &MockWebhookSender{}, NewPolicy(&MockCollectionRepository{}))
+	webhook, err := service.CreateWebhook("user-1", "https://hooks.example.com/athena", []string{model.EventBookmarkArchived})
+	if err != nil {
+		t.Fatalf("CreateWebhook() unexpected error = %v", err)
+	}
+	if _, err := service.CreateWebhook("user-2", "https://hooks.example.com/other", nil); err != nil {
+		t.Fatalf("CreateWebhook() unexpected error = %v", err)
+	}
+
+	service.HandleEvent(model.Event{ID: "e1", UserID: "user-1", Type: model.EventBookmarkCreated, Bookmark: model.Bookmark{ID: "b1"}})
+	if len(repo.deliveries) != 0 {
+		t.Fatalf("HandleEvent() queued %d deliveries for an unsubscribed event", len(repo.deliveries))
+	}
+	at := time.Now()
+	service.HandleEvent(model.Event{ID: "e2", UserID: "user-1", Type: model.EventBookmarkArchived, At: at,
+		Bookmark: model.Bookmark{ID: "b1", UserID: "user-1", URL: "https://go.dev", IsArchived: true}})
+
+	delivery := repo.delivery(t)
+	if delivery.WebhookID != webhook.ID || delivery.EventID != "e2" || delivery.Status != model.WebhookDeliveryPending || !delivery.NextAttemptAt.Equal(at) {
+		t.Errorf("HandleEvent() queued %+v, want a pending delivery of e2 due now", delivery)
+	}
+	var payload map[string]any
+	if err := json.Unmarshal(delivery.Payload, &payload); err != nil {
+		t.Fatalf("HandleEvent() payload is not JSON: %v", err)
+	}
+	bookmark, _ := payload["bookmark"].(map[string]any)
+	if payload["type"] != model.EventBookmarkArchived || payload["bookmark_id"] != "b1" || bookmark["is_archived"] != true {
+		t.Errorf("HandleEvent() payload = %s, want the archived bookmark", delivery.Payload)
+	}
+}
+
+// TestWebhookService_HandleEventCachesWebhooks tests that events reuse the webhooks of their
+// owner until one of them changes
+func TestWebhookService_HandleEventCachesWebhooks(t *testing.T) {
+	repo := &MockWebhookRepository{}
+	service := NewWebhookService(repo, &MockWebhookSender{}, NewPolicy(&MockCollectionRepository{}))
+	webhook, err := service.CreateWebhook("user-1", "https://hooks.example.com/athena", []string{model.EventBookmarkCreated})
+	if err != nil {
+		t.Fatalf("CreateWebhook() unexpected error = %v", err)
+	}
+	repo.listCalls = 0
+	created := func(id string) model.Event {
+		return model.Event{ID: id, UserID: "user-1", Type: model.EventBookmarkCreated, At: time.Now(), Bookmark: model.Bookmark{ID: "b1"}}
+	}
+
+	service.HandleEvent(created("e1"))
+	service.HandleEvent(created("e2"))
+	if repo.listCalls != 1 {
+		t.Errorf("HandleEvent() listed webhooks %d times for 2 events, want 1", repo.listCalls)
+	}
+
+	// Disabling the webhook applies to the next event at once
+	active := false
+	if _, err := service.UpdateWebhook("user-1", webhook.ID, model.WebhookUpdate{Active: &active}); err != nil {
+		t.Fatalf("UpdateWebhook() unexpected error = %v", err)
+	}
+	service.HandleEvent(created("e3"))
+	if deliveries, _ := repo.ListDeliveries(webhook.ID, 10); len(deliveries) != 2 {
+		t.Errorf("queued %d deliveries, want 2 before the webhook was disabled", len(deliveries))
+	}
+}
+
+func TestWebhookService_SendDueDeliveries(t *testing.T) {
+	sender := &MockWebhookSender{status: 204}
+	repo := &MockWebhookRepository{}
+	service := NewWebhookService(repo, sender, NewPolicy(&MockCollectionRepository{}))
+	webhook, err := service.CreateWebhook("user-1", "https://hooks.example.com/athena", nil)
+	if err != nil {
+		t.Fatalf("CreateWebhook() unexpected error = %v", err)
+	}
+	service.HandleEvent(model.Event{ID: "e1", UserID: "user-1", Type: model.EventBookmarkDeleted, At: time.Now(), Bookmark: model.Bookmark{ID: "b1"}})
+
+	sent, err := service.SendDueDeliveries(context.Background())
+	if err != nil || sent != 1 {
+		t.Fatalf("SendDueDeliveries() = %d, %v, want 1 sent", sent, err)
+	}
+
+	delivery := repo.delivery(t)
+	if delivery.Status != model.WebhookDeliverySucceeded || delivery.Attempts != 1 || delivery.ResponseStatus != 204 {
+		t.Errorf("SendDueDeliveries() recorded %+v, want one successful attempt", delivery)
+	}
+	request := sender.requests[0]
+	mac := hmac.New(sha256.New, []byte(webhook.Secret))
+	mac.Write(request.Body)
+	if want := "sha256=" + hex.EncodeToString(mac.Sum(nil)); request.Headers[WebhookSignatureHeader] != want {
+		t.Errorf("SendDueDeliveries() signature = %q, want %q", request.Headers[WebhookSignatureHeader], want)
+	}
+	if request.URL != webhook.URL || request.Headers[WebhookEventHeader] != model.EventBookmarkDeleted || request.Headers[WebhookDeliveryHeader] != delivery.ID {
+		t.Errorf("SendDueDeliveries() request = %+v, want the event posted to the webhook URL", request)
+	}
+
+	if sent, _ := service.SendDueDeliveries(context.Background()); sent != 0 {
+		t.Errorf("SendDueDeliveries() sent %d finished deliveries again", sent)
+	}
+}
+
+func TestWebhookService_SendDueDeliveries_RetriesWithBackoff(t *testing.T) {
+	sender := &MockWebhookSender{status: 500}
+	repo := &MockWebhookRepository{}
+	service := NewWebhookService(repo, sender, NewPolicy(&MockCollectionRepository{}))
+	webhook, err := service.CreateWebhook("user-1", "https://hooks.example.com/athena", nil)
+	if err != nil {
+		t.Fatalf("CreateWebhook() unexpected error = %v", err)
+	}
+	service.HandleEvent(model.Event{ID: "e1", UserID: "user-1", Type: model.EventBookmarkCreated, At: time.Now(), Bookmark: model.Bookmark{ID: "b1"}})
+
+	for attempt := 1; attempt <= model.MaxWebhookAttempts; attempt++ {
+		start := time.Now()
+		if sent, err := service.SendDueDeliveries(context.Background()); err != nil || sent != 1 {
+			t.Fatalf("attempt %d: SendDueDeliveries() = %d, %v, want 1 sent", attempt, sent, err)
+		}
+		delivery := repo.delivery(t)
+		if delivery.Attempts != attempt || delivery.ResponseStatus != 500 || delivery.Error == "" {
+			t.Fatalf("attempt %d: recorded %+v, want a failed attempt", attempt, delivery)
+		}
+		if attempt == model.MaxWebhookAttempts {
+			if delivery.Status != model.WebhookDeliveryFailed {
+				t.Errorf("after %d attempts status = %s, want failed", attempt, delivery.Status)
+			}
+			break
+		}
+		wantDelay := webhookRetryDelay << (attempt - 1)
+		if delivery.Status != model.WebhookDeliveryPending || delivery.NextAttemptAt.Before(start.Add(wantDelay)) || delivery.NextAttemptAt.After(time.Now().Add(wantDelay)) {
+			t.Fatalf("attempt %d: next attempt at %v, want pending in %v", attempt, delivery.NextAttemptAt, wantDelay)
+		}
+		// Make the retry due
+		delivery.NextAttemptAt = time.Now()
+		repo.deliveries[delivery.ID] = delivery
+	}
+
+	if stored, _ := repo.GetWebhook(webhook.ID); stored.ConsecutiveFailures != model.MaxWebhookAttempts || !stored.Active {
+		t.Errorf("webhook = %+v, want %d failures and still active", stored, model.MaxWebhookAttempts)
+	}
+}
+
+func TestWebhookService_SendDueDeliveries_DisablesFailingWebhook(t *testing.T) {
+	sender := &MockWebhookSender{err: errors.New("connection refused")}
+	repo := &MockWebhookRepository{}
+	service := NewWebhookService(repo, sender, NewPolicy(&MockCollectionRepository{}))
+	webhook, err := service.CreateWebhook("user-1", "https://hooks.example.com/athena", nil)
+	if err != nil {
+		t.Fatalf("CreateWebhook() unexpected error = %v", err)
+	}
+	webhook.ConsecutiveFailures = model.MaxWebhookFailures - 1
+	repo.webhooks[webhook.ID] = webhook
+	service.HandleEvent(model.Event{ID: "e1", UserID: "user-1", Type: model.EventBookmarkCreated, At: time.Now(), Bookmark: model.Bookmark{ID: "b1"}})
+	service.HandleEvent(model.Event{ID: "e2", UserID: "user-1", Type: model.EventBookmarkCreated, At: time.Now(), Bookmark: model.Bookmark{ID: "b2"}})
+
+	if _, err := service.SendDueDeliveries(context.Background()); err != nil {
+		t.Fatalf("SendDueDeliveries() unexpected error = %v", err)
+	}
+
+	stored, _ := repo.GetWebhook(webhook.ID)
+	if stored.Active || stored.DisabledAt.IsZero() {
+		t.Errorf("webhook = %+v, want disabled after %d failures", stored, model.MaxWebhookFailures)
+	}
+	if len(sender.requests) != 1 {
+		t.Errorf("sent %d requests, want none after the webhook was disabled", len(sender.requests))
+	}
+	deliveries, _ := repo.ListDeliveries(webhook.ID, 10)
+	if deliveries[0].Status != model.WebhookDeliveryFailed || deliveries[0].Error != "webhook is disabled" {
+		t.Errorf("delivery queued for the disabled webhook = %+v, want failed", deliveries[0])
+	}
+
+	// Reactivating resets the failure count
+	active := true
+	updated, err := service.UpdateWebhook("user-1", webhook.ID, model.WebhookUpdate{Active: &active})
+	if err != nil {
+		t.Fatalf("UpdateWebhook() unexpected error = %v", err)
+	}
+	if !updated.Active || updated.ConsecutiveFailures != 0 || !updated.DisabledAt.IsZero() {
+		t.Errorf("UpdateWebhook() = %+v, want an active webhook without failures", updated)
+	}
+}
+
+// TestWebhookService_SendDueDeliveries_KeepsConcurrentEdits tests that recording a failed attempt
+// does not undo changes the owner made while it was in flight
+func TestWebhookService_SendDueDeliveries_KeepsConcurrentEdits(t *testing.T) {
+	sender := &MockWebhookSender{err: errors.New("connection refused")}
+	repo := &MockWebhookRepository{}
+	service := NewWebhookService(repo, sender, NewPolicy(&MockCollectionRepository{}))
+	webhook, err := service.CreateWebhook("user-1", "https://hooks.example.com/athena", nil)
+	if err != nil {
+		t.Fatalf("CreateWebhook() unexpected error = %v", err)
+	}
+	newURL := "https://hooks.example.com/moved"
+	sender.onSend = func() {
+		if _, err := service.UpdateWebhook("user-1", webhook.ID, model.WebhookUpdate{URL: &newURL}); err != nil {
+			t.Errorf("UpdateWebhook() unexpected error = %v", err)
+		}
+	}
+	service.HandleEvent(model.Event{ID: "e1", UserID: "user-1", Type: model.EventBookmarkCreated, At: time.Now(), Bookmark: model.Bookmark{ID: "b1"}})
+
+	if _, err := service.SendDueDeliveries(context.Background()); err != nil {
+		t.Fatalf("SendDueDeliveries() unexpected error = %v", err)
+	}
+
+	stored, _ := repo.GetWebhook(webhook.ID)
+	if stored.URL != newURL || stored.ConsecutiveFailures != 1 {
+		t.Errorf("webhook = %+v, want the new URL and 1 failure", stored)
+	}
+}
+
+func TestWebhookService_OtherUsersWebhooks(t *testing.T) {
+	service := NewWebhookService(&MockWebhookRepository{}, &MockWebhookSender{}, NewPolicy(&MockCollectionRepository{}))
+	webhook, err := service.CreateWebhook("user-1", "https://hooks.example.com/athena", nil)
+	if err != nil {
+		t.Fatalf("CreateWebhook() unexpected error = %v", err)
+	}
+	active := false
+
+	if _, err := service.UpdateWebhook("user-2", webhook.ID, model.WebhookUpdate{Active: &active}); !errors.Is(err, model.ErrForbidden) {
+		t.Errorf("UpdateWebhook() error = %v, want ErrForbidden", err)
+	}
+	if err := service.DeleteWebhook("user-2", webhook.ID); !errors.Is(err, model.ErrForbidden) {
+		t.Errorf("DeleteWebhook() error = %v, want ErrForbidden", err)
+	}
+	if _, err := service.ListDeliveries("user-2", webhook.ID, 10); !errors.Is(err, model.ErrForbidden) {
+		t.Errorf("ListDeliveries() error = %v, want ErrForbidden", err)
+	}
+	if _, err := service.UpdateWebhook("user-1", webhook.ID, model.WebhookUpdate{}); !errors.Is(err, model.ErrInvalidInput) {
+		t.Errorf("UpdateWebhook() without changes error = %v, want ErrInvalidInput", err)
+	}
+}
@@ -0,0 +1,42 @@
+package transport
+
+import "time"
+
+// AdminUserResponse represents a user as seen by administrators
+type AdminUserResponse struct {
+	ID              string     `json:"id"`
+	Name            string     `json:"name"`
+	Email           string     `json:"email"`
+	Tier            string     `json:"tier"`
+	Role            string     `json:"role"`
+	Disabled        bool       `json:"disabled"`
+	TokensRevokedAt *time.Time `json:"tokens_revoked_at,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// AdminUserListResponse represents a paginated list of users
+type AdminUserListResponse struct {
+	Users      []AdminUserResponse `json:"users"`
+	TotalCount int                 `json:"total_count"`
+	Page       int                 `json:"page"`
+	PageSize   int                 `json:"page_size"`
+	TotalPages int                 `json:"total_pages"`
+}
+
+// AdminUserStatsResponse represents a user together with their bookmark counts
+type AdminUserStatsResponse struct {
+	AdminUserResponse
+	BookmarkCount int `json:"bookmark_count"`
+	ArchivedCount int `json:"archived_count"`
+}
+
+// UpdateTierRequest represents the request body for changing a user's tier
+type UpdateTierRequest struct {
+	Tier string `json:"tier"`
+}
+
+// UpdateRoleRequest represents the request body for changing a user's role
+type UpdateRoleRequest struct {
+	Role string `json:"role"`
+}
@@ -3,12 +3,118 @@ package transport
 import "time"
 
 type BookmarkTransport struct {
-	ID             string    `json:"id"`
-	URL            string    `json:"url"`
-	Title          string    `json:"title"`
-	UserID         string    `json:"user_id"`
-	MainImageURL   string    `json:"main_image_url"`
-	ContentSummary string    `json:"content_summary"`
-	CreatedAt      time.Time `json:"created_at"`
-	IsArchived     bool      `json:"is_archived"`
+	ID              string               `json:"id"`
+	URL             string               `json:"url"`
+	Title           string               `json:"title"`
+	UserID          string               `json:"user_id"`
+	MainImageURL    string               `json:"main_image_url"`
+	ContentSummary  string               `json:"content_summary"`
+	CreatedAt       time.Time            `json:"created_at"`
+	UpdatedAt       time.Time            `json:"updated_at"`
+	Version         int64                `json:"version,omitempty"` // The ETag of the bookmark, for If-Match
+	LastOpenedAt    *time.Time           `json:"last_opened_at,omitempty"`
+	DeletedAt       *time.Time           `json:"deleted_at,omitempty"` // Only set in the trash
+	IsArchived      bool                 `json:"is_archived"`
+	IsRead          bool                 `json:"is_read"`
+	ReadAt          *time.Time           `json:"read_at,omitempty"`
+	IsFavorite      bool                 `json:"is_favorite"`
+	ReadingProgress int                  `json:"reading_progress"` // Percentage read, from 0 to 100
+	CollectionIDs   []string             `json:"collection_ids,omitempty"`
+	Tags            []string             `json:"tags,omitempty"`  // Lowercased; set when creating the bookmark
+	Notes           string               `json:"notes,omitempty"` // Markdown, only shown to the owner
+	SnapshotAt      *time.Time           `json:"snapshot_at,omitempty"`
+	ThumbnailURLs   map[string]string    `json:"thumbnail_urls,omitempty"` // Resized main image by size name
+	LinkHealth      *LinkHealthTransport `json:"link_health,omitempty"`
+
+	// Details the page gives about itself, omitted when unknown
+	Description string          `json:"description,omitempty"`
+	SiteName    string          `json:"site_name,omitempty"`
+	PageType    string          `json:"page_type,omitempty"`
+	Author      string          `json:"author,omitempty"`
+	PublishedAt *time.Time      `json:"published_at,omitempty"`
+	FaviconURL  string          `json:"favicon_url,omitempty"`
+	PageCount   int             `json:"page_count,omitempty"`
+	WordCount   int             `json:"word_count,omitempty"`
+	ReadingTime int             `json:"reading_time_minutes,omitempty"` // Estimated from the word or page count
+	Embed       *EmbedTransport `json:"embed,omitempty"`
+}
+
+// ReadingStateRequest changes the reading state of a bookmark; omitted fields are unchanged
+type ReadingStateRequest struct {
+	IsRead          *bool `json:"is_read"`
+	IsFavorite      *bool `json:"is_favorite"`
+	ReadingProgress *int  `json:"reading_progress"`
+}
+
+// EmbedTransport is a video player to show in an iframe
+type EmbedTransport struct {
+	Provider string `json:"provider,omitempty"`
+	URL      string `json:"url"`
+	Width    int    `json:"width,omitempty"`
+	Height   int    `json:"height,omitempty"`
+}
+
+// LinkHealthTransport is the result of the last dead-link check of a bookmark
+type LinkHealthTransport struct {
+	Status     string    `json:"status"`
+	StatusCode int       `json:"status_code,omitempty"`
+	FinalURL   string    `json:"final_url,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	CheckedAt  time.Time `json:"checked_at"`
+}
+
+// EnrichmentTransport is one run of the lookups that fill in a bookmark's title, image,
+// summary and metadata
+type EnrichmentTransport struct {
+	At              time.Time `json:"at"`
+	Trigger         string    `json:"trigger"`
+	Summary         string    `json:"summary"`
+	SummaryProvider string    `json:"summary_provider,omitempty"`
+	Updated         []string  `json:"updated"`
+	Failed          []string  `json:"failed,omitempty"`
+}
+
+// BulkRefreshRequest represents the request body for refreshing several bookmarks
+type BulkRefreshRequest struct {
+	BookmarkIDs []string `json:"bookmark_ids"`
+}
+
+// BulkRefreshResponse lists the bookmarks queued for refresh
+type BulkRefreshResponse struct {
+	Queued   []string `json:"queued"`
+	NotFound []string `json:"not_found"`
+}
+
+// BatchRequest represents the request body for applying several bookmark operations
+type BatchRequest struct {
+	Operations []BatchOperationTransport `json:"operations"`
+}
+
+// BatchOperationTransport is one operation of a batch
+type BatchOperationTransport struct {
+	Op           string   `json:"op"`
+	BookmarkID   string   `json:"bookmark_id"`
+	Tags         []string `json:"tags,omitempty"`
+	CollectionID string   `json:"collection_id,omitempty"`
+}
+
+// BatchResultTransport is the outcome of the operation at Index of a batch
+type BatchResultTransport struct {
+	Index      int    `json:"index"`
+	Op         string `json:"op"`
+	BookmarkID string `json:"bookmark_id"`
+	Status     string `json:"status"`
+	Error      string `json:"error,omitempty"`
+}
+
+// BatchResponse lists the outcome of every operation of a batch, in request order
+type BatchResponse struct {
+	Results   []BatchResultTransport `json:"results"`
+	Succeeded int                    `json:"succeeded"`
+	Failed    int                    `json:"failed"`
+}
+
+// EmptyTrashResponse tells how many bookmarks emptying the trash deleted
+type EmptyTrashResponse struct {
+	Purged int `json:"purged"`
 }
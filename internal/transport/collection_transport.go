@@ -0,0 +1,84 @@
+package transport
+
+import "time"
+
+// CollectionRequest represents the request body for creating or updating a collection
+type CollectionRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	ParentID    string `json:"parent_id"`
+	Position    int    `json:"position"`
+}
+
+// CollectionResponse represents a collection returned by the API
+type CollectionResponse struct {
+	ID            string                 `json:"id"`
+	UserID        string                 `json:"user_id"`
+	ParentID      string                 `json:"parent_id,omitempty"`
+	Name          string                 `json:"name"`
+	Description   string                 `json:"description"`
+	Position      int                    `json:"position"`
+	Role          string                 `json:"role"` // The caller's role: owner, editor or viewer
+	BookmarkIDs   []string               `json:"bookmark_ids"`
+	Collaborators []CollaboratorResponse `json:"collaborators"`
+	CreatedAt     time.Time              `json:"created_at"`
+	UpdatedAt     time.Time              `json:"updated_at"`
+}
+
+// CollaboratorResponse represents a user a collection is shared with
+type CollaboratorResponse struct {
+	UserID  string    `json:"user_id"`
+	Role    string    `json:"role"`
+	AddedAt time.Time `json:"added_at"`
+}
+
+// CollectionBookmarkResponse represents a bookmark in a collection together with who added it
+type CollectionBookmarkResponse struct {
+	BookmarkTransport
+	AddedBy string `json:"added_by"`
+}
+
+// ActivityResponse represents one entry of a collection's activity log
+type ActivityResponse struct {
+	ID           string    `json:"id"`
+	ActorID      string    `json:"actor_id"`
+	Action       string    `json:"action"`
+	BookmarkID   string    `json:"bookmark_id,omitempty"`
+	TargetUserID string    `json:"target_user_id,omitempty"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// AddCollectionBookmarkRequest represents the request body for adding a bookmark to a collection.
+// A missing position appends the bookmark.
+type AddCollectionBookmarkRequest struct {
+	BookmarkID string `json:"bookmark_id"`
+	Position   *int   `json:"position"`
+}
+
+// ReorderCollectionRequest represents the request body for reordering the bookmarks of a collection
+type ReorderCollectionRequest struct {
+	BookmarkIDs []string `json:"bookmark_ids"`
+}
+
+// InviteCollaboratorRequest represents the request body for inviting a collaborator by email
+type InviteCollaboratorRequest struct {
+	Email string `json:"email"`
+	Role  string `json:"role"`
+}
+
+// UpdateCollaboratorRequest represents the request body for changing a collaborator's role
+type UpdateCollaboratorRequest struct {
+	Role string `json:"role"`
+}
+
+// InvitationResponse represents an invitation to a shared collection
+type InvitationResponse struct {
+	ID           string     `json:"id"`
+	CollectionID string     `json:"collection_id"`
+	InviterID    string     `json:"inviter_id"`
+	Email        string     `json:"email"`
+	Role         string     `json:"role"`
+	Status       string     `json:"status"`
+	CreatedAt    time.Time  `json:"created_at"`
+	RespondedAt  *time.Time `json:"responded_at,omitempty"`
+}
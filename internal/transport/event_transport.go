@@ -0,0 +1,12 @@
+package transport
+
+import "time"
+
+// EventTransport is the data of a bookmark change sent on the event stream
+type EventTransport struct {
+	ID         string             `json:"id"`
+	Type       string             `json:"type"`
+	BookmarkID string             `json:"bookmark_id"`
+	Bookmark   *BookmarkTransport `json:"bookmark,omitempty"` // Omitted for deleted bookmarks
+	At         time.Time          `json:"at"`
+}
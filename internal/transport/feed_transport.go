@@ -0,0 +1,92 @@
+package transport
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+// CreateFeedTokenRequest represents the request body for creating a feed token
+type CreateFeedTokenRequest struct {
+	CollectionID string `json:"collection_id"` // Optional; limits the feed to one collection
+}
+
+// FeedTokenResponse represents a feed token and the feed URLs it unlocks
+type FeedTokenResponse struct {
+	Token        string    `json:"token"`
+	CollectionID string    `json:"collection_id,omitempty"`
+	AtomURL      string    `json:"atom_url"`
+	RSSURL       string    `json:"rss_url"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// AtomFeed is an Atom 1.0 feed document (RFC 4287)
+type AtomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Author  AtomPerson  `xml:"author"`
+	Links   []AtomLink  `xml:"link"`
+	Entries []AtomEntry `xml:"entry"`
+}
+
+// AtomPerson is the author of an Atom feed
+type AtomPerson struct {
+	Name string `xml:"name"`
+}
+
+// AtomLink is an Atom link; rel "enclosure" carries the bookmark's image
+type AtomLink struct {
+	Rel  string `xml:"rel,attr,omitempty"`
+	Href string `xml:"href,attr"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+// AtomEntry is one bookmark in an Atom feed
+type AtomEntry struct {
+	ID        string     `xml:"id"`
+	Title     string     `xml:"title"`
+	Links     []AtomLink `xml:"link"`
+	Published string     `xml:"published"`
+	Updated   string     `xml:"updated"`
+	Summary   string     `xml:"summary,omitempty"`
+}
+
+// RSSFeed is an RSS 2.0 feed document
+type RSSFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Channel RSSChannel `xml:"channel"`
+}
+
+// RSSChannel is the channel of an RSS 2.0 feed
+type RSSChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate"`
+	Items         []RSSItem `xml:"item"`
+}
+
+// RSSItem is one bookmark in an RSS 2.0 feed
+type RSSItem struct {
+	Title       string        `xml:"title"`
+	Link        string        `xml:"link"`
+	Description string        `xml:"description,omitempty"`
+	GUID        RSSGUID       `xml:"guid"`
+	PubDate     string        `xml:"pubDate"`
+	Enclosure   *RSSEnclosure `xml:"enclosure,omitempty"`
+}
+
+// RSSGUID identifies an RSS item
+type RSSGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+// RSSEnclosure attaches the bookmark's image to an RSS item. The length is unknown and reported as 0.
+type RSSEnclosure struct {
+	URL    string `xml:"url,attr"`
+	Length int    `xml:"length,attr"`
+	Type   string `xml:"type,attr"`
+}
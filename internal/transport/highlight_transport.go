@@ -0,0 +1,39 @@
+package transport
+
+import "time"
+
+// HighlightTransport is a highlighted passage of a bookmarked page
+type HighlightTransport struct {
+	ID         string                    `json:"id"`
+	BookmarkID string                    `json:"bookmark_id"`
+	Quote      string                    `json:"quote"`
+	Note       string                    `json:"note,omitempty"` // Markdown
+	Anchor     *HighlightAnchorTransport `json:"anchor,omitempty"`
+	CreatedAt  time.Time                 `json:"created_at"`
+	UpdatedAt  time.Time                 `json:"updated_at"`
+}
+
+// HighlightAnchorTransport locates a highlight in the text of the offline snapshot, as
+// character offsets with end exclusive
+type HighlightAnchorTransport struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+// CreateHighlightRequest highlights a passage of a bookmarked page
+type CreateHighlightRequest struct {
+	Quote  string                    `json:"quote"`
+	Note   string                    `json:"note"`
+	Anchor *HighlightAnchorTransport `json:"anchor"`
+}
+
+// UpdateHighlightRequest changes a highlight; omitted fields are unchanged
+type UpdateHighlightRequest struct {
+	Note   *string                   `json:"note"`
+	Anchor *HighlightAnchorTransport `json:"anchor"`
+}
+
+// NotesRequest replaces the notes of a bookmark
+type NotesRequest struct {
+	Notes string `json:"notes"` // Markdown; empty clears the notes
+}
@@ -0,0 +1,38 @@
+package transport
+
+import "time"
+
+// CreateShareLinkRequest represents the request body for sharing a bookmark or collection publicly
+type CreateShareLinkRequest struct {
+	Password  string     `json:"password"`   // Optional; visitors must supply it to view the share
+	ExpiresAt *time.Time `json:"expires_at"` // Optional; the link never expires when omitted
+}
+
+// ShareLinkResponse represents a share link returned to its owner
+type ShareLinkResponse struct {
+	Slug              string     `json:"slug"`
+	URL               string     `json:"url"`
+	ResourceType      string     `json:"resource_type"`
+	ResourceID        string     `json:"resource_id"`
+	PasswordProtected bool       `json:"password_protected"`
+	ExpiresAt         *time.Time `json:"expires_at,omitempty"`
+	CreatedAt         time.Time  `json:"created_at"`
+}
+
+// PublicShareResponse represents what an unauthenticated visitor of a share link sees
+type PublicShareResponse struct {
+	ResourceType string                   `json:"resource_type"`
+	Name         string                   `json:"name"`
+	Description  string                   `json:"description,omitempty"`
+	ExpiresAt    *time.Time               `json:"expires_at,omitempty"`
+	Bookmarks    []PublicBookmarkResponse `json:"bookmarks"`
+}
+
+// PublicBookmarkResponse is a bookmark without owner or account details
+type PublicBookmarkResponse struct {
+	URL            string    `json:"url"`
+	Title          string    `json:"title"`
+	MainImageURL   string    `json:"main_image_url"`
+	ContentSummary string    `json:"content_summary"`
+	CreatedAt      time.Time `json:"created_at"`
+}
@@ -0,0 +1,47 @@
+package transport
+
+import "time"
+
+// SyncResponse lists how bookmarks changed since the token of a pull
+type SyncResponse struct {
+	Created []BookmarkTransport `json:"created"`
+	Updated []BookmarkTransport `json:"updated"`
+	Deleted []string            `json:"deleted"`  // IDs of bookmarks moved to the trash
+	Token   string              `json:"token"`    // Pass as since on the next pull
+	HasMore bool                `json:"has_more"` // Pull again from token right away
+}
+
+// SyncPushRequest represents the request body for pushing offline edits
+type SyncPushRequest struct {
+	Changes []SyncChangeTransport `json:"changes"`
+}
+
+// SyncChangeTransport is an edit a client made to a bookmark while offline; omitted fields
+// are unchanged
+type SyncChangeTransport struct {
+	BookmarkID      string     `json:"bookmark_id"`
+	BaseUpdatedAt   *time.Time `json:"base_updated_at"` // updated_at of the copy the client edited
+	EditedAt        *time.Time `json:"edited_at"`       // When the edit was made; defaults to now
+	Deleted         bool       `json:"deleted"`         // Move the bookmark to the trash
+	IsArchived      *bool      `json:"is_archived"`
+	IsRead          *bool      `json:"is_read"`
+	IsFavorite      *bool      `json:"is_favorite"`
+	ReadingProgress *int       `json:"reading_progress"`
+	Notes           *string    `json:"notes"`
+	Tags            *[]string  `json:"tags"`
+}
+
+// SyncResultTransport is the outcome of the change at Index of a push
+type SyncResultTransport struct {
+	Index      int                `json:"index"`
+	BookmarkID string             `json:"bookmark_id"`
+	Status     string             `json:"status"`
+	Conflicted bool               `json:"conflicted,omitempty"` // The bookmark also changed on the server
+	Bookmark   *BookmarkTransport `json:"bookmark,omitempty"`   // The server copy after the push
+	Error      string             `json:"error,omitempty"`
+}
+
+// SyncPushResponse lists the outcome of every change of a push, in request order
+type SyncPushResponse struct {
+	Results []SyncResultTransport `json:"results"`
+}
@@ -0,0 +1,19 @@
+package transport
+
+// EntitlementsResponse represents the limits of a tier, -1 means unlimited
+type EntitlementsResponse struct {
+	MaxBookmarks      int  `json:"max_bookmarks"`
+	SummariesPerMonth int  `json:"summaries_per_month"`
+	ImportsPerDay     int  `json:"imports_per_day"`
+	MaxAPITokens      int  `json:"max_api_tokens"`
+	SemanticSearch    bool `json:"semantic_search"`
+}
+
+// UsageResponse represents the authenticated user's consumption against their limits
+type UsageResponse struct {
+	Tier               string               `json:"tier"`
+	Limits             EntitlementsResponse `json:"limits"`
+	Bookmarks          int                  `json:"bookmarks"`
+	SummariesThisMonth int                  `json:"summaries_this_month"`
+	ImportsToday       int                  `json:"imports_today"`
+}